@@ -9,8 +9,10 @@ import (
 	"github.com/pkg/errors"
 
 	"github.com/kyma-project/cli/internal/cli"
+	"github.com/kyma-project/cli/internal/coredns"
 	"github.com/kyma-project/cli/internal/kube"
 	"github.com/kyma-project/cli/pkg/asyncui"
+	"github.com/kyma-project/cli/pkg/installation"
 	"github.com/spf13/cobra"
 
 	installConfig "github.com/kyma-incubator/hydroform/parallel-install/pkg/config"
@@ -90,6 +92,12 @@ func (cmd *command) Run() error {
 		ComponentsListFile:            fmt.Sprintf("uninstall-%s", kymaMeta.ComponentListFile),
 	}
 
+	// the record of the labels and taints added by "kyma install" might be deleted with Kyma, so it is read first
+	nodeChanges, err := installation.RecordedNodeChanges(cmd.K8s.Static())
+	if err != nil {
+		return err
+	}
+
 	// recover the component list used for the Kyma installation
 	if err := cmd.recoverComponentsListFile(installCfg.ComponentsListFile, kymaMeta.ComponentListData); err != nil {
 		return err
@@ -116,11 +124,48 @@ func (cmd *command) Run() error {
 	}
 
 	if uninstallErr == nil {
+		cmd.removeCoreDNSPatch()
+		cmd.revertNodeChanges(nodeChanges)
 		cmd.showSuccessMessage()
 	}
 	return uninstallErr
 }
 
+// removeCoreDNSPatch reverts the resolution of the Kyma domain added to CoreDNS by "kyma install", if any
+func (cmd *command) removeCoreDNSPatch() {
+	s := cmd.NewStep("Removing the Kyma domain from CoreDNS")
+	changed, err := coredns.Unpatch(cmd.K8s.Static())
+	switch {
+	case err != nil:
+		// Kyma is deleted anyway, the patch only affects the resolution of the Kyma domain
+		s.Failure()
+		s.LogError(err.Error())
+	case changed:
+		s.Successf("Kyma domain removed from CoreDNS")
+	default:
+		s.Successf("CoreDNS was not patched")
+	}
+}
+
+// revertNodeChanges removes the labels and taints added to the nodes by "kyma install", if any
+func (cmd *command) revertNodeChanges(changes []installation.NodeChange) {
+	if len(changes) == 0 {
+		return
+	}
+	s := cmd.NewStep("Removing the labels and taints added to the nodes")
+	reverted, err := installation.RevertNodeChanges(cmd.K8s.Static(), changes)
+	for _, r := range reverted {
+		s.LogInfof("Reverted the %s", r)
+	}
+	if err != nil {
+		// like the CoreDNS patch, the labels do not keep Kyma from being deleted
+		s.Failure()
+		s.LogError(err.Error())
+		return
+	}
+	s.Successf("Labels and taints removed from %d nodes", len(reverted))
+}
+
 func (cmd *command) recoverComponentsListFile(file string, data []byte) error {
 	restoreClStep := cmd.NewStep("Restore component list used for initial Kyma installation")
 	err := ioutil.WriteFile(file, data, 0600)
@@ -161,5 +206,5 @@ func (cmd *command) retrieveKymaMetadata() (*metadata.KymaMetadata, error) {
 
 func (cmd *command) showSuccessMessage() {
 	// TODO: show processing summary
-	fmt.Println("Kyma successfully removed.")
+	fmt.Fprintln(cmd.Stdout(), "Kyma successfully removed.")
 }
@@ -1,7 +1,6 @@
 package deploy
 
 import (
-	"context"
 	"fmt"
 	"io/ioutil"
 	"os"
@@ -11,10 +10,9 @@ import (
 
 	"github.com/pkg/errors"
 
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-
 	"github.com/kyma-project/cli/internal/cli"
 	"github.com/kyma-project/cli/internal/kube"
+	kymaMetadata "github.com/kyma-project/cli/internal/metadata"
 	"github.com/kyma-project/cli/pkg/asyncui"
 	"github.com/kyma-project/cli/pkg/deploy"
 	"github.com/magiconair/properties"
@@ -65,6 +63,7 @@ func NewCmd(o *Options) *cobra.Command {
 	- To use the local sources, write "kyma alpha deploy --source=local".`)
 	cobraCmd.Flags().StringVarP(&o.Profile, "profile", "p", "",
 		fmt.Sprintf("Kyma deployment profile. Supported profiles are: \"%s\".", strings.Join(kymaProfiles, "\", \"")))
+	cli.MarkPathFlags(cobraCmd, "workspace", "components", "values-file", "tls-crt", "tls-key")
 	return cobraCmd
 }
 
@@ -334,7 +333,7 @@ func (cmd *command) showSuccessMessage() {
 	var err error
 	logFunc := cli.LogFunc(cmd.Verbose)
 
-	fmt.Println("Kyma successfully installed.")
+	fmt.Fprintln(cmd.Stdout(), "Kyma successfully installed.")
 
 	tlsProvided, err := cmd.opts.tlsCertAndKeyProvided()
 	if err != nil {
@@ -346,7 +345,7 @@ func (cmd *command) showSuccessMessage() {
 		if err = cmd.storeCrtAsFile(); err != nil {
 			logFunc("%s", err)
 		}
-		fmt.Println(`
+		fmt.Fprintln(cmd.Stdout(), `
 Generated self signed TLS certificate should be trusted in your system.
 
   * On Mac Os X, execute this command:
@@ -364,31 +363,38 @@ This is a one time operation (you can skip this step if you did it before).`)
 	if err != nil {
 		logFunc("%s", err)
 	}
-	fmt.Printf(`
+	fmt.Fprintf(cmd.Stdout(), `
 Kyma Console Url: %s
 User: admin@kyma.cx
 Password: %s
 
-`, fmt.Sprintf("https://console.%s", cmd.opts.Domain), adminPw)
+`, cmd.consoleURL(), adminPw)
 }
 
-func (cmd *command) storeCrtAsFile() error {
-	secret, err := cmd.K8s.Static().CoreV1().Secrets("istio-system").Get(context.Background(), "kyma-gateway-certs", metav1.GetOptions{})
+// consoleURL reads the console address from the cluster, as the console might use another host than the one composed from the domain
+func (cmd *command) consoleURL() string {
+	host, err := kube.ConsoleHost(cmd.K8s)
 	if err != nil {
-		return err
+		cli.LogFunc(cmd.Verbose)("Unable to read the console address from the cluster: %s", err)
+		return fmt.Sprintf("https://console.%s (assumed)", cmd.opts.Domain)
 	}
-	if err = ioutil.WriteFile("kyma.crt", secret.Data["cert"], 0600); err != nil {
+	return fmt.Sprintf("https://%s", host)
+}
+
+func (cmd *command) storeCrtAsFile() error {
+	cert, err := kube.GetSecretValue(cmd.K8s.Static(), "istio-system", "kyma-gateway-certs", "cert")
+	if err != nil {
 		return err
 	}
-	return nil
+	return ioutil.WriteFile("kyma.crt", cert, 0600)
 }
 
 func (cmd *command) adminPw() (string, error) {
-	secret, err := cmd.K8s.Static().CoreV1().Secrets("kyma-system").Get(context.Background(), "admin-user", metav1.GetOptions{})
+	password, err := kube.GetSecretValue(cmd.K8s.Static(), kymaMetadata.Latest().AdminSecretNamespace, kymaMetadata.Latest().AdminSecret, "password")
 	if err != nil {
 		return "", err
 	}
-	return string(secret.Data["password"]), nil
+	return string(password), nil
 }
 
 //avoidUserInteraction returns true if user won't provide input
@@ -3,10 +3,8 @@ package version
 import (
 	"fmt"
 	"io"
-	"os"
 
 	"github.com/kyma-incubator/hydroform/parallel-install/pkg/metadata"
-	"github.com/kyma-project/cli/cmd/kyma/version"
 	"github.com/kyma-project/cli/internal/cli"
 	"github.com/kyma-project/cli/internal/kube"
 	"github.com/pkg/errors"
@@ -56,13 +54,13 @@ func (cmd *command) Run() error {
 		}
 	}
 
-	printVersion(os.Stdout, cmd.opts.ClientOnly, clusterMetadata)
+	printVersion(cmd.Stdout(), cmd.opts.ClientOnly, clusterMetadata)
 
 	return nil
 }
 
 func printVersion(w io.Writer, clientOnly bool, clusterMetadata *metadata.KymaMetadata) {
-	fmt.Fprintf(w, "Kyma CLI version: %s\n", versionOrDefault(version.Version))
+	fmt.Fprintf(w, "Kyma CLI version: %s\n", versionOrDefault(cli.Version))
 
 	if clientOnly {
 		return
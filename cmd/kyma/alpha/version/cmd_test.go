@@ -5,7 +5,7 @@ import (
 	"testing"
 
 	"github.com/kyma-incubator/hydroform/parallel-install/pkg/metadata"
-	"github.com/kyma-project/cli/cmd/kyma/version"
+	"github.com/kyma-project/cli/internal/cli"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -68,7 +68,7 @@ func TestPrintVersion(t *testing.T) {
 	for _, tc := range tests {
 		t.Run(tc.name, func(t *testing.T) {
 			buf := new(bytes.Buffer)
-			version.Version = tc.clientVersion
+			cli.Version = tc.clientVersion
 
 			printVersion(buf, tc.clientOnly, tc.clusterMetadata)
 			assert.Equal(t, tc.want, buf.String())
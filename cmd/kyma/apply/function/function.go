@@ -51,6 +51,7 @@ Use the flags to specify the desired location for the source files or run the co
 - json
 - yaml
 - none`)
+	cli.MarkPathFlags(cmd, "filename")
 
 	return cmd
 }
@@ -209,7 +210,7 @@ func (l *logger) pre(v interface{}, err error) error {
 		if marshalError != nil {
 			return marshalError
 		}
-		fmt.Printf(jsonFormat, string(bytes))
+		fmt.Fprintf(l.Stdout(), jsonFormat, string(bytes))
 		return nil
 	case YAMLOutput:
 		if err != nil {
@@ -221,7 +222,7 @@ func (l *logger) pre(v interface{}, err error) error {
 		if marshalError != nil {
 			return marshalError
 		}
-		fmt.Printf(yamlFormat, string(bytes))
+		fmt.Fprintf(l.Stdout(), yamlFormat, string(bytes))
 		return nil
 	case NoneOutput:
 		return err
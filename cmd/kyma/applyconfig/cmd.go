@@ -0,0 +1,91 @@
+package applyconfig
+
+import (
+	"errors"
+	"time"
+
+	"github.com/kyma-project/cli/cmd/kyma/wait"
+	"github.com/kyma-project/cli/internal/cli"
+	"github.com/kyma-project/cli/internal/kube"
+	"github.com/kyma-project/cli/pkg/installation"
+	pkgErrors "github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+type command struct {
+	opts *Options
+	cli.Command
+}
+
+//NewCmd creates a new apply-config command
+func NewCmd(o *Options) *cobra.Command {
+	cmd := command{
+		Command: cli.Command{Options: o.Options},
+		opts:    o,
+	}
+
+	cobraCmd := &cobra.Command{
+		Use:   "apply-config",
+		Short: "Changes the overrides of the installed Kyma and installs it again.",
+		Long: `Use this command to change the overrides of the Kyma Installer, for example to enable a feature after the installation, and to let the Kyma Installer reconcile the components with them.
+The overrides are compared with the ConfigMaps and Secrets of the Kyma Installer, and the added and changed keys are displayed before they are applied. Values of Secrets are not displayed.
+Changed keys are updated in the ConfigMap or Secret which holds them, new keys are added to the overrides of the component.
+Then, the Installation CR is labeled to install Kyma again, and the command waits for the installation as "kyma install" does.
+
+As the Kyma Installer reads the overrides when it starts the installation, the command fails while an installation is in progress.
+`,
+		Example: `kyma apply-config --override global.disableLegacyConnectivity=true
+kyma apply-config --override ory:hydra.deployment.resources.limits.memory=256Mi --wait=false
+kyma apply-config --override-file my-overrides.yaml`,
+		RunE: func(_ *cobra.Command, _ []string) error { return cmd.Run() },
+	}
+
+	cobraCmd.Flags().StringArrayVar(&o.Overrides, "override", nil, `Override in the format key=value for global overrides, or component:key=value for the overrides of a component. The flag can be repeated, the values take precedence over the override files.`)
+	cobraCmd.Flags().StringArrayVar(&o.OverrideFiles, "override-file", nil, `Path to a YAML file with overrides, in the format of the "--override" files of "kyma install". The flag can be repeated, later files take precedence.`)
+	cobraCmd.Flags().StringVar(&o.InstallationName, "installation-name", "", "Name of the Kyma Installation CR. If not set, it is discovered from the cluster.")
+	cobraCmd.Flags().BoolVar(&o.Force, "force", false, "Applies the overrides and triggers the installation even if an installation is in progress.")
+	cli.AddWaitFlag(cobraCmd, &o.NoWait, "Waits for the Kyma installation to complete. Use --wait=false to return once the installation is triggered.")
+	cobraCmd.Flags().DurationVar(&o.Timeout, "timeout", 1*time.Hour, "Maximum time to wait for the installation. Use 0 to wait without a timeout.")
+	cli.MarkPathFlags(cobraCmd, "override-file")
+	return cobraCmd
+}
+
+//Run runs the command
+func (cmd *command) Run() error {
+	if cmd.opts.CI {
+		cmd.Factory.NonInteractive = true
+	}
+
+	var err error
+	if cmd.K8s, err = kube.NewFromConfig("", cmd.KubeconfigPath); err != nil {
+		return pkgErrors.Wrap(err, "Could not initialize the Kubernetes client. Make sure your kubeconfig is valid")
+	}
+	s, err := installation.NewInstallationService(cmd.K8s.RestConfig(), cmd.opts.Timeout, "", nil)
+	if err != nil {
+		return pkgErrors.Wrap(err, "Failed to create installation service. Make sure your kubeconfig is valid")
+	}
+
+	i := &installation.Installation{
+		K8s:     cmd.K8s,
+		Service: s,
+		Options: &installation.Options{
+			Verbose:          cmd.opts.Verbose,
+			CI:               cmd.opts.CI,
+			NonInteractive:   cmd.Factory.NonInteractive,
+			Timeout:          cmd.opts.Timeout,
+			NoWait:           cmd.opts.NoWait,
+			Force:            cmd.opts.Force,
+			InstallationName: cmd.opts.InstallationName,
+			OverrideValues:   cmd.opts.Overrides,
+			OverrideConfigs:  cmd.opts.OverrideFiles,
+		},
+		Factory: cmd.Factory,
+	}
+	if err := i.ApplyConfiguration(); err != nil {
+		if errors.Is(err, installation.ErrInstallationTimeout) {
+			return &cli.ExitError{Code: wait.ExitCodeTimeout, Err: err}
+		}
+		return err
+	}
+	return nil
+}
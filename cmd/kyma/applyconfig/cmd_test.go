@@ -0,0 +1,41 @@
+package applyconfig
+
+import (
+	"testing"
+	"time"
+
+	"github.com/kyma-project/cli/internal/cli"
+	"github.com/stretchr/testify/require"
+)
+
+// TestApplyConfigFlags ensures that the provided command flags are stored in the options.
+func TestApplyConfigFlags(t *testing.T) {
+	t.Parallel()
+	o := NewOptions(&cli.Options{})
+	c := NewCmd(o)
+
+	// test default flag values
+	require.Empty(t, o.Overrides, "Default value for the override flag not as expected.")
+	require.Empty(t, o.OverrideFiles, "Default value for the override-file flag not as expected.")
+	require.False(t, o.Force, "Default value for the force flag not as expected.")
+	require.False(t, o.NoWait, "Default value for the wait flag not as expected.")
+	require.Equal(t, 1*time.Hour, o.Timeout, "Default value for the timeout flag not as expected.")
+
+	// test passing flags
+	err := c.ParseFlags([]string{
+		"--override", "global.domainName=example.com",
+		"--override", "ory:hydra.enabled=true",
+		"--override-file", "/tmp/overrides.yaml",
+		"--installation-name", "my-installation",
+		"--force",
+		"--wait=false",
+		"--timeout", "10m",
+	})
+	require.NoError(t, err, "Parsing flags should not return an error")
+	require.Equal(t, []string{"global.domainName=example.com", "ory:hydra.enabled=true"}, o.Overrides, "The parsed value for the override flag not as expected.")
+	require.Equal(t, []string{"/tmp/overrides.yaml"}, o.OverrideFiles, "The parsed value for the override-file flag not as expected.")
+	require.Equal(t, "my-installation", o.InstallationName, "The parsed value for the installation-name flag not as expected.")
+	require.True(t, o.Force, "The parsed value for the force flag not as expected.")
+	require.True(t, o.NoWait, "The parsed value for the wait flag not as expected.")
+	require.Equal(t, 10*time.Minute, o.Timeout, "The parsed value for the timeout flag not as expected.")
+}
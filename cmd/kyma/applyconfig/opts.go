@@ -0,0 +1,23 @@
+package applyconfig
+
+import (
+	"time"
+
+	"github.com/kyma-project/cli/internal/cli"
+)
+
+//Options defines available options for the apply-config command
+type Options struct {
+	*cli.Options
+	Overrides        []string
+	OverrideFiles    []string
+	InstallationName string
+	Force            bool
+	NoWait           bool
+	Timeout          time.Duration
+}
+
+//NewOptions creates options with default values
+func NewOptions(o *cli.Options) *Options {
+	return &Options{Options: o}
+}
@@ -0,0 +1,172 @@
+package components
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/kyma-project/cli/internal/cli"
+	"github.com/kyma-project/cli/internal/kube"
+	"github.com/kyma-project/cli/internal/nice"
+	"github.com/kyma-project/cli/internal/release"
+	"github.com/kyma-project/cli/internal/releases"
+	"github.com/kyma-project/cli/pkg/installation"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	apiErrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+type command struct {
+	opts *Options
+	cli.Command
+}
+
+//NewCmd creates a new components command
+func NewCmd(o *Options) *cobra.Command {
+	cmd := command{
+		Command: cli.Command{Options: o.Options},
+		opts:    o,
+	}
+
+	cobraCmd := &cobra.Command{
+		Use:   "components",
+		Short: "Lists the Kyma components installed on the cluster.",
+		Long: `Use this command to list the components of the Kyma installation on the cluster the current kubeconfig points to.
+The components are read from the Installation CR. For each component, the chart version, the revision and the status of its Helm release are displayed. The releases are read from the secrets of Helm 3 and, for Kyma releases installing the components with Helm 2, from the ConfigMaps of Tiller, which do not expose the chart version.
+
+With --diff, the installed components are compared with the components of the Kyma release given with --release, to show which components an upgrade to that release would add and remove.
+`,
+		Example: `  # List the installed components as JSON
+  kyma components -o json
+
+  # Show the components an upgrade to Kyma 1.16.0 would add and remove
+  kyma components --diff --release=1.16.0`,
+		RunE: func(_ *cobra.Command, _ []string) error { return cmd.Run() },
+	}
+
+	cobraCmd.Flags().StringVar(&o.InstallationName, "installation-name", "", "Name of the Kyma Installation CR. If not set, it is discovered from the cluster.")
+	cobraCmd.Flags().StringVarP(&o.OutputFormat, "output", "o", "", "Output format. One of: json")
+	cobraCmd.Flags().BoolVar(&o.Diff, "diff", false, "Compares the installed components with the components of the Kyma release given with --release.")
+	cobraCmd.Flags().StringVar(&o.Release, "release", "", `Kyma release (e.g. 1.16.0) or release channel (e.g. "stable") the installed components are compared with if --diff is set.`)
+	_ = cobraCmd.RegisterFlagCompletionFunc("release", releases.CompleteVersions)
+	return cobraCmd
+}
+
+//Run runs the command
+func (cmd *command) Run() error {
+	if cmd.opts.OutputFormat != "" && !strings.EqualFold(cmd.opts.OutputFormat, "json") {
+		return fmt.Errorf("unsupported output format '%s'. Use 'json' or omit the flag", cmd.opts.OutputFormat)
+	}
+	if cmd.opts.Diff && cmd.opts.Release == "" {
+		return errors.New("--diff requires the Kyma release to compare with, for example --release=1.16.0")
+	}
+	if !cmd.opts.Diff && cmd.opts.Release != "" {
+		return errors.New("--release is only used together with --diff")
+	}
+
+	var err error
+	if cmd.K8s, err = kube.NewFromConfig("", cmd.KubeconfigPath); err != nil {
+		return errors.Wrap(err, "Could not initialize the Kubernetes client. Make sure your kubeconfig is valid")
+	}
+
+	installed, err := installation.InstalledComponents(cmd.K8s, cmd.opts.InstallationName, warn)
+	if err != nil {
+		return errors.Wrap(err, "Could not list the Kyma components")
+	}
+	if cmd.opts.Diff {
+		return cmd.diff(installed)
+	}
+
+	if strings.EqualFold(cmd.opts.OutputFormat, "json") {
+		return printJSON(cmd.Stdout(), installed, "components")
+	}
+
+	if len(installed) == 0 {
+		fmt.Fprintln(cmd.Stdout(), "Kyma is not installed")
+		return nil
+	}
+	writer := nice.NewTableWriter([]string{"NAME", "NAMESPACE", "VERSION", "REVISION", "STATUS"}, cmd.Stdout())
+	for _, c := range installed {
+		version, revision := c.Version, ""
+		if version == "" {
+			version = "-"
+		}
+		if c.Revision > 0 {
+			revision = strconv.Itoa(c.Revision)
+		}
+		writer.Append([]string{c.Name, c.Namespace, version, revision, c.Status})
+	}
+	writer.Render()
+	return nil
+}
+
+// diff prints the components an upgrade to the release given with --release would add and remove
+func (cmd *command) diff(installed []installation.InstalledComponent) error {
+	if len(installed) == 0 {
+		return errors.New("Kyma is not installed, so there are no components to compare")
+	}
+	r, err := cmd.targetRelease()
+	if err != nil {
+		return err
+	}
+
+	// the component lists of the Installation CRs of local and remote clusters differ
+	profile := release.RemoteCluster
+	clusterInfo, err := installation.GetClusterInfoFromConfigMap(cmd.K8s)
+	if err != nil && !apiErrors.IsForbidden(err) {
+		return err
+	}
+	if clusterInfo.IsLocal {
+		profile = release.LocalCluster
+	}
+	target, err := installation.ReleaseComponents(r, profile)
+	if err != nil {
+		return err
+	}
+
+	diff := installation.DiffComponents(installed, r.Version, target)
+	if strings.EqualFold(cmd.opts.OutputFormat, "json") {
+		return printJSON(cmd.Stdout(), diff, "component diff")
+	}
+
+	if len(diff.Added) == 0 && len(diff.Removed) == 0 {
+		fmt.Fprintf(cmd.Stdout(), "Kyma %s installs the same components as the cluster\n", diff.Release)
+		return nil
+	}
+	writer := nice.NewTableWriter([]string{"COMPONENT", "CHANGE"}, cmd.Stdout())
+	for _, c := range diff.Added {
+		writer.Append([]string{c, "added"})
+	}
+	for _, c := range diff.Removed {
+		writer.Append([]string{c, "removed"})
+	}
+	writer.Render()
+	return nil
+}
+
+// targetRelease returns the release given with --release, release channels are resolved to their current version
+func (cmd *command) targetRelease() (*release.Release, error) {
+	for _, channel := range releases.ChannelNames() {
+		if strings.EqualFold(cmd.opts.Release, channel) {
+			return release.FromChannel(channel, warn)
+		}
+	}
+	return release.FromVersion(strings.TrimPrefix(cmd.opts.Release, "v"))
+}
+
+// warn prints a warning to stderr, so that it does not mix with the JSON output
+func warn(format string, args ...interface{}) {
+	fmt.Fprintf(os.Stderr, "Warning: "+format+"\n", args...)
+}
+
+func printJSON(w io.Writer, v interface{}, what string) error {
+	d, err := json.MarshalIndent(v, "", "\t")
+	if err != nil {
+		return errors.Wrapf(err, "Unable to marshal the %s to json", what)
+	}
+	fmt.Fprintln(w, string(d))
+	return nil
+}
@@ -0,0 +1,34 @@
+package components
+
+import (
+	"testing"
+
+	"github.com/kyma-project/cli/internal/cli"
+	"github.com/stretchr/testify/require"
+)
+
+// TestComponentsFlags ensures that the provided command flags are stored in the options.
+func TestComponentsFlags(t *testing.T) {
+	t.Parallel()
+	o := NewOptions(&cli.Options{})
+	c := NewCmd(o)
+
+	// test default flag values
+	require.Empty(t, o.InstallationName, "Default value for the installation-name flag not as expected.")
+	require.Empty(t, o.OutputFormat, "Default value for the output flag not as expected.")
+	require.False(t, o.Diff, "Default value for the diff flag not as expected.")
+	require.Empty(t, o.Release, "Default value for the release flag not as expected.")
+
+	// test passing flags
+	err := c.ParseFlags([]string{
+		"--installation-name", "my-installation",
+		"-o", "json",
+		"--diff",
+		"--release", "1.16.0",
+	})
+	require.NoError(t, err, "Parsing flags should not return an error")
+	require.Equal(t, "my-installation", o.InstallationName, "The parsed value for the installation-name flag not as expected.")
+	require.Equal(t, "json", o.OutputFormat, "The parsed value for the output flag not as expected.")
+	require.True(t, o.Diff, "The parsed value for the diff flag not as expected.")
+	require.Equal(t, "1.16.0", o.Release, "The parsed value for the release flag not as expected.")
+}
@@ -0,0 +1,17 @@
+package components
+
+import "github.com/kyma-project/cli/internal/cli"
+
+//Options defines available options for the components command
+type Options struct {
+	*cli.Options
+	InstallationName string
+	OutputFormat     string
+	Diff             bool
+	Release          string
+}
+
+//NewOptions creates options with default values
+func NewOptions(o *cli.Options) *Options {
+	return &Options{Options: o}
+}
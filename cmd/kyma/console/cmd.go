@@ -1,8 +1,9 @@
 package console
 
 import (
-	"context"
 	"fmt"
+	"os"
+	"os/signal"
 
 	"github.com/kyma-project/cli/internal/cli"
 	"github.com/kyma-project/cli/internal/kube"
@@ -10,7 +11,6 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/pkg/browser"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 type command struct {
@@ -30,12 +30,18 @@ func NewCmd(o *Options) *cobra.Command {
 		Use:   "console",
 		Short: "Opens the Kyma Console in a web browser.",
 		Long: `Use this command to open the Kyma Console in a web browser.
+If the domain of the cluster cannot be resolved, use ` + "`--port-forward`" + ` to forward local ports to the Console and Dex services and open the Console on localhost.
+The port-forwards are kept alive until you stop the command with Ctrl+C.
 
 `,
 
 		RunE:    func(_ *cobra.Command, _ []string) error { return c.Run() },
 		Aliases: []string{"c"},
 	}
+
+	cmd.Flags().BoolVar(&o.PortForward, "port-forward", false, "Forwards local ports to the Console and Dex services and opens the Console on localhost, for clusters whose domain cannot be resolved.")
+	cmd.Flags().IntVar(&o.ConsolePort, "console-port", 3000, "Local port forwarded to the Console if --port-forward is set.")
+	cmd.Flags().IntVar(&o.DexPort, "dex-port", 5556, "Local port forwarded to Dex if --port-forward is set.")
 	return cmd
 }
 
@@ -46,28 +52,89 @@ func (c *command) Run() error {
 		return errors.Wrap(err, "Could not initialize the Kubernetes client. Make sure your kubeconfig is valid")
 	}
 
+	if c.opts.PortForward {
+		return c.portForward()
+	}
+
 	// Reading the Kyma console URL from the cluster
-	var consoleURL string
-	vs, err := c.K8s.Istio().NetworkingV1alpha3().VirtualServices("kyma-system").Get(context.Background(), "console-web", metav1.GetOptions{})
-	switch {
-	case err != nil:
-		fmt.Printf("Unable to read the Kyma console URL due to error: %s. Check if your cluster is available and has Kyma installed\r\n", err.Error())
-		return nil
-	case vs != nil && len(vs.Spec.Hosts) > 0:
-		consoleURL = fmt.Sprintf("https://%s", vs.Spec.Hosts[0])
-	default:
-		fmt.Println("Kyma console URL could not be obtained.")
+	host, err := kube.ConsoleHost(c.K8s)
+	if err != nil {
+		fmt.Fprintf(c.Stdout(), "Unable to read the Kyma console URL due to error: %s. Check if your cluster is available and has Kyma installed\r\n", err.Error())
 		return nil
 	}
+	consoleURL := fmt.Sprintf("https://%s", host)
 
-	fmt.Printf("Opening the Kyma console in the default browser using the following url: %s\n", consoleURL)
-	err = browser.OpenURL(consoleURL)
+	c.openConsole(consoleURL)
+	return nil
+}
+
+func (c *command) openConsole(consoleURL string) {
+	fmt.Fprintf(c.Stdout(), "Opening the Kyma console in the default browser using the following url: %s\n", consoleURL)
+	err := browser.OpenURL(consoleURL)
 	if err != nil {
-		fmt.Println("Failed to open the Kyma console. Try to open the url manually")
+		fmt.Fprintln(c.Stdout(), "Failed to open the Kyma console. Try to open the url manually")
 		if c.opts.Verbose {
-			fmt.Printf("error: %v\n", err)
+			fmt.Fprintf(c.Stdout(), "error: %v\n", err)
 		}
 	}
+}
 
-	return nil
+// portForward forwards the local ports to the console and Dex and opens the console on localhost, until the command is interrupted.
+// The forwards run in the CLI process, so nothing is left running once it exits.
+func (c *command) portForward() error {
+	stop := make(chan struct{})
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, os.Interrupt)
+	defer signal.Stop(interrupt)
+
+	consoleReady := make(chan struct{}, 1)
+	failed := make(chan error, 1)
+	forward := func(service string, port int, ready chan<- struct{}, required bool) {
+		pf := &kube.PortForward{
+			Namespace: "kyma-system",
+			Service:   service,
+			LocalPort: port,
+			Ready: func(pod string) {
+				if c.opts.Verbose {
+					fmt.Fprintf(c.Stdout(), "Forwarding localhost:%d to the pod '%s' of the service '%s'\n", port, pod, service)
+				}
+				if ready != nil {
+					select {
+					case ready <- struct{}{}:
+					default:
+					}
+				}
+			},
+			Lost: func(err error) {
+				fmt.Fprintf(c.Stdout(), "Port-forward to the service '%s' lost, reconnecting: %s\n", service, err)
+			},
+		}
+		if err := pf.Run(c.K8s, stop); err != nil {
+			if required {
+				failed <- err
+				return
+			}
+			fmt.Fprintf(c.Stdout(), "Warning: %s, the login to the console might not work\n", err)
+		}
+	}
+	go forward("console-web", c.opts.ConsolePort, consoleReady, true)
+	go forward("dex-service", c.opts.DexPort, nil, false)
+
+	opened := false
+	for {
+		select {
+		case <-consoleReady:
+			if !opened {
+				opened = true
+				c.openConsole(fmt.Sprintf("https://localhost:%d", c.opts.ConsolePort))
+				fmt.Fprintln(c.Stdout(), "Press Ctrl+C to stop forwarding the ports.")
+			}
+		case err := <-failed:
+			close(stop)
+			return errors.Wrap(err, "Unable to forward a port to the Kyma console")
+		case <-interrupt:
+			close(stop)
+			return nil
+		}
+	}
 }
@@ -0,0 +1,31 @@
+package console
+
+import (
+	"testing"
+
+	"github.com/kyma-project/cli/internal/cli"
+	"github.com/stretchr/testify/require"
+)
+
+// TestConsoleFlags ensures that the provided command flags are stored in the options.
+func TestConsoleFlags(t *testing.T) {
+	t.Parallel()
+	o := NewOptions(&cli.Options{})
+	c := NewCmd(o)
+
+	// test default flag values
+	require.False(t, o.PortForward, "Default value for the port-forward flag not as expected.")
+	require.Equal(t, 3000, o.ConsolePort, "Default value for the console-port flag not as expected.")
+	require.Equal(t, 5556, o.DexPort, "Default value for the dex-port flag not as expected.")
+
+	// test passing flags
+	err := c.ParseFlags([]string{
+		"--port-forward",
+		"--console-port", "8000",
+		"--dex-port", "8001",
+	})
+	require.NoError(t, err, "Parsing flags should not return an error")
+	require.True(t, o.PortForward, "The parsed value for the port-forward flag not as expected.")
+	require.Equal(t, 8000, o.ConsolePort, "The parsed value for the console-port flag not as expected.")
+	require.Equal(t, 8001, o.DexPort, "The parsed value for the dex-port flag not as expected.")
+}
@@ -5,6 +5,10 @@ import "github.com/kyma-project/cli/internal/cli"
 //Options defines available options for the console command
 type Options struct {
 	*cli.Options
+
+	PortForward bool
+	ConsolePort int
+	DexPort     int
 }
 
 //NewOptions creates options with default values
@@ -62,7 +62,7 @@ To generate a new token, rerun the same command with the ` + "`--update`" + ` fl
 func (c *command) Run(args []string) error {
 	if c.opts.OutputFormat == "" && !c.opts.NonInteractive {
 		// TODO remove when out of alpha
-		np := nice.Nice{}
+		np := nice.Nice{Out: c.Stdout()}
 		np.PrintImportant("WARNING: This command is experimental and might change in its final version.")
 	}
 
@@ -126,14 +126,14 @@ func (c *command) Run(args []string) error {
 		if err != nil {
 			return err
 		}
-		fmt.Println(string(b))
+		fmt.Fprintln(c.Stdout(), string(b))
 
 	case "json":
 		b, err := json.MarshalIndent(token.Object, "", " ")
 		if err != nil {
 			return err
 		}
-		fmt.Println(string(b))
+		fmt.Fprintln(c.Stdout(), string(b))
 
 	default:
 
@@ -153,20 +153,21 @@ func (c *command) Run(args []string) error {
 			return errors.New("system access URL not found")
 		}
 
-		fmt.Println("")
+		fmt.Fprintln(c.Stdout(), "")
 		nicePrint := nice.Nice{
 			NonInteractive: c.Factory.NonInteractive,
+			Out:            c.Stdout(),
 		}
-		fmt.Print("System name:\t\t")
+		fmt.Fprint(c.Stdout(), "System name:\t\t")
 		nicePrint.PrintImportant(name)
 
-		fmt.Print("Namespace:\t\t")
+		fmt.Fprint(c.Stdout(), "Namespace:\t\t")
 		nicePrint.PrintImportant(c.opts.Namespace)
 
-		fmt.Print("Token:\t\t\t")
+		fmt.Fprint(c.Stdout(), "Token:\t\t\t")
 		nicePrint.PrintImportant(t)
 
-		fmt.Print("URL:\t\t\t")
+		fmt.Fprint(c.Stdout(), "URL:\t\t\t")
 		nicePrint.PrintImportant(url)
 	}
 
@@ -0,0 +1,66 @@
+package defaults
+
+import (
+	"fmt"
+
+	"github.com/kyma-project/cli/internal/cli"
+	pkgErrors "github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v2"
+)
+
+type command struct {
+	opts *Options
+	cli.Command
+}
+
+//NewCmd creates a new defaults command
+func NewCmd(o *Options) *cobra.Command {
+	cmd := command{
+		Command: cli.Command{Options: o.Options},
+		opts:    o,
+	}
+
+	cobraCmd := &cobra.Command{
+		Use:   "defaults",
+		Short: "Displays the defaults embedded in Kyma CLI.",
+		Long: `Use this command to display the data embedded in this build of Kyma CLI as YAML: the default Kyma release, the range of supported Kyma versions, the installation profiles with their components and overrides, the aliases of renamed components, and the namespaces, label selectors, and resource names of the Kyma releases.
+The output is the same for the same build, and its format is versioned with "schemaVersion". The data loaded with --profile-dir and --metadata-file is not part of it.
+
+With --write-dir, the defaults are written to files instead, which can be edited and passed back to the CLI: the profiles to "profiles/<name>.yaml" for --profile-dir, the release metadata to "release-metadata.yaml" for --metadata-file, and all defaults to "defaults.yaml" for reference. Existing files are replaced.
+`,
+		Example: `  # Display the defaults of the CLI
+  kyma defaults
+
+  # Change the minimal profile and install it
+  kyma defaults --write-dir ./kyma-defaults
+  kyma install --profile-dir ./kyma-defaults/profiles --profile minimal`,
+		RunE: func(_ *cobra.Command, _ []string) error { return cmd.Run() },
+	}
+
+	cobraCmd.Flags().StringVar(&o.WriteDir, "write-dir", "", "Directory to which the defaults are written as editable files, instead of displaying them.")
+	cli.MarkPathFlags(cobraCmd, "write-dir")
+	return cobraCmd
+}
+
+//Run runs the command
+func (cmd *command) Run() error {
+	d := Collect()
+	if cmd.opts.WriteDir == "" {
+		out, err := yaml.Marshal(d)
+		if err != nil {
+			return pkgErrors.Wrap(err, "unable to marshal the defaults")
+		}
+		fmt.Fprint(cmd.Stdout(), string(out))
+		return nil
+	}
+
+	files, err := Write(cmd.opts.WriteDir, d)
+	if err != nil {
+		return err
+	}
+	for _, f := range files {
+		fmt.Fprintln(cmd.Stdout(), f)
+	}
+	return nil
+}
@@ -0,0 +1,23 @@
+package defaults
+
+import (
+	"testing"
+
+	"github.com/kyma-project/cli/internal/cli"
+	"github.com/stretchr/testify/require"
+)
+
+// TestDefaultsFlags ensures that the provided command flags are stored in the options.
+func TestDefaultsFlags(t *testing.T) {
+	t.Parallel()
+	o := NewOptions(&cli.Options{})
+	c := NewCmd(o)
+
+	// test default flag values
+	require.Equal(t, "", o.WriteDir, "Default value for the write-dir flag not as expected.")
+
+	// test passing flags
+	err := c.ParseFlags([]string{"--write-dir", "/tmp/kyma-defaults"})
+	require.NoError(t, err, "Parsing flags should not return an error")
+	require.Equal(t, "/tmp/kyma-defaults", o.WriteDir, "The parsed value for the write-dir flag not as expected.")
+}
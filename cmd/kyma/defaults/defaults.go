@@ -0,0 +1,102 @@
+package defaults
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/kyma-project/cli/internal/cli"
+	"github.com/kyma-project/cli/internal/metadata"
+	kymaVersion "github.com/kyma-project/cli/internal/version"
+	"github.com/kyma-project/cli/pkg/installation"
+	pkgErrors "github.com/pkg/errors"
+	"gopkg.in/yaml.v2"
+)
+
+// SchemaVersion is the version of the format of Defaults. It is increased if fields are renamed or removed, so that tools reading the output notice it.
+const SchemaVersion = 1
+
+// the files and directories written by Write
+const (
+	defaultsFile = "defaults.yaml"
+	profilesDir  = "profiles"
+	metadataFile = "release-metadata.yaml"
+)
+
+// Defaults is the data embedded in the CLI, as printed by "kyma defaults"
+type Defaults struct {
+	SchemaVersion int `yaml:"schemaVersion"`
+	// CLIVersion is the version of the CLI build the data belongs to
+	CLIVersion string `yaml:"cliVersion"`
+	// DefaultRelease is the Kyma release installed if no source is given
+	DefaultRelease string `yaml:"defaultRelease"`
+	// Compatibility is the range of Kyma minor versions the CLI supports
+	Compatibility Compatibility `yaml:"compatibility"`
+	// Profiles are the installation profiles, sorted by name
+	Profiles []installation.Profile `yaml:"profiles"`
+	// ComponentAliases maps the current names of renamed components to the names used by the releases
+	ComponentAliases map[string][]installation.ComponentAliasMapping `yaml:"componentAliases"`
+	// ReleaseMetadata is the table of namespaces, label selectors and resource names per Kyma release range
+	ReleaseMetadata []metadata.Metadata `yaml:"releaseMetadata"`
+}
+
+// Compatibility is the range of Kyma minor versions the CLI supports, e.g. "1.14" to "1.18"
+type Compatibility struct {
+	MinKymaVersion string `yaml:"minKymaVersion"`
+	MaxKymaVersion string `yaml:"maxKymaVersion"`
+}
+
+// Collect returns the data embedded in the CLI. Data loaded with --profile-dir or --metadata-file is not part of it.
+func Collect() Defaults {
+	cliVersion := cli.Version
+	if cliVersion == "" {
+		cliVersion = "N/A"
+	}
+	return Defaults{
+		SchemaVersion:    SchemaVersion,
+		CLIVersion:       cliVersion,
+		DefaultRelease:   kymaVersion.DefaultKymaVersion,
+		Compatibility:    Compatibility{MinKymaVersion: kymaVersion.MinKymaVersion, MaxKymaVersion: kymaVersion.MaxKymaVersion},
+		Profiles:         installation.EmbeddedProfiles(),
+		ComponentAliases: installation.ComponentAliases(),
+		ReleaseMetadata:  metadata.Embedded(),
+	}
+}
+
+// Write dumps the defaults to the directory as files which can be edited and passed to the CLI: one file per profile for --profile-dir,
+// the release metadata for --metadata-file, and all defaults in one file for reference. Existing files are replaced. It returns the written files.
+func Write(dir string, d Defaults) ([]string, error) {
+	if err := os.MkdirAll(filepath.Join(dir, profilesDir), 0700); err != nil {
+		return nil, pkgErrors.Wrap(err, "unable to create the directory of the defaults")
+	}
+
+	var written []string
+	write := func(name, header string, v interface{}) error {
+		data, err := yaml.Marshal(v)
+		if err != nil {
+			return pkgErrors.Wrapf(err, "unable to marshal %s", name)
+		}
+		path := filepath.Join(dir, name)
+		if err := ioutil.WriteFile(path, append([]byte(header), data...), 0600); err != nil {
+			return pkgErrors.Wrapf(err, "unable to write %s", path)
+		}
+		written = append(written, path)
+		return nil
+	}
+
+	origin := fmt.Sprintf("# Embedded in Kyma CLI %s (schema version %d).\n", d.CLIVersion, d.SchemaVersion)
+	if err := write(defaultsFile, origin+"# For reference only, the CLI does not read this file.\n", d); err != nil {
+		return nil, err
+	}
+	for _, p := range d.Profiles {
+		header := origin + fmt.Sprintf("# Use the directory with --profile-dir, and this profile with --profile %s.\n", p.Name)
+		if err := write(filepath.Join(profilesDir, p.Name+".yaml"), header, p); err != nil {
+			return nil, err
+		}
+	}
+	if err := write(metadataFile, origin+"# Use it with --metadata-file.\n", d.ReleaseMetadata); err != nil {
+		return nil, err
+	}
+	return written, nil
+}
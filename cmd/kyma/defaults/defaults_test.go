@@ -0,0 +1,66 @@
+package defaults
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/kyma-project/cli/internal/metadata"
+	"github.com/kyma-project/cli/pkg/installation"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v2"
+)
+
+func TestCollect(t *testing.T) {
+	t.Parallel()
+	d := Collect()
+	require.Equal(t, SchemaVersion, d.SchemaVersion)
+	require.NotEmpty(t, d.Profiles)
+	require.NotEmpty(t, d.ComponentAliases)
+	require.NotEmpty(t, d.ReleaseMetadata)
+
+	// the output is deterministic
+	first, err := yaml.Marshal(d)
+	require.NoError(t, err)
+	for n := 0; n < 5; n++ {
+		out, err := yaml.Marshal(Collect())
+		require.NoError(t, err)
+		require.Equal(t, string(first), string(out))
+	}
+
+	var parsed Defaults
+	require.NoError(t, yaml.UnmarshalStrict(first, &parsed))
+	require.Equal(t, d, parsed, "the output must load back identically")
+}
+
+func TestWrite(t *testing.T) {
+	t.Parallel()
+	dir, err := ioutil.TempDir("", "kyma-defaults-")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	d := Collect()
+	files, err := Write(dir, d)
+	require.NoError(t, err)
+	require.Len(t, files, len(d.Profiles)+2)
+
+	// the files load back identically with the loaders of the flags consuming them
+	profiles, err := installation.ReadProfiles(filepath.Join(dir, profilesDir))
+	require.NoError(t, err)
+	require.Equal(t, d.Profiles, profiles)
+
+	entries, err := metadata.Read(filepath.Join(dir, metadataFile))
+	require.NoError(t, err)
+	require.Equal(t, d.ReleaseMetadata, entries)
+
+	data, err := ioutil.ReadFile(filepath.Join(dir, defaultsFile))
+	require.NoError(t, err)
+	var parsed Defaults
+	require.NoError(t, yaml.UnmarshalStrict(data, &parsed))
+	require.Equal(t, d, parsed)
+
+	// existing files are replaced
+	_, err = Write(dir, d)
+	require.NoError(t, err)
+}
@@ -0,0 +1,14 @@
+package defaults
+
+import "github.com/kyma-project/cli/internal/cli"
+
+//Options defines available options for the defaults command
+type Options struct {
+	*cli.Options
+	WriteDir string
+}
+
+//NewOptions creates options with default values
+func NewOptions(o *cli.Options) *Options {
+	return &Options{Options: o}
+}
@@ -0,0 +1,61 @@
+package diagnostics
+
+import (
+	"fmt"
+	"io/ioutil"
+	"time"
+
+	"github.com/kyma-project/cli/internal/cli"
+	diag "github.com/kyma-project/cli/internal/diagnostics"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+type command struct {
+	opts *Options
+}
+
+//NewCmd creates a new diagnostics command
+func NewCmd(o *Options) *cobra.Command {
+	c := &command{opts: o}
+
+	cmd := &cobra.Command{
+		Use:   "diagnostics",
+		Short: "Displays the environment of Kyma CLI for support requests.",
+		Long: `Use this command to collect the details usually asked for in support requests and issues:
+the versions of Kyma CLI, kubectl and the Kubernetes cluster, the current kubeconfig context and whether its API server is reachable, the Minikube status, the connection to Docker, the free disk space, and whether the Kyma release can be downloaded.
+The details are printed as a block which can be copied into an issue. Use --redact to mask the server URLs and the home directory before sharing them.
+`,
+		RunE: func(_ *cobra.Command, _ []string) error { return c.Run() },
+	}
+
+	cmd.Flags().StringVarP(&o.OutputFile, "output-file", "f", "", "Path to a file to which the diagnostics are written in addition to the output, e.g. for attaching it to an issue.")
+	cmd.Flags().BoolVar(&o.Redact, "redact", false, "Masks the server URLs, Docker hosts and the home directory in the diagnostics.")
+	// the global flag is repeated, so that it is described for the command
+	cmd.Flags().StringVar(&o.Options.MinikubeProfile, "minikube-profile", "", "Minikube profile whose status is displayed. If not set, the profile named like the current kubeconfig context is used.")
+	cmd.Flags().DurationVar(&o.Timeout, "timeout", 10*time.Second, "Maximum time of each check.")
+	cli.MarkPathFlags(cmd, "output-file")
+	return cmd
+}
+
+//Run runs the command
+func (c *command) Run() error {
+	report := diag.Collect(diag.Options{
+		CLIVersion:      cli.Version,
+		KubeconfigPath:  c.opts.KubeconfigPath,
+		MinikubeProfile: c.opts.MinikubeProfile,
+		Timeout:         c.opts.Timeout,
+	})
+	if c.opts.Redact {
+		report.Redact()
+	}
+	fmt.Fprint(c.opts.Stdout(), report)
+
+	if c.opts.OutputFile != "" {
+		if err := ioutil.WriteFile(c.opts.OutputFile, []byte(report.String()), 0600); err != nil {
+			return errors.Wrap(err, "unable to write the diagnostics")
+		}
+		fmt.Fprintf(c.opts.Stdout(), "Diagnostics written to %s\n", c.opts.OutputFile)
+	}
+	return nil
+}
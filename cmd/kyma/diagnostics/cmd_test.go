@@ -0,0 +1,35 @@
+package diagnostics
+
+import (
+	"testing"
+	"time"
+
+	"github.com/kyma-project/cli/internal/cli"
+	"github.com/stretchr/testify/require"
+)
+
+// TestDiagnosticsFlags ensures that the provided command flags are stored in the options.
+func TestDiagnosticsFlags(t *testing.T) {
+	t.Parallel()
+	o := NewOptions(&cli.Options{})
+	c := NewCmd(o)
+
+	// test default flag values
+	require.Equal(t, "", o.OutputFile, "Default value for the output-file flag not as expected.")
+	require.Equal(t, false, o.Redact, "Default value for the redact flag not as expected.")
+	require.Equal(t, "", o.MinikubeProfile, "Default value for the minikube-profile flag not as expected.")
+	require.Equal(t, 10*time.Second, o.Timeout, "Default value for the timeout flag not as expected.")
+
+	// test passing flags
+	err := c.ParseFlags([]string{
+		"--output-file", "/tmp/diagnostics.txt",
+		"--redact",
+		"--minikube-profile", "kyma",
+		"--timeout", "3s",
+	})
+	require.NoError(t, err, "Parsing flags should not return an error")
+	require.Equal(t, "/tmp/diagnostics.txt", o.OutputFile, "The parsed value for the output-file flag not as expected.")
+	require.Equal(t, true, o.Redact, "The parsed value for the redact flag not as expected.")
+	require.Equal(t, "kyma", o.MinikubeProfile, "The parsed value for the minikube-profile flag not as expected.")
+	require.Equal(t, 3*time.Second, o.Timeout, "The parsed value for the timeout flag not as expected.")
+}
@@ -0,0 +1,20 @@
+package diagnostics
+
+import (
+	"time"
+
+	"github.com/kyma-project/cli/internal/cli"
+)
+
+//Options defines available options for the diagnostics command
+type Options struct {
+	*cli.Options
+	OutputFile string
+	Redact     bool
+	Timeout    time.Duration
+}
+
+//NewOptions creates options with default values
+func NewOptions(o *cli.Options) *Options {
+	return &Options{Options: o}
+}
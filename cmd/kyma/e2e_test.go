@@ -0,0 +1,275 @@
+package kyma
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kyma-project/cli/internal/cli"
+	"github.com/kyma-project/cli/internal/trace"
+	"github.com/stretchr/testify/require"
+	"sigs.k8s.io/yaml"
+)
+
+// e2eScenario is a scripted run of a CLI command against the fake API server
+type e2eScenario struct {
+	// Args are the arguments passed to the CLI, the kubeconfig of the fake API server is added.
+	Args []string `json:"args"`
+	// Env holds environment variables set while the CLI runs, an empty value unsets the variable.
+	Env map[string]string `json:"env"`
+	// Objects are the resources on the cluster before the CLI runs.
+	Objects []map[string]interface{} `json:"objects"`
+	// Reactions simulate the Kyma Installer and other components acting on the cluster.
+	Reactions []fakeReaction `json:"reactions"`
+	// Requests must be sent to the API server in the given order, other requests may happen in between.
+	Requests []string `json:"requests"`
+	// UnexpectedRequests must not be sent to the API server.
+	UnexpectedRequests []string `json:"unexpectedRequests"`
+	// Output must be printed by the CLI in the given order.
+	Output []string `json:"output"`
+	// Error is the expected error of the command, if empty the command must succeed.
+	Error string `json:"error"`
+}
+
+// TestInstallEndToEnd runs "kyma install" with the full command tree for each scenario in testdata/e2e.
+// The cluster is replaced by a fake API server and the release artifacts are served from testdata/e2e/release.
+// The tests capture the standard outputs of the process, so they must not run in parallel.
+func TestInstallEndToEnd(t *testing.T) {
+	scenarios, err := filepath.Glob(filepath.Join("testdata", "e2e", "install-*.yaml"))
+	require.NoError(t, err)
+	require.NotEmpty(t, scenarios, "no scenarios found in testdata/e2e")
+
+	defaultTransport := http.DefaultTransport
+	http.DefaultTransport = releaseTransport{dir: filepath.Join("testdata", "e2e", "release"), next: defaultTransport}
+	defer func() { http.DefaultTransport = defaultTransport }()
+
+	for _, file := range scenarios {
+		file := file
+		t.Run(strings.TrimSuffix(filepath.Base(file), ".yaml"), func(t *testing.T) {
+			runScenario(t, file)
+		})
+	}
+}
+
+func runScenario(t *testing.T, file string) {
+	runE2EScenario(t, loadScenario(t, file))
+}
+
+func loadScenario(t *testing.T, file string) e2eScenario {
+	data, err := ioutil.ReadFile(file)
+	require.NoError(t, err)
+	scenario := e2eScenario{}
+	require.NoError(t, yaml.Unmarshal(data, &scenario), "invalid scenario %s", file)
+	return scenario
+}
+
+func runE2EScenario(t *testing.T, scenario e2eScenario) {
+	server, err := newFakeAPIServer(scenario.Objects, scenario.Reactions)
+	require.NoError(t, err)
+	defer server.Close()
+
+	dir, err := ioutil.TempDir("", "kyma-e2e-")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	kubeconfig := filepath.Join(dir, "kubeconfig")
+	require.NoError(t, ioutil.WriteFile(kubeconfig, []byte(server.Kubeconfig()), 0600))
+
+	runE2EStep(t, server, kubeconfig, scenario)
+}
+
+// runE2EStep runs the CLI command of the scenario against a running fake API server, the objects and reactions of the scenario are not used.
+// Only the requests sent by this command are checked, so that several commands can run against the same server one after the other.
+func runE2EStep(t *testing.T, server *fakeAPIServer, kubeconfig string, scenario e2eScenario) {
+	for k, v := range scenario.Env {
+		defer restoreEnv(k)()
+		if v == "" {
+			require.NoError(t, os.Unsetenv(k))
+		} else {
+			require.NoError(t, os.Setenv(k, v))
+		}
+	}
+
+	sent := len(server.Requests())
+	output, err := runCLI(append([]string{"--kubeconfig", kubeconfig}, scenario.Args...))
+	requests := server.Requests()[sent:]
+
+	if scenario.Error == "" {
+		require.NoError(t, err, "command failed, output:\n%s", output)
+	} else {
+		require.Error(t, err, "command must fail, output:\n%s", output)
+		require.Contains(t, err.Error(), scenario.Error)
+	}
+	requireInOrder(t, scenario.Output, strings.Split(output, "\n"), strings.Contains, "output")
+	requireInOrder(t, scenario.Requests, requests, func(a, b string) bool { return a == b }, "requests")
+	for _, r := range scenario.UnexpectedRequests {
+		require.NotContains(t, requests, r, "unexpected request sent to the API server")
+	}
+}
+
+// e2eStages are CLI commands run one after the other against the same fake API server
+type e2eStages struct {
+	// Objects are the resources on the cluster before the first command runs.
+	Objects []map[string]interface{} `json:"objects"`
+	// Reactions simulate the Kyma Installer and other components acting on the cluster during all commands.
+	Reactions []fakeReaction `json:"reactions"`
+	// Steps are the commands in the order they run, their objects and reactions are not used.
+	Steps []struct {
+		e2eScenario
+		// Simulate are reactions applied before the command runs, e.g. the Kyma Installer finishing the installation.
+		Simulate []fakeReaction `json:"simulate"`
+	} `json:"steps"`
+}
+
+// TestInstallStages runs the stages of "kyma install" separately for each scenario in testdata/e2e, like a script would
+func TestInstallStages(t *testing.T) {
+	scenarios, err := filepath.Glob(filepath.Join("testdata", "e2e", "stages-*.yaml"))
+	require.NoError(t, err)
+	require.NotEmpty(t, scenarios, "no stage scenarios found in testdata/e2e")
+
+	defaultTransport := http.DefaultTransport
+	http.DefaultTransport = releaseTransport{dir: filepath.Join("testdata", "e2e", "release"), next: defaultTransport}
+	defer func() { http.DefaultTransport = defaultTransport }()
+
+	for _, file := range scenarios {
+		file := file
+		t.Run(strings.TrimSuffix(filepath.Base(file), ".yaml"), func(t *testing.T) {
+			data, err := ioutil.ReadFile(file)
+			require.NoError(t, err)
+			stages := e2eStages{}
+			require.NoError(t, yaml.Unmarshal(data, &stages), "invalid scenario %s", file)
+
+			server, err := newFakeAPIServer(stages.Objects, stages.Reactions)
+			require.NoError(t, err)
+			defer server.Close()
+			dir, err := ioutil.TempDir("", "kyma-e2e-")
+			require.NoError(t, err)
+			defer os.RemoveAll(dir)
+			kubeconfig := filepath.Join(dir, "kubeconfig")
+			require.NoError(t, ioutil.WriteFile(kubeconfig, []byte(server.Kubeconfig()), 0600))
+
+			for _, step := range stages.Steps {
+				for _, r := range step.Simulate {
+					require.NoError(t, server.Simulate(r))
+				}
+				runE2EStep(t, server, kubeconfig, step.e2eScenario)
+			}
+		})
+	}
+}
+
+// TestInstallTrace checks that --trace-file records the stages of a scripted installation and the checks of the installation state within the span of the command
+func TestInstallTrace(t *testing.T) {
+	defaultTransport := http.DefaultTransport
+	http.DefaultTransport = releaseTransport{dir: filepath.Join("testdata", "e2e", "release"), next: defaultTransport}
+	defer func() { http.DefaultTransport = defaultTransport }()
+
+	dir, err := ioutil.TempDir("", "kyma-trace-")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	file := filepath.Join(dir, "trace.json")
+
+	scenario := loadScenario(t, filepath.Join("testdata", "e2e", "install-fresh.yaml"))
+	scenario.Args = append(scenario.Args, "--trace-file", file)
+	runE2EScenario(t, scenario)
+
+	spans, err := trace.ReadChrome(file)
+	require.NoError(t, err)
+	byName := map[string]trace.Span{}
+	for _, s := range spans {
+		byName[s.Name] = s
+	}
+	root, ok := byName["kyma install"]
+	require.True(t, ok, "no span of the command in %v", spans)
+	require.Equal(t, 0, root.Parent)
+	// the times are written in microseconds, so the bounds may be off by the rounding
+	rounding := 2 * time.Microsecond
+	for _, name := range []string{"stage: acquiring the cluster lock", "stage: checking the previous installation", "stage: triggering the installation", "poll installation state"} {
+		s, ok := byName[name]
+		require.True(t, ok, "no span %q in %v", name, spans)
+		require.Equal(t, root.ID, s.Parent, "span %q must be a child of the command", name)
+		require.False(t, s.Start.Add(rounding).Before(root.Start), "span %q starts before the command", name)
+		require.False(t, s.Start.Add(s.Duration).After(root.Start.Add(root.Duration+rounding)), "span %q ends after the command", name)
+	}
+}
+
+// runCLI executes the Kyma CLI with the given arguments and returns everything it printed
+func runCLI(args []string) (string, error) {
+	r, w, err := os.Pipe()
+	if err != nil {
+		return "", err
+	}
+	stdout, stderr := os.Stdout, os.Stderr
+	os.Stdout, os.Stderr = w, w
+	output := make(chan string)
+	go func() {
+		data, _ := ioutil.ReadAll(r)
+		output <- string(data)
+	}()
+
+	cmd := NewCmd(&cli.Options{})
+	cmd.SetArgs(args)
+	err = cmd.Execute()
+	// like the main function, the trace file is written once the command returns
+	if traceErr := trace.Flush(err); traceErr != nil && err == nil {
+		err = traceErr
+	}
+
+	os.Stdout, os.Stderr = stdout, stderr
+	w.Close()
+	return <-output, err
+}
+
+// requireInOrder checks that the actual entries contain all expected ones in the given order
+func requireInOrder(t *testing.T, expected, actual []string, matches func(actual, expected string) bool, what string) {
+	n := 0
+	for _, e := range expected {
+		for n < len(actual) && !matches(actual[n], e) {
+			n++
+		}
+		require.True(t, n < len(actual), "%s do not contain %q in the expected order, got:\n%s", what, e, strings.Join(actual, "\n"))
+		n++
+	}
+}
+
+func restoreEnv(key string) func() {
+	value, set := os.LookupEnv(key)
+	return func() {
+		if set {
+			os.Setenv(key, value)
+		} else {
+			os.Unsetenv(key)
+		}
+	}
+}
+
+// releaseTransport serves the release artifacts from a local directory instead of the Google Cloud Storage buckets
+type releaseTransport struct {
+	dir  string
+	next http.RoundTripper
+}
+
+func (t releaseTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	if r.URL.Host != "storage.googleapis.com" {
+		return t.next.RoundTrip(r)
+	}
+	status := http.StatusOK
+	data, err := ioutil.ReadFile(filepath.Join(t.dir, path.Base(r.URL.Path)))
+	if err != nil {
+		status = http.StatusNotFound
+		data = nil
+	}
+	return &http.Response{
+		Status:        http.StatusText(status),
+		StatusCode:    status,
+		Header:        http.Header{},
+		Body:          ioutil.NopCloser(bytes.NewReader(data)),
+		ContentLength: int64(len(data)),
+		Request:       r,
+	}, nil
+}
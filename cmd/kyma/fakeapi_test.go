@@ -0,0 +1,474 @@
+package kyma
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// fakeResource is a resource type served by the fake API server
+type fakeResource struct {
+	group      string
+	version    string
+	resource   string
+	kind       string
+	namespaced bool
+}
+
+func (r fakeResource) groupVersion() string {
+	if r.group == "" {
+		return r.version
+	}
+	return r.group + "/" + r.version
+}
+
+// path is the root path of the group version of the resource
+func (r fakeResource) path() string {
+	if r.group == "" {
+		return "/api/" + r.version
+	}
+	return "/apis/" + r.groupVersion()
+}
+
+// fakeResources are the resource types the CLI uses during an installation, they are also announced by the discovery endpoints
+var fakeResources = []fakeResource{
+	{"", "v1", "namespaces", "Namespace", false},
+	{"", "v1", "configmaps", "ConfigMap", true},
+	{"", "v1", "secrets", "Secret", true},
+	{"", "v1", "pods", "Pod", true},
+	{"", "v1", "serviceaccounts", "ServiceAccount", true},
+	{"apps", "v1", "deployments", "Deployment", true},
+	{"rbac.authorization.k8s.io", "v1", "clusterrolebindings", "ClusterRoleBinding", false},
+	{"apiextensions.k8s.io", "v1beta1", "customresourcedefinitions", "CustomResourceDefinition", false},
+	{"installer.kyma-project.io", "v1alpha1", "installations", "Installation", true},
+	{"networking.istio.io", "v1alpha3", "virtualservices", "VirtualService", true},
+}
+
+// fakeReaction simulates the components acting on the cluster, such as the Kyma Installer.
+// When a request matching Request succeeds, Merge is merged into the requested object and the Apply objects are stored.
+type fakeReaction struct {
+	Request string                   `json:"request"`
+	Merge   map[string]interface{}   `json:"merge,omitempty"`
+	Apply   []map[string]interface{} `json:"apply,omitempty"`
+}
+
+// fakeAPIServer is an in-memory Kubernetes API server which records all requests.
+// It replaces kubectl and a real cluster in the end-to-end tests, as the CLI talks to the API server through client-go.
+type fakeAPIServer struct {
+	*httptest.Server
+
+	mu        sync.Mutex
+	objects   map[string]map[string]interface{}
+	reactions []fakeReaction
+	requests  []string
+	version   int
+}
+
+func newFakeAPIServer(objects []map[string]interface{}, reactions []fakeReaction) (*fakeAPIServer, error) {
+	f := &fakeAPIServer{
+		objects:   map[string]map[string]interface{}{},
+		reactions: reactions,
+	}
+	for _, obj := range objects {
+		if err := f.store(obj); err != nil {
+			return nil, err
+		}
+	}
+	f.Server = httptest.NewServer(f)
+	return f, nil
+}
+
+// Requests returns the recorded requests as "METHOD path", watch requests are recorded with the WATCH method
+func (f *fakeAPIServer) Requests() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]string{}, f.requests...)
+}
+
+// Kubeconfig returns a kubeconfig pointing to the fake API server
+func (f *fakeAPIServer) Kubeconfig() string {
+	return fmt.Sprintf(`apiVersion: v1
+kind: Config
+clusters:
+- name: fake
+  cluster:
+    server: %s
+contexts:
+- name: fake
+  context:
+    cluster: fake
+    user: fake
+current-context: fake
+users:
+- name: fake
+  user:
+    token: fake
+`, f.URL)
+}
+
+func (f *fakeAPIServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	method := r.Method
+	if r.URL.Query().Get("watch") == "true" {
+		method = "WATCH"
+	}
+	request := method + " " + r.URL.Path
+	f.requests = append(f.requests, request)
+
+	if f.serveDiscovery(w, r) {
+		return
+	}
+	res, namespace, name, ok := resolvePath(r.URL.Path)
+	if !ok {
+		writeStatus(w, http.StatusNotFound, metav1.StatusReasonNotFound, fmt.Sprintf("the server could not find the requested resource %s", r.URL.Path))
+		return
+	}
+	key := objectKey(res, namespace, name)
+
+	var body map[string]interface{}
+	if r.Body != nil {
+		data, err := ioutil.ReadAll(r.Body)
+		if err == nil && len(data) > 0 {
+			if err := json.Unmarshal(data, &body); err != nil {
+				writeStatus(w, http.StatusBadRequest, metav1.StatusReasonBadRequest, err.Error())
+				return
+			}
+		}
+	}
+
+	switch {
+	case method == "WATCH":
+		// the CLI never relies on watch events, they are not supported
+		writeStatus(w, http.StatusMethodNotAllowed, metav1.StatusReasonMethodNotAllowed, "watch is not supported by the fake API server")
+
+	case method == http.MethodGet && name == "":
+		writeJSON(w, http.StatusOK, f.list(res, namespace, r.URL.Query().Get("labelSelector")))
+
+	case method == http.MethodGet:
+		obj, exists := f.objects[key]
+		if !exists {
+			writeStatus(w, http.StatusNotFound, metav1.StatusReasonNotFound, fmt.Sprintf("%s %q not found", res.resource, name))
+			return
+		}
+		writeJSON(w, http.StatusOK, obj)
+
+	case method == http.MethodPost && name == "":
+		name = stringAt(body, "metadata", "name")
+		key = objectKey(res, namespace, name)
+		if _, exists := f.objects[key]; exists {
+			writeStatus(w, http.StatusConflict, metav1.StatusReasonAlreadyExists, fmt.Sprintf("%s %q already exists", res.resource, name))
+			return
+		}
+		f.save(res, namespace, body)
+		f.react(request, key)
+		writeJSON(w, http.StatusCreated, f.objects[key])
+
+	case method == http.MethodPut || method == http.MethodPatch:
+		obj, exists := f.objects[key]
+		if !exists {
+			writeStatus(w, http.StatusNotFound, metav1.StatusReasonNotFound, fmt.Sprintf("%s %q not found", res.resource, name))
+			return
+		}
+		if method == http.MethodPatch {
+			body = mergeObjects(obj, body)
+		}
+		f.save(res, namespace, body)
+		f.react(request, key)
+		writeJSON(w, http.StatusOK, f.objects[key])
+
+	case method == http.MethodDelete:
+		if _, exists := f.objects[key]; !exists {
+			writeStatus(w, http.StatusNotFound, metav1.StatusReasonNotFound, fmt.Sprintf("%s %q not found", res.resource, name))
+			return
+		}
+		delete(f.objects, key)
+		f.react(request, key)
+		writeStatus(w, http.StatusOK, "", "")
+
+	default:
+		writeStatus(w, http.StatusMethodNotAllowed, metav1.StatusReasonMethodNotAllowed, fmt.Sprintf("%s is not supported by the fake API server", request))
+	}
+}
+
+// serveDiscovery announces the fake resources, as needed by the REST mapping of the installer SDK
+func (f *fakeAPIServer) serveDiscovery(w http.ResponseWriter, r *http.Request) bool {
+	path := strings.TrimSuffix(r.URL.Path, "/")
+	switch path {
+	case "/api":
+		writeJSON(w, http.StatusOK, &metav1.APIVersions{
+			TypeMeta: metav1.TypeMeta{Kind: "APIVersions"},
+			Versions: []string{"v1"},
+		})
+		return true
+	case "/apis":
+		groups := &metav1.APIGroupList{TypeMeta: metav1.TypeMeta{Kind: "APIGroupList", APIVersion: "v1"}}
+		seen := map[string]bool{}
+		for _, res := range fakeResources {
+			if res.group == "" || seen[res.group] {
+				continue
+			}
+			seen[res.group] = true
+			gv := metav1.GroupVersionForDiscovery{GroupVersion: res.groupVersion(), Version: res.version}
+			groups.Groups = append(groups.Groups, metav1.APIGroup{Name: res.group, Versions: []metav1.GroupVersionForDiscovery{gv}, PreferredVersion: gv})
+		}
+		writeJSON(w, http.StatusOK, groups)
+		return true
+	}
+
+	list := &metav1.APIResourceList{TypeMeta: metav1.TypeMeta{Kind: "APIResourceList", APIVersion: "v1"}}
+	for _, res := range fakeResources {
+		if path != res.path() {
+			continue
+		}
+		list.GroupVersion = res.groupVersion()
+		list.APIResources = append(list.APIResources, metav1.APIResource{
+			Name:       res.resource,
+			Namespaced: res.namespaced,
+			Kind:       res.kind,
+			Verbs:      metav1.Verbs{"create", "delete", "get", "list", "patch", "update"},
+		})
+	}
+	if list.GroupVersion == "" {
+		return false
+	}
+	writeJSON(w, http.StatusOK, list)
+	return true
+}
+
+func (f *fakeAPIServer) list(res fakeResource, namespace, selector string) map[string]interface{} {
+	keys := make([]string, 0, len(f.objects))
+	for key := range f.objects {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	// without a namespace, the objects of all namespaces are listed
+	prefix := res.group + "/" + res.resource + "/"
+	if namespace != "" {
+		prefix += namespace + "/"
+	}
+	items := []interface{}{}
+	for _, key := range keys {
+		if strings.HasPrefix(key, prefix) && matchesLabels(f.objects[key], selector) {
+			items = append(items, f.objects[key])
+		}
+	}
+	return map[string]interface{}{
+		"apiVersion": res.groupVersion(),
+		"kind":       res.kind + "List",
+		"metadata":   map[string]interface{}{"resourceVersion": strconv.Itoa(f.version)},
+		"items":      items,
+	}
+}
+
+// store adds an object of the scenario to the cluster
+func (f *fakeAPIServer) store(obj map[string]interface{}) error {
+	apiVersion, _ := obj["apiVersion"].(string)
+	kind, _ := obj["kind"].(string)
+	for _, res := range fakeResources {
+		if res.groupVersion() == apiVersion && res.kind == kind {
+			namespace := stringAt(obj, "metadata", "namespace")
+			if !res.namespaced {
+				namespace = ""
+			}
+			f.save(res, namespace, obj)
+			return nil
+		}
+	}
+	return fmt.Errorf("kind %s of %s is not served by the fake API server", kind, apiVersion)
+}
+
+// save stores the object and sets the fields maintained by the API server
+func (f *fakeAPIServer) save(res fakeResource, namespace string, obj map[string]interface{}) {
+	f.version++
+	metadata, _ := obj["metadata"].(map[string]interface{})
+	if metadata == nil {
+		metadata = map[string]interface{}{}
+		obj["metadata"] = metadata
+	}
+	name, _ := metadata["name"].(string)
+	key := objectKey(res, namespace, name)
+	if namespace != "" {
+		metadata["namespace"] = namespace
+	}
+	if existing, ok := f.objects[key]; ok {
+		metadata["uid"] = stringAt(existing, "metadata", "uid")
+		metadata["creationTimestamp"] = stringAt(existing, "metadata", "creationTimestamp")
+	} else {
+		metadata["uid"] = fmt.Sprintf("fake-uid-%d", f.version)
+		metadata["creationTimestamp"] = time.Now().UTC().Format(time.RFC3339)
+	}
+	metadata["resourceVersion"] = strconv.Itoa(f.version)
+	obj["apiVersion"] = res.groupVersion()
+	obj["kind"] = res.kind
+
+	if res.kind == "CustomResourceDefinition" {
+		// as the real API server, the fake one serves new resource types right away
+		obj["status"] = map[string]interface{}{
+			"conditions": []interface{}{map[string]interface{}{"type": "Established", "status": "True"}},
+		}
+	}
+	f.objects[key] = obj
+}
+
+func (f *fakeAPIServer) react(request, key string) {
+	for _, reaction := range f.reactions {
+		if reaction.Request == request {
+			f.apply(reaction, key)
+		}
+	}
+}
+
+// Simulate applies the reaction as if its request succeeded, e.g. for the Kyma Installer acting on the cluster between two commands
+func (f *fakeAPIServer) Simulate(reaction fakeReaction) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	parts := strings.SplitN(reaction.Request, " ", 2)
+	if len(parts) != 2 {
+		return fmt.Errorf("invalid request %q", reaction.Request)
+	}
+	res, namespace, name, ok := resolvePath(parts[1])
+	if !ok {
+		return fmt.Errorf("unknown resource of the request %q", reaction.Request)
+	}
+	f.apply(reaction, objectKey(res, namespace, name))
+	return nil
+}
+
+func (f *fakeAPIServer) apply(reaction fakeReaction, key string) {
+	if obj, ok := f.objects[key]; ok && reaction.Merge != nil {
+		f.objects[key] = mergeObjects(obj, reaction.Merge)
+	}
+	for _, obj := range reaction.Apply {
+		_ = f.store(mergeObjects(map[string]interface{}{}, obj))
+	}
+}
+
+// resolvePath returns the resource type, namespace and name addressed by the path. For collections, the name is empty.
+func resolvePath(path string) (fakeResource, string, string, bool) {
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	var group, version string
+	var rest []string
+	switch {
+	case len(segments) >= 2 && segments[0] == "api":
+		version, rest = segments[1], segments[2:]
+	case len(segments) >= 3 && segments[0] == "apis":
+		group, version, rest = segments[1], segments[2], segments[3:]
+	default:
+		return fakeResource{}, "", "", false
+	}
+
+	var namespace string
+	if len(rest) >= 3 && rest[0] == "namespaces" {
+		namespace, rest = rest[1], rest[2:]
+	}
+	if len(rest) == 0 || len(rest) > 2 {
+		return fakeResource{}, "", "", false
+	}
+	var name string
+	if len(rest) == 2 {
+		name = rest[1]
+	}
+
+	for _, res := range fakeResources {
+		if res.group == group && res.version == version && res.resource == rest[0] && res.namespaced == (namespace != "") {
+			return res, namespace, name, true
+		}
+	}
+	// namespaced resources can also be listed across all namespaces
+	for _, res := range fakeResources {
+		if res.group == group && res.version == version && res.resource == rest[0] && res.namespaced && name == "" {
+			return res, "", "", true
+		}
+	}
+	return fakeResource{}, "", "", false
+}
+
+func objectKey(res fakeResource, namespace, name string) string {
+	return res.group + "/" + res.resource + "/" + namespace + "/" + name
+}
+
+// matchesLabels supports the equality based label selectors used by the CLI, such as "name=kyma-installer"
+func matchesLabels(obj map[string]interface{}, selector string) bool {
+	if selector == "" {
+		return true
+	}
+	metadata, _ := obj["metadata"].(map[string]interface{})
+	labels, _ := metadata["labels"].(map[string]interface{})
+	for _, requirement := range strings.Split(selector, ",") {
+		kv := strings.SplitN(requirement, "=", 2)
+		if len(kv) != 2 || fmt.Sprint(labels[kv[0]]) != kv[1] {
+			return false
+		}
+	}
+	return true
+}
+
+// mergeObjects returns a copy of the object with the patch merged into it, following the JSON merge patch semantics
+func mergeObjects(obj, patch map[string]interface{}) map[string]interface{} {
+	result := map[string]interface{}{}
+	for k, v := range obj {
+		result[k] = v
+	}
+	for k, v := range patch {
+		if v == nil {
+			delete(result, k)
+			continue
+		}
+		nestedPatch, patchIsMap := v.(map[string]interface{})
+		nestedObj, objIsMap := result[k].(map[string]interface{})
+		switch {
+		case patchIsMap && objIsMap:
+			result[k] = mergeObjects(nestedObj, nestedPatch)
+		case patchIsMap:
+			result[k] = mergeObjects(map[string]interface{}{}, nestedPatch)
+		default:
+			result[k] = v
+		}
+	}
+	return result
+}
+
+func stringAt(obj map[string]interface{}, fields ...string) string {
+	var current interface{} = obj
+	for _, f := range fields {
+		m, ok := current.(map[string]interface{})
+		if !ok {
+			return ""
+		}
+		current = m[f]
+	}
+	s, _ := current.(string)
+	return s
+}
+
+func writeStatus(w http.ResponseWriter, code int, reason metav1.StatusReason, message string) {
+	status := &metav1.Status{
+		TypeMeta: metav1.TypeMeta{Kind: "Status", APIVersion: "v1"},
+		Status:   metav1.StatusSuccess,
+		Code:     int32(code),
+		Reason:   reason,
+		Message:  message,
+	}
+	if code >= http.StatusBadRequest {
+		status.Status = metav1.StatusFailure
+	}
+	writeJSON(w, code, status)
+}
+
+func writeJSON(w http.ResponseWriter, code int, obj interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(obj)
+}
@@ -0,0 +1,16 @@
+package helm
+
+import (
+	"github.com/spf13/cobra"
+)
+
+//NewCmd creates a new helm command
+func NewCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "helm",
+		Short: "Configures the helm client for the Kyma cluster.",
+		Long: `Use this command to configure the helm client, such as its certificates for the Tiller of the Kyma cluster, on the local machine.
+`,
+	}
+	return cmd
+}
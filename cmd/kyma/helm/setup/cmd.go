@@ -0,0 +1,214 @@
+package setup
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/kyma-project/cli/internal/cli"
+	"github.com/kyma-project/cli/internal/kube"
+	"github.com/kyma-project/cli/internal/metadata"
+	"github.com/kyma-project/cli/internal/version"
+	pkgErrors "github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"k8s.io/client-go/kubernetes"
+)
+
+// helmSecret is the Secret in the namespace of the Kyma Installer with the certificates of Tiller and of the helm client
+const helmSecret = "helm-secret"
+
+// clientFile is a file of the helm home with the PEM encoded data of a key of the helm Secret
+type clientFile struct {
+	name string
+	key  string
+	perm os.FileMode
+	data []byte
+}
+
+// clientFiles are the files helm 2 reads from the helm home with --tls
+func clientFiles() []clientFile {
+	return []clientFile{
+		{name: "ca.pem", key: "global.helm.ca.crt", perm: 0644},
+		{name: "cert.pem", key: "global.helm.tls.crt", perm: 0644},
+		{name: "key.pem", key: "global.helm.tls.key", perm: 0600},
+	}
+}
+
+type command struct {
+	opts *Options
+	cli.Command
+}
+
+//NewCmd creates a new helm setup command
+func NewCmd(o *Options) *cobra.Command {
+	cmd := command{
+		Command: cli.Command{Options: o.Options},
+		opts:    o,
+	}
+
+	cobraCmd := &cobra.Command{
+		Use:   "setup",
+		Short: "Writes the helm client certificates for the Tiller of the Kyma cluster to the helm home.",
+		Long: `Use this command to configure the helm 2 client for Kyma releases which secure Tiller with TLS. Without the client certificates, helm commands fail with "transport is closing".
+
+The certificates are read from the "helm-secret" Secret, which the Kyma Installer creates in the kyma-installer namespace, and written to ca.pem, cert.pem, and key.pem in the helm home.
+Files which already have the content of the cluster are not written again, so the command can be run after every installation or upgrade.
+Afterwards, the connection to Tiller is verified with "helm version --tls" if helm is installed.
+
+Kyma 1.16 and newer releases are installed with Helm 3 and run no Tiller, so there is nothing to set up for them.
+`,
+		Example: `  # Write the certificates to the helm home and verify the connection to Tiller
+  kyma helm setup
+
+  # Write the certificates to another directory
+  kyma helm setup --helm-home /tmp/helm`,
+		RunE: func(_ *cobra.Command, _ []string) error { return cmd.Run() },
+	}
+
+	cobraCmd.Flags().StringVar(&o.HelmHome, "helm-home", "", `Directory the helm client certificates are written to. If not set, the HELM_HOME environment variable or "$HOME/.helm" is used.`)
+	cli.MarkPathFlags(cobraCmd, "helm-home")
+	return cobraCmd
+}
+
+//Run runs the command
+func (cmd *command) Run() error {
+	if cmd.opts.CI {
+		cmd.Factory.NonInteractive = true
+	}
+
+	var err error
+	if cmd.K8s, err = kube.NewFromConfig("", cmd.KubeconfigPath); err != nil {
+		return pkgErrors.Wrap(err, "Could not initialize the Kubernetes client. Make sure your kubeconfig is valid")
+	}
+
+	s := cmd.NewStep("Checking the Kyma version")
+	v, err := version.ClusterKymaVersion(cmd.K8s)
+	if err != nil {
+		s.Failure()
+		return err
+	}
+	if v.State == version.NotInstalled {
+		s.Failure()
+		return errors.New("Kyma is not installed on the cluster")
+	}
+	m := metadata.For(v.Version)
+	if !hasTiller(m) {
+		s.Successf("Kyma %s is installed with Helm 3 and runs no Tiller, the helm client needs no certificates", v.Version)
+		return nil
+	}
+	s.Successf("Kyma %s secures Tiller with TLS", v.Version)
+
+	home, err := helmHome(cmd.opts.HelmHome)
+	if err != nil {
+		return err
+	}
+
+	s = cmd.NewStep("Reading the helm client certificates")
+	files, err := readClientCerts(cmd.K8s.Static(), m)
+	if err != nil {
+		s.Failure()
+		return err
+	}
+	s.Success()
+
+	s = cmd.NewStep(fmt.Sprintf("Writing the helm client certificates to %s", home))
+	written, err := writeClientCerts(home, files)
+	if err != nil {
+		s.Failure()
+		return err
+	}
+	if len(written) == 0 {
+		s.Successf("Helm client certificates in %s are up to date", home)
+	} else {
+		s.Successf("Helm client certificates written to %s", strings.Join(written, ", "))
+	}
+
+	return cmd.verify(home, m)
+}
+
+// verify checks with "helm version --tls" that the helm client connects to Tiller with the certificates of the helm home
+func (cmd *command) verify(home string, m metadata.Metadata) error {
+	args := []string{"version", "--tls", "--home", home, "--tiller-namespace", m.TillerNamespace}
+	s := cmd.NewStep("Verifying the connection to Tiller")
+	if _, err := exec.LookPath("helm"); err != nil {
+		s.Successf("Helm is not installed, run 'helm %s' to verify the connection to Tiller", strings.Join(args, " "))
+		return nil
+	}
+	if cmd.KubeconfigPath != "" {
+		args = append(args, "--kubeconfig", cmd.KubeconfigPath)
+	}
+	out, err := cli.RunCmdWithTimeout(time.Minute, "helm", args...)
+	if err != nil {
+		s.Failure()
+		return pkgErrors.Wrap(err, "helm could not connect to Tiller with the certificates. Make sure a helm 2 client is installed")
+	}
+	s.Success()
+	fmt.Fprintln(cmd.Stdout(), out)
+	return nil
+}
+
+// hasTiller checks if the Kyma release of the metadata installs its components with Helm 2
+func hasTiller(m metadata.Metadata) bool {
+	return m.TillerNamespace != ""
+}
+
+// helmHome returns the directory the helm client certificates are written to: the given one, $HELM_HOME, or ~/.helm like the helm 2 client
+func helmHome(dir string) (string, error) {
+	if dir == "" {
+		dir = os.Getenv("HELM_HOME")
+	}
+	if dir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", pkgErrors.Wrap(err, "Could not determine the helm home, set it with --helm-home")
+		}
+		dir = filepath.Join(home, ".helm")
+	}
+	return filepath.Abs(dir)
+}
+
+// readClientCerts reads the certificates of the helm client from the helm Secret in the namespace of the Kyma Installer
+func readClientCerts(k8s kubernetes.Interface, m metadata.Metadata) ([]clientFile, error) {
+	files := clientFiles()
+	for i, f := range files {
+		data, err := kube.GetResourceField(k8s, "secret", helmSecret, m.InstallerNamespace, fmt.Sprintf("{.data.%s}", kube.JSONPathKey(f.key)), true)
+		if err == nil && data == "" {
+			err = fmt.Errorf("the key '%s' is empty", f.key)
+		}
+		if err != nil {
+			return nil, pkgErrors.Wrapf(err, "Could not read the helm client certificates from the Secret %s/%s", m.InstallerNamespace, helmSecret)
+		}
+		files[i].data = []byte(data)
+	}
+	return files, nil
+}
+
+// writeClientCerts writes the certificate files to the directory and returns the paths of the written ones.
+// Files which already have the same content are not written again.
+func writeClientCerts(dir string, files []clientFile) ([]string, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, pkgErrors.Wrap(err, "unable to create the helm home")
+	}
+	var written []string
+	for _, f := range files {
+		path := filepath.Join(dir, f.name)
+		if current, err := ioutil.ReadFile(path); err == nil && bytes.Equal(current, f.data) {
+			continue
+		}
+		if err := ioutil.WriteFile(path, f.data, f.perm); err != nil {
+			return written, pkgErrors.Wrapf(err, "unable to write the helm client certificate %s", path)
+		}
+		// WriteFile keeps the permissions of existing files
+		if err := os.Chmod(path, f.perm); err != nil {
+			return written, pkgErrors.Wrapf(err, "unable to restrict the permissions of %s", path)
+		}
+		written = append(written, path)
+	}
+	return written, nil
+}
@@ -0,0 +1,99 @@
+package setup
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/kyma-project/cli/internal/cli"
+	"github.com/kyma-project/cli/internal/metadata"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+// TestHelmSetupFlags ensures that the provided command flags are stored in the options.
+func TestHelmSetupFlags(t *testing.T) {
+	t.Parallel()
+	o := NewOptions(&cli.Options{})
+	c := NewCmd(o)
+
+	// test default flag values
+	require.Equal(t, "", o.HelmHome, "Default value for the helm-home flag not as expected.")
+
+	// test passing flags
+	err := c.ParseFlags([]string{"--helm-home", "/tmp/helm"})
+	require.NoError(t, err, "Parsing flags should not return an error")
+	require.Equal(t, "/tmp/helm", o.HelmHome, "The parsed value for the helm-home flag not as expected.")
+}
+
+func TestHasTiller(t *testing.T) {
+	t.Parallel()
+	require.True(t, hasTiller(metadata.For("1.15.1")), "Kyma 1.15 runs Tiller")
+	require.False(t, hasTiller(metadata.For("1.16.0")), "Kyma 1.16 runs no Tiller")
+	require.False(t, hasTiller(metadata.For("1.18.0")), "Kyma 1.18 runs no Tiller")
+	require.False(t, hasTiller(metadata.For("master-00e83e99")), "master builds run no Tiller")
+}
+
+func TestReadClientCerts(t *testing.T) {
+	t.Parallel()
+	m := metadata.For("1.15.1")
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: helmSecret, Namespace: m.InstallerNamespace},
+		Data: map[string][]byte{
+			"global.helm.ca.crt":  []byte("ca"),
+			"global.helm.tls.crt": []byte("cert"),
+			"global.helm.tls.key": []byte("key"),
+		},
+	}
+
+	files, err := readClientCerts(fake.NewSimpleClientset(secret), m)
+	require.NoError(t, err)
+	require.Len(t, files, 3)
+	for _, f := range files {
+		require.Equal(t, string(secret.Data[f.key]), string(f.data), f.name)
+	}
+
+	delete(secret.Data, "global.helm.tls.key")
+	_, err = readClientCerts(fake.NewSimpleClientset(secret), m)
+	require.Error(t, err, "all certificates are required")
+
+	_, err = readClientCerts(fake.NewSimpleClientset(), m)
+	require.Error(t, err)
+}
+
+func TestWriteClientCerts(t *testing.T) {
+	t.Parallel()
+	tmp, err := ioutil.TempDir("", "helm-setup-test")
+	require.NoError(t, err)
+	defer os.RemoveAll(tmp)
+	dir := filepath.Join(tmp, ".helm")
+
+	files := clientFiles()
+	for i := range files {
+		files[i].data = []byte(files[i].name)
+	}
+
+	written, err := writeClientCerts(dir, files)
+	require.NoError(t, err)
+	require.Equal(t, []string{filepath.Join(dir, "ca.pem"), filepath.Join(dir, "cert.pem"), filepath.Join(dir, "key.pem")}, written)
+	for _, f := range files {
+		data, err := ioutil.ReadFile(filepath.Join(dir, f.name))
+		require.NoError(t, err)
+		require.Equal(t, f.name, string(data))
+	}
+
+	written, err = writeClientCerts(dir, files)
+	require.NoError(t, err)
+	require.Empty(t, written, "unchanged certificates must not be written again")
+
+	files[2].data = []byte("new key")
+	written, err = writeClientCerts(dir, files)
+	require.NoError(t, err)
+	require.Equal(t, []string{filepath.Join(dir, "key.pem")}, written, "only the changed certificate is written")
+	data, err := ioutil.ReadFile(filepath.Join(dir, "key.pem"))
+	require.NoError(t, err)
+	require.Equal(t, "new key", string(data))
+}
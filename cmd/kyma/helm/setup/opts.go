@@ -0,0 +1,14 @@
+package setup
+
+import "github.com/kyma-project/cli/internal/cli"
+
+//Options defines available options for the helm setup command
+type Options struct {
+	*cli.Options
+	HelmHome string
+}
+
+//NewOptions creates options with default values
+func NewOptions(o *cli.Options) *Options {
+	return &Options{Options: o}
+}
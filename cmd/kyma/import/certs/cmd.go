@@ -0,0 +1,207 @@
+package certs
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/kyma-project/cli/internal/cli"
+	"github.com/kyma-project/cli/internal/kube"
+	"github.com/kyma-project/cli/internal/metadata"
+	"github.com/kyma-project/cli/internal/redact"
+	"github.com/kyma-project/cli/internal/trust"
+	pkgErrors "github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"k8s.io/client-go/kubernetes"
+)
+
+type command struct {
+	opts *Options
+	cli.Command
+}
+
+//NewCmd creates a new import certs command
+func NewCmd(o *Options) *cobra.Command {
+	cmd := command{
+		Command: cli.Command{Options: o.Options},
+		opts:    o,
+	}
+
+	cobraCmd := &cobra.Command{
+		Use:   "certs",
+		Short: "Imports the certificate of the Kyma cluster to the local machine.",
+		Long: `Use this command to write the certificate of the Kyma domain to a file and, optionally, to add it to the trusted root certificates of the OS and of Java.
+Clusters with a self-signed certificate, such as local clusters, are only accessible from browsers and applications which trust their certificate.
+
+The certificate is read from the Secret with the TLS certificate of the Kyma domain in the kyma-system namespace, or from the overrides of the Kyma Installer for older Kyma releases.
+With --trust, the certificate is added to the trusted root certificates of the OS: to the System keychain with "security" on macOS, to the CA certificates directory of the distribution with "update-ca-certificates" or "update-ca-trust" on Linux, and to the Root store with "certutil" on Windows.
+On macOS and Linux, the commands are run with sudo, which asks for your password. On Windows, run the command in a terminal with administrator rights.
+With --java-keystore, the certificate is added to the given Java keystore with "keytool".
+
+Use --remove to remove the certificate from the selected stores and to delete the certificate file, and --dry-run to print the commands instead of running them.
+`,
+		Example: `  # Write the certificate to kyma.crt and trust it on the OS
+  kyma import certs --trust
+
+  # Trust the certificate in the cacerts of the JDK
+  kyma import certs --java-keystore $JAVA_HOME/lib/security/cacerts
+
+  # Print the commands removing the certificate again
+  kyma import certs --remove --trust --dry-run`,
+		RunE: func(_ *cobra.Command, _ []string) error { return cmd.Run() },
+	}
+
+	cobraCmd.Flags().StringVarP(&o.CertFile, "cert-file", "f", "kyma.crt", "Path of the file the PEM encoded certificate is written to.")
+	cobraCmd.Flags().BoolVar(&o.Trust, "trust", false, "Adds the certificate to the trusted root certificates of the OS, or removes it with --remove.")
+	cobraCmd.Flags().StringVar(&o.JavaKeystore, "java-keystore", "", "Java keystore the certificate is added to, or removed from with --remove (for example, $JAVA_HOME/lib/security/cacerts).")
+	cobraCmd.Flags().StringVar(&o.JavaKeystorePassword, "java-keystore-password", "changeit", "Password of the Java keystore.")
+	cobraCmd.Flags().BoolVar(&o.Remove, "remove", false, "Removes the certificate from the stores selected with --trust and --java-keystore, and deletes the certificate file.")
+	cobraCmd.Flags().BoolVar(&o.DryRun, "dry-run", false, "Prints the commands changing the trusted certificates instead of running them, and does not write or delete the certificate file.")
+	cli.MarkPathFlags(cobraCmd, "cert-file", "java-keystore")
+	return cobraCmd
+}
+
+//Run runs the command
+func (cmd *command) Run() error {
+	if cmd.opts.CI {
+		cmd.Factory.NonInteractive = true
+	}
+	// the password is part of the keytool commands, which are logged and displayed
+	redact.Add(cmd.opts.JavaKeystorePassword)
+
+	if cmd.opts.Remove {
+		return cmd.remove()
+	}
+
+	data, err := cmd.clusterCertificate()
+	if err != nil {
+		return err
+	}
+	cert, err := trust.ParseCertificate(data)
+	if err != nil {
+		return pkgErrors.Wrap(err, "the Kyma certificate is invalid")
+	}
+
+	file, err := filepath.Abs(cmd.opts.CertFile)
+	if err != nil {
+		return err
+	}
+	if cmd.opts.DryRun {
+		fmt.Fprintf(cmd.Stdout(), "Would write the certificate '%s' to %s\n", cert.Subject.CommonName, file)
+	} else {
+		s := cmd.NewStep(fmt.Sprintf("Writing the certificate to %s", file))
+		if err := ioutil.WriteFile(file, data, 0644); err != nil {
+			s.Failure()
+			return pkgErrors.Wrap(err, "unable to write the certificate file")
+		}
+		s.Successf("Certificate '%s' written to %s", cert.Subject.CommonName, file)
+	}
+
+	var cmds []trust.Command
+	if cmd.opts.Trust {
+		c, err := trust.ImportCommands(file, cert)
+		if err != nil {
+			return err
+		}
+		cmds = append(cmds, c...)
+	}
+	if cmd.opts.JavaKeystore != "" {
+		cmds = append(cmds, trust.JavaImportCommands(file, cmd.opts.JavaKeystore, cmd.opts.JavaKeystorePassword, cert)...)
+	}
+	return cmd.execute(cmds)
+}
+
+// remove removes the certificate of the certificate file from the selected stores and deletes the file.
+// If the file does not exist any more, the certificate is read from the cluster.
+func (cmd *command) remove() error {
+	file, err := filepath.Abs(cmd.opts.CertFile)
+	if err != nil {
+		return err
+	}
+	data, err := ioutil.ReadFile(file)
+	exists := err == nil
+	if errors.Is(err, os.ErrNotExist) {
+		data, err = cmd.clusterCertificate()
+	}
+	if err != nil {
+		return err
+	}
+	cert, err := trust.ParseCertificate(data)
+	if err != nil {
+		return pkgErrors.Wrap(err, "the certificate to remove is invalid")
+	}
+
+	var cmds []trust.Command
+	if cmd.opts.Trust {
+		c, err := trust.RemoveCommands(cert)
+		if err != nil {
+			return err
+		}
+		cmds = append(cmds, c...)
+	}
+	if cmd.opts.JavaKeystore != "" {
+		cmds = append(cmds, trust.JavaRemoveCommands(cmd.opts.JavaKeystore, cmd.opts.JavaKeystorePassword, cert)...)
+	}
+	if err := cmd.execute(cmds); err != nil {
+		return err
+	}
+
+	switch {
+	case !exists:
+		return nil
+	case cmd.opts.DryRun:
+		fmt.Fprintf(cmd.Stdout(), "Would delete the certificate file %s\n", file)
+	default:
+		if err := os.Remove(file); err != nil {
+			return pkgErrors.Wrap(err, "unable to delete the certificate file")
+		}
+	}
+	return nil
+}
+
+// execute runs the commands changing the trusted certificates, or prints them with --dry-run
+func (cmd *command) execute(cmds []trust.Command) error {
+	for _, c := range cmds {
+		if cmd.opts.DryRun {
+			fmt.Fprintln(cmd.Stdout(), redact.String(c.String()))
+			continue
+		}
+		s := cmd.NewStep(fmt.Sprintf("Running %s", redact.String(c.String())))
+		if err := c.Run(); err != nil {
+			s.Failure()
+			return err
+		}
+		s.Success()
+	}
+	return nil
+}
+
+// clusterCertificate reads the PEM encoded certificate of the Kyma domain from the cluster
+func (cmd *command) clusterCertificate() ([]byte, error) {
+	var err error
+	if cmd.K8s, err = kube.NewFromConfig("", cmd.KubeconfigPath); err != nil {
+		return nil, pkgErrors.Wrap(err, "Could not initialize the Kubernetes client. Make sure your kubeconfig is valid")
+	}
+	s := cmd.NewStep("Reading the Kyma certificate")
+	data, err := readCertificate(cmd.K8s.Static(), metadata.Latest())
+	if err != nil {
+		s.Failure()
+		return nil, err
+	}
+	s.Success()
+	return data, nil
+}
+
+// readCertificate reads the certificate from the Secret of the metadata, or from the overrides of the Kyma Installer if the Secret does not exist
+func readCertificate(k8s kubernetes.Interface, m metadata.Metadata) ([]byte, error) {
+	cert, err := kube.GetResourceField(k8s, "secret", m.CertificateSecret, m.CertificateSecretNamespace, fmt.Sprintf("{.data.%s}", kube.JSONPathKey(m.CertificateSecretKey)), true)
+	if errors.Is(err, kube.ErrResourceNotFound) {
+		cert, err = kube.GetResourceField(k8s, "configmap", "net-global-overrides", m.InstallerNamespace, `{.data.global\.ingress\.tlsCrt}`, true)
+	}
+	if err != nil {
+		return nil, pkgErrors.Wrap(err, "Could not read the Kyma certificate")
+	}
+	return []byte(cert), nil
+}
@@ -0,0 +1,68 @@
+package certs
+
+import (
+	"testing"
+
+	"github.com/kyma-project/cli/internal/cli"
+	"github.com/kyma-project/cli/internal/metadata"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+// TestImportCertsFlags ensures that the provided command flags are stored in the options.
+func TestImportCertsFlags(t *testing.T) {
+	t.Parallel()
+	o := NewOptions(&cli.Options{})
+	c := NewCmd(o)
+
+	// test default flag values
+	require.Equal(t, "kyma.crt", o.CertFile, "Default value for the cert-file flag not as expected.")
+	require.False(t, o.Trust, "Default value for the trust flag not as expected.")
+	require.Equal(t, "", o.JavaKeystore, "Default value for the java-keystore flag not as expected.")
+	require.Equal(t, "changeit", o.JavaKeystorePassword, "Default value for the java-keystore-password flag not as expected.")
+	require.False(t, o.Remove, "Default value for the remove flag not as expected.")
+	require.False(t, o.DryRun, "Default value for the dry-run flag not as expected.")
+
+	// test passing flags
+	err := c.ParseFlags([]string{
+		"-f", "/tmp/local.crt",
+		"--trust",
+		"--java-keystore", "/opt/java/lib/security/cacerts",
+		"--java-keystore-password", "secret",
+		"--remove",
+		"--dry-run",
+	})
+	require.NoError(t, err, "Parsing flags should not return an error")
+	require.Equal(t, "/tmp/local.crt", o.CertFile, "The parsed value for the cert-file flag not as expected.")
+	require.True(t, o.Trust, "The parsed value for the trust flag not as expected.")
+	require.Equal(t, "/opt/java/lib/security/cacerts", o.JavaKeystore, "The parsed value for the java-keystore flag not as expected.")
+	require.Equal(t, "secret", o.JavaKeystorePassword, "The parsed value for the java-keystore-password flag not as expected.")
+	require.True(t, o.Remove, "The parsed value for the remove flag not as expected.")
+	require.True(t, o.DryRun, "The parsed value for the dry-run flag not as expected.")
+}
+
+func TestReadCertificate(t *testing.T) {
+	t.Parallel()
+	m := metadata.Latest()
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: m.CertificateSecret, Namespace: m.CertificateSecretNamespace},
+		Data:       map[string][]byte{m.CertificateSecretKey: []byte("secret cert")},
+	}
+	overrides := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "net-global-overrides", Namespace: m.InstallerNamespace},
+		Data:       map[string]string{"global.ingress.tlsCrt": "b3ZlcnJpZGVzIGNlcnQ="},
+	}
+
+	cert, err := readCertificate(fake.NewSimpleClientset(secret, overrides), m)
+	require.NoError(t, err)
+	require.Equal(t, "secret cert", string(cert))
+
+	cert, err = readCertificate(fake.NewSimpleClientset(overrides), m)
+	require.NoError(t, err)
+	require.Equal(t, "overrides cert", string(cert), "the overrides are read if the Secret does not exist")
+
+	_, err = readCertificate(fake.NewSimpleClientset(), m)
+	require.Error(t, err)
+}
@@ -0,0 +1,19 @@
+package certs
+
+import "github.com/kyma-project/cli/internal/cli"
+
+//Options defines available options for the import certs command
+type Options struct {
+	*cli.Options
+	CertFile             string
+	Trust                bool
+	JavaKeystore         string
+	JavaKeystorePassword string
+	Remove               bool
+	DryRun               bool
+}
+
+//NewOptions creates options with default values
+func NewOptions(o *cli.Options) *Options {
+	return &Options{Options: o}
+}
@@ -0,0 +1,16 @@
+package imports
+
+import (
+	"github.com/spf13/cobra"
+)
+
+//NewCmd creates a new import command
+func NewCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "import",
+		Short: "Imports resources of the Kyma cluster to the local machine.",
+		Long: `Use this command to import resources of the Kyma cluster, such as its certificate, to the local machine.
+`,
+	}
+	return cmd
+}
@@ -51,6 +51,7 @@ Use the flags to specify the initial configuration for your Function or to choos
 	cmd.Flags().Var(&o.RepositoryName, "repository-name", `The name of the Git repository to be created`)
 	cmd.Flags().StringVar(&o.Reference, "reference", defaultReference, `Commit hash or branch name`)
 	cmd.Flags().StringVar(&o.BaseDir, "base-dir", defaultBaseDir, `A directory in the repository containing the Function's sources`)
+	cli.MarkPathFlags(cmd, "dir")
 
 	return cmd
 }
@@ -0,0 +1,67 @@
+package cleanup
+
+import (
+	"github.com/kyma-project/cli/internal/cli"
+	"github.com/kyma-project/cli/internal/kube"
+	"github.com/kyma-project/cli/pkg/installation"
+	pkgErrors "github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+type command struct {
+	opts *Options
+	cli.Command
+}
+
+//NewCmd creates a new install cleanup command
+func NewCmd(o *Options) *cobra.Command {
+	cmd := command{
+		Command: cli.Command{Options: o.Options},
+		opts:    o,
+	}
+
+	cobraCmd := &cobra.Command{
+		Use:   "cleanup",
+		Short: "Deletes the resources created by a failed Kyma installation.",
+		Long: `Use this command to delete the resources which a failed "kyma install" created, such as the Kyma Installer, its namespace, the CRDs and the Installation CR, so that they do not interfere with the next installation.
+The resources are deleted in the reverse order of their creation. Resources which existed before the installation are kept.
+The same cleanup runs automatically with "kyma install --cleanup-on-failure".
+`,
+		RunE: func(_ *cobra.Command, _ []string) error { return cmd.Run() },
+	}
+
+	cobraCmd.Flags().StringVar(&o.InstallationName, "installation-name", "", "Name of the Kyma Installation CR. If not set, it is discovered from the cluster.")
+	cobraCmd.Flags().BoolVar(&o.Force, "force", false, "Deletes the resources even if the Kyma Installer reports Kyma as installed.")
+	return cobraCmd
+}
+
+//Run runs the command
+func (cmd *command) Run() error {
+	if cmd.opts.CI {
+		cmd.Factory.NonInteractive = true
+	}
+
+	var err error
+	if cmd.K8s, err = kube.NewFromConfig("", cmd.KubeconfigPath); err != nil {
+		return pkgErrors.Wrap(err, "Could not initialize the Kubernetes client. Make sure your kubeconfig is valid")
+	}
+	s, err := installation.NewInstallationService(cmd.K8s.RestConfig(), 0, "", nil)
+	if err != nil {
+		return pkgErrors.Wrap(err, "Failed to create installation service. Make sure your kubeconfig is valid")
+	}
+
+	i := &installation.Installation{
+		K8s:     cmd.K8s,
+		Service: s,
+		Options: &installation.Options{
+			Verbose:          cmd.opts.Verbose,
+			CI:               cmd.opts.CI,
+			NonInteractive:   cmd.Factory.NonInteractive,
+			InstallationName: cmd.opts.InstallationName,
+			Force:            cmd.opts.Force,
+		},
+		Factory: cmd.Factory,
+	}
+	_, err = i.CleanupInstallation()
+	return err
+}
@@ -0,0 +1,15 @@
+package cleanup
+
+import "github.com/kyma-project/cli/internal/cli"
+
+//Options defines available options for the install cleanup command
+type Options struct {
+	*cli.Options
+	InstallationName string
+	Force            bool
+}
+
+//NewOptions creates options with default values
+func NewOptions(o *cli.Options) *Options {
+	return &Options{Options: o}
+}
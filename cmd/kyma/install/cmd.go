@@ -6,26 +6,34 @@ import (
 	"os"
 	"time"
 
+	"github.com/kyma-project/cli/internal/coredns"
 	"github.com/kyma-project/cli/internal/hosts"
 	"github.com/kyma-project/cli/internal/kube"
-	"github.com/kyma-project/cli/internal/nice"
+	"github.com/kyma-project/cli/internal/releases"
 	"github.com/kyma-project/cli/internal/trust"
 
 	"github.com/kyma-project/cli/internal/cli"
 
+	kymaVersion "github.com/kyma-project/cli/internal/version"
 	"github.com/kyma-project/cli/pkg/installation"
+	"github.com/kyma-project/cli/pkg/installation/summary"
 	"github.com/pkg/errors"
 
 	"github.com/spf13/cobra"
+	apiErrors "k8s.io/apimachinery/pkg/api/errors"
 )
 
 const (
 	defaultDomain = "kyma.local"
+	// exitCodeUnreadyWorkloads is returned with --strict if workloads are not ready after the installation
+	exitCodeUnreadyWorkloads = 3
 )
 
 type command struct {
 	opts *Options
 	cli.Command
+	// stage is the installation stage run by a subcommand, all stages run if it is empty
+	stage string
 }
 
 //NewCmd creates a new kyma command
@@ -74,30 +82,135 @@ The standard installation uses the minimal configuration. The system performs th
     
 2. Runs Kyma installation until the ` + "**installed**" + ` status confirms the successful installation. You can override the standard installation settings using the ` + "`--override`" + ` flag.
 
+To run the two steps separately, for example in scripts, use ` + "`kyma install installer`" + ` and then ` + "`kyma install components`" + ` with the same flags.
+
 `,
+		Example: `  # Install the default Kyma release on the cluster of the current kubeconfig context
+  kyma install
+
+  # Install a specific release with a custom domain and its TLS certificate
+  kyma install --source=1.15.1 --domain=kyma.example.com --tls-cert="$(base64 < tls.crt)" --tls-key="$(base64 < tls.key)"
+
+  # Install the newest release of a release channel with a profile
+  kyma install --release=stable --profile=evaluation
+
+  # Install from the local sources on a remote cluster
+  kyma install --source=local --src-path=~/kyma --custom-image=eu.gcr.io/my-project/kyma-installer:dev
+
+  # Review the manifests without applying anything
+  kyma install --dry-run --export-manifests=./manifests`,
+		PreRunE: func(c *cobra.Command, _ []string) error { return cmd.validateFlags(c.Flags()) },
 		RunE:    func(_ *cobra.Command, _ []string) error { return cmd.Run() },
 		Aliases: []string{"i"},
 	}
 
-	cobraCmd.Flags().BoolVarP(&o.NoWait, "no-wait", "n", false, "Determines if the command should wait for Kyma installation to complete.")
+	cli.AddWaitFlag(cobraCmd, &o.NoWait, "Waits for the Kyma installation to complete. Use --wait=false to return once the installation is triggered.")
+	cobraCmd.Flags().BoolVar(&o.SkipActivationCheck, "skip-activation-check", false, "Skips the check that the Kyma Installer picks up the Installation CR within a minute after it is activated. The check is also done with --wait=false, so that a crash-looping Kyma Installer is noticed. If the Installation CR is not picked up, the state and the last log lines of the Kyma Installer pod are displayed.")
+	cobraCmd.Flags().BoolVar(&o.ExternalInstaller, "external-installer", false, `Uses the Kyma Installer and the Installation CR deployed outside of the CLI, for example with GitOps, instead of deploying them. The CLI verifies that both exist, applies the overrides of --config, --override, --password and --domain, activates the Installation CR, and waits for the installation as usual. The flags which change the deployed Kyma Installer, such as --source or --components, cannot be used with it.`)
 	cobraCmd.Flags().StringVarP(&o.Domain, "domain", "d", defaultDomain, "Domain used for installation.")
+	cobraCmd.Flags().BoolVar(&o.UseNipIO, "use-nip-io", false, "Uses the wildcard domain \"<ip>.nip.io\" of the load balancer IP of the Istio ingress gateway, for remote clusters without a DNS entry. The domain is determined as soon as the gateway is installed, and the components installed afterwards use it.")
 	cobraCmd.Flags().StringVarP(&o.TLSCert, "tls-cert", "", "", "TLS certificate for the domain used for installation. The certificate must be a base64-encoded value.")
 	cobraCmd.Flags().StringVarP(&o.TLSKey, "tls-key", "", "", "TLS key for the domain used for installation. The key must be a base64-encoded value.")
-	cobraCmd.Flags().StringVarP(&o.Source, "source", "s", DefaultKymaVersion, `Installation source. 
+	cobraCmd.Flags().StringVarP(&o.Source, "source", "s", kymaVersion.DefaultKymaVersion, `Installation source. 
 	- To use a specific release, write "kyma install --source=1.15.1".
+	- To use the newest stable release, write "kyma install --source=latest".
 	- To use the master branch, write "kyma install --source=master".
 	- To use a commit, write "kyma install --source=34edf09a".
 	- To use a pull request, write "kyma install --source=PR-9486".
 	- To use the local sources, write "kyma install --source=local".
 	- To use a custom installer image, write "kyma install --source=user/my-kyma-installer:v1.4.0".`)
-	cobraCmd.Flags().StringVarP(&o.LocalSrcPath, "src-path", "", "", "Absolute path to local sources.")
-	cobraCmd.Flags().DurationVarP(&o.Timeout, "timeout", "", 1*time.Hour, "Timeout after which CLI stops watching the installation progress.")
+	_ = cobraCmd.RegisterFlagCompletionFunc("source", releases.CompleteVersions)
+	cobraCmd.Flags().StringVar(&o.Release, "release", "", `Release channel which is resolved to the Kyma version to install, instead of --source: "stable" (newest generally available release), "canary" (newest release including release candidates), or "nightly" (newest master build). The resolved version is displayed and cached for ten minutes. To use a channel offline, pin its version with the KYMACTL_RELEASE_<CHANNEL> environment variable, e.g. KYMACTL_RELEASE_STABLE=1.16.0.`)
+	_ = cobraCmd.RegisterFlagCompletionFunc("release", func(_ *cobra.Command, _ []string, _ string) ([]string, cobra.ShellCompDirective) {
+		return releases.ChannelNames(), cobra.ShellCompDirectiveNoFileComp
+	})
+	cobraCmd.Flags().StringVarP(&o.LocalSrcPath, "src-path", "", "", "Path to local sources, either a directory or a .tar.gz, .tgz or .zip archive of the Kyma repository. If not set, the sources are looked up in $GOPATH/src/github.com/kyma-project/kyma, ~/.kyma/sources/kyma, and the current directory.")
+	cobraCmd.Flags().BoolVar(&o.KeepSources, "keep-sources", false, "Keeps the directory to which the archive of the local sources is extracted.")
+	cobraCmd.Flags().BoolVar(&o.PruneDocker, "prune-docker", false, "Removes the images not used by any container and the stopped containers from the Docker daemon of Minikube before the Kyma Installer image is built from local sources. The images of running pods are always kept.")
+	cobraCmd.Flags().DurationVarP(&o.Timeout, "timeout", "", 1*time.Hour, "Timeout after which CLI stops watching the installation progress. Use 0 to watch it without a timeout.")
+	cobraCmd.Flags().DurationVar(&o.RequestTimeout, "request-timeout", 30*time.Second, "Timeout of a single request to the Kubernetes API server. Checks of the installation state failing because the cluster is unreachable are retried 5 times.")
+	cobraCmd.Flags().IntVar(&o.Retries, "retries", 0, "Number of times a failed installation is triggered again by labeling the Installation CR, e.g. if a component fails because a webhook is not ready yet. The errors of each attempt are displayed before the retry.")
+	cobraCmd.Flags().DurationVar(&o.DockerTimeout, "docker-timeout", 10*time.Minute, "Maximum time of the Docker build of the Kyma Installer image if --src-path is used. While the image is built, the elapsed time is displayed every minute.")
+	cobraCmd.Flags().DurationVar(&o.ApplyTimeout, "apply-timeout", 5*time.Minute, "Time-out for each download of a release artifact and for applying the Kyma Installer to the cluster. The download progress is displayed on the step. Increase it on slow connections.")
 	cobraCmd.Flags().StringVarP(&o.Password, "password", "p", "", "Predefined cluster password.")
 	cobraCmd.Flags().StringArrayVarP(&o.OverrideConfigs, "override", "o", nil, "Path to a YAML file with parameters to override.")
-	cobraCmd.Flags().StringVarP(&o.ComponentsConfig, "components", "c", "", "Path to a YAML file with a component list to override.")
+	cobraCmd.Flags().StringSliceVar(&o.Configs, "config", nil, "Path or URL of a YAML file with installer overrides (ConfigMaps and Secrets) and an optional Installation CR. The flag can be repeated or take a comma-separated list, and the files are merged in the given order: the values of later files override the values of earlier ones, key by key. Different Installation CRs in several files are an error.")
+	cobraCmd.Flags().StringVar(&o.ReleaseArtifacts, "release-artifacts", "", "URL or local directory to read the artifacts of the Kyma release from, instead of the Kyma artifact buckets, for example a mirror or a copy for air-gapped installations.")
+	cobraCmd.Flags().StringVar(&o.InstallerManifest, "installer-manifest", "", "Path or URL of the Kyma Installer manifest to apply instead of the one of the Kyma release or the local sources, for example a mirrored or patched copy. It must contain the Kyma Installer Deployment and its ServiceAccount. The source is recorded in an annotation of the Kyma Installer Deployment.")
+	cobraCmd.Flags().BoolVar(&o.UpgradeCRDs, "upgrade-crds", false, "Replaces the CRDs on the cluster which differ from the ones of the Kyma Installer manifest. By default, existing CRDs are not applied again, so that changed schemas do not conflict with them. Replacing a CRD does not migrate the existing custom resources.")
+	cobraCmd.Flags().StringArrayVar(&o.ChartValues, "chart-values", nil, "Helm values file (e.g. istio=path/to/values.yaml) converted into the overrides of the component. The flag can be repeated, later files of the same component take precedence.")
+	cobraCmd.Flags().StringArrayVar(&o.DexUsers, "add-user", nil, `Additional static user of Dex for logging in to the console, in the format "email=user@example.com,password=...,groups=group1,group2". The groups are optional. The flag can be repeated. The users are listed in the summary, without their passwords.`)
+	cobraCmd.Flags().StringVarP(&o.ComponentsConfig, "components", "c", "", "Path to a YAML file with a component list to override. The current names of renamed components (eventing, serverless and api-gateway) are translated to the names of the selected release.")
+	cobraCmd.Flags().StringSliceVar(&o.EnableFeatures, "enable", nil, "Optional features whose components are added to the component list (istio|kiali|logging|monitoring|serverless|service-catalog|tracing). Features required by an enabled feature must be installed as well.")
+	cobraCmd.Flags().StringSliceVar(&o.DisableFeatures, "disable", nil, "Optional features whose components are removed from the component list (istio|kiali|logging|monitoring|serverless|service-catalog|tracing). Features requiring a disabled feature must be disabled as well.")
 	cobraCmd.Flags().IntVar(&o.FallbackLevel, "fallback-level", 5, `If "source=master", defines the number of commits from master branch taken into account if artifacts for newer commits do not exist yet`)
 	cobraCmd.Flags().StringVarP(&o.CustomImage, "custom-image", "", "", "Full image name including the registry and the tag. Required for installation from local sources to a remote cluster.")
-	cobraCmd.Flags().StringVarP(&o.Profile, "profile", "", "", "Kyma installation profile (evaluation|production).")
+	cobraCmd.Flags().StringVarP(&o.Profile, "profile", "", "", "Profile with the components and overrides of the installation (evaluation|full|minimal|production). Use \"kyma install profiles\" to list their contents. Explicit --components and --override files take precedence.")
+	cobraCmd.Flags().StringVarP(&o.InstallationName, "installation-name", "", "", "Name of the Kyma Installation CR. If not set, it is discovered from the cluster or taken from the Installation CR file.")
+	cobraCmd.Flags().StringVar(&o.ExportManifests, "export-manifests", "", "Directory in which a copy of every applied manifest is stored. The CLI creates a timestamped subdirectory with one numbered YAML file per manifest and, for installations from a release, the downloaded release artifacts.")
+	cobraCmd.Flags().BoolVar(&o.RedactSecrets, "redact-secrets", true, "Replaces the values of Secrets exported with --export-manifests.")
+	cobraCmd.Flags().BoolVar(&o.DryRun, "dry-run", false, "Prepares the installation without applying anything to the cluster. Use it together with --export-manifests to review the manifests.")
+	cobraCmd.Flags().BoolVar(&o.GetConfig, "get-config", false, "Prints the configuration the Kyma Installer would get as YAML, without applying anything to the cluster. Each value is annotated with the source which set it, such as the release defaults, an --override file, or a flag. The progress is logged to stderr.")
+	cobraCmd.Flags().BoolVar(&o.FailFast, "fail-fast", true, "Stops the installation at the first failing stage. Set it to false to run all preparation stages and get a report of all errors at the end, for example to debug the environment. Stages depending on a failed stage are skipped, and Kyma is only installed if all stages succeed.")
+	cobraCmd.Flags().BoolVar(&o.FromScratch, "from-scratch", false, "Runs all installation stages, ignoring the stages completed by a previous installation attempt which was interrupted or failed. Without it, the Kyma Installer image is not built again if the local sources did not change.")
+	cobraCmd.Flags().BoolVar(&o.Explain, "explain", false, "Describes the operations of each installation stage before it runs, such as the downloaded files, the resources applied to the cluster, and the Docker build context. In interactive mode, asks whether to continue, skip, or abort each stage. Skipping a stage also skips the stages depending on it.")
+	cobraCmd.Flags().IntVar(&o.StatusPort, "status-port", 0, "Port on localhost on which the installation progress is served while the command runs, for example for CI dashboards. The endpoints are /status (JSON), /metrics (Prometheus text format), and /healthz, which fails if the installation makes no progress for 20 minutes.")
+	cobraCmd.Flags().StringVar(&o.StatusFile, "status-file", "", "Path to a JSON file which is replaced with the installation progress at every check. It holds the same data as the /status endpoint of --status-port.")
+	cobraCmd.Flags().BoolVar(&o.SkipPodVerification, "skip-pod-verification", false, "Skips the check that the Deployments and StatefulSets in kyma-system and kyma-integration are ready after the installation. Without it, the workloads which are not ready within 3 minutes are listed in the summary.")
+	cobraCmd.Flags().BoolVar(&o.WatchEvents, "watch-events", false, "Displays the warning events of the kyma-installer and kyma-system namespaces, such as failed scheduling, image pull back-offs, or webhook denials, while the installation is waited for. Repeated events are displayed once, and at most 5 events per minute.")
+	cobraCmd.Flags().BoolVar(&o.Strict, "strict", false, "Fails the command if workloads in kyma-system or kyma-integration are not ready after the installation. Without it, they are only listed as warnings in the summary.")
+	cobraCmd.Flags().BoolVar(&o.PrePullImages, "pre-pull-images", false, "Pulls the component images into the local cluster while the Kyma Installer initializes. Failed pulls are reported as warnings and do not fail the installation.")
+	cobraCmd.Flags().IntVar(&o.PrePullConcurrency, "pre-pull-concurrency", 4, "Number of images pulled in parallel if --pre-pull-images is set.")
+	cobraCmd.Flags().BoolVar(&o.Force, "force", false, "Installs Kyma versions which are not supported by this Kyma CLI version, and installs on clusters running software which conflicts with Kyma, with a warning instead of an error.")
+	cobraCmd.Flags().StringSliceVar(&o.AllowedConflicts, "allow-conflict", nil, "Installs even though the cluster already runs the given software, which Kyma installs itself (istio, cert-manager, or knative). Conflicts which are not allowed stop the installation. The flag can be repeated or given a comma-separated list.")
+	cobraCmd.Flags().BoolVar(&o.CIDRCheck, "cidr-check", false, "Checks whether the Service and pod CIDRs of the cluster overlap with the routes of the local machine, such as the networks of a corporate VPN, which makes the console unreachable from this machine. Overlaps are reported as warnings and do not fail the installation.")
+	cobraCmd.Flags().BoolVar(&o.ForceUnlock, "force-unlock", false, "Overrides the lock held by another Kyma CLI instance changing the cluster. Use it only if that instance is no longer running.")
+	cobraCmd.Flags().DurationVar(&o.LockTTL, "lock-ttl", installation.DefaultLockTTL, "Time after which the lock held by another Kyma CLI instance is considered stale.")
+	cobraCmd.Flags().StringVarP(&o.ImagePullSecret, "set-image-pull-secret", "", "", "Path to a docker config JSON file with the credentials for a private registry. The CLI creates an image pull secret from it and configures the Kyma Installer to use it.")
+	cobraCmd.Flags().StringVarP(&o.RegistryServer, "registry-server", "", "", "Private registry server used to create the image pull secret. Use it together with --registry-user and --registry-password instead of --set-image-pull-secret.")
+	cobraCmd.Flags().StringVarP(&o.RegistryUser, "registry-user", "", "", "User of the private registry used to create the image pull secret.")
+	cobraCmd.Flags().StringVarP(&o.RegistryPassword, "registry-password", "", "", "Password of the private registry used to create the image pull secret.")
+	cobraCmd.Flags().StringSliceVarP(&o.ImagePullSecretNamespaces, "image-pull-secret-namespace", "", nil, "Additional namespaces (e.g. kyma-system) in which the image pull secret is created. The secret is always created in the kyma-installer namespace.")
+	cobraCmd.Flags().StringToStringVar(&o.NodeSelector, "node-selector", nil, "Node labels (e.g. pool=infra) the Kyma Installer pod is scheduled on. Use it for clusters with dedicated node pools.")
+	cobraCmd.Flags().StringArrayVar(&o.Tolerations, "toleration", nil, "Taint the Kyma Installer pod tolerates, in the format \"key=value:Effect\" or \"key:Effect\" (e.g. dedicated=infra:NoSchedule). The flag can be repeated.")
+	cobraCmd.Flags().StringVar(&o.PriorityClass, "priority-class", "", "Name of the priority class of the Kyma Installer pod.")
+	cobraCmd.Flags().StringVar(&o.InstallerCPU, "installer-cpu", "", "CPU request of the Kyma Installer container, as a Kubernetes quantity (e.g. 200m). Use it if the Kyma Installer pod cannot be scheduled or is evicted on small clusters.")
+	cobraCmd.Flags().StringVar(&o.InstallerMemory, "installer-memory", "", "Memory request of the Kyma Installer container, as a Kubernetes quantity (e.g. 512Mi).")
+	cobraCmd.Flags().StringVar(&o.InstallerCPULimit, "installer-cpu-limit", "", "CPU limit of the Kyma Installer container, as a Kubernetes quantity (e.g. 1).")
+	cobraCmd.Flags().StringVar(&o.InstallerMemoryLimit, "installer-memory-limit", "", "Memory limit of the Kyma Installer container, as a Kubernetes quantity (e.g. 1Gi).")
+	cobraCmd.Flags().StringVar(&o.InstallerLogLevel, "installer-log-level", "", `Log level of the Kyma Installer container, "debug" or "info". Use "debug" to get verbose Kyma Installer logs from the start of the installation, it enables the Helm debug mode of the Kyma Installer (-helmDebugMode). If not set, the setting of the Kyma Installer manifest is used.`)
+	cobraCmd.Flags().StringToStringVar(&o.NodeLabels, "label-nodes", nil, `Label (e.g. local-storage=enabled) added to the nodes before the Kyma Installer is activated, for components which require labeled nodes. The flag can be repeated. Labels the nodes already have are kept. The added labels are removed by "kyma install cleanup" and "kyma alpha delete".`)
+	cobraCmd.Flags().StringArrayVar(&o.NodeTaints, "taint-nodes", nil, `Taint added to the nodes before the Kyma Installer is activated, in the format "key=value:Effect" or "key:Effect" (e.g. dedicated=istio:NoSchedule). The flag can be repeated. The added taints are removed like the labels of --label-nodes.`)
+	cobraCmd.Flags().StringVar(&o.NodeLabelSelector, "node-selector-for-labeling", "", "Label selector (e.g. pool=istio) of the nodes --label-nodes and --taint-nodes are applied to. If not set, all nodes are changed.")
+	cobraCmd.Flags().BoolVar(&o.CleanupOnFailure, "cleanup-on-failure", false, `Deletes the resources created by the installation (such as the Kyma Installer, its namespace, the CRDs and the Installation CR) in the reverse order if the installation fails after they were applied. Resources which existed before the installation are kept. To clean up later, run "kyma install cleanup".`)
+	cobraCmd.Flags().BoolVar(&o.CreatePSP, "create-psp", false, "Creates a PodSecurityPolicy for the Kyma Installer pod and a ClusterRole and ClusterRoleBinding allowing the service account of the Kyma Installer to use it. Use it on clusters with the PodSecurityPolicy admission controller, which reject the Kyma Installer pod if no policy allows it.")
+	cobraCmd.Flags().StringToStringVar(&o.ExtraLabels, "extra-label", nil, "Label (e.g. team=kyma) added to the metadata of all resources the CLI creates, such as the Kyma Installer, its namespace and the Installation CR. The flag can be repeated.")
+	cobraCmd.Flags().StringToStringVar(&o.ExtraAnnotations, "extra-annotation", nil, "Annotation (e.g. owner=kyma-team) added to the metadata of all resources the CLI creates. The flag can be repeated.")
+	cobraCmd.Flags().BoolVar(&o.AnnotateKubeconfig, "annotate-kubeconfig", false, "Logs in with the admin user at Dex once Kyma is installed, and adds the user with a context named \"kyma-<domain>\" to the kubeconfig. The user refreshes its token, so that the context keeps working. The current context is not changed.")
+	cobraCmd.Flags().BoolVar(&o.PatchCoreDNS, "patch-coredns", false, `Patches the CoreDNS configuration of the cluster once Kyma is installed, so that pods resolve the Kyma domain and its subdomains to the Istio ingress gateway. It is always done for local installations with the "kyma.local" domain. The patch is removed by "kyma alpha delete".`)
+	cobraCmd.Flags().StringVar(&o.Output, "output", "", `Format of the summary. Use "summary-markdown" to get a Markdown table of the version, console link, durations, and failed components, for example for comments of pull request bots. The admin password is never part of it. If not set, the summary is displayed as text.`)
+	cobraCmd.Flags().StringVar(&o.SummaryFile, "summary-file", "", "Path to a file to which the summary selected by --output is written, instead of stdout. The text summary is displayed as well.")
+	cobraCmd.Flags().StringVar(&o.KubeconfigDir, "kubeconfig-dir", "", "Path to a directory with one kubeconfig file per cluster. Kyma is installed on each cluster with the other flags of the command, and the results are displayed in one table. The exit code is the highest exit code of the failed installations.")
+	cobraCmd.Flags().StringArrayVar(&o.Contexts, "context", nil, "Kubeconfig context of the cluster to install Kyma on. Repeat the flag to install Kyma on several clusters like with --kubeconfig-dir.")
+	cobraCmd.Flags().IntVar(&o.Parallel, "parallel", 1, "Maximum number of clusters Kyma is installed on at the same time, if --kubeconfig-dir or several --context flags are used.")
+	cobraCmd.Flags().StringVar(&o.LogDir, "log-dir", "", "Path to the directory to which the log file of each cluster is written, if Kyma is installed on several clusters. If not set, a temporary directory is used.")
+	cli.MarkPathFlags(cobraCmd, "src-path", "override", "components", "export-manifests", "status-file", "set-image-pull-secret", "summary-file", "kubeconfig-dir", "log-dir")
+
+	cobraCmd.AddCommand(
+		newStageCmd(cobraCmd, o, stageInstaller, "Deploys the Kyma Installer and triggers the Kyma installation, without waiting for the components.",
+			`Use this command to run the first step of "kyma install" on its own. It runs the same checks of the cluster and the configuration, prepares the installation files, deploys the Kyma Installer with the configuration and the Installation CR, and waits until the Kyma Installer pod is running. It does not wait for the components to be installed.
+
+Afterwards, run "kyma install components" to wait for the components and complete the installation. The command takes the flags of "kyma install".
+`, `  # Deploy the Kyma Installer of a release, and wait for the components in a later step of the script
+  kyma install installer --ci --source=1.15.1
+  kyma install components --ci --source=1.15.1`),
+		newStageCmd(cobraCmd, o, stageComponents, "Waits for the Kyma Installer to install the components and completes the Kyma installation.",
+			`Use this command to run the second step of "kyma install" on its own, after "kyma install installer". It waits until the Kyma Installer has installed all components, verifies the workloads, imports the Kyma certificate, adds the local domains to /etc/hosts, and displays the summary.
+
+The command fails without changing the cluster if no installation was triggered or the Kyma Installer pod is not ready. It takes the flags of "kyma install". The flags selecting the installation files are ignored, because "kyma install installer" applied them already.
+`, `  # Wait for the components of the installation triggered by "kyma install installer", and fail if workloads are not ready
+  kyma install components --ci --strict`),
+	)
 	return cobraCmd
 }
 
@@ -106,18 +219,61 @@ func (cmd *command) Run() error {
 	if cmd.opts.CI {
 		cmd.Factory.NonInteractive = true
 	}
+	if err := cmd.configureStage(); err != nil {
+		return err
+	}
+
+	if cmd.multiCluster() {
+		return cmd.runMultiCluster(os.Args[1:], execInstall, cmd.Stdout())
+	}
+	if len(cmd.opts.Contexts) == 1 {
+		kube.SetContext(cmd.opts.Contexts[0])
+	}
 
 	var err error
-	if cmd.K8s, err = kube.NewFromConfigWithTimeout("", cmd.KubeconfigPath, cmd.opts.Timeout); err != nil {
+	if cmd.K8s, err = kube.NewFromConfigWithTimeout("", cmd.KubeconfigPath, cmd.opts.RequestTimeout); err != nil {
 		return errors.Wrap(err, "Could not initialize the Kubernetes client. Make sure your kubeconfig is valid")
 	}
+	// a stale kubeconfig would otherwise only fail with connection errors in later steps
+	if err := kube.CheckReachable(cmd.K8s); err != nil {
+		return err
+	}
+
+	// keep stdout free for the configuration or the summary, the logger writes to stderr
+	if cmd.opts.GetConfig || (cmd.opts.Output != "" && cmd.opts.SummaryFile == "") {
+		cmd.Factory.UseLogger = true
+	}
+
+	if cmd.opts.Release != "" {
+		if err := cmd.resolveRelease(); err != nil {
+			return err
+		}
+	}
+
+	if cmd.opts.Source == releases.Latest {
+		s := cmd.NewStep("Resolving the latest Kyma release")
+		if cmd.opts.Source, err = releases.ResolveLatest(kymaVersion.DefaultKymaVersion, s.LogErrorf); err != nil {
+			s.Failure()
+			return err
+		}
+		s.Successf("Using Kyma release %s", cmd.opts.Source)
+	}
 
 	s := cmd.NewStep("Determining cluster type for installation")
 	clusterConfig, err := installation.GetClusterInfoFromConfigMap(cmd.K8s)
+	// restricted users are often not permitted to read the kube-system namespace, which must not stop the installation
+	if apiErrors.IsForbidden(err) {
+		s.LogErrorf("Warning: not permitted to read the cluster type from the kube-system namespace, so the cluster is treated as a remote cluster: %s", err)
+		err = nil
+	}
 	if err != nil {
 		s.Failure()
 		return err
 	}
+	clusterConfig.ResolveMinikubeProfile(cmd.opts.MinikubeProfile, cmd.KubeconfigPath, cmd.opts.Verbose, cmd.opts.Timeout)
+	if clusterConfig.Profile != "" {
+		s.LogInfof("Using the Minikube profile '%s'", clusterConfig.Profile)
+	}
 	s.Successf("Cluster type determined")
 
 	i, err := cmd.configureInstallation(clusterConfig)
@@ -125,8 +281,22 @@ func (cmd *command) Run() error {
 		return err
 	}
 
+	if cmd.opts.GetConfig {
+		i.Factory = cmd.Factory
+		cfg, err := i.EffectiveConfiguration()
+		if err != nil {
+			return err
+		}
+		fmt.Fprint(cmd.Stdout(), cfg)
+		return nil
+	}
+
 	result, err := i.InstallKyma()
 	if err != nil {
+		if cmd.opts.Output == outputSummaryMarkdown {
+			// the summary of a failed installation is written as well, the installation error is returned anyway
+			_ = cmd.writeSummary(summary.Markdown(nil, i.StepDurations(), err, i.FailedComponents()))
+		}
 		return err
 	}
 	if result == nil {
@@ -140,9 +310,10 @@ func (cmd *command) Run() error {
 		}
 	}
 
-	if clusterConfig.IsLocal {
+	// the domains are read from the virtual services of the components, so they are only complete once the installation finished
+	if clusterConfig.IsLocal && result.ClusterVersion.State == kymaVersion.Installed {
 		s = cmd.NewStep("Adding domains to /etc/hosts")
-		err = hosts.AddDevDomainsToEtcHosts(s, clusterConfig, cmd.K8s, cmd.opts.Verbose, cmd.opts.Timeout, cmd.opts.Domain)
+		err = hosts.AddDevDomainsToEtcHosts(s, clusterConfig, cmd.K8s, cmd.opts.Verbose, cmd.opts.Timeout, result.Domain)
 		if err != nil {
 			s.Failure()
 			return err
@@ -150,20 +321,77 @@ func (cmd *command) Run() error {
 		s.Successf("Domains added")
 	}
 
+	// pods of local clusters cannot resolve the kyma.local hosts otherwise
+	if (cmd.opts.PatchCoreDNS || (clusterConfig.IsLocal && cmd.opts.Domain == defaultDomain)) && result.ClusterVersion.State == kymaVersion.Installed {
+		s = cmd.NewStep("Patching CoreDNS to resolve the Kyma domain inside the cluster")
+		if changed, err := coredns.Patch(cmd.K8s.Static(), cmd.opts.Domain); err != nil {
+			// like the certificate import, the in-cluster resolution does not mean the installation failed
+			s.Failure()
+			s.LogError(err.Error())
+		} else if changed {
+			s.Successf("CoreDNS resolves '%s' to the Istio ingress gateway", cmd.opts.Domain)
+		} else {
+			s.Successf("CoreDNS already resolves '%s'", cmd.opts.Domain)
+		}
+	}
+
+	var kubeContext string
+	if cmd.opts.AnnotateKubeconfig && result.ClusterVersion.State == kymaVersion.Installed {
+		s = cmd.NewStep("Adding the Kyma admin user to the kubeconfig")
+		if kubeContext, err = cmd.addDexUser(result); err != nil {
+			// like the certificate import, a missing context does not mean the installation failed
+			s.Failure()
+			s.LogError(err.Error())
+		} else {
+			s.Successf("Context '%s' added to the kubeconfig", kubeContext)
+		}
+	}
+
+	if cmd.opts.Output == outputSummaryMarkdown {
+		if err := cmd.writeSummary(summary.Markdown(result, result.StepDurations, nil, nil)); err != nil {
+			return err
+		}
+		if cmd.opts.SummaryFile == "" {
+			return cmd.checkWorkloads(result)
+		}
+	}
+
 	err = cmd.printSummary(result)
 	if err != nil {
 		return err
 	}
+	if kubeContext != "" {
+		fmt.Fprintf(cmd.Stdout(), "\nTo use kubectl as the Kyma admin user, run: kubectl config use-context %s\n", kubeContext)
+	}
+	if cmd.stage == stageInstaller && result.ClusterVersion.State != kymaVersion.Installed {
+		fmt.Fprintf(cmd.Stdout(), "\nTo wait for the components, run: kyma install %s\n", stageComponents)
+	}
 
-	return nil
+	return cmd.checkWorkloads(result)
+}
+
+// checkWorkloads fails the command with --strict if workloads are not ready after the installation, otherwise they are only listed in the summary
+func (cmd *command) checkWorkloads(result *installation.Result) error {
+	if !cmd.opts.Strict || len(result.UnreadyWorkloads) == 0 {
+		return nil
+	}
+	return &cli.ExitError{Code: exitCodeUnreadyWorkloads, Err: fmt.Errorf("%d workloads are not ready after the installation", len(result.UnreadyWorkloads))}
 }
 
 func (cmd *command) configureInstallation(clusterConfig installation.ClusterInfo) (*installation.Installation, error) {
 
-	cmp, err := installation.LoadComponentsConfig(cmd.opts.ComponentsConfig)
+	cmp, err := installation.LoadComponents(cmd.opts.ComponentsConfig, cmd.opts.Profile)
 	if err != nil {
 		return &installation.Installation{}, errors.Wrap(err, "Could not load component configuration file. Make sure file is a valid YAML and contains a component list")
 	}
+	if len(cmp) > 0 {
+		// the feature toggles adjust the explicit component list
+		if cmp, err = installation.ApplyFeatures(cmp, cmd.opts.EnableFeatures, cmd.opts.DisableFeatures); err != nil {
+			return &installation.Installation{}, err
+		}
+		// the stable names of the components are translated to the names of the release, unmapped aliases are reported by the installation
+		cmp, _ = installation.ResolveComponentAliases(cmp, cmd.opts.Source)
+	}
 	s, err := installation.NewInstallationService(cmd.K8s.RestConfig(), cmd.opts.Timeout, "", cmp)
 	if err != nil {
 		return &installation.Installation{}, errors.Wrap(err, "Failed to create installation service. Make sure your kubeconfig is valid")
@@ -173,23 +401,78 @@ func (cmd *command) configureInstallation(clusterConfig installation.ClusterInfo
 		K8s:     cmd.K8s,
 		Service: s,
 		Options: &installation.Options{
-			NoWait:           cmd.opts.NoWait,
-			Verbose:          cmd.opts.Verbose,
-			CI:               cmd.opts.CI,
-			NonInteractive:   cmd.Factory.NonInteractive,
-			Timeout:          cmd.opts.Timeout,
-			CustomImage:      cmd.opts.CustomImage,
-			Domain:           cmd.opts.Domain,
-			TLSCert:          cmd.opts.TLSCert,
-			TLSKey:           cmd.opts.TLSKey,
-			LocalSrcPath:     cmd.opts.LocalSrcPath,
-			Password:         cmd.opts.Password,
-			OverrideConfigs:  cmd.opts.OverrideConfigs,
-			ComponentsConfig: cmd.opts.ComponentsConfig,
-			Source:           cmd.opts.Source,
-			FallbackLevel:    cmd.opts.FallbackLevel,
-			Profile:          cmd.opts.Profile,
-			IsLocal:          clusterConfig.IsLocal,
+			NoWait:                    cmd.opts.NoWait,
+			AttachOnly:                cmd.stage == stageComponents,
+			ExternalInstaller:         cmd.opts.ExternalInstaller,
+			SkipActivationCheck:       cmd.opts.SkipActivationCheck,
+			Verbose:                   cmd.opts.Verbose,
+			CI:                        cmd.opts.CI,
+			NonInteractive:            cmd.Factory.NonInteractive,
+			Timeout:                   cmd.opts.Timeout,
+			Retries:                   cmd.opts.Retries,
+			DockerTimeout:             cmd.opts.DockerTimeout,
+			ApplyTimeout:              cmd.opts.ApplyTimeout,
+			CustomImage:               cmd.opts.CustomImage,
+			Domain:                    cmd.opts.Domain,
+			UseNipIO:                  cmd.opts.UseNipIO,
+			TLSCert:                   cmd.opts.TLSCert,
+			TLSKey:                    cmd.opts.TLSKey,
+			LocalSrcPath:              cmd.opts.LocalSrcPath,
+			KeepSources:               cmd.opts.KeepSources,
+			PruneDocker:               cmd.opts.PruneDocker,
+			Password:                  cmd.opts.Password,
+			OverrideConfigs:           cmd.opts.OverrideConfigs,
+			Configs:                   cmd.opts.Configs,
+			InstallerManifest:         cmd.opts.InstallerManifest,
+			ReleaseArtifacts:          cmd.opts.ReleaseArtifacts,
+			UpgradeCRDs:               cmd.opts.UpgradeCRDs,
+			ChartValues:               cmd.opts.ChartValues,
+			DexUsers:                  cmd.opts.DexUsers,
+			ComponentsConfig:          cmd.opts.ComponentsConfig,
+			EnableFeatures:            cmd.opts.EnableFeatures,
+			DisableFeatures:           cmd.opts.DisableFeatures,
+			Source:                    cmd.opts.Source,
+			FallbackLevel:             cmd.opts.FallbackLevel,
+			Profile:                   cmd.opts.Profile,
+			InstallationName:          cmd.opts.InstallationName,
+			ExportManifests:           cmd.opts.ExportManifests,
+			RedactSecrets:             cmd.opts.RedactSecrets,
+			DryRun:                    cmd.opts.DryRun,
+			ContinueOnError:           !cmd.opts.FailFast,
+			Explain:                   cmd.opts.Explain,
+			FromScratch:               cmd.opts.FromScratch,
+			StatusPort:                cmd.opts.StatusPort,
+			StatusFile:                cmd.opts.StatusFile,
+			PrePullImages:             cmd.opts.PrePullImages,
+			SkipPodVerification:       cmd.opts.SkipPodVerification,
+			WatchEvents:               cmd.opts.WatchEvents,
+			PrePullConcurrency:        cmd.opts.PrePullConcurrency,
+			Force:                     cmd.opts.Force,
+			AllowedConflicts:          cmd.opts.AllowedConflicts,
+			CIDRCheck:                 cmd.opts.CIDRCheck,
+			ForceUnlock:               cmd.opts.ForceUnlock,
+			LockTTL:                   cmd.opts.LockTTL,
+			ImagePullSecret:           cmd.opts.ImagePullSecret,
+			RegistryServer:            cmd.opts.RegistryServer,
+			RegistryUser:              cmd.opts.RegistryUser,
+			RegistryPassword:          cmd.opts.RegistryPassword,
+			ImagePullSecretNamespaces: cmd.opts.ImagePullSecretNamespaces,
+			InstallerNodeSelector:     cmd.opts.NodeSelector,
+			InstallerTolerations:      cmd.opts.Tolerations,
+			InstallerPriorityClass:    cmd.opts.PriorityClass,
+			InstallerCPURequest:       cmd.opts.InstallerCPU,
+			InstallerMemoryRequest:    cmd.opts.InstallerMemory,
+			InstallerCPULimit:         cmd.opts.InstallerCPULimit,
+			InstallerMemoryLimit:      cmd.opts.InstallerMemoryLimit,
+			InstallerLogLevel:         cmd.opts.InstallerLogLevel,
+			NodeLabels:                cmd.opts.NodeLabels,
+			NodeTaints:                cmd.opts.NodeTaints,
+			NodeLabelSelector:         cmd.opts.NodeLabelSelector,
+			CreatePSP:                 cmd.opts.CreatePSP,
+			CleanupOnFailure:          cmd.opts.CleanupOnFailure,
+			ExtraLabels:               cmd.opts.ExtraLabels,
+			ExtraAnnotations:          cmd.opts.ExtraAnnotations,
+			IsLocal:                   clusterConfig.IsLocal,
 			LocalCluster: &installation.LocalCluster{
 				IP:       clusterConfig.LocalIP,
 				Profile:  clusterConfig.Profile,
@@ -231,47 +514,3 @@ func (cmd *command) importCertificate(ca trust.Certifier) error {
 	}
 	return nil
 }
-
-func (cmd *command) printSummary(result *installation.Result) error {
-	nicePrint := nice.Nice{
-		NonInteractive: cmd.Factory.NonInteractive,
-	}
-
-	fmt.Println()
-	nicePrint.PrintKyma()
-	fmt.Print(" is installed in version:\t")
-	nicePrint.PrintImportant(result.KymaVersion)
-
-	nicePrint.PrintKyma()
-	fmt.Print(" installation took:\t\t")
-	nicePrint.PrintImportantf("%d hours %d minutes",
-		int64(result.Duration.Hours()), int64(result.Duration.Minutes()))
-
-	nicePrint.PrintKyma()
-	fmt.Print(" is running at:\t\t")
-	nicePrint.PrintImportant(result.Host)
-
-	nicePrint.PrintKyma()
-	fmt.Print(" console:\t\t\t")
-	nicePrint.PrintImportantf(result.Console)
-
-	nicePrint.PrintKyma()
-	fmt.Print(" admin email:\t\t")
-	nicePrint.PrintImportant(result.AdminEmail)
-
-	if cmd.opts.Password == "" && !cmd.Factory.NonInteractive {
-		nicePrint.PrintKyma()
-		fmt.Printf(" admin password:\t\t")
-		nicePrint.PrintImportant(result.AdminPassword)
-	}
-
-	for _, warning := range result.Warnings {
-		nicePrint.PrintImportant(warning)
-	}
-
-	fmt.Printf("\nHappy ")
-	nicePrint.PrintKyma()
-	fmt.Printf("-ing! :)\n\n")
-
-	return nil
-}
@@ -0,0 +1,45 @@
+package install
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/kyma-project/cli/internal/dex"
+	"github.com/kyma-project/cli/internal/kube"
+	"github.com/kyma-project/cli/internal/trust"
+	"github.com/kyma-project/cli/pkg/installation"
+)
+
+// addDexUser logs in with the admin user at Dex and adds the user with a context named after the Kyma domain to the kubeconfig.
+// It returns the name of the context.
+func (cmd *command) addDexUser(result *installation.Result) (string, error) {
+	if result.AdminEmail == "" || result.AdminPassword == "" {
+		return "", errors.New("the admin credentials are not available, so the kubeconfig user cannot be added")
+	}
+	issuer, err := dex.Issuer(cmd.K8s)
+	if err != nil {
+		return "", err
+	}
+	secret, err := dex.ClientSecret(cmd.K8s)
+	if err != nil {
+		return "", err
+	}
+	// the Kyma domain is usually signed by the Kyma root certificate, which the OS might not trust yet.
+	// Without the certificate, only the root certificates of the OS are used.
+	ca, _ := trust.NewCertifier(cmd.K8s).Certificate()
+	provider, err := dex.Discover(issuer, secret, ca)
+	if err != nil {
+		return "", err
+	}
+	tokens, err := provider.Login(result.AdminEmail, result.AdminPassword)
+	if err != nil {
+		return "", err
+	}
+
+	name := fmt.Sprintf("kyma-%s", strings.TrimPrefix(strings.TrimPrefix(issuer, "https://"), "dex."))
+	if err := kube.AddUserContext(name, provider.AuthInfo(tokens), cmd.KubeconfigPath); err != nil {
+		return "", fmt.Errorf("unable to add the context '%s' to the kubeconfig: %s", name, err)
+	}
+	return name, nil
+}
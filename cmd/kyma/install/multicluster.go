@@ -0,0 +1,334 @@
+package install
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kyma-project/cli/internal/cli"
+	"github.com/pkg/errors"
+)
+
+// multiClusterFlags are the flags selecting the clusters, they are replaced by the flags of a single cluster for each installation
+var multiClusterFlags = []string{"kubeconfig-dir", "context", "parallel", "log-dir"}
+
+// progressInterval is the interval in which the progress table is displayed while the installations are running
+var progressInterval = time.Minute
+
+// clusterTarget is a cluster Kyma is installed on by one of several parallel installations
+type clusterTarget struct {
+	name       string
+	kubeconfig string
+	context    string
+}
+
+// clusterRun is the state of the installation on one cluster
+type clusterRun struct {
+	target   clusterTarget
+	logFile  string
+	state    string
+	started  time.Time
+	duration time.Duration
+	exitCode int
+}
+
+// runInstall installs Kyma on a single cluster with the given arguments and writes the output to the log, it returns the exit code of the installation
+type runInstall func(ctx context.Context, args []string, log io.Writer) (int, error)
+
+// multiCluster checks if Kyma is installed on several clusters in parallel
+func (cmd *command) multiCluster() bool {
+	return cmd.opts.KubeconfigDir != "" || len(cmd.opts.Contexts) > 1
+}
+
+// clusterTargets lists the clusters to install Kyma on, either the kubeconfig files of --kubeconfig-dir or the kubeconfig contexts given with --context
+func (cmd *command) clusterTargets() ([]clusterTarget, error) {
+	if cmd.opts.KubeconfigDir != "" && len(cmd.opts.Contexts) > 0 {
+		return nil, fmt.Errorf("use either --kubeconfig-dir or --context")
+	}
+	var targets []clusterTarget
+	if cmd.opts.KubeconfigDir == "" {
+		seen := map[string]bool{}
+		for _, c := range cmd.opts.Contexts {
+			if seen[c] {
+				return nil, fmt.Errorf("the context '%s' is given several times", c)
+			}
+			seen[c] = true
+			targets = append(targets, clusterTarget{name: c, context: c})
+		}
+		return targets, nil
+	}
+
+	files, err := ioutil.ReadDir(cmd.opts.KubeconfigDir)
+	if err != nil {
+		return nil, errors.Wrap(err, "Could not read the kubeconfig directory")
+	}
+	for _, f := range files {
+		// hidden files are usually editor or lock files, not kubeconfigs
+		if f.IsDir() || strings.HasPrefix(f.Name(), ".") {
+			continue
+		}
+		targets = append(targets, clusterTarget{
+			name:       strings.TrimSuffix(f.Name(), filepath.Ext(f.Name())),
+			kubeconfig: filepath.Join(cmd.opts.KubeconfigDir, f.Name()),
+		})
+	}
+	if len(targets) == 0 {
+		return nil, fmt.Errorf("the kubeconfig directory '%s' contains no kubeconfig files", cmd.opts.KubeconfigDir)
+	}
+	return targets, nil
+}
+
+// clusterArgs returns the arguments of the installation on the cluster: the arguments of the command without the flags selecting the clusters,
+// and the --kubeconfig or --context flag of the cluster. The CI mode keeps the log files free of spinners and prompts.
+func clusterArgs(args []string, target clusterTarget) []string {
+	removed := map[string]bool{}
+	for _, f := range multiClusterFlags {
+		removed[f] = true
+	}
+	if target.kubeconfig != "" {
+		removed["kubeconfig"] = true
+	}
+
+	var result []string
+	for n := 0; n < len(args); n++ {
+		name := strings.TrimPrefix(args[n], "--")
+		if name == args[n] {
+			result = append(result, args[n])
+			continue
+		}
+		if eq := strings.Index(name, "="); eq >= 0 {
+			if removed[name[:eq]] {
+				continue
+			}
+		} else if removed[name] {
+			// the value is given as the next argument
+			n++
+			continue
+		}
+		result = append(result, args[n])
+	}
+
+	if target.kubeconfig != "" {
+		result = append(result, "--kubeconfig", target.kubeconfig)
+	}
+	if target.context != "" {
+		result = append(result, "--context", target.context)
+	}
+	return append(result, "--ci")
+}
+
+// clusterEnv returns the environment of the installations, without the variables setting the flags selecting the clusters
+func clusterEnv(env []string) []string {
+	var result []string
+	for _, e := range env {
+		keep := true
+		for _, f := range multiClusterFlags {
+			if strings.HasPrefix(e, cli.EnvName(f)+"=") {
+				keep = false
+			}
+		}
+		if keep {
+			result = append(result, e)
+		}
+	}
+	return result
+}
+
+// execInstall runs the installation on the cluster with the executable of the CLI, so that the global state of the CLI (such as the kubeconfig and the kubectl arguments) is not shared between the clusters
+func execInstall(ctx context.Context, args []string, log io.Writer) (int, error) {
+	executable, err := os.Executable()
+	if err != nil {
+		return 1, errors.Wrap(err, "Could not find the executable of the CLI")
+	}
+	c := exec.CommandContext(ctx, executable, args...)
+	c.Env = clusterEnv(os.Environ())
+	c.Stdout = log
+	c.Stderr = log
+	err = c.Run()
+	if exitErr, ok := err.(*exec.ExitError); ok {
+		return exitErr.ExitCode(), nil
+	}
+	if err != nil {
+		return 1, err
+	}
+	return 0, nil
+}
+
+// runMultiCluster installs Kyma on several clusters, at most --parallel at the same time. The output of each installation is written to a log file,
+// and the progress of all installations is displayed in one table. The exit code is the highest exit code of the failed installations.
+func (cmd *command) runMultiCluster(args []string, run runInstall, out io.Writer) error {
+	if cmd.opts.Parallel < 1 {
+		return fmt.Errorf("invalid value '%d' of --parallel: at least one installation must run at a time", cmd.opts.Parallel)
+	}
+	// the installations would share the files and the port of these flags. The options are checked, as the flags can also be set with environment variables, which the installations inherit.
+	for _, f := range []struct {
+		name string
+		set  bool
+	}{
+		{"summary-file", cmd.opts.SummaryFile != ""},
+		{"status-file", cmd.opts.StatusFile != ""},
+		{"status-port", cmd.opts.StatusPort != 0},
+		{"export-manifests", cmd.opts.ExportManifests != ""},
+		{"trace-file", cmd.opts.Options != nil && cmd.opts.TraceFile != ""},
+	} {
+		if f.set {
+			return fmt.Errorf("--%s is not supported when Kyma is installed on several clusters", f.name)
+		}
+	}
+	targets, err := cmd.clusterTargets()
+	if err != nil {
+		return err
+	}
+
+	logDir := cmd.opts.LogDir
+	if logDir == "" {
+		if logDir, err = ioutil.TempDir("", "kyma-install-"); err != nil {
+			return errors.Wrap(err, "Could not create the directory of the log files")
+		}
+	} else if err := os.MkdirAll(logDir, 0700); err != nil {
+		return errors.Wrap(err, "Could not create the directory of the log files")
+	}
+	fmt.Fprintf(out, "Installing Kyma on %d clusters, %d at a time. The logs are written to '%s'.\n", len(targets), cmd.opts.Parallel, logDir)
+
+	runs := make([]*clusterRun, len(targets))
+	for n, t := range targets {
+		runs[n] = &clusterRun{target: t, logFile: filepath.Join(logDir, t.name+".log"), state: "pending"}
+	}
+
+	var mu sync.Mutex
+	progress := func() {
+		mu.Lock()
+		defer mu.Unlock()
+		printProgress(out, runs)
+	}
+	done := make(chan struct{})
+	ticker := time.NewTicker(progressInterval)
+	defer ticker.Stop()
+	go func() {
+		for {
+			select {
+			case <-ticker.C:
+				progress()
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	slots := make(chan struct{}, cmd.opts.Parallel)
+	var wg sync.WaitGroup
+	for _, r := range runs {
+		wg.Add(1)
+		slots <- struct{}{}
+		go func(r *clusterRun) {
+			defer func() { <-slots; wg.Done() }()
+			mu.Lock()
+			r.state, r.started = "running", time.Now()
+			mu.Unlock()
+			progress()
+
+			code, err := cmd.installCluster(r, args, run)
+			mu.Lock()
+			r.duration, r.exitCode, r.state = time.Since(r.started), code, "succeeded"
+			if code != 0 {
+				r.state = "failed"
+			}
+			mu.Unlock()
+			if err != nil {
+				appendLog(r.logFile, err)
+			}
+			progress()
+		}(r)
+	}
+	wg.Wait()
+	close(done)
+
+	printResults(out, runs)
+	var failed, exitCode int
+	for _, r := range runs {
+		if r.exitCode != 0 {
+			failed++
+			if r.exitCode > exitCode {
+				exitCode = r.exitCode
+			}
+		}
+	}
+	if failed > 0 {
+		return &cli.ExitError{Code: exitCode, Err: fmt.Errorf("the installation failed on %d of %d clusters", failed, len(runs))}
+	}
+	return nil
+}
+
+// installCluster runs the installation on the cluster of the run, it returns exit code 1 if the installation cannot be started
+func (cmd *command) installCluster(r *clusterRun, args []string, run runInstall) (int, error) {
+	log, err := os.Create(r.logFile)
+	if err != nil {
+		return 1, errors.Wrap(err, "Could not create the log file")
+	}
+	defer log.Close()
+	code, err := run(context.Background(), clusterArgs(args, r.target), log)
+	if err != nil && code == 0 {
+		code = 1
+	}
+	return code, err
+}
+
+// appendLog adds the error to the log file of a cluster whose installation could not be started
+func appendLog(file string, err error) {
+	f, openErr := os.OpenFile(file, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if openErr != nil {
+		return
+	}
+	defer f.Close()
+	fmt.Fprintf(f, "Error: %s\n", err)
+}
+
+// printProgress displays the state of the installations and the last line of their logs
+func printProgress(out io.Writer, runs []*clusterRun) {
+	fmt.Fprintln(out)
+	fmt.Fprintf(out, "%-30s %-10s %-10s %s\n", "CLUSTER", "STATE", "DURATION", "LAST LOG LINE")
+	for _, r := range runs {
+		duration := r.duration
+		if r.state == "running" {
+			duration = time.Since(r.started)
+		}
+		var elapsed string
+		if !r.started.IsZero() {
+			elapsed = duration.Round(time.Second).String()
+		}
+		fmt.Fprintf(out, "%-30s %-10s %-10s %s\n", r.target.name, r.state, elapsed, lastLogLine(r.logFile))
+	}
+}
+
+// printResults displays the summary matrix of the installations, sorted by cluster
+func printResults(out io.Writer, runs []*clusterRun) {
+	sorted := append([]*clusterRun{}, runs...)
+	sort.Slice(sorted, func(a, b int) bool { return sorted[a].target.name < sorted[b].target.name })
+	fmt.Fprintln(out)
+	fmt.Fprintf(out, "%-30s %-10s %-10s %-10s %s\n", "CLUSTER", "RESULT", "EXIT CODE", "DURATION", "LOG FILE")
+	for _, r := range sorted {
+		fmt.Fprintf(out, "%-30s %-10s %-10d %-10s %s\n", r.target.name, r.state, r.exitCode, r.duration.Round(time.Second), r.logFile)
+	}
+}
+
+// lastLogLine returns the last non-empty line of the log file, shortened to fit into the progress table
+func lastLogLine(file string) string {
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return ""
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	line := strings.TrimSpace(lines[len(lines)-1])
+	if len(line) > 80 {
+		line = line[:77] + "..."
+	}
+	return line
+}
@@ -0,0 +1,122 @@
+package install
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/kyma-project/cli/internal/cli"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClusterArgs(t *testing.T) {
+	t.Parallel()
+	args := []string{"install", "--kubeconfig-dir", "clusters", "--parallel=3", "--source", "1.15.1", "--kubeconfig=old", "--log-dir", "logs", "-n"}
+	require.Equal(t,
+		[]string{"install", "--source", "1.15.1", "-n", "--kubeconfig", "clusters/a.yaml", "--ci"},
+		clusterArgs(args, clusterTarget{name: "a", kubeconfig: "clusters/a.yaml"}))
+
+	args = []string{"install", "--context", "a", "--context=b", "--kubeconfig=shared"}
+	require.Equal(t,
+		[]string{"install", "--kubeconfig=shared", "--context", "b", "--ci"},
+		clusterArgs(args, clusterTarget{name: "b", context: "b"}), "the kubeconfig of the contexts is kept")
+
+	require.Equal(t, []string{"PATH=/bin", "KYMACTL_DOMAIN=example.com"},
+		clusterEnv([]string{"PATH=/bin", "KYMACTL_KUBECONFIG_DIR=clusters", "KYMACTL_DOMAIN=example.com", "KYMACTL_PARALLEL=2"}))
+}
+
+func TestClusterTargets(t *testing.T) {
+	t.Parallel()
+	dir, err := ioutil.TempDir("", "kubeconfigs")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	for _, f := range []string{"b.yaml", "a.yaml", ".a.yaml.swp"} {
+		require.NoError(t, ioutil.WriteFile(filepath.Join(dir, f), nil, 0600))
+	}
+	require.NoError(t, os.Mkdir(filepath.Join(dir, "old"), 0700))
+
+	cmd := command{opts: &Options{KubeconfigDir: dir}}
+	targets, err := cmd.clusterTargets()
+	require.NoError(t, err)
+	require.Equal(t, []clusterTarget{
+		{name: "a", kubeconfig: filepath.Join(dir, "a.yaml")},
+		{name: "b", kubeconfig: filepath.Join(dir, "b.yaml")},
+	}, targets)
+
+	cmd.opts.Contexts = []string{"a"}
+	_, err = cmd.clusterTargets()
+	require.Error(t, err, "--kubeconfig-dir and --context must not be combined")
+
+	cmd.opts = &Options{Contexts: []string{"a", "b", "a"}}
+	_, err = cmd.clusterTargets()
+	require.EqualError(t, err, "the context 'a' is given several times")
+}
+
+func TestRunMultiCluster(t *testing.T) {
+	t.Parallel()
+	logDir, err := ioutil.TempDir("", "install-logs")
+	require.NoError(t, err)
+	defer os.RemoveAll(logDir)
+
+	var mu sync.Mutex
+	var running, maxRunning int
+	run := func(_ context.Context, args []string, log io.Writer) (int, error) {
+		mu.Lock()
+		running++
+		if running > maxRunning {
+			maxRunning = running
+		}
+		mu.Unlock()
+		defer func() { mu.Lock(); running--; mu.Unlock() }()
+
+		name := args[len(args)-2]
+		fmt.Fprintf(log, "Installing Kyma on %s\n", name)
+		switch name {
+		case "broken":
+			fmt.Fprintln(log, "Error: the installation failed")
+			return 1, nil
+		case "slow":
+			return 2, nil
+		}
+		return 0, nil
+	}
+
+	cmd := command{opts: &Options{Contexts: []string{"c1", "broken", "c2", "slow", "c3"}, Parallel: 2, LogDir: logDir}}
+	require.True(t, cmd.multiCluster())
+	out := &bytes.Buffer{}
+	err = cmd.runMultiCluster([]string{"install", "--context", "c1"}, run, out)
+	require.Error(t, err)
+	require.Equal(t, 2, cli.ExitCode(err), "the highest exit code of the failed installations is returned")
+	require.EqualError(t, err, "the installation failed on 2 of 5 clusters")
+	require.LessOrEqual(t, maxRunning, 2)
+
+	log, err := ioutil.ReadFile(filepath.Join(logDir, "broken.log"))
+	require.NoError(t, err)
+	require.Equal(t, "Installing Kyma on broken\nError: the installation failed\n", string(log))
+
+	results := out.String()[strings.LastIndex(out.String(), "CLUSTER"):]
+	require.Regexp(t, `broken\s+failed\s+1 `, results)
+	require.Regexp(t, `c1\s+succeeded\s+0 `, results)
+	require.Regexp(t, `slow\s+failed\s+2 `, results)
+
+	cmd.opts.Parallel = 0
+	require.Error(t, cmd.runMultiCluster(nil, run, out))
+	cmd.opts.Parallel = 1
+	cmd.opts.SummaryFile = "summary.md"
+	require.EqualError(t, cmd.runMultiCluster(nil, run, out), "--summary-file is not supported when Kyma is installed on several clusters")
+
+	// the flags can also be set with environment variables
+	cmd.opts.SummaryFile = ""
+	cmd.opts.StatusPort = 8080
+	require.EqualError(t, cmd.runMultiCluster(nil, run, out), "--status-port is not supported when Kyma is installed on several clusters")
+	cmd.opts.StatusPort = 0
+	cmd.opts.Options = &cli.Options{TraceFile: "trace.json"}
+	require.EqualError(t, cmd.runMultiCluster(nil, run, out), "--trace-file is not supported when Kyma is installed on several clusters")
+}
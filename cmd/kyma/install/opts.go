@@ -6,25 +6,88 @@ import (
 	"github.com/kyma-project/cli/internal/cli"
 )
 
-//DefaultKymaVersion contains the default Kyma version to be installed in case another version is not specified
-var DefaultKymaVersion string
-
 //Options defines available options for the command
 type Options struct {
 	*cli.Options
-	NoWait           bool
-	Domain           string
-	TLSCert          string
-	TLSKey           string
-	LocalSrcPath     string
-	Timeout          time.Duration
-	Password         string
-	OverrideConfigs  []string
-	ComponentsConfig string
-	Source           string
-	FallbackLevel    int
-	CustomImage      string
-	Profile          string
+	NoWait                    bool
+	SkipActivationCheck       bool
+	ExternalInstaller         bool
+	Domain                    string
+	UseNipIO                  bool
+	TLSCert                   string
+	TLSKey                    string
+	LocalSrcPath              string
+	KeepSources               bool
+	PruneDocker               bool
+	Timeout                   time.Duration
+	RequestTimeout            time.Duration
+	Retries                   int
+	DockerTimeout             time.Duration
+	ApplyTimeout              time.Duration
+	Password                  string
+	OverrideConfigs           []string
+	Configs                   []string
+	InstallerManifest         string
+	ReleaseArtifacts          string
+	UpgradeCRDs               bool
+	DexUsers                  []string
+	ChartValues               []string
+	ComponentsConfig          string
+	EnableFeatures            []string
+	DisableFeatures           []string
+	Source                    string
+	Release                   string
+	AllowedConflicts          []string
+	CIDRCheck                 bool
+	FallbackLevel             int
+	CustomImage               string
+	Profile                   string
+	InstallationName          string
+	ExportManifests           string
+	RedactSecrets             bool
+	DryRun                    bool
+	GetConfig                 bool
+	FailFast                  bool
+	Explain                   bool
+	FromScratch               bool
+	StatusPort                int
+	StatusFile                string
+	PrePullImages             bool
+	SkipPodVerification       bool
+	WatchEvents               bool
+	Strict                    bool
+	PrePullConcurrency        int
+	Force                     bool
+	ForceUnlock               bool
+	LockTTL                   time.Duration
+	ImagePullSecret           string
+	RegistryServer            string
+	RegistryUser              string
+	RegistryPassword          string
+	ImagePullSecretNamespaces []string
+	NodeSelector              map[string]string
+	Tolerations               []string
+	PriorityClass             string
+	InstallerCPU              string
+	InstallerMemory           string
+	InstallerCPULimit         string
+	InstallerMemoryLimit      string
+	InstallerLogLevel         string
+	NodeLabels                map[string]string
+	NodeTaints                []string
+	NodeLabelSelector         string
+	KubeconfigDir             string
+	Contexts                  []string
+	Parallel                  int
+	LogDir                    string
+	CreatePSP                 bool
+	CleanupOnFailure          bool
+	ExtraLabels               map[string]string
+	ExtraAnnotations          map[string]string
+	AnnotateKubeconfig        bool
+	PatchCoreDNS              bool
+	Output                    string
+	SummaryFile               string
 }
 
 //NewOptions creates options with default values
@@ -0,0 +1,72 @@
+package profiles
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/kyma-project/cli/internal/cli"
+	"github.com/kyma-project/cli/pkg/installation"
+	"github.com/spf13/cobra"
+)
+
+type command struct {
+	opts *Options
+	cli.Command
+}
+
+//NewCmd creates a new profiles command
+func NewCmd(o *Options) *cobra.Command {
+	cmd := command{
+		Command: cli.Command{Options: o.Options},
+		opts:    o,
+	}
+
+	cobraCmd := &cobra.Command{
+		Use:   "profiles",
+		Short: "Lists the installation profiles shipped with Kyma CLI.",
+		Long: `Use this command to list the profiles which you can pass to the "--profile" flag of the "install" and "upgrade" commands.
+For each profile, the command shows the component list and the overrides it installs. Profiles without a component list install the components of the release.
+`,
+		RunE: func(_ *cobra.Command, _ []string) error { return cmd.Run() },
+	}
+	return cobraCmd
+}
+
+//Run runs the command
+func (cmd *command) Run() error {
+	return printProfiles(cmd.Stdout(), installation.Profiles())
+}
+
+func printProfiles(out io.Writer, profiles []installation.Profile) error {
+	for n, p := range profiles {
+		if n > 0 {
+			fmt.Fprintln(out)
+		}
+		fmt.Fprintf(out, "%s\n  %s\n", p.Name, p.Description)
+		if p.InstallerProfile != "" {
+			fmt.Fprintf(out, "  Kyma Installer profile: %s\n", p.InstallerProfile)
+		}
+
+		components, err := p.ComponentList()
+		if err != nil {
+			return err
+		}
+		if len(components) == 0 {
+			fmt.Fprintln(out, "  Components: all components of the release")
+		} else {
+			fmt.Fprintln(out, "  Components:")
+			for _, c := range components {
+				fmt.Fprintf(out, "    - %s (%s)\n", c.Name, c.Namespace)
+			}
+		}
+
+		if p.Overrides != "" {
+			fmt.Fprintln(out, "  Overrides:")
+			for _, line := range strings.Split(strings.TrimSuffix(p.Overrides, "\n"), "\n") {
+				fmt.Fprintf(out, "    %s\n", line)
+			}
+		}
+	}
+	return nil
+}
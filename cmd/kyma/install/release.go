@@ -0,0 +1,28 @@
+package install
+
+import (
+	"fmt"
+
+	"github.com/kyma-project/cli/internal/release"
+	"github.com/kyma-project/cli/internal/releases"
+	kymaVersion "github.com/kyma-project/cli/internal/version"
+)
+
+// resolveRelease sets the source to the version the release channel given with --release points to
+func (cmd *command) resolveRelease() error {
+	if cmd.opts.Source != kymaVersion.DefaultKymaVersion {
+		return fmt.Errorf("--release and --source cannot be used together")
+	}
+	s := cmd.NewStep(fmt.Sprintf("Resolving the release channel '%s'", cmd.opts.Release))
+	r, err := releases.ResolveChannel(cmd.opts.Release, s.LogErrorf)
+	if err != nil {
+		s.Failure()
+		return err
+	}
+	if r.Pinned {
+		s.LogInfof("Using the version pinned with %s", releases.ChannelEnv(r.Channel))
+	}
+	cmd.opts.Source = r.Version
+	s.Successf("Release channel '%s' resolved to Kyma %s (%s)", r.Channel, r.Version, release.FromResolution(r).Location())
+	return nil
+}
@@ -0,0 +1,52 @@
+package install
+
+import (
+	"fmt"
+
+	"github.com/kyma-project/cli/internal/cli"
+	"github.com/spf13/cobra"
+)
+
+const (
+	// stageInstaller deploys the Kyma Installer and triggers the installation, without waiting for it
+	stageInstaller = "installer"
+	// stageComponents waits for the installation triggered by stageInstaller and completes it
+	stageComponents = "components"
+)
+
+// newStageCmd creates a subcommand of install which runs one stage of the installation with the flags of the install command
+func newStageCmd(install *cobra.Command, o *Options, stage, short, long, example string) *cobra.Command {
+	cmd := command{
+		Command: cli.Command{Options: o.Options},
+		opts:    o,
+		stage:   stage,
+	}
+
+	cobraCmd := &cobra.Command{
+		Use:     stage,
+		Short:   short,
+		Long:    long,
+		Example: example,
+		PreRunE: func(c *cobra.Command, _ []string) error { return cmd.validateFlags(c.Flags()) },
+		RunE:    func(_ *cobra.Command, _ []string) error { return cmd.Run() },
+	}
+	// the flags are shared, so both commands set the same options
+	cobraCmd.Flags().AddFlagSet(install.Flags())
+	return cobraCmd
+}
+
+// configureStage adjusts the options to the stage run by the command and rejects the flags which do not apply to it
+func (cmd *command) configureStage() error {
+	switch cmd.stage {
+	case stageInstaller:
+		if cmd.opts.UseNipIO {
+			return fmt.Errorf("--use-nip-io cannot be used with \"kyma install %s\", because the domain is determined while the components are installed. Use \"kyma install\" instead", stageInstaller)
+		}
+		cmd.opts.NoWait = true
+	case stageComponents:
+		if cmd.opts.NoWait || cmd.opts.DryRun || cmd.opts.GetConfig {
+			return fmt.Errorf("--wait=false, --dry-run and --get-config cannot be used with \"kyma install %s\"", stageComponents)
+		}
+	}
+	return nil
+}
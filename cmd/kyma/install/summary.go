@@ -0,0 +1,30 @@
+package install
+
+import (
+	"fmt"
+	"io/ioutil"
+
+	"github.com/kyma-project/cli/pkg/installation"
+	"github.com/kyma-project/cli/pkg/installation/summary"
+	"github.com/pkg/errors"
+)
+
+// outputSummaryMarkdown is the --output format rendering the summary as Markdown
+const outputSummaryMarkdown = "summary-markdown"
+
+// printSummary shows the details of the installation, depending on whether Kyma is installed or the installation is still in progress
+func (cmd *command) printSummary(result *installation.Result) error {
+	return summary.Print(cmd.Stdout(), result, summary.Options{NonInteractive: cmd.Factory.NonInteractive, PasswordKnown: cmd.opts.Password != ""})
+}
+
+// writeSummary writes the rendered summary to the --summary-file, or to stdout
+func (cmd *command) writeSummary(s string) error {
+	if cmd.opts.SummaryFile == "" {
+		_, err := fmt.Fprint(cmd.Stdout(), s)
+		return err
+	}
+	if err := ioutil.WriteFile(cmd.opts.SummaryFile, []byte(s), 0644); err != nil {
+		return errors.Wrap(err, "Could not write the summary file")
+	}
+	return nil
+}
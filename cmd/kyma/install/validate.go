@@ -0,0 +1,144 @@
+package install
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/blang/semver/v4"
+	"github.com/kyma-project/cli/internal/releases"
+	kymaVersion "github.com/kyma-project/cli/internal/version"
+	"github.com/kyma-project/cli/pkg/installation"
+	"github.com/spf13/pflag"
+)
+
+// localOnlyFlags are only used for installations from local sources
+var localOnlyFlags = []string{"custom-image", "keep-sources", "prune-docker", "docker-timeout"}
+
+// installerFlags change the Kyma Installer or the Installation CR deployed by the CLI, so they cannot be used with --external-installer
+var installerFlags = []string{"source", "release", "src-path", "custom-image", "keep-sources", "prune-docker", "docker-timeout",
+	"installer-manifest", "release-artifacts", "upgrade-crds", "fallback-level", "components", "profile", "enable", "disable", "chart-values", "add-user",
+	"set-image-pull-secret", "registry-server", "registry-user", "registry-password", "image-pull-secret-namespace",
+	"node-selector", "toleration", "priority-class", "installer-cpu", "installer-memory", "installer-cpu-limit", "installer-memory-limit", "installer-log-level",
+	"create-psp", "export-manifests", "get-config"}
+
+// validateFlags checks the values of the flags before anything is started, and normalizes the values which have one obvious correct form.
+// The errors show the invalid value and an example of a valid one.
+// The checks which need the cluster or read files are done by the installation.
+func (cmd *command) validateFlags(flags *pflag.FlagSet) error {
+	o := cmd.opts
+
+	// release tags are often written with a leading v, which is not part of the version of the release artifacts
+	if v := strings.TrimPrefix(o.Source, "v"); v != o.Source {
+		if _, err := semver.Parse(v); err == nil {
+			o.Source = v
+		}
+	}
+	if o.Release != "" {
+		if !isChannel(o.Release) {
+			return fmt.Errorf("invalid release channel '%s': use one of %s, e.g. --release=%s. To install a specific version, use e.g. --source=%s",
+				o.Release, strings.Join(releases.ChannelNames(), ", "), releases.ChannelStable, installExampleVersion(o.Release))
+		}
+		if flags.Changed("source") {
+			return fmt.Errorf("--release and --source cannot be used together, use either e.g. --release=%s or --source=%s", releases.ChannelStable, kymaVersion.DefaultKymaVersion)
+		}
+	}
+
+	if flags.Changed("domain") {
+		if err := installation.ValidateDomain(o.Domain); err != nil {
+			return err
+		}
+	}
+
+	// --timeout=0 watches the installation without a timeout
+	if o.Timeout < 0 {
+		return fmt.Errorf("invalid value '%s' of --timeout: the duration must not be negative, e.g. --timeout=%s", o.Timeout, flags.Lookup("timeout").DefValue)
+	}
+	for _, d := range []struct {
+		name  string
+		value time.Duration
+	}{
+		{"request-timeout", o.RequestTimeout},
+		{"docker-timeout", o.DockerTimeout},
+		{"apply-timeout", o.ApplyTimeout},
+		{"lock-ttl", o.LockTTL},
+	} {
+		if d.value <= 0 {
+			return fmt.Errorf("invalid value '%s' of --%s: the duration must be positive, e.g. --%s=%s", d.value, d.name, d.name, flags.Lookup(d.name).DefValue)
+		}
+	}
+	for _, n := range []struct {
+		name  string
+		value int
+		min   int
+	}{
+		{"retries", o.Retries, 0},
+		{"fallback-level", o.FallbackLevel, 0},
+		{"pre-pull-concurrency", o.PrePullConcurrency, 1},
+		{"parallel", o.Parallel, 1},
+	} {
+		if n.value < n.min {
+			return fmt.Errorf("invalid value '%d' of --%s: the value must be at least %d, e.g. --%s=%s", n.value, n.name, n.min, n.name, flags.Lookup(n.name).DefValue)
+		}
+	}
+	if o.StatusPort < 0 || o.StatusPort > 65535 {
+		return fmt.Errorf("invalid value '%d' of --status-port: the port must be between 1 and 65535, or 0 to serve no status, e.g. --status-port=8080", o.StatusPort)
+	}
+
+	if o.ExternalInstaller {
+		for _, name := range installerFlags {
+			if flags.Changed(name) {
+				return fmt.Errorf("--%s cannot be used with --external-installer, as the Kyma Installer and the Installation CR are not deployed by the CLI. Configure them where they are deployed", name)
+			}
+		}
+	}
+	if !strings.EqualFold(o.Source, "local") {
+		for _, name := range localOnlyFlags {
+			if flags.Changed(name) {
+				return fmt.Errorf("--%s is only used with --source=local, e.g. kyma install --source=local --%s=%s", name, name, localFlagExample(name))
+			}
+		}
+	}
+	if o.Strict && o.SkipPodVerification {
+		return fmt.Errorf("--strict and --skip-pod-verification cannot be used together, as --strict fails the command if the verified workloads are not ready")
+	}
+	if o.Strict && o.NoWait {
+		return fmt.Errorf("--strict and --wait=false cannot be used together, as the workloads are only verified once the installation finished")
+	}
+	if o.Output != "" && o.Output != outputSummaryMarkdown {
+		return fmt.Errorf("unsupported output format '%s'. Use '%s' or omit the flag to display the summary as text", o.Output, outputSummaryMarkdown)
+	}
+	if o.SummaryFile != "" && o.Output == "" {
+		return fmt.Errorf("--summary-file requires --output, e.g. --output=%s --summary-file=%s", outputSummaryMarkdown, o.SummaryFile)
+	}
+	return nil
+}
+
+func isChannel(name string) bool {
+	for _, c := range releases.ChannelNames() {
+		if c == name {
+			return true
+		}
+	}
+	return false
+}
+
+// installExampleVersion returns the version given instead of a release channel without a leading v, or the default version if it is no version
+func installExampleVersion(value string) string {
+	if v := strings.TrimPrefix(value, "v"); v != "" {
+		if _, err := semver.Parse(v); err == nil {
+			return v
+		}
+	}
+	return kymaVersion.DefaultKymaVersion
+}
+
+func localFlagExample(name string) string {
+	switch name {
+	case "custom-image":
+		return "eu.gcr.io/my-project/kyma-installer:dev"
+	case "docker-timeout":
+		return "20m"
+	}
+	return "true"
+}
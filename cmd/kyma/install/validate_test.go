@@ -0,0 +1,100 @@
+package install
+
+import (
+	"testing"
+
+	"github.com/kyma-project/cli/internal/cli"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateFlags(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name string
+		args []string
+		err  string
+	}{
+		{name: "defaults"},
+		{name: "release channel", args: []string{"--release=canary"}},
+		{name: "version instead of a channel", args: []string{"--release=v1.16.0"},
+			err: "invalid release channel 'v1.16.0': use one of stable, canary, nightly, e.g. --release=stable. To install a specific version, use e.g. --source=1.16.0"},
+		{name: "release and source", args: []string{"--release=stable", "--source=1.15.1"},
+			err: "--release and --source cannot be used together"},
+		{name: "domain with scheme", args: []string{"--domain=https://kyma.example.com"},
+			err: "invalid domain 'https://kyma.example.com': the domain must not contain a scheme, use for example 'kyma.example.com'"},
+		{name: "domain with port", args: []string{"--domain=kyma.example.com:443"},
+			err: "invalid domain 'kyma.example.com:443': the domain must not contain a port or a path, use for example 'kyma.example.com'"},
+		{name: "negative timeout", args: []string{"--timeout=-5m"},
+			err: "invalid value '-5m0s' of --timeout: the duration must not be negative, e.g. --timeout=1h0m0s"},
+		{name: "no timeout", args: []string{"--timeout=0"}},
+		{name: "zero request timeout", args: []string{"--request-timeout=0s"},
+			err: "invalid value '0s' of --request-timeout: the duration must be positive, e.g. --request-timeout=30s"},
+		{name: "negative lock TTL", args: []string{"--lock-ttl=-1s"},
+			err: "of --lock-ttl: the duration must be positive"},
+		{name: "negative retries", args: []string{"--retries=-1"},
+			err: "invalid value '-1' of --retries: the value must be at least 0, e.g. --retries=0"},
+		{name: "no parallel installations", args: []string{"--parallel=0"},
+			err: "invalid value '0' of --parallel: the value must be at least 1, e.g. --parallel=1"},
+		{name: "no parallel pulls", args: []string{"--pre-pull-concurrency=0"},
+			err: "invalid value '0' of --pre-pull-concurrency: the value must be at least 1, e.g. --pre-pull-concurrency=4"},
+		{name: "status port out of range", args: []string{"--status-port=70000"},
+			err: "invalid value '70000' of --status-port"},
+		{name: "custom image of a release", args: []string{"--source=1.15.1", "--custom-image=foo/installer:dev"},
+			err: "--custom-image is only used with --source=local, e.g. kyma install --source=local --custom-image=eu.gcr.io/my-project/kyma-installer:dev"},
+		{name: "custom image of local sources", args: []string{"--source=local", "--custom-image=foo/installer:dev"}},
+		{name: "docker timeout of a release", args: []string{"--docker-timeout=20m"},
+			err: "--docker-timeout is only used with --source=local"},
+		{name: "external installer", args: []string{"--external-installer", "--override=overrides.yaml", "--domain=kyma.example.com"}},
+		{name: "external installer with a release", args: []string{"--external-installer", "--source=1.15.1"},
+			err: "--source cannot be used with --external-installer"},
+		{name: "external installer with components", args: []string{"--external-installer", "--components=components.yaml"},
+			err: "--components cannot be used with --external-installer"},
+		{name: "strict without verification", args: []string{"--strict", "--skip-pod-verification"},
+			err: "--strict and --skip-pod-verification cannot be used together"},
+		{name: "strict without waiting", args: []string{"--strict", "--wait=false"},
+			err: "--strict and --wait=false cannot be used together"},
+		{name: "strict without waiting, deprecated flag", args: []string{"--strict", "--no-wait"},
+			err: "--strict and --wait=false cannot be used together"},
+		{name: "strict without waiting, deprecated camelCase flag", args: []string{"--strict", "--noWait"},
+			err: "--strict and --wait=false cannot be used together"},
+		{name: "strict with waiting", args: []string{"--strict", "--noWait", "--wait"}},
+		{name: "unknown output", args: []string{"--output=json"},
+			err: "unsupported output format 'json'"},
+		{name: "summary file without output", args: []string{"--summary-file=summary.md"},
+			err: "--summary-file requires --output, e.g. --output=summary-markdown --summary-file=summary.md"},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			o := NewOptions(&cli.Options{})
+			cobraCmd := NewCmd(o)
+			require.NoError(t, cobraCmd.ParseFlags(tc.args))
+			cmd := &command{opts: o}
+			err := cmd.validateFlags(cobraCmd.Flags())
+			if tc.err == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			require.Contains(t, err.Error(), tc.err)
+		})
+	}
+}
+
+func TestValidateFlagsNormalizesVersions(t *testing.T) {
+	t.Parallel()
+	for source, expected := range map[string]string{
+		"v1.15.1":                       "1.15.1",
+		"1.15.1":                        "1.15.1",
+		"local":                         "local",
+		"user/my-kyma-installer:v1.4.0": "user/my-kyma-installer:v1.4.0",
+		"vendor/installer":              "vendor/installer",
+	} {
+		o := NewOptions(&cli.Options{})
+		cobraCmd := NewCmd(o)
+		require.NoError(t, cobraCmd.ParseFlags([]string{"--source=" + source}))
+		require.NoError(t, (&command{opts: o}).validateFlags(cobraCmd.Flags()))
+		require.Equal(t, expected, o.Source)
+	}
+}
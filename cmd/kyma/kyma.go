@@ -1,6 +1,10 @@
 package kyma
 
 import (
+	"fmt"
+	"os"
+	"strings"
+
 	"github.com/kyma-project/cli/cmd/kyma/alpha"
 	alphaDelete "github.com/kyma-project/cli/cmd/kyma/alpha/delete"
 	alphaInstall "github.com/kyma-project/cli/cmd/kyma/alpha/deploy"
@@ -8,11 +12,23 @@ import (
 	"github.com/kyma-project/cli/cmd/kyma/alpha/provision/k3s"
 	alphaVersion "github.com/kyma-project/cli/cmd/kyma/alpha/version"
 	"github.com/kyma-project/cli/cmd/kyma/apply"
+	"github.com/kyma-project/cli/cmd/kyma/applyconfig"
 	"github.com/kyma-project/cli/cmd/kyma/completion"
+	"github.com/kyma-project/cli/cmd/kyma/components"
 	"github.com/kyma-project/cli/cmd/kyma/console"
 	"github.com/kyma-project/cli/cmd/kyma/create"
+	"github.com/kyma-project/cli/cmd/kyma/defaults"
+	"github.com/kyma-project/cli/cmd/kyma/diagnostics"
+	"github.com/kyma-project/cli/cmd/kyma/helm"
+	helmSetup "github.com/kyma-project/cli/cmd/kyma/helm/setup"
+	imports "github.com/kyma-project/cli/cmd/kyma/import"
+	importCerts "github.com/kyma-project/cli/cmd/kyma/import/certs"
 	initial "github.com/kyma-project/cli/cmd/kyma/init"
 	"github.com/kyma-project/cli/cmd/kyma/install"
+	installCleanup "github.com/kyma-project/cli/cmd/kyma/install/cleanup"
+	"github.com/kyma-project/cli/cmd/kyma/install/profiles"
+	"github.com/kyma-project/cli/cmd/kyma/plugin"
+	pluginList "github.com/kyma-project/cli/cmd/kyma/plugin/list"
 	"github.com/kyma-project/cli/cmd/kyma/provision/aks"
 	"github.com/kyma-project/cli/cmd/kyma/provision/gardener"
 	"github.com/kyma-project/cli/cmd/kyma/provision/gardener/aws"
@@ -20,6 +36,9 @@ import (
 	"github.com/kyma-project/cli/cmd/kyma/provision/gardener/gcp"
 	"github.com/kyma-project/cli/cmd/kyma/provision/gke"
 	"github.com/kyma-project/cli/cmd/kyma/provision/minikube"
+	"github.com/kyma-project/cli/cmd/kyma/releases"
+	kymaStatus "github.com/kyma-project/cli/cmd/kyma/status"
+	"github.com/kyma-project/cli/cmd/kyma/summary"
 	"github.com/kyma-project/cli/cmd/kyma/sync"
 	"github.com/kyma-project/cli/cmd/kyma/test"
 	"github.com/kyma-project/cli/cmd/kyma/test/definitions"
@@ -28,14 +47,28 @@ import (
 	"github.com/kyma-project/cli/cmd/kyma/test/logs"
 	"github.com/kyma-project/cli/cmd/kyma/test/run"
 	"github.com/kyma-project/cli/cmd/kyma/test/status"
+	"github.com/kyma-project/cli/cmd/kyma/updatecli"
 	"github.com/kyma-project/cli/cmd/kyma/version"
+	"github.com/kyma-project/cli/cmd/kyma/wait"
+	waitInstallation "github.com/kyma-project/cli/cmd/kyma/wait/installation"
+	waitPod "github.com/kyma-project/cli/cmd/kyma/wait/pod"
 
 	"github.com/kyma-project/cli/cmd/kyma/provision"
 	"github.com/kyma-project/cli/cmd/kyma/upgrade"
 	"github.com/kyma-project/cli/internal/cli"
+	"github.com/kyma-project/cli/internal/github"
+	"github.com/kyma-project/cli/internal/kube"
+	"github.com/kyma-project/cli/internal/logger"
+	"github.com/kyma-project/cli/internal/metadata"
+	"github.com/kyma-project/cli/internal/trace"
+	"github.com/kyma-project/cli/pkg/installation"
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 )
 
+// kubectlArgsEnv lists kubectl style connection flags applied if the --kubectl-arg flag is not used
+const kubectlArgsEnv = "KYMACTL_KUBECTL_ARGS"
+
 //NewCmd creates a new kyma CLI command
 func NewCmd(o *cli.Options) *cobra.Command {
 	cmd := &cobra.Command{
@@ -44,18 +77,61 @@ func NewCmd(o *cli.Options) *cobra.Command {
 		Long: `Kyma is a flexible and easy way to connect and extend enterprise applications in a cloud-native world.
 Kyma CLI allows you to install, test, and manage Kyma.
 
+All flags can also be set with environment variables, which are named after the flag with the KYMACTL_ prefix (for example, KYMACTL_NON_INTERACTIVE=true for --non-interactive). Flags given on the command line take precedence over the environment variables.
+
 `,
 		// Affects children as well
 		SilenceErrors: false,
 		SilenceUsage:  true,
+		PersistentPreRunE: func(c *cobra.Command, _ []string) error {
+			if err := o.ApplyEnvFlags(c.Flags(), os.LookupEnv); err != nil {
+				return err
+			}
+			// the token is set before anything is logged or traced, so that it is masked everywhere
+			github.SetToken(o.GitHubToken)
+			// "~" is not expanded by the shell in flags like --src-path=~/kyma, so all path flags are normalized before they are used
+			if err := cli.NormalizePathFlags(c); err != nil {
+				return err
+			}
+			if err := configureLogs(o); err != nil {
+				return err
+			}
+			configureTrace(o, c)
+			if o.MetadataFile != "" {
+				if err := metadata.Load(o.MetadataFile); err != nil {
+					return err
+				}
+			}
+			if o.ProfileDir != "" {
+				if err := installation.LoadProfiles(o.ProfileDir); err != nil {
+					return err
+				}
+			}
+			return configureKube(o)
+		},
 	}
 
 	cmd.PersistentFlags().BoolVarP(&o.Verbose, "verbose", "v", false, "Displays details of actions triggered by the command.")
 	cmd.PersistentFlags().BoolVar(&o.NonInteractive, "non-interactive", false, "Enables the non-interactive shell mode.")
 	cmd.PersistentFlags().BoolVar(&o.CI, "ci", false, "Enables the CI mode to run on CI/CD systems. It avoids any user interaction (such as no dialog prompts) and ensures that logs are formatted properly in log files (such as no spinners for CLI steps).")
+	cmd.PersistentFlags().StringVar(&o.GitHubToken, "github-token", "", `Token added as Authorization header to all requests of the CLI to GitHub, such as the lookup of the Kyma releases and the download of the release artifacts. Use it if a proxy in front of GitHub requires authentication, or to avoid the rate limits of the GitHub API. It can also be set with the KYMACTL_GITHUB_TOKEN environment variable, which keeps it out of the shell history. The token is masked in the logs and traces.`)
 	// Kubeconfig env var and default paths are resolved by the kyma k8s client using the k8s defined resolution strategy.
 	cmd.PersistentFlags().StringVar(&o.KubeconfigPath, "kubeconfig", "", `Specifies the path to the kubeconfig file. By default, Kyma CLI uses the KUBECONFIG environment variable or "/$HOME/.kube/config" if the variable is not set.`)
+	cmd.PersistentFlags().StringArrayVar(&o.KubectlArgs, "kubectl-arg", nil, `kubectl style connection flag applied to all requests to the Kubernetes API server (for example, "--insecure-skip-tls-verify" or "--request-timeout=30s"). Can be repeated. If not set, the flags listed in the KYMACTL_KUBECTL_ARGS environment variable are used.`)
+	cmd.PersistentFlags().StringVar(&o.MinikubeProfile, "minikube-profile", "", `Minikube profile of the local cluster, whose Docker daemon, IP and status are used. If not set, the profile recorded when the cluster was provisioned is used, or the profile named like the current kubeconfig context.`)
+	cmd.PersistentFlags().StringVar(&o.LogFormat, "log-format", "text", `Format of the structured logs of the commands executed by the CLI, the Docker builds and the steps (text or json). The "text" logs are displayed with --verbose. The "json" logs are written to stdout, one JSON object per line, and the regular output is displayed on stderr.`)
+	cmd.PersistentFlags().StringVar(&o.MetadataFile, "metadata-file", "", `YAML file with the namespaces, label selectors, and resource names the CLI uses to find the parts of a Kyma installation, as a list of entries for the Kyma release ranges given with "versions" (for example, ">=1.18.0"). The fields of an entry take precedence over the embedded metadata of the matching releases. Use it if a Kyma release labels its resources differently than the CLI expects.`)
+	cmd.PersistentFlags().StringVar(&o.ProfileDir, "profile-dir", "", `Directory with installation profiles, one YAML file per profile, which replace the embedded profiles of the same name or add new ones. Use "kyma defaults --write-dir" to get the embedded profiles as a starting point.`)
+	cmd.PersistentFlags().StringVar(&o.TraceFile, "trace-file", "", `Writes the timings of the invocation (such as the stages of the installation, the executed commands, and the checks of the installation state) to the given file in the Chrome trace event format, which can be opened with chrome://tracing or https://ui.perfetto.dev.`)
 	cmd.PersistentFlags().BoolP("help", "h", false, "Displays help for the command.")
+	for _, name := range []string{"kubeconfig", "metadata-file", "trace-file"} {
+		if err := cmd.MarkPersistentFlagFilename(name); err != nil {
+			panic(err)
+		}
+	}
+	if err := cmd.MarkPersistentFlagDirname("profile-dir"); err != nil {
+		panic(err)
+	}
 
 	//Alpha commands
 	alphaCmd := alpha.NewCmd()
@@ -78,15 +154,26 @@ Kyma CLI allows you to install, test, and manage Kyma.
 	gardenerCmd.AddCommand(aws.NewCmd(aws.NewOptions(o)))
 	provisionCmd.AddCommand(gardenerCmd)
 
+	installCmd := install.NewCmd(install.NewOptions(o))
+	installCmd.AddCommand(profiles.NewCmd(profiles.NewOptions(o)), installCleanup.NewCmd(installCleanup.NewOptions(o)))
+
 	cmd.AddCommand(
 		alphaCmd,
 		version.NewCmd(version.NewOptions(o)),
 		completion.NewCmd(),
-		install.NewCmd(install.NewOptions(o)),
+		installCmd,
 		provisionCmd,
 		console.NewCmd(console.NewOptions(o)),
 		upgrade.NewCmd(upgrade.NewOptions(o)),
 		create.NewCmd(o),
+		releases.NewCmd(releases.NewOptions(o)),
+		kymaStatus.NewCmd(kymaStatus.NewOptions(o)),
+		summary.NewCmd(summary.NewOptions(o)),
+		defaults.NewCmd(defaults.NewOptions(o)),
+		components.NewCmd(components.NewOptions(o)),
+		diagnostics.NewCmd(diagnostics.NewOptions(o)),
+		applyconfig.NewCmd(applyconfig.NewOptions(o)),
+		updatecli.NewCmd(updatecli.NewOptions(o)),
 	)
 
 	testCmd := test.NewCmd()
@@ -99,11 +186,89 @@ Kyma CLI allows you to install, test, and manage Kyma.
 	testCmd.AddCommand(testRunCmd, testStatusCmd, testDeleteCmd, testListCmd, testDefsCmd, testLogsCmd)
 	cmd.AddCommand(testCmd)
 
+	waitCmd := wait.NewCmd()
+	waitCmd.AddCommand(waitPod.NewCmd(waitPod.NewOptions(o)), waitInstallation.NewCmd(waitInstallation.NewOptions(o)))
+	cmd.AddCommand(waitCmd)
+
+	importCmd := imports.NewCmd()
+	importCmd.AddCommand(importCerts.NewCmd(importCerts.NewOptions(o)))
+	cmd.AddCommand(importCmd)
+
+	helmCmd := helm.NewCmd()
+	helmCmd.AddCommand(helmSetup.NewCmd(helmSetup.NewOptions(o)))
+	cmd.AddCommand(helmCmd)
+
 	cmd.AddCommand(
 		initial.NewCmd(o),
 		apply.NewCmd(o),
 		sync.NewCmd(o),
 	)
 
+	pluginCmd := plugin.NewCmd()
+	pluginCmd.AddCommand(pluginList.NewCmd(pluginList.NewOptions(o)))
+	cmd.AddCommand(pluginCmd)
+
 	return cmd
 }
+
+// configureLogs enables the structured logs of the CLI in the format given by --log-format
+func configureLogs(o *cli.Options) error {
+	switch o.LogFormat {
+	case "text":
+		if o.Verbose {
+			logger.Set(logger.NewHuman(os.Stderr))
+		} else {
+			logger.Set(logger.Discard)
+		}
+	case "json":
+		logger.Set(logger.NewJSON(os.Stdout))
+		// stdout only holds the JSON logs, the output for users is still displayed in case the flag is used interactively
+		o.Factory.Out = os.Stderr
+	default:
+		return fmt.Errorf("unsupported log format '%s', use text or json", o.LogFormat)
+	}
+	return nil
+}
+
+// configureTrace starts the root span of the invocation if --trace-file is set, the spans are written by trace.Flush once the command returns
+func configureTrace(o *cli.Options, c *cobra.Command) {
+	if o.TraceFile == "" {
+		return
+	}
+	trace.Enable(o.TraceFile)
+	trace.Start(c.CommandPath(), trace.Attributes{"flags": setFlags(c)})
+}
+
+// setFlags returns the names of the flags given to the command. The values are left out, because flags like --password hold secrets.
+func setFlags(c *cobra.Command) []string {
+	names := []string{}
+	c.Flags().Visit(func(f *pflag.Flag) {
+		names = append(names, f.Name)
+	})
+	return names
+}
+
+// configureKube applies the global Kubernetes settings to all clients and tools used by the CLI
+func configureKube(o *cli.Options) error {
+	// Tools executed by the CLI (such as minikube or k3d) must use the same kubeconfig as the CLI
+	if err := kube.ExportConfigPath(o.KubeconfigPath); err != nil {
+		return err
+	}
+
+	args := o.KubectlArgs
+	if len(args) == 0 {
+		args = strings.Fields(os.Getenv(kubectlArgsEnv))
+	}
+	flags, err := kube.SetKubectlArgs(args)
+	if err != nil {
+		return err
+	}
+	// only flag names are logged, as values might contain credentials (e.g. --token)
+	if o.Verbose && len(flags) > 0 {
+		fmt.Fprintf(o.Stdout(), "Applying kubectl arguments to all Kubernetes requests: %s\n", strings.Join(flags, ", "))
+	}
+	if o.Verbose && len(o.EnvFlags) > 0 {
+		fmt.Fprintf(o.Stdout(), "Applying flags from environment variables: %s\n", strings.Join(o.EnvFlagNames(), ", "))
+	}
+	return nil
+}
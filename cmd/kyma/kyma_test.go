@@ -2,9 +2,11 @@ package kyma
 
 import (
 	"io/ioutil"
+	"os"
 	"testing"
 
 	"github.com/kyma-project/cli/internal/cli"
+	"github.com/kyma-project/cli/internal/logger"
 	"github.com/stretchr/testify/require"
 )
 
@@ -19,13 +21,19 @@ func TestKymaFlags(t *testing.T) {
 	require.Equal(t, "", o.KubeconfigPath, "kubeconfig path must be empty when default")
 	require.False(t, o.Verbose, "Verbose flag must be false")
 	require.False(t, o.NonInteractive, "Non-interactive flag must be false")
+	require.Equal(t, "text", o.LogFormat, "Log format must be text when default")
 
 	// test passing flags
-	err := c.ParseFlags([]string{"--kubeconfig=/some/file", "--non-interactive=true", "--verbose=true"})
+	err := c.ParseFlags([]string{"--kubeconfig=/some/file", "--non-interactive=true", "--verbose=true", "--kubectl-arg=--insecure-skip-tls-verify", "--kubectl-arg=--request-timeout=30s", "--log-format=json", "--metadata-file=/some/metadata.yaml", "--profile-dir=/some/profiles", "--github-token=ghp_s3cr3t"})
 	require.NoError(t, err)
 	require.Equal(t, "/some/file", o.KubeconfigPath, "kubeconfig path must be the same as the flag provided")
 	require.True(t, o.Verbose, "Verbose flag must be true")
 	require.True(t, o.NonInteractive, "Non-interactive flag must be true")
+	require.Equal(t, []string{"--insecure-skip-tls-verify", "--request-timeout=30s"}, o.KubectlArgs, "kubectl args must be the same as the flags provided")
+	require.Equal(t, "json", o.LogFormat, "Log format must be the same as the flag provided")
+	require.Equal(t, "/some/metadata.yaml", o.MetadataFile, "Metadata file must be the same as the flag provided")
+	require.Equal(t, "/some/profiles", o.ProfileDir, "Profile directory must be the same as the flag provided")
+	require.Equal(t, "ghp_s3cr3t", o.GitHubToken, "GitHub token must be the same as the flag provided")
 }
 
 func TestKymaSubcommands(t *testing.T) {
@@ -38,5 +46,35 @@ func TestKymaSubcommands(t *testing.T) {
 
 	sub := c.Commands()
 
-	require.Equal(t, 13, len(sub), "Number of Kyma subcommands not as expected")
+	require.Equal(t, 25, len(sub), "Number of Kyma subcommands not as expected")
+}
+
+func TestConfigureLogs(t *testing.T) {
+	defer logger.Set(logger.Discard)
+
+	o := &cli.Options{LogFormat: "text"}
+	require.NoError(t, configureLogs(o))
+	require.False(t, logger.Enabled(), "Text logs must only be enabled with --verbose")
+	require.Equal(t, os.Stdout, o.Stdout())
+
+	stdout := os.Stdout
+	o = &cli.Options{LogFormat: "json"}
+	require.NoError(t, configureLogs(o))
+	require.True(t, logger.Enabled())
+	require.Equal(t, os.Stderr, o.Stdout(), "The output of the commands must move to stderr, stdout holds the JSON logs")
+	require.Equal(t, stdout, os.Stdout, "The standard output must not be replaced")
+
+	require.Error(t, configureLogs(&cli.Options{LogFormat: "xml"}))
+}
+
+func TestSetFlags(t *testing.T) {
+	c := NewCmd(&cli.Options{})
+	c.SetOutput(ioutil.Discard)
+	install, _, err := c.Find([]string{"install"})
+	require.NoError(t, err)
+	require.NoError(t, install.ParseFlags([]string{"--password", "s3cr3t", "--non-interactive"}))
+
+	flags := setFlags(install)
+	require.ElementsMatch(t, []string{"password", "non-interactive"}, flags)
+	require.NotContains(t, flags, "s3cr3t")
 }
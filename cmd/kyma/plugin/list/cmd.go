@@ -0,0 +1,65 @@
+package list
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	pluginCmd "github.com/kyma-project/cli/cmd/kyma/plugin"
+	"github.com/kyma-project/cli/internal/cli"
+	"github.com/kyma-project/cli/internal/nice"
+	"github.com/kyma-project/cli/internal/plugin"
+	"github.com/spf13/cobra"
+)
+
+type command struct {
+	opts *Options
+	cli.Command
+}
+
+//NewCmd creates a new plugin list command
+func NewCmd(o *Options) *cobra.Command {
+	cmd := command{
+		Command: cli.Command{Options: o.Options},
+		opts:    o,
+	}
+
+	cobraCmd := &cobra.Command{
+		Use:   "list",
+		Short: "Lists the Kyma CLI plugins found on the PATH.",
+		Long: `Use this command to list all executables on your PATH that provide a Kyma CLI plugin.
+The command warns about plugins that are never run because a built-in command or another plugin with the same name takes precedence.`,
+		RunE:    func(c *cobra.Command, _ []string) error { return cmd.Run(c.Root()) },
+		Aliases: []string{"l"},
+	}
+	return cobraCmd
+}
+
+//Run runs the command
+func (cmd *command) Run(root *cobra.Command) error {
+	plugins := plugin.Find(os.Getenv("PATH"))
+	if len(plugins) == 0 {
+		fmt.Fprintln(cmd.Stdout(), "No plugins found")
+		return nil
+	}
+
+	writer := nice.NewTableWriter([]string{"NAME", "PATH"}, cmd.Stdout())
+	for _, p := range plugins {
+		writer.Append([]string{p.Name, p.Path})
+	}
+	writer.Render()
+
+	printWarnings(root, plugins, os.Stderr)
+	return nil
+}
+
+func printWarnings(root *cobra.Command, plugins []plugin.Plugin, w io.Writer) {
+	for _, p := range plugins {
+		if pluginCmd.BuiltIn(root, p.Name) {
+			fmt.Fprintf(w, "Warning: %s is overshadowed by the built-in command \"kyma %s\" and is never run\n", p.Path, p.Name)
+		}
+		for _, s := range p.Shadowed {
+			fmt.Fprintf(w, "Warning: %s is overshadowed by %s found earlier on the PATH and is never run\n", s, p.Path)
+		}
+	}
+}
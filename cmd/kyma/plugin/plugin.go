@@ -0,0 +1,168 @@
+package plugin
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+
+	"github.com/kyma-project/cli/internal/cli"
+	"github.com/kyma-project/cli/internal/kube"
+	"github.com/kyma-project/cli/internal/plugin"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// pathAnnotation marks the commands provided by plugins and holds the path of the plugin executable
+const pathAnnotation = "kyma-cli/plugin-path"
+
+//NewCmd creates a new plugin command
+func NewCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "plugin",
+		Short: "Provides utilities for interacting with plugins.",
+		Long: `Use this command to interact with Kyma CLI plugins.
+
+A plugin is any executable on your PATH named "` + plugin.Prefix + `<name>". Kyma CLI provides it as the "kyma <name>" command and passes all arguments, the standard input and outputs to the plugin.
+The global Kyma CLI settings are passed in the following environment variables:
+` + "`" + plugin.EnvKubeconfig + "`, `" + plugin.EnvContext + "`, `" + plugin.EnvKubectlArgs + "`, `" + plugin.EnvVerbose + "`, `" + plugin.EnvCI + "`, and `" + plugin.EnvNonInteractive + "`" + `.
+Global flags must be placed before the plugin arguments, for example "kyma --verbose <name> <args>".
+If a plugin has the same name as a built-in command, the built-in command is used.
+`,
+	}
+	return cmd
+}
+
+// AddPlugin adds the command of the plugin called by the given CLI arguments to the root command.
+// The PATH is only searched if the arguments do not call a built-in command, "kyma plugin list" warns about the plugins overshadowed by them.
+func AddPlugin(root *cobra.Command, o *cli.Options, args []string) {
+	addPlugin(root, o, args, os.Getenv("PATH"))
+}
+
+func addPlugin(root *cobra.Command, o *cli.Options, args []string, path string) {
+	n, err := globalFlagsEnd(root.PersistentFlags(), args)
+	if err != nil || n >= len(args) {
+		return
+	}
+	name := args[n]
+	if strings.HasPrefix(name, "-") || BuiltIn(root, name) {
+		return
+	}
+	if p, ok := plugin.Lookup(name, path); ok {
+		root.AddCommand(newPluginCmd(p, o))
+	}
+}
+
+// BuiltIn checks if the root command has a built-in subcommand or alias with the given name
+func BuiltIn(root *cobra.Command, name string) bool {
+	if name == "help" {
+		return true
+	}
+	for _, c := range root.Commands() {
+		if _, isPlugin := c.Annotations[pathAnnotation]; isPlugin {
+			continue
+		}
+		if c.Name() == name || c.HasAlias(name) {
+			return true
+		}
+	}
+	return false
+}
+
+func newPluginCmd(p plugin.Plugin, o *cli.Options) *cobra.Command {
+	return &cobra.Command{
+		Use:                p.Name,
+		Short:              fmt.Sprintf("Runs the %s plugin.", p.Name),
+		Long:               fmt.Sprintf("Use this command to run the plugin installed at %s.", p.Path),
+		Annotations:        map[string]string{pathAnnotation: p.Path},
+		DisableFlagParsing: true,
+		RunE: func(cobraCmd *cobra.Command, args []string) error {
+			// flag parsing is left to the plugin, only the global flags in front of its arguments are used by the CLI
+			args, err := parseGlobalFlags(cobraCmd.Root().PersistentFlags(), args)
+			if err != nil {
+				return err
+			}
+			// the flags are not parsed by cobra for plugins, so the environment variables are applied to the global flags here
+			if err := o.ApplyEnvFlags(cobraCmd.Root().PersistentFlags(), os.LookupEnv); err != nil {
+				return err
+			}
+			if err := kube.ExportConfigPath(o.KubeconfigPath); err != nil {
+				return err
+			}
+			if len(o.KubectlArgs) > 0 {
+				if _, err := kube.SetKubectlArgs(o.KubectlArgs); err != nil {
+					return err
+				}
+			}
+
+			err = p.Run(args, pluginEnv(o))
+			if exitErr, ok := err.(*exec.ExitError); ok {
+				// the plugin reports its own errors, only its exit code is kept
+				cobraCmd.SilenceErrors = true
+				return &cli.ExitError{Code: exitErr.ExitCode(), Err: err}
+			}
+			return err
+		},
+	}
+}
+
+// parseGlobalFlags parses the global flags at the start of the arguments and returns the remaining arguments
+func parseGlobalFlags(fs *pflag.FlagSet, args []string) ([]string, error) {
+	n, err := globalFlagsEnd(fs, args)
+	if err != nil {
+		return nil, err
+	}
+	if err := fs.Parse(args[:n]); err != nil {
+		return nil, err
+	}
+	return args[n:], nil
+}
+
+// globalFlagsEnd returns the number of arguments taken by the global flags at the start of the arguments
+func globalFlagsEnd(fs *pflag.FlagSet, args []string) (int, error) {
+	n := 0
+	for n < len(args) {
+		arg := args[n]
+		if !strings.HasPrefix(arg, "-") || arg == "-" || arg == "--" {
+			break
+		}
+		name := strings.SplitN(strings.TrimLeft(arg, "-"), "=", 2)[0]
+		var f *pflag.Flag
+		if strings.HasPrefix(arg, "--") {
+			f = fs.Lookup(name)
+		} else if len(name) == 1 {
+			f = fs.ShorthandLookup(name)
+		}
+		// the help flag and all unknown flags belong to the plugin
+		if f == nil || f.Name == "help" {
+			break
+		}
+		n++
+		if !strings.Contains(arg, "=") && f.NoOptDefVal == "" {
+			n++
+		}
+	}
+	if n > len(args) {
+		return 0, fmt.Errorf("flag needs an argument: %s", args[len(args)-1])
+	}
+	return n, nil
+}
+
+func pluginEnv(o *cli.Options) map[string]string {
+	kubeconfig := o.KubeconfigPath
+	if kubeconfig == "" {
+		kubeconfig = os.Getenv("KUBECONFIG")
+	}
+	env := map[string]string{
+		plugin.EnvKubeconfig:     kubeconfig,
+		plugin.EnvContext:        kube.ContextOverride(),
+		plugin.EnvVerbose:        strconv.FormatBool(o.Verbose),
+		plugin.EnvCI:             strconv.FormatBool(o.CI),
+		plugin.EnvNonInteractive: strconv.FormatBool(o.NonInteractive),
+	}
+	if len(o.KubectlArgs) > 0 {
+		env[plugin.EnvKubectlArgs] = strings.Join(o.KubectlArgs, " ")
+	}
+	return env
+}
@@ -0,0 +1,102 @@
+package plugin
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/kyma-project/cli/internal/cli"
+	"github.com/kyma-project/cli/internal/plugin"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseGlobalFlags(t *testing.T) {
+	t.Parallel()
+	o := &cli.Options{}
+	root := &cobra.Command{Use: "kyma"}
+	root.PersistentFlags().BoolVarP(&o.Verbose, "verbose", "v", false, "")
+	root.PersistentFlags().StringVar(&o.KubeconfigPath, "kubeconfig", "", "")
+	root.PersistentFlags().BoolP("help", "h", false, "")
+
+	args, err := parseGlobalFlags(root.PersistentFlags(), []string{"-v", "--kubeconfig", "/some/file", "get", "--verbose"})
+	require.NoError(t, err)
+	require.Equal(t, []string{"get", "--verbose"}, args)
+	require.True(t, o.Verbose)
+	require.Equal(t, "/some/file", o.KubeconfigPath)
+
+	// unknown and help flags belong to the plugin
+	args, err = parseGlobalFlags(root.PersistentFlags(), []string{"--kubeconfig=/other", "--plugin-flag", "-v", "-h"})
+	require.NoError(t, err)
+	require.Equal(t, []string{"--plugin-flag", "-v", "-h"}, args)
+	require.Equal(t, "/other", o.KubeconfigPath)
+
+	_, err = parseGlobalFlags(root.PersistentFlags(), []string{"--kubeconfig"})
+	require.Error(t, err)
+}
+
+func TestBuiltIn(t *testing.T) {
+	t.Parallel()
+	root := &cobra.Command{Use: "kyma"}
+	root.AddCommand(&cobra.Command{Use: "install", Aliases: []string{"i"}})
+	root.AddCommand(newPluginCmd(plugin.Plugin{Name: "foo", Path: "/bin/kymactl-foo"}, &cli.Options{}))
+
+	require.True(t, BuiltIn(root, "install"))
+	require.True(t, BuiltIn(root, "i"))
+	require.True(t, BuiltIn(root, "help"))
+	require.False(t, BuiltIn(root, "foo"), "plugins are not built-in commands")
+	require.False(t, BuiltIn(root, "bar"))
+}
+
+func TestAddPlugin(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the test plugins are shell scripts")
+	}
+	t.Parallel()
+	dir, err := ioutil.TempDir("", "plugins")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	for _, name := range []string{"kymactl-foo", "kymactl-install"} {
+		require.NoError(t, ioutil.WriteFile(filepath.Join(dir, name), []byte("#!/bin/sh\nexit 3\n"), 0755))
+	}
+
+	newRoot := func() (*cobra.Command, *cli.Options) {
+		o := &cli.Options{}
+		root := &cobra.Command{Use: "kyma"}
+		root.PersistentFlags().StringVar(&o.KubeconfigPath, "kubeconfig", "", "")
+		root.AddCommand(&cobra.Command{Use: "install"})
+		return root, o
+	}
+	names := func(root *cobra.Command) []string {
+		var names []string
+		for _, c := range root.Commands() {
+			names = append(names, c.Name())
+		}
+		return names
+	}
+
+	// only the called plugin is added
+	root, o := newRoot()
+	addPlugin(root, o, []string{"--kubeconfig", "/some/file", "foo", "bar"}, dir)
+	require.ElementsMatch(t, []string{"install", "foo"}, names(root))
+
+	// built-in commands take precedence
+	root, o = newRoot()
+	addPlugin(root, o, []string{"install"}, dir)
+	require.ElementsMatch(t, []string{"install"}, names(root))
+
+	root, o = newRoot()
+	addPlugin(root, o, []string{"--kubeconfig"}, dir)
+	addPlugin(root, o, []string{"missing"}, dir)
+	require.ElementsMatch(t, []string{"install"}, names(root))
+
+	// the exit code of the plugin is returned in the error
+	root, o = newRoot()
+	addPlugin(root, o, []string{"foo"}, dir)
+	root.SetArgs([]string{"foo"})
+	err = root.Execute()
+	require.Error(t, err)
+	require.Equal(t, 3, cli.ExitCode(err))
+}
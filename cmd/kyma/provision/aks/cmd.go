@@ -6,8 +6,10 @@ import (
 	"io/ioutil"
 	"log"
 	"strings"
+	"time"
 
 	"github.com/avast/retry-go"
+	"github.com/kyma-project/cli/internal/diagnostics"
 	"github.com/kyma-project/cli/internal/kube"
 
 	hf "github.com/kyma-incubator/hydroform/provision"
@@ -33,22 +35,29 @@ func NewCmd(o *Options) *cobra.Command {
 		Use:   "aks",
 		Short: "Provisions an Azure Kubernetes Service (AKS) cluster on Azure.",
 		Long: `Use this command to provision an AKS cluster on Azure for Kyma installation. Use the flags to specify cluster details. 
-	NOTE: To provision and access the provisioned cluster, make sure you get authenticated by using the Azure CLI. To do so,run ` + "`az login`" + ` and log in with your Azure credentials.`,
+	NOTE: To provision and access the provisioned cluster, make sure you get authenticated by using the Azure CLI. To do so,run ` + "`az login`" + ` and log in with your Azure credentials.
+	If the cluster already exists, provisioning is skipped and only the kubeconfig is imported. After provisioning, the command verifies that the cluster meets the Kyma requirements (Kubernetes version, node resources).
+	To delete the cluster, run the command with the same flags and ` + "`--delete`" + `.`,
 
 		RunE: func(_ *cobra.Command, _ []string) error { return c.Run() },
 	}
 
 	cmd.Flags().StringVarP(&o.Name, "name", "n", "", "Name of the AKS cluster to provision. (required)")
 	cmd.Flags().StringVarP(&o.Project, "project", "p", "", "Name of the Azure Resource Group where you provision the AKS cluster. (required)")
+	cmd.Flags().StringVar(&o.Project, "resource-group", "", "Name of the Azure Resource Group where you provision the AKS cluster. Same as --project.")
 	cmd.Flags().StringVarP(&o.CredentialsFile, "credentials", "c", "", "Path to the TOML file containing the Azure Subscription ID (SUBSCRIPTION_ID), Tenant ID (TENANT_ID), Client ID (CLIENT_ID) and Client Secret (CLIENT_SECRET). (required)")
 	cmd.Flags().StringVarP(&o.KubernetesVersion, "kube-version", "k", "1.19.7", "Kubernetes version of the cluster.")
 	cmd.Flags().StringVarP(&o.Location, "location", "l", "westeurope", "Location of the cluster.")
 	cmd.Flags().StringVarP(&o.MachineType, "type", "t", "Standard_D4_v3", "Machine type used for the cluster.")
+	cmd.Flags().StringVar(&o.MachineType, "node-size", "Standard_D4_v3", "Machine type used for the cluster. Same as --type.")
 	cmd.Flags().IntVar(&o.DiskSizeGB, "disk-size", 50, "Disk size (in GB) of the cluster.")
 	cmd.Flags().IntVar(&o.NodeCount, "nodes", 3, "Number of cluster nodes.")
 	// Temporary disabled flag. To be enabled when hydroform supports TF modules
 	//cmd.Flags().StringSliceVarP(&o.Extra, "extra", "e", nil, "Provide one or more arguments of the form NAME=VALUE to add extra configurations.")
 	cmd.Flags().UintVar(&o.Attempts, "attempts", 3, "Maximum number of attempts to provision the cluster.")
+	cmd.Flags().BoolVar(&o.Delete, "delete", false, "Deletes the AKS cluster and removes it from the kubeconfig.")
+	cmd.Flags().BoolVar(&o.DiagnosticsOnFailure, "diagnostics-on-failure", false, `Displays the diagnostics of the environment, as collected by "kyma diagnostics", if the cluster does not meet the Kyma requirements.`)
+	cli.MarkPathFlags(cmd, "credentials")
 
 	return cmd
 }
@@ -68,40 +77,95 @@ func (c *command) Run() error {
 		// discard all the noise from terraform logs if not verbose
 		log.SetOutput(ioutil.Discard)
 	}
-	s := c.NewStep("Provisioning AKS cluster")
 	home, err := files.KymaHome()
 	if err != nil {
-		s.Failure()
 		return err
 	}
 
-	err = retry.Do(
-		func() error {
-			cluster, err = hf.Provision(cluster, provider, types.WithDataDir(home), types.Persistent())
+	if c.opts.Delete {
+		return c.deprovision(cluster, provider, home)
+	}
+
+	s := c.NewStep("Provisioning AKS cluster")
+	// provisioning an existing cluster again is skipped, so that the command can be re-run after a failure
+	if status, err := hf.Status(cluster, provider, types.WithDataDir(home), types.Persistent()); err == nil && status != nil && status.Phase == types.Provisioned {
+		s.Successf("AKS cluster '%s' already exists", cluster.Name)
+	} else {
+		err = retry.Do(
+			func() error {
+				cluster, err = hf.Provision(cluster, provider, types.WithDataDir(home), types.Persistent())
+				return err
+			},
+			retry.Attempts(c.opts.Attempts), retry.LastErrorOnly(!c.opts.Verbose))
+
+		if err != nil {
+			s.Failure()
 			return err
-		},
-		retry.Attempts(c.opts.Attempts), retry.LastErrorOnly(!c.opts.Verbose))
+		}
+		s.Success()
+	}
 
+	s = c.NewStep("Importing kubeconfig")
+	kubeconfig, err := hf.Credentials(cluster, provider, types.WithDataDir(home), types.Persistent())
 	if err != nil {
 		s.Failure()
 		return err
 	}
+
+	if err := kube.AppendConfig(kubeconfig, c.opts.KubeconfigPath); err != nil {
+		s.Failure()
+		return err
+	}
 	s.Success()
 
-	s = c.NewStep("Importing kubeconfig")
-	kubeconfig, err := hf.Credentials(cluster, provider, types.WithDataDir(home), types.Persistent())
-	if err != nil {
+	s = c.NewStep("Verifying the cluster meets the Kyma requirements")
+	if c.K8s, err = kube.NewFromConfig("", c.opts.KubeconfigPath); err != nil {
 		s.Failure()
 		return err
 	}
+	if err := kube.CheckRequirements(c.K8s.Static(), kube.KymaRequirements); err != nil {
+		s.Failure()
+		if c.opts.DiagnosticsOnFailure {
+			fmt.Fprint(c.Stdout(), diagnostics.Collect(diagnostics.Options{
+				CLIVersion:     cli.Version,
+				KubeconfigPath: c.opts.KubeconfigPath,
+				Timeout:        10 * time.Second,
+			}))
+		}
+		return err
+	}
+	s.Success()
 
-	if err := kube.AppendConfig(kubeconfig, c.opts.KubeconfigPath); err != nil {
+	fmt.Fprintf(c.Stdout(), "\nAKS cluster installed\nKubectl correctly configured: pointing to %s\n\nHappy AKS-ing! :)\n", cluster.Name)
+	return nil
+}
+
+func (c *command) deprovision(cluster *types.Cluster, provider *types.Provider, home string) error {
+	s := c.NewStep("Deleting AKS cluster")
+	// the kubeconfig is needed to remove the cluster from the local kubeconfig afterwards
+	kubeconfig, credentialsErr := hf.Credentials(cluster, provider, types.WithDataDir(home), types.Persistent())
+
+	err := retry.Do(
+		func() error {
+			return hf.Deprovision(cluster, provider, types.WithDataDir(home), types.Persistent())
+		},
+		retry.Attempts(c.opts.Attempts), retry.LastErrorOnly(!c.opts.Verbose))
+	if err != nil {
 		s.Failure()
 		return err
 	}
 	s.Success()
 
-	fmt.Printf("\nAKS cluster installed\nKubectl correctly configured: pointing to %s\n\nHappy AKS-ing! :)\n", cluster.Name)
+	if credentialsErr == nil {
+		s = c.NewStep("Removing cluster from kubeconfig")
+		if err := kube.RemoveConfig(kubeconfig, c.opts.KubeconfigPath); err != nil {
+			s.Failure()
+			return err
+		}
+		s.Success()
+	}
+
+	fmt.Fprintf(c.Stdout(), "\nAKS cluster %s deleted\n", cluster.Name)
 	return nil
 }
 
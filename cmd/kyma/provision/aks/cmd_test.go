@@ -40,6 +40,7 @@ func TestProvisionAKSFlags(t *testing.T) {
 		// Temporary disable flag. To be enabled when hydroform supports TF modules
 		//"--extra", "VAR1=VALUE1,VAR2=VALUE2",
 		"--attempts", "2",
+		"--diagnostics-on-failure",
 	})
 	require.NoError(t, err, "Parsing flags should not return an error")
 	require.Equal(t, "my-cluster", o.Name, "The parsed value for the name flag not as expected.")
@@ -53,6 +54,19 @@ func TestProvisionAKSFlags(t *testing.T) {
 	// Temporary disable flag. To be enabled when hydroform supports TF modules
 	//require.Equal(t, []string{"VAR1=VALUE1", "VAR2=VALUE2"}, o.Extra, "The parsed value for the extra flag not as expected.")
 	require.Equal(t, uint(2), o.Attempts, "The parsed value for the attempts flag not as expected.")
+	require.True(t, o.DiagnosticsOnFailure, "The parsed value for the diagnostics-on-failure flag not as expected.")
+	require.False(t, o.Delete, "Default value for the delete flag not as expected.")
+
+	// test the flags named after the az CLI
+	err = c.ParseFlags([]string{
+		"--resource-group", "other-resource-group",
+		"--node-size", "Standard_D8_v3",
+		"--delete",
+	})
+	require.NoError(t, err, "Parsing flags should not return an error")
+	require.Equal(t, "other-resource-group", o.Project, "The parsed value for the resource-group flag not as expected.")
+	require.Equal(t, "Standard_D8_v3", o.MachineType, "The parsed value for the node-size flag not as expected.")
+	require.True(t, o.Delete, "The parsed value for the delete flag not as expected.")
 }
 
 func TestProvisionAKSSubcommands(t *testing.T) {
@@ -15,6 +15,9 @@ type Options struct {
 	NodeCount         int
 	Extra             []string
 	Attempts          uint
+	Delete            bool
+	// DiagnosticsOnFailure displays the diagnostics of the environment if the cluster does not meet the Kyma requirements
+	DiagnosticsOnFailure bool
 }
 
 //NewOptions creates options with default values
@@ -6,12 +6,14 @@ import (
 	"io/ioutil"
 	"log"
 	"strings"
+	"time"
 
 	"github.com/kyma-project/cli/internal/kube"
 
 	retry "github.com/avast/retry-go"
 	hf "github.com/kyma-incubator/hydroform/provision"
 	"github.com/kyma-incubator/hydroform/provision/types"
+	"github.com/kyma-project/cli/cmd/kyma/provision/gardener"
 	"github.com/kyma-project/cli/internal/cli"
 	"github.com/kyma-project/cli/internal/files"
 	"github.com/spf13/cobra"
@@ -35,7 +37,9 @@ func NewCmd(o *Options) *cobra.Command {
 		Long: `Use this command to provision Kubernetes clusters with Gardener on AWS for Kyma installation. 
 To successfully provision a cluster on AWS, you must first create a service account to pass its details as one of the command parameters. 
 Check the roles and create a service account using instructions at https://gardener.cloud/050-tutorials/content/howto/gardener_aws/.
-Use service account details to create a Secret and store it in Gardener.`,
+Use service account details to create a Secret and store it in Gardener.
+If the cluster already exists, provisioning is skipped and only the kubeconfig is imported.
+To delete the cluster, run the command with the same flags and ` + "`--delete`" + `.`,
 
 		RunE: func(_ *cobra.Command, _ []string) error { return c.Run() },
 	}
@@ -54,6 +58,9 @@ Use service account details to create a Secret and store it in Gardener.`,
 	cmd.Flags().IntVar(&o.ScalerMax, "scaler-max", 3, "Maximum autoscale value of the cluster.")
 	cmd.Flags().StringSliceVarP(&o.Extra, "extra", "e", nil, "One or more arguments provided as the `NAME=VALUE` key-value pairs to configure additional cluster settings. You can use this flag multiple times or enter the key-value pairs as a comma-separated list.")
 	cmd.Flags().UintVar(&o.Attempts, "attempts", 3, "Maximum number of attempts to provision the cluster.")
+	cmd.Flags().DurationVar(&o.Timeout, "timeout", 30*time.Minute, `Maximum time to wait until the cluster is reconciled or deleted by Gardener. Valid time units are "ns", "us" (or "µs"), "ms", "s", "m", "h".`)
+	cmd.Flags().BoolVar(&o.Delete, "delete", false, "Deletes the Gardener cluster and removes it from the kubeconfig.")
+	cli.MarkPathFlags(cmd, "credentials")
 
 	return cmd
 }
@@ -73,29 +80,37 @@ func (c *command) Run() error {
 		// discard all the noise from terraform logs if not verbose
 		log.SetOutput(ioutil.Discard)
 	}
-	s := c.NewStep("Provisioning Gardener cluster on AWS")
-
 	home, err := files.KymaHome()
 	if err != nil {
-		s.Failure()
 		return err
 	}
+	ops := []types.Option{types.WithDataDir(home), types.Persistent(), types.WithTimeouts(&types.Timeouts{Create: c.opts.Timeout, Update: c.opts.Timeout, Delete: c.opts.Timeout})}
 
-	err = retry.Do(
-		func() error {
-			cluster, err = hf.Provision(cluster, provider, types.WithDataDir(home), types.Persistent())
-			return err
-		},
-		retry.Attempts(c.opts.Attempts), retry.LastErrorOnly(!c.opts.Verbose))
+	if c.opts.Delete {
+		return gardener.Deprovision(&c.Command, "AWS", cluster, provider, c.opts.Attempts, ops)
+	}
 
-	if err != nil {
-		s.Failure()
-		return err
+	s := c.NewStep("Provisioning Gardener cluster on AWS")
+	// provisioning an existing cluster again is skipped, so that the command can be re-run after a failure
+	if status, err := hf.Status(cluster, provider, ops...); err == nil && status != nil && status.Phase == types.Provisioned {
+		s.Successf("Gardener cluster '%s' already exists", cluster.Name)
+	} else {
+		err = retry.Do(
+			func() error {
+				cluster, err = hf.Provision(cluster, provider, ops...)
+				return err
+			},
+			retry.Attempts(c.opts.Attempts), retry.LastErrorOnly(!c.opts.Verbose))
+
+		if err != nil {
+			s.Failure()
+			return err
+		}
+		s.Success()
 	}
-	s.Success()
 
 	s = c.NewStep("Importing kubeconfig")
-	kubeconfig, err := hf.Credentials(cluster, provider, types.WithDataDir(home), types.Persistent())
+	kubeconfig, err := hf.Credentials(cluster, provider, ops...)
 	if err != nil {
 		s.Failure()
 		return err
@@ -107,7 +122,7 @@ func (c *command) Run() error {
 	}
 	s.Success()
 
-	fmt.Printf("\nGardener cluster installed\nKubectl correctly configured: pointing to %s\n\nHappy Garden-ing! :)\n", cluster.Name)
+	fmt.Fprintf(c.Stdout(), "\nGardener cluster installed\nKubectl correctly configured: pointing to %s\n\nHappy Garden-ing! :)\n", cluster.Name)
 	return nil
 }
 
@@ -2,6 +2,7 @@ package az
 
 import (
 	"testing"
+	"time"
 
 	"github.com/kyma-incubator/hydroform/provision/types"
 	"github.com/kyma-project/cli/internal/cli"
@@ -29,6 +30,8 @@ func TestProvisionGardenerAzureFlags(t *testing.T) {
 	require.Equal(t, 3, o.ScalerMax, "Default value for the scaler-max flag not as expected.")
 	require.Empty(t, o.Extra, "Default value for the extra flag not as expected.")
 	require.Equal(t, uint(3), o.Attempts, "Default value for the attempts flag not as expected.")
+	require.Equal(t, 30*time.Minute, o.Timeout, "Default value for the timeout flag not as expected.")
+	require.False(t, o.Delete, "Default value for the delete flag not as expected.")
 
 	// test passing flags
 	err := c.ParseFlags([]string{
@@ -46,6 +49,8 @@ func TestProvisionGardenerAzureFlags(t *testing.T) {
 		"--scaler-max", "99",
 		"--extra", "VAR1=VALUE1,VAR2=VALUE2",
 		"--attempts", "2",
+		"--timeout", "45m",
+		"--delete",
 	})
 
 	require.NoError(t, err, "Parsing flags should not return an error")
@@ -63,6 +68,8 @@ func TestProvisionGardenerAzureFlags(t *testing.T) {
 	require.Equal(t, 99, o.ScalerMax, "The parsed value for the scaler-max flag not as expected.")
 	require.Equal(t, []string{"VAR1=VALUE1", "VAR2=VALUE2"}, o.Extra, "The parsed value for the extra flag not as expected.")
 	require.Equal(t, uint(2), o.Attempts, "The parsed value for the attempts flag not as expected.")
+	require.Equal(t, 45*time.Minute, o.Timeout, "The parsed value for the timeout flag not as expected.")
+	require.True(t, o.Delete, "The parsed value for the delete flag not as expected.")
 }
 
 func TestProvisionGardenerAzureSubcommands(t *testing.T) {
@@ -1,6 +1,10 @@
 package az
 
-import "github.com/kyma-project/cli/internal/cli"
+import (
+	"time"
+
+	"github.com/kyma-project/cli/internal/cli"
+)
 
 type Options struct {
 	*cli.Options
@@ -19,6 +23,8 @@ type Options struct {
 	ScalerMax         int
 	Extra             []string
 	Attempts          uint
+	Timeout           time.Duration
+	Delete            bool
 }
 
 //NewOptions creates options with default values
@@ -0,0 +1,42 @@
+package gardener
+
+import (
+	"fmt"
+
+	retry "github.com/avast/retry-go"
+	hf "github.com/kyma-incubator/hydroform/provision"
+	"github.com/kyma-incubator/hydroform/provision/types"
+	"github.com/kyma-project/cli/internal/cli"
+	"github.com/kyma-project/cli/internal/kube"
+)
+
+// Deprovision deletes the Gardener cluster for the --delete flag of the provider subcommands, e.g. "AWS",
+// and removes the cluster from the kubeconfig
+func Deprovision(c *cli.Command, providerName string, cluster *types.Cluster, provider *types.Provider, attempts uint, ops []types.Option) error {
+	s := c.NewStep(fmt.Sprintf("Deleting Gardener cluster on %s", providerName))
+	// the kubeconfig is needed to remove the cluster from the local kubeconfig afterwards
+	kubeconfig, credentialsErr := hf.Credentials(cluster, provider, ops...)
+
+	err := retry.Do(
+		func() error {
+			return hf.Deprovision(cluster, provider, ops...)
+		},
+		retry.Attempts(attempts), retry.LastErrorOnly(!c.Verbose))
+	if err != nil {
+		s.Failure()
+		return err
+	}
+	s.Success()
+
+	if credentialsErr == nil {
+		s = c.NewStep("Removing cluster from kubeconfig")
+		if err := kube.RemoveConfig(kubeconfig, c.KubeconfigPath); err != nil {
+			s.Failure()
+			return err
+		}
+		s.Success()
+	}
+
+	fmt.Fprintf(c.Stdout(), "\nGardener cluster %s deleted\n", cluster.Name)
+	return nil
+}
@@ -49,6 +49,7 @@ NOTE: To access the provisioned cluster, make sure you get authenticated by Goog
 	// Temporary disabled flag. To be enabled when hydroform supports TF modules
 	//cmd.Flags().StringSliceVarP(&o.Extra, "extra", "e", nil, "Provide one or more arguments of the form NAME=VALUE to add extra configurations.")
 	cmd.Flags().UintVar(&o.Attempts, "attempts", 3, "Maximum number of attempts to provision the cluster.")
+	cli.MarkPathFlags(cmd, "credentials")
 
 	return cmd
 }
@@ -104,7 +105,7 @@ func (c *command) Run() error {
 	}
 	s.Success()
 
-	fmt.Printf("\nGKE cluster installed\nKubectl correctly configured: pointing to %s\n\nHappy GKE-ing! :)\n", cluster.Name)
+	fmt.Fprintf(c.Stdout(), "\nGKE cluster installed\nKubectl correctly configured: pointing to %s\n\nHappy GKE-ing! :)\n", cluster.Name)
 	return nil
 }
 
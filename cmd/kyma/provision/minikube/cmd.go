@@ -13,6 +13,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"github.com/kyma-project/cli/internal/cli"
+	"github.com/kyma-project/cli/internal/diagnostics"
 	"github.com/kyma-project/cli/internal/kube"
 	"github.com/kyma-project/cli/internal/minikube"
 	"github.com/kyma-project/cli/pkg/step"
@@ -71,18 +72,32 @@ func NewCmd(o *Options) *cobra.Command {
 	cmd.Flags().StringVar(&o.DiskSize, "disk-size", "30g", "Specifies the disk size used for installation.")
 	cmd.Flags().StringVar(&o.Memory, "memory", "8192", "Specifies RAM reserved for installation.")
 	cmd.Flags().StringVar(&o.CPUS, "cpus", "4", "Specifies the number of CPUs used for installation.")
-	cmd.Flags().StringVar(&o.Profile, "profile", "", "Specifies the Minikube profile.")
+	cmd.Flags().StringVar(&o.Profile, "profile", "", "Specifies the Minikube profile. If not set, the global --minikube-profile flag is used.")
 	cmd.Flags().DurationVar(&o.Timeout, "timeout", 5*time.Minute, `Maximum time during which the provisioning takes place, where "0" means "infinite". Valid time units are "ns", "us" (or "µs"), "ms", "s", "m", "h".`)
 	cmd.Flags().BoolVar(&o.UseVPNKitSock, "use-hyperkit-vpnkit-sock", false, `Uses vpnkit sock provided by Docker. This is useful when DNS Port (53) is being used by some other program like dns-proxy (eg. provided by Cisco Umbrella. This flag works only on Mac OS).`)
 	cmd.Flags().StringVarP(&o.KubernetesVersion, "kube-version", "k", "1.16.15", "Kubernetes version of the cluster.")
+	cmd.Flags().StringArrayVar(&o.RegistryMirrors, "registry-mirror", nil, "URL of a registry mirror (e.g. https://mirror.example.com) used by the Docker daemon of Minikube instead of Docker Hub. The flag can be repeated. It is only applied when the cluster is created.")
+	cmd.Flags().StringArrayVar(&o.InsecureRegistries, "insecure-registry", nil, "Registry (e.g. registry.example.com:5000 or 10.0.0.0/8) which the Docker daemon of Minikube accesses without TLS verification. The flag can be repeated. It is only applied when the cluster is created.")
+	cmd.Flags().BoolVar(&o.DiagnosticsOnFailure, "diagnostics-on-failure", false, `Displays the diagnostics of the environment, as collected by "kyma diagnostics", if the requirements are not met.`)
 	return cmd
 }
 
 //Run runs the command
 func (c *command) Run() error {
+	if c.opts.Profile == "" {
+		c.opts.Profile = c.opts.MinikubeProfile
+	}
 	s := c.NewStep("Checking requirements")
 	if err := c.checkRequirements(s); err != nil {
 		s.Failure()
+		if c.opts.DiagnosticsOnFailure {
+			fmt.Fprint(c.Stdout(), diagnostics.Collect(diagnostics.Options{
+				CLIVersion:      cli.Version,
+				KubeconfigPath:  c.opts.KubeconfigPath,
+				MinikubeProfile: c.opts.Profile,
+				Timeout:         10 * time.Second,
+			}))
+		}
 		return err
 	}
 	s.Successf("Requirements verified")
@@ -143,6 +158,13 @@ func (c *command) Run() error {
 	}
 	s.Successf("Minikube up and running")
 
+	s = c.NewStep("Verifying the Kyma requirements")
+	if err := c.verifyRequirements(s); err != nil {
+		s.Failure()
+		return err
+	}
+	s.Successf("Kyma requirements verified")
+
 	s = c.NewStep("Adjusting Minikube cluster")
 	s.Status("Increase fs.inotify.max_user_instances")
 	err = c.increaseFsInotifyMaxUserInstances()
@@ -158,6 +180,16 @@ func (c *command) Run() error {
 	}
 	s.Successf("Adjustments finished")
 
+	// the Docker daemon of the none driver runs on the host and is not configured by Minikube
+	if (len(c.opts.RegistryMirrors) > 0 || len(c.opts.InsecureRegistries) > 0) && c.opts.VMDriver != vmDriverNone {
+		s = c.NewStep("Verifying the registry configuration")
+		if err := c.verifyRegistries(); err != nil {
+			s.Failure()
+			return err
+		}
+		s.Successf("Registry configuration verified")
+	}
+
 	s = c.NewStep("Creating cluster info ConfigMap")
 	err = c.createClusterInfoConfigMap()
 	if err != nil {
@@ -189,6 +221,11 @@ func (c *command) checkRequirements(s step.Step) error {
 		return fmt.Errorf("docker-ports flag is applicable only for VMDriver '%s'", vmDriverDocker)
 	}
 
+	if err := validateRegistries(c.opts.RegistryMirrors, c.opts.InsecureRegistries); err != nil {
+		s.Failure()
+		return err
+	}
+
 	versionWarning, err := minikube.CheckVersion(c.opts.Verbose, c.opts.Timeout)
 	if err != nil {
 		s.Failure()
@@ -236,7 +273,7 @@ func (c *command) startMinikube() error {
 		"--cpus", c.opts.CPUS,
 		"--extra-config=apiserver.authorization-mode=RBAC",
 		"--extra-config=apiserver.cors-allowed-origins='http://*'",
-		"--extra-config=apiserver.enable-admission-plugins=DefaultStorageClass,LimitRanger,MutatingAdmissionWebhook,NamespaceExists,NamespaceLifecycle,ResourceQuota,ServiceAccount,ValidatingAdmissionWebhook",
+		minikube.AdmissionPluginsArg(minikube.AdmissionPlugins),
 		"--extra-config=apiserver.service-account-signing-key-file=/var/lib/minikube/certs/sa.key",
 		"--extra-config=apiserver.service-account-issuer=kubernetes/serviceaccount",
 		"--extra-config=apiserver.service-account-api-audiences=api",
@@ -256,6 +293,8 @@ func (c *command) startMinikube() error {
 		}
 	}
 
+	startCmd = append(startCmd, registryStartArgs(c.opts.RegistryMirrors, c.opts.InsecureRegistries)...)
+
 	startCmd, err := osSpecificRun(c, startCmd)
 	if err != nil {
 		return err
@@ -347,16 +386,16 @@ func (c *command) enableMetricsServer() error {
 }
 
 func (c *command) printSummary() error {
-	fmt.Println()
-	fmt.Println("Minikube cluster installed")
+	fmt.Fprintln(c.Stdout())
+	fmt.Fprintln(c.Stdout(), "Minikube cluster installed")
 	clusterInfo, err := minikube.RunCmd(c.opts.Verbose, c.opts.Profile, c.opts.Timeout, "status", "-b="+bootstrapper)
 	if err != nil {
-		fmt.Printf("Cannot show cluster-info because of '%s", err)
+		fmt.Fprintf(c.Stdout(), "Cannot show cluster-info because of '%s", err)
 	} else {
-		fmt.Println(clusterInfo)
+		fmt.Fprintln(c.Stdout(), clusterInfo)
 	}
 
-	fmt.Println("Happy Minikube-ing! :)")
+	fmt.Fprintln(c.Stdout(), "Happy Minikube-ing! :)")
 	return nil
 }
 
@@ -22,12 +22,19 @@ func TestProvisionMinikubeFlags(t *testing.T) {
 		"--memory", "4096",
 		"--vm-driver", "kvm",
 		"--profile", "fooProfile",
+		"--diagnostics-on-failure",
+		"--registry-mirror", "https://mirror.example.com",
+		"--insecure-registry", "registry.example.com:5000",
+		"--insecure-registry", "10.0.0.0/8",
 	})
 	require.NoError(t, err, "Parsing flags should not return an error")
 	require.Equal(t, "6", o.CPUS)
 	require.Equal(t, "4096", o.Memory)
 	require.Equal(t, "kvm", o.VMDriver)
 	require.Equal(t, "fooProfile", o.Profile)
+	require.True(t, o.DiagnosticsOnFailure)
+	require.Equal(t, []string{"https://mirror.example.com"}, o.RegistryMirrors)
+	require.Equal(t, []string{"registry.example.com:5000", "10.0.0.0/8"}, o.InsecureRegistries)
 }
 
 func TestCheckRequirements(t *testing.T) {
@@ -54,6 +61,15 @@ func TestCheckRequirements(t *testing.T) {
 				VMDriver: "hyperv",
 			},
 		},
+		{
+			name:        "Registry mirrors must be URLs",
+			shouldFail:  true,
+			expectedErr: "invalid registry mirror 'mirror.example.com', use a URL like https://mirror.example.com",
+			op: Options{
+				VMDriver:        "hyperkit",
+				RegistryMirrors: []string{"mirror.example.com"},
+			},
+		},
 		{
 			name:        "--docker-ports require VM Driver docker",
 			shouldFail:  true,
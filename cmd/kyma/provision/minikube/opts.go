@@ -9,16 +9,19 @@ import (
 //options defines available options for the minikube provisioning command
 type Options struct {
 	*cli.Options
-	VMDriver            string
-	DiskSize            string
-	Memory              string
-	CPUS                string
-	HypervVirtualSwitch string
-	DockerPorts         []string
-	Profile             string
-	UseVPNKitSock       bool
-	Timeout             time.Duration
-	KubernetesVersion   string
+	VMDriver             string
+	DiskSize             string
+	Memory               string
+	CPUS                 string
+	HypervVirtualSwitch  string
+	DockerPorts          []string
+	Profile              string
+	UseVPNKitSock        bool
+	Timeout              time.Duration
+	KubernetesVersion    string
+	DiagnosticsOnFailure bool
+	RegistryMirrors      []string
+	InsecureRegistries   []string
 }
 
 //NewOptions creates options with default values
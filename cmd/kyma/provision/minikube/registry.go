@@ -0,0 +1,103 @@
+package minikube
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+
+	"github.com/docker/docker/api/types/registry"
+	"github.com/kyma-project/cli/internal/minikube"
+	"github.com/pkg/errors"
+)
+
+// registryTestImage is pulled from Docker Hub to verify that the registry mirrors work
+const registryTestImage = "busybox:1.32"
+
+// validateRegistries ensures that the registry mirrors are URLs and the insecure registries are host names or CIDRs, as the Docker daemon expects
+func validateRegistries(mirrors, insecure []string) error {
+	for _, m := range mirrors {
+		u, err := url.Parse(m)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("invalid registry mirror '%s', use a URL like https://mirror.example.com", m)
+		}
+	}
+	for _, r := range insecure {
+		if r == "" || strings.Contains(r, "://") {
+			return fmt.Errorf("invalid insecure registry '%s', use a host name like registry.example.com:5000 or a CIDR like 10.0.0.0/8", r)
+		}
+	}
+	return nil
+}
+
+// registryStartArgs returns the "minikube start" flags configuring the Docker daemon of the VM
+func registryStartArgs(mirrors, insecure []string) []string {
+	var args []string
+	for _, m := range mirrors {
+		args = append(args, "--registry-mirror="+m)
+	}
+	for _, r := range insecure {
+		args = append(args, "--insecure-registry="+r)
+	}
+	return args
+}
+
+// verifyRegistries checks that the Docker daemon of Minikube uses the registry mirrors and insecure registries, and pulls a test image through the mirrors.
+// Minikube only configures the daemon when the VM is created, so the settings are missing if an existing cluster was kept.
+func (c *command) verifyRegistries() error {
+	out, err := minikube.RunCmd(c.opts.Verbose, c.opts.Profile, c.opts.Timeout, "ssh", "--", "docker info --format '{{json .RegistryConfig}}'")
+	if err != nil {
+		return errors.Wrap(err, "unable to read the registry configuration of the Docker daemon")
+	}
+	cfg := registry.ServiceConfig{}
+	if err := json.Unmarshal([]byte(strings.TrimSpace(out)), &cfg); err != nil {
+		return errors.Wrap(err, "unable to parse the registry configuration of the Docker daemon")
+	}
+	if missing := missingRegistries(cfg, c.opts.RegistryMirrors, c.opts.InsecureRegistries); len(missing) > 0 {
+		return fmt.Errorf("the Docker daemon of Minikube does not use %s. Minikube only applies them when the cluster is created, so delete the existing cluster and provision it again", strings.Join(missing, ", "))
+	}
+
+	if len(c.opts.RegistryMirrors) > 0 {
+		if _, err := minikube.RunCmd(c.opts.Verbose, c.opts.Profile, c.opts.Timeout, "ssh", "--", "docker pull "+registryTestImage); err != nil {
+			return errors.Wrapf(err, "unable to pull the test image '%s' through the registry mirrors", registryTestImage)
+		}
+	}
+	return nil
+}
+
+// missingRegistries lists the registry mirrors and insecure registries which are not part of the registry configuration of the Docker daemon
+func missingRegistries(cfg registry.ServiceConfig, mirrors, insecure []string) []string {
+	var missing []string
+	for _, m := range mirrors {
+		found := false
+		for _, configured := range cfg.Mirrors {
+			// the daemon normalizes the mirrors with a trailing slash
+			if strings.TrimSuffix(configured, "/") == strings.TrimSuffix(m, "/") {
+				found = true
+			}
+		}
+		if !found {
+			missing = append(missing, fmt.Sprintf("the registry mirror '%s'", m))
+		}
+	}
+	for _, r := range insecure {
+		if !insecureRegistry(cfg, r) {
+			missing = append(missing, fmt.Sprintf("the insecure registry '%s'", r))
+		}
+	}
+	return missing
+}
+
+func insecureRegistry(cfg registry.ServiceConfig, r string) bool {
+	if _, cidr, err := net.ParseCIDR(r); err == nil {
+		for _, configured := range cfg.InsecureRegistryCIDRs {
+			if configured != nil && (*net.IPNet)(configured).String() == cidr.String() {
+				return true
+			}
+		}
+		return false
+	}
+	index, ok := cfg.IndexConfigs[r]
+	return ok && !index.Secure
+}
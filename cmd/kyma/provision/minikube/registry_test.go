@@ -0,0 +1,41 @@
+package minikube
+
+import (
+	"net"
+	"testing"
+
+	"github.com/docker/docker/api/types/registry"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateRegistries(t *testing.T) {
+	t.Parallel()
+	require.NoError(t, validateRegistries([]string{"https://mirror.example.com", "http://10.0.0.1:5000"}, []string{"registry.example.com:5000", "10.0.0.0/8"}))
+	require.EqualError(t, validateRegistries([]string{"mirror.example.com"}, nil), "invalid registry mirror 'mirror.example.com', use a URL like https://mirror.example.com")
+	require.EqualError(t, validateRegistries(nil, []string{"https://registry.example.com"}), "invalid insecure registry 'https://registry.example.com', use a host name like registry.example.com:5000 or a CIDR like 10.0.0.0/8")
+}
+
+func TestRegistryStartArgs(t *testing.T) {
+	t.Parallel()
+	require.Equal(t, []string{"--registry-mirror=https://a.example.com", "--registry-mirror=https://b.example.com", "--insecure-registry=10.0.0.0/8"},
+		registryStartArgs([]string{"https://a.example.com", "https://b.example.com"}, []string{"10.0.0.0/8"}))
+	require.Empty(t, registryStartArgs(nil, nil))
+}
+
+func TestMissingRegistries(t *testing.T) {
+	t.Parallel()
+	_, cidr, err := net.ParseCIDR("10.96.0.0/12")
+	require.NoError(t, err)
+	cfg := registry.ServiceConfig{
+		Mirrors:               []string{"https://mirror.example.com/"},
+		InsecureRegistryCIDRs: []*registry.NetIPNet{(*registry.NetIPNet)(cidr)},
+		IndexConfigs: map[string]*registry.IndexInfo{
+			"docker.io":                 {Name: "docker.io", Secure: true},
+			"registry.example.com:5000": {Name: "registry.example.com:5000", Secure: false},
+		},
+	}
+
+	require.Empty(t, missingRegistries(cfg, []string{"https://mirror.example.com"}, []string{"10.96.0.0/12", "registry.example.com:5000"}))
+	require.Equal(t, []string{"the registry mirror 'https://other.example.com'", "the insecure registry 'docker.io'", "the insecure registry '10.0.0.0/8'"},
+		missingRegistries(cfg, []string{"https://other.example.com"}, []string{"docker.io", "10.0.0.0/8"}))
+}
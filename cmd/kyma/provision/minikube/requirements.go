@@ -0,0 +1,51 @@
+package minikube
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/kyma-project/cli/internal/minikube"
+	"github.com/kyma-project/cli/pkg/step"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// verifyRequirements checks the running cluster against the requirements of Kyma and fixes the discrepancies.
+// An existing cluster kept by the user might have been started without the settings of "kyma provision minikube".
+func (c *command) verifyRequirements(s step.Step) error {
+	discrepancies := c.discrepancies(s)
+	if len(discrepancies) == 0 {
+		return nil
+	}
+	for _, d := range discrepancies {
+		s.LogInfof("Fixing the Minikube settings: %s", d.Problem)
+	}
+	s.Status("Apply the Minikube settings")
+	if err := minikube.Fix(c.opts.Verbose, c.opts.Profile, c.opts.Timeout, discrepancies); err != nil {
+		return err
+	}
+	s.Status("Wait for Minikube to be up and running")
+	if err := c.waitForMinikubeToBeUp(s); err != nil {
+		return err
+	}
+	if err := c.K8s.WaitPodStatusByLabel("kube-system", "k8s-app", "kube-dns", corev1.PodRunning); err != nil {
+		return err
+	}
+
+	if remaining := c.discrepancies(s); len(remaining) > 0 {
+		var problems []string
+		for _, d := range remaining {
+			problems = append(problems, d.Problem)
+		}
+		return fmt.Errorf("unable to fix the Minikube settings: %s. Delete the cluster with \"minikube delete\" and provision it again", strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+// discrepancies reads the settings of the cluster, settings which cannot be read are logged and not checked
+func (c *command) discrepancies(s step.Step) []minikube.Discrepancy {
+	settings, errs := minikube.ReadSettings(c.opts.Verbose, c.opts.Profile, c.opts.Timeout, c.K8s.Static(), c.K8s.RestConfig().Host, c.getMinikubeIP())
+	for _, err := range errs {
+		s.LogInfof("Unable to verify a Minikube setting: %s", err)
+	}
+	return minikube.Verify(settings)
+}
@@ -0,0 +1,93 @@
+package releases
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/kyma-project/cli/internal/cli"
+	"github.com/kyma-project/cli/internal/nice"
+	"github.com/kyma-project/cli/internal/releases"
+	kymaVersion "github.com/kyma-project/cli/internal/version"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+type command struct {
+	opts *Options
+	cli.Command
+}
+
+type release struct {
+	releases.Release
+	Default bool `json:"default"`
+}
+
+//NewCmd creates a new releases command
+func NewCmd(o *Options) *cobra.Command {
+	cmd := command{
+		Command: cli.Command{Options: o.Options},
+		opts:    o,
+	}
+
+	cobraCmd := &cobra.Command{
+		Use:   "releases",
+		Short: "Lists the available Kyma releases.",
+		Long: `Use this command to list the Kyma releases which you can pass to the "--source" flag of the "install" and "upgrade" commands.
+The releases are fetched from GitHub and cached for one hour in the Kyma CLI home folder.
+`,
+		RunE: func(_ *cobra.Command, _ []string) error { return cmd.Run() },
+	}
+
+	cobraCmd.Flags().BoolVar(&o.Prerelease, "prerelease", false, "Includes release candidates in the list.")
+	cobraCmd.Flags().StringVarP(&o.OutputFormat, "output", "o", "", "Output format. One of: json")
+	return cobraCmd
+}
+
+//Run runs the command
+func (cmd *command) Run() error {
+	if cmd.opts.OutputFormat != "" && !strings.EqualFold(cmd.opts.OutputFormat, "json") {
+		return fmt.Errorf("unsupported output format '%s'. Use 'json' or omit the flag to print a table", cmd.opts.OutputFormat)
+	}
+
+	rels, err := releases.List(cmd.opts.Prerelease)
+	if err != nil {
+		return errors.Wrap(err, "Could not list the Kyma releases. Make sure you can reach the GitHub API")
+	}
+
+	var result []release
+	for _, r := range rels {
+		result = append(result, release{Release: r, Default: r.Version == kymaVersion.DefaultKymaVersion})
+	}
+
+	if strings.EqualFold(cmd.opts.OutputFormat, "json") {
+		d, err := json.MarshalIndent(result, "", "\t")
+		if err != nil {
+			return errors.Wrap(err, "Unable to marshal the Kyma releases to json")
+		}
+		fmt.Fprintln(cmd.Stdout(), string(d))
+		return nil
+	}
+
+	if len(result) == 0 {
+		fmt.Fprintln(cmd.Stdout(), "No releases found")
+		return nil
+	}
+
+	writer := nice.NewTableWriter([]string{"VERSION", "PUBLISHED", "LOCAL CONFIG", "DEFAULT"}, cmd.Stdout())
+	for _, r := range result {
+		var isDefault string
+		if r.Default {
+			isDefault = "*"
+		}
+		writer.Append([]string{
+			r.Version,
+			r.PublishedAt.Format("2006-01-02"),
+			fmt.Sprintf("%t", r.HasLocalConfig),
+			isDefault,
+		})
+	}
+	writer.Render()
+
+	return nil
+}
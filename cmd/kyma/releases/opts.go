@@ -0,0 +1,15 @@
+package releases
+
+import "github.com/kyma-project/cli/internal/cli"
+
+//Options defines available options for the releases command
+type Options struct {
+	*cli.Options
+	Prerelease   bool
+	OutputFormat string
+}
+
+//NewOptions creates options with default values
+func NewOptions(o *cli.Options) *Options {
+	return &Options{Options: o}
+}
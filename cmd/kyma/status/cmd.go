@@ -0,0 +1,84 @@
+package status
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/kyma-project/cli/internal/cli"
+	"github.com/kyma-project/cli/internal/kube"
+	"github.com/kyma-project/cli/internal/version"
+	"github.com/kyma-project/cli/pkg/installation"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+type command struct {
+	opts *Options
+	cli.Command
+}
+
+//NewCmd creates a new status command
+func NewCmd(o *Options) *cobra.Command {
+	cmd := command{
+		Command: cli.Command{Options: o.Options},
+		opts:    o,
+	}
+
+	cobraCmd := &cobra.Command{
+		Use:   "status",
+		Short: "Displays the status of the Kyma installation.",
+		Long: `Use this command to print the status of the Kyma installation on the cluster the current kubeconfig points to.
+If the installation failed, the errors reported by the Kyma Installer are listed per component, starting with the first failing component.
+`,
+		RunE: func(_ *cobra.Command, _ []string) error { return cmd.Run() },
+	}
+
+	cobraCmd.Flags().StringVar(&o.InstallationName, "installation-name", "", "Name of the Kyma Installation CR. If not set, it is discovered from the cluster.")
+	cobraCmd.Flags().StringVarP(&o.OutputFormat, "output", "o", "", "Output format. One of: json")
+	return cobraCmd
+}
+
+//Run runs the command
+func (cmd *command) Run() error {
+	if cmd.opts.OutputFormat != "" && !strings.EqualFold(cmd.opts.OutputFormat, "json") {
+		return fmt.Errorf("unsupported output format '%s'. Use 'json' or omit the flag", cmd.opts.OutputFormat)
+	}
+
+	var err error
+	if cmd.K8s, err = kube.NewFromConfig("", cmd.KubeconfigPath); err != nil {
+		return errors.Wrap(err, "Could not initialize the Kubernetes client. Make sure your kubeconfig is valid")
+	}
+
+	status, err := installation.GetStatus(cmd.K8s, cmd.opts.InstallationName)
+	if err != nil {
+		return errors.Wrap(err, "Could not get the status of the Kyma installation")
+	}
+
+	if strings.EqualFold(cmd.opts.OutputFormat, "json") {
+		d, err := json.MarshalIndent(status, "", "\t")
+		if err != nil {
+			return errors.Wrap(err, "Unable to marshal the Kyma installation status to json")
+		}
+		fmt.Fprintln(cmd.Stdout(), string(d))
+		return nil
+	}
+
+	if status.Name == "" {
+		fmt.Fprintln(cmd.Stdout(), "Kyma is not installed")
+		return nil
+	}
+	fmt.Fprintf(cmd.Stdout(), "Kyma installation:\t%s\n", status.Name)
+	fmt.Fprintf(cmd.Stdout(), "Status:\t\t\t%s\n", status.State)
+	if status.Description != "" {
+		fmt.Fprintf(cmd.Stdout(), "Description:\t\t%s\n", status.Description)
+	}
+	fmt.Fprintf(cmd.Stdout(), "Kyma version:\t\t%s\n", status.KymaVersion)
+	if err := version.CheckCompatibility(status.KymaVersion); err != nil {
+		fmt.Fprintf(cmd.Stdout(), "Warning:\t\t%s\n", err)
+	}
+	if len(status.Errors) > 0 {
+		fmt.Fprintf(cmd.Stdout(), "\n%s\n", installation.FormatComponentErrors(status.Errors))
+	}
+	return nil
+}
@@ -0,0 +1,125 @@
+package summary
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/kyma-project/cli/internal/cli"
+	"github.com/kyma-project/cli/internal/kube"
+	"github.com/kyma-project/cli/internal/version"
+	"github.com/kyma-project/cli/pkg/installation"
+	installSummary "github.com/kyma-project/cli/pkg/installation/summary"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+const (
+	outputJSON     = "json"
+	outputMarkdown = "markdown"
+)
+
+type command struct {
+	opts *Options
+	cli.Command
+}
+
+//NewCmd creates a new summary command
+func NewCmd(o *Options) *cobra.Command {
+	cmd := command{
+		Command: cli.Command{Options: o.Options},
+		opts:    o,
+	}
+
+	cobraCmd := &cobra.Command{
+		Use:   "summary",
+		Short: "Displays the summary of the Kyma installation.",
+		Long: `Use this command to display the summary of "kyma install" for the Kyma installed on the cluster the current kubeconfig points to, without changing the cluster.
+The summary shows the Kyma version, the cluster, the console address, and the admin credentials. Like after an installation, the admin credentials are waited for if the admin-user Secret is not populated yet, and the console address is read from the cluster.
+The admin password is only displayed in interactive mode, it is never part of the JSON or Markdown output.
+
+The command fails if Kyma is not installed on the cluster.
+`,
+		Example: `  # Display the summary of the installed Kyma
+  kyma summary
+
+  # Render the summary as Markdown, for example for a comment of a pull request bot
+  kyma summary -o markdown`,
+		RunE: func(_ *cobra.Command, _ []string) error { return cmd.Run() },
+	}
+
+	cobraCmd.Flags().StringVarP(&o.OutputFormat, "output", "o", "", "Output format. One of: json|markdown")
+	return cobraCmd
+}
+
+//Run runs the command
+func (cmd *command) Run() error {
+	format := strings.ToLower(cmd.opts.OutputFormat)
+	if format != "" && format != outputJSON && format != outputMarkdown {
+		return fmt.Errorf("unsupported output format '%s'. Use 'json', 'markdown' or omit the flag", cmd.opts.OutputFormat)
+	}
+	if cmd.opts.CI {
+		cmd.Factory.NonInteractive = true
+	}
+
+	var err error
+	if cmd.K8s, err = kube.NewFromConfig("", cmd.KubeconfigPath); err != nil {
+		return errors.Wrap(err, "Could not initialize the Kubernetes client. Make sure your kubeconfig is valid")
+	}
+
+	result, err := installation.Summary(cmd.K8s)
+	if err != nil {
+		return err
+	}
+
+	switch format {
+	case outputJSON:
+		d, err := json.MarshalIndent(newSummaryOutput(result), "", "\t")
+		if err != nil {
+			return errors.Wrap(err, "Unable to marshal the summary to json")
+		}
+		fmt.Fprintln(cmd.Stdout(), string(d))
+		return nil
+	case outputMarkdown:
+		fmt.Fprint(cmd.Stdout(), installSummary.Markdown(result, nil, nil, nil))
+		return nil
+	}
+	return installSummary.Print(cmd.Stdout(), result, installSummary.Options{NonInteractive: cmd.Factory.NonInteractive})
+}
+
+// summaryOutput is the JSON representation of the summary, without the admin password
+type summaryOutput struct {
+	Version         string     `json:"version"`
+	State           string     `json:"state"`
+	InProgressSince *time.Time `json:"inProgressSince,omitempty"`
+	Cluster         string     `json:"cluster"`
+	Console         string     `json:"console,omitempty"`
+	ConsoleAssumed  bool       `json:"consoleAssumed,omitempty"`
+	Domain          string     `json:"domain,omitempty"`
+	AdminEmail      string     `json:"adminEmail,omitempty"`
+	Warnings        []string   `json:"warnings,omitempty"`
+}
+
+func newSummaryOutput(result *installation.Result) summaryOutput {
+	out := summaryOutput{
+		Version:        result.ClusterVersion.Version,
+		State:          "Installed",
+		Cluster:        result.Host,
+		Console:        result.Console,
+		ConsoleAssumed: result.ConsoleAssumed,
+		Domain:         result.Domain,
+		AdminEmail:     result.AdminEmail,
+		Warnings:       result.Warnings,
+	}
+	switch result.ClusterVersion.State {
+	case version.InProgress:
+		out.State = "InProgress"
+		if since := result.ClusterVersion.Since; !since.IsZero() {
+			out.InProgressSince = &since
+		}
+	case version.Failed:
+		out.State = "Error"
+	}
+	return out
+}
@@ -0,0 +1,53 @@
+package summary
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/kyma-project/cli/internal/cli"
+	"github.com/kyma-project/cli/internal/version"
+	"github.com/kyma-project/cli/pkg/installation"
+	"github.com/stretchr/testify/require"
+)
+
+// TestSummaryFlags ensures that the provided command flags are stored in the options.
+func TestSummaryFlags(t *testing.T) {
+	t.Parallel()
+	o := NewOptions(&cli.Options{})
+	c := NewCmd(o)
+
+	// test default flag values
+	require.Empty(t, o.OutputFormat, "Default value for the output flag not as expected.")
+
+	// test passing flags
+	err := c.ParseFlags([]string{"-o", "markdown"})
+	require.NoError(t, err, "Parsing flags should not return an error")
+	require.Equal(t, "markdown", o.OutputFormat, "The parsed value for the output flag not as expected.")
+}
+
+func TestSummaryOutput(t *testing.T) {
+	t.Parallel()
+	result := &installation.Result{
+		KymaVersion:    "1.17.0",
+		ClusterVersion: version.ClusterVersion{Version: "1.17.0", State: version.Installed},
+		Host:           "https://api.cluster.example.com",
+		Console:        "https://console.kyma.example.com",
+		Domain:         "kyma.example.com",
+		AdminEmail:     "admin@kyma.cx",
+		AdminPassword:  "s3cr3t",
+	}
+	d, err := json.Marshal(newSummaryOutput(result))
+	require.NoError(t, err)
+	require.JSONEq(t, `{"version":"1.17.0","state":"Installed","cluster":"https://api.cluster.example.com","console":"https://console.kyma.example.com","domain":"kyma.example.com","adminEmail":"admin@kyma.cx"}`, string(d))
+	require.NotContains(t, string(d), "s3cr3t", "The admin password must never be part of the JSON output.")
+
+	since := time.Date(2020, 9, 1, 12, 0, 0, 0, time.UTC)
+	result.ClusterVersion = version.ClusterVersion{Version: "1.17.0", State: version.InProgress, Since: since}
+	out := newSummaryOutput(result)
+	require.Equal(t, "InProgress", out.State)
+	require.Equal(t, &since, out.InProgressSince)
+
+	result.ClusterVersion = version.ClusterVersion{Version: "1.17.0", State: version.Failed, Since: since}
+	require.Equal(t, "Error", newSummaryOutput(result).State)
+}
@@ -0,0 +1,14 @@
+package summary
+
+import "github.com/kyma-project/cli/internal/cli"
+
+//Options defines available options for the summary command
+type Options struct {
+	*cli.Options
+	OutputFormat string
+}
+
+//NewOptions creates options with default values
+func NewOptions(o *cli.Options) *Options {
+	return &Options{Options: o}
+}
@@ -42,6 +42,7 @@ Use the flags to specify the name of your Function, the Namespace, or the locati
 
 	cmd.Flags().StringVarP(&o.Namespace, "namespace", "n", "", `Namespace from which you want to sync the Function.`)
 	cmd.Flags().StringVarP(&o.Dir, "dir", "d", "", `Full path to the directory where you want to save the project.`)
+	cli.MarkPathFlags(cmd, "dir")
 
 	return cmd
 }
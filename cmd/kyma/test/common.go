@@ -1,10 +1,7 @@
 package test
 
 import (
-	"io"
-
 	oct "github.com/kyma-incubator/octopus/pkg/apis/testing/v1alpha1"
-	"github.com/olekukonko/tablewriter"
 	"github.com/pkg/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
@@ -64,19 +61,6 @@ func WithMatchLabelsExpression(selector labels.Selector) SuiteOption {
 	}
 }
 
-func NewTableWriter(columns []string, out io.Writer) *tablewriter.Table {
-	writer := tablewriter.NewWriter(out)
-	writer.SetBorder(false)
-	writer.SetHeader(columns)
-	writer.SetAlignment(tablewriter.ALIGN_LEFT)
-	writer.SetHeaderAlignment(tablewriter.ALIGN_LEFT)
-	writer.SetHeaderLine(false)
-	writer.SetRowSeparator("")
-	writer.SetCenterSeparator("")
-	writer.SetColumnSeparator("")
-	return writer
-}
-
 func GetNumberOfFinishedTests(testSuite *oct.ClusterTestSuite) int {
 	result := 0
 	for _, t := range testSuite.Status.Results {
@@ -43,11 +43,11 @@ func (cmd *command) Run() error {
 		return err
 	}
 	if len(testDefs) == 0 {
-		fmt.Println("No test definitions found")
+		fmt.Fprintln(cmd.Stdout(), "No test definitions found")
 		return nil
 	}
 	for _, t := range testDefs {
-		fmt.Printf("%s\r\n", t)
+		fmt.Fprintf(cmd.Stdout(), "%s\r\n", t)
 	}
 
 	return nil
@@ -2,6 +2,7 @@ package del
 
 import (
 	"fmt"
+	"io"
 
 	oct "github.com/kyma-incubator/octopus/pkg/apis/testing/v1alpha1"
 	"github.com/kyma-project/cli/cmd/kyma/test"
@@ -54,7 +55,7 @@ func (cmd *command) Run(args []string) error {
 	}
 	testSuites.Items = tSuites
 	for _, ts := range testSuites.Items {
-		if err := deleteTestSuite(cmd.K8s.Octopus(), ts.GetName()); err != nil {
+		if err := deleteTestSuite(cmd.Stdout(), cmd.K8s.Octopus(), ts.GetName()); err != nil {
 			return err
 		}
 	}
@@ -62,11 +63,11 @@ func (cmd *command) Run(args []string) error {
 	return nil
 }
 
-func deleteTestSuite(cli octopus.Interface, testName string) error {
+func deleteTestSuite(w io.Writer, cli octopus.Interface, testName string) error {
 	if err := cli.DeleteTestSuite(test.NewTestSuite(testName).GetName(), metav1.DeleteOptions{}); err != nil {
 		return errors.Wrap(err, fmt.Sprintf("Unable to delete test suite '%s'",
 			testName))
 	}
-	fmt.Printf("Test suite '%s' successfully deleted\n", testName)
+	fmt.Fprintf(w, "Test suite '%s' successfully deleted\n", testName)
 	return nil
 }
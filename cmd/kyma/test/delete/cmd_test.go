@@ -1,6 +1,7 @@
 package del
 
 import (
+	"io/ioutil"
 	"testing"
 
 	oct "github.com/kyma-incubator/octopus/pkg/apis/testing/v1alpha1"
@@ -71,7 +72,7 @@ func Test_deleteTestSuite(t *testing.T) {
 
 	for _, tt := range testData {
 		mCli := octopus.NewMockedOctopusRestClient(nil, tt.testSuitesAvailable, nil)
-		err := deleteTestSuite(mCli, tt.testSuiteNameToDelete)
+		err := deleteTestSuite(ioutil.Discard, mCli, tt.testSuiteNameToDelete)
 		if !tt.shouldFail {
 			require.Nil(t, err, tt.testName)
 		} else {
@@ -2,11 +2,11 @@ package list
 
 import (
 	"fmt"
-	"os"
 
 	"github.com/kyma-project/cli/cmd/kyma/test"
 	"github.com/kyma-project/cli/internal/cli"
 	"github.com/kyma-project/cli/internal/kube"
+	"github.com/kyma-project/cli/internal/nice"
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -45,11 +45,11 @@ func (cmd *command) Run() error {
 	}
 
 	if len(testSuites.Items) == 0 {
-		fmt.Println("No test suites found")
+		fmt.Fprintln(cmd.Stdout(), "No test suites found")
 		return nil
 	}
 
-	writer := test.NewTableWriter([]string{"TEST SUITE", "COMPLETED", "STATUS"}, os.Stdout)
+	writer := nice.NewTableWriter([]string{"TEST SUITE", "COMPLETED", "STATUS"}, cmd.Stdout())
 
 	for idx := range testSuites.Items {
 		ts := testSuites.Items[idx]
@@ -123,7 +123,7 @@ func (cmd *command) Run(args []string) error {
 	if _, err := cmd.K8s.Octopus().CreateTestSuite(testResource); err != nil {
 		return err
 	}
-	fmt.Printf("- Test suite '%s' successfully created\r\n", testSuiteName)
+	fmt.Fprintf(cmd.Stdout(), "- Test suite '%s' successfully created\r\n", testSuiteName)
 
 	if cmd.opts.Watch {
 		waitStep := cmd.NewStep("Waiting for test suite to finish")
@@ -3,7 +3,7 @@ package status
 import (
 	"encoding/json"
 	"fmt"
-	"os"
+	"io"
 	"strings"
 
 	oct "github.com/kyma-incubator/octopus/pkg/apis/testing/v1alpha1"
@@ -12,6 +12,7 @@ import (
 	"github.com/kyma-project/cli/internal/junitxml"
 	"github.com/kyma-project/cli/internal/kube"
 	"github.com/kyma-project/cli/internal/logs"
+	"github.com/kyma-project/cli/internal/nice"
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 	"gopkg.in/yaml.v2"
@@ -69,7 +70,7 @@ func (cmd *command) Run(args []string) error {
 		}
 
 		if len(testList.Items) == 0 {
-			fmt.Println("No test suites found")
+			fmt.Fprintln(cmd.Stdout(), "No test suites found")
 			return nil
 		}
 
@@ -101,7 +102,7 @@ func (cmd *command) printTestSuiteStatus(testSuite *oct.ClusterTestSuite, output
 			return errors.Wrapf(err, "Unable to marshal test suite '%s' to yaml",
 				testSuite.GetName())
 		}
-		fmt.Println(string(d))
+		fmt.Fprintln(cmd.Stdout(), string(d))
 		return nil
 	case "json":
 		d, err := json.MarshalIndent(testSuite, "", "\t")
@@ -109,43 +110,43 @@ func (cmd *command) printTestSuiteStatus(testSuite *oct.ClusterTestSuite, output
 			return errors.Wrapf(err, "Unable to marshal test suite '%s' to json",
 				testSuite.GetName())
 		}
-		fmt.Println(string(d))
+		fmt.Fprintln(cmd.Stdout(), string(d))
 		return nil
 	case "wide":
-		printTestSuite(testSuite, true)
+		printTestSuite(cmd.Stdout(), testSuite, true)
 	case "junit":
 		logsFetcher := logs.NewFetcherForTestingPods(cmd.K8s.Static().CoreV1(), []string{})
 		junitCreator := junitxml.NewCreator(logsFetcher)
-		if err := junitCreator.Write(os.Stdout, testSuite); err != nil {
+		if err := junitCreator.Write(cmd.Stdout(), testSuite); err != nil {
 			return errors.Wrapf(err, "while writing junit report for '%s' test suite", testSuite.GetName())
 		}
 	default:
-		printTestSuite(testSuite, false)
+		printTestSuite(cmd.Stdout(), testSuite, false)
 	}
 
 	return nil
 }
 
-func printTestSuite(testSuite *oct.ClusterTestSuite, wide bool) {
-	fmt.Printf("Name:\t\t%s\r\n", testSuite.GetName())
-	fmt.Printf("Concurrency:\t%d\r\n", testSuite.Spec.Concurrency)
-	fmt.Printf("MaxRetries:\t%d\r\n", testSuite.Spec.MaxRetries)
+func printTestSuite(w io.Writer, testSuite *oct.ClusterTestSuite, wide bool) {
+	fmt.Fprintf(w, "Name:\t\t%s\r\n", testSuite.GetName())
+	fmt.Fprintf(w, "Concurrency:\t%d\r\n", testSuite.Spec.Concurrency)
+	fmt.Fprintf(w, "MaxRetries:\t%d\r\n", testSuite.Spec.MaxRetries)
 	if testSuite.Status.StartTime != nil {
-		fmt.Printf("StartTime:\t%s\r\n", testSuite.Status.StartTime.String())
+		fmt.Fprintf(w, "StartTime:\t%s\r\n", testSuite.Status.StartTime.String())
 	} else {
-		fmt.Printf("StartTime:\t%s\r\n", "Not started yet")
+		fmt.Fprintf(w, "StartTime:\t%s\r\n", "Not started yet")
 	}
 	if testSuite.Status.CompletionTime != nil {
-		fmt.Printf("EndTime:\t%s\r\n", testSuite.Status.CompletionTime)
+		fmt.Fprintf(w, "EndTime:\t%s\r\n", testSuite.Status.CompletionTime)
 	} else {
-		fmt.Printf("EndTime:\t%s\r\n", "Not finished yet")
+		fmt.Fprintf(w, "EndTime:\t%s\r\n", "Not finished yet")
 	}
 
 	if len(testSuite.Status.Conditions) > 0 {
-		fmt.Printf("Condition:\t%s\r\n", testSuite.Status.Conditions[len(testSuite.Status.Conditions)-1].Type)
+		fmt.Fprintf(w, "Condition:\t%s\r\n", testSuite.Status.Conditions[len(testSuite.Status.Conditions)-1].Type)
 	}
 
-	writer := test.NewTableWriter([]string{}, os.Stdout)
+	writer := nice.NewTableWriter([]string{}, w)
 	for _, t := range testSuite.Status.Results {
 
 		if wide {
@@ -166,12 +167,12 @@ func printTestSuite(testSuite *oct.ClusterTestSuite, wide bool) {
 			writer.Append([]string{t.Name, string(t.Status)})
 		}
 	}
-	fmt.Printf("Completed:\t%d/%d\r\n",
+	fmt.Fprintf(w, "Completed:\t%d/%d\r\n",
 		test.GetNumberOfFinishedTests(testSuite),
 		len(testSuite.Status.Results))
 	writer.Render()
 	if rc := generateRerunCommand(testSuite); rc != "" {
-		fmt.Println("Rerun failed tests:", rc)
+		fmt.Fprintln(w, "Rerun failed tests:", rc)
 	}
 }
 
@@ -0,0 +1,103 @@
+package updatecli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/kyma-project/cli/internal/cli"
+	"github.com/kyma-project/cli/internal/github"
+	"github.com/kyma-project/cli/internal/selfupdate"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+type command struct {
+	opts *Options
+	cli.Command
+}
+
+//NewCmd creates a new update-cli command
+func NewCmd(o *Options) *cobra.Command {
+	cmd := command{
+		Command: cli.Command{Options: o.Options},
+		opts:    o,
+	}
+
+	cobraCmd := &cobra.Command{
+		Use:   "update-cli",
+		Short: "Checks for a newer Kyma CLI release and updates the CLI.",
+		Long: `Use this command to check if a newer release of Kyma CLI is published on GitHub.
+If there is one, the command prints how to update the CLI, depending on how it was installed (Homebrew, Scoop, or a manual download).
+With "--apply", a manually downloaded CLI downloads the binary of the release for your platform, verifies its checksum, and replaces itself.
+The previous binary is restored if the replacement fails. In non-interactive mode, "--apply" also requires "--yes".
+`,
+		RunE: func(_ *cobra.Command, _ []string) error { return cmd.Run() },
+	}
+
+	cobraCmd.Flags().BoolVar(&o.Apply, "apply", false, "Replaces the running Kyma CLI with the binary of the newest release.")
+	cobraCmd.Flags().BoolVar(&o.Yes, "yes", false, "Confirms the replacement of the Kyma CLI, which is required with --apply in non-interactive mode.")
+	return cobraCmd
+}
+
+//Run runs the command
+func (cmd *command) Run() error {
+	client := github.NewClient(2 * time.Minute)
+	s := cmd.NewStep("Checking for a newer Kyma CLI release")
+	latest, err := selfupdate.Latest(client)
+	if err != nil {
+		s.Failure()
+		return errors.Wrap(err, "Could not check for a newer Kyma CLI release. Make sure you can reach the GitHub API")
+	}
+	current := cli.Version
+	newer, err := selfupdate.Newer(current, latest.Version)
+	if err != nil {
+		s.Failure()
+		return err
+	}
+	if !newer {
+		s.Successf("Kyma CLI %s is up to date", current)
+		return nil
+	}
+	s.Successf("Kyma CLI %s is available, this is %s", latest.Version, current)
+
+	executable, err := os.Executable()
+	if err == nil {
+		executable, err = filepath.EvalSymlinks(executable)
+	}
+	if err != nil {
+		return errors.Wrap(err, "Could not find the executable of the CLI")
+	}
+	method := selfupdate.DetectMethod(executable)
+	if !cmd.opts.Apply {
+		fmt.Fprintf(cmd.Stdout(), "Kyma CLI was installed with %s. %s\n", method, selfupdate.Instructions(method, latest))
+		return nil
+	}
+	// package managers keep track of the installed binary, so they must replace it themselves
+	if method != selfupdate.Manual {
+		return fmt.Errorf("Kyma CLI was installed with %s and cannot replace itself. %s", method, selfupdate.Instructions(method, latest))
+	}
+	if err := selfupdate.CheckWritable(executable); err != nil {
+		return err
+	}
+	if !cmd.opts.Yes {
+		if cmd.opts.NonInteractive || cmd.opts.CI {
+			return fmt.Errorf("replacing '%s' requires --yes in non-interactive mode", executable)
+		}
+		confirmStep := cmd.NewStep("Replacing the Kyma CLI")
+		if !confirmStep.PromptYesNo(fmt.Sprintf("Do you want to replace '%s' with Kyma CLI %s? ", executable, latest.Version)) {
+			confirmStep.Successf("Kyma CLI not updated")
+			return nil
+		}
+		confirmStep.Success()
+	}
+
+	s = cmd.NewStep(fmt.Sprintf("Updating Kyma CLI to %s", latest.Version))
+	if err := selfupdate.Apply(client, latest, executable); err != nil {
+		s.Failure()
+		return err
+	}
+	s.Successf("Kyma CLI updated to %s", latest.Version)
+	return nil
+}
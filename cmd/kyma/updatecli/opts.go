@@ -0,0 +1,15 @@
+package updatecli
+
+import "github.com/kyma-project/cli/internal/cli"
+
+//Options defines available options for the update-cli command
+type Options struct {
+	*cli.Options
+	Apply bool
+	Yes   bool
+}
+
+//NewOptions creates options with default values
+func NewOptions(o *cli.Options) *Options {
+	return &Options{Options: o}
+}
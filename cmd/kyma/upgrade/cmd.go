@@ -2,17 +2,22 @@ package upgrade
 
 import (
 	"fmt"
+	"path/filepath"
 	"time"
 
 	"github.com/kyma-project/cli/internal/cli"
+	"github.com/kyma-project/cli/internal/files"
 	"github.com/kyma-project/cli/internal/hosts"
 	"github.com/kyma-project/cli/internal/kube"
 	"github.com/kyma-project/cli/internal/nice"
+	"github.com/kyma-project/cli/internal/releases"
 
+	kymaVersion "github.com/kyma-project/cli/internal/version"
 	"github.com/kyma-project/cli/pkg/installation"
 	"github.com/pkg/errors"
 
 	"github.com/spf13/cobra"
+	apiErrors "k8s.io/apimachinery/pkg/api/errors"
 )
 
 const (
@@ -38,24 +43,55 @@ func NewCmd(o *Options) *cobra.Command {
 		RunE:  func(_ *cobra.Command, _ []string) error { return cmd.Run() },
 	}
 
-	cobraCmd.Flags().BoolVarP(&o.NoWait, "no-wait", "n", false, "Determines if the command should wait for the Kyma upgrade to complete.")
+	cli.AddWaitFlag(cobraCmd, &o.NoWait, "Waits for the Kyma upgrade to complete. Use --wait=false to return once the upgrade is triggered.")
 	cobraCmd.Flags().StringVarP(&o.Domain, "domain", "d", defaultDomain, "Domain used for the upgrade.")
 	cobraCmd.Flags().StringVarP(&o.TLSCert, "tls-cert", "", "", "TLS certificate for the domain used for the upgrade. The certificate must be a base64-encoded value.")
 	cobraCmd.Flags().StringVarP(&o.TLSKey, "tls-key", "", "", "TLS key for the domain used for the upgrade. The key must be a base64-encoded value.")
-	cobraCmd.Flags().StringVarP(&o.Source, "source", "s", DefaultKymaVersion, `Upgrade source. 
+	cobraCmd.Flags().StringVarP(&o.Source, "source", "s", kymaVersion.DefaultKymaVersion, `Upgrade source. 
 	- To use a specific release, write "kyma upgrade --source=1.3.0".
+	- To use the newest stable release, write "kyma upgrade --source=latest".
 	- To use the master branch, write "kyma install --source=master".
 	- To use a commit, write "kyma upgrade --source=34edf09a".
 	- To use the local sources, write "kyma upgrade --source=local".
 	- To use a custom installer image, write "kyma upgrade --source=user/my-kyma-installer:v1.4.0".`)
-	cobraCmd.Flags().StringVarP(&o.LocalSrcPath, "src-path", "", "", "Absolute path to local sources.")
+	_ = cobraCmd.RegisterFlagCompletionFunc("source", releases.CompleteVersions)
+	cobraCmd.Flags().StringVar(&o.Release, "release", "", `Release channel which is resolved to the Kyma version to upgrade, instead of --source: "stable" (newest generally available release), "canary" (newest release including release candidates), or "nightly" (newest master build). The resolved version is displayed and cached for ten minutes. To use a channel offline, pin its version with the KYMACTL_RELEASE_<CHANNEL> environment variable, e.g. KYMACTL_RELEASE_STABLE=1.16.0. With "kyma upgrade --release=stable", the cluster is upgraded to the newest stable release it can reach, which is at most one minor version ahead.`)
+	_ = cobraCmd.RegisterFlagCompletionFunc("release", func(_ *cobra.Command, _ []string, _ string) ([]string, cobra.ShellCompDirective) {
+		return releases.ChannelNames(), cobra.ShellCompDirectiveNoFileComp
+	})
+	cobraCmd.Flags().StringVarP(&o.LocalSrcPath, "src-path", "", "", "Path to local sources, either a directory or a .tar.gz, .tgz or .zip archive of the Kyma repository. If not set, the sources are looked up in $GOPATH/src/github.com/kyma-project/kyma, ~/.kyma/sources/kyma, and the current directory.")
+	cobraCmd.Flags().BoolVar(&o.KeepSources, "keep-sources", false, "Keeps the directory to which the archive of the local sources is extracted.")
+	cobraCmd.Flags().BoolVar(&o.PruneDocker, "prune-docker", false, "Removes the images not used by any container and the stopped containers from the Docker daemon of Minikube before the Kyma Installer image is built from local sources. The images of running pods are always kept.")
 	cobraCmd.Flags().DurationVarP(&o.Timeout, "timeout", "", 1*time.Hour, "Timeout after which CLI stops watching the upgrade progress.")
+	cobraCmd.Flags().DurationVar(&o.RequestTimeout, "request-timeout", 30*time.Second, "Timeout of a single request to the Kubernetes API server. Checks of the upgrade state failing because the cluster is unreachable are retried 5 times.")
+	cobraCmd.Flags().IntVar(&o.Retries, "retries", 0, "Number of times a failed upgrade is triggered again by labeling the Installation CR, e.g. if a component fails because a webhook is not ready yet. The errors of each attempt are displayed before the retry.")
+	cobraCmd.Flags().DurationVar(&o.DockerTimeout, "docker-timeout", 10*time.Minute, "Maximum time of the Docker build of the Kyma Installer image if --src-path is used. While the image is built, the elapsed time is displayed every minute.")
+	cobraCmd.Flags().DurationVar(&o.ApplyTimeout, "apply-timeout", 5*time.Minute, "Time-out for each download of a release artifact and for applying the Kyma Installer to the cluster. The download progress is displayed on the step. Increase it on slow connections.")
 	cobraCmd.Flags().StringVarP(&o.Password, "password", "p", "", "Predefined cluster password.")
 	cobraCmd.Flags().StringArrayVarP(&o.OverrideConfigs, "override", "o", nil, "Path to a YAML file with parameters to override.")
-	cobraCmd.Flags().StringVarP(&o.ComponentsConfig, "components", "c", "", "Path to a YAML file with a component list to override.")
+	cobraCmd.Flags().StringSliceVar(&o.Configs, "config", nil, "Path or URL of a YAML file with installer overrides (ConfigMaps and Secrets) and an optional Installation CR. The flag can be repeated or take a comma-separated list, and the files are merged in the given order: the values of later files override the values of earlier ones, key by key. Different Installation CRs in several files are an error.")
+	cobraCmd.Flags().StringVar(&o.InstallerManifest, "installer-manifest", "", "Path or URL of the Kyma Installer manifest to apply instead of the one of the Kyma release or the local sources, for example a mirrored or patched copy. It must contain the Kyma Installer Deployment and its ServiceAccount. The source is recorded in an annotation of the Kyma Installer Deployment.")
+	cobraCmd.Flags().BoolVar(&o.UpgradeCRDs, "upgrade-crds", false, "Replaces the CRDs on the cluster which differ from the ones of the Kyma Installer manifest. By default, existing CRDs are not applied again, so that changed schemas do not conflict with them. Replacing a CRD does not migrate the existing custom resources.")
+	cobraCmd.Flags().StringArrayVar(&o.ChartValues, "chart-values", nil, "Helm values file (e.g. istio=path/to/values.yaml) converted into the overrides of the component. The flag can be repeated, later files of the same component take precedence.")
+	cobraCmd.Flags().StringVarP(&o.ComponentsConfig, "components", "c", "", "Path to a YAML file with a component list to override. The current names of renamed components (eventing, serverless and api-gateway) are translated to the names of the selected release.")
 	cobraCmd.Flags().IntVar(&o.FallbackLevel, "fallback-level", 5, `If "source=master", defines the number of commits from master branch taken into account if artifacts for newer commits do not exist yet`)
 	cobraCmd.Flags().StringVarP(&o.CustomImage, "custom-image", "", "", "Full image name including the registry and the tag. Required for upgrading a remote cluster from local sources.")
-	cobraCmd.Flags().StringVarP(&o.Profile, "profile", "", "", "Kyma installation profile (evaluation|production).")
+	cobraCmd.Flags().StringVarP(&o.Profile, "profile", "", "", "Profile with the components and overrides of the installation (evaluation|full|minimal|production). Use \"kyma install profiles\" to list their contents. Explicit --components and --override files take precedence.")
+	cobraCmd.Flags().StringVarP(&o.InstallationName, "installation-name", "", "", "Name of the Kyma Installation CR. If not set, it is discovered from the cluster or taken from the Installation CR file.")
+	cobraCmd.Flags().BoolVar(&o.Force, "force", false, "Upgrades even if the target Kyma version or the Kyma version on the cluster is not supported by this Kyma CLI version, with a warning instead of an error.")
+	cobraCmd.Flags().BoolVar(&o.ForceUnlock, "force-unlock", false, "Overrides the lock held by another Kyma CLI instance changing the cluster. Use it only if that instance is no longer running.")
+	cobraCmd.Flags().DurationVar(&o.LockTTL, "lock-ttl", installation.DefaultLockTTL, "Time after which the lock held by another Kyma CLI instance is considered stale.")
+	cobraCmd.Flags().StringVarP(&o.ImagePullSecret, "set-image-pull-secret", "", "", "Path to a docker config JSON file with the credentials for a private registry. The CLI creates an image pull secret from it and configures the Kyma Installer to use it.")
+	cobraCmd.Flags().StringVarP(&o.RegistryServer, "registry-server", "", "", "Private registry server used to create the image pull secret. Use it together with --registry-user and --registry-password instead of --set-image-pull-secret.")
+	cobraCmd.Flags().StringVarP(&o.RegistryUser, "registry-user", "", "", "User of the private registry used to create the image pull secret.")
+	cobraCmd.Flags().StringVarP(&o.RegistryPassword, "registry-password", "", "", "Password of the private registry used to create the image pull secret.")
+	cobraCmd.Flags().StringSliceVarP(&o.ImagePullSecretNamespaces, "image-pull-secret-namespace", "", nil, "Additional namespaces (e.g. kyma-system) in which the image pull secret is created. The secret is always created in the kyma-installer namespace.")
+	cobraCmd.Flags().StringToStringVar(&o.ExtraLabels, "extra-label", nil, "Label (e.g. team=kyma) added to the metadata of all resources the CLI creates, such as the Kyma Installer, its namespace and the Installation CR. The flag can be repeated.")
+	cobraCmd.Flags().StringToStringVar(&o.ExtraAnnotations, "extra-annotation", nil, "Annotation (e.g. owner=kyma-team) added to the metadata of all resources the CLI creates. The flag can be repeated.")
+	cobraCmd.Flags().BoolVar(&o.SkipBackup, "skip-backup", false, "Skips backing up the Installation CR, the installer overrides and the list of CRDs before the upgrade.")
+	cobraCmd.Flags().StringVar(&o.BackupDir, "backup-dir", "", `Directory in which the timestamped backup is stored before the upgrade. (default "$HOME/.kyma/backups")`)
+	cobraCmd.Flags().BoolVar(&o.BackupRedactSecrets, "backup-redact-secrets", false, "Replaces the values of the backed up Secrets.")
+	cli.MarkPathFlags(cobraCmd, "src-path", "override", "components", "set-image-pull-secret", "backup-dir")
 	return cobraCmd
 }
 
@@ -66,16 +102,45 @@ func (cmd *command) Run() error {
 	}
 
 	var err error
-	if cmd.K8s, err = kube.NewFromConfigWithTimeout("", cmd.KubeconfigPath, cmd.opts.Timeout); err != nil {
+	if cmd.K8s, err = kube.NewFromConfigWithTimeout("", cmd.KubeconfigPath, cmd.opts.RequestTimeout); err != nil {
 		return errors.Wrap(err, "Could not initialize the Kubernetes client. Make sure your kubeconfig is valid")
 	}
+	// a stale kubeconfig would otherwise only fail with connection errors in later steps
+	if err := kube.CheckReachable(cmd.K8s); err != nil {
+		return err
+	}
+
+	if cmd.opts.Release != "" {
+		upToDate, err := cmd.resolveRelease()
+		if err != nil || upToDate {
+			return err
+		}
+	}
+
+	if cmd.opts.Source == releases.Latest {
+		s := cmd.NewStep("Resolving the latest Kyma release")
+		if cmd.opts.Source, err = releases.ResolveLatest(kymaVersion.DefaultKymaVersion, s.LogErrorf); err != nil {
+			s.Failure()
+			return err
+		}
+		s.Successf("Using Kyma release %s", cmd.opts.Source)
+	}
 
 	s := cmd.NewStep("Reading cluster info from ConfigMap")
 	clusterConfig, err := installation.GetClusterInfoFromConfigMap(cmd.K8s)
+	// restricted users are often not permitted to read the kube-system namespace, which must not stop the installation
+	if apiErrors.IsForbidden(err) {
+		s.LogErrorf("Warning: not permitted to read the cluster type from the kube-system namespace, so the cluster is treated as a remote cluster: %s", err)
+		err = nil
+	}
 	if err != nil {
 		s.Failure()
 		return err
 	}
+	clusterConfig.ResolveMinikubeProfile(cmd.opts.MinikubeProfile, cmd.KubeconfigPath, cmd.opts.Verbose, cmd.opts.Timeout)
+	if clusterConfig.Profile != "" {
+		s.LogInfof("Using the Minikube profile '%s'", clusterConfig.Profile)
+	}
 	s.Successf("Cluster info read")
 
 	i, err := cmd.configureInstallation(clusterConfig)
@@ -91,9 +156,10 @@ func (cmd *command) Run() error {
 		return nil
 	}
 
-	if clusterConfig.IsLocal {
+	// the domains are read from the virtual services of the components, so they are only complete once the installation finished
+	if clusterConfig.IsLocal && result.ClusterVersion.State == kymaVersion.Installed {
 		s = cmd.NewStep("Adding domains to /etc/hosts")
-		err = hosts.AddDevDomainsToEtcHosts(s, clusterConfig, cmd.K8s, cmd.opts.Verbose, cmd.opts.Timeout, cmd.opts.Domain)
+		err = hosts.AddDevDomainsToEtcHosts(s, clusterConfig, cmd.K8s, cmd.opts.Verbose, cmd.opts.Timeout, result.Domain)
 		if err != nil {
 			s.Failure()
 			return err
@@ -111,36 +177,67 @@ func (cmd *command) Run() error {
 
 func (cmd *command) configureInstallation(clusterConfig installation.ClusterInfo) (*installation.Installation, error) {
 
-	cmp, err := installation.LoadComponentsConfig(cmd.opts.ComponentsConfig)
+	cmp, err := installation.LoadComponents(cmd.opts.ComponentsConfig, cmd.opts.Profile)
 	if err != nil {
 		return &installation.Installation{}, errors.Wrap(err, "Could not load component configuration file. Make sure file is a valid YAML and contains a component list")
 	}
+	// the stable names of the components are translated to the names of the release, unmapped aliases are reported by the upgrade
+	cmp, _ = installation.ResolveComponentAliases(cmp, cmd.opts.Source)
 	s, err := installation.NewInstallationService(cmd.K8s.RestConfig(), cmd.opts.Timeout, "", cmp)
 	if err != nil {
 		return &installation.Installation{}, errors.Wrap(err, "Failed to create installation service. Make sure your kubeconfig is valid")
 	}
 
+	backupDir := ""
+	if !cmd.opts.SkipBackup {
+		if backupDir, err = cmd.backupDir(); err != nil {
+			return &installation.Installation{}, err
+		}
+	}
+
 	return &installation.Installation{
 		K8s:     cmd.K8s,
 		Service: s,
 		Options: &installation.Options{
-			NoWait:           cmd.opts.NoWait,
-			Verbose:          cmd.opts.Verbose,
-			CI:               cmd.opts.CI,
-			NonInteractive:   cmd.Factory.NonInteractive,
-			Timeout:          cmd.opts.Timeout,
-			CustomImage:      cmd.opts.CustomImage,
-			Domain:           cmd.opts.Domain,
-			TLSCert:          cmd.opts.TLSCert,
-			TLSKey:           cmd.opts.TLSKey,
-			LocalSrcPath:     cmd.opts.LocalSrcPath,
-			Password:         cmd.opts.Password,
-			OverrideConfigs:  cmd.opts.OverrideConfigs,
-			ComponentsConfig: cmd.opts.ComponentsConfig,
-			Source:           cmd.opts.Source,
-			FallbackLevel:    cmd.opts.FallbackLevel,
-			Profile:          cmd.opts.Profile,
-			IsLocal:          clusterConfig.IsLocal,
+			NoWait:                    cmd.opts.NoWait,
+			Verbose:                   cmd.opts.Verbose,
+			CI:                        cmd.opts.CI,
+			NonInteractive:            cmd.Factory.NonInteractive,
+			Timeout:                   cmd.opts.Timeout,
+			Retries:                   cmd.opts.Retries,
+			DockerTimeout:             cmd.opts.DockerTimeout,
+			ApplyTimeout:              cmd.opts.ApplyTimeout,
+			CustomImage:               cmd.opts.CustomImage,
+			Domain:                    cmd.opts.Domain,
+			TLSCert:                   cmd.opts.TLSCert,
+			TLSKey:                    cmd.opts.TLSKey,
+			LocalSrcPath:              cmd.opts.LocalSrcPath,
+			KeepSources:               cmd.opts.KeepSources,
+			PruneDocker:               cmd.opts.PruneDocker,
+			Password:                  cmd.opts.Password,
+			OverrideConfigs:           cmd.opts.OverrideConfigs,
+			Configs:                   cmd.opts.Configs,
+			InstallerManifest:         cmd.opts.InstallerManifest,
+			UpgradeCRDs:               cmd.opts.UpgradeCRDs,
+			ChartValues:               cmd.opts.ChartValues,
+			ComponentsConfig:          cmd.opts.ComponentsConfig,
+			Source:                    cmd.opts.Source,
+			FallbackLevel:             cmd.opts.FallbackLevel,
+			Profile:                   cmd.opts.Profile,
+			InstallationName:          cmd.opts.InstallationName,
+			Force:                     cmd.opts.Force,
+			ForceUnlock:               cmd.opts.ForceUnlock,
+			LockTTL:                   cmd.opts.LockTTL,
+			ImagePullSecret:           cmd.opts.ImagePullSecret,
+			RegistryServer:            cmd.opts.RegistryServer,
+			RegistryUser:              cmd.opts.RegistryUser,
+			RegistryPassword:          cmd.opts.RegistryPassword,
+			ImagePullSecretNamespaces: cmd.opts.ImagePullSecretNamespaces,
+			ExtraLabels:               cmd.opts.ExtraLabels,
+			ExtraAnnotations:          cmd.opts.ExtraAnnotations,
+			BackupDir:                 backupDir,
+			BackupRedactSecrets:       cmd.opts.BackupRedactSecrets,
+			IsLocal:                   clusterConfig.IsLocal,
 			LocalCluster: &installation.LocalCluster{
 				IP:       clusterConfig.LocalIP,
 				Profile:  clusterConfig.Profile,
@@ -151,20 +248,60 @@ func (cmd *command) configureInstallation(clusterConfig installation.ClusterInfo
 	}, nil
 }
 
+// backupDir returns the directory for the backup before the upgrade, by default the backups folder of the Kyma home directory
+func (cmd *command) backupDir() (string, error) {
+	if cmd.opts.BackupDir != "" {
+		return cmd.opts.BackupDir, nil
+	}
+	home, err := files.KymaHome()
+	if err != nil {
+		return "", errors.Wrap(err, "Could not determine the backup directory. Use the --backup-dir flag to set it, or --skip-backup to upgrade without a backup")
+	}
+	return filepath.Join(home, "backups"), nil
+}
+
 func (cmd *command) printSummary(result *installation.Result) error {
 	nicePrint := nice.Nice{
 		NonInteractive: cmd.Factory.NonInteractive,
+		Out:            cmd.Stdout(),
+	}
+
+	fmt.Fprintln(cmd.Stdout())
+	if result.ClusterVersion.State == kymaVersion.InProgress {
+		nicePrint.PrintKyma()
+		fmt.Fprint(cmd.Stdout(), " is being upgraded to version:\t")
+		nicePrint.PrintImportant(result.ClusterVersion.Version)
+		if !result.ClusterVersion.Since.IsZero() {
+			nicePrint.PrintKyma()
+			fmt.Fprint(cmd.Stdout(), " upgrade in progress since:\t")
+			nicePrint.PrintImportant(result.ClusterVersion.Since.Format(time.RFC1123))
+		}
+		return nil
+	}
+	if result.ClusterVersion.State == kymaVersion.Failed {
+		nicePrint.PrintKyma()
+		fmt.Fprint(cmd.Stdout(), " upgrade failed in version:\t")
+		nicePrint.PrintImportant(result.ClusterVersion.Version)
+		return nil
 	}
 
-	fmt.Println()
 	nicePrint.PrintKyma()
-	fmt.Print(" is upgraded to version:\t")
+	fmt.Fprint(cmd.Stdout(), " is upgraded to version:\t")
 	nicePrint.PrintImportant(result.KymaVersion)
 
 	nicePrint.PrintKyma()
-	fmt.Print(" upgrade took:\t\t")
+	fmt.Fprint(cmd.Stdout(), " upgrade took:\t\t")
 	nicePrint.PrintImportantf("%d hours %d minutes",
 		int64(result.Duration.Hours()), int64(result.Duration.Minutes()))
 
+	if len(result.ComponentDurations) > 0 {
+		nicePrint.PrintKyma()
+		fmt.Fprintln(cmd.Stdout(), " component durations:")
+		for _, c := range result.ComponentDurations {
+			fmt.Fprintf(cmd.Stdout(), "\t%-30s", c.Name)
+			nicePrint.PrintImportant(c.Duration.Round(time.Second).String())
+		}
+	}
+
 	return nil
 }
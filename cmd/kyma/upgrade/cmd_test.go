@@ -5,6 +5,8 @@ import (
 	"time"
 
 	"github.com/kyma-project/cli/internal/cli"
+	kymaVersion "github.com/kyma-project/cli/internal/version"
+	"github.com/kyma-project/cli/pkg/installation"
 	"github.com/stretchr/testify/require"
 )
 
@@ -15,45 +17,118 @@ func TestUpgradeFlags(t *testing.T) {
 	c := NewCmd(o)
 
 	// test default flag values
-	require.Equal(t, false, o.NoWait, "Default value for the noWait flag not as expected.")
+	require.Equal(t, false, o.NoWait, "Default value for the wait flag not as expected.")
 	require.Equal(t, defaultDomain, o.Domain, "Default value for the domain flag not as expected.")
 	require.Equal(t, "", o.TLSCert, "Default value for the tlsCert flag not as expected.")
 	require.Equal(t, "", o.TLSKey, "Default value for the tlsKey flag not as expected.")
-	require.Equal(t, DefaultKymaVersion, o.Source, "Default value for the source flag not as expected.")
+	require.Equal(t, kymaVersion.DefaultKymaVersion, o.Source, "Default value for the source flag not as expected.")
 	require.Equal(t, "", o.LocalSrcPath, "Default value for the src-path flag not as expected.")
 	require.Equal(t, 1*time.Hour, o.Timeout, "Default value for the timeout flag not as expected.")
+	require.Equal(t, 30*time.Second, o.RequestTimeout, "Default value for the request-timeout flag not as expected.")
+	require.Equal(t, 0, o.Retries, "Default value for the retries flag not as expected.")
+	require.Equal(t, 10*time.Minute, o.DockerTimeout, "Default value for the docker-timeout flag not as expected.")
+	require.Equal(t, 5*time.Minute, o.ApplyTimeout, "Default value for the apply-timeout flag not as expected.")
 	require.Equal(t, "", o.Password, "Default value for the password flag not as expected.")
 	require.Equal(t, []string([]string(nil)), o.OverrideConfigs, "Default value for the override flag not as expected.")
+	require.Equal(t, []string([]string(nil)), o.Configs, "Default value for the config flag not as expected.")
+	require.Equal(t, "", o.InstallerManifest, "Default value for the installer-manifest flag not as expected.")
+	require.Equal(t, false, o.UpgradeCRDs, "Default value for the upgrade-crds flag not as expected.")
+	require.Equal(t, []string([]string(nil)), o.ChartValues, "Default value for the chart-values flag not as expected.")
 	require.Equal(t, "", o.ComponentsConfig, "Default value for the components flag not as expected.")
 	require.Equal(t, 5, o.FallbackLevel, "Default value for the fallbackLevel flag not as expected.")
 	require.Equal(t, "", o.CustomImage, "Default value for the custom-image flag not as expected.")
+	require.Equal(t, false, o.ForceUnlock, "Default value for the force-unlock flag not as expected.")
+	require.Equal(t, installation.DefaultLockTTL, o.LockTTL, "Default value for the lock-ttl flag not as expected.")
+	require.Equal(t, "", o.InstallationName, "Default value for the installation-name flag not as expected.")
+	require.Equal(t, "", o.ImagePullSecret, "Default value for the set-image-pull-secret flag not as expected.")
+	require.Equal(t, "", o.RegistryServer, "Default value for the registry-server flag not as expected.")
+	require.Equal(t, "", o.RegistryUser, "Default value for the registry-user flag not as expected.")
+	require.Equal(t, "", o.RegistryPassword, "Default value for the registry-password flag not as expected.")
+	require.Equal(t, []string([]string(nil)), o.ImagePullSecretNamespaces, "Default value for the image-pull-secret-namespace flag not as expected.")
+	require.Equal(t, map[string]string(nil), o.ExtraLabels, "Default value for the extra-label flag not as expected.")
+	require.Equal(t, map[string]string(nil), o.ExtraAnnotations, "Default value for the extra-annotation flag not as expected.")
+	require.Equal(t, false, o.KeepSources, "Default value for the keep-sources flag not as expected.")
+	require.Equal(t, false, o.PruneDocker, "Default value for the prune-docker flag not as expected.")
+	require.Equal(t, false, o.Force, "Default value for the force flag not as expected.")
+	require.Equal(t, false, o.SkipBackup, "Default value for the skip-backup flag not as expected.")
+	require.Equal(t, "", o.BackupDir, "Default value for the backup-dir flag not as expected.")
+	require.Equal(t, false, o.BackupRedactSecrets, "Default value for the backup-redact-secrets flag not as expected.")
 
 	// test passing flags
 	err := c.ParseFlags([]string{
-		"-n", "true",
+		"--wait=false",
 		"-d", "fake-domain",
 		"--tls-cert", "fake-cert",
 		"--tls-key", "fake-key",
 		"-s", "test-registry/test-image:1",
 		"--src-path", "fake/path/to/source",
 		"--timeout", "100s",
+		"--request-timeout", "10s",
+		"--retries", "2",
+		"--docker-timeout", "20m",
+		"--apply-timeout", "15m",
 		"-p", "fake-pwd",
 		"-o", "fake/path/to/overrides",
+		"--config", "fake/path/to/base.yaml,fake/path/to/patch.yaml",
+		"--installer-manifest", "https://mirror.example.com/kyma-installer-cluster.yaml",
+		"--upgrade-crds",
+		"--chart-values", "istio=fake/path/to/values.yaml",
 		"-c", "fake/path/to/components",
 		"--fallback-level", "7",
 		"--custom-image", "test-registry/test-image:2",
+		"--installation-name", "fake-installation",
+		"--force-unlock",
+		"--lock-ttl", "30m",
+		"--set-image-pull-secret", "fake/path/to/docker/config.json",
+		"--registry-server", "fake-registry",
+		"--registry-user", "fake-user",
+		"--registry-password", "fake-registry-pwd",
+		"--image-pull-secret-namespace", "kyma-system",
+		"--extra-label", "team=kyma",
+		"--extra-label", "cost-center=1234",
+		"--extra-annotation", "owner=kyma-team",
+		"--keep-sources",
+		"--prune-docker",
+		"--force",
+		"--skip-backup",
+		"--backup-dir", "/fake/backups",
+		"--backup-redact-secrets",
 	})
 	require.NoError(t, err, "Parsing flags should not return an error")
-	require.Equal(t, true, o.NoWait, "The parsed value for the noWait flag not as expected.")
+	require.Equal(t, true, o.NoWait, "The parsed value for the wait flag not as expected.")
 	require.Equal(t, "fake-domain", o.Domain, "The parsed value for the domain flag not as expected.")
 	require.Equal(t, "fake-cert", o.TLSCert, "The parsed value for the tlsCert flag not as expected.")
 	require.Equal(t, "fake-key", o.TLSKey, "The parsed value for the tlsKey flag not as expected.")
 	require.Equal(t, "test-registry/test-image:1", o.Source, "The parsed value for the source flag not as expected.")
 	require.Equal(t, "fake/path/to/source", o.LocalSrcPath, "The parsed value for the src-path flag not as expected.")
 	require.Equal(t, 100*time.Second, o.Timeout, "The parsed value for the timeout flag not as expected.")
+	require.Equal(t, 10*time.Second, o.RequestTimeout, "The parsed value for the request-timeout flag not as expected.")
+	require.Equal(t, 2, o.Retries, "The parsed value for the retries flag not as expected.")
+	require.Equal(t, 20*time.Minute, o.DockerTimeout, "The parsed value for the docker-timeout flag not as expected.")
+	require.Equal(t, 15*time.Minute, o.ApplyTimeout, "The parsed value for the apply-timeout flag not as expected.")
 	require.Equal(t, "fake-pwd", o.Password, "The parsed value for the password flag not as expected.")
 	require.Equal(t, []string([]string{"fake/path/to/overrides"}), o.OverrideConfigs, "The parsed value for the override flag not as expected.")
+	require.Equal(t, []string{"fake/path/to/base.yaml", "fake/path/to/patch.yaml"}, o.Configs, "The parsed value for the config flag not as expected.")
+	require.Equal(t, "https://mirror.example.com/kyma-installer-cluster.yaml", o.InstallerManifest, "The parsed value for the installer-manifest flag not as expected.")
+	require.Equal(t, true, o.UpgradeCRDs, "The parsed value for the upgrade-crds flag not as expected.")
+	require.Equal(t, []string{"istio=fake/path/to/values.yaml"}, o.ChartValues, "The parsed value for the chart-values flag not as expected.")
 	require.Equal(t, "fake/path/to/components", o.ComponentsConfig, "The parsed value for the components flag not as expected.")
 	require.Equal(t, 7, o.FallbackLevel, "The parsed value for the fallbackLevel flag not as expected.")
 	require.Equal(t, "test-registry/test-image:2", o.CustomImage, "The parsed value for the custom-image flag not as expected.")
+	require.Equal(t, true, o.ForceUnlock, "The parsed value for the force-unlock flag not as expected.")
+	require.Equal(t, 30*time.Minute, o.LockTTL, "The parsed value for the lock-ttl flag not as expected.")
+	require.Equal(t, "fake-installation", o.InstallationName, "The parsed value for the installation-name flag not as expected.")
+	require.Equal(t, "fake/path/to/docker/config.json", o.ImagePullSecret, "The parsed value for the set-image-pull-secret flag not as expected.")
+	require.Equal(t, "fake-registry", o.RegistryServer, "The parsed value for the registry-server flag not as expected.")
+	require.Equal(t, "fake-user", o.RegistryUser, "The parsed value for the registry-user flag not as expected.")
+	require.Equal(t, "fake-registry-pwd", o.RegistryPassword, "The parsed value for the registry-password flag not as expected.")
+	require.Equal(t, []string{"kyma-system"}, o.ImagePullSecretNamespaces, "The parsed value for the image-pull-secret-namespace flag not as expected.")
+	require.Equal(t, map[string]string{"team": "kyma", "cost-center": "1234"}, o.ExtraLabels, "The parsed value for the extra-label flag not as expected.")
+	require.Equal(t, map[string]string{"owner": "kyma-team"}, o.ExtraAnnotations, "The parsed value for the extra-annotation flag not as expected.")
+	require.Equal(t, true, o.KeepSources, "The parsed value for the keep-sources flag not as expected.")
+	require.Equal(t, true, o.PruneDocker, "The parsed value for the prune-docker flag not as expected.")
+	require.Equal(t, true, o.Force, "The parsed value for the force flag not as expected.")
+	require.Equal(t, true, o.SkipBackup, "The parsed value for the skip-backup flag not as expected.")
+	require.Equal(t, "/fake/backups", o.BackupDir, "The parsed value for the backup-dir flag not as expected.")
+	require.Equal(t, true, o.BackupRedactSecrets, "The parsed value for the backup-redact-secrets flag not as expected.")
 }
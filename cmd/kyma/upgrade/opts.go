@@ -6,25 +6,47 @@ import (
 	"github.com/kyma-project/cli/internal/cli"
 )
 
-//DefaultKymaVersion contains the default target Kyma version for upgrade in case another version is not specified
-var DefaultKymaVersion string
-
 //Options defines available options for the command
 type Options struct {
 	*cli.Options
-	NoWait           bool
-	Domain           string
-	TLSCert          string
-	TLSKey           string
-	LocalSrcPath     string
-	Timeout          time.Duration
-	Password         string
-	OverrideConfigs  []string
-	ComponentsConfig string
-	Source           string
-	FallbackLevel    int
-	CustomImage      string
-	Profile          string
+	NoWait                    bool
+	Domain                    string
+	TLSCert                   string
+	TLSKey                    string
+	LocalSrcPath              string
+	KeepSources               bool
+	PruneDocker               bool
+	Timeout                   time.Duration
+	RequestTimeout            time.Duration
+	Retries                   int
+	DockerTimeout             time.Duration
+	ApplyTimeout              time.Duration
+	Password                  string
+	OverrideConfigs           []string
+	Configs                   []string
+	InstallerManifest         string
+	UpgradeCRDs               bool
+	ChartValues               []string
+	ComponentsConfig          string
+	Source                    string
+	Release                   string
+	FallbackLevel             int
+	CustomImage               string
+	Profile                   string
+	InstallationName          string
+	Force                     bool
+	ForceUnlock               bool
+	LockTTL                   time.Duration
+	ImagePullSecret           string
+	RegistryServer            string
+	RegistryUser              string
+	RegistryPassword          string
+	ImagePullSecretNamespaces []string
+	ExtraLabels               map[string]string
+	ExtraAnnotations          map[string]string
+	SkipBackup                bool
+	BackupDir                 string
+	BackupRedactSecrets       bool
 }
 
 //NewOptions creates options with default values
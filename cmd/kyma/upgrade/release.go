@@ -0,0 +1,45 @@
+package upgrade
+
+import (
+	"fmt"
+
+	"github.com/kyma-project/cli/internal/releases"
+	kymaVersion "github.com/kyma-project/cli/internal/version"
+)
+
+// resolveRelease sets the source to the version the release channel given with --release points to.
+// For the stable channel, the newest release the cluster can be upgraded to is used, so that repeated upgrades reach the newest stable release.
+// It returns true if the cluster already runs the newest stable release.
+func (cmd *command) resolveRelease() (bool, error) {
+	if cmd.opts.Source != kymaVersion.DefaultKymaVersion {
+		return false, fmt.Errorf("--release and --source cannot be used together")
+	}
+	s := cmd.NewStep(fmt.Sprintf("Resolving the release channel '%s'", cmd.opts.Release))
+	r, err := releases.ResolveChannel(cmd.opts.Release, s.LogErrorf)
+	if err != nil {
+		s.Failure()
+		return false, err
+	}
+	if r.Pinned {
+		s.LogInfof("Using the version pinned with %s", releases.ChannelEnv(r.Channel))
+	}
+	cmd.opts.Source = r.Version
+
+	if r.Channel == releases.ChannelStable && !r.Pinned {
+		cv, err := kymaVersion.ClusterKymaVersion(cmd.K8s)
+		if err != nil {
+			s.LogErrorf("Warning: unable to determine the Kyma version on the cluster, upgrading to %s: %s", r.Version, err)
+		} else if cv.Version == r.Version {
+			s.Successf("Kyma %s on the cluster is the newest stable release", cv.Version)
+			return true, nil
+		} else if list, err := releases.List(false); err == nil {
+			if target, ok := releases.UpgradeTarget(cv.Version, list); ok && target != r.Version {
+				s.LogInfof("Kyma %s cannot be upgraded to %s directly. Upgrading to %s, run the command again to continue", cv.Version, r.Version, target)
+				r.Version = target
+				cmd.opts.Source = target
+			}
+		}
+	}
+	s.Successf("Release channel '%s' resolved to Kyma %s (%s)", r.Channel, r.Version, r.Artifacts())
+	return false, nil
+}
@@ -1,21 +1,20 @@
 package version
 
 import (
-	"context"
 	"fmt"
-	"strings"
+	"os"
 	"time"
 
+	"github.com/kyma-project/cli/internal/cli"
 	"github.com/kyma-project/cli/internal/kube"
+	"github.com/kyma-project/cli/internal/metadata"
+	"github.com/kyma-project/cli/internal/releases"
+	kymaVersion "github.com/kyma-project/cli/internal/version"
 	"github.com/pkg/errors"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"github.com/spf13/cobra"
 )
 
-//Version contains the cli binary version injected by the build system
-var Version string
-
 type command struct {
 	opts *Options
 }
@@ -30,6 +29,7 @@ func NewCmd(o *Options) *cobra.Command {
 		Use:   "version",
 		Short: "Displays the version of Kyma CLI and the connected Kyma cluster.",
 		Long: `Use this command to print the version of Kyma CLI and the version of the Kyma cluster the current kubeconfig points to.
+It also prints the default Kyma version of the "install" and "upgrade" commands, and the newest stable Kyma release which "--source=latest" resolves to.
 `,
 		RunE: func(_ *cobra.Command, _ []string) error { return c.Run() },
 	}
@@ -39,11 +39,24 @@ func NewCmd(o *Options) *cobra.Command {
 
 //Run runs the command
 func (c command) Run() error {
-	version := Version
+	version := cli.Version
 	if version == "" {
 		version = "N/A"
 	}
-	fmt.Printf("Kyma CLI version: %s\n", version)
+	fmt.Fprintf(c.opts.Stdout(), "Kyma CLI version: %s\n", version)
+
+	defaultVersion := kymaVersion.DefaultKymaVersion
+	if defaultVersion == "" {
+		defaultVersion = "N/A"
+	}
+	fmt.Fprintf(c.opts.Stdout(), "Default Kyma version: %s\n", defaultVersion)
+	latest, err := releases.ResolveLatest(kymaVersion.DefaultKymaVersion, func(format string, args ...interface{}) {
+		fmt.Fprintf(os.Stderr, "Warning: "+format+"\n", args...)
+	})
+	if err != nil {
+		latest = fmt.Sprintf("N/A (%s)", err)
+	}
+	fmt.Fprintf(c.opts.Stdout(), "Latest Kyma release: %s\n", latest)
 
 	if !c.opts.Client {
 		k8s, err := kube.NewFromConfigWithTimeout("", c.opts.KubeconfigPath, 2*time.Second)
@@ -51,32 +64,16 @@ func (c command) Run() error {
 			return errors.Wrap(err, "Could not initialize the Kubernetes client. Make sure your kubeconfig is valid")
 		}
 
-		version, err := KymaVersion(k8s)
+		version, err := kymaVersion.ClusterKymaVersion(k8s)
 		if err != nil {
-			fmt.Printf("Unable to get Kyma cluster version due to error: %s. Check if your cluster is available and has Kyma installed\r\n", err.Error())
+			fmt.Fprintf(c.opts.Stdout(), "Unable to get Kyma cluster version due to error: %s. Check if your cluster is available and has Kyma installed\r\n", err.Error())
 			return nil
 		}
-		fmt.Printf("Kyma cluster version: %s\n", version)
+		fmt.Fprintf(c.opts.Stdout(), "Kyma cluster version: %s\n", version)
+		if selectors := metadata.Latest().InstallerSelectors; version.State != kymaVersion.NotInstalled && version.InstallerSelector != selectors[0] {
+			fmt.Fprintf(c.opts.Stdout(), "The Kyma Installer was found with the label selector '%s' of an older release\n", version.InstallerSelector)
+		}
 	}
 
 	return nil
 }
-
-//KymaVersion determines the version of kyma installed in the cluster sccessible via the provided kubernetes client
-func KymaVersion(k8s kube.KymaKube) (string, error) {
-	pods, err := k8s.Static().CoreV1().Pods("kyma-installer").List(context.Background(), metav1.ListOptions{LabelSelector: "name=kyma-installer"})
-	if err != nil {
-		return "", err
-	}
-
-	if len(pods.Items) == 0 {
-		return "N/A", nil
-	}
-
-	imageParts := strings.Split(pods.Items[0].Spec.Containers[0].Image, ":")
-	if len(imageParts) < 2 {
-		return "N/A", nil
-	}
-
-	return imageParts[1], nil
-}
@@ -0,0 +1,24 @@
+package wait
+
+import (
+	"github.com/spf13/cobra"
+)
+
+const (
+	// ExitCodeTimeout is the exit code of the wait commands if the timeout is reached
+	ExitCodeTimeout = 2
+	// ExitCodeFailure is the exit code of the wait commands if the awaited resource failed and will not become ready
+	ExitCodeFailure = 3
+)
+
+//NewCmd creates a new wait command
+func NewCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "wait",
+		Short: "Waits until resources of the Kyma cluster are ready.",
+		Long: `Use this command to wait in scripts until resources of the Kyma cluster are ready.
+The command exits with 0 if the resources are ready, with 2 if the timeout is reached, with 3 if a resource failed, and with 1 for any other error.
+`,
+	}
+	return cmd
+}
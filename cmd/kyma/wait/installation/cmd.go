@@ -0,0 +1,75 @@
+package installation
+
+import (
+	"errors"
+	"time"
+
+	"github.com/kyma-project/cli/cmd/kyma/wait"
+	"github.com/kyma-project/cli/internal/cli"
+	"github.com/kyma-project/cli/internal/kube"
+	"github.com/kyma-project/cli/pkg/installation"
+	pkgErrors "github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+type command struct {
+	opts *Options
+	cli.Command
+}
+
+//NewCmd creates a new wait installation command
+func NewCmd(o *Options) *cobra.Command {
+	cmd := command{
+		Command: cli.Command{Options: o.Options},
+		opts:    o,
+	}
+
+	cobraCmd := &cobra.Command{
+		Use:   "installation",
+		Short: "Waits until the Kyma installation is completed.",
+		Long: `Use this command to wait until the Kyma Installer reports Kyma as installed, for example after running "kyma install --wait=false".
+The progress is displayed as during the installation. If the installation is not completed within the timeout, the command exits with 2.
+`,
+		RunE: func(_ *cobra.Command, _ []string) error { return cmd.Run() },
+	}
+
+	cobraCmd.Flags().StringVar(&o.InstallationName, "installation-name", "", "Name of the Kyma Installation CR. If not set, it is discovered from the cluster.")
+	cobraCmd.Flags().DurationVar(&o.Timeout, "timeout", 1*time.Hour, "Maximum time to wait for the installation. Use 0 to wait without a timeout.")
+	return cobraCmd
+}
+
+//Run runs the command
+func (cmd *command) Run() error {
+	if cmd.opts.CI {
+		cmd.Factory.NonInteractive = true
+	}
+
+	var err error
+	if cmd.K8s, err = kube.NewFromConfig("", cmd.KubeconfigPath); err != nil {
+		return pkgErrors.Wrap(err, "Could not initialize the Kubernetes client. Make sure your kubeconfig is valid")
+	}
+	s, err := installation.NewInstallationService(cmd.K8s.RestConfig(), cmd.opts.Timeout, "", nil)
+	if err != nil {
+		return pkgErrors.Wrap(err, "Failed to create installation service. Make sure your kubeconfig is valid")
+	}
+
+	i := &installation.Installation{
+		K8s:     cmd.K8s,
+		Service: s,
+		Options: &installation.Options{
+			Verbose:          cmd.opts.Verbose,
+			CI:               cmd.opts.CI,
+			NonInteractive:   cmd.Factory.NonInteractive,
+			Timeout:          cmd.opts.Timeout,
+			InstallationName: cmd.opts.InstallationName,
+		},
+		Factory: cmd.Factory,
+	}
+	if err := i.WaitForInstallation(); err != nil {
+		if errors.Is(err, installation.ErrInstallationTimeout) {
+			return &cli.ExitError{Code: wait.ExitCodeTimeout, Err: err}
+		}
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,29 @@
+package installation
+
+import (
+	"testing"
+	"time"
+
+	"github.com/kyma-project/cli/internal/cli"
+	"github.com/stretchr/testify/require"
+)
+
+// TestWaitInstallationFlags ensures that the provided command flags are stored in the options.
+func TestWaitInstallationFlags(t *testing.T) {
+	t.Parallel()
+	o := NewOptions(&cli.Options{})
+	c := NewCmd(o)
+
+	// test default flag values
+	require.Equal(t, "", o.InstallationName, "Default value for the installation-name flag not as expected.")
+	require.Equal(t, 1*time.Hour, o.Timeout, "Default value for the timeout flag not as expected.")
+
+	// test passing flags
+	err := c.ParseFlags([]string{
+		"--installation-name", "my-installation",
+		"--timeout", "10m",
+	})
+	require.NoError(t, err, "Parsing flags should not return an error")
+	require.Equal(t, "my-installation", o.InstallationName, "The parsed value for the installation-name flag not as expected.")
+	require.Equal(t, 10*time.Minute, o.Timeout, "The parsed value for the timeout flag not as expected.")
+}
@@ -0,0 +1,19 @@
+package installation
+
+import (
+	"time"
+
+	"github.com/kyma-project/cli/internal/cli"
+)
+
+//Options defines available options for the wait installation command
+type Options struct {
+	*cli.Options
+	InstallationName string
+	Timeout          time.Duration
+}
+
+//NewOptions creates options with default values
+func NewOptions(o *cli.Options) *Options {
+	return &Options{Options: o}
+}
@@ -0,0 +1,79 @@
+package pod
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/kyma-project/cli/cmd/kyma/wait"
+	"github.com/kyma-project/cli/internal/cli"
+	"github.com/kyma-project/cli/internal/kube"
+	pkgErrors "github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+type command struct {
+	opts *Options
+	cli.Command
+}
+
+//NewCmd creates a new wait pod command
+func NewCmd(o *Options) *cobra.Command {
+	cmd := command{
+		Command: cli.Command{Options: o.Options},
+		opts:    o,
+	}
+
+	cobraCmd := &cobra.Command{
+		Use:   "pod",
+		Short: "Waits until the pods matching a label selector are ready.",
+		Long: `Use this command to wait until at least one pod matches the label selector and all matching pods are ready or completed.
+The command fails immediately with exit code 3 if a pod failed or cannot start, for example because its image cannot be pulled or its container is in CrashLoopBackOff.
+If the pods are not ready within the timeout, the command exits with 2.
+`,
+		Example: "kyma wait pod --namespace kyma-system --selector app=console --timeout 5m",
+		RunE:    func(_ *cobra.Command, _ []string) error { return cmd.Run() },
+	}
+
+	cobraCmd.Flags().StringVarP(&o.Namespace, "namespace", "n", "", "Namespace of the pods. If not set, the namespace of the current kubeconfig context is used.")
+	cobraCmd.Flags().StringVarP(&o.Selector, "selector", "l", "", "Label selector of the pods (for example, app=console).")
+	cobraCmd.Flags().DurationVar(&o.Timeout, "timeout", 5*time.Minute, "Maximum time to wait for the pods. Use 0 to wait without a timeout.")
+	return cobraCmd
+}
+
+//Run runs the command
+func (cmd *command) Run() error {
+	if cmd.opts.CI {
+		cmd.Factory.NonInteractive = true
+	}
+
+	if cmd.opts.Selector == "" {
+		return errors.New("the --selector flag is required, for example --selector app=console")
+	}
+
+	var err error
+	if cmd.K8s, err = kube.NewFromConfig("", cmd.KubeconfigPath); err != nil {
+		return pkgErrors.Wrap(err, "Could not initialize the Kubernetes client. Make sure your kubeconfig is valid")
+	}
+	namespace := cmd.opts.Namespace
+	if namespace == "" {
+		namespace = cmd.K8s.DefaultNamespace()
+	}
+
+	s := cmd.NewStep(fmt.Sprintf("Waiting for the pods '%s' in the namespace '%s'", cmd.opts.Selector, namespace))
+	err = kube.WaitForPods(cmd.K8s.Static(), namespace, cmd.opts.Selector, cmd.opts.Timeout)
+	if err == nil {
+		s.Successf("Pods '%s' in the namespace '%s' are ready", cmd.opts.Selector, namespace)
+		return nil
+	}
+	s.Failure()
+
+	failed := &kube.PodFailedError{}
+	switch {
+	case errors.Is(err, kube.ErrWaitTimeout):
+		return &cli.ExitError{Code: wait.ExitCodeTimeout, Err: err}
+	case errors.As(err, &failed):
+		return &cli.ExitError{Code: wait.ExitCodeFailure, Err: err}
+	}
+	return err
+}
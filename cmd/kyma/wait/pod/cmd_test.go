@@ -0,0 +1,32 @@
+package pod
+
+import (
+	"testing"
+	"time"
+
+	"github.com/kyma-project/cli/internal/cli"
+	"github.com/stretchr/testify/require"
+)
+
+// TestWaitPodFlags ensures that the provided command flags are stored in the options.
+func TestWaitPodFlags(t *testing.T) {
+	t.Parallel()
+	o := NewOptions(&cli.Options{})
+	c := NewCmd(o)
+
+	// test default flag values
+	require.Equal(t, "", o.Namespace, "Default value for the namespace flag not as expected.")
+	require.Equal(t, "", o.Selector, "Default value for the selector flag not as expected.")
+	require.Equal(t, 5*time.Minute, o.Timeout, "Default value for the timeout flag not as expected.")
+
+	// test passing flags
+	err := c.ParseFlags([]string{
+		"-n", "kyma-system",
+		"-l", "app=console",
+		"--timeout", "30s",
+	})
+	require.NoError(t, err, "Parsing flags should not return an error")
+	require.Equal(t, "kyma-system", o.Namespace, "The parsed value for the namespace flag not as expected.")
+	require.Equal(t, "app=console", o.Selector, "The parsed value for the selector flag not as expected.")
+	require.Equal(t, 30*time.Second, o.Timeout, "The parsed value for the timeout flag not as expected.")
+}
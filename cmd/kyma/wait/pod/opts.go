@@ -0,0 +1,20 @@
+package pod
+
+import (
+	"time"
+
+	"github.com/kyma-project/cli/internal/cli"
+)
+
+//Options defines available options for the wait pod command
+type Options struct {
+	*cli.Options
+	Namespace string
+	Selector  string
+	Timeout   time.Duration
+}
+
+//NewOptions creates options with default values
+func NewOptions(o *cli.Options) *Options {
+	return &Options{Options: o}
+}
@@ -7,18 +7,26 @@ import (
 	"syscall"
 
 	"github.com/kyma-project/cli/cmd/kyma"
+	"github.com/kyma-project/cli/cmd/kyma/plugin"
 	"github.com/kyma-project/cli/internal/cli"
+	"github.com/kyma-project/cli/internal/trace"
 
 	_ "k8s.io/client-go/plugin/pkg/client/auth/gcp"
 )
 
 func main() {
 	setupCloseHandler()
-	command := kyma.NewCmd(cli.NewOptions())
+	o := cli.NewOptions()
+	command := kyma.NewCmd(o)
+	// the called plugin is added after the built-in commands, so that they take precedence
+	plugin.AddPlugin(command, o, os.Args[1:])
 
 	err := command.Execute()
+	if traceErr := trace.Flush(err); traceErr != nil {
+		fmt.Fprintf(os.Stderr, "Unable to write the trace file: %s\n", traceErr)
+	}
 	if err != nil {
-		os.Exit(1)
+		os.Exit(cli.ExitCode(err))
 	}
 
 }
@@ -28,6 +36,11 @@ func setupCloseHandler() {
 	signal.Notify(c, syscall.SIGTERM, syscall.SIGINT)
 	go func() {
 		sig := <-c
+		// a running installation releases the cluster lock and returns, so that its cleanup runs before the CLI exits
+		if cli.InterruptsHandled() {
+			fmt.Printf("\r- Signal '%v' received from Terminal. Stopping, send it again to exit immediately...\n ", sig)
+			sig = <-c
+		}
 		fmt.Printf("\r- Signal '%v' received from Terminal. Exiting...\n ", sig)
 		os.Exit(0)
 	}()
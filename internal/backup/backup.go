@@ -0,0 +1,183 @@
+// Package backup stores a snapshot of the Kyma installation state of a cluster, so that a failed change of the installation can be rolled back manually.
+package backup
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/kyma-project/cli/internal/kube"
+	"github.com/kyma-project/cli/internal/metadata"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apiErrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	dirPrefix = "kyma-backup-"
+
+	overridesSelector = "installer=overrides"
+	redactedValue     = "<redacted>"
+
+	installationFile = "installation.yaml"
+	configMapsFile   = "overrides-configmaps.yaml"
+	secretsFile      = "overrides-secrets.yaml"
+	crdsFile         = "crds.txt"
+)
+
+// Options holds the settings of a backup.
+type Options struct {
+	// Dir is the directory in which the timestamped backup directory is created.
+	Dir string
+	// InstallationName is the name of the Installation CR to back up.
+	InstallationName string
+	// RedactSecrets enables replacing the values of the backed up Secrets.
+	RedactSecrets bool
+}
+
+// Create stores the Installation CR, the override ConfigMaps and Secrets of the Kyma Installer and the names of all CRDs
+// in a new timestamped directory and returns its path.
+// A failed backup leaves no partial directory behind.
+func Create(k8s kube.KymaKube, opts Options) (string, error) {
+	dir := filepath.Join(opts.Dir, dirPrefix+time.Now().Format("20060102-150405"))
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return "", errors.Wrapf(err, "unable to create the backup directory '%s'", dir)
+	}
+	if err := write(k8s, dir, opts); err != nil {
+		os.RemoveAll(dir)
+		return "", err
+	}
+	return dir, nil
+}
+
+// write stores all backup files in the directory
+func write(k8s kube.KymaKube, dir string, opts Options) error {
+	m := metadata.Latest()
+	cr, err := k8s.Dynamic().Resource(m.InstallationGVR()).Namespace(m.InstallationNamespace).Get(context.Background(), opts.InstallationName, metav1.GetOptions{})
+	if err != nil {
+		return errors.Wrapf(err, "unable to read the Installation CR '%s'", opts.InstallationName)
+	}
+	if err := writeDocuments(filepath.Join(dir, installationFile), cleanUnstructured(cr)); err != nil {
+		return err
+	}
+
+	listOpts := metav1.ListOptions{LabelSelector: overridesSelector}
+	configMaps, err := k8s.Static().CoreV1().ConfigMaps(m.InstallerNamespace).List(context.Background(), listOpts)
+	if err != nil {
+		return errors.Wrap(err, "unable to read the override ConfigMaps")
+	}
+	var docs []interface{}
+	for n := range configMaps.Items {
+		cm := configMaps.Items[n]
+		cm.TypeMeta = metav1.TypeMeta{APIVersion: "v1", Kind: "ConfigMap"}
+		cm.ObjectMeta = cleanMeta(cm.ObjectMeta)
+		docs = append(docs, cm)
+	}
+	if err := writeDocuments(filepath.Join(dir, configMapsFile), docs...); err != nil {
+		return err
+	}
+
+	secrets, err := k8s.Static().CoreV1().Secrets(m.InstallerNamespace).List(context.Background(), listOpts)
+	if err != nil {
+		return errors.Wrap(err, "unable to read the override Secrets")
+	}
+	docs = nil
+	for n := range secrets.Items {
+		secret := secrets.Items[n]
+		secret.TypeMeta = metav1.TypeMeta{APIVersion: "v1", Kind: "Secret"}
+		secret.ObjectMeta = cleanMeta(secret.ObjectMeta)
+		if opts.RedactSecrets {
+			secret = redact(secret)
+		}
+		docs = append(docs, secret)
+	}
+	if err := writeDocuments(filepath.Join(dir, secretsFile), docs...); err != nil {
+		return err
+	}
+
+	crds, err := crdNames(k8s)
+	if err != nil {
+		return err
+	}
+	if err := ioutil.WriteFile(filepath.Join(dir, crdsFile), []byte(strings.Join(crds, "\n")+"\n"), 0600); err != nil {
+		return errors.Wrapf(err, "unable to write the backup file '%s'", crdsFile)
+	}
+
+	return nil
+}
+
+// crdNames returns the sorted names of all CRDs, older clusters only serve the v1beta1 API
+func crdNames(k8s kube.KymaKube) ([]string, error) {
+	var list *unstructured.UnstructuredList
+	var err error
+	for _, version := range []string{"v1", "v1beta1"} {
+		gvr := schema.GroupVersionResource{Group: "apiextensions.k8s.io", Version: version, Resource: "customresourcedefinitions"}
+		if list, err = k8s.Dynamic().Resource(gvr).List(context.Background(), metav1.ListOptions{}); err == nil || !apiErrors.IsNotFound(err) {
+			break
+		}
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to list the CRDs")
+	}
+	names := make([]string, 0, len(list.Items))
+	for _, crd := range list.Items {
+		names = append(names, crd.GetName())
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// writeDocuments stores the objects as a multi-document YAML file
+func writeDocuments(file string, objects ...interface{}) error {
+	var docs []string
+	for _, obj := range objects {
+		data, err := yaml.Marshal(obj)
+		if err != nil {
+			return errors.Wrapf(err, "unable to encode the backup of '%s'", filepath.Base(file))
+		}
+		docs = append(docs, string(data))
+	}
+	if err := ioutil.WriteFile(file, []byte(strings.Join(docs, "---\n")), 0600); err != nil {
+		return errors.Wrapf(err, "unable to write the backup file '%s'", file)
+	}
+	return nil
+}
+
+// cleanMeta removes the metadata set by the API server, so that the backup can be applied again
+func cleanMeta(meta metav1.ObjectMeta) metav1.ObjectMeta {
+	return metav1.ObjectMeta{
+		Name:        meta.Name,
+		Namespace:   meta.Namespace,
+		Labels:      meta.Labels,
+		Annotations: meta.Annotations,
+	}
+}
+
+func cleanUnstructured(u *unstructured.Unstructured) map[string]interface{} {
+	obj := u.DeepCopy().Object
+	for _, field := range []string{"resourceVersion", "uid", "selfLink", "creationTimestamp", "generation", "managedFields"} {
+		unstructured.RemoveNestedField(obj, "metadata", field)
+	}
+	return obj
+}
+
+func redact(secret corev1.Secret) corev1.Secret {
+	stringData := make(map[string]string, len(secret.Data)+len(secret.StringData))
+	for k := range secret.Data {
+		stringData[k] = redactedValue
+	}
+	for k := range secret.StringData {
+		stringData[k] = redactedValue
+	}
+	secret.Data = nil
+	secret.StringData = stringData
+	return secret
+}
@@ -0,0 +1,114 @@
+package backup
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	k8sMocks "github.com/kyma-project/cli/internal/kube/mocks"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	fakeDynamic "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func fakeKube() *k8sMocks.KymaKube {
+	installation := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "installer.kyma-project.io/v1alpha1",
+		"kind":       "Installation",
+		"metadata": map[string]interface{}{
+			"name":            "kyma-installation",
+			"namespace":       "default",
+			"resourceVersion": "42",
+			"uid":             "fake-uid",
+		},
+		"spec": map[string]interface{}{"version": "1.15.0"},
+	}}
+	crd := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "apiextensions.k8s.io/v1",
+		"kind":       "CustomResourceDefinition",
+		"metadata":   map[string]interface{}{"name": "installations.installer.kyma-project.io"},
+	}}
+	overrides := map[string]string{"installer": "overrides"}
+	static := fake.NewSimpleClientset(
+		&corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "global-overrides", Namespace: "kyma-installer", Labels: overrides, ResourceVersion: "7"},
+			Data:       map[string]string{"global.domainName": "kyma.local"},
+		},
+		&corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "unrelated", Namespace: "kyma-installer"},
+		},
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "admin-overrides", Namespace: "kyma-installer", Labels: overrides},
+			Data:       map[string][]byte{"global.adminPassword": []byte("s3cr3t")},
+		},
+	)
+
+	kymaMock := &k8sMocks.KymaKube{}
+	kymaMock.On("Static").Return(static)
+	kymaMock.On("Dynamic").Return(fakeDynamic.NewSimpleDynamicClient(runtime.NewScheme(), installation, crd))
+	return kymaMock
+}
+
+func tempDir(t *testing.T) string {
+	dir, err := ioutil.TempDir("", "kyma-backup-test")
+	require.NoError(t, err)
+	t.Cleanup(func() { os.RemoveAll(dir) })
+	return dir
+}
+
+func readFile(t *testing.T, dir, file string) string {
+	data, err := ioutil.ReadFile(filepath.Join(dir, file))
+	require.NoError(t, err)
+	return string(data)
+}
+
+func TestCreate(t *testing.T) {
+	t.Run("Back up the installation state", func(t *testing.T) {
+		dir, err := Create(fakeKube(), Options{Dir: tempDir(t), InstallationName: "kyma-installation"})
+		require.NoError(t, err)
+		require.True(t, strings.HasPrefix(filepath.Base(dir), dirPrefix))
+
+		cr := readFile(t, dir, installationFile)
+		require.Contains(t, cr, "name: kyma-installation")
+		require.Contains(t, cr, "version: 1.15.0")
+		require.NotContains(t, cr, "resourceVersion")
+		require.NotContains(t, cr, "uid")
+
+		cms := readFile(t, dir, configMapsFile)
+		require.Contains(t, cms, "kind: ConfigMap")
+		require.Contains(t, cms, "global.domainName: kyma.local")
+		require.NotContains(t, cms, "unrelated")
+		require.NotContains(t, cms, "resourceVersion")
+
+		secrets := readFile(t, dir, secretsFile)
+		require.Contains(t, secrets, "kind: Secret")
+		require.Contains(t, secrets, "global.adminPassword: czNjcjN0")
+
+		require.Equal(t, "installations.installer.kyma-project.io\n", readFile(t, dir, crdsFile))
+	})
+
+	t.Run("Redact secrets", func(t *testing.T) {
+		dir, err := Create(fakeKube(), Options{Dir: tempDir(t), InstallationName: "kyma-installation", RedactSecrets: true})
+		require.NoError(t, err)
+
+		secrets := readFile(t, dir, secretsFile)
+		require.Contains(t, secrets, "global.adminPassword: <redacted>")
+		require.NotContains(t, secrets, "czNjcjN0")
+	})
+
+	t.Run("Missing Installation CR", func(t *testing.T) {
+		dir := tempDir(t)
+		_, err := Create(fakeKube(), Options{Dir: dir, InstallationName: "other-installation"})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "unable to read the Installation CR 'other-installation'")
+		entries, err := ioutil.ReadDir(dir)
+		require.NoError(t, err)
+		require.Empty(t, entries, "No partial backup should be left behind")
+	})
+}
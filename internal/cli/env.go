@@ -0,0 +1,68 @@
+package cli
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/spf13/pflag"
+)
+
+// EnvPrefix is the prefix of the environment variables which set the flags of the commands, e.g. KYMACTL_NON_INTERACTIVE for --non-interactive
+const EnvPrefix = "KYMACTL_"
+
+// EnvName returns the name of the environment variable bound to a flag
+func EnvName(flag string) string {
+	return EnvPrefix + strings.ToUpper(strings.ReplaceAll(flag, "-", "_"))
+}
+
+// ApplyEnvFlags sets all flags of the flag set which are not given on the command line from their KYMACTL_ environment variables,
+// so that a flag takes precedence over the environment variable, which takes precedence over the default value.
+// The applied environment variables are recorded in EnvFlags, mapped to the names of their flags.
+func (o *Options) ApplyEnvFlags(fs *pflag.FlagSet, lookup func(string) (string, bool)) error {
+	o.EnvFlags = map[string]string{}
+	var err error
+	fs.VisitAll(func(f *pflag.Flag) {
+		if err != nil || f.Changed || f.Name == "help" {
+			return
+		}
+		env := EnvName(f.Name)
+		value, ok := lookup(env)
+		if !ok || value == "" {
+			return
+		}
+		if f.Value.Type() == "bool" {
+			if value, err = parseEnvBool(env, value); err != nil {
+				return
+			}
+		}
+		// setting the flag marks it as changed, so that the value is treated exactly like a flag given by the user
+		if setErr := fs.Set(f.Name, value); setErr != nil {
+			err = fmt.Errorf("invalid value '%s' in the environment variable %s: %s", value, env, setErr)
+			return
+		}
+		o.EnvFlags[env] = f.Name
+	})
+	return err
+}
+
+// EnvFlagNames lists the applied environment variables with their flags, sorted by name (e.g. "KYMACTL_DOMAIN (--domain)")
+func (o *Options) EnvFlagNames() []string {
+	var names []string
+	for env, flag := range o.EnvFlags {
+		names = append(names, fmt.Sprintf("%s (--%s)", env, flag))
+	}
+	sort.Strings(names)
+	return names
+}
+
+// parseEnvBool accepts the usual ways to write booleans in the environment, ignoring the case
+func parseEnvBool(env, value string) (string, error) {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "1", "true", "yes", "y", "on":
+		return "true", nil
+	case "0", "false", "no", "n", "off":
+		return "false", nil
+	}
+	return "", fmt.Errorf("invalid value '%s' in the environment variable %s, use true or false", value, env)
+}
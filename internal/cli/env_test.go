@@ -0,0 +1,69 @@
+package cli
+
+import (
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApplyEnvFlags(t *testing.T) {
+	t.Parallel()
+	var domain, release string
+	var nonInteractive, verbose bool
+	var components []string
+	cmd := &cobra.Command{Use: "test"}
+	cmd.Flags().StringVar(&domain, "domain", "local.kyma.dev", "")
+	cmd.Flags().StringVar(&release, "release", "1.0.0", "")
+	cmd.Flags().BoolVar(&nonInteractive, "non-interactive", false, "")
+	cmd.Flags().BoolVarP(&verbose, "verbose", "v", false, "")
+	cmd.Flags().StringSliceVar(&components, "components", nil, "")
+
+	env := map[string]string{
+		"KYMACTL_DOMAIN":          "env.example.com",
+		"KYMACTL_RELEASE":         "2.0.0",
+		"KYMACTL_NON_INTERACTIVE": "Yes",
+		"KYMACTL_VERBOSE":         "",
+		"KYMACTL_COMPONENTS":      "istio,dex",
+	}
+	lookup := func(name string) (string, bool) {
+		v, ok := env[name]
+		return v, ok
+	}
+
+	require.NoError(t, cmd.ParseFlags([]string{"--release", "1.5.0"}))
+	o := NewOptions()
+	require.NoError(t, o.ApplyEnvFlags(cmd.Flags(), lookup))
+
+	require.Equal(t, "env.example.com", domain)
+	require.Equal(t, "1.5.0", release, "The flag must take precedence over the environment variable.")
+	require.True(t, nonInteractive)
+	require.False(t, verbose, "Empty environment variables must be ignored.")
+	require.Equal(t, []string{"istio", "dex"}, components)
+	require.True(t, cmd.Flags().Changed("domain"))
+	require.Equal(t, []string{"KYMACTL_COMPONENTS (--components)", "KYMACTL_DOMAIN (--domain)", "KYMACTL_NON_INTERACTIVE (--non-interactive)"}, o.EnvFlagNames())
+
+	// invalid boolean
+	cmd = &cobra.Command{Use: "test"}
+	cmd.Flags().BoolVar(&nonInteractive, "non-interactive", false, "")
+	env = map[string]string{"KYMACTL_NON_INTERACTIVE": "maybe"}
+	err := NewOptions().ApplyEnvFlags(cmd.Flags(), lookup)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "KYMACTL_NON_INTERACTIVE")
+}
+
+func TestParseEnvBool(t *testing.T) {
+	t.Parallel()
+	for _, v := range []string{"1", "true", "TRUE", "yes", "Yes"} {
+		b, err := parseEnvBool("KYMACTL_CI", v)
+		require.NoError(t, err)
+		require.Equal(t, "true", b, v)
+	}
+	for _, v := range []string{"0", "false", "False", "no", "NO"} {
+		b, err := parseEnvBool("KYMACTL_CI", v)
+		require.NoError(t, err)
+		require.Equal(t, "false", b, v)
+	}
+	_, err := parseEnvBool("KYMACTL_CI", "2")
+	require.Error(t, err)
+}
@@ -1,17 +1,56 @@
 package cli
 
 import (
+	"context"
 	"fmt"
 	"os/exec"
+	"path/filepath"
 	"strings"
+	"time"
+
+	"github.com/kyma-project/cli/internal/logger"
+	"github.com/kyma-project/cli/internal/nice"
+	"github.com/kyma-project/cli/internal/trace"
 )
 
+// StripANSI removes the ANSI escape sequences, such as the colors of kubectl, from the output returned by RunCmd.
+// Set it to false to get the raw output.
+var StripANSI = true
+
 // RunCmd executes a command with given arguments
 func RunCmd(c string, args ...string) (string, error) {
-	cmd := exec.Command(c, args...)
-	out, err := cmd.CombinedOutput()
+	return RunCmdWithTimeout(0, c, args...)
+}
+
+// RunCmdWithTimeout executes a command with given arguments and kills it if it does not finish within the timeout.
+// A timeout of 0 lets the command run until it finishes.
+func RunCmdWithTimeout(timeout time.Duration, c string, args ...string) (string, error) {
+	ctx := context.Background()
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	cmd := exec.CommandContext(ctx, c, args...)
+	started := time.Now()
+	raw, err := cmd.CombinedOutput()
+	out := string(raw)
+	if StripANSI {
+		out = nice.StripANSI(out)
+	}
+	if ctx.Err() == context.DeadlineExceeded {
+		err = fmt.Errorf("Executing command '%s %s' timed out after %s", c, args, timeout)
+		logger.Command(filepath.Base(c), args, started, err)
+		trace.Record(filepath.Base(c), started, err, trace.Attributes{"args": args})
+		return "", err
+	}
 	if err != nil {
-		return "", fmt.Errorf("Executing command '%s %s' failed with output '%s' and error message '%s'", c, args, out, err)
+		err = fmt.Errorf("Executing command '%s %s' failed with output '%s' and error message '%s'", c, args, out, err)
+		logger.Command(filepath.Base(c), args, started, err)
+		trace.Record(filepath.Base(c), started, err, trace.Attributes{"args": args})
+		return "", err
 	}
-	return strings.Replace(string(out), "'", "", -1), nil
+	logger.Command(filepath.Base(c), args, started, nil)
+	trace.Record(filepath.Base(c), started, nil, trace.Attributes{"args": args})
+	return strings.Replace(out, "'", "", -1), nil
 }
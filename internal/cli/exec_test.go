@@ -3,6 +3,7 @@ package cli
 import (
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 )
@@ -51,3 +52,34 @@ func TestRunCmd(t *testing.T) {
 		})
 	}
 }
+
+func TestRunCmdWithTimeout(t *testing.T) {
+	t.Parallel()
+	t.Run("Command finishes in time", func(t *testing.T) {
+		out, err := RunCmdWithTimeout(5*time.Second, "echo", "Hello!")
+		require.NoError(t, err)
+		require.Equal(t, "Hello!\n", out)
+	})
+
+	t.Run("Command runs past the deadline", func(t *testing.T) {
+		start := time.Now()
+		out, err := RunCmdWithTimeout(100*time.Millisecond, "sleep", "10")
+		require.Equal(t, errors.New("Executing command 'sleep [10]' timed out after 100ms"), err)
+		require.Empty(t, out)
+		require.Less(t, int64(time.Since(start)), int64(5*time.Second), "The command should be killed at the deadline")
+	})
+}
+
+func TestRunCmdStripANSI(t *testing.T) {
+	// StripANSI is changed, so the test must not run in parallel with other users of RunCmd
+	colored := `printf '\033[0;32mKubernetes master\033[0m is running at \033[0;33mhttps://127.0.0.1:6443\033[0m\n'`
+	out, err := RunCmd("sh", "-c", colored)
+	require.NoError(t, err)
+	require.Equal(t, "Kubernetes master is running at https://127.0.0.1:6443\n", out)
+
+	defer func() { StripANSI = true }()
+	StripANSI = false
+	out, err = RunCmd("sh", "-c", colored)
+	require.NoError(t, err)
+	require.Equal(t, "\x1b[0;32mKubernetes master\x1b[0m is running at \x1b[0;33mhttps://127.0.0.1:6443\x1b[0m\n", out)
+}
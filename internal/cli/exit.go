@@ -0,0 +1,29 @@
+package cli
+
+import "errors"
+
+// ExitError is an error which terminates the CLI with a specific exit code, so that scripts can react to the cause of the failure
+type ExitError struct {
+	Code int
+	Err  error
+}
+
+func (e *ExitError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *ExitError) Unwrap() error {
+	return e.Err
+}
+
+// ExitCode returns the exit code of the CLI for the error returned by a command: 0 without an error, the code of an ExitError, or 1 for any other error
+func ExitCode(err error) int {
+	if err == nil {
+		return 0
+	}
+	exitErr := &ExitError{}
+	if errors.As(err, &exitErr) {
+		return exitErr.Code
+	}
+	return 1
+}
@@ -0,0 +1,20 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestExitCode(t *testing.T) {
+	t.Parallel()
+	require.Equal(t, 0, ExitCode(nil))
+	require.Equal(t, 1, ExitCode(errors.New("failed")))
+
+	err := &ExitError{Code: 2, Err: errors.New("timeout")}
+	require.Equal(t, 2, ExitCode(err))
+	require.Equal(t, 2, ExitCode(fmt.Errorf("wait failed: %w", err)))
+	require.EqualError(t, err, "timeout")
+}
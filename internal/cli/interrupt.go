@@ -0,0 +1,25 @@
+package cli
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// interruptHandlers counts the running operations which stop and clean up by themselves on SIGINT or SIGTERM
+var interruptHandlers int32
+
+// HandleInterrupts marks that an operation, such as an installation holding the cluster lock, stops and cleans up by itself on SIGINT or SIGTERM.
+// Until the returned function is called, the close handler of the CLI does not exit on the first signal, so that the command can return
+// and its deferred cleanups run. A second signal still exits immediately.
+func HandleInterrupts() func() {
+	atomic.AddInt32(&interruptHandlers, 1)
+	var once sync.Once
+	return func() {
+		once.Do(func() { atomic.AddInt32(&interruptHandlers, -1) })
+	}
+}
+
+// InterruptsHandled checks if an operation currently stops and cleans up by itself on SIGINT or SIGTERM
+func InterruptsHandled() bool {
+	return atomic.LoadInt32(&interruptHandlers) > 0
+}
@@ -0,0 +1,23 @@
+package cli
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// not parallel, as the handlers are counted for the whole process
+func TestHandleInterrupts(t *testing.T) {
+	require.False(t, InterruptsHandled())
+
+	first := HandleInterrupts()
+	second := HandleInterrupts()
+	require.True(t, InterruptsHandled())
+
+	first()
+	first()
+	require.True(t, InterruptsHandled(), "Ending a handler twice must not end the other one.")
+
+	second()
+	require.False(t, InterruptsHandled())
+}
@@ -1,6 +1,9 @@
 package cli
 
 import (
+	"io"
+	"os"
+
 	"github.com/kyma-project/cli/pkg/step"
 )
 
@@ -10,9 +13,33 @@ type Options struct {
 	Verbose bool
 	step.Factory
 	KubeconfigPath string
+	KubectlArgs    []string
+	// MinikubeProfile is the profile of the local Minikube cluster, it is detected from the cluster and the kubeconfig context if it is empty
+	MinikubeProfile string
+	// LogFormat is the format of the structured logs of the CLI, either text or json
+	LogFormat string
+	// GitHubToken authenticates the requests to GitHub, e.g. if a proxy in front of GitHub requires the Authorization header
+	GitHubToken string
+	// MetadataFile is a YAML file with the namespaces, label selectors and resource names of Kyma releases, which take precedence over the embedded ones
+	MetadataFile string
+	// ProfileDir is a directory with installation profiles, which replace the embedded profiles of the same name
+	ProfileDir string
+	// TraceFile is the file the spans of the invocation are written to, tracing is disabled if it is empty
+	TraceFile string
+	// EnvFlags maps the KYMACTL_ environment variables applied to the command to the names of their flags
+	EnvFlags map[string]string
 }
 
 //NewOptions creates options with default values
 func NewOptions() *Options {
 	return &Options{}
 }
+
+// Stdout is the writer for the regular output of the commands, the output of the steps is displayed on it as well.
+// It is os.Stdout, unless the structured logs claim stdout (see --log-format).
+func (o *Options) Stdout() io.Writer {
+	if o.Factory.Out != nil {
+		return o.Factory.Out
+	}
+	return os.Stdout
+}
@@ -0,0 +1,55 @@
+package cli
+
+import (
+	"github.com/kyma-project/cli/internal/files"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// MarkPathFlags marks flags holding file or directory paths, so that their values are normalized by NormalizePathFlags.
+// The shells also complete file names for them.
+func MarkPathFlags(cmd *cobra.Command, names ...string) {
+	for _, name := range names {
+		// only fails if the flag does not exist, which is a programming error
+		if err := cmd.MarkFlagFilename(name); err != nil {
+			panic(err)
+		}
+	}
+}
+
+// NormalizePathFlags expands "~" and makes the paths absolute in all path flags of the command set by the user
+func NormalizePathFlags(cmd *cobra.Command) error {
+	var err error
+	cmd.Flags().VisitAll(func(f *pflag.Flag) {
+		if err != nil || !f.Changed {
+			return
+		}
+		if _, ok := f.Annotations[cobra.BashCompFilenameExt]; !ok {
+			if _, ok := f.Annotations[cobra.BashCompSubdirsInDir]; !ok {
+				return
+			}
+		}
+		err = normalizeFlag(f)
+	})
+	return err
+}
+
+func normalizeFlag(f *pflag.Flag) error {
+	if slice, ok := f.Value.(pflag.SliceValue); ok {
+		values := slice.GetSlice()
+		for n, v := range values {
+			normalized, err := files.NormalizePath(v)
+			if err != nil {
+				return errors.Wrapf(err, "invalid path in the flag --%s", f.Name)
+			}
+			values[n] = normalized
+		}
+		return slice.Replace(values)
+	}
+	normalized, err := files.NormalizePath(f.Value.String())
+	if err != nil {
+		return errors.Wrapf(err, "invalid path in the flag --%s", f.Name)
+	}
+	return f.Value.Set(normalized)
+}
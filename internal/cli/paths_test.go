@@ -0,0 +1,33 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizePathFlags(t *testing.T) {
+	t.Parallel()
+	wd, err := os.Getwd()
+	require.NoError(t, err)
+
+	var file, other string
+	var files []string
+	cmd := &cobra.Command{Use: "test"}
+	cmd.Flags().StringVar(&file, "file", "", "")
+	cmd.Flags().StringArrayVar(&files, "files", nil, "")
+	cmd.Flags().StringVar(&other, "other", "", "")
+	MarkPathFlags(cmd, "file", "files")
+
+	require.NoError(t, cmd.ParseFlags([]string{"--file", "values.yaml", "--files", "a.yaml", "--files", "b/../c.yaml", "--other", "kyma"}))
+	require.NoError(t, NormalizePathFlags(cmd))
+
+	require.Equal(t, filepath.Join(wd, "values.yaml"), file)
+	require.Equal(t, []string{filepath.Join(wd, "a.yaml"), filepath.Join(wd, "c.yaml")}, files)
+	require.Equal(t, "kyma", other, "Flags which are not marked as paths must not be changed.")
+
+	require.Panics(t, func() { MarkPathFlags(cmd, "missing") }, "Marking an unknown flag must panic.")
+}
@@ -0,0 +1,4 @@
+package cli
+
+// Version contains the cli binary version injected by the build system
+var Version string
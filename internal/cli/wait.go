@@ -0,0 +1,74 @@
+package cli
+
+import (
+	"strconv"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// the flags which determine if a command waits, --no-wait is the deprecated spelling of --wait=false
+const (
+	waitFlag   = "wait"
+	noWaitFlag = "no-wait"
+)
+
+// AddWaitFlag adds the --wait flag, which is set by default, to a command whose options keep the inverted value in noWait.
+// The deprecated flags --no-wait, -n and --noWait still set noWait, but are hidden from the help and the shell completion and print a deprecation warning.
+// As all spellings set the same value, the last one given on the command line wins.
+func AddWaitFlag(cmd *cobra.Command, noWait *bool, usage string) {
+	fs := cmd.Flags()
+	fs.Var(&waitValue{noWait: noWait, inverse: true, other: noWaitFlag, fs: fs}, waitFlag, usage)
+	fs.Lookup(waitFlag).NoOptDefVal = "true"
+
+	fs.VarP(&waitValue{noWait: noWait, other: waitFlag, fs: fs}, noWaitFlag, "n", "Deprecated, use --wait=false instead.")
+	fs.Lookup(noWaitFlag).NoOptDefVal = "true"
+	// only fails if the flag does not exist, which is a programming error
+	if err := fs.MarkDeprecated(noWaitFlag, "use --wait=false instead"); err != nil {
+		panic(err)
+	}
+	fs.SetNormalizeFunc(normalizeWaitFlag)
+}
+
+// normalizeWaitFlag maps the camelCase --noWait of older CLI versions to --no-wait
+func normalizeWaitFlag(_ *pflag.FlagSet, name string) pflag.NormalizedName {
+	if name == "noWait" {
+		name = noWaitFlag
+	}
+	return pflag.NormalizedName(name)
+}
+
+// waitValue is a bool flag stored in noWait, inverted for --wait. Setting it marks the other spelling as changed as well,
+// so that a KYMACTL_ environment variable of one spelling does not override the other one given on the command line.
+type waitValue struct {
+	noWait  *bool
+	inverse bool
+	other   string
+	fs      *pflag.FlagSet
+}
+
+func (v *waitValue) Set(s string) error {
+	b, err := strconv.ParseBool(s)
+	if err != nil {
+		return err
+	}
+	*v.noWait = b != v.inverse
+	if f := v.fs.Lookup(v.other); f != nil {
+		f.Changed = true
+	}
+	return nil
+}
+
+func (v *waitValue) String() string {
+	return strconv.FormatBool(*v.noWait != v.inverse)
+}
+
+// Type is "bool", so that the flags are displayed, completed, and set from environment variables like any other bool flag
+func (v *waitValue) Type() string {
+	return "bool"
+}
+
+// IsBoolFlag makes the help display the default value of --wait
+func (v *waitValue) IsBoolFlag() bool {
+	return true
+}
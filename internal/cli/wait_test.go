@@ -0,0 +1,99 @@
+package cli
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/require"
+)
+
+func newWaitCmd(noWait *bool) (*cobra.Command, *bytes.Buffer) {
+	cmd := &cobra.Command{Use: "test", Run: func(*cobra.Command, []string) {}}
+	AddWaitFlag(cmd, noWait, "Waits for the test.")
+	out := &bytes.Buffer{}
+	cmd.Flags().SetOutput(out)
+	return cmd, out
+}
+
+func TestAddWaitFlag(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		args       []string
+		noWait     bool
+		deprecated bool
+	}{
+		{args: nil, noWait: false},
+		{args: []string{"--wait"}, noWait: false},
+		{args: []string{"--wait=true"}, noWait: false},
+		{args: []string{"--wait=false"}, noWait: true},
+		{args: []string{"--no-wait"}, noWait: true, deprecated: true},
+		{args: []string{"-n"}, noWait: true, deprecated: true},
+		{args: []string{"--noWait"}, noWait: true, deprecated: true},
+		{args: []string{"--no-wait=false"}, noWait: false, deprecated: true},
+		{args: []string{"--noWait=false"}, noWait: false, deprecated: true},
+		{args: []string{"--wait=false", "--wait"}, noWait: false},
+		{args: []string{"--noWait", "--wait"}, noWait: false, deprecated: true},
+		{args: []string{"--wait", "--no-wait"}, noWait: true, deprecated: true},
+	}
+	for _, tc := range tests {
+		var noWait bool
+		cmd, out := newWaitCmd(&noWait)
+		require.NoError(t, cmd.ParseFlags(tc.args), tc.args)
+		require.Equal(t, tc.noWait, noWait, "The flags %v do not set the expected value.", tc.args)
+		require.Equal(t, !tc.noWait, cmd.Flags().Lookup("wait").Value.String() == "true", tc.args)
+		if tc.deprecated {
+			require.Contains(t, out.String(), "Flag --no-wait has been deprecated, use --wait=false instead", tc.args)
+		} else {
+			require.Empty(t, out.String(), tc.args)
+		}
+	}
+
+	var noWait bool
+	cmd, _ := newWaitCmd(&noWait)
+	require.Error(t, cmd.ParseFlags([]string{"--wait=maybe"}))
+}
+
+func TestWaitFlagEnv(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		args   []string
+		env    map[string]string
+		noWait bool
+	}{
+		{env: map[string]string{"KYMACTL_WAIT": "false"}, noWait: true},
+		{env: map[string]string{"KYMACTL_NO_WAIT": "true"}, noWait: true},
+		{args: []string{"--wait"}, env: map[string]string{"KYMACTL_NO_WAIT": "true"}, noWait: false},
+		{args: []string{"--noWait"}, env: map[string]string{"KYMACTL_WAIT": "true"}, noWait: true},
+	}
+	for _, tc := range tests {
+		var noWait bool
+		cmd, _ := newWaitCmd(&noWait)
+		require.NoError(t, cmd.ParseFlags(tc.args))
+		lookup := func(name string) (string, bool) {
+			v, ok := tc.env[name]
+			return v, ok
+		}
+		require.NoError(t, NewOptions().ApplyEnvFlags(cmd.Flags(), lookup))
+		require.Equal(t, tc.noWait, noWait, "The flags %v and the environment %v do not set the expected value.", tc.args, tc.env)
+	}
+}
+
+func TestWaitFlagHelp(t *testing.T) {
+	t.Parallel()
+	var noWait bool
+	cmd, _ := newWaitCmd(&noWait)
+
+	usage := cmd.Flags().FlagUsages()
+	require.Contains(t, usage, "--wait ")
+	require.Contains(t, usage, "(default true)")
+	require.NotContains(t, usage, "no-wait", "The deprecated flag must be hidden.")
+
+	completion := &bytes.Buffer{}
+	require.NoError(t, cmd.GenBashCompletion(completion))
+	require.Contains(t, completion.String(), `flags+=("--wait")`)
+	require.False(t, strings.Contains(completion.String(), "no-wait") || strings.Contains(completion.String(), "noWait"), "The deprecated flags must not be completed.")
+}
@@ -0,0 +1,122 @@
+// Package coredns makes the DNS of a cluster resolve the Kyma domain, so that pods can call the Kyma hosts (such as the console) on local clusters.
+package coredns
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+	apiErrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	namespace     = "kube-system"
+	configMapName = "coredns"
+	corefileKey   = "Corefile"
+	dnsPodLabel   = "k8s-app=kube-dns"
+
+	gatewayNamespace = "istio-system"
+	gatewayService   = "istio-ingressgateway"
+
+	// the patch is kept between these markers, so that it can be replaced and removed without touching the rest of the Corefile
+	beginMarker = "# kyma-cli: begin"
+	endMarker   = "# kyma-cli: end"
+)
+
+var (
+	errNoCoreDNS = fmt.Errorf("the ConfigMap '%s' of CoreDNS does not exist in the namespace '%s'. Only clusters using CoreDNS are supported", configMapName, namespace)
+
+	serverBlock = regexp.MustCompile(`(?m)^\.:53\s*\{[ \t]*\n`)
+	patchBlock  = regexp.MustCompile(`(?s)[ \t]*` + regexp.QuoteMeta(beginMarker) + `.*?` + regexp.QuoteMeta(endMarker) + `[ \t]*\n`)
+)
+
+// Patch makes CoreDNS resolve the domain and all its subdomains to the cluster IP of the Istio ingress gateway, and restarts the DNS pods.
+// An existing patch is replaced, and nothing is changed if the Corefile is already patched for the same address.
+// It returns whether the Corefile was changed.
+func Patch(k8s kubernetes.Interface, domain string) (bool, error) {
+	gateway, err := k8s.CoreV1().Services(gatewayNamespace).Get(context.Background(), gatewayService, metav1.GetOptions{})
+	if err != nil {
+		return false, errors.Wrap(err, "unable to find the Istio ingress gateway")
+	}
+	if gateway.Spec.ClusterIP == "" || gateway.Spec.ClusterIP == "None" {
+		return false, fmt.Errorf("the service '%s' has no cluster IP", gatewayService)
+	}
+	return update(k8s, func(corefile string) (string, error) {
+		return patchCorefile(corefile, domain, gateway.Spec.ClusterIP)
+	})
+}
+
+// Unpatch removes the patch of Patch from the Corefile and restarts the DNS pods. Nothing is changed if the Corefile is not patched
+// or the cluster does not use CoreDNS. It returns whether the Corefile was changed.
+func Unpatch(k8s kubernetes.Interface) (bool, error) {
+	changed, err := update(k8s, func(corefile string) (string, error) {
+		return unpatchCorefile(corefile), nil
+	})
+	if err == errNoCoreDNS {
+		return false, nil
+	}
+	return changed, err
+}
+
+func update(k8s kubernetes.Interface, change func(corefile string) (string, error)) (bool, error) {
+	configMaps := k8s.CoreV1().ConfigMaps(namespace)
+	cm, err := configMaps.Get(context.Background(), configMapName, metav1.GetOptions{})
+	if apiErrors.IsNotFound(err) {
+		return false, errNoCoreDNS
+	}
+	if err != nil {
+		return false, errors.Wrap(err, "unable to read the CoreDNS configuration")
+	}
+
+	corefile, err := change(cm.Data[corefileKey])
+	if err != nil {
+		return false, err
+	}
+	if corefile == cm.Data[corefileKey] {
+		return false, nil
+	}
+	cm.Data[corefileKey] = corefile
+	if _, err := configMaps.Update(context.Background(), cm, metav1.UpdateOptions{}); err != nil {
+		return false, errors.Wrap(err, "unable to update the CoreDNS configuration")
+	}
+
+	// the pods are replaced by their deployment, so that the configuration is loaded even if the reload plugin is not enabled
+	pods, err := k8s.CoreV1().Pods(namespace).List(context.Background(), metav1.ListOptions{LabelSelector: dnsPodLabel})
+	if err != nil {
+		return true, errors.Wrap(err, "unable to restart the DNS pods")
+	}
+	for _, pod := range pods.Items {
+		if err := k8s.CoreV1().Pods(namespace).Delete(context.Background(), pod.Name, metav1.DeleteOptions{}); err != nil && !apiErrors.IsNotFound(err) {
+			return true, errors.Wrapf(err, "unable to restart the DNS pod '%s'", pod.Name)
+		}
+	}
+	return true, nil
+}
+
+// patchCorefile adds a template to the default server block, which answers the A queries of the domain and its subdomains with the IP
+func patchCorefile(corefile, domain, ip string) (string, error) {
+	corefile = unpatchCorefile(corefile)
+	location := serverBlock.FindStringIndex(corefile)
+	if location == nil {
+		return "", errors.New("the CoreDNS configuration has no server block for '.:53'")
+	}
+	block := strings.Join([]string{
+		"    " + beginMarker,
+		fmt.Sprintf("    template IN A %s {", domain),
+		fmt.Sprintf(`        match "^(.*\.)?%s\.$"`, regexp.QuoteMeta(domain)),
+		fmt.Sprintf(`        answer "{{ .Name }} 60 IN A %s"`, ip),
+		"        fallthrough",
+		"    }",
+		"    " + endMarker,
+		"",
+	}, "\n")
+	return corefile[:location[1]] + block + corefile[location[1]:], nil
+}
+
+func unpatchCorefile(corefile string) string {
+	return patchBlock.ReplaceAllString(corefile, "")
+}
@@ -0,0 +1,102 @@
+package coredns
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+const corefile = `.:53 {
+    errors
+    health
+    kubernetes cluster.local in-addr.arpa ip6.arpa {
+       pods insecure
+       fallthrough in-addr.arpa ip6.arpa
+    }
+    forward . /etc/resolv.conf
+    cache 30
+    reload
+}
+`
+
+func TestPatch(t *testing.T) {
+	t.Parallel()
+	k8s := fake.NewSimpleClientset(
+		&corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: configMapName, Namespace: namespace},
+			Data:       map[string]string{corefileKey: corefile},
+		},
+		&corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{Name: gatewayService, Namespace: gatewayNamespace},
+			Spec:       corev1.ServiceSpec{ClusterIP: "10.96.12.34"},
+		},
+		&corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "coredns-1", Namespace: namespace, Labels: map[string]string{"k8s-app": "kube-dns"}},
+		},
+	)
+	read := func() string {
+		cm, err := k8s.CoreV1().ConfigMaps(namespace).Get(context.Background(), configMapName, metav1.GetOptions{})
+		require.NoError(t, err)
+		return cm.Data[corefileKey]
+	}
+
+	changed, err := Patch(k8s, "kyma.local")
+	require.NoError(t, err)
+	require.True(t, changed)
+	patched := read()
+	require.Contains(t, patched, "template IN A kyma.local {")
+	require.Contains(t, patched, `match "^(.*\.)?kyma\.local\.$"`)
+	require.Contains(t, patched, `answer "{{ .Name }} 60 IN A 10.96.12.34"`)
+	pods, err := k8s.CoreV1().Pods(namespace).List(context.Background(), metav1.ListOptions{})
+	require.NoError(t, err)
+	require.Empty(t, pods.Items, "The DNS pods must be restarted.")
+
+	// patching again changes nothing
+	changed, err = Patch(k8s, "kyma.local")
+	require.NoError(t, err)
+	require.False(t, changed)
+	require.Equal(t, patched, read())
+
+	changed, err = Unpatch(k8s)
+	require.NoError(t, err)
+	require.True(t, changed)
+	require.Equal(t, corefile, read())
+
+	changed, err = Unpatch(k8s)
+	require.NoError(t, err)
+	require.False(t, changed)
+}
+
+func TestPatchCorefile(t *testing.T) {
+	t.Parallel()
+	// a patch for another domain is replaced
+	patched, err := patchCorefile(corefile, "kyma.local", "10.0.0.1")
+	require.NoError(t, err)
+	patched, err = patchCorefile(patched, "example.com", "10.0.0.2")
+	require.NoError(t, err)
+	require.NotContains(t, patched, "kyma.local")
+	require.Contains(t, patched, "template IN A example.com {")
+	require.Equal(t, corefile, unpatchCorefile(patched))
+
+	_, err = patchCorefile("example.org {\n    forward . 8.8.8.8\n}\n", "kyma.local", "10.0.0.1")
+	require.Error(t, err)
+}
+
+func TestPatchWithoutCoreDNS(t *testing.T) {
+	t.Parallel()
+	k8s := fake.NewSimpleClientset(&corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: gatewayService, Namespace: gatewayNamespace},
+		Spec:       corev1.ServiceSpec{ClusterIP: "10.96.12.34"},
+	})
+	_, err := Patch(k8s, "kyma.local")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "Only clusters using CoreDNS are supported")
+
+	changed, err := Unpatch(k8s)
+	require.NoError(t, err, "Clusters without CoreDNS have nothing to remove.")
+	require.False(t, changed)
+}
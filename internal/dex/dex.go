@@ -0,0 +1,203 @@
+// Package dex logs in with the Kyma admin user at Dex, the OIDC identity provider of Kyma, and builds the kubeconfig user for the tokens.
+package dex
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/kyma-project/cli/internal/kube"
+	pkgErrors "github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/clientcmd/api"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// ClientID is the static client of the Dex configuration of Kyma used by kubectl
+	ClientID = "kyma-client"
+	// configMap holds the Dex configuration of Kyma, including its static clients
+	configMap = "dex-config"
+	configKey = "config.yaml"
+	// scopes are requested so that the ID token contains the email and groups of the user, and a refresh token is issued
+	scopes = "openid email profile groups offline_access"
+
+	requestTimeout = 30 * time.Second
+)
+
+// Provider holds the endpoints and the root certificate of Dex
+type Provider struct {
+	Issuer        string
+	TokenEndpoint string
+	// ClientSecret is the secret of the static client, which is public like the secret of any client running on the machine of the user
+	ClientSecret string
+	// CA is the root certificate of the Kyma domain, if it is not trusted by the OS
+	CA []byte
+}
+
+// Token holds the tokens issued to the user
+type Token struct {
+	IDToken      string `json:"id_token"`
+	RefreshToken string `json:"refresh_token"`
+}
+
+// Issuer returns the URL of Dex, read from the host of its virtual service
+func Issuer(k kube.KymaKube) (string, error) {
+	vs, err := k.Istio().NetworkingV1alpha3().VirtualServices("kyma-system").Get(context.Background(), "dex-virtualservice", metav1.GetOptions{})
+	if err != nil {
+		return "", pkgErrors.Wrap(err, "unable to find Dex on the cluster")
+	}
+	if len(vs.Spec.Hosts) == 0 {
+		return "", errors.New("the virtual service of Dex has no host")
+	}
+	return fmt.Sprintf("https://%s", vs.Spec.Hosts[0]), nil
+}
+
+// ClientSecret reads the secret of the static client from the Dex configuration on the cluster
+func ClientSecret(k kube.KymaKube) (string, error) {
+	cm, err := k.Static().CoreV1().ConfigMaps("kyma-system").Get(context.Background(), configMap, metav1.GetOptions{})
+	if err != nil {
+		return "", pkgErrors.Wrap(err, "unable to read the Dex configuration")
+	}
+	var cfg struct {
+		StaticClients []struct {
+			ID     string `json:"id"`
+			Secret string `json:"secret"`
+		} `json:"staticClients"`
+	}
+	if err := yaml.Unmarshal([]byte(cm.Data[configKey]), &cfg); err != nil {
+		return "", pkgErrors.Wrap(err, "unable to decode the Dex configuration")
+	}
+	for _, c := range cfg.StaticClients {
+		if c.ID == ClientID {
+			return c.Secret, nil
+		}
+	}
+	return "", fmt.Errorf("the Dex configuration has no static client '%s'", ClientID)
+}
+
+// Discover reads the OIDC configuration of Dex, the client secret is used for the requests of the static client. The CA is trusted in addition to the root certificates of the OS.
+func Discover(issuer, clientSecret string, ca []byte) (*Provider, error) {
+	client, err := httpClient(ca)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := client.Get(strings.TrimSuffix(issuer, "/") + "/.well-known/openid-configuration")
+	if err != nil {
+		return nil, pkgErrors.Wrap(err, "unable to read the OIDC configuration of Dex")
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unable to read the OIDC configuration of Dex: %s", resp.Status)
+	}
+
+	var cfg struct {
+		Issuer        string `json:"issuer"`
+		TokenEndpoint string `json:"token_endpoint"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&cfg); err != nil {
+		return nil, pkgErrors.Wrap(err, "unable to decode the OIDC configuration of Dex")
+	}
+	if cfg.TokenEndpoint == "" {
+		return nil, errors.New("the OIDC configuration of Dex has no token endpoint")
+	}
+	// the ID tokens are validated against the issuer of the configuration, which can differ from the URL in its trailing slash
+	if cfg.Issuer == "" {
+		cfg.Issuer = issuer
+	}
+	return &Provider{Issuer: cfg.Issuer, TokenEndpoint: cfg.TokenEndpoint, ClientSecret: clientSecret, CA: ca}, nil
+}
+
+// Login requests the tokens of the user with the password grant of the static client
+func (p *Provider) Login(email, password string) (*Token, error) {
+	client, err := httpClient(p.CA)
+	if err != nil {
+		return nil, err
+	}
+	form := url.Values{
+		"grant_type": {"password"},
+		"username":   {email},
+		"password":   {password},
+		"scope":      {scopes},
+	}
+	req, err := http.NewRequest(http.MethodPost, p.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.SetBasicAuth(ClientID, p.ClientSecret)
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, pkgErrors.Wrap(err, "unable to log in at Dex")
+	}
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, pkgErrors.Wrap(err, "unable to read the response of Dex")
+	}
+	if resp.StatusCode != http.StatusOK {
+		var oauthErr struct {
+			Error       string `json:"error"`
+			Description string `json:"error_description"`
+		}
+		if json.Unmarshal(body, &oauthErr) == nil && oauthErr.Error != "" {
+			return nil, fmt.Errorf("unable to log in at Dex: %s %s", oauthErr.Error, oauthErr.Description)
+		}
+		return nil, fmt.Errorf("unable to log in at Dex: %s", resp.Status)
+	}
+
+	t := &Token{}
+	if err := json.Unmarshal(body, t); err != nil {
+		return nil, pkgErrors.Wrap(err, "unable to decode the tokens of Dex")
+	}
+	if t.IDToken == "" {
+		return nil, errors.New("Dex did not issue an ID token")
+	}
+	return t, nil
+}
+
+// AuthInfo builds a kubeconfig user with the oidc auth provider, which refreshes the ID token with the refresh token once it expired
+func (p *Provider) AuthInfo(t *Token) *api.AuthInfo {
+	cfg := map[string]string{
+		"idp-issuer-url": p.Issuer,
+		"client-id":      ClientID,
+		"client-secret":  p.ClientSecret,
+		"id-token":       t.IDToken,
+	}
+	if t.RefreshToken != "" {
+		cfg["refresh-token"] = t.RefreshToken
+	}
+	user := api.NewAuthInfo()
+	user.AuthProvider = &api.AuthProviderConfig{Name: "oidc", Config: cfg}
+	if len(p.CA) > 0 {
+		// kubectl refreshes the token with its own client, so the CA of the Kyma domain must be part of the user
+		user.AuthProvider.Config["idp-certificate-authority-data"] = base64.StdEncoding.EncodeToString(p.CA)
+	}
+	return user
+}
+
+func httpClient(ca []byte) (*http.Client, error) {
+	client := &http.Client{Timeout: requestTimeout}
+	if len(ca) == 0 {
+		return client, nil
+	}
+	pool, err := x509.SystemCertPool()
+	if err != nil || pool == nil {
+		pool = x509.NewCertPool()
+	}
+	if !pool.AppendCertsFromPEM(ca) {
+		return nil, errors.New("the Kyma root certificate is not a valid PEM certificate")
+	}
+	client.Transport = &http.Transport{TLSClientConfig: &tls.Config{RootCAs: pool}}
+	return client, nil
+}
@@ -0,0 +1,103 @@
+package dex
+
+import (
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kyma-project/cli/internal/kube/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func fakeDex(t *testing.T) *httptest.Server {
+	mux := http.NewServeMux()
+	var srv *httptest.Server
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]string{"issuer": srv.URL, "token_endpoint": srv.URL + "/token"})
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		// the handler runs in the goroutine of the server, so failures are only recorded
+		id, secret, _ := r.BasicAuth()
+		assert.Equal(t, ClientID, id)
+		assert.Equal(t, "client-secret", secret)
+		assert.NoError(t, r.ParseForm())
+		assert.Equal(t, "password", r.Form.Get("grant_type"))
+		assert.Contains(t, r.Form.Get("scope"), "offline_access")
+		if r.Form.Get("username") != "admin@kyma.cx" || r.Form.Get("password") != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			_ = json.NewEncoder(w).Encode(map[string]string{"error": "invalid_grant", "error_description": "Invalid username or password"})
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]string{"id_token": "id-token", "refresh_token": "refresh-token", "access_token": "access-token"})
+	})
+	srv = httptest.NewTLSServer(mux)
+	return srv
+}
+
+func TestLogin(t *testing.T) {
+	t.Parallel()
+	srv := fakeDex(t)
+	defer srv.Close()
+	ca := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: srv.Certificate().Raw})
+
+	// the self-signed certificate is only trusted with the CA
+	_, err := Discover(srv.URL, "client-secret", nil)
+	require.Error(t, err)
+
+	p, err := Discover(srv.URL, "client-secret", ca)
+	require.NoError(t, err)
+	require.Equal(t, srv.URL+"/token", p.TokenEndpoint)
+
+	tokens, err := p.Login("admin@kyma.cx", "secret")
+	require.NoError(t, err)
+	require.Equal(t, &Token{IDToken: "id-token", RefreshToken: "refresh-token"}, tokens)
+
+	_, err = p.Login("admin@kyma.cx", "wrong")
+	require.EqualError(t, err, "unable to log in at Dex: invalid_grant Invalid username or password")
+
+	user := p.AuthInfo(tokens)
+	require.Equal(t, "oidc", user.AuthProvider.Name)
+	require.Equal(t, srv.URL, user.AuthProvider.Config["idp-issuer-url"])
+	require.Equal(t, "refresh-token", user.AuthProvider.Config["refresh-token"])
+	require.Equal(t, "client-secret", user.AuthProvider.Config["client-secret"])
+	require.NotEmpty(t, user.AuthProvider.Config["idp-certificate-authority-data"])
+}
+
+func TestClientSecret(t *testing.T) {
+	t.Parallel()
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "dex-config", Namespace: "kyma-system"},
+		Data: map[string]string{"config.yaml": `issuer: https://dex.kyma.local
+staticClients:
+- id: console
+  secret: console-secret
+- id: kyma-client
+  name: Kyma Client
+  secret: kyma-client-secret
+`},
+	}
+	kymaMock := &mocks.KymaKube{}
+	kymaMock.On("Static").Return(fake.NewSimpleClientset(cm))
+	secret, err := ClientSecret(kymaMock)
+	require.NoError(t, err)
+	require.Equal(t, "kyma-client-secret", secret)
+
+	// the static client is missing
+	cm.Data["config.yaml"] = "staticClients:\n- id: console\n  secret: console-secret\n"
+	kymaMock = &mocks.KymaKube{}
+	kymaMock.On("Static").Return(fake.NewSimpleClientset(cm))
+	_, err = ClientSecret(kymaMock)
+	require.EqualError(t, err, "the Dex configuration has no static client 'kyma-client'")
+
+	// Dex is not installed
+	kymaMock = &mocks.KymaKube{}
+	kymaMock.On("Static").Return(fake.NewSimpleClientset())
+	_, err = ClientSecret(kymaMock)
+	require.Error(t, err)
+}
@@ -0,0 +1,163 @@
+package diagnostics
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"os/user"
+	"runtime"
+	"strings"
+
+	"github.com/kyma-project/cli/internal/cli"
+	"github.com/kyma-project/cli/internal/github"
+	"github.com/kyma-project/cli/internal/kube"
+	"github.com/kyma-project/cli/internal/minikube"
+	"github.com/kyma-project/cli/internal/release"
+	"github.com/kyma-project/cli/internal/releases"
+	kymaVersion "github.com/kyma-project/cli/internal/version"
+	"github.com/kyma-project/cli/pkg/docker"
+	"github.com/pkg/errors"
+)
+
+func cliVersion(o Options) ([]Entry, []string) {
+	version := o.CLIVersion
+	if version == "" {
+		version = "N/A"
+	}
+	return []Entry{{Name: "Kyma CLI version", Value: version}}, nil
+}
+
+func platform(_ Options) ([]Entry, []string) {
+	return []Entry{{Name: "OS/Arch", Value: fmt.Sprintf("%s/%s", runtime.GOOS, runtime.GOARCH)}}, nil
+}
+
+func kubectlVersion(o Options) ([]Entry, []string) {
+	out, err := cli.RunCmdWithTimeout(o.Timeout, "kubectl", "version", "--client", "--short")
+	return []Entry{{Name: "kubectl client version", Value: strings.TrimPrefix(strings.TrimSpace(out), "Client Version: "), Err: err}}, nil
+}
+
+// kubernetes reports the current context of the kubeconfig and whether its API server is reachable
+func kubernetes(o Options) ([]Entry, []string) {
+	k, err := kube.NewFromConfigWithTimeout("", o.KubeconfigPath, o.Timeout)
+	if err != nil {
+		err = errors.Wrap(err, "invalid kubeconfig")
+		return []Entry{{Name: "Kubernetes context", Err: err}, {Name: "Kubernetes API server", Err: err}, {Name: "Kubernetes server version", Err: err}}, nil
+	}
+
+	cfg := k.KubeConfig()
+	var server string
+	if ctx, ok := cfg.Contexts[cfg.CurrentContext]; ok {
+		if cluster, ok := cfg.Clusters[ctx.Cluster]; ok {
+			server = cluster.Server
+		}
+	}
+	sensitive := []string{server}
+	if u, err := url.Parse(server); err == nil {
+		sensitive = append(sensitive, u.Host, u.Hostname())
+	}
+
+	entries := []Entry{{Name: "Kubernetes context", Value: cfg.CurrentContext}}
+	v, err := k.Static().Discovery().ServerVersion()
+	if err != nil {
+		return append(entries,
+			Entry{Name: "Kubernetes API server", Value: server, Err: errors.Wrapf(err, "%s is not reachable", server)},
+			Entry{Name: "Kubernetes server version", Err: err},
+		), sensitive
+	}
+	return append(entries,
+		Entry{Name: "Kubernetes API server", Value: fmt.Sprintf("%s (reachable)", server)},
+		Entry{Name: "Kubernetes server version", Value: v.GitVersion},
+	), sensitive
+}
+
+func minikubeStatus(o Options) ([]Entry, []string) {
+	version, err := minikube.RunCmd(false, "", o.Timeout, "version", "--short")
+	entries := []Entry{{Name: "Minikube version", Value: strings.TrimPrefix(strings.TrimSpace(version), "minikube version: "), Err: err}}
+	if err != nil {
+		// without Minikube, its status is of no interest
+		return entries, nil
+	}
+	profile := minikube.ResolveProfile(false, o.Timeout, o.MinikubeProfile, "", kube.CurrentContext(o.KubeconfigPath))
+	status, err := minikube.RunCmd(false, profile, o.Timeout, "status", "--format", "{{.Host}}")
+	if strings.TrimSpace(status) != "" {
+		// "minikube status" also fails if the cluster is only stopped
+		err = nil
+	}
+	value := strings.TrimSpace(status)
+	if profile != "" && value != "" {
+		value = fmt.Sprintf("%s (profile %s)", value, profile)
+	}
+	return append(entries, Entry{Name: "Minikube status", Value: value, Err: err}), nil
+}
+
+// dockerStatus checks the connection to the Docker daemon configured in the environment (e.g. with DOCKER_HOST)
+func dockerStatus(o Options) ([]Entry, []string) {
+	sensitive := []string{os.Getenv("DOCKER_HOST")}
+	c, err := docker.NewClient()
+	if err != nil {
+		return []Entry{{Name: "Docker", Err: err}}, sensitive
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), o.Timeout)
+	defer cancel()
+	c.NegotiateAPIVersion(ctx)
+	info, err := c.Info(ctx)
+	if err != nil {
+		return []Entry{{Name: "Docker", Err: err}}, sensitive
+	}
+	return []Entry{{Name: "Docker", Value: fmt.Sprintf("reachable, server version %s", info.ServerVersion)}}, sensitive
+}
+
+// diskSpace reports the free space of the file system holding the home directory, where the CLI stores Kyma sources and backups
+func diskSpace(_ Options) ([]Entry, []string) {
+	u, err := user.Current()
+	if err != nil {
+		return []Entry{{Name: "Free disk space", Err: err}}, nil
+	}
+	free, err := freeSpace(u.HomeDir)
+	if err != nil {
+		return []Entry{{Name: "Free disk space", Err: err}}, []string{u.HomeDir}
+	}
+	// the home directory contains the user name
+	return []Entry{{Name: "Free disk space", Value: fmt.Sprintf("%s in %s", formatSize(free), u.HomeDir)}}, []string{u.HomeDir}
+}
+
+func releaseReachable(o Options) ([]Entry, []string) {
+	if o.ReleaseURL == "" {
+		return []Entry{{Name: "Kyma release", Err: errors.New("no Kyma release to check")}}, nil
+	}
+	resp, err := github.NewClient(o.Timeout).Head(o.ReleaseURL)
+	if err != nil {
+		return []Entry{{Name: "Kyma release", Err: err}}, nil
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return []Entry{{Name: "Kyma release", Err: fmt.Errorf("%s returned %s", o.ReleaseURL, resp.Status)}}, nil
+	}
+	return []Entry{{Name: "Kyma release", Value: fmt.Sprintf("%s (reachable)", o.ReleaseURL)}}, nil
+}
+
+// defaultReleaseURL returns the installer of the default Kyma version, or of the latest release for builds without a default version
+func defaultReleaseURL() string {
+	v := kymaVersion.DefaultKymaVersion
+	if v == "" {
+		v, _ = releases.ResolveLatest("", func(string, ...interface{}) {})
+	}
+	r, err := release.FromVersion(v)
+	if err != nil {
+		return ""
+	}
+	return r.InstallerManifest().Location
+}
+
+func formatSize(bytes uint64) string {
+	units := []string{"B", "KiB", "MiB", "GiB", "TiB"}
+	size := float64(bytes)
+	n := 0
+	for size >= 1024 && n < len(units)-1 {
+		size /= 1024
+		n++
+	}
+	return strings.TrimSuffix(fmt.Sprintf("%.1f", size), ".0") + " " + units[n]
+}
@@ -0,0 +1,116 @@
+package diagnostics
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// redacted replaces the sensitive values in a redacted report
+const redacted = "<redacted>"
+
+// Options configures which environment the diagnostics are collected from
+type Options struct {
+	// CLIVersion is the version of the Kyma CLI binary
+	CLIVersion string
+	// KubeconfigPath is the kubeconfig of the checked cluster, an empty path uses the default resolution of kubectl
+	KubeconfigPath string
+	// MinikubeProfile is the Minikube profile whose status is checked
+	MinikubeProfile string
+	// ReleaseURL is a file of the Kyma release which must be downloadable for an installation.
+	// If it is empty, the installer of the default Kyma version is checked.
+	ReleaseURL string
+	// Timeout is the maximum duration of each check
+	Timeout time.Duration
+}
+
+// Entry is one fact about the environment. If it could not be determined, Err holds the reason.
+type Entry struct {
+	Name  string
+	Value string
+	Err   error
+}
+
+// Report holds the diagnostics of the environment the CLI runs in
+type Report struct {
+	Time    time.Time
+	Entries []Entry
+	// sensitive holds the values such as server URLs which are masked by Redact
+	sensitive []string
+}
+
+// check collects one or more entries, and the values among them which must be masked in a redacted report
+type check func(o Options) (entries []Entry, sensitive []string)
+
+// Collect runs all checks of the environment. Failing checks do not stop the collection, their errors are part of the report.
+func Collect(o Options) *Report {
+	if o.ReleaseURL == "" {
+		o.ReleaseURL = defaultReleaseURL()
+	}
+	return collect(o, []check{
+		cliVersion,
+		platform,
+		kubectlVersion,
+		kubernetes,
+		minikubeStatus,
+		dockerStatus,
+		diskSpace,
+		releaseReachable,
+	})
+}
+
+func collect(o Options, checks []check) *Report {
+	r := &Report{Time: time.Now()}
+	for _, c := range checks {
+		entries, sensitive := c(o)
+		r.Entries = append(r.Entries, entries...)
+		for _, s := range sensitive {
+			if s != "" {
+				r.sensitive = append(r.sensitive, s)
+			}
+		}
+	}
+	return r
+}
+
+// Redact masks the server URLs and credentials found in the report, so that it can be attached to public issues
+func (r *Report) Redact() {
+	// longer values first, so that a URL is masked as a whole and not only its host
+	sort.Slice(r.sensitive, func(a, b int) bool { return len(r.sensitive[a]) > len(r.sensitive[b]) })
+	mask := func(s string) string {
+		for _, v := range r.sensitive {
+			s = strings.Replace(s, v, redacted, -1)
+		}
+		return s
+	}
+	for n, e := range r.Entries {
+		r.Entries[n].Value = mask(e.Value)
+		if e.Err != nil {
+			r.Entries[n].Err = fmt.Errorf("%s", mask(e.Err.Error()))
+		}
+	}
+}
+
+// String renders the report as a block which can be copied into an issue
+func (r *Report) String() string {
+	width := 0
+	for _, e := range r.Entries {
+		if len(e.Name) > width {
+			width = len(e.Name)
+		}
+	}
+
+	var b strings.Builder
+	b.WriteString("```\n")
+	fmt.Fprintf(&b, "Kyma CLI diagnostics (%s)\n", r.Time.UTC().Format(time.RFC3339))
+	for _, e := range r.Entries {
+		value := e.Value
+		if e.Err != nil {
+			value = fmt.Sprintf("N/A (%s)", strings.Replace(e.Err.Error(), "\n", " ", -1))
+		}
+		fmt.Fprintf(&b, "%-*s %s\n", width+1, e.Name+":", value)
+	}
+	b.WriteString("```\n")
+	return b.String()
+}
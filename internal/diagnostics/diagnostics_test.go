@@ -0,0 +1,45 @@
+package diagnostics
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCollect(t *testing.T) {
+	t.Parallel()
+	checks := []check{
+		func(o Options) ([]Entry, []string) {
+			return []Entry{{Name: "Kyma CLI version", Value: o.CLIVersion}}, nil
+		},
+		func(_ Options) ([]Entry, []string) {
+			return []Entry{
+				{Name: "Kubernetes API server", Value: "https://api.cluster.example.com:6443 (reachable)"},
+				{Name: "Kubernetes server version", Err: errors.New("Get https://api.cluster.example.com:6443/version: timeout\nretry later")},
+			}, []string{"https://api.cluster.example.com:6443", "api.cluster.example.com", ""}
+		},
+	}
+	r := collect(Options{CLIVersion: "1.17.0"}, checks)
+	r.Time = time.Date(2021, 3, 1, 10, 0, 0, 0, time.UTC)
+
+	require.Equal(t, "```\n"+
+		"Kyma CLI diagnostics (2021-03-01T10:00:00Z)\n"+
+		"Kyma CLI version:          1.17.0\n"+
+		"Kubernetes API server:     https://api.cluster.example.com:6443 (reachable)\n"+
+		"Kubernetes server version: N/A (Get https://api.cluster.example.com:6443/version: timeout retry later)\n"+
+		"```\n", r.String())
+
+	r.Redact()
+	require.Equal(t, "<redacted> (reachable)", r.Entries[1].Value)
+	require.EqualError(t, r.Entries[2].Err, "Get <redacted>/version: timeout\nretry later")
+	require.Equal(t, "1.17.0", r.Entries[0].Value, "Values without sensitive data must not be changed.")
+}
+
+func TestFormatSize(t *testing.T) {
+	t.Parallel()
+	require.Equal(t, "512 B", formatSize(512))
+	require.Equal(t, "1.5 KiB", formatSize(1536))
+	require.Equal(t, "20 GiB", formatSize(20<<30))
+}
@@ -0,0 +1,14 @@
+// +build !windows
+
+package diagnostics
+
+import "syscall"
+
+// freeSpace returns the space in bytes available to the user on the file system holding the path
+func freeSpace(path string) (uint64, error) {
+	var stat syscall.Statfs_t
+	if err := syscall.Statfs(path, &stat); err != nil {
+		return 0, err
+	}
+	return stat.Bavail * uint64(stat.Bsize), nil
+}
@@ -0,0 +1,10 @@
+// +build windows
+
+package diagnostics
+
+import "errors"
+
+// freeSpace is not supported on Windows, where the free space is displayed in the Explorer
+func freeSpace(_ string) (uint64, error) {
+	return 0, errors.New("not supported on Windows")
+}
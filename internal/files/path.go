@@ -0,0 +1,46 @@
+package files
+
+import (
+	"fmt"
+	"os"
+	"os/user"
+	"path/filepath"
+	"strings"
+)
+
+// NormalizePath expands a leading "~" or "~user" to the home directory, makes the path absolute and cleans it.
+// Shells do not expand "~" in flags like --src-path=~/kyma, and relative paths would otherwise depend on where they are used.
+// An empty path is returned as it is.
+func NormalizePath(path string) (string, error) {
+	if path == "" {
+		return "", nil
+	}
+	expanded, err := expandHome(path, user.Current, user.Lookup)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Abs(expanded)
+}
+
+// expandHome replaces a leading "~" with the home directory of the current user and "~user" with the home directory of the user
+func expandHome(path string, current func() (*user.User, error), lookup func(name string) (*user.User, error)) (string, error) {
+	if !strings.HasPrefix(path, "~") {
+		return path, nil
+	}
+	name, rest := path[1:], ""
+	if n := strings.IndexAny(name, `/`+string(os.PathSeparator)); n >= 0 {
+		name, rest = name[:n], name[n+1:]
+	}
+
+	var u *user.User
+	var err error
+	if name == "" {
+		u, err = current()
+	} else {
+		u, err = lookup(name)
+	}
+	if err != nil {
+		return "", fmt.Errorf("unable to expand '~%s' in the path '%s': %s", name, path, err)
+	}
+	return filepath.Join(u.HomeDir, rest), nil
+}
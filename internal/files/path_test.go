@@ -0,0 +1,67 @@
+package files
+
+import (
+	"errors"
+	"os"
+	"os/user"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestExpandHome(t *testing.T) {
+	t.Parallel()
+	home := filepath.Join(string(os.PathSeparator), "home", "kyma")
+	other := filepath.Join(string(os.PathSeparator), "home", "other")
+	current := func() (*user.User, error) { return &user.User{HomeDir: home}, nil }
+	lookup := func(name string) (*user.User, error) {
+		if name == "other" {
+			return &user.User{HomeDir: other}, nil
+		}
+		return nil, errors.New("unknown user")
+	}
+
+	tests := map[string]string{
+		"~":                home,
+		"~/":               home,
+		"~/workspace/kyma": filepath.Join(home, "workspace", "kyma"),
+		"~other/kyma":      filepath.Join(other, "kyma"),
+		"workspace/~/kyma": "workspace/~/kyma",
+		"/tmp/kyma":        "/tmp/kyma",
+		"":                 "",
+	}
+	for path, expected := range tests {
+		expanded, err := expandHome(path, current, lookup)
+		require.NoError(t, err, path)
+		require.Equal(t, expected, expanded, path)
+	}
+
+	_, err := expandHome("~unknown/kyma", current, lookup)
+	require.EqualError(t, err, "unable to expand '~unknown' in the path '~unknown/kyma': unknown user")
+}
+
+func TestNormalizePath(t *testing.T) {
+	t.Parallel()
+	wd, err := os.Getwd()
+	require.NoError(t, err)
+
+	path, err := NormalizePath("")
+	require.NoError(t, err)
+	require.Empty(t, path, "An empty path must stay empty.")
+
+	path, err = NormalizePath(filepath.Join("testdata", "..", "values.yaml"))
+	require.NoError(t, err)
+	require.Equal(t, filepath.Join(wd, "values.yaml"), path, "Relative paths must be resolved against the working directory.")
+
+	abs := filepath.Join(wd, "kyma")
+	path, err = NormalizePath(abs + string(os.PathSeparator))
+	require.NoError(t, err)
+	require.Equal(t, abs, path, "Absolute paths must only be cleaned.")
+
+	u, err := user.Current()
+	require.NoError(t, err)
+	path, err = NormalizePath(filepath.Join("~", "kyma"))
+	require.NoError(t, err)
+	require.Equal(t, filepath.Join(u.HomeDir, "kyma"), path)
+}
@@ -0,0 +1,71 @@
+// Package github provides the HTTP clients of the CLI for GitHub, which authenticate with the token given by --github-token.
+// Proxies in front of GitHub might require the Authorization header, so all requests of the CLI to GitHub must use these clients.
+package github
+
+import (
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kyma-project/cli/internal/redact"
+)
+
+var (
+	token string
+	lock  sync.RWMutex
+)
+
+// DefaultClient authenticates the requests to GitHub and has no timeout, like http.DefaultClient
+var DefaultClient = NewClient(0)
+
+// SetToken configures the token added to the requests to GitHub. It is masked in the logs and traces of the CLI from now on.
+func SetToken(t string) {
+	redact.Add(t)
+	lock.Lock()
+	defer lock.Unlock()
+	token = t
+}
+
+func currentToken() string {
+	lock.RLock()
+	defer lock.RUnlock()
+	return token
+}
+
+// NewClient returns an HTTP client with the timeout, which authenticates the requests to GitHub with the token
+func NewClient(timeout time.Duration) *http.Client {
+	return &http.Client{Timeout: timeout, Transport: NewTransport(http.DefaultTransport)}
+}
+
+// NewTransport wraps the transport, so that the token is added to the requests to GitHub.
+// Requests to other hosts, such as the artifact buckets or mirrors, never get the token, also if GitHub redirects to them.
+func NewTransport(base http.RoundTripper) http.RoundTripper {
+	return &transport{base: base}
+}
+
+type transport struct {
+	base http.RoundTripper
+}
+
+func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	tok := currentToken()
+	if tok == "" || !isGitHub(req.URL.Hostname()) || req.Header.Get("Authorization") != "" {
+		return t.base.RoundTrip(req)
+	}
+	// a RoundTripper must not modify the request
+	req = req.Clone(req.Context())
+	req.Header.Set("Authorization", "token "+tok)
+	return t.base.RoundTrip(req)
+}
+
+// isGitHub checks if the host serves GitHub content, e.g. github.com, api.github.com or raw.githubusercontent.com
+func isGitHub(host string) bool {
+	host = strings.ToLower(host)
+	for _, domain := range []string{"github.com", "githubusercontent.com"} {
+		if host == domain || strings.HasSuffix(host, "."+domain) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,46 @@
+package github
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/kyma-project/cli/internal/redact"
+	"github.com/stretchr/testify/require"
+)
+
+// recorder is a transport recording the Authorization header of the requests
+type recorder struct {
+	auth []string
+}
+
+func (r *recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	r.auth = append(r.auth, req.Header.Get("Authorization"))
+	return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody, Request: req}, nil
+}
+
+func TestTransport(t *testing.T) {
+	// not parallel: the token is package level
+	defer SetToken("")
+	rec := &recorder{}
+	client := &http.Client{Timeout: time.Second, Transport: NewTransport(rec)}
+	get := func(url string) {
+		resp, err := client.Get(url)
+		require.NoError(t, err)
+		resp.Body.Close()
+	}
+
+	get("https://api.github.com/repos/kyma-project/kyma/releases")
+	require.Equal(t, []string{""}, rec.auth, "no header is added without a token")
+
+	SetToken("ghp_s3cr3t")
+	rec.auth = nil
+	get("https://api.github.com/repos/kyma-project/kyma/releases")
+	get("https://github.com/kyma-project/kyma/releases/download/1.18.0/kyma-installer-cluster.yaml")
+	get("https://raw.githubusercontent.com/kyma-project/kyma/master/README.md")
+	get("https://storage.googleapis.com/kyma-prow-artifacts/1.18.0/kyma-installer-cluster.yaml")
+	get("https://github.com.example.com/kyma-installer-cluster.yaml")
+	require.Equal(t, []string{"token ghp_s3cr3t", "token ghp_s3cr3t", "token ghp_s3cr3t", "", ""}, rec.auth, "only requests to GitHub get the token")
+
+	require.Equal(t, "--github-token="+redact.Placeholder, redact.String("--github-token=ghp_s3cr3t"), "the token is masked in the logs")
+}
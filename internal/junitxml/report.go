@@ -10,7 +10,7 @@ import (
 	oct "github.com/kyma-incubator/octopus/pkg/apis/testing/v1alpha1"
 	"github.com/pkg/errors"
 
-	"github.com/kyma-project/cli/cmd/kyma/version"
+	"github.com/kyma-project/cli/internal/cli"
 )
 
 //go:generate mockery --name logsFetcher --structname LogsFetcher
@@ -78,10 +78,10 @@ func (c *Creator) getNumberOfFailedTests(testSuite *oct.ClusterTestSuite) int {
 }
 
 func (c *Creator) cliVersion() string {
-	if version.Version == "" {
+	if cli.Version == "" {
 		return "N/A"
 	}
-	return version.Version
+	return cli.Version
 }
 
 func (c *Creator) packageProperties() []JUnitProperty {
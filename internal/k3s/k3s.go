@@ -9,6 +9,8 @@ import (
 	"time"
 
 	"github.com/blang/semver/v4"
+	"github.com/kyma-project/cli/internal/logger"
+	"github.com/kyma-project/cli/internal/trace"
 	"github.com/pkg/errors"
 )
 
@@ -24,8 +26,11 @@ func RunCmd(verbose bool, timeout time.Duration, args ...string) (string, error)
 
 	cmd := exec.CommandContext(ctx, "k3d", args...)
 
+	started := time.Now()
 	outBytes, err := cmd.CombinedOutput()
 	out := string(outBytes)
+	logger.Command("k3d", args, started, err)
+	trace.Record("k3d", started, err, trace.Attributes{"args": args})
 	if err != nil {
 		if verbose {
 			fmt.Printf("Failing command:\n  k3d %s\nwith output:\n  %s\nand error:\n  %s\n", strings.Join(args, " "), string(out), err)
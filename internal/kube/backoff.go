@@ -0,0 +1,84 @@
+package kube
+
+import (
+	"math/rand"
+	"time"
+)
+
+const (
+	// defaultBackoffInitial is the interval after the first check of a wait
+	defaultBackoffInitial = time.Second
+	// defaultBackoffMax caps the interval between two checks of a wait
+	defaultBackoffMax = 10 * time.Second
+	// defaultBackoffJitter is the share by which an interval is shortened at most
+	defaultBackoffJitter = 0.2
+)
+
+// clock provides the time to the waits, so that tests can simulate it
+type clock interface {
+	Now() time.Time
+	After(d time.Duration) <-chan time.Time
+}
+
+type realClock struct{}
+
+func (realClock) Now() time.Time                         { return time.Now() }
+func (realClock) After(d time.Duration) <-chan time.Time { return time.After(d) }
+
+// backoff spaces the checks of a wait: the interval starts at initial and doubles after every check, up to max.
+// Each interval is shortened by a random share of up to jitter, so that concurrent waits, e.g. of several clusters, do not query the API server in lockstep.
+type backoff struct {
+	initial time.Duration
+	max     time.Duration
+	jitter  float64
+	clock   clock
+	// random returns a number in [0,1) which scales the jitter
+	random func() float64
+	// current is the interval before jitter of the next wait, it is zero until the first wait
+	current time.Duration
+}
+
+// newBackoff returns the backoff of the waits with the given initial and maximum interval, using the real time
+func newBackoff(initial, max time.Duration) *backoff {
+	return &backoff{initial: initial, max: max, jitter: defaultBackoffJitter, clock: realClock{}, random: rand.Float64}
+}
+
+// defaultBackoff returns the backoff used by the waits of the client
+func defaultBackoff() *backoff {
+	return newBackoff(defaultBackoffInitial, defaultBackoffMax)
+}
+
+// next returns the interval until the next check and increases the following one
+func (b *backoff) next() time.Duration {
+	if b.current == 0 {
+		b.current = b.initial
+	} else if b.current *= 2; b.current > b.max {
+		b.current = b.max
+	}
+	d := b.current
+	if b.jitter > 0 {
+		d -= time.Duration(float64(d) * b.jitter * b.random())
+	}
+	return d
+}
+
+// reset starts the intervals at the initial one again, e.g. once the waited for resources changed
+func (b *backoff) reset() {
+	b.current = 0
+}
+
+// after returns a channel receiving the time once the next interval passed, but not later than the deadline if it is set
+func (b *backoff) after(deadline time.Time) <-chan time.Time {
+	d := b.next()
+	if !deadline.IsZero() {
+		if remaining := deadline.Sub(b.clock.Now()); remaining < d {
+			d = remaining
+		}
+	}
+	return b.clock.After(d)
+}
+
+// sleep waits for the next interval
+func (b *backoff) sleep() {
+	<-b.after(time.Time{})
+}
@@ -0,0 +1,138 @@
+package kube
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/kubernetes/fake"
+	k8sTesting "k8s.io/client-go/testing"
+)
+
+// fakeClock simulates the time, the intervals waited for pass immediately
+type fakeClock struct {
+	now   time.Time
+	waits []time.Duration
+}
+
+func (c *fakeClock) Now() time.Time { return c.now }
+
+func (c *fakeClock) After(d time.Duration) <-chan time.Time {
+	c.waits = append(c.waits, d)
+	c.now = c.now.Add(d)
+	ch := make(chan time.Time, 1)
+	ch <- c.now
+	return ch
+}
+
+func fakeBackoff(jitter float64) (*backoff, *fakeClock) {
+	c := &fakeClock{now: time.Date(2020, 9, 1, 0, 0, 0, 0, time.UTC)}
+	b := defaultBackoff()
+	b.clock, b.jitter, b.random = c, jitter, func() float64 { return 1 }
+	return b, c
+}
+
+func TestBackoff(t *testing.T) {
+	t.Parallel()
+	b, _ := fakeBackoff(0)
+	var intervals []time.Duration
+	for n := 0; n < 7; n++ {
+		intervals = append(intervals, b.next())
+	}
+	require.Equal(t, []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 8 * time.Second, 10 * time.Second, 10 * time.Second, 10 * time.Second}, intervals)
+
+	b.reset()
+	require.Equal(t, time.Second, b.next(), "the intervals start again after a reset")
+
+	// the jitter shortens the intervals by up to its share
+	b, _ = fakeBackoff(0.2)
+	require.Equal(t, 800*time.Millisecond, b.next())
+	b.random = func() float64 { return 0 }
+	require.Equal(t, 2*time.Second, b.next())
+
+	// the deadline is not exceeded
+	b, c := fakeBackoff(0)
+	b.next()
+	b.next()
+	<-b.after(c.now.Add(3 * time.Second))
+	require.Equal(t, []time.Duration{3 * time.Second}, c.waits)
+}
+
+func TestWaitForPodsPolling(t *testing.T) {
+	t.Parallel()
+	k8s := fake.NewSimpleClientset(&corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "a", Namespace: "ns", Labels: map[string]string{"app": "connector"}},
+		Status:     corev1.PodStatus{Phase: corev1.PodPending},
+	})
+	lists := 0
+	k8s.PrependReactor("list", "pods", func(k8sTesting.Action) (bool, runtime.Object, error) {
+		lists++
+		return false, nil, nil
+	})
+	// without a watch, the pods are polled
+	k8s.PrependWatchReactor("pods", func(k8sTesting.Action) (bool, watch.Interface, error) {
+		return true, nil, errors.New("watch not supported")
+	})
+
+	b, c := fakeBackoff(0)
+	err := waitForPods(k8s, "ns", "app=connector", time.Minute, b)
+	require.True(t, errors.Is(err, ErrWaitTimeout))
+	// checks after 0, 1, 3, 7, 15, 25, 35, 45 and 55 seconds, and at the timeout; a fixed interval of 3 seconds makes 21 checks
+	require.Equal(t, 10, lists)
+	require.Equal(t, time.Minute, c.waits[len(c.waits)-1]+55*time.Second)
+}
+
+func TestWaitForPodsWatch(t *testing.T) {
+	t.Parallel()
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "a", Namespace: "ns", Labels: map[string]string{"app": "connector"}},
+		Status:     corev1.PodStatus{Phase: corev1.PodPending},
+	}
+	k8s := fake.NewSimpleClientset(pod)
+	lists := 0
+	k8s.PrependReactor("list", "pods", func(k8sTesting.Action) (bool, runtime.Object, error) {
+		lists++
+		return false, nil, nil
+	})
+	// the pod becomes ready once the watch is open, and the watch reports the change before the interval passes
+	changes := watch.NewFakeWithChanSize(1, false)
+	watches := 0
+	k8s.PrependWatchReactor("pods", func(k8sTesting.Action) (bool, watch.Interface, error) {
+		watches++
+		ready := pod.DeepCopy()
+		ready.Status = corev1.PodStatus{Phase: corev1.PodRunning, Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue}}}
+		if err := k8s.Tracker().Update(corev1.SchemeGroupVersion.WithResource("pods"), ready, "ns"); err != nil {
+			return true, nil, err
+		}
+		changes.Modify(ready)
+		return true, changes, nil
+	})
+
+	b := newBackoff(time.Hour, time.Hour)
+	require.NoError(t, waitForPods(k8s, "ns", "app=connector", 0, b))
+	require.Equal(t, 2, lists)
+	require.Equal(t, 1, watches)
+}
+
+func TestWaitPodStatusByLabelBackoff(t *testing.T) {
+	t.Parallel()
+	// the ReplicaSet never creates its pod, the pods are checked until the grace period ends
+	k8s := fake.NewSimpleClientset(&appsv1.ReplicaSet{ObjectMeta: metav1.ObjectMeta{Name: "installer-abc", Namespace: "ns", Labels: map[string]string{"name": "installer"}}})
+	lists := 0
+	k8s.PrependReactor("list", "pods", func(k8sTesting.Action) (bool, runtime.Object, error) {
+		lists++
+		return false, nil, nil
+	})
+	c := &client{static: k8s}
+	b, clock := fakeBackoff(0)
+	require.NoError(t, c.waitPodStatusByLabel("ns", "name", "installer", corev1.PodRunning, 30*time.Second, b))
+	// checks after 0, 1, 3, 7, 15, 25 and 35 seconds
+	require.Equal(t, 7, lists)
+	require.Len(t, clock.waits, 6)
+}
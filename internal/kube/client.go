@@ -23,8 +23,9 @@ import (
 
 const (
 	defaultHTTPTimeout = 30 * time.Second
-	defaultWaitSleep   = 3 * time.Second
-	defaultNamespace   = "default"
+	// podCreationGracePeriod is the time given to a controller to create its pods before the reason is looked up in the events
+	podCreationGracePeriod = 30 * time.Second
+	defaultNamespace       = "default"
 )
 
 // client is the default KymaKube implementation
@@ -134,7 +135,22 @@ func (c *client) IsPodDeployedByLabel(namespace, labelName, labelValue string) (
 	return len(pods.Items) > 0, nil
 }
 
+func (c *client) IsPodReadyByLabel(namespace, labelName, labelValue string) (bool, error) {
+	pods, err := c.Static().CoreV1().Pods(namespace).List(context.Background(), metav1.ListOptions{LabelSelector: fmt.Sprintf("%s=%s", labelName, labelValue)})
+	if err != nil {
+		return false, err
+	}
+
+	for n := range pods.Items {
+		if pod := &pods.Items[n]; pod.Status.Phase != corev1.PodRunning || !podReady(pod) {
+			return false, nil
+		}
+	}
+	return len(pods.Items) > 0, nil
+}
+
 func (c *client) WaitPodStatus(namespace, name string, status corev1.PodPhase) error {
+	b := defaultBackoff()
 	for {
 		pod, err := c.Static().CoreV1().Pods(namespace).Get(context.Background(), name, metav1.GetOptions{})
 		if err != nil && !strings.Contains(err.Error(), "not found") {
@@ -144,17 +160,41 @@ func (c *client) WaitPodStatus(namespace, name string, status corev1.PodPhase) e
 		if status == pod.Status.Phase {
 			return nil
 		}
-		time.Sleep(defaultWaitSleep)
+		b.sleep()
 	}
 }
 
 func (c *client) WaitPodStatusByLabel(namespace, labelName, labelValue string, status corev1.PodPhase) error {
+	return c.waitPodStatusByLabel(namespace, labelName, labelValue, status, podCreationGracePeriod, defaultBackoff())
+}
+
+// waitPodStatusByLabel waits for the pods selected by the label. If a ReplicaSet with the label does not create its pods within the grace period,
+// it is checked for pods rejected by the cluster (e.g. by a PodSecurityPolicy) and a PodCreationError is returned.
+func (c *client) waitPodStatusByLabel(namespace, labelName, labelValue string, status corev1.PodPhase, grace time.Duration, b *backoff) error {
+	selector := fmt.Sprintf("%s=%s", labelName, labelValue)
+	started := b.clock.Now()
 	for {
-		pods, err := c.Static().CoreV1().Pods(namespace).List(context.Background(), metav1.ListOptions{LabelSelector: fmt.Sprintf("%s=%s", labelName, labelValue)})
+		pods, err := c.Static().CoreV1().Pods(namespace).List(context.Background(), metav1.ListOptions{LabelSelector: selector})
 		if err != nil {
 			return err
 		}
 
+		if len(pods.Items) == 0 {
+			// without a ReplicaSet no pod is expected, the lookups are made on a best effort basis and end the wait if they fail
+			sets, err := scaledReplicaSets(c.Static(), namespace, selector)
+			if err != nil || len(sets) == 0 {
+				return nil
+			}
+			if b.clock.Now().Sub(started) >= grace {
+				if failure, err := PodCreationFailures(c.Static(), namespace, selector); err == nil && failure != nil {
+					return failure
+				}
+				return nil
+			}
+			b.sleep()
+			continue
+		}
+
 		ok := true
 		for _, pod := range pods.Items {
 			// if any pod is not in the desired status no need to check further
@@ -166,7 +206,7 @@ func (c *client) WaitPodStatusByLabel(namespace, labelName, labelValue string, s
 		if ok {
 			return nil
 		}
-		time.Sleep(defaultWaitSleep)
+		b.sleep()
 	}
 }
 
@@ -176,6 +216,7 @@ func (c *client) WatchResource(res schema.GroupVersionResource, name, namespace
 		timeout = time.After(c.restCfg.Timeout)
 	}
 
+	b := defaultBackoff()
 	for {
 		select {
 		case <-timeout:
@@ -200,7 +241,7 @@ func (c *client) WatchResource(res schema.GroupVersionResource, name, namespace
 			if finished {
 				return nil
 			}
-			time.Sleep(defaultWaitSleep)
+			b.sleep()
 		}
 	}
 }
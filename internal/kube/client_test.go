@@ -92,6 +92,38 @@ func TestIsPodDeployedByLabel(t *testing.T) {
 
 }
 
+func TestIsPodReadyByLabel(t *testing.T) {
+	t.Parallel()
+	//setup
+	c := fakeClientWithNS()
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   "test-pod1",
+			Labels: map[string]string{"team": "huskies"},
+		},
+		Status: corev1.PodStatus{Phase: corev1.PodRunning},
+	}
+	_, err := c.Static().CoreV1().Pods("ns").Create(context.Background(), pod, metav1.CreateOptions{})
+	require.NoError(t, err)
+
+	// a running pod which is not ready yet
+	ready, err := c.IsPodReadyByLabel("ns", "team", "huskies")
+	require.NoError(t, err, "Checking if the Pod is ready and no errors occur.")
+	require.False(t, ready, "Pod is not ready")
+
+	pod.Status.Conditions = []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue}}
+	_, err = c.Static().CoreV1().Pods("ns").UpdateStatus(context.Background(), pod, metav1.UpdateOptions{})
+	require.NoError(t, err)
+	ready, err = c.IsPodReadyByLabel("ns", "team", "huskies")
+	require.NoError(t, err, "Checking if the Pod is ready and no errors occur.")
+	require.True(t, ready, "Pod is ready")
+
+	// no pod is never ready
+	ready, err = c.IsPodReadyByLabel("ns", "team", "skydiving-tunas")
+	require.NoError(t, err, "Checking if the Pod is ready and no errors occur.")
+	require.False(t, ready, "Pod is not deployed")
+}
+
 func TestWaitPodStatus(t *testing.T) {
 	t.Parallel()
 	// setup
@@ -259,3 +291,47 @@ func TestDefaultNamespace(t *testing.T) {
 	ns = c.DefaultNamespace()
 	require.Equal(t, "test", ns, "The kubeconfig namespace is expected.")
 }
+
+func TestWaitForPods(t *testing.T) {
+	t.Parallel()
+	ready := corev1.PodStatus{Phase: corev1.PodRunning, Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue}}}
+	pod := func(name string, status corev1.PodStatus) *corev1.Pod {
+		return &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "ns", Labels: map[string]string{"app": "connector"}}, Status: status}
+	}
+
+	t.Run("Ready and completed pods", func(t *testing.T) {
+		k8s := fake.NewSimpleClientset(pod("a", ready), pod("b", corev1.PodStatus{Phase: corev1.PodSucceeded}))
+		require.NoError(t, waitForPods(k8s, "ns", "app=connector", time.Second, newBackoff(time.Millisecond, time.Millisecond)))
+	})
+
+	t.Run("Pod becomes ready", func(t *testing.T) {
+		p := pod("a", corev1.PodStatus{Phase: corev1.PodPending})
+		k8s := fake.NewSimpleClientset(p)
+		go func() {
+			time.Sleep(20 * time.Millisecond)
+			p.Status = ready
+			_, _ = k8s.CoreV1().Pods("ns").UpdateStatus(context.Background(), p, metav1.UpdateOptions{})
+		}()
+		require.NoError(t, waitForPods(k8s, "ns", "app=connector", 5*time.Second, newBackoff(time.Millisecond, time.Millisecond)))
+	})
+
+	t.Run("Failing container", func(t *testing.T) {
+		k8s := fake.NewSimpleClientset(pod("a", corev1.PodStatus{Phase: corev1.PodPending, ContainerStatuses: []corev1.ContainerStatus{
+			{Name: "connector", State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "ImagePullBackOff", Message: "Back-off pulling image"}}},
+		}}))
+		err := waitForPods(k8s, "ns", "app=connector", time.Second, newBackoff(time.Millisecond, time.Millisecond))
+		failed := &PodFailedError{}
+		require.True(t, errors.As(err, &failed))
+		require.EqualError(t, err, "the pod 'a' failed: container 'connector' is in ImagePullBackOff Back-off pulling image")
+	})
+
+	t.Run("Timeout", func(t *testing.T) {
+		k8s := fake.NewSimpleClientset(pod("b", corev1.PodStatus{Phase: corev1.PodPending}), pod("a", ready))
+		err := waitForPods(k8s, "ns", "app=connector", 10*time.Millisecond, newBackoff(time.Millisecond, time.Millisecond))
+		require.True(t, errors.Is(err, ErrWaitTimeout))
+		require.EqualError(t, err, "timeout reached while waiting for the pods: not ready: b")
+
+		err = waitForPods(k8s, "ns", "app=missing", 10*time.Millisecond, newBackoff(time.Millisecond, time.Millisecond))
+		require.EqualError(t, err, "timeout reached while waiting for the pods: no pod matches 'app=missing' in the namespace 'ns'")
+	})
+}
@@ -1,29 +1,153 @@
 package kube
 
 import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/spf13/pflag"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
 	"k8s.io/client-go/tools/clientcmd/api"
 )
 
+const inClusterContext = "in-cluster"
+
+// serviceAccountNamespaceFile contains the namespace of the service account mounted into pods (used for in-cluster execution).
+var serviceAccountNamespaceFile = "/var/run/secrets/kubernetes.io/serviceaccount/namespace"
+
+// pathOptions provides the kubeconfig loading rules shared by all Kubernetes clients of the CLI.
+// Default PathOptions gets kubeconfig in this order: the explicit path given, all files listed in KUBECONFIG (merged), recommended file path
+func pathOptions(file string) *clientcmd.PathOptions {
+	po := clientcmd.NewDefaultPathOptions()
+	po.LoadingRules.ExplicitPath = file
+	return po
+}
+
+// ConfigPaths provides the kubeconfig files taken into account for the given explicit file.
+// If the file is empty, all files listed in the KUBECONFIG environment variable or the recommended file path are returned.
+func ConfigPaths(file string) []string {
+	po := pathOptions(file)
+	if po.IsExplicitFile() {
+		return []string{po.GetExplicitFile()}
+	}
+	return po.GetLoadingPrecedence()
+}
+
+// ExportConfigPath sets the KUBECONFIG environment variable to the given explicit file,
+// so that tools executed by the CLI (for example, minikube or k3d) use the same kubeconfig as the CLI itself.
+// If the file is empty, the environment is left untouched.
+func ExportConfigPath(file string) error {
+	if file == "" {
+		return nil
+	}
+	return os.Setenv(clientcmd.RecommendedConfigPathEnvVar, file)
+}
+
+// configOverrides holds the kubectl style connection flags applied to all Kubernetes clients of the CLI.
+var configOverrides = &clientcmd.ConfigOverrides{}
+
+// SetKubectlArgs parses kubectl style connection flags (for example, --insecure-skip-tls-verify or --request-timeout)
+// and applies them to all Kubernetes clients created afterwards.
+// It returns the names of the flags that were set.
+func SetKubectlArgs(args []string) ([]string, error) {
+	for _, a := range args {
+		if a == "-o" || strings.HasPrefix(a, "-o=") || a == "--output" || strings.HasPrefix(a, "--output=") {
+			return nil, fmt.Errorf("the kubectl argument '%s' is not supported because it changes the output format", a)
+		}
+	}
+
+	overrides := &clientcmd.ConfigOverrides{}
+	fs := pflag.NewFlagSet("kubectl-arg", pflag.ContinueOnError)
+	fs.Usage = func() {}
+	clientcmd.BindOverrideFlags(overrides, fs, clientcmd.RecommendedConfigOverrideFlags(""))
+	if err := fs.Parse(args); err != nil {
+		return nil, fmt.Errorf("invalid kubectl argument: %s", err)
+	}
+	if fs.NArg() > 0 {
+		return nil, fmt.Errorf("invalid kubectl argument: %s. Only flags are supported", strings.Join(fs.Args(), " "))
+	}
+
+	var names []string
+	fs.Visit(func(f *pflag.Flag) { names = append(names, "--"+f.Name) })
+	configOverrides = overrides
+	return names, nil
+}
+
+// SetContext selects the kubeconfig context used by all Kubernetes clients created afterwards, like the --context flag of kubectl.
+func SetContext(context string) {
+	configOverrides.CurrentContext = context
+}
+
+// ContextOverride returns the kubeconfig context set through the kubectl style connection flags, if any.
+func ContextOverride() string {
+	return configOverrides.CurrentContext
+}
+
+// CurrentContext returns the kubeconfig context used by the clients, which is the one set through the kubectl style connection flags or the current context of the kubeconfig.
+// It is empty if the kubeconfig cannot be read.
+func CurrentContext(file string) string {
+	if context := ContextOverride(); context != "" {
+		return context
+	}
+	cfg, err := kubeConfig(file)
+	if err != nil {
+		return ""
+	}
+	return cfg.CurrentContext
+}
+
+// isInCluster determines if the CLI runs inside a pod and no kubeconfig is available,
+// in which case the service account credentials of the pod are used.
+func isInCluster(file string) bool {
+	if file != "" || os.Getenv("KUBERNETES_SERVICE_HOST") == "" {
+		return false
+	}
+	for _, p := range ConfigPaths(file) {
+		if _, err := os.Stat(p); err == nil {
+			return false
+		}
+	}
+	return true
+}
+
 // restConfig loads the rest configuration needed by k8s clients to interact with clusters based on the kubeconfig.
 // Loading rules are based on standard defined kubernetes config loading.
+// If no kubeconfig exists and the CLI runs inside a pod, the in-cluster configuration is used.
 func restConfig(url, file string) (*rest.Config, error) {
-	// Default PathOptions gets kubeconfig in this order: the explicit path given, KUBECONFIG current context, recommentded file path
-	po := clientcmd.NewDefaultPathOptions()
-	po.LoadingRules.ExplicitPath = file
+	if url == "" && isInCluster(file) {
+		return rest.InClusterConfig()
+	}
 
-	return clientcmd.BuildConfigFromKubeconfigGetter(url, po.GetStartingConfig)
+	overrides := *configOverrides
+	if url != "" {
+		overrides.ClusterInfo.Server = url
+	}
+	return clientcmd.NewNonInteractiveDeferredLoadingClientConfig(pathOptions(file).LoadingRules, &overrides).ClientConfig()
 }
 
 // kubeConfig loads a structured representation of the Kubeconfig.
 // Loading rules are based on standard defined kubernetes config loading.
+// If no kubeconfig exists and the CLI runs inside a pod, a kubeconfig with the namespace of the pod's service account is provided.
 func kubeConfig(file string) (*api.Config, error) {
-	// Default PathOptions gets kubeconfig in this order: the explicit path given, KUBECONFIG current context, recommentded file path
-	po := clientcmd.NewDefaultPathOptions()
-	po.LoadingRules.ExplicitPath = file
+	if isInCluster(file) {
+		return inClusterKubeConfig(), nil
+	}
 
-	return po.GetStartingConfig()
+	return pathOptions(file).GetStartingConfig()
+}
+
+// inClusterKubeConfig builds a minimal kubeconfig for in-cluster execution with a context pointing to the service account namespace.
+func inClusterKubeConfig() *api.Config {
+	cfg := api.NewConfig()
+	ctx := api.NewContext()
+	if ns, err := ioutil.ReadFile(serviceAccountNamespaceFile); err == nil {
+		ctx.Namespace = strings.TrimSpace(string(ns))
+	}
+	cfg.Contexts[inClusterContext] = ctx
+	cfg.CurrentContext = inClusterContext
+	return cfg
 }
 
 // Append adds the provided kubeconfig in the []byte to the Kubeconfig in the target path without altering other existing conifgs.
@@ -34,9 +158,7 @@ func AppendConfig(cfg []byte, target string) error {
 		return err
 	}
 
-	// Default PathOptions gets kubeconfig in this order: the explicit path given, KUBECONFIG current context, recommentded file path
-	po := clientcmd.NewDefaultPathOptions()
-	po.LoadingRules.ExplicitPath = target
+	po := pathOptions(target)
 
 	t, err := po.GetStartingConfig()
 	if err != nil {
@@ -72,9 +194,7 @@ func RemoveConfig(cfg []byte, target string) error {
 		return err
 	}
 
-	// Default PathOptions gets kubeconfig in this order: the explicit path given, KUBECONFIG current context, recommentded file path
-	po := clientcmd.NewDefaultPathOptions()
-	po.LoadingRules.ExplicitPath = target
+	po := pathOptions(target)
 
 	t, err := po.GetStartingConfig()
 	if err != nil {
@@ -101,3 +221,28 @@ func RemoveConfig(cfg []byte, target string) error {
 	// write config back
 	return clientcmd.ModifyConfig(po, *t, false)
 }
+
+// AddUserContext adds the user and a context with the same name to the kubeconfig in the target path. The context uses the cluster of the current context,
+// which is kept as the current context. An existing user or context with the name is replaced.
+// If the target path is empty, standard kubeconfig loading rules apply.
+func AddUserContext(name string, user *api.AuthInfo, target string) error {
+	po := pathOptions(target)
+
+	t, err := po.GetStartingConfig()
+	if err != nil {
+		return err
+	}
+	current, ok := t.Contexts[t.CurrentContext]
+	if !ok || current.Cluster == "" {
+		return fmt.Errorf("the kubeconfig has no current context, so the cluster of the context '%s' is unknown", name)
+	}
+
+	ctx := api.NewContext()
+	ctx.Cluster = current.Cluster
+	ctx.AuthInfo = name
+	t.AuthInfos[name] = user
+	t.Contexts[name] = ctx
+
+	// write config back
+	return clientcmd.ModifyConfig(po, *t, false)
+}
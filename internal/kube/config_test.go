@@ -0,0 +1,191 @@
+package kube
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/client-go/tools/clientcmd/api"
+)
+
+const kubeconfigTpl = `apiVersion: v1
+kind: Config
+clusters:
+- cluster:
+    server: https://%[1]s.example.com
+  name: %[1]s
+contexts:
+- context:
+    cluster: %[1]s
+    user: %[1]s
+    namespace: ns-%[1]s
+  name: %[1]s
+current-context: %[2]s
+users:
+- name: %[1]s
+  user:
+    token: fake-token
+`
+
+func TestConfigMultiplePaths(t *testing.T) {
+	dir, err := ioutil.TempDir("", "kubeconfig")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	a := writeKubeconfig(t, dir, "a", "b")
+	b := writeKubeconfig(t, dir, "b", "a")
+
+	defer os.Setenv("KUBECONFIG", os.Getenv("KUBECONFIG"))
+	require.NoError(t, os.Setenv("KUBECONFIG", a+string(os.PathListSeparator)+b))
+
+	// all files listed in KUBECONFIG are used
+	require.Equal(t, []string{a, b}, ConfigPaths(""))
+
+	// the kubeconfig is merged and the first file wins for the current context
+	kc, err := kubeConfig("")
+	require.NoError(t, err)
+	require.Len(t, kc.Contexts, 2)
+	require.Equal(t, "b", kc.CurrentContext)
+
+	// the rest config resolves the merged current context
+	rc, err := restConfig("", "")
+	require.NoError(t, err)
+	require.Equal(t, "https://b.example.com", rc.Host)
+
+	// an explicit file overrules KUBECONFIG
+	c := writeKubeconfig(t, dir, "c", "c")
+	require.Equal(t, []string{c}, ConfigPaths(c))
+	rc, err = restConfig("", c)
+	require.NoError(t, err)
+	require.Equal(t, "https://c.example.com", rc.Host)
+}
+
+func TestInCluster(t *testing.T) {
+	dir, err := ioutil.TempDir("", "kubeconfig")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	defer os.Setenv("KUBECONFIG", os.Getenv("KUBECONFIG"))
+	defer os.Setenv("KUBERNETES_SERVICE_HOST", os.Getenv("KUBERNETES_SERVICE_HOST"))
+	nsFile := serviceAccountNamespaceFile
+	defer func() { serviceAccountNamespaceFile = nsFile }()
+
+	// no kubeconfig and not running in a pod
+	require.NoError(t, os.Setenv("KUBECONFIG", filepath.Join(dir, "missing")))
+	require.NoError(t, os.Unsetenv("KUBERNETES_SERVICE_HOST"))
+	require.False(t, isInCluster(""))
+
+	// no kubeconfig and running in a pod
+	require.NoError(t, os.Setenv("KUBERNETES_SERVICE_HOST", "10.0.0.1"))
+	require.True(t, isInCluster(""))
+
+	serviceAccountNamespaceFile = filepath.Join(dir, "namespace")
+	require.NoError(t, ioutil.WriteFile(serviceAccountNamespaceFile, []byte("kyma-system\n"), 0600))
+	kc, err := kubeConfig("")
+	require.NoError(t, err)
+	c := &client{kubeCfg: kc}
+	require.Equal(t, "kyma-system", c.DefaultNamespace())
+
+	// an existing kubeconfig always takes precedence over the in-cluster config
+	a := writeKubeconfig(t, dir, "a", "a")
+	require.NoError(t, os.Setenv("KUBECONFIG", a))
+	require.False(t, isInCluster(""))
+
+	// so does an explicit file
+	require.False(t, isInCluster(filepath.Join(dir, "explicit")))
+}
+
+func TestExportConfigPath(t *testing.T) {
+	defer os.Setenv("KUBECONFIG", os.Getenv("KUBECONFIG"))
+	require.NoError(t, os.Setenv("KUBECONFIG", "/some/kubeconfig"))
+
+	// no explicit file keeps the environment
+	require.NoError(t, ExportConfigPath(""))
+	require.Equal(t, "/some/kubeconfig", os.Getenv("KUBECONFIG"))
+
+	require.NoError(t, ExportConfigPath("/other/kubeconfig"))
+	require.Equal(t, "/other/kubeconfig", os.Getenv("KUBECONFIG"))
+}
+
+func TestAddUserContext(t *testing.T) {
+	dir, err := ioutil.TempDir("", "kubeconfig")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := writeKubeconfig(t, dir, "a", "a")
+
+	user := api.NewAuthInfo()
+	user.AuthProvider = &api.AuthProviderConfig{Name: "oidc", Config: map[string]string{"id-token": "id-token"}}
+	require.NoError(t, AddUserContext("kyma-local", user, path))
+
+	kc, err := clientcmd.LoadFromFile(path)
+	require.NoError(t, err)
+	require.Equal(t, "a", kc.CurrentContext, "the current context is kept")
+	require.Equal(t, "a", kc.Contexts["kyma-local"].Cluster, "the context uses the cluster of the current context")
+	require.Equal(t, "kyma-local", kc.Contexts["kyma-local"].AuthInfo)
+	require.Equal(t, "oidc", kc.AuthInfos["kyma-local"].AuthProvider.Name)
+	require.Equal(t, "fake-token", kc.AuthInfos["a"].Token, "the other users are kept")
+
+	// an existing user is replaced
+	user.AuthProvider.Config["id-token"] = "new-id-token"
+	require.NoError(t, AddUserContext("kyma-local", user, path))
+	kc, err = clientcmd.LoadFromFile(path)
+	require.NoError(t, err)
+	require.Equal(t, "new-id-token", kc.AuthInfos["kyma-local"].AuthProvider.Config["id-token"])
+
+	// without a current context, the cluster is unknown
+	empty := writeKubeconfig(t, dir, "b", "")
+	require.Error(t, AddUserContext("kyma-local", user, empty))
+}
+
+func writeKubeconfig(t *testing.T, dir, name, currentContext string) string {
+	path := filepath.Join(dir, name)
+	require.NoError(t, ioutil.WriteFile(path, []byte(fmt.Sprintf(kubeconfigTpl, name, currentContext)), 0600))
+	return path
+}
+
+func TestSetKubectlArgs(t *testing.T) {
+	dir, err := ioutil.TempDir("", "kubeconfig")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	c := writeKubeconfig(t, dir, "c", "c")
+	defer func() { configOverrides = &clientcmd.ConfigOverrides{} }()
+
+	// connection flags are applied to the rest config
+	flags, err := SetKubectlArgs([]string{"--insecure-skip-tls-verify", "--request-timeout=30s"})
+	require.NoError(t, err)
+	require.Equal(t, []string{"--insecure-skip-tls-verify", "--request-timeout"}, flags)
+	rc, err := restConfig("", c)
+	require.NoError(t, err)
+	require.True(t, rc.Insecure)
+	require.Equal(t, 30*time.Second, rc.Timeout)
+
+	// the explicit URL still wins
+	rc, err = restConfig("https://other.example.com", c)
+	require.NoError(t, err)
+	require.Equal(t, "https://other.example.com", rc.Host)
+
+	// flags changing the output format are rejected
+	_, err = SetKubectlArgs([]string{"-o", "json"})
+	require.Error(t, err)
+	_, err = SetKubectlArgs([]string{"--output=yaml"})
+	require.Error(t, err)
+
+	// unknown flags and positional arguments are rejected
+	_, err = SetKubectlArgs([]string{"--unknown-flag"})
+	require.Error(t, err)
+	_, err = SetKubectlArgs([]string{"get", "pods"})
+	require.Error(t, err)
+
+	// no flags reset the overrides
+	flags, err = SetKubectlArgs(nil)
+	require.NoError(t, err)
+	require.Empty(t, flags)
+	rc, err = restConfig("", c)
+	require.NoError(t, err)
+	require.False(t, rc.Insecure)
+}
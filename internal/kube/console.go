@@ -0,0 +1,33 @@
+package kube
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	consoleNamespace      = "kyma-system"
+	consoleVirtualService = "console-web"
+	consoleHostPrefix     = "console."
+)
+
+// ConsoleHost reads the host of the Kyma console from its virtual service, which is the host the cluster was installed with.
+// Errors of the API server are returned unchanged, so that callers can check with apiErrors.IsNotFound if the console is not installed.
+func ConsoleHost(k KymaKube) (string, error) {
+	vs, err := k.Istio().NetworkingV1alpha3().VirtualServices(consoleNamespace).Get(context.Background(), consoleVirtualService, metav1.GetOptions{})
+	if err != nil {
+		return "", err
+	}
+	if vs == nil || len(vs.Spec.Hosts) == 0 {
+		return "", errors.New("the virtual service of the console has no host")
+	}
+	return vs.Spec.Hosts[0], nil
+}
+
+// ConsoleDomain returns the domain of the cluster from the host of the console, or the host itself if it does not follow the console.<domain> scheme
+func ConsoleDomain(host string) string {
+	return strings.TrimPrefix(host, consoleHostPrefix)
+}
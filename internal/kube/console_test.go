@@ -0,0 +1,34 @@
+package kube
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	networkingv1alpha3 "istio.io/api/networking/v1alpha3"
+	"istio.io/client-go/pkg/apis/networking/v1alpha3"
+	fakeIstio "istio.io/client-go/pkg/clientset/versioned/fake"
+	apiErrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestConsoleHost(t *testing.T) {
+	t.Parallel()
+	console := func(hosts ...string) *v1alpha3.VirtualService {
+		return &v1alpha3.VirtualService{
+			ObjectMeta: metav1.ObjectMeta{Name: "console-web", Namespace: "kyma-system"},
+			Spec:       networkingv1alpha3.VirtualService{Hosts: hosts},
+		}
+	}
+
+	host, err := ConsoleHost(&client{istio: fakeIstio.NewSimpleClientset(console("console.kyma.example.com"))})
+	require.NoError(t, err)
+	require.Equal(t, "console.kyma.example.com", host)
+	require.Equal(t, "kyma.example.com", ConsoleDomain(host))
+	require.Equal(t, "kyma.example.com", ConsoleDomain("kyma.example.com"))
+
+	_, err = ConsoleHost(&client{istio: fakeIstio.NewSimpleClientset(console())})
+	require.EqualError(t, err, "the virtual service of the console has no host")
+
+	_, err = ConsoleHost(&client{istio: fakeIstio.NewSimpleClientset()})
+	require.True(t, apiErrors.IsNotFound(err))
+}
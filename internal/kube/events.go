@@ -0,0 +1,104 @@
+package kube
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// reasonFailedCreate is the reason of the events recorded if a controller is not allowed to create its pods
+const reasonFailedCreate = "FailedCreate"
+
+// PodCreationError is returned if the pods of a ReplicaSet are not created, for example because they are forbidden by a PodSecurityPolicy
+type PodCreationError struct {
+	ReplicaSet string
+	Messages   []string
+}
+
+func (e *PodCreationError) Error() string {
+	return fmt.Sprintf("the pods of the ReplicaSet '%s' are not created: %s", e.ReplicaSet, strings.Join(e.Messages, "; "))
+}
+
+// Forbidden checks if the pods were rejected by an admission controller, such as the PodSecurityPolicy admission
+func (e *PodCreationError) Forbidden() bool {
+	for _, m := range e.Messages {
+		if strings.Contains(m, "forbidden") {
+			return true
+		}
+	}
+	return false
+}
+
+// Events returns the events of the given object, oldest first.
+// The events are filtered by the client, since field selectors are not supported by all API servers and fake clients.
+func Events(k8s kubernetes.Interface, namespace, kind, name string) ([]corev1.Event, error) {
+	list, err := k8s.CoreV1().Events(namespace).List(context.Background(), metav1.ListOptions{})
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to read the events of the %s '%s'", kind, name)
+	}
+	var events []corev1.Event
+	for _, e := range list.Items {
+		if e.InvolvedObject.Kind == kind && e.InvolvedObject.Name == name {
+			events = append(events, e)
+		}
+	}
+	sort.SliceStable(events, func(a, b int) bool { return events[a].LastTimestamp.Before(&events[b].LastTimestamp) })
+	return events, nil
+}
+
+// PodCreationFailures checks the ReplicaSets matching the label selector which should have pods for FailedCreate events.
+// A PodCreationError with the messages of the events is returned for the first ReplicaSet which failed to create its pods, nil otherwise.
+func PodCreationFailures(k8s kubernetes.Interface, namespace, selector string) (*PodCreationError, error) {
+	sets, err := scaledReplicaSets(k8s, namespace, selector)
+	if err != nil {
+		return nil, err
+	}
+	for _, rs := range sets {
+		events, err := Events(k8s, namespace, "ReplicaSet", rs.Name)
+		if err != nil {
+			return nil, err
+		}
+		var messages []string
+		for _, e := range events {
+			if e.Reason == reasonFailedCreate && !contains(messages, e.Message) {
+				messages = append(messages, e.Message)
+			}
+		}
+		if len(messages) > 0 {
+			return &PodCreationError{ReplicaSet: rs.Name, Messages: messages}, nil
+		}
+	}
+	return nil, nil
+}
+
+// scaledReplicaSets returns the ReplicaSets matching the label selector which should have pods, old ReplicaSets of a Deployment are scaled to zero
+func scaledReplicaSets(k8s kubernetes.Interface, namespace, selector string) ([]appsv1.ReplicaSet, error) {
+	list, err := k8s.AppsV1().ReplicaSets(namespace).List(context.Background(), metav1.ListOptions{LabelSelector: selector})
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to find the ReplicaSets matching '%s'", selector)
+	}
+	var sets []appsv1.ReplicaSet
+	for _, rs := range list.Items {
+		// the API server defaults the replicas to 1
+		if rs.Spec.Replicas == nil || *rs.Spec.Replicas > 0 {
+			sets = append(sets, rs)
+		}
+	}
+	return sets, nil
+}
+
+func contains(list []string, s string) bool {
+	for _, e := range list {
+		if e == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,78 @@
+package kube
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func forbiddenReplicaSet() *fake.Clientset {
+	return fake.NewSimpleClientset(
+		&appsv1.ReplicaSet{ObjectMeta: metav1.ObjectMeta{Name: "installer-abc", Namespace: "ns", Labels: map[string]string{"name": "installer"}}},
+		&corev1.Event{
+			ObjectMeta:     metav1.ObjectMeta{Name: "e1", Namespace: "ns"},
+			InvolvedObject: corev1.ObjectReference{Kind: "ReplicaSet", Name: "installer-abc"},
+			Reason:         "FailedCreate",
+			Message:        `Error creating: pods "installer-abc-" is forbidden: unable to validate against any pod security policy: []`,
+		},
+		&corev1.Event{
+			ObjectMeta:     metav1.ObjectMeta{Name: "e2", Namespace: "ns"},
+			InvolvedObject: corev1.ObjectReference{Kind: "ReplicaSet", Name: "other"},
+			Reason:         "FailedCreate",
+			Message:        "unrelated",
+		},
+	)
+}
+
+func TestPodCreationFailures(t *testing.T) {
+	t.Parallel()
+	failure, err := PodCreationFailures(forbiddenReplicaSet(), "ns", "name=installer")
+	require.NoError(t, err)
+	require.NotNil(t, failure)
+	require.Equal(t, "installer-abc", failure.ReplicaSet)
+	require.Len(t, failure.Messages, 1, "only the events of the selected ReplicaSet are returned")
+	require.True(t, failure.Forbidden())
+
+	failure, err = PodCreationFailures(forbiddenReplicaSet(), "ns", "name=other")
+	require.NoError(t, err)
+	require.Nil(t, failure)
+}
+
+func TestWaitPodStatusByLabelCreationFailure(t *testing.T) {
+	t.Parallel()
+	c := &client{static: forbiddenReplicaSet()}
+	err := c.waitPodStatusByLabel("ns", "name", "installer", corev1.PodRunning, 10*time.Millisecond, newBackoff(time.Millisecond, time.Millisecond))
+	failure := &PodCreationError{}
+	require.True(t, errors.As(err, &failure))
+	require.Contains(t, err.Error(), "unable to validate against any pod security policy")
+
+	// without a ReplicaSet no pod is expected
+	c = fakeClientWithNS()
+	require.NoError(t, c.waitPodStatusByLabel("ns", "name", "installer", corev1.PodRunning, time.Hour, newBackoff(time.Millisecond, time.Millisecond)))
+
+	// a ReplicaSet scaled to zero does not create pods
+	replicas := int32(0)
+	c = &client{static: fake.NewSimpleClientset(&appsv1.ReplicaSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "installer-old", Namespace: "ns", Labels: map[string]string{"name": "installer"}},
+		Spec:       appsv1.ReplicaSetSpec{Replicas: &replicas},
+	})}
+	require.NoError(t, c.waitPodStatusByLabel("ns", "name", "installer", corev1.PodRunning, time.Hour, newBackoff(time.Millisecond, time.Millisecond)))
+
+	// pods created during the grace period are waited for
+	c = &client{static: fake.NewSimpleClientset(&appsv1.ReplicaSet{ObjectMeta: metav1.ObjectMeta{Name: "installer-abc", Namespace: "ns", Labels: map[string]string{"name": "installer"}}})}
+	go func() {
+		time.Sleep(5 * time.Millisecond)
+		_, _ = c.Static().CoreV1().Pods("ns").Create(context.Background(), &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "installer", Labels: map[string]string{"name": "installer"}},
+			Status:     corev1.PodStatus{Phase: corev1.PodRunning},
+		}, metav1.CreateOptions{})
+	}()
+	require.NoError(t, c.waitPodStatusByLabel("ns", "name", "installer", corev1.PodRunning, time.Hour, newBackoff(time.Millisecond, time.Millisecond)))
+}
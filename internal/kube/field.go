@@ -0,0 +1,129 @@
+package kube
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+	apiErrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/util/jsonpath"
+)
+
+var (
+	// ErrResourceNotFound is returned by GetResourceField if the resource does not exist
+	ErrResourceNotFound = errors.New("not found")
+	// ErrFieldNotSet is returned by GetResourceField if the field is empty or missing, for example because it is not populated yet
+	ErrFieldNotSet = errors.New("not set")
+)
+
+// GetResourceField returns the field of a resource selected with a kubectl style JSONPath, e.g. {.data.global\.ingress\.tlsCrt}, the braces are optional.
+// ConfigMaps and Secrets are read with the client, other kinds and all kinds without a client are read with kubectl.
+// Surrounding whitespace and quotes are removed from the value, which is base64 decoded if decodeBase64 is set, also if it is wrapped over multiple lines.
+// ErrResourceNotFound and ErrFieldNotSet can be checked with errors.Is.
+func GetResourceField(k8s kubernetes.Interface, kind, name, namespace, path string, decodeBase64 bool) (string, error) {
+	resource := fmt.Sprintf("the %s '%s/%s'", kindName(kind), namespace, name)
+	if !strings.HasPrefix(path, "{") {
+		path = fmt.Sprintf("{%s}", path)
+	}
+	field := strings.TrimSuffix(strings.TrimPrefix(path, "{"), "}")
+	jp := jsonpath.New(field).AllowMissingKeys(true)
+	if err := jp.Parse(path); err != nil {
+		return "", errors.Wrapf(err, "invalid path '%s'", path)
+	}
+
+	var obj interface{}
+	var err error
+	if k8s != nil && isClientKind(kind) {
+		obj, err = clientResource(k8s, kind, name, namespace, resource)
+	} else {
+		obj, err = kubectlResource(kind, name, namespace, resource)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	buf := &bytes.Buffer{}
+	if err := jp.Execute(buf, obj); err != nil {
+		return "", errors.Wrapf(err, "unable to read the field '%s' of %s", field, resource)
+	}
+
+	value := strings.Trim(strings.TrimSpace(buf.String()), `"'`)
+	if value == "" {
+		return "", errors.Wrapf(ErrFieldNotSet, "the field '%s' of %s", field, resource)
+	}
+	if !decodeBase64 {
+		return value, nil
+	}
+	decoded, err := base64.StdEncoding.DecodeString(value)
+	if err != nil {
+		// values might be wrapped over multiple lines
+		if decoded, err = base64.StdEncoding.DecodeString(strings.Join(strings.Fields(value), "")); err != nil {
+			return "", fmt.Errorf("the field '%s' of %s is not a valid base64 encoded value", field, resource)
+		}
+	}
+	return string(decoded), nil
+}
+
+// JSONPathKey escapes the dots of a key, so that it can be part of a JSONPath, e.g. for the keys of ConfigMaps
+func JSONPathKey(key string) string {
+	return strings.ReplaceAll(key, ".", `\.`)
+}
+
+func isClientKind(kind string) bool {
+	switch strings.ToLower(kind) {
+	case "configmap", "secret":
+		return true
+	}
+	return false
+}
+
+// kindName returns the kind as it is written in messages
+func kindName(kind string) string {
+	switch strings.ToLower(kind) {
+	case "configmap":
+		return "ConfigMap"
+	case "secret":
+		return "Secret"
+	}
+	return kind
+}
+
+// clientResource reads the ConfigMap or Secret in the format kubectl returns it, so that the same paths apply, e.g. the values of Secrets are base64 encoded
+func clientResource(k8s kubernetes.Interface, kind, name, namespace, resource string) (interface{}, error) {
+	var obj runtime.Object
+	var err error
+	if strings.ToLower(kind) == "secret" {
+		obj, err = k8s.CoreV1().Secrets(namespace).Get(context.Background(), name, metav1.GetOptions{})
+	} else {
+		obj, err = k8s.CoreV1().ConfigMaps(namespace).Get(context.Background(), name, metav1.GetOptions{})
+	}
+	if apiErrors.IsNotFound(err) {
+		return nil, errors.Wrapf(ErrResourceNotFound, "%s", resource)
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to read %s", resource)
+	}
+	return runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+}
+
+func kubectlResource(kind, name, namespace, resource string) (interface{}, error) {
+	out, err := runKubectl("get", strings.ToLower(kind), name, "--namespace", namespace, "--output", "json")
+	if err != nil {
+		if strings.Contains(err.Error(), "NotFound") {
+			return nil, errors.Wrapf(ErrResourceNotFound, "%s", resource)
+		}
+		return nil, errors.Wrapf(err, "unable to read %s", resource)
+	}
+	var obj interface{}
+	if err := json.Unmarshal(out, &obj); err != nil {
+		return nil, errors.Wrapf(err, "unable to parse %s", resource)
+	}
+	return obj, nil
+}
@@ -0,0 +1,101 @@
+package kube
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestGetResourceField(t *testing.T) {
+	t.Parallel()
+	k8s := fake.NewSimpleClientset(
+		&corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "net-global-overrides", Namespace: "kyma-installer"},
+			Data: map[string]string{
+				"plain":                 "a3ltYS1jZXJ0aWZpY2F0ZQ==",
+				"quoted":                "'a3ltYS1jZXJ0aWZpY2F0ZQ=='\n",
+				"doubleQuoted":          `"a3ltYS1jZXJ0aWZpY2F0ZQ=="`,
+				"wrapped":               "a3ltYS1jZXJ0\naWZpY2F0ZQ==\n",
+				"empty":                 " \n",
+				"invalid":               "not-base64!",
+				"global.ingress.tlsCrt": "a3ltYS1jZXJ0aWZpY2F0ZQ==",
+			},
+		},
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "admin-user", Namespace: "kyma-system"},
+			Data:       map[string][]byte{"password": []byte("s3cr3t")},
+		},
+	)
+
+	cases := []struct {
+		name        string
+		kind        string
+		path        string
+		decode      bool
+		expected    string
+		expectedErr string
+	}{
+		{name: "plain value", kind: "configmap", path: "{.data.plain}", decode: true, expected: "kyma-certificate"},
+		{name: "quoted value", kind: "configmap", path: "{.data.quoted}", decode: true, expected: "kyma-certificate"},
+		{name: "double quoted value", kind: "configmap", path: "{.data.doubleQuoted}", decode: true, expected: "kyma-certificate"},
+		{name: "newline wrapped value", kind: "configmap", path: "{.data.wrapped}", decode: true, expected: "kyma-certificate"},
+		{name: "escaped key", kind: "configmap", path: `{.data.global\.ingress\.tlsCrt}`, decode: true, expected: "kyma-certificate"},
+		{name: "path without braces", kind: "ConfigMap", path: ".data.plain", decode: true, expected: "kyma-certificate"},
+		{name: "not decoded", kind: "configmap", path: "{.data.quoted}", expected: "a3ltYS1jZXJ0aWZpY2F0ZQ=="},
+		{name: "Secret values are encoded", kind: "secret", path: "{.data.password}", decode: true, expected: "s3cr3t"},
+		{name: "metadata", kind: "secret", path: "{.metadata.namespace}", expected: "kyma-system"},
+		{name: "empty value", kind: "configmap", path: "{.data.empty}", decode: true, expectedErr: "the field '.data.empty' of the ConfigMap 'kyma-installer/net-global-overrides': not set"},
+		{name: "missing value", kind: "configmap", path: "{.data.missing}", expectedErr: "the field '.data.missing' of the ConfigMap 'kyma-installer/net-global-overrides': not set"},
+		{name: "invalid value", kind: "configmap", path: "{.data.invalid}", decode: true, expectedErr: "the field '.data.invalid' of the ConfigMap 'kyma-installer/net-global-overrides' is not a valid base64 encoded value"},
+		{name: "invalid path", kind: "configmap", path: "{.data[}", expectedErr: "invalid path '{.data[}'"},
+	}
+	for _, c := range cases {
+		value, err := GetResourceField(k8s, c.kind, "net-global-overrides", "kyma-installer", c.path, c.decode)
+		if c.kind == "secret" {
+			value, err = GetResourceField(k8s, c.kind, "admin-user", "kyma-system", c.path, c.decode)
+		}
+		if c.expectedErr != "" {
+			require.Error(t, err, c.name)
+			require.Contains(t, err.Error(), c.expectedErr, c.name)
+		} else {
+			require.NoError(t, err, c.name)
+			require.Equal(t, c.expected, value, c.name)
+		}
+	}
+
+	_, err := GetResourceField(k8s, "configmap", "missing", "kyma-installer", "{.data.plain}", false)
+	require.True(t, errors.Is(err, ErrResourceNotFound))
+	require.EqualError(t, err, "the ConfigMap 'kyma-installer/missing': not found")
+	_, err = GetResourceField(k8s, "configmap", "net-global-overrides", "kyma-installer", "{.data.empty}", false)
+	require.True(t, errors.Is(err, ErrFieldNotSet))
+}
+
+func TestGetResourceFieldWithKubectl(t *testing.T) {
+	// runKubectl is replaced, so the test must not run in parallel with other kubectl users
+	defer func(run func(args ...string) ([]byte, error)) { runKubectl = run }(runKubectl)
+	var args []string
+	runKubectl = func(a ...string) ([]byte, error) {
+		args = a
+		if a[2] != "kyma-installation" {
+			return nil, errors.New(`executing 'kubectl get installation' failed: Error from server (NotFound): installations.installer.kyma-project.io "other" not found`)
+		}
+		return []byte(`{"kind":"Installation","metadata":{"name":"kyma-installation"},"status":{"state":"Installed","description":""}}`), nil
+	}
+
+	// kinds other than ConfigMaps and Secrets are read with kubectl, also if a client is given
+	value, err := GetResourceField(fake.NewSimpleClientset(), "installation", "kyma-installation", "default", "{.status.state}", false)
+	require.NoError(t, err)
+	require.Equal(t, "Installed", value)
+	require.Equal(t, []string{"get", "installation", "kyma-installation", "--namespace", "default", "--output", "json"}, args)
+
+	_, err = GetResourceField(nil, "installation", "kyma-installation", "default", "{.status.description}", false)
+	require.True(t, errors.Is(err, ErrFieldNotSet))
+
+	_, err = GetResourceField(nil, "installation", "other", "default", "{.status.state}", false)
+	require.True(t, errors.Is(err, ErrResourceNotFound))
+	require.EqualError(t, err, "the installation 'default/other': not found")
+}
@@ -40,10 +40,14 @@ type KymaKube interface {
 	// IsPodDeployedByLabel checks if there is at least 1 pod in the given namespace with the given label  (independently of its status)
 	IsPodDeployedByLabel(namespace, labelName, labelValue string) (bool, error)
 
+	// IsPodReadyByLabel checks if there is at least 1 pod in the given namespace with the given label and all of them are running and ready
+	IsPodReadyByLabel(namespace, labelName, labelValue string) (bool, error)
+
 	// WaitPodStatus waits for the given pod to reach the desired status.
 	WaitPodStatus(namespace, name string, status corev1.PodPhase) error
 
-	// WaitPodStatusByLabel selects a set of pods by label and waits for them
+	// WaitPodStatusByLabel selects a set of pods by label and waits for them.
+	// If a ReplicaSet with the label does not create its pods within a grace period, a PodCreationError with the reason is returned.
 	WaitPodStatusByLabel(namespace, labelName, labelValue string, status corev1.PodPhase) error
 
 	// WatchResource watches an arbitrary resource using the k8s unstructured API.
@@ -100,6 +100,27 @@ func (_m *KymaKube) IsPodDeployedByLabel(namespace string, labelName string, lab
 	return r0, r1
 }
 
+// IsPodReadyByLabel provides a mock function with given fields: namespace, labelName, labelValue
+func (_m *KymaKube) IsPodReadyByLabel(namespace string, labelName string, labelValue string) (bool, error) {
+	ret := _m.Called(namespace, labelName, labelValue)
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func(string, string, string) bool); ok {
+		r0 = rf(namespace, labelName, labelValue)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, string, string) error); ok {
+		r1 = rf(namespace, labelName, labelValue)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Istio provides a mock function with given fields:
 func (_m *KymaKube) Istio() versioned.Interface {
 	ret := _m.Called()
@@ -0,0 +1,141 @@
+package kube
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/portforward"
+	"k8s.io/client-go/transport/spdy"
+)
+
+// portForwardRetryInterval is the time after which a lost port-forward is established again
+const portForwardRetryInterval = 2 * time.Second
+
+// PortForward forwards a local port to a service, until the stop channel is closed.
+// The connection is made to a running pod of the service, and is established again with another pod if the pod is restarted or replaced.
+// Ready is called each time the forward is established, and Lost is called with the reason each time it is lost.
+type PortForward struct {
+	Namespace string
+	Service   string
+	LocalPort int
+	Ready     func(pod string)
+	Lost      func(err error)
+}
+
+// Run keeps the port-forward alive until the stop channel is closed. An error is only returned if the service cannot be forwarded at all.
+func (p *PortForward) Run(k KymaKube, stop <-chan struct{}) error {
+	if _, _, err := servicePod(k.Static(), p.Namespace, p.Service); err != nil {
+		return err
+	}
+	for {
+		err := p.forward(k, stop)
+		select {
+		case <-stop:
+			return nil
+		default:
+		}
+		if p.Lost != nil {
+			p.Lost(err)
+		}
+		select {
+		case <-stop:
+			return nil
+		case <-time.After(portForwardRetryInterval):
+		}
+	}
+}
+
+func (p *PortForward) forward(k KymaKube, stop <-chan struct{}) error {
+	pod, port, err := servicePod(k.Static(), p.Namespace, p.Service)
+	if err != nil {
+		return err
+	}
+	transport, upgrader, err := spdy.RoundTripperFor(k.RestConfig())
+	if err != nil {
+		return errors.Wrap(err, "unable to create the port-forward connection")
+	}
+	url := k.Static().CoreV1().RESTClient().Post().Resource("pods").Namespace(p.Namespace).Name(pod).SubResource("portforward").URL()
+	dialer := spdy.NewDialer(upgrader, &http.Client{Transport: transport}, http.MethodPost, url)
+
+	ready := make(chan struct{})
+	fw, err := portforward.New(dialer, []string{fmt.Sprintf("%d:%d", p.LocalPort, port)}, stop, ready, ioutil.Discard, ioutil.Discard)
+	if err != nil {
+		return errors.Wrapf(err, "unable to forward port %d to the pod '%s'", p.LocalPort, pod)
+	}
+	// the forward can fail before it is ready, so the goroutine waiting for it is ended together with the attempt
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		p.awaitReady(pod, ready, stop, done)
+	}()
+	defer func() {
+		close(done)
+		wg.Wait()
+	}()
+	if err := fw.ForwardPorts(); err != nil {
+		return err
+	}
+	return fmt.Errorf("the connection to the pod '%s' was closed", pod)
+}
+
+// awaitReady calls Ready once the forward to the pod is established, unless the forward is stopped or the attempt is over before
+func (p *PortForward) awaitReady(pod string, ready, stop, done <-chan struct{}) {
+	select {
+	case <-ready:
+		if p.Ready != nil {
+			p.Ready(pod)
+		}
+	case <-stop:
+	case <-done:
+	}
+}
+
+// servicePod returns a running pod of the service and the pod port targeted by the first port of the service
+func servicePod(k8s kubernetes.Interface, namespace, service string) (string, int, error) {
+	svc, err := k8s.CoreV1().Services(namespace).Get(context.Background(), service, metav1.GetOptions{})
+	if err != nil {
+		return "", 0, errors.Wrapf(err, "unable to find the service '%s' in the namespace '%s'", service, namespace)
+	}
+	if len(svc.Spec.Ports) == 0 || len(svc.Spec.Selector) == 0 {
+		return "", 0, fmt.Errorf("the service '%s' has no ports or no pod selector", service)
+	}
+	pods, err := k8s.CoreV1().Pods(namespace).List(context.Background(), metav1.ListOptions{LabelSelector: labels.SelectorFromSet(svc.Spec.Selector).String()})
+	if err != nil {
+		return "", 0, errors.Wrapf(err, "unable to find the pods of the service '%s'", service)
+	}
+
+	target := svc.Spec.Ports[0].TargetPort
+	for _, pod := range pods.Items {
+		if pod.Status.Phase != corev1.PodRunning || pod.DeletionTimestamp != nil {
+			continue
+		}
+		if target.Type == intstr.String {
+			// named target ports are resolved with the container ports of the pod
+			for _, c := range pod.Spec.Containers {
+				for _, port := range c.Ports {
+					if port.Name == target.StrVal {
+						return pod.Name, int(port.ContainerPort), nil
+					}
+				}
+			}
+			continue
+		}
+		if target.IntValue() > 0 {
+			return pod.Name, target.IntValue(), nil
+		}
+		return pod.Name, int(svc.Spec.Ports[0].Port), nil
+	}
+	return "", 0, fmt.Errorf("the service '%s' has no running pod", service)
+}
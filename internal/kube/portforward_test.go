@@ -0,0 +1,76 @@
+package kube
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestServicePod(t *testing.T) {
+	t.Parallel()
+	service := func(name string, target intstr.IntOrString) *corev1.Service {
+		return &corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "kyma-system"},
+			Spec: corev1.ServiceSpec{
+				Selector: map[string]string{"app": "console"},
+				Ports:    []corev1.ServicePort{{Port: 80, TargetPort: target}},
+			},
+		}
+	}
+	pod := func(name string, phase corev1.PodPhase) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "kyma-system", Labels: map[string]string{"app": "console"}},
+			Spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: "console", Ports: []corev1.ContainerPort{{Name: "http", ContainerPort: 8080}}}}},
+			Status:     corev1.PodStatus{Phase: phase},
+		}
+	}
+
+	k8s := fake.NewSimpleClientset(
+		service("console-web", intstr.FromInt(8080)),
+		service("console-named", intstr.FromString("http")),
+		service("console-default", intstr.IntOrString{}),
+		pod("console-pending", corev1.PodPending),
+		pod("console-running", corev1.PodRunning),
+		&corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: "no-pods", Namespace: "kyma-system"}, Spec: corev1.ServiceSpec{
+			Selector: map[string]string{"app": "missing"},
+			Ports:    []corev1.ServicePort{{Port: 80}},
+		}},
+	)
+
+	for svc, port := range map[string]int{"console-web": 8080, "console-named": 8080, "console-default": 80} {
+		name, p, err := servicePod(k8s, "kyma-system", svc)
+		require.NoError(t, err, svc)
+		require.Equal(t, "console-running", name, svc)
+		require.Equal(t, port, p, svc)
+	}
+
+	_, _, err := servicePod(k8s, "kyma-system", "no-pods")
+	require.EqualError(t, err, "the service 'no-pods' has no running pod")
+
+	_, _, err = servicePod(k8s, "kyma-system", "missing")
+	require.Error(t, err)
+}
+
+func TestAwaitReady(t *testing.T) {
+	t.Parallel()
+	var readyPods []string
+	p := &PortForward{Ready: func(pod string) { readyPods = append(readyPods, pod) }}
+
+	ready := make(chan struct{})
+	close(ready)
+	p.awaitReady("console-1", ready, make(chan struct{}), make(chan struct{}))
+	require.Equal(t, []string{"console-1"}, readyPods)
+
+	// a failed attempt or a stopped forward ends the wait without calling Ready
+	done := make(chan struct{})
+	close(done)
+	p.awaitReady("console-2", make(chan struct{}), make(chan struct{}), done)
+	stop := make(chan struct{})
+	close(stop)
+	p.awaitReady("console-3", make(chan struct{}), stop, make(chan struct{}))
+	require.Equal(t, []string{"console-1"}, readyPods)
+}
@@ -0,0 +1,86 @@
+package kube
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"time"
+
+	apiErrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+)
+
+// reachableTimeout is the maximum time the API server may take to answer the reachability probe
+const reachableTimeout = 5 * time.Second
+
+// UnreachableError indicates that the API server of the current context does not answer, usually because the cluster was deleted or stopped
+type UnreachableError struct {
+	Context string
+	Server  string
+	Err     error
+}
+
+func (e *UnreachableError) Error() string {
+	return fmt.Sprintf("The cluster of the kubeconfig context '%s' at %s is not reachable: %s\n%s", e.Context, e.Server, e.Err, e.hint())
+}
+
+func (e *UnreachableError) Unwrap() error {
+	return e.Err
+}
+
+func (e *UnreachableError) hint() string {
+	if strings.Contains(e.Context, "minikube") || isLocalServer(e.Server) {
+		return "Is minikube running? Try: minikube start"
+	}
+	return "Make sure that the cluster is running, or select another context with: kubectl config use-context <context>"
+}
+
+// CheckReachable probes the API server of the client with a short timeout, so that a stale kubeconfig is reported
+// before the commands fail with connection errors in later steps
+func CheckReachable(k KymaKube) error {
+	context := ContextOverride()
+	if context == "" && k.KubeConfig() != nil {
+		context = k.KubeConfig().CurrentContext
+	}
+	return checkReachable(k.RestConfig(), context)
+}
+
+func checkReachable(config *rest.Config, context string) error {
+	probeConfig := rest.CopyConfig(config)
+	probeConfig.Timeout = reachableTimeout
+	client, err := kubernetes.NewForConfig(probeConfig)
+	if err != nil {
+		return err
+	}
+	_, err = client.Discovery().ServerVersion()
+	// any answer of the server (e.g. Unauthorized) proves that it is reachable, the actual problem is reported by the later steps
+	if _, answered := err.(apiErrors.APIStatus); err == nil || answered {
+		return nil
+	}
+	return &UnreachableError{Context: context, Server: config.Host, Err: err}
+}
+
+// isLocalServer checks if the API server runs on the local machine or in a local VM, such as Minikube
+func isLocalServer(server string) bool {
+	host := server
+	if u, err := url.Parse(server); err == nil && u.Host != "" {
+		host = u.Hostname()
+	}
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && (ip.IsLoopback() || isPrivateIP(ip))
+}
+
+func isPrivateIP(ip net.IP) bool {
+	for _, cidr := range []string{"10.0.0.0/8", "172.16.0.0/12", "192.168.0.0/16"} {
+		_, network, _ := net.ParseCIDR(cidr)
+		if network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
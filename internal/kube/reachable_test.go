@@ -0,0 +1,45 @@
+package kube
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"k8s.io/client-go/rest"
+)
+
+func TestCheckReachable(t *testing.T) {
+	t.Parallel()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"major": "1", "minor": "19", "gitVersion": "v1.19.7"}`))
+	}))
+	require.NoError(t, checkReachable(&rest.Config{Host: server.URL}, "test"))
+
+	// the server answers with an error
+	denied := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer denied.Close()
+	require.NoError(t, checkReachable(&rest.Config{Host: denied.URL}, "test"))
+
+	// the cluster no longer exists
+	server.Close()
+	err := checkReachable(&rest.Config{Host: server.URL}, "minikube")
+	require.Error(t, err)
+	var unreachable *UnreachableError
+	require.True(t, errors.As(err, &unreachable))
+	require.Contains(t, err.Error(), "'minikube'")
+	require.Contains(t, err.Error(), server.URL)
+	require.Contains(t, err.Error(), "minikube start")
+}
+
+func TestUnreachableErrorHint(t *testing.T) {
+	t.Parallel()
+	local := &UnreachableError{Context: "dev", Server: "https://192.168.64.2:8443"}
+	require.Contains(t, local.hint(), "minikube start")
+	remote := &UnreachableError{Context: "gke_project_cluster", Server: "https://35.1.2.3"}
+	require.Contains(t, remote.hint(), "kubectl config use-context")
+}
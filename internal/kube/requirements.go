@@ -0,0 +1,74 @@
+package kube
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/blang/semver/v4"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// Requirements describes the minimal cluster a Kyma installation needs.
+type Requirements struct {
+	// KubernetesVersion is the minimal Kubernetes version of the API server.
+	KubernetesVersion string
+	// CPU is the minimal sum of allocatable CPU of all nodes.
+	CPU resource.Quantity
+	// Memory is the minimal sum of allocatable memory of all nodes.
+	Memory resource.Quantity
+}
+
+// KymaRequirements are the requirements of a Kyma evaluation installation, as used for local clusters.
+var KymaRequirements = Requirements{
+	KubernetesVersion: "1.16.0",
+	CPU:               resource.MustParse("4"),
+	Memory:            resource.MustParse("8Gi"),
+}
+
+// CheckRequirements verifies that the cluster meets the given requirements and returns an error listing all unmet requirements.
+func CheckRequirements(client kubernetes.Interface, r Requirements) error {
+	var problems []string
+
+	info, err := client.Discovery().ServerVersion()
+	if err != nil {
+		return fmt.Errorf("unable to get the Kubernetes version of the cluster: %s", err)
+	}
+	minVersion, err := semver.ParseTolerant(r.KubernetesVersion)
+	if err != nil {
+		return fmt.Errorf("invalid minimal Kubernetes version '%s': %s", r.KubernetesVersion, err)
+	}
+	version, err := semver.ParseTolerant(info.GitVersion)
+	if err != nil {
+		return fmt.Errorf("unable to parse the Kubernetes version '%s' of the cluster: %s", info.GitVersion, err)
+	}
+	// provider specific suffixes (e.g. v1.19.7-gke.1) are no pre-releases of the version
+	version.Pre = nil
+	if version.LT(minVersion) {
+		problems = append(problems, fmt.Sprintf("Kubernetes version %s is lower than the required %s", info.GitVersion, r.KubernetesVersion))
+	}
+
+	nodes, err := client.CoreV1().Nodes().List(context.Background(), metav1.ListOptions{})
+	if err != nil {
+		return fmt.Errorf("unable to list the nodes of the cluster: %s", err)
+	}
+	cpu := resource.Quantity{}
+	memory := resource.Quantity{}
+	for _, n := range nodes.Items {
+		cpu.Add(*n.Status.Allocatable.Cpu())
+		memory.Add(*n.Status.Allocatable.Memory())
+	}
+	if cpu.Cmp(r.CPU) < 0 {
+		problems = append(problems, fmt.Sprintf("allocatable CPU of all nodes is %s, but at least %s is required", cpu.String(), r.CPU.String()))
+	}
+	if memory.Cmp(r.Memory) < 0 {
+		problems = append(problems, fmt.Sprintf("allocatable memory of all nodes is %s, but at least %s is required", memory.String(), r.Memory.String()))
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("the cluster does not meet the Kyma requirements:\n- %s", strings.Join(problems, "\n- "))
+	}
+	return nil
+}
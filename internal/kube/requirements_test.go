@@ -0,0 +1,44 @@
+package kube
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/version"
+	fakeDiscovery "k8s.io/client-go/discovery/fake"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestCheckRequirements(t *testing.T) {
+	t.Parallel()
+	node := func(name, cpu, memory string) *corev1.Node {
+		return &corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Status: corev1.NodeStatus{Allocatable: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse(cpu),
+				corev1.ResourceMemory: resource.MustParse(memory),
+			}},
+		}
+	}
+	client := func(gitVersion string, nodes ...*corev1.Node) *fake.Clientset {
+		c := fake.NewSimpleClientset()
+		for _, n := range nodes {
+			require.NoError(t, c.Tracker().Add(n))
+		}
+		c.Discovery().(*fakeDiscovery.FakeDiscovery).FakedServerVersion = &version.Info{GitVersion: gitVersion}
+		return c
+	}
+
+	// resources of all nodes add up
+	err := CheckRequirements(client("v1.19.7-gke.1", node("n1", "2", "4Gi"), node("n2", "2", "4Gi")), KymaRequirements)
+	require.NoError(t, err)
+
+	err = CheckRequirements(client("v1.15.3", node("n1", "3900m", "4Gi")), KymaRequirements)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "Kubernetes version v1.15.3")
+	require.Contains(t, err.Error(), "allocatable CPU")
+	require.Contains(t, err.Error(), "allocatable memory")
+}
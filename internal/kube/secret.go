@@ -0,0 +1,55 @@
+package kube
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/kyma-project/cli/internal/logger"
+	"github.com/kyma-project/cli/internal/nice"
+	"github.com/kyma-project/cli/internal/trace"
+	"github.com/pkg/errors"
+	"k8s.io/client-go/kubernetes"
+)
+
+// kubectlTimeout is the time given to kubectl to read a resource
+const kubectlTimeout = 30 * time.Second
+
+var (
+	// ErrSecretNotFound is returned by GetSecretValue if the Secret does not exist
+	ErrSecretNotFound = ErrResourceNotFound
+	// ErrSecretKeyEmpty is returned by GetSecretValue if the Secret has no value for the key, for example because it is not populated yet
+	ErrSecretKeyEmpty = ErrFieldNotSet
+)
+
+// runKubectl executes kubectl with the given arguments and returns its standard output, it is replaced in tests
+var runKubectl = func(args ...string) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), kubectlTimeout)
+	defer cancel()
+	started := time.Now()
+	var stderr strings.Builder
+	cmd := exec.CommandContext(ctx, "kubectl", args...)
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		err = fmt.Errorf("executing 'kubectl %s' failed: %s", strings.Join(args, " "), strings.TrimSpace(nice.StripANSI(stderr.String())))
+	}
+	logger.Command("kubectl", args, started, err)
+	trace.Record("kubectl", started, err, trace.Attributes{"args": args})
+	return []byte(nice.StripANSI(string(out))), err
+}
+
+// GetSecretValue returns the decoded value of the key of a Secret. The Secret is read with the client, or with kubectl if no client is given,
+// e.g. where the kubeconfig is only known to kubectl. ErrSecretNotFound and ErrSecretKeyEmpty can be checked with errors.Is.
+func GetSecretValue(k8s kubernetes.Interface, namespace, name, key string) ([]byte, error) {
+	value, err := GetResourceField(k8s, "secret", name, namespace, fmt.Sprintf("{.data.%s}", JSONPathKey(key)), true)
+	if errors.Is(err, ErrFieldNotSet) {
+		return nil, errors.Wrapf(ErrSecretKeyEmpty, "the key '%s' of the Secret '%s/%s'", key, namespace, name)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return []byte(value), nil
+}
@@ -0,0 +1,79 @@
+package kube
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+// binaryValue is not valid UTF-8, so it must be returned unchanged
+var binaryValue = []byte{0x30, 0x82, 0x01, 0x0a, 0x00, 0xff, 0xfe, '\'', '\n'}
+
+func TestGetSecretValue(t *testing.T) {
+	t.Parallel()
+	k8s := fake.NewSimpleClientset(&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "admin-user", Namespace: "kyma-system"},
+		Data:       map[string][]byte{"password": []byte("s3cr3t"), "cert": binaryValue, "email": {}},
+	})
+
+	value, err := GetSecretValue(k8s, "kyma-system", "admin-user", "password")
+	require.NoError(t, err)
+	require.Equal(t, "s3cr3t", string(value))
+
+	value, err = GetSecretValue(k8s, "kyma-system", "admin-user", "cert")
+	require.NoError(t, err)
+	require.Equal(t, binaryValue, value)
+
+	for _, key := range []string{"email", "missing"} {
+		_, err = GetSecretValue(k8s, "kyma-system", "admin-user", key)
+		require.True(t, errors.Is(err, ErrSecretKeyEmpty), key)
+		require.EqualError(t, err, fmt.Sprintf("the key '%s' of the Secret 'kyma-system/admin-user': not set", key))
+	}
+
+	_, err = GetSecretValue(k8s, "kyma-system", "other", "password")
+	require.True(t, errors.Is(err, ErrSecretNotFound))
+	require.EqualError(t, err, "the Secret 'kyma-system/other': not found")
+}
+
+func TestGetSecretValueWithKubectl(t *testing.T) {
+	// runKubectl is replaced, so the test must not run in parallel with other kubectl users
+	defer func(run func(args ...string) ([]byte, error)) { runKubectl = run }(runKubectl)
+	var args []string
+	runKubectl = func(a ...string) ([]byte, error) {
+		args = a
+		switch a[2] {
+		case "admin-user":
+			// "MIIBCgD//icK" is the base64 encoding of binaryValue
+			return []byte(`{"apiVersion":"v1","kind":"Secret","metadata":{"name":"admin-user","namespace":"kyma-system"},"data":{"cert":"MIIBCgD//icK","password":"czNjcjN0"}}`), nil
+		case "forbidden":
+			return nil, errors.New(`executing 'kubectl get secret forbidden' failed: Error from server (Forbidden): secrets "forbidden" is forbidden`)
+		default:
+			return nil, errors.New(`executing 'kubectl get secret' failed: Error from server (NotFound): secrets "other" not found`)
+		}
+	}
+
+	value, err := GetSecretValue(nil, "kyma-system", "admin-user", "password")
+	require.NoError(t, err)
+	require.Equal(t, "s3cr3t", string(value))
+	require.Equal(t, []string{"get", "secret", "admin-user", "--namespace", "kyma-system", "--output", "json"}, args)
+
+	value, err = GetSecretValue(nil, "kyma-system", "admin-user", "cert")
+	require.NoError(t, err)
+	require.Equal(t, binaryValue, value)
+
+	_, err = GetSecretValue(nil, "kyma-system", "admin-user", "email")
+	require.True(t, errors.Is(err, ErrSecretKeyEmpty))
+
+	_, err = GetSecretValue(nil, "kyma-system", "other", "password")
+	require.True(t, errors.Is(err, ErrSecretNotFound))
+
+	_, err = GetSecretValue(nil, "kyma-system", "forbidden", "password")
+	require.Error(t, err)
+	require.False(t, errors.Is(err, ErrSecretNotFound))
+	require.Contains(t, err.Error(), "unable to read the Secret 'kyma-system/forbidden'")
+}
@@ -0,0 +1,38 @@
+package kube
+
+import (
+	"context"
+	"strings"
+
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// FindPodSelector returns the first of the label selectors in the format "key=value" which selects pods in the namespace, and true.
+// Labels changed between releases of the same workload, so the selectors are tried in the given order.
+// If no selector matches a pod, the first one is returned with false, as it is the one of newly created pods.
+func FindPodSelector(k8s kubernetes.Interface, namespace string, selectors []string) (string, bool, error) {
+	if len(selectors) == 0 {
+		return "", false, errors.New("no label selector given")
+	}
+	for _, s := range selectors {
+		pods, err := k8s.CoreV1().Pods(namespace).List(context.Background(), metav1.ListOptions{LabelSelector: s, Limit: 1})
+		if err != nil {
+			return "", false, errors.Wrapf(err, "unable to list the pods in the namespace '%s'", namespace)
+		}
+		if len(pods.Items) > 0 {
+			return s, true, nil
+		}
+	}
+	return selectors[0], false, nil
+}
+
+// SplitSelector splits a label selector in the format "key=value" into the label name and value, e.g. for IsPodReadyByLabel
+func SplitSelector(selector string) (string, string) {
+	parts := strings.SplitN(selector, "=", 2)
+	if len(parts) < 2 {
+		return parts[0], ""
+	}
+	return parts[0], parts[1]
+}
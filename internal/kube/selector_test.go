@@ -0,0 +1,41 @@
+package kube
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestFindPodSelector(t *testing.T) {
+	t.Parallel()
+	selectors := []string{"name=kyma-installer", "app=kyma-installer"}
+	k8s := fake.NewSimpleClientset(&corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "kyma-installer-6c8b9", Namespace: "kyma-installer", Labels: map[string]string{"app": "kyma-installer"}},
+	})
+
+	selector, found, err := FindPodSelector(k8s, "kyma-installer", selectors)
+	require.NoError(t, err)
+	require.True(t, found)
+	require.Equal(t, "app=kyma-installer", selector, "the historical selector matches")
+
+	selector, found, err = FindPodSelector(k8s, "other", selectors)
+	require.NoError(t, err)
+	require.False(t, found)
+	require.Equal(t, "name=kyma-installer", selector, "the first selector is returned if none matches")
+
+	_, _, err = FindPodSelector(k8s, "kyma-installer", nil)
+	require.Error(t, err)
+}
+
+func TestSplitSelector(t *testing.T) {
+	t.Parallel()
+	name, value := SplitSelector("app.kubernetes.io/name=kyma-installer")
+	require.Equal(t, "app.kubernetes.io/name", name)
+	require.Equal(t, "kyma-installer", value)
+	name, value = SplitSelector("name")
+	require.Equal(t, "name", name)
+	require.Equal(t, "", value)
+}
@@ -0,0 +1,140 @@
+package kube
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/kubernetes"
+)
+
+// ErrWaitTimeout is returned if the pods are not ready within the timeout
+var ErrWaitTimeout = errors.New("timeout reached while waiting for the pods")
+
+// failingContainerReasons are the reasons of waiting containers which will not start without a change of the pod
+var failingContainerReasons = []string{"CrashLoopBackOff", "ErrImagePull", "ImagePullBackOff", "InvalidImageName", "CreateContainerConfigError", "CreateContainerError"}
+
+// PodFailedError is returned if a pod failed or will not become ready without a change
+type PodFailedError struct {
+	Pod    string
+	Reason string
+}
+
+func (e *PodFailedError) Error() string {
+	return fmt.Sprintf("the pod '%s' failed: %s", e.Pod, e.Reason)
+}
+
+// WaitForPods waits until at least one pod matches the label selector and all matching pods are ready or completed.
+// It returns a PodFailedError as soon as a pod fails, and ErrWaitTimeout if the pods are not ready within the timeout (0 means no timeout).
+// The pods are checked whenever a watch reports a change, and with an increasing interval in case a change is missed or the watch fails.
+func WaitForPods(k8s kubernetes.Interface, namespace, selector string, timeout time.Duration) error {
+	return waitForPods(k8s, namespace, selector, timeout, defaultBackoff())
+}
+
+func waitForPods(k8s kubernetes.Interface, namespace, selector string, timeout time.Duration, b *backoff) error {
+	var deadline time.Time
+	if timeout > 0 {
+		deadline = b.clock.Now().Add(timeout)
+	}
+	var changes watch.Interface
+	defer func() {
+		if changes != nil {
+			changes.Stop()
+		}
+	}()
+
+	for {
+		pods, err := k8s.CoreV1().Pods(namespace).List(context.Background(), metav1.ListOptions{LabelSelector: selector})
+		if err != nil {
+			return err
+		}
+
+		var pending []string
+		for n := range pods.Items {
+			pod := &pods.Items[n]
+			if reason := podFailure(pod); reason != "" {
+				return &PodFailedError{Pod: pod.Name, Reason: reason}
+			}
+			if !podReady(pod) {
+				pending = append(pending, pod.Name)
+			}
+		}
+		if len(pods.Items) > 0 && len(pending) == 0 {
+			return nil
+		}
+
+		if !deadline.IsZero() && !b.clock.Now().Before(deadline) {
+			if len(pods.Items) == 0 {
+				return fmt.Errorf("%w: no pod matches '%s' in the namespace '%s'", ErrWaitTimeout, selector, namespace)
+			}
+			sort.Strings(pending)
+			return fmt.Errorf("%w: not ready: %s", ErrWaitTimeout, strings.Join(pending, ", "))
+		}
+
+		// the watch starts at the listed version, so no change is missed; without it the pods are polled
+		if changes == nil {
+			if changes, err = k8s.CoreV1().Pods(namespace).Watch(context.Background(), metav1.ListOptions{LabelSelector: selector, ResourceVersion: pods.ResourceVersion}); err != nil {
+				changes = nil
+			}
+		}
+		if changes == nil {
+			<-b.after(deadline)
+			continue
+		}
+		select {
+		case event, ok := <-changes.ResultChan():
+			if !ok || event.Type == watch.Error {
+				// the API server ends watches after a while, the watch is opened again after the interval
+				changes.Stop()
+				changes = nil
+				<-b.after(deadline)
+				continue
+			}
+			b.reset()
+		case <-b.after(deadline):
+		}
+	}
+}
+
+// podReady checks if the pod is ready, or completed successfully like the pod of a job
+func podReady(pod *corev1.Pod) bool {
+	if pod.Status.Phase == corev1.PodSucceeded {
+		return true
+	}
+	if pod.Status.Phase != corev1.PodRunning {
+		return false
+	}
+	for _, c := range pod.Status.Conditions {
+		if c.Type == corev1.PodReady {
+			return c.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
+
+// podFailure returns why the pod failed, or an empty string if it can still become ready
+func podFailure(pod *corev1.Pod) string {
+	if pod.Status.Phase == corev1.PodFailed {
+		if pod.Status.Reason != "" {
+			return fmt.Sprintf("%s %s", pod.Status.Reason, pod.Status.Message)
+		}
+		return "the pod is in the phase Failed"
+	}
+	statuses := append(append([]corev1.ContainerStatus{}, pod.Status.InitContainerStatuses...), pod.Status.ContainerStatuses...)
+	for _, s := range statuses {
+		if w := s.State.Waiting; w != nil {
+			for _, r := range failingContainerReasons {
+				if w.Reason == r {
+					return strings.TrimSpace(fmt.Sprintf("container '%s' is in %s %s", s.Name, w.Reason, w.Message))
+				}
+			}
+		}
+	}
+	return ""
+}
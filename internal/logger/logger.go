@@ -0,0 +1,162 @@
+package logger
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kyma-project/cli/internal/redact"
+)
+
+// Level is the severity of a log entry
+type Level string
+
+const (
+	// LevelInfo marks entries about the regular progress of the CLI
+	LevelInfo Level = "info"
+	// LevelError marks entries about failed actions
+	LevelError Level = "error"
+)
+
+// Fields holds additional details of a log entry, such as the arguments or the duration of a command
+type Fields map[string]interface{}
+
+// Entry is a structured log entry of the CLI
+type Entry struct {
+	Time  time.Time
+	Level Level
+	// Component is the part of the CLI which logged the entry (e.g. kubectl, docker or step)
+	Component string
+	Message   string
+	Fields    Fields
+}
+
+// Logger records the log entries of the CLI
+type Logger interface {
+	Log(e Entry)
+}
+
+// discard drops all entries
+type discard struct{}
+
+func (discard) Log(Entry) {}
+
+// Discard is the logger used if structured logs are not enabled
+var Discard Logger = discard{}
+
+var (
+	current Logger = Discard
+	lock    sync.RWMutex
+)
+
+// Set replaces the logger used by the CLI
+func Set(l Logger) {
+	lock.Lock()
+	defer lock.Unlock()
+	current = l
+}
+
+// Enabled checks if the entries are recorded, so that expensive details are only collected when needed
+func Enabled() bool {
+	lock.RLock()
+	defer lock.RUnlock()
+	return current != Discard
+}
+
+// Info logs an entry about the regular progress of the CLI
+func Info(component, msg string, fields Fields) {
+	log(LevelInfo, component, msg, fields)
+}
+
+// Error logs an entry about a failed action
+func Error(component, msg string, fields Fields) {
+	log(LevelError, component, msg, fields)
+}
+
+// Command logs the result of a command executed by the CLI, with its arguments and duration
+func Command(component string, args []string, started time.Time, err error) {
+	fields := Fields{"args": args, "duration": time.Since(started)}
+	if err != nil {
+		fields["error"] = err
+		Error(component, "command failed", fields)
+		return
+	}
+	Info(component, "command finished", fields)
+}
+
+func log(level Level, component, msg string, fields Fields) {
+	lock.RLock()
+	l := current
+	lock.RUnlock()
+	l.Log(Entry{Time: time.Now(), Level: level, Component: component, Message: msg, Fields: fields})
+}
+
+// writerLogger serializes the entries written to the output
+type writerLogger struct {
+	mu     sync.Mutex
+	out    io.Writer
+	format func(e Entry) []byte
+}
+
+func (w *writerLogger) Log(e Entry) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	// logging must never fail the CLI, so write errors are ignored. Secrets like the GitHub token might be part of the arguments of a command, so they are masked.
+	_, _ = w.out.Write(redact.Bytes(w.format(e)))
+}
+
+// NewHuman creates a logger writing one readable line per entry, e.g. "2021-03-01T10:00:00Z info [kubectl] command finished args=[get pods]"
+func NewHuman(out io.Writer) Logger {
+	return &writerLogger{out: out, format: formatHuman}
+}
+
+// NewJSON creates a logger writing one JSON object per line with the keys time, level, component, msg and the fields of the entry
+func NewJSON(out io.Writer) Logger {
+	return &writerLogger{out: out, format: formatJSON}
+}
+
+func formatHuman(e Entry) []byte {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s %s [%s] %s", e.Time.Format(time.RFC3339), e.Level, e.Component, e.Message)
+	for _, k := range sortedKeys(e.Fields) {
+		fmt.Fprintf(&b, " %s=%v", k, e.Fields[k])
+	}
+	b.WriteString("\n")
+	return []byte(b.String())
+}
+
+func formatJSON(e Entry) []byte {
+	doc := map[string]interface{}{}
+	for k, v := range e.Fields {
+		if d, ok := v.(time.Duration); ok {
+			// durations are logged in seconds, so that they can be aggregated
+			v = d.Seconds()
+		}
+		if err, ok := v.(error); ok {
+			v = err.Error()
+		}
+		doc[k] = v
+	}
+	doc["time"] = e.Time.Format(time.RFC3339Nano)
+	doc["level"] = e.Level
+	doc["component"] = e.Component
+	doc["msg"] = e.Message
+	data, err := json.Marshal(doc)
+	if err != nil {
+		data, _ = json.Marshal(map[string]interface{}{"time": doc["time"], "level": e.Level, "component": e.Component, "msg": e.Message, "error": err.Error()})
+	}
+	return append(data, '\n')
+}
+
+func sortedKeys(fields Fields) []string {
+	keys := make([]string, 0, len(fields))
+	for k := range fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,72 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/kyma-project/cli/internal/redact"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFormats(t *testing.T) {
+	t.Parallel()
+	e := Entry{
+		Time:      time.Date(2021, 3, 1, 10, 0, 0, 0, time.UTC),
+		Level:     LevelError,
+		Component: "minikube",
+		Message:   "command failed",
+		Fields:    Fields{"args": []string{"status"}, "duration": 1500 * time.Millisecond, "error": errors.New("exit status 1")},
+	}
+
+	require.Equal(t, "2021-03-01T10:00:00Z error [minikube] command failed args=[status] duration=1.5s error=exit status 1\n", string(formatHuman(e)))
+
+	var doc map[string]interface{}
+	require.NoError(t, json.Unmarshal(formatJSON(e), &doc))
+	require.Equal(t, map[string]interface{}{
+		"time":      "2021-03-01T10:00:00Z",
+		"level":     "error",
+		"component": "minikube",
+		"msg":       "command failed",
+		"args":      []interface{}{"status"},
+		"duration":  1.5,
+		"error":     "exit status 1",
+	}, doc)
+}
+
+func TestSet(t *testing.T) {
+	require.False(t, Enabled(), "Structured logs must be disabled by default")
+
+	out := &bytes.Buffer{}
+	Set(NewJSON(out))
+	defer Set(Discard)
+	require.True(t, Enabled())
+
+	Command("k3d", []string{"version"}, time.Now(), nil)
+	Info("step", "Preparations done", nil)
+	lines := bytes.Split(bytes.TrimSpace(out.Bytes()), []byte("\n"))
+	require.Len(t, lines, 2)
+
+	var doc map[string]interface{}
+	require.NoError(t, json.Unmarshal(lines[0], &doc))
+	require.Equal(t, "k3d", doc["component"])
+	require.Equal(t, "info", doc["level"])
+	require.Equal(t, "command finished", doc["msg"])
+	require.Equal(t, []interface{}{"version"}, doc["args"])
+}
+
+func TestSecretsMasked(t *testing.T) {
+	t.Parallel()
+	redact.Add("ghp_l0gg3r")
+	var out bytes.Buffer
+	NewHuman(&out).Log(Entry{
+		Time:      time.Date(2021, 3, 1, 10, 0, 0, 0, time.UTC),
+		Level:     LevelInfo,
+		Component: "kubectl",
+		Message:   "command finished",
+		Fields:    Fields{"args": []string{"--token", "ghp_l0gg3r"}},
+	})
+	require.Equal(t, "2021-03-01T10:00:00Z info [kubectl] command finished args=[--token <redacted>]\n", out.String())
+}
@@ -0,0 +1,230 @@
+// Package metadata holds the names of the namespaces, the label selectors and the resources the CLI uses to find the parts of a Kyma installation.
+// They changed between Kyma releases, so they are kept in a table by release range instead of literals, and the table can be extended with a file.
+package metadata
+
+import (
+	"fmt"
+	"io/ioutil"
+
+	"github.com/blang/semver/v4"
+	pkgErrors "github.com/pkg/errors"
+	"gopkg.in/yaml.v2"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// Metadata describes where the CLI finds the parts of a Kyma installation of the releases in its version range
+type Metadata struct {
+	// Versions is the semantic version range of the Kyma releases the entry applies to (e.g. "<1.16.0"). An entry without a range applies to all releases.
+	Versions string `yaml:"versions,omitempty"`
+	// InstallerNamespace is the namespace of the Kyma Installer and its overrides
+	InstallerNamespace string `yaml:"installerNamespace,omitempty"`
+	// InstallerSelectors are the label selectors of the Kyma Installer pods in the format "key=value", tried in the given order
+	InstallerSelectors []string `yaml:"installerSelectors,omitempty"`
+	// TillerNamespace is the namespace of Tiller and its release ConfigMaps, it is empty for the releases installed with Helm 3
+	TillerNamespace string `yaml:"tillerNamespace,omitempty"`
+	// InstallationNamespace is the namespace of the Installation CR
+	InstallationNamespace string `yaml:"installationNamespace,omitempty"`
+	// InstallationGroup, InstallationVersion, InstallationResource and InstallationKind identify the Installation CRD
+	InstallationGroup    string `yaml:"installationGroup,omitempty"`
+	InstallationVersion  string `yaml:"installationVersion,omitempty"`
+	InstallationResource string `yaml:"installationResource,omitempty"`
+	InstallationKind     string `yaml:"installationKind,omitempty"`
+	// AdminSecret is the name of the Secret holding the credentials of the admin user, in the namespace AdminSecretNamespace
+	AdminSecret          string `yaml:"adminSecret,omitempty"`
+	AdminSecretNamespace string `yaml:"adminSecretNamespace,omitempty"`
+	// CertificateSecret is the name of the Secret holding the PEM encoded certificate of the Kyma domain in the key CertificateSecretKey, in the namespace CertificateSecretNamespace
+	CertificateSecret          string `yaml:"certificateSecret,omitempty"`
+	CertificateSecretNamespace string `yaml:"certificateSecretNamespace,omitempty"`
+	CertificateSecretKey       string `yaml:"certificateSecretKey,omitempty"`
+	// DexStaticUsersOverride is the override of the Dex chart holding the static users added next to the admin user, it is empty if the chart has none
+	DexStaticUsersOverride string `yaml:"dexStaticUsersOverride,omitempty"`
+}
+
+// InstallationGVR returns the resource of the Installation CRs
+func (m Metadata) InstallationGVR() schema.GroupVersionResource {
+	return schema.GroupVersionResource{Group: m.InstallationGroup, Version: m.InstallationVersion, Resource: m.InstallationResource}
+}
+
+// defaultTable is the metadata of the Kyma releases, the entries are matched in the given order.
+// The Kyma Installer pods were labeled with app instead of name in some releases, so both selectors are tried.
+const defaultTable = `
+# Tiller was removed in Kyma 1.16, the components are installed with Helm 3 since then
+- versions: "<1.16.0"
+  installerNamespace: kyma-installer
+  installerSelectors:
+  - name=kyma-installer
+  - app=kyma-installer
+  tillerNamespace: kube-system
+  installationNamespace: default
+  installationGroup: installer.kyma-project.io
+  installationVersion: v1alpha1
+  installationResource: installations
+  installationKind: Installation
+  adminSecret: admin-user
+  adminSecretNamespace: kyma-system
+  certificateSecret: ingress-tls-cert
+  certificateSecretNamespace: kyma-system
+  certificateSecretKey: tls.crt
+  dexStaticUsersOverride: dex.staticPasswords
+- installerNamespace: kyma-installer
+  installerSelectors:
+  - name=kyma-installer
+  - app=kyma-installer
+  - app.kubernetes.io/name=kyma-installer
+  installationNamespace: default
+  installationGroup: installer.kyma-project.io
+  installationVersion: v1alpha1
+  installationResource: installations
+  installationKind: Installation
+  adminSecret: admin-user
+  adminSecretNamespace: kyma-system
+  certificateSecret: ingress-tls-cert
+  certificateSecretNamespace: kyma-system
+  certificateSecretKey: tls.crt
+  dexStaticUsersOverride: dex.staticPasswords
+`
+
+var (
+	embedded = mustParse(defaultTable)
+	// overrides holds the entries loaded from a file, they take precedence over the embedded ones
+	overrides []Metadata
+)
+
+func mustParse(table string) []Metadata {
+	entries, err := parse([]byte(table))
+	if err != nil {
+		panic(err)
+	}
+	return entries
+}
+
+func parse(data []byte) ([]Metadata, error) {
+	var entries []Metadata
+	if err := yaml.UnmarshalStrict(data, &entries); err != nil {
+		return nil, err
+	}
+	for _, e := range entries {
+		if e.Versions == "" {
+			continue
+		}
+		if _, err := semver.ParseRange(e.Versions); err != nil {
+			return nil, fmt.Errorf("invalid version range '%s': %s", e.Versions, err)
+		}
+	}
+	return entries, nil
+}
+
+// Load reads entries from a YAML file in the format of the embedded table. They take precedence over the embedded entries,
+// and the fields they do not set are taken from the embedded entry of the same release.
+func Load(path string) error {
+	entries, err := Read(path)
+	if err != nil {
+		return err
+	}
+	overrides = entries
+	return nil
+}
+
+// Read parses a YAML file in the format of the embedded table without applying it
+func Read(path string) ([]Metadata, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, pkgErrors.Wrap(err, "unable to read the metadata file")
+	}
+	entries, err := parse(data)
+	if err != nil {
+		return nil, pkgErrors.Wrapf(err, "unable to parse the metadata file '%s'", path)
+	}
+	return entries, nil
+}
+
+// Embedded returns the entries of the table built into the CLI, without the entries loaded from a file
+func Embedded() []Metadata {
+	entries := make([]Metadata, len(embedded))
+	for i, e := range embedded {
+		e.InstallerSelectors = append([]string(nil), e.InstallerSelectors...)
+		entries[i] = e
+	}
+	return entries
+}
+
+// For returns the metadata of the given Kyma version. Versions which are no release, such as "master-34edf09a" or an empty version, get the metadata of the newest releases.
+func For(version string) Metadata {
+	m := match(embedded, version)
+	if o, ok := find(overrides, version); ok {
+		m = merge(o, m)
+	}
+	return m
+}
+
+// Latest returns the metadata of the newest Kyma releases, it is used if the version is not known yet
+func Latest() Metadata {
+	return For("")
+}
+
+// TillerNamespace returns the Tiller namespace of the newest releases installed with Helm 2.
+// Components are migrated from Helm 2 to Helm 3 on upgrades, so the Helm 2 releases are looked up independent of the installed version.
+func TillerNamespace() string {
+	for _, entries := range [][]Metadata{overrides, embedded} {
+		for i := len(entries) - 1; i >= 0; i-- {
+			if entries[i].TillerNamespace != "" {
+				return entries[i].TillerNamespace
+			}
+		}
+	}
+	return ""
+}
+
+// match returns the entry of the version, or the last entry if no other one matches
+func match(entries []Metadata, version string) Metadata {
+	if m, ok := find(entries, version); ok {
+		return m
+	}
+	return entries[len(entries)-1]
+}
+
+func find(entries []Metadata, version string) (Metadata, bool) {
+	v, err := semver.ParseTolerant(version)
+	for _, e := range entries {
+		if e.Versions == "" {
+			return e, true
+		}
+		if err != nil {
+			continue
+		}
+		// the range was validated when the table was parsed
+		if r, _ := semver.ParseRange(e.Versions); r(v) {
+			return e, true
+		}
+	}
+	return Metadata{}, false
+}
+
+// merge fills the fields which are not set in the entry from the fallback
+func merge(entry, fallback Metadata) Metadata {
+	str := func(v *string, f string) {
+		if *v == "" {
+			*v = f
+		}
+	}
+	list := func(v *[]string, f []string) {
+		if len(*v) == 0 {
+			*v = f
+		}
+	}
+	str(&entry.InstallerNamespace, fallback.InstallerNamespace)
+	list(&entry.InstallerSelectors, fallback.InstallerSelectors)
+	str(&entry.TillerNamespace, fallback.TillerNamespace)
+	str(&entry.InstallationNamespace, fallback.InstallationNamespace)
+	str(&entry.InstallationGroup, fallback.InstallationGroup)
+	str(&entry.InstallationVersion, fallback.InstallationVersion)
+	str(&entry.InstallationResource, fallback.InstallationResource)
+	str(&entry.InstallationKind, fallback.InstallationKind)
+	str(&entry.AdminSecret, fallback.AdminSecret)
+	str(&entry.AdminSecretNamespace, fallback.AdminSecretNamespace)
+	str(&entry.CertificateSecret, fallback.CertificateSecret)
+	str(&entry.CertificateSecretNamespace, fallback.CertificateSecretNamespace)
+	str(&entry.CertificateSecretKey, fallback.CertificateSecretKey)
+	str(&entry.DexStaticUsersOverride, fallback.DexStaticUsersOverride)
+	return entry
+}
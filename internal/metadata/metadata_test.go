@@ -0,0 +1,70 @@
+package metadata
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFor(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		version         string
+		tillerNamespace string
+	}{
+		{version: "1.15.1", tillerNamespace: "kube-system"},
+		{version: "1.16.0", tillerNamespace: ""},
+		{version: "2.0.0-rc1", tillerNamespace: ""},
+		{version: "master-34edf09a", tillerNamespace: ""},
+		{version: "", tillerNamespace: ""},
+	}
+	for _, c := range cases {
+		m := For(c.version)
+		require.Equal(t, c.tillerNamespace, m.TillerNamespace, "Tiller namespace of version '%s'", c.version)
+		require.Equal(t, "kyma-installer", m.InstallerNamespace)
+		require.Equal(t, "name=kyma-installer", m.InstallerSelectors[0], "the first selector is the one of new pods")
+		require.Equal(t, "installations", m.InstallationGVR().Resource)
+		require.Equal(t, "ingress-tls-cert", m.CertificateSecret)
+	}
+	require.Equal(t, For(""), Latest())
+	require.Equal(t, embedded, Embedded())
+	require.Equal(t, "kube-system", TillerNamespace())
+}
+
+func TestLoad(t *testing.T) {
+	// not parallel: the loaded entries are package level
+	defer func() { overrides = nil }()
+	dir, err := ioutil.TempDir("", "metadata")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	file := filepath.Join(dir, "metadata.yaml")
+	require.NoError(t, ioutil.WriteFile(file, []byte(`
+- versions: ">=1.18.0"
+  installerNamespace: custom-installer
+  installerSelectors:
+  - app=custom-installer
+`), 0600))
+	entries, err := Read(file)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	require.Nil(t, overrides, "reading a file does not apply it")
+	require.NoError(t, Load(file))
+
+	m := For("1.18.1")
+	require.Equal(t, "custom-installer", m.InstallerNamespace)
+	require.Equal(t, []string{"app=custom-installer"}, m.InstallerSelectors)
+	require.Equal(t, "default", m.InstallationNamespace, "fields which are not set are taken from the embedded table")
+	require.Equal(t, "tls.crt", m.CertificateSecretKey, "fields which are not set are taken from the embedded table")
+	require.Equal(t, "dex.staticPasswords", m.DexStaticUsersOverride, "fields which are not set are taken from the embedded table")
+	require.Equal(t, "kyma-installer", For("1.17.0").InstallerNamespace, "the entry does not apply to other versions")
+
+	require.NoError(t, ioutil.WriteFile(file, []byte(`- versions: "not a range"`), 0600))
+	require.Error(t, Load(file))
+	require.NoError(t, ioutil.WriteFile(file, []byte(`- installerNamespaces: typo`), 0600))
+	require.Error(t, Load(file), "unknown fields are rejected")
+	require.Error(t, Load(filepath.Join(dir, "missing.yaml")))
+}
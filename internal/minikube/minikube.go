@@ -6,11 +6,15 @@ import (
 	"os"
 	"os/exec"
 	"regexp"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/blang/semver/v4"
 	docker "github.com/docker/docker/client"
+	"github.com/kyma-project/cli/internal/logger"
+	"github.com/kyma-project/cli/internal/nice"
+	"github.com/kyma-project/cli/internal/trace"
 )
 
 const (
@@ -19,7 +23,17 @@ const (
 
 //RunCmd executes a minikube command with given arguments
 func RunCmd(verbose bool, profile string, timeout time.Duration, rawArgs ...string) (string, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	out, err := runCmd(verbose, profile, timeout, rawArgs...)
+	return strings.Replace(out, "'", "", -1), err
+}
+
+// runCmd executes a minikube command and returns its output without removing the quotes, e.g. of paths containing apostrophes
+func runCmd(verbose bool, profile string, timeout time.Duration, rawArgs ...string) (string, error) {
+	// a timeout of 0 runs the command without a timeout, e.g. with kyma install --timeout=0
+	ctx, cancel := context.Background(), context.CancelFunc(func() {})
+	if timeout > 0 {
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+	}
 	defer cancel()
 
 	args := []string{}
@@ -31,23 +45,26 @@ func RunCmd(verbose bool, profile string, timeout time.Duration, rawArgs ...stri
 
 	cmd := exec.CommandContext(ctx, "minikube", args...)
 
+	started := time.Now()
 	out, err := cmd.CombinedOutput()
-	unquotedOut := strings.Replace(string(out), "'", "", -1)
+	strippedOut := nice.StripANSI(string(out))
+	logger.Command("minikube", args, started, err)
+	trace.Record("minikube", started, err, trace.Attributes{"args": args})
 
 	if ctx.Err() == context.DeadlineExceeded {
-		return unquotedOut, fmt.Errorf("Executing 'minikube %s' command with output '%s' timed out, try running the command manually or increasing timeout using the 'timeout' flag", strings.Join(args, " "), out)
+		return strippedOut, fmt.Errorf("Executing 'minikube %s' command with output '%s' timed out, try running the command manually or increasing timeout using the 'timeout' flag", strings.Join(args, " "), out)
 	}
 
 	if err != nil {
 		if verbose {
 			fmt.Printf("\nExecuted command:\n  minikube %s\nwith output:\n  %s\nand error:\n  %s\n", strings.Join(args, " "), string(out), err)
 		}
-		return unquotedOut, fmt.Errorf("Executing the 'minikube %s' command with output '%s' and error message '%s' failed", strings.Join(args, " "), out, err)
+		return strippedOut, fmt.Errorf("Executing the 'minikube %s' command with output '%s' and error message '%s' failed", strings.Join(args, " "), out, err)
 	}
 	if verbose {
 		fmt.Printf("\nExecuted command:\n  minikube %s\nwith output:\n  %s\n", strings.Join(args, " "), string(out))
 	}
-	return unquotedOut, nil
+	return strippedOut, nil
 }
 
 //CheckVersion checks whether minikube version is supported
@@ -77,7 +94,7 @@ func CheckVersion(verbose bool, timeout time.Duration) (string, error) {
 
 //DockerClient creates a docker client based on minikube "docker-env" configuration
 func DockerClient(verbose bool, profile string, timeout time.Duration) (*docker.Client, error) {
-	envOut, err := RunCmd(verbose, profile, timeout, "docker-env", "--shell", "bash")
+	envOut, err := runCmd(verbose, profile, timeout, "docker-env", "--shell", "bash")
 	if err != nil {
 		if strings.Contains(err.Error(), "driver does not support 'minikube docker-env'") {
 			fmt.Println("docker-env not supported, skipped")
@@ -92,18 +109,72 @@ func DockerClient(verbose bool, profile string, timeout time.Duration) (*docker.
 			os.Setenv(key, val)
 		}
 	}()
-	for _, line := range strings.Split(envOut, "\n") {
-		if strings.HasPrefix(line, "export") {
-			env := strings.SplitN(line, " ", 2)[1]
-			envParts := strings.SplitN(env, "=", 2)
-			envKey := envParts[0]
-			envVal := strings.Trim(envParts[1], `"`)
-			oldEnvs[envKey] = os.Getenv(envKey)
-			err := os.Setenv(envKey, envVal)
-			if err != nil {
-				return nil, err
-			}
+	for envKey, envVal := range parseDockerEnv(envOut) {
+		oldEnvs[envKey] = os.Getenv(envKey)
+		err := os.Setenv(envKey, envVal)
+		if err != nil {
+			return nil, err
 		}
 	}
 	return docker.NewClientWithOpts(docker.FromEnv)
 }
+
+// parseDockerEnv reads the variables exported by "minikube docker-env --shell bash".
+// The values are unquoted like bash does, so that paths with spaces, quotes or non-ASCII characters, such as DOCKER_CERT_PATH, stay intact.
+func parseDockerEnv(out string) map[string]string {
+	envs := make(map[string]string)
+	for _, line := range strings.Split(out, "\n") {
+		line = strings.TrimSpace(line)
+		if !strings.HasPrefix(line, "export ") {
+			continue
+		}
+		envParts := strings.SplitN(strings.TrimSpace(strings.TrimPrefix(line, "export ")), "=", 2)
+		if len(envParts) != 2 {
+			continue
+		}
+		envs[envParts[0]] = unquoteShell(envParts[1])
+	}
+	return envs
+}
+
+// unquoteShell removes the bash quotes of a value: single quotes keep everything literally, double quotes only escape \, ", $ and `
+func unquoteShell(value string) string {
+	if len(value) >= 2 && value[0] == '\'' && value[len(value)-1] == '\'' {
+		return value[1 : len(value)-1]
+	}
+	if len(value) < 2 || value[0] != '"' || value[len(value)-1] != '"' {
+		return value
+	}
+	value = value[1 : len(value)-1]
+	var b strings.Builder
+	for n := 0; n < len(value); n++ {
+		if value[n] == '\\' && n+1 < len(value) && strings.IndexByte("\\\"$`", value[n+1]) >= 0 {
+			n++
+		}
+		b.WriteByte(value[n])
+	}
+	return b.String()
+}
+
+//FreeDiskSpace returns the free space in bytes of the file system holding the path inside the Minikube VM
+func FreeDiskSpace(verbose bool, profile string, timeout time.Duration, path string) (uint64, error) {
+	out, err := RunCmd(verbose, profile, timeout, "ssh", "--", "df", "-Pk", path)
+	if err != nil {
+		return 0, err
+	}
+	return parseDfAvailable(out)
+}
+
+// parseDfAvailable reads the available space from the output of "df -Pk", which is given in kilobytes
+func parseDfAvailable(out string) (uint64, error) {
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	fields := strings.Fields(lines[len(lines)-1])
+	if len(lines) < 2 || len(fields) < 4 {
+		return 0, fmt.Errorf("unable to read the free disk space from '%s'", out)
+	}
+	available, err := strconv.ParseUint(fields[3], 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("unable to read the free disk space from '%s': %s", out, err)
+	}
+	return available * 1024, nil
+}
@@ -0,0 +1,39 @@
+package minikube
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseDfAvailable(t *testing.T) {
+	t.Parallel()
+	free, err := parseDfAvailable(`Filesystem     1024-blocks    Used Available Capacity Mounted on
+/dev/vda1         17784760 5079276  11775540      31% /mnt/vda1
+`)
+	require.NoError(t, err)
+	require.Equal(t, uint64(11775540*1024), free)
+
+	_, err = parseDfAvailable("df: /var/lib/docker: No such file or directory")
+	require.Error(t, err)
+}
+
+func TestParseDockerEnv(t *testing.T) {
+	t.Parallel()
+	// the output of Minikube on Windows ends the lines with \r\n
+	envs := parseDockerEnv("export DOCKER_TLS_VERIFY=\"1\"\r\n" +
+		"export DOCKER_HOST=\"tcp://192.168.64.2:2376\"\r\n" +
+		"export DOCKER_CERT_PATH=\"/Users/Zoë O'Brien/Dev Projects/.minikube/certs\"\r\n" +
+		"export MINIKUBE_ACTIVE_DOCKERD='kyma là'\n" +
+		"export ESCAPED=\"a \\\"quoted\\\" \\$HOME\"\n" +
+		"\n" +
+		"# To point your shell to minikube's docker-daemon, run:\n" +
+		"# eval $(minikube -p minikube docker-env)\n")
+	require.Equal(t, map[string]string{
+		"DOCKER_TLS_VERIFY":       "1",
+		"DOCKER_HOST":             "tcp://192.168.64.2:2376",
+		"DOCKER_CERT_PATH":        "/Users/Zoë O'Brien/Dev Projects/.minikube/certs",
+		"MINIKUBE_ACTIVE_DOCKERD": "kyma là",
+		"ESCAPED":                 `a "quoted" $HOME`,
+	}, envs)
+}
@@ -0,0 +1,65 @@
+package minikube
+
+import (
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// ResolveProfile returns the Minikube profile of the cluster. The given profile takes precedence over the one recorded when the cluster was provisioned.
+// Without both, the profile is detected from the kubeconfig context, which Minikube names after the profile, if Minikube has a profile of that name.
+// An empty result selects the default profile.
+func ResolveProfile(verbose bool, timeout time.Duration, profile, recorded, context string) string {
+	return resolveProfile(profile, recorded, context, func() ([]string, error) { return Profiles(verbose, timeout) })
+}
+
+func resolveProfile(profile, recorded, context string, profiles func() ([]string, error)) string {
+	if profile != "" {
+		return profile
+	}
+	if recorded != "" || context == "" {
+		return recorded
+	}
+	names, err := profiles()
+	if err != nil {
+		return ""
+	}
+	for _, name := range names {
+		if name == context {
+			return name
+		}
+	}
+	return ""
+}
+
+// Profiles lists the names of the valid Minikube profiles with "minikube profile list"
+func Profiles(verbose bool, timeout time.Duration) ([]string, error) {
+	out, err := RunCmd(verbose, "", timeout, "profile", "list", "--output", "json")
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to list the profiles of Minikube")
+	}
+	return parseProfiles(out)
+}
+
+// parseProfiles reads the names of the valid profiles from the JSON output of "minikube profile list"
+func parseProfiles(out string) ([]string, error) {
+	// minikube might print warnings before the JSON document
+	if start := strings.Index(out, "{"); start > 0 {
+		out = out[start:]
+	}
+	list := struct {
+		Valid []struct {
+			Name string
+		} `json:"valid"`
+	}{}
+	if err := json.Unmarshal([]byte(out), &list); err != nil {
+		return nil, errors.Wrap(err, "unable to parse the profiles of Minikube")
+	}
+	var names []string
+	for _, p := range list.Valid {
+		names = append(names, p.Name)
+	}
+	return names, nil
+}
@@ -0,0 +1,37 @@
+package minikube
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolveProfile(t *testing.T) {
+	t.Parallel()
+	listed := false
+	profiles := func() ([]string, error) {
+		listed = true
+		return []string{"minikube", "kyma"}, nil
+	}
+
+	require.Equal(t, "dev", resolveProfile("dev", "kyma", "kyma", profiles), "the given profile takes precedence")
+	require.Equal(t, "kyma", resolveProfile("", "kyma", "minikube", profiles), "the recorded profile takes precedence over the context")
+	require.False(t, listed, "the profiles must only be listed to detect the profile")
+
+	require.Equal(t, "kyma", resolveProfile("", "", "kyma", profiles))
+	require.True(t, listed)
+	require.Equal(t, "", resolveProfile("", "", "gke_project_zone_cluster", profiles), "contexts of other clusters select the default profile")
+	require.Equal(t, "", resolveProfile("", "", "kyma", func() ([]string, error) { return nil, errors.New("minikube not found") }))
+}
+
+func TestParseProfiles(t *testing.T) {
+	t.Parallel()
+	profiles, err := parseProfiles(`! minikube 1.14.0 is available
+{"invalid":[{"Name":"broken"}],"valid":[{"Name":"minikube","Status":"Running"},{"Name":"kyma","Status":"Stopped"}]}`)
+	require.NoError(t, err)
+	require.Equal(t, []string{"minikube", "kyma"}, profiles)
+
+	_, err = parseProfiles("* There is no local cluster")
+	require.Error(t, err)
+}
@@ -0,0 +1,246 @@
+package minikube
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+const disableAdmissionPluginsFlag = "--disable-admission-plugins="
+
+var (
+	// AdmissionPlugins are the admission plugins enabled on the API server of clusters provisioned for Kyma
+	AdmissionPlugins = []string{"DefaultStorageClass", "LimitRanger", "MutatingAdmissionWebhook", "NamespaceExists", "NamespaceLifecycle", "ResourceQuota", "ServiceAccount", "ValidatingAdmissionWebhook"}
+	// RequiredAdmissionPlugins are the admission plugins without which the webhooks of the Kyma components are not called.
+	// The API server enables them by default, so they are only missing if they are disabled explicitly.
+	RequiredAdmissionPlugins = []string{"MutatingAdmissionWebhook", "ValidatingAdmissionWebhook"}
+	// ConflictingAddons are the Minikube addons which conflict with the Kyma components, with the reason
+	ConflictingAddons = map[string]string{
+		"ingress": "its NGINX ingress controller competes with the Istio ingress gateway for the ports 80 and 443",
+	}
+)
+
+// Settings are the settings of a running Minikube cluster which are checked against the requirements of Kyma.
+// Settings which could not be read are left empty and are not checked.
+type Settings struct {
+	// DisabledAdmissionPlugins are the admission plugins disabled on the running API server, nil if unknown
+	DisabledAdmissionPlugins []string
+	// Addons are the enabled addons of the profile
+	Addons []string
+	// Certificate is the serving certificate of the API server
+	Certificate *x509.Certificate
+	// IP is the Minikube IP which the certificate must be valid for
+	IP string
+}
+
+// Discrepancy is a setting of the cluster which does not meet the requirements of Kyma
+type Discrepancy struct {
+	Problem string
+	// Required discrepancies make the installation fail, the other ones only affect parts of Kyma
+	Required bool
+	// StartArgs are the "minikube start" flags fixing the discrepancy
+	StartArgs []string
+	// Addon is the addon to disable to fix the discrepancy
+	Addon string
+}
+
+// Verify compares the settings with the requirements of Kyma
+func Verify(settings Settings) []Discrepancy {
+	var result []Discrepancy
+	var disabled, keep []string
+	for _, p := range settings.DisabledAdmissionPlugins {
+		if contains(RequiredAdmissionPlugins, p) {
+			disabled = append(disabled, p)
+		} else {
+			keep = append(keep, p)
+		}
+	}
+	if len(disabled) > 0 {
+		result = append(result, Discrepancy{
+			Problem:   fmt.Sprintf("the API server disables the admission plugins %s", strings.Join(disabled, ", ")),
+			Required:  true,
+			StartArgs: []string{"--extra-config=apiserver.disable-admission-plugins=" + strings.Join(keep, ",")},
+		})
+	}
+	for _, addon := range settings.Addons {
+		if reason, ok := ConflictingAddons[addon]; ok {
+			result = append(result, Discrepancy{
+				Problem: fmt.Sprintf("the addon '%s' is enabled and %s", addon, reason),
+				Addon:   addon,
+			})
+		}
+	}
+	if settings.Certificate != nil && settings.IP != "" {
+		if err := settings.Certificate.VerifyHostname(settings.IP); err != nil {
+			result = append(result, Discrepancy{
+				Problem:   fmt.Sprintf("the certificate of the API server is not valid for the Minikube IP %s", settings.IP),
+				StartArgs: []string{"--apiserver-ips=" + settings.IP},
+			})
+		}
+	}
+	return result
+}
+
+// AdmissionPluginsArg returns the "minikube start" flag enabling the admission plugins on the API server
+func AdmissionPluginsArg(plugins []string) string {
+	return "--extra-config=apiserver.enable-admission-plugins=" + strings.Join(plugins, ",")
+}
+
+// FixCommands returns the minikube commands fixing the discrepancies, as they are displayed to users
+func FixCommands(profile string, discrepancies []Discrepancy) []string {
+	var profileArg string
+	if profile != "" {
+		profileArg = " --profile " + profile
+	}
+	var commands, startArgs []string
+	for _, d := range discrepancies {
+		startArgs = append(startArgs, d.StartArgs...)
+		if d.Addon != "" {
+			commands = append(commands, fmt.Sprintf("minikube addons disable %s%s", d.Addon, profileArg))
+		}
+	}
+	if len(startArgs) > 0 {
+		commands = append([]string{fmt.Sprintf("minikube start%s %s", profileArg, strings.Join(startArgs, " "))}, commands...)
+	}
+	return commands
+}
+
+// Fix runs the minikube commands fixing the discrepancies. Minikube restarts the API server of an existing cluster with the given flags.
+func Fix(verbose bool, profile string, timeout time.Duration, discrepancies []Discrepancy) error {
+	startArgs := []string{"start"}
+	for _, d := range discrepancies {
+		startArgs = append(startArgs, d.StartArgs...)
+		if d.Addon != "" {
+			if _, err := RunCmd(verbose, profile, timeout, "addons", "disable", d.Addon); err != nil {
+				return err
+			}
+		}
+	}
+	if len(startArgs) > 1 {
+		if _, err := RunCmd(verbose, profile, timeout, startArgs...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ReadSettings reads the settings of the running cluster: the disabled admission plugins from the API server pod, the addons from the Minikube profile,
+// and the serving certificate from the API server at the host of the kubeconfig. Settings which cannot be read are returned as errors and left empty.
+func ReadSettings(verbose bool, profile string, timeout time.Duration, k8s kubernetes.Interface, host, ip string) (Settings, []error) {
+	var errs []error
+	settings := Settings{IP: ip}
+	var err error
+	if settings.DisabledAdmissionPlugins, err = APIServerDisabledAdmissionPlugins(k8s); err != nil {
+		errs = append(errs, err)
+	}
+	if settings.Addons, err = EnabledAddons(verbose, profile, timeout); err != nil {
+		errs = append(errs, err)
+	}
+	if ip != "" {
+		if settings.Certificate, err = ServingCertificate(host, timeout); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return settings, errs
+}
+
+// APIServerDisabledAdmissionPlugins reads the disabled admission plugins from the flags of the static API server pod in the kube-system namespace
+func APIServerDisabledAdmissionPlugins(k8s kubernetes.Interface) ([]string, error) {
+	pods, err := k8s.CoreV1().Pods("kube-system").List(context.Background(), metav1.ListOptions{LabelSelector: "component=kube-apiserver"})
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to read the flags of the API server")
+	}
+	if len(pods.Items) == 0 || len(pods.Items[0].Spec.Containers) == 0 {
+		return nil, errors.New("unable to find the API server pod in the namespace 'kube-system'")
+	}
+	c := pods.Items[0].Spec.Containers[0]
+	return disabledAdmissionPlugins(append(c.Command, c.Args...)), nil
+}
+
+// disabledAdmissionPlugins returns the plugins given with --disable-admission-plugins, the API server keeps all its default plugins if the flag is missing
+func disabledAdmissionPlugins(flags []string) []string {
+	plugins := []string{}
+	for _, f := range flags {
+		if strings.HasPrefix(f, disableAdmissionPluginsFlag) {
+			for _, p := range strings.Split(strings.TrimPrefix(f, disableAdmissionPluginsFlag), ",") {
+				if p = strings.TrimSpace(p); p != "" {
+					plugins = append(plugins, p)
+				}
+			}
+		}
+	}
+	return plugins
+}
+
+// EnabledAddons reads the enabled addons of the profile with "minikube addons list"
+func EnabledAddons(verbose bool, profile string, timeout time.Duration) ([]string, error) {
+	out, err := RunCmd(verbose, profile, timeout, "addons", "list", "--output", "json")
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to read the addons of Minikube")
+	}
+	return parseAddons(out)
+}
+
+// parseAddons reads the enabled addons from the JSON output of "minikube addons list", sorted by name
+func parseAddons(out string) ([]string, error) {
+	// minikube might print warnings before the JSON document
+	if start := strings.Index(out, "{"); start > 0 {
+		out = out[start:]
+	}
+	addons := map[string]struct {
+		Status string
+	}{}
+	if err := json.Unmarshal([]byte(out), &addons); err != nil {
+		return nil, errors.Wrap(err, "unable to parse the addons of Minikube")
+	}
+	var enabled []string
+	for name, a := range addons {
+		if a.Status == "enabled" {
+			enabled = append(enabled, name)
+		}
+	}
+	sort.Strings(enabled)
+	return enabled, nil
+}
+
+// ServingCertificate returns the certificate the API server at the given URL presents.
+// The certificate is not verified, as its SANs are what is checked.
+func ServingCertificate(host string, timeout time.Duration) (*x509.Certificate, error) {
+	addr := host
+	if u, err := url.Parse(host); err == nil && u.Host != "" {
+		addr = u.Host
+	}
+	if _, _, err := net.SplitHostPort(addr); err != nil {
+		addr = net.JoinHostPort(addr, "443")
+	}
+	conn, err := tls.DialWithDialer(&net.Dialer{Timeout: timeout}, "tcp", addr, &tls.Config{InsecureSkipVerify: true})
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to read the certificate of the API server at '%s'", addr)
+	}
+	defer conn.Close()
+	certs := conn.ConnectionState().PeerCertificates
+	if len(certs) == 0 {
+		return nil, fmt.Errorf("the API server at '%s' presents no certificate", addr)
+	}
+	return certs[0], nil
+}
+
+func contains(list []string, s string) bool {
+	for _, e := range list {
+		if e == s {
+			return true
+		}
+	}
+	return false
+}
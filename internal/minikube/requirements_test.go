@@ -0,0 +1,95 @@
+package minikube
+
+import (
+	"crypto/x509"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestVerify(t *testing.T) {
+	t.Parallel()
+	server := httptest.NewTLSServer(http.NotFoundHandler())
+	defer server.Close()
+	cert := server.Certificate()
+
+	t.Run("Settings meet the requirements", func(t *testing.T) {
+		require.Empty(t, Verify(Settings{DisabledAdmissionPlugins: []string{"PodSecurityPolicy"}, Addons: []string{"metrics-server"}, Certificate: cert, IP: "127.0.0.1"}))
+	})
+
+	t.Run("Unknown settings are not checked", func(t *testing.T) {
+		require.Empty(t, Verify(Settings{IP: "192.168.64.2"}))
+	})
+
+	t.Run("Discrepancies", func(t *testing.T) {
+		discrepancies := Verify(Settings{
+			DisabledAdmissionPlugins: []string{"PodSecurityPolicy", "ValidatingAdmissionWebhook"},
+			Addons:                   []string{"ingress", "metrics-server"},
+			Certificate:              cert,
+			IP:                       "192.168.64.2",
+		})
+		require.Len(t, discrepancies, 3)
+		require.True(t, discrepancies[0].Required)
+		require.Equal(t, "the API server disables the admission plugins ValidatingAdmissionWebhook", discrepancies[0].Problem)
+		require.Equal(t, "ingress", discrepancies[1].Addon)
+		require.False(t, discrepancies[2].Required)
+
+		require.Equal(t, []string{
+			"minikube start --profile kyma --extra-config=apiserver.disable-admission-plugins=PodSecurityPolicy --apiserver-ips=192.168.64.2",
+			"minikube addons disable ingress --profile kyma",
+		}, FixCommands("kyma", discrepancies))
+		require.Equal(t, []string{"minikube addons disable ingress"}, FixCommands("", discrepancies[1:2]))
+	})
+}
+
+func TestDisabledAdmissionPlugins(t *testing.T) {
+	t.Parallel()
+	require.Equal(t, []string{"PodSecurityPolicy", "MutatingAdmissionWebhook"}, disabledAdmissionPlugins([]string{"kube-apiserver", "--enable-admission-plugins=LimitRanger", "--disable-admission-plugins=PodSecurityPolicy, MutatingAdmissionWebhook"}))
+	require.Equal(t, []string{}, disabledAdmissionPlugins([]string{"kube-apiserver", "--enable-admission-plugins=NamespaceLifecycle"}), "the webhooks are enabled by default")
+}
+
+func TestAPIServerDisabledAdmissionPlugins(t *testing.T) {
+	t.Parallel()
+	_, err := APIServerDisabledAdmissionPlugins(fake.NewSimpleClientset())
+	require.Error(t, err)
+
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "kube-apiserver-minikube", Namespace: "kube-system", Labels: map[string]string{"component": "kube-apiserver"}},
+		Spec: corev1.PodSpec{Containers: []corev1.Container{{
+			Name:    "kube-apiserver",
+			Command: []string{"kube-apiserver", "--enable-admission-plugins=NamespaceLifecycle", "--disable-admission-plugins=ValidatingAdmissionWebhook"},
+		}}},
+	}
+	plugins, err := APIServerDisabledAdmissionPlugins(fake.NewSimpleClientset(pod))
+	require.NoError(t, err)
+	require.Equal(t, []string{"ValidatingAdmissionWebhook"}, plugins)
+}
+
+func TestParseAddons(t *testing.T) {
+	t.Parallel()
+	addons, err := parseAddons(`! Executing "docker container inspect minikube" took an unusually long time
+{"dashboard":{"Profile":"minikube","Status":"disabled"},"metrics-server":{"Profile":"minikube","Status":"enabled"},"ingress":{"Profile":"minikube","Status":"enabled"}}`)
+	require.NoError(t, err)
+	require.Equal(t, []string{"ingress", "metrics-server"}, addons)
+
+	_, err = parseAddons("minikube profile not found")
+	require.Error(t, err)
+}
+
+func TestServingCertificate(t *testing.T) {
+	t.Parallel()
+	server := httptest.NewTLSServer(http.NotFoundHandler())
+	defer server.Close()
+
+	cert, err := ServingCertificate(server.URL, 5*time.Second)
+	require.NoError(t, err)
+	require.NoError(t, cert.VerifyHostname("127.0.0.1"))
+	require.True(t, errors.As(cert.VerifyHostname("192.168.64.2"), &x509.HostnameError{}))
+}
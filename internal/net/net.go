@@ -6,6 +6,8 @@ import (
 	"net/http"
 	"strconv"
 	"time"
+
+	"github.com/kyma-project/cli/internal/github"
 )
 
 func GetAvailablePort() (int, error) {
@@ -31,7 +33,8 @@ func DoGet(url string) (int, error) {
 		CheckRedirect: func(req *http.Request, via []*http.Request) error {
 			return http.ErrUseLastResponse
 		},
-		Timeout: 5 * time.Second,
+		Timeout:   5 * time.Second,
+		Transport: github.NewTransport(http.DefaultTransport),
 	}
 	req, err := http.NewRequest("GET", url, nil)
 	if err != nil {
@@ -0,0 +1,11 @@
+package nice
+
+import "regexp"
+
+// ansiSequence matches the ANSI escape sequences tools like kubectl and minikube use to colorize their output, e.g. "\x1b[0;32m"
+var ansiSequence = regexp.MustCompile(`\x1b(\[[0-9;?]*[ -/]*[@-~]|\][^\x07\x1b]*(\x07|\x1b\\)|[@-Z\\-_])`)
+
+// StripANSI removes ANSI escape sequences from the text, so that captured output can be written to log files, JSON or Markdown
+func StripANSI(s string) string {
+	return ansiSequence.ReplaceAllString(s, "")
+}
@@ -3,6 +3,8 @@ package nice
 
 import (
 	"fmt"
+	"io"
+	"os"
 
 	ct "github.com/daviddengcn/go-colortext"
 )
@@ -10,27 +12,29 @@ import (
 // Nice contains the option to determine the interactivity of the printing.
 type Nice struct {
 	NonInteractive bool
+	// Out is the writer to print to, os.Stdout if not set. The colors are only printed to os.Stdout.
+	Out io.Writer
 }
 
 // PrintKyma prints the Kyma word with its identity color
 func (n *Nice) PrintKyma() {
-	if n.NonInteractive {
-		fmt.Print("Kyma")
-	} else {
+	if n.colored() {
 		ct.ChangeColor(ct.Cyan, false, ct.None, false)
-		fmt.Print("Kyma")
+		fmt.Fprint(n.out(), "Kyma")
 		ct.ResetColor()
+	} else {
+		fmt.Fprint(n.out(), "Kyma")
 	}
 }
 
 // PrintImportant prints the line with yellow color
 func (n *Nice) PrintImportant(s string) {
-	if n.NonInteractive {
-		fmt.Println(s)
-	} else {
+	if n.colored() {
 		ct.ChangeColor(ct.Yellow, true, ct.None, false)
-		fmt.Println(s)
+		fmt.Fprintln(n.out(), s)
 		ct.ResetColor()
+	} else {
+		fmt.Fprintln(n.out(), s)
 	}
 }
 
@@ -38,3 +42,14 @@ func (n *Nice) PrintImportant(s string) {
 func (n *Nice) PrintImportantf(format string, a ...interface{}) {
 	n.PrintImportant(fmt.Sprintf(format, a...))
 }
+
+func (n *Nice) out() io.Writer {
+	if n.Out == nil {
+		return os.Stdout
+	}
+	return n.Out
+}
+
+func (n *Nice) colored() bool {
+	return !n.NonInteractive && n.out() == os.Stdout
+}
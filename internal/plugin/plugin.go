@@ -0,0 +1,126 @@
+// Package plugin discovers and runs Kyma CLI plugins.
+// Following the kubectl plugin model, a plugin is any executable on the PATH named "kymactl-<name>",
+// which is then available as the "kyma <name>" command.
+package plugin
+
+import (
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+)
+
+// Prefix is the prefix of the executable name of all plugins.
+const Prefix = "kymactl-"
+
+// Environment variables in which the global Kyma CLI settings are passed to the plugins.
+const (
+	EnvKubeconfig     = "KYMACTL_KUBECONFIG"
+	EnvContext        = "KYMACTL_CONTEXT"
+	EnvKubectlArgs    = "KYMACTL_KUBECTL_ARGS"
+	EnvVerbose        = "KYMACTL_VERBOSE"
+	EnvCI             = "KYMACTL_CI"
+	EnvNonInteractive = "KYMACTL_NON_INTERACTIVE"
+)
+
+// Plugin is an executable providing a Kyma CLI command.
+type Plugin struct {
+	// Name is the name of the command provided by the plugin.
+	Name string
+	// Path is the absolute path of the executable.
+	Path string
+	// Shadowed lists the paths of executables with the same name found later on the PATH, which are never run.
+	Shadowed []string
+}
+
+// Find returns all plugins found in the directories of the given PATH value, sorted by name.
+// If several executables provide the same plugin, the first one on the PATH wins, as with any other executable.
+func Find(path string) []Plugin {
+	plugins := map[string]*Plugin{}
+	for _, dir := range filepath.SplitList(path) {
+		if dir == "" {
+			continue
+		}
+		files, err := ioutil.ReadDir(dir)
+		if err != nil {
+			// PATH entries that do not exist or cannot be read are skipped, as the shell does
+			continue
+		}
+		for _, f := range files {
+			name, ok := pluginName(f)
+			if !ok {
+				continue
+			}
+			file := filepath.Join(dir, f.Name())
+			if p, exists := plugins[name]; exists {
+				p.Shadowed = append(p.Shadowed, file)
+				continue
+			}
+			plugins[name] = &Plugin{Name: name, Path: file}
+		}
+	}
+
+	result := make([]Plugin, 0, len(plugins))
+	for _, p := range plugins {
+		result = append(result, *p)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Name < result[j].Name })
+	return result
+}
+
+// Lookup returns the plugin providing the command with the given name from the directories of the given PATH value.
+// Only the executables of this plugin are checked, so that the other plugins on the PATH do not slow down the CLI.
+func Lookup(name, path string) (Plugin, bool) {
+	file := Prefix + name
+	if runtime.GOOS == "windows" {
+		file += ".exe"
+	}
+	for _, dir := range filepath.SplitList(path) {
+		if dir == "" {
+			continue
+		}
+		f, err := os.Stat(filepath.Join(dir, file))
+		if err != nil {
+			continue
+		}
+		if n, ok := pluginName(f); ok && n == name {
+			return Plugin{Name: name, Path: filepath.Join(dir, file)}, true
+		}
+	}
+	return Plugin{}, false
+}
+
+// pluginName returns the command name of a plugin executable
+func pluginName(f os.FileInfo) (string, bool) {
+	if f.IsDir() || !strings.HasPrefix(f.Name(), Prefix) {
+		return "", false
+	}
+	name := strings.TrimPrefix(f.Name(), Prefix)
+	if runtime.GOOS == "windows" {
+		ext := filepath.Ext(name)
+		if !strings.EqualFold(ext, ".exe") {
+			return "", false
+		}
+		name = strings.TrimSuffix(name, ext)
+	} else if f.Mode()&0111 == 0 {
+		return "", false
+	}
+	return name, name != ""
+}
+
+// Run executes the plugin with the given arguments and the additional environment variables.
+// The standard input and outputs of the CLI are passed to the plugin.
+func (p Plugin) Run(args []string, env map[string]string) error {
+	cmd := exec.Command(p.Path, args...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	cmd.Env = os.Environ()
+	for k, v := range env {
+		cmd.Env = append(cmd.Env, k+"="+v)
+	}
+	return cmd.Run()
+}
@@ -0,0 +1,56 @@
+package plugin
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFind(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("executable permissions are not used on windows")
+	}
+	t.Parallel()
+	first, err := ioutil.TempDir("", "plugins-first")
+	require.NoError(t, err)
+	defer os.RemoveAll(first)
+	second, err := ioutil.TempDir("", "plugins-second")
+	require.NoError(t, err)
+	defer os.RemoveAll(second)
+
+	write := func(dir, name string, mode os.FileMode) string {
+		file := filepath.Join(dir, name)
+		require.NoError(t, ioutil.WriteFile(file, []byte("#!/bin/sh\n"), mode))
+		return file
+	}
+	foo := write(first, "kymactl-foo", 0755)
+	write(first, "kymactl-not-executable", 0644)
+	write(first, "kubectl-other", 0755)
+	require.NoError(t, os.Mkdir(filepath.Join(first, "kymactl-dir"), 0755))
+	shadowedFoo := write(second, "kymactl-foo", 0755)
+	bar := write(second, "kymactl-bar", 0755)
+
+	path := strings.Join([]string{first, filepath.Join(first, "missing"), second}, string(os.PathListSeparator))
+	plugins := Find(path)
+	require.Equal(t, []Plugin{
+		{Name: "bar", Path: bar},
+		{Name: "foo", Path: foo, Shadowed: []string{shadowedFoo}},
+	}, plugins)
+
+	require.Empty(t, Find(""))
+
+	p, ok := Lookup("foo", path)
+	require.True(t, ok)
+	require.Equal(t, Plugin{Name: "foo", Path: foo}, p)
+	_, ok = Lookup("not-executable", path)
+	require.False(t, ok)
+	_, ok = Lookup("dir", path)
+	require.False(t, ok)
+	_, ok = Lookup("missing", path)
+	require.False(t, ok)
+}
@@ -0,0 +1,51 @@
+// Package redact masks the secrets given to the CLI, such as tokens, in its logs and traces.
+package redact
+
+import (
+	"strings"
+	"sync"
+)
+
+// Placeholder replaces the secrets
+const Placeholder = "<redacted>"
+
+var (
+	secrets []string
+	lock    sync.RWMutex
+)
+
+// Add registers a secret, which is masked from now on. Empty secrets are ignored.
+func Add(secret string) {
+	if secret == "" {
+		return
+	}
+	lock.Lock()
+	defer lock.Unlock()
+	for _, s := range secrets {
+		if s == secret {
+			return
+		}
+	}
+	secrets = append(secrets, secret)
+}
+
+// String replaces all registered secrets in the text with the placeholder
+func String(text string) string {
+	lock.RLock()
+	defer lock.RUnlock()
+	for _, s := range secrets {
+		text = strings.ReplaceAll(text, s, Placeholder)
+	}
+	return text
+}
+
+// Bytes replaces all registered secrets in the data with the placeholder, e.g. in serialized logs
+func Bytes(data []byte) []byte {
+	lock.RLock()
+	empty := len(secrets) == 0
+	lock.RUnlock()
+	if empty {
+		return data
+	}
+	return []byte(String(string(data)))
+}
@@ -0,0 +1,21 @@
+package redact
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRedact(t *testing.T) {
+	// not parallel: the secrets are package level
+	defer func() { secrets = nil }()
+
+	require.Equal(t, "--github-token=ghp_s3cr3t", String("--github-token=ghp_s3cr3t"), "nothing is masked before a secret is added")
+
+	Add("ghp_s3cr3t")
+	Add("ghp_s3cr3t")
+	Add("")
+	require.Len(t, secrets, 1)
+	require.Equal(t, "--github-token=<redacted>", String("--github-token=ghp_s3cr3t"))
+	require.Equal(t, []byte(`{"args":["install","--github-token","<redacted>"]}`), Bytes([]byte(`{"args":["install","--github-token","ghp_s3cr3t"]}`)))
+}
@@ -0,0 +1,250 @@
+// Package release locates the artifacts of a Kyma release, independent of where they are published:
+// the artifact buckets of a version or release channel, a mirror or a local directory for air-gapped installations.
+package release
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/blang/semver/v4"
+	"github.com/kyma-project/cli/internal/github"
+	"github.com/kyma-project/cli/internal/releases"
+	"github.com/pkg/errors"
+)
+
+const (
+	// InstallerManifestAsset is the manifest of the Kyma Installer
+	InstallerManifestAsset = "kyma-installer-cluster.yaml"
+	// TillerAsset is the manifest of Tiller, it is only published by the releases which install the components with Helm 2
+	TillerAsset = "tiller.yaml"
+	// ChecksumsAsset lists the SHA-256 checksums of the artifacts in the format of sha256sum
+	ChecksumsAsset = "checksums.txt"
+
+	releaseArtifacts     = "https://storage.googleapis.com/kyma-prow-artifacts"
+	developmentArtifacts = "https://storage.googleapis.com/kyma-development-artifacts"
+)
+
+// ClusterProfile is the kind of cluster the Installation CR and the installer configuration of a release are made for
+type ClusterProfile string
+
+const (
+	// LocalCluster is a Minikube or k3d cluster
+	LocalCluster ClusterProfile = "local"
+	// RemoteCluster is a cluster of a cloud provider or a Gardener cluster
+	RemoteCluster ClusterProfile = "cluster"
+)
+
+// ErrArtifactNotFound is returned if the release does not publish an artifact, it can be checked with errors.Is
+var ErrArtifactNotFound = errors.New("the artifact is not published")
+
+// developmentVersion matches the versions of the master builds and pull requests, e.g. master-34edf09a or PR-9486
+var developmentVersion = regexp.MustCompile(`^(master-[0-9a-fA-F]{8}|PR-[0-9]+)$`)
+
+// Release is a Kyma version and the location of its artifacts
+type Release struct {
+	Version string
+	// base is the URL or, if local is set, the directory holding the artifacts
+	base  string
+	local bool
+}
+
+// Artifact is a file published with a release
+type Artifact struct {
+	Name string
+	// Location is the URL of the artifact, or its path if the release is stored in a local directory
+	Location string
+	local    bool
+}
+
+// FromVersion returns the release published in the Kyma artifact buckets, either a release version (e.g. 1.15.1),
+// or the build of a master commit (e.g. master-34edf09a) or pull request (e.g. PR-9486)
+func FromVersion(version string) (*Release, error) {
+	if developmentVersion.MatchString(version) {
+		return &Release{Version: version, base: fmt.Sprintf("%s/%s", developmentArtifacts, version)}, nil
+	}
+	if _, err := semver.Parse(version); err != nil {
+		return nil, fmt.Errorf("'%s' is neither a release version nor the version of a master or pull request build", version)
+	}
+	return &Release{Version: version, base: fmt.Sprintf("%s/%s", releaseArtifacts, version)}, nil
+}
+
+// FromChannel returns the release the channel currently points to, see releases.ResolveChannel
+func FromChannel(channel string, warn func(format string, args ...interface{})) (*Release, error) {
+	r, err := releases.ResolveChannel(channel, warn)
+	if err != nil {
+		return nil, err
+	}
+	return FromResolution(r), nil
+}
+
+// FromResolution returns the release of a resolved release channel
+func FromResolution(r releases.Resolution) *Release {
+	return &Release{Version: r.Version, base: r.Artifacts()}
+}
+
+// FromURL returns the release of the version whose artifacts are published at the URL, e.g. on a mirror
+func FromURL(base, version string) (*Release, error) {
+	u, err := url.Parse(base)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, fmt.Errorf("invalid URL '%s' of the release artifacts: use http or https", base)
+	}
+	return &Release{Version: version, base: strings.TrimSuffix(base, "/")}, nil
+}
+
+// FromDirectory returns the release of the version whose artifacts are stored in the local directory, e.g. for air-gapped installations
+func FromDirectory(dir, version string) (*Release, error) {
+	info, err := os.Stat(dir)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to read the directory of the release artifacts")
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("'%s' is not a directory", dir)
+	}
+	return &Release{Version: version, base: dir, local: true}, nil
+}
+
+// FromLocation returns the release of the version whose artifacts are published at the URL or stored in the directory
+func FromLocation(location, version string) (*Release, error) {
+	if strings.HasPrefix(location, "http://") || strings.HasPrefix(location, "https://") {
+		return FromURL(location, version)
+	}
+	return FromDirectory(location, version)
+}
+
+// Location returns the URL or the directory of the artifacts
+func (r *Release) Location() string {
+	return r.base
+}
+
+// Artifact returns the artifact with the given name
+func (r *Release) Artifact(name string) Artifact {
+	if r.local {
+		return Artifact{Name: name, Location: filepath.Join(r.base, name), local: true}
+	}
+	return Artifact{Name: name, Location: fmt.Sprintf("%s/%s", r.base, name)}
+}
+
+// InstallerManifest returns the manifest of the Kyma Installer
+func (r *Release) InstallerManifest() Artifact {
+	return r.Artifact(InstallerManifestAsset)
+}
+
+// InstallerCR returns the Installation CR for the kind of cluster
+func (r *Release) InstallerCR(profile ClusterProfile) Artifact {
+	return r.Artifact(fmt.Sprintf("kyma-installer-cr-%s.yaml", profile))
+}
+
+// InstallerConfig returns the installer configuration for the kind of cluster, the releases only publish one for local clusters
+func (r *Release) InstallerConfig(profile ClusterProfile) (Artifact, bool) {
+	if profile != LocalCluster {
+		return Artifact{}, false
+	}
+	return r.Artifact(releases.LocalConfigAsset), true
+}
+
+// TillerManifest returns the manifest of Tiller
+func (r *Release) TillerManifest() Artifact {
+	return r.Artifact(TillerAsset)
+}
+
+// Checksums returns the SHA-256 checksums of the artifacts by name
+func (r *Release) Checksums() (map[string]string, error) {
+	reader, _, err := r.Artifact(ChecksumsAsset).Open(github.DefaultClient)
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	checksums := map[string]string{}
+	scanner := bufio.NewScanner(reader)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 {
+			continue
+		}
+		if len(fields) != 2 || len(fields[0]) != 64 {
+			return nil, fmt.Errorf("invalid line in '%s': %s", ChecksumsAsset, scanner.Text())
+		}
+		// sha256sum marks files read in binary mode with an asterisk
+		checksums[strings.TrimPrefix(fields[1], "*")] = strings.ToLower(fields[0])
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, errors.Wrapf(err, "unable to read '%s'", ChecksumsAsset)
+	}
+	return checksums, nil
+}
+
+// Local checks if the artifact is stored in a local directory
+func (a Artifact) Local() bool {
+	return a.local
+}
+
+// Open returns the content of the artifact and its size, which is -1 if unknown. Downloads use the client.
+// ErrArtifactNotFound is returned if the artifact does not exist.
+func (a Artifact) Open(client *http.Client) (io.ReadCloser, int64, error) {
+	if a.local {
+		f, err := os.Open(a.Location)
+		if os.IsNotExist(err) {
+			return nil, 0, errors.Wrapf(ErrArtifactNotFound, "couldn't read the file: %s", a.Location)
+		}
+		if err != nil {
+			return nil, 0, err
+		}
+		info, err := f.Stat()
+		if err != nil {
+			f.Close()
+			return nil, 0, err
+		}
+		return f, info.Size(), nil
+	}
+
+	resp, err := client.Get(a.Location)
+	if err != nil {
+		return nil, 0, err
+	}
+	if resp.StatusCode == http.StatusOK {
+		return resp.Body, resp.ContentLength, nil
+	}
+	resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, 0, errors.Wrapf(ErrArtifactNotFound, "couldn't download the file: %s, response: %v", a.Location, resp.Status)
+	}
+	return nil, 0, fmt.Errorf("couldn't download the file: %s, response: %v", a.Location, resp.Status)
+}
+
+// Available checks if the artifact exists, without downloading it
+func (a Artifact) Available(client *http.Client) (bool, error) {
+	if a.local {
+		_, err := os.Stat(a.Location)
+		if os.IsNotExist(err) {
+			return false, nil
+		}
+		return err == nil, err
+	}
+
+	resp, err := client.Head(a.Location)
+	if err != nil {
+		return false, err
+	}
+	if err := resp.Body.Close(); err != nil {
+		return false, errors.Wrap(err, "while closing body")
+	}
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return true, nil
+	case http.StatusNotFound:
+		return false, nil
+	}
+	return false, fmt.Errorf("got unexpected status code when checking the file %s: [%d]", a.Location, resp.StatusCode)
+}
+
+func (a Artifact) String() string {
+	return a.Location
+}
@@ -0,0 +1,155 @@
+package release
+
+import (
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/kyma-project/cli/internal/releases"
+	"github.com/stretchr/testify/require"
+)
+
+const checksums = `9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08  kyma-installer-cluster.yaml
+60303ae22b998861bce3b28f33eec1be758a213c86c93c076dbe9f558c11c752 *kyma-config-local.yaml
+`
+
+func TestFromVersion(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		version  string
+		expected string
+	}{
+		{version: "1.15.1", expected: "https://storage.googleapis.com/kyma-prow-artifacts/1.15.1/kyma-installer-cluster.yaml"},
+		{version: "1.16.0-rc2", expected: "https://storage.googleapis.com/kyma-prow-artifacts/1.16.0-rc2/kyma-installer-cluster.yaml"},
+		{version: "master-34edf09a", expected: "https://storage.googleapis.com/kyma-development-artifacts/master-34edf09a/kyma-installer-cluster.yaml"},
+		{version: "PR-9486", expected: "https://storage.googleapis.com/kyma-development-artifacts/PR-9486/kyma-installer-cluster.yaml"},
+	}
+	for _, c := range cases {
+		r, err := FromVersion(c.version)
+		require.NoError(t, err, c.version)
+		require.Equal(t, c.version, r.Version)
+		require.Equal(t, c.expected, r.InstallerManifest().Location, c.version)
+		require.False(t, r.InstallerManifest().Local())
+	}
+
+	for _, v := range []string{"master", "master-34edf09", "PR-abc", "latest"} {
+		_, err := FromVersion(v)
+		require.Error(t, err, v)
+	}
+}
+
+func TestFromResolution(t *testing.T) {
+	t.Parallel()
+	r := FromResolution(releases.Resolution{Channel: releases.ChannelNightly, Version: "34edf09a"})
+	require.Equal(t, "34edf09a", r.Version)
+	require.Equal(t, "https://storage.googleapis.com/kyma-development-artifacts/master-34edf09a/tiller.yaml", r.TillerManifest().Location)
+
+	r = FromResolution(releases.Resolution{Channel: releases.ChannelStable, Version: "1.16.1"})
+	require.Equal(t, "https://storage.googleapis.com/kyma-prow-artifacts/1.16.1", r.Location())
+}
+
+func TestFromURL(t *testing.T) {
+	t.Parallel()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/kyma/1.15.1/kyma-installer-cluster.yaml":
+			_, _ = w.Write([]byte("kind: Deployment\n"))
+		case "/kyma/1.15.1/checksums.txt":
+			_, _ = w.Write([]byte(checksums))
+		case "/kyma/1.15.1/tiller.yaml":
+			w.WriteHeader(http.StatusInternalServerError)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	r, err := FromURL(server.URL+"/kyma/1.15.1/", "1.15.1")
+	require.NoError(t, err)
+	require.Equal(t, server.URL+"/kyma/1.15.1/kyma-installer-cr-cluster.yaml", r.InstallerCR(RemoteCluster).Location)
+
+	reader, _, err := r.InstallerManifest().Open(server.Client())
+	require.NoError(t, err)
+	data, err := ioutil.ReadAll(reader)
+	require.NoError(t, err)
+	require.NoError(t, reader.Close())
+	require.Equal(t, "kind: Deployment\n", string(data))
+	available, err := r.InstallerManifest().Available(server.Client())
+	require.NoError(t, err)
+	require.True(t, available)
+
+	config, ok := r.InstallerConfig(LocalCluster)
+	require.True(t, ok)
+	_, _, err = config.Open(server.Client())
+	require.True(t, errors.Is(err, ErrArtifactNotFound))
+	require.Contains(t, err.Error(), "404 Not Found")
+	available, err = config.Available(server.Client())
+	require.NoError(t, err)
+	require.False(t, available)
+
+	// other errors than a missing artifact are not reported as such
+	_, _, err = r.TillerManifest().Open(server.Client())
+	require.Error(t, err)
+	require.False(t, errors.Is(err, ErrArtifactNotFound))
+	_, err = r.TillerManifest().Available(server.Client())
+	require.Error(t, err)
+
+	sums, err := r.Checksums()
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{
+		"kyma-installer-cluster.yaml": "9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08",
+		"kyma-config-local.yaml":      "60303ae22b998861bce3b28f33eec1be758a213c86c93c076dbe9f558c11c752",
+	}, sums)
+
+	for _, base := range []string{"ftp://mirror.example.com/kyma", "mirror.example.com/kyma", "https://"} {
+		_, err := FromURL(base, "1.15.1")
+		require.Error(t, err, base)
+	}
+}
+
+func TestFromDirectory(t *testing.T) {
+	t.Parallel()
+	dir, err := ioutil.TempDir("", "kyma-release")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "kyma-installer-cr-local.yaml"), []byte("kind: Installation\n"), 0600))
+
+	r, err := FromLocation(dir, "1.15.1")
+	require.NoError(t, err)
+	cr := r.InstallerCR(LocalCluster)
+	require.True(t, cr.Local())
+	require.Equal(t, filepath.Join(dir, "kyma-installer-cr-local.yaml"), cr.Location)
+	reader, size, err := cr.Open(nil)
+	require.NoError(t, err)
+	require.NoError(t, reader.Close())
+	require.Equal(t, int64(len("kind: Installation\n")), size)
+	available, err := cr.Available(nil)
+	require.NoError(t, err)
+	require.True(t, available)
+
+	_, _, err = r.InstallerManifest().Open(nil)
+	require.True(t, errors.Is(err, ErrArtifactNotFound))
+	available, err = r.InstallerManifest().Available(nil)
+	require.NoError(t, err)
+	require.False(t, available)
+	_, err = r.Checksums()
+	require.True(t, errors.Is(err, ErrArtifactNotFound))
+
+	_, ok := r.InstallerConfig(RemoteCluster)
+	require.False(t, ok, "the releases publish no installer configuration for clusters")
+
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, ChecksumsAsset), []byte("not a checksum\n"), 0600))
+	_, err = r.Checksums()
+	require.Error(t, err)
+	require.True(t, strings.HasPrefix(err.Error(), "invalid line in 'checksums.txt'"))
+
+	_, err = FromDirectory(filepath.Join(dir, "missing"), "1.15.1")
+	require.Error(t, err)
+	_, err = FromDirectory(cr.Location, "1.15.1")
+	require.EqualError(t, err, "'"+cr.Location+"' is not a directory")
+}
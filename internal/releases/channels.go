@@ -0,0 +1,231 @@
+package releases
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/blang/semver/v4"
+	"github.com/kyma-project/cli/internal/github"
+	"github.com/pkg/errors"
+)
+
+const (
+	// ChannelStable resolves to the newest generally available release
+	ChannelStable = "stable"
+	// ChannelCanary resolves to the newest release, including release candidates
+	ChannelCanary = "canary"
+	// ChannelNightly resolves to the newest master commit with published build artifacts
+	ChannelNightly = "nightly"
+
+	channelCacheFile = "channels.json"
+	channelCacheTTL  = 10 * time.Minute
+	// channelEnvPrefix is the prefix of the environment variables pinning the version of a channel, e.g. KYMACTL_RELEASE_STABLE=1.16.0
+	channelEnvPrefix = "KYMACTL_RELEASE_"
+	// nightlyCandidates is the number of master commits checked for build artifacts
+	nightlyCandidates = 10
+)
+
+var (
+	// commitsURL is the GitHub API endpoint listing the newest commits of the Kyma master branch
+	commitsURL = fmt.Sprintf("https://api.github.com/repos/kyma-project/kyma/commits?per_page=%d", nightlyCandidates)
+
+	channels = []Channel{
+		{
+			Name:         ChannelStable,
+			Description:  "the newest generally available release",
+			ArtifactsURL: "https://storage.googleapis.com/kyma-prow-artifacts/%s",
+			resolve:      func(Channel) (string, error) { return LatestVersion() },
+		},
+		{
+			Name:         ChannelCanary,
+			Description:  "the newest release, including release candidates",
+			ArtifactsURL: "https://storage.googleapis.com/kyma-prow-artifacts/%s",
+			resolve:      latestPrerelease,
+		},
+		{
+			Name:         ChannelNightly,
+			Description:  "the newest master build",
+			ArtifactsURL: "https://storage.googleapis.com/kyma-development-artifacts/master-%s",
+			resolve:      latestNightly,
+		},
+	}
+)
+
+// Channel is a release track which resolves to a Kyma version when it is used
+type Channel struct {
+	Name        string
+	Description string
+	// ArtifactsURL is the template of the location of the release artifacts, the version is filled in
+	ArtifactsURL string
+	resolve      func(c Channel) (string, error)
+}
+
+// Resolution is the version a channel resolved to
+type Resolution struct {
+	Channel    string    `json:"channel"`
+	Version    string    `json:"version"`
+	ResolvedAt time.Time `json:"resolvedAt"`
+	// Pinned is set if the version was given with the environment variable of the channel
+	Pinned bool `json:"-"`
+}
+
+// Artifacts returns the location of the artifacts of the resolved version
+func (r Resolution) Artifacts() string {
+	c, _ := channel(r.Channel)
+	return fmt.Sprintf(c.ArtifactsURL, r.Version)
+}
+
+// ChannelNames lists the names of the release channels
+func ChannelNames() []string {
+	var names []string
+	for _, c := range channels {
+		names = append(names, c.Name)
+	}
+	return names
+}
+
+// ChannelEnv returns the environment variable pinning the version of the channel, for offline use
+func ChannelEnv(name string) string {
+	return channelEnvPrefix + strings.ToUpper(name)
+}
+
+func channel(name string) (Channel, error) {
+	for _, c := range channels {
+		if c.Name == name {
+			return c, nil
+		}
+	}
+	return Channel{}, fmt.Errorf("unknown release channel '%s', use one of: %s", name, strings.Join(ChannelNames(), ", "))
+}
+
+// ResolveChannel returns the version the release channel currently points to.
+// Resolutions are cached for ten minutes in the Kyma CLI home folder. If the channel cannot be resolved, e.g. when offline,
+// an outdated cached resolution is used and the reason is passed to warn. The environment variable of the channel overrides the resolution.
+func ResolveChannel(name string, warn func(format string, args ...interface{})) (Resolution, error) {
+	c, err := channel(name)
+	if err != nil {
+		return Resolution{}, err
+	}
+	path, err := cachePath()
+	if err != nil {
+		return Resolution{}, err
+	}
+	return resolveChannel(c, filepath.Join(filepath.Dir(path), channelCacheFile), os.LookupEnv, warn)
+}
+
+func resolveChannel(c Channel, cachePath string, lookup func(string) (string, bool), warn func(format string, args ...interface{})) (Resolution, error) {
+	if v, ok := lookup(ChannelEnv(c.Name)); ok && v != "" {
+		return Resolution{Channel: c.Name, Version: v, ResolvedAt: time.Now(), Pinned: true}, nil
+	}
+
+	cached := readChannelCache(cachePath)
+	r, found := cached[c.Name]
+	if found && time.Since(r.ResolvedAt) < channelCacheTTL {
+		return r, nil
+	}
+
+	version, err := c.resolve(c)
+	if err != nil {
+		if !found {
+			return Resolution{}, errors.Wrapf(err, "unable to resolve the release channel '%s'. To use it offline, set the version with %s", c.Name, ChannelEnv(c.Name))
+		}
+		warn("Unable to resolve the release channel '%s', using the version %s it resolved to at %s instead: %s", c.Name, r.Version, r.ResolvedAt.Format(time.RFC3339), err)
+		return r, nil
+	}
+
+	r = Resolution{Channel: c.Name, Version: version, ResolvedAt: time.Now()}
+	cached[c.Name] = r
+	// a failing cache only slows down the next call, so the error is ignored
+	_ = writeChannelCache(cachePath, cached)
+	return r, nil
+}
+
+func readChannelCache(path string) map[string]Resolution {
+	cached := map[string]Resolution{}
+	if data, err := ioutil.ReadFile(path); err == nil {
+		_ = json.Unmarshal(data, &cached)
+	}
+	return cached
+}
+
+func writeChannelCache(path string, cached map[string]Resolution) error {
+	data, err := json.Marshal(cached)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, data, 0600)
+}
+
+// latestPrerelease returns the version of the newest Kyma release, including release candidates
+func latestPrerelease(Channel) (string, error) {
+	releases, err := List(true)
+	if err != nil {
+		return "", err
+	}
+	if len(releases) == 0 {
+		return "", errors.New("no Kyma release found")
+	}
+	return releases[0].Version, nil
+}
+
+// latestNightly returns the abbreviated hash of the newest master commit whose build artifacts are published
+func latestNightly(c Channel) (string, error) {
+	// the token is only sent to GitHub, not to the artifacts bucket
+	client := github.NewClient(10 * time.Second)
+	resp, err := client.Get(commitsURL)
+	if err != nil {
+		return "", errors.Wrap(err, "unable to fetch the Kyma commits")
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("unable to fetch the Kyma commits, response: %v", resp.Status)
+	}
+	var commits []struct {
+		SHA string `json:"sha"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&commits); err != nil {
+		return "", errors.Wrap(err, "unable to read the Kyma commits")
+	}
+
+	for _, commit := range commits {
+		if len(commit.SHA) < 8 {
+			continue
+		}
+		hash := commit.SHA[:8]
+		head, err := client.Head(fmt.Sprintf(c.ArtifactsURL, hash) + "/kyma-installer-cluster.yaml")
+		if err != nil {
+			return "", errors.Wrap(err, "unable to check the build artifacts")
+		}
+		head.Body.Close()
+		if head.StatusCode == http.StatusOK {
+			return hash, nil
+		}
+	}
+	return "", fmt.Errorf("none of the %d newest master commits has published build artifacts", len(commits))
+}
+
+// UpgradeTarget returns the newest of the releases which a cluster running the given version can be upgraded to, which is at most one minor version ahead.
+// It returns false if the current version is not a release or there is no newer release.
+func UpgradeTarget(current string, releases []Release) (string, bool) {
+	cv, err := semver.ParseTolerant(current)
+	if err != nil {
+		return "", false
+	}
+	for _, r := range releases {
+		v, err := semver.ParseTolerant(r.Version)
+		if err != nil || !v.GT(cv) || v.Major != cv.Major || v.Minor > cv.Minor+1 {
+			continue
+		}
+		return r.Version, true
+	}
+	return "", false
+}
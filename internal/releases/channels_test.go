@@ -0,0 +1,118 @@
+package releases
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolveChannel(t *testing.T) {
+	t.Parallel()
+	dir, err := ioutil.TempDir("", "kyma-channels")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	cachePath := filepath.Join(dir, "cache", channelCacheFile)
+
+	var warnings []string
+	warn := func(format string, args ...interface{}) {
+		warnings = append(warnings, fmt.Sprintf(format, args...))
+	}
+	noEnv := func(string) (string, bool) { return "", false }
+	calls := 0
+	online := Channel{Name: ChannelStable, ArtifactsURL: "https://example.com/%s", resolve: func(Channel) (string, error) { calls++; return "1.16.1", nil }}
+	offline := Channel{Name: ChannelStable, resolve: func(Channel) (string, error) { calls++; return "", errors.New("no network") }}
+
+	_, err = resolveChannel(offline, cachePath, noEnv, warn)
+	require.EqualError(t, err, "unable to resolve the release channel 'stable'. To use it offline, set the version with KYMACTL_RELEASE_STABLE: no network")
+
+	r, err := resolveChannel(online, cachePath, noEnv, warn)
+	require.NoError(t, err)
+	require.Equal(t, "1.16.1", r.Version)
+	require.False(t, r.Pinned)
+
+	// served from the cache
+	r, err = resolveChannel(offline, cachePath, noEnv, warn)
+	require.NoError(t, err)
+	require.Equal(t, "1.16.1", r.Version)
+	require.Equal(t, 2, calls)
+	require.Empty(t, warnings)
+
+	// an outdated resolution is used if the channel cannot be resolved
+	cached := readChannelCache(cachePath)
+	r = cached[ChannelStable]
+	r.ResolvedAt = r.ResolvedAt.Add(-time.Hour)
+	cached[ChannelStable] = r
+	require.NoError(t, writeChannelCache(cachePath, cached))
+	r, err = resolveChannel(offline, cachePath, noEnv, warn)
+	require.NoError(t, err)
+	require.Equal(t, "1.16.1", r.Version)
+	require.Len(t, warnings, 1)
+	require.Contains(t, warnings[0], "Unable to resolve the release channel 'stable', using the version 1.16.1")
+
+	// the environment variable pins the version
+	pinned := func(env string) (string, bool) { return map[string]string{"KYMACTL_RELEASE_STABLE": "1.15.0"}[env], true }
+	r, err = resolveChannel(offline, cachePath, pinned, warn)
+	require.NoError(t, err)
+	require.Equal(t, Resolution{Channel: ChannelStable, Version: "1.15.0", ResolvedAt: r.ResolvedAt, Pinned: true}, r)
+}
+
+func TestChannel(t *testing.T) {
+	t.Parallel()
+	require.Equal(t, []string{"stable", "canary", "nightly"}, ChannelNames())
+	_, err := channel("beta")
+	require.EqualError(t, err, "unknown release channel 'beta', use one of: stable, canary, nightly")
+
+	require.Equal(t, "https://storage.googleapis.com/kyma-prow-artifacts/1.16.0", Resolution{Channel: ChannelStable, Version: "1.16.0"}.Artifacts())
+	require.Equal(t, "https://storage.googleapis.com/kyma-development-artifacts/master-34edf09a", Resolution{Channel: ChannelNightly, Version: "34edf09a"}.Artifacts())
+}
+
+func TestLatestNightly(t *testing.T) {
+	// not parallel: the package level commits URL is modified
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/commits":
+			fmt.Fprint(w, `[{"sha": "aaaaaaaa1111"}, {"sha": "bbbbbbbb2222"}, {"sha": "cccccccc3333"}]`)
+		case "/master-bbbbbbbb/kyma-installer-cluster.yaml", "/master-cccccccc/kyma-installer-cluster.yaml":
+			w.WriteHeader(http.StatusOK)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+	defaultURL := commitsURL
+	commitsURL = srv.URL + "/commits"
+	defer func() { commitsURL = defaultURL }()
+
+	hash, err := latestNightly(Channel{Name: ChannelNightly, ArtifactsURL: srv.URL + "/master-%s"})
+	require.NoError(t, err)
+	require.Equal(t, "bbbbbbbb", hash, "the newest commit with artifacts is used")
+
+	_, err = latestNightly(Channel{Name: ChannelNightly, ArtifactsURL: srv.URL + "/missing-%s"})
+	require.EqualError(t, err, "none of the 3 newest master commits has published build artifacts")
+}
+
+func TestUpgradeTarget(t *testing.T) {
+	t.Parallel()
+	list := []Release{{Version: "1.17.0"}, {Version: "1.16.1"}, {Version: "1.16.0"}, {Version: "1.15.1"}}
+
+	target, ok := UpgradeTarget("1.15.0", list)
+	require.True(t, ok)
+	require.Equal(t, "1.16.1", target, "at most one minor version ahead")
+
+	target, ok = UpgradeTarget("1.16.0", list)
+	require.True(t, ok)
+	require.Equal(t, "1.17.0", target)
+
+	_, ok = UpgradeTarget("1.17.0", list)
+	require.False(t, ok)
+	_, ok = UpgradeTarget("master-34edf09a", list)
+	require.False(t, ok)
+}
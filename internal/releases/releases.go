@@ -0,0 +1,212 @@
+// Package releases provides the list of Kyma releases published on GitHub.
+package releases
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/blang/semver/v4"
+	"github.com/kyma-project/cli/internal/files"
+	"github.com/kyma-project/cli/internal/github"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+const (
+	// LocalConfigAsset is the release asset required to install a release on a local cluster
+	LocalConfigAsset = "kyma-config-local.yaml"
+
+	cacheFolder = "cache"
+	cacheFile   = "releases.json"
+	cacheTTL    = 1 * time.Hour
+
+	// Latest is the source which resolves to the newest stable Kyma release at runtime
+	Latest = "latest"
+)
+
+// releasesURL is the GitHub API endpoint listing the Kyma releases
+var releasesURL = "https://api.github.com/repos/kyma-project/kyma/releases?per_page=100"
+
+// Release describes a published Kyma release.
+type Release struct {
+	Version        string    `json:"version"`
+	PublishedAt    time.Time `json:"publishedAt"`
+	Prerelease     bool      `json:"prerelease"`
+	HasLocalConfig bool      `json:"hasLocalConfig"`
+}
+
+type cache struct {
+	FetchedAt time.Time `json:"fetchedAt"`
+	Releases  []Release `json:"releases"`
+}
+
+type githubRelease struct {
+	TagName     string    `json:"tag_name"`
+	PublishedAt time.Time `json:"published_at"`
+	Prerelease  bool      `json:"prerelease"`
+	Draft       bool      `json:"draft"`
+	Assets      []struct {
+		Name string `json:"name"`
+	} `json:"assets"`
+}
+
+// List returns the Kyma releases sorted from the newest to the oldest version.
+// Release candidates are only included if prerelease is set.
+// The releases are cached for an hour in the Kyma CLI home folder to avoid hitting the GitHub API rate limits.
+func List(prerelease bool) ([]Release, error) {
+	cachePath, err := cachePath()
+	if err != nil {
+		return nil, err
+	}
+	return list(cachePath, prerelease)
+}
+
+func list(cachePath string, prerelease bool) ([]Release, error) {
+	all, ok := readCache(cachePath)
+	if !ok {
+		var err error
+		if all, err = fetch(); err != nil {
+			return nil, err
+		}
+		// a failing cache only slows down the next call, so the error is ignored
+		_ = writeCache(cachePath, all)
+	}
+
+	var result []Release
+	for _, r := range all {
+		if r.Prerelease && !prerelease {
+			continue
+		}
+		result = append(result, r)
+	}
+	return result, nil
+}
+
+func fetch() ([]Release, error) {
+	resp, err := github.NewClient(10 * time.Second).Get(releasesURL)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to fetch the Kyma releases")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unable to fetch the Kyma releases, response: %v", resp.Status)
+	}
+
+	var ghReleases []githubRelease
+	if err := json.NewDecoder(resp.Body).Decode(&ghReleases); err != nil {
+		return nil, errors.Wrap(err, "unable to read the Kyma releases")
+	}
+
+	var releases []Release
+	for _, ghr := range ghReleases {
+		if ghr.Draft {
+			continue
+		}
+		r := Release{
+			Version:     ghr.TagName,
+			PublishedAt: ghr.PublishedAt,
+			Prerelease:  ghr.Prerelease,
+		}
+		for _, a := range ghr.Assets {
+			if a.Name == LocalConfigAsset {
+				r.HasLocalConfig = true
+			}
+		}
+		releases = append(releases, r)
+	}
+	sortReleases(releases)
+	return releases, nil
+}
+
+// sortReleases orders the releases by semantic version (newest first), falling back to the publishing date
+func sortReleases(releases []Release) {
+	sort.SliceStable(releases, func(i, j int) bool {
+		vi, errI := semver.ParseTolerant(releases[i].Version)
+		vj, errJ := semver.ParseTolerant(releases[j].Version)
+		if errI == nil && errJ == nil {
+			return vi.GT(vj)
+		}
+		return releases[i].PublishedAt.After(releases[j].PublishedAt)
+	})
+}
+
+func cachePath() (string, error) {
+	home, err := files.KymaHome()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, cacheFolder, cacheFile), nil
+}
+
+func readCache(path string) ([]Release, bool) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, false
+	}
+	c := cache{}
+	if err := json.Unmarshal(data, &c); err != nil || time.Since(c.FetchedAt) > cacheTTL {
+		return nil, false
+	}
+	return c.Releases, true
+}
+
+func writeCache(path string, releases []Release) error {
+	data, err := json.Marshal(cache{FetchedAt: time.Now(), Releases: releases})
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, data, 0600)
+}
+
+// LatestVersion returns the version of the newest stable Kyma release
+func LatestVersion() (string, error) {
+	releases, err := List(false)
+	if err != nil {
+		return "", err
+	}
+	if len(releases) == 0 {
+		return "", errors.New("no stable Kyma release found")
+	}
+	return releases[0].Version, nil
+}
+
+// ResolveLatest returns the version of the newest stable Kyma release.
+// If it cannot be determined, e.g. when GitHub is not reachable, the fallback version is returned and the reason is passed to warn.
+func ResolveLatest(fallback string, warn func(format string, args ...interface{})) (string, error) {
+	return resolveLatest(LatestVersion, fallback, warn)
+}
+
+func resolveLatest(latest func() (string, error), fallback string, warn func(format string, args ...interface{})) (string, error) {
+	version, err := latest()
+	if err == nil {
+		return version, nil
+	}
+	if fallback == "" || fallback == Latest {
+		return "", errors.Wrap(err, "unable to resolve the latest Kyma release")
+	}
+	warn("Unable to resolve the latest Kyma release, using the default version %s instead: %s", fallback, err)
+	return fallback, nil
+}
+
+// CompleteVersions suggests the Kyma release versions for shell completion of flags such as --source
+func CompleteVersions(_ *cobra.Command, _ []string, _ string) ([]string, cobra.ShellCompDirective) {
+	releases, err := List(false)
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	var versions []string
+	for _, r := range releases {
+		versions = append(versions, r.Version)
+	}
+	return versions, cobra.ShellCompDirectiveNoFileComp
+}
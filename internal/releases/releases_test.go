@@ -0,0 +1,84 @@
+package releases
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const githubResponse = `[
+	{"tag_name": "1.16.0", "published_at": "2020-10-01T10:00:00Z", "assets": [{"name": "kyma-config-local.yaml"}]},
+	{"tag_name": "1.17.0-rc1", "published_at": "2020-10-20T10:00:00Z", "prerelease": true, "assets": []},
+	{"tag_name": "1.16.1", "published_at": "2020-10-10T10:00:00Z", "assets": [{"name": "kyma-installer-cluster.yaml"}]},
+	{"tag_name": "1.18.0", "published_at": "2020-11-01T10:00:00Z", "draft": true}
+]`
+
+func TestList(t *testing.T) {
+	// not parallel: the package level releases URL is modified
+	calls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		fmt.Fprint(w, githubResponse)
+	}))
+	defer srv.Close()
+	defaultURL := releasesURL
+	releasesURL = srv.URL
+	defer func() { releasesURL = defaultURL }()
+
+	dir, err := ioutil.TempDir("", "kyma-releases")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	cachePath := filepath.Join(dir, "cache", cacheFile)
+
+	releases, err := list(cachePath, false)
+	require.NoError(t, err)
+	require.Len(t, releases, 2)
+	require.Equal(t, "1.16.1", releases[0].Version)
+	require.False(t, releases[0].HasLocalConfig)
+	require.Equal(t, "1.16.0", releases[1].Version)
+	require.True(t, releases[1].HasLocalConfig)
+
+	// second call is served from the cache and includes the release candidates
+	releases, err = list(cachePath, true)
+	require.NoError(t, err)
+	require.Len(t, releases, 3)
+	require.Equal(t, "1.17.0-rc1", releases[0].Version)
+	require.Equal(t, 1, calls)
+
+	// failing API
+	srv.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	})
+	_, err = list(filepath.Join(dir, "other-cache.json"), false)
+	require.Error(t, err)
+}
+
+func TestResolveLatest(t *testing.T) {
+	t.Parallel()
+	var warnings []string
+	warn := func(format string, args ...interface{}) {
+		warnings = append(warnings, fmt.Sprintf(format, args...))
+	}
+	online := func() (string, error) { return "1.16.1", nil }
+	offline := func() (string, error) { return "", errors.New("no network") }
+
+	version, err := resolveLatest(online, "1.15.0", warn)
+	require.NoError(t, err)
+	require.Equal(t, "1.16.1", version)
+	require.Empty(t, warnings)
+
+	version, err = resolveLatest(offline, "1.15.0", warn)
+	require.NoError(t, err)
+	require.Equal(t, "1.15.0", version)
+	require.Equal(t, []string{"Unable to resolve the latest Kyma release, using the default version 1.15.0 instead: no network"}, warnings)
+
+	_, err = resolveLatest(offline, "", warn)
+	require.EqualError(t, err, "unable to resolve the latest Kyma release: no network")
+}
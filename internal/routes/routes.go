@@ -0,0 +1,149 @@
+// Package routes reads the IPv4 routes of the local machine, for example to find the networks of a VPN.
+// The routing table is read per OS: from /proc/net/route on Linux, with netstat on macOS, and with route on Windows.
+package routes
+
+import (
+	"bufio"
+	"encoding/hex"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// commandTimeout is the maximum time the command printing the routing table may take
+const commandTimeout = 10 * time.Second
+
+// Overlaps checks if the two networks share addresses
+func Overlaps(a, b *net.IPNet) bool {
+	return a.Contains(b.IP) || b.Contains(a.IP)
+}
+
+// relevant filters the routes which never conflict with the networks of a cluster: default routes, and routes of the loopback, link-local and multicast ranges.
+// Duplicate routes, for example of several interfaces, are removed.
+func relevant(routes []*net.IPNet) []*net.IPNet {
+	seen := map[string]bool{}
+	var result []*net.IPNet
+	for _, r := range routes {
+		if ones, _ := r.Mask.Size(); ones == 0 {
+			continue
+		}
+		if r.IP.IsLoopback() || r.IP.IsLinkLocalUnicast() || r.IP.IsMulticast() || r.IP.Equal(net.IPv4bcast) {
+			continue
+		}
+		if seen[r.String()] {
+			continue
+		}
+		seen[r.String()] = true
+		result = append(result, r)
+	}
+	return result
+}
+
+// parseProcRoutes parses the routing table of Linux in the format of /proc/net/route, whose addresses are hex encoded in little endian byte order
+func parseProcRoutes(table string) ([]*net.IPNet, error) {
+	var routes []*net.IPNet
+	scanner := bufio.NewScanner(strings.NewReader(table))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 8 || fields[0] == "Iface" {
+			continue
+		}
+		ip, err := procAddress(fields[1])
+		if err != nil {
+			return nil, err
+		}
+		mask, err := procAddress(fields[7])
+		if err != nil {
+			return nil, err
+		}
+		routes = append(routes, &net.IPNet{IP: ip, Mask: net.IPMask(mask)})
+	}
+	return routes, scanner.Err()
+}
+
+func procAddress(field string) (net.IP, error) {
+	b, err := hex.DecodeString(field)
+	if err != nil || len(b) != net.IPv4len {
+		return nil, fmt.Errorf("invalid address '%s' in the routing table", field)
+	}
+	return net.IPv4(b[3], b[2], b[1], b[0]).To4(), nil
+}
+
+// parseNetstat parses the output of "netstat -rn -f inet" on macOS. The destinations omit trailing zero octets, e.g. "10/8", "192.168.1" or "127".
+func parseNetstat(out string) []*net.IPNet {
+	var routes []*net.IPNet
+	header := false
+	scanner := bufio.NewScanner(strings.NewReader(out))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 {
+			continue
+		}
+		if fields[0] == "Destination" {
+			header = true
+			continue
+		}
+		if !header {
+			continue
+		}
+		if r := bsdDestination(fields[0]); r != nil {
+			routes = append(routes, r)
+		}
+	}
+	return routes
+}
+
+// bsdDestination parses an abbreviated destination of the BSD netstat, it returns nil for "default" and destinations which are no IPv4 networks
+func bsdDestination(dest string) *net.IPNet {
+	addr, bits := dest, -1
+	if i := strings.Index(dest, "/"); i >= 0 {
+		var err error
+		if bits, err = strconv.Atoi(dest[i+1:]); err != nil {
+			return nil
+		}
+		addr = dest[:i]
+	}
+	// interface scoped destinations, such as "224.0.0/4%en0"
+	addr = strings.SplitN(addr, "%", 2)[0]
+
+	octets := strings.Split(addr, ".")
+	if len(octets) > net.IPv4len {
+		return nil
+	}
+	b := make([]byte, net.IPv4len)
+	for i, o := range octets {
+		n, err := strconv.ParseUint(o, 10, 8)
+		if err != nil {
+			return nil
+		}
+		b[i] = byte(n)
+	}
+	if bits < 0 {
+		bits = 8 * len(octets)
+	}
+	if bits > 32 {
+		return nil
+	}
+	mask := net.CIDRMask(bits, 32)
+	return &net.IPNet{IP: net.IP(b).Mask(mask), Mask: mask}
+}
+
+// parseRoutePrint parses the output of "route print -4" on Windows, the routes are listed with their destination and netmask
+func parseRoutePrint(out string) []*net.IPNet {
+	var routes []*net.IPNet
+	scanner := bufio.NewScanner(strings.NewReader(out))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 4 {
+			continue
+		}
+		ip, mask := net.ParseIP(fields[0]).To4(), net.ParseIP(fields[1]).To4()
+		if ip == nil || mask == nil {
+			continue
+		}
+		routes = append(routes, &net.IPNet{IP: ip.Mask(net.IPMask(mask)), Mask: net.IPMask(mask)})
+	}
+	return routes
+}
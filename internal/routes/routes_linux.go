@@ -0,0 +1,24 @@
+// +build linux
+
+package routes
+
+import (
+	"io/ioutil"
+	"net"
+
+	"github.com/pkg/errors"
+)
+
+// Local returns the IPv4 networks the local machine has routes for, such as the networks of a VPN.
+// Default routes and the loopback, link-local and multicast ranges are not returned.
+func Local() ([]*net.IPNet, error) {
+	table, err := ioutil.ReadFile("/proc/net/route")
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to read the routing table")
+	}
+	routes, err := parseProcRoutes(string(table))
+	if err != nil {
+		return nil, err
+	}
+	return relevant(routes), nil
+}
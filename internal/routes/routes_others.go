@@ -0,0 +1,14 @@
+// +build !linux,!darwin,!windows
+
+package routes
+
+import (
+	"fmt"
+	"net"
+	"runtime"
+)
+
+// Local returns the IPv4 networks the local machine has routes for. Reading the routing table is not supported on this OS.
+func Local() ([]*net.IPNet, error) {
+	return nil, fmt.Errorf("reading the routing table is not supported on %s", runtime.GOOS)
+}
@@ -0,0 +1,88 @@
+package routes
+
+import (
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func networks(routes []*net.IPNet) []string {
+	var result []string
+	for _, r := range routes {
+		result = append(result, r.String())
+	}
+	return result
+}
+
+func TestParseProcRoutes(t *testing.T) {
+	t.Parallel()
+	routes, err := parseProcRoutes(`Iface	Destination	Gateway 	Flags	RefCnt	Use	Metric	Mask		MTU	Window	IRTT
+eth0	00000000	0101A8C0	0003	0	0	100	00000000	0	0	0
+eth0	0001A8C0	00000000	0001	0	0	100	00FFFFFF	0	0	0
+tun0	0000000A	00000000	0001	0	0	0	000000FF	0	0	0
+docker0	000011AC	00000000	0001	0	0	0	0000FFFF	0	0	0
+`)
+	require.NoError(t, err)
+	require.Equal(t, []string{"0.0.0.0/0", "192.168.1.0/24", "10.0.0.0/8", "172.17.0.0/16"}, networks(routes))
+	require.Equal(t, []string{"192.168.1.0/24", "10.0.0.0/8", "172.17.0.0/16"}, networks(relevant(routes)), "the default route is dropped")
+
+	_, err = parseProcRoutes("eth0	invalid	00000000	0001	0	0	0	00FFFFFF	0	0	0")
+	require.Error(t, err)
+}
+
+func TestParseNetstat(t *testing.T) {
+	t.Parallel()
+	routes := parseNetstat(`Routing tables
+
+Internet:
+Destination        Gateway            Flags        Netif Expire
+default            192.168.1.1        UGScg          en0
+10/8               10.8.0.1           UGSc         utun3
+10.8.0.1           10.8.0.5           UH           utun3
+127                127.0.0.1          UCS            lo0
+169.254            link#6             UCS            en0      !
+172.16/12          10.8.0.1           UGSc         utun3
+192.168.1          link#6             UCS            en0      !
+224.0.0/4          link#6             UmCS           en0      !
+`)
+	require.Equal(t, []string{"10.0.0.0/8", "10.8.0.1/32", "127.0.0.0/8", "169.254.0.0/16", "172.16.0.0/12", "192.168.1.0/24", "224.0.0.0/4"}, networks(routes))
+	require.Equal(t, []string{"10.0.0.0/8", "10.8.0.1/32", "172.16.0.0/12", "192.168.1.0/24"}, networks(relevant(routes)))
+}
+
+func TestParseRoutePrint(t *testing.T) {
+	t.Parallel()
+	routes := parseRoutePrint(`===========================================================================
+Interface List
+ 12...00 15 5d 01 02 03 ......Hyper-V Virtual Ethernet Adapter
+===========================================================================
+
+IPv4 Route Table
+===========================================================================
+Active Routes:
+Network Destination        Netmask          Gateway       Interface  Metric
+          0.0.0.0          0.0.0.0      192.168.1.1    192.168.1.10     25
+         10.0.0.0        255.0.0.0         On-link        10.8.0.5    281
+        127.0.0.0        255.0.0.0         On-link         127.0.0.1    331
+      192.168.1.0    255.255.255.0         On-link      192.168.1.10    281
+  255.255.255.255  255.255.255.255         On-link         127.0.0.1    331
+===========================================================================
+Persistent Routes:
+  Network Address          Netmask  Gateway Address  Metric
+         10.0.0.0        255.0.0.0         10.8.0.1       1
+`)
+	require.Equal(t, []string{"10.0.0.0/8", "192.168.1.0/24"}, networks(relevant(routes)))
+}
+
+func TestOverlaps(t *testing.T) {
+	t.Parallel()
+	parse := func(cidr string) *net.IPNet {
+		_, n, err := net.ParseCIDR(cidr)
+		require.NoError(t, err)
+		return n
+	}
+	require.True(t, Overlaps(parse("10.96.0.0/12"), parse("10.0.0.0/8")))
+	require.True(t, Overlaps(parse("10.0.0.0/8"), parse("10.96.0.0/12")))
+	require.True(t, Overlaps(parse("10.244.0.0/16"), parse("10.244.3.7/32")))
+	require.False(t, Overlaps(parse("10.96.0.0/12"), parse("172.16.0.0/12")))
+}
@@ -0,0 +1,20 @@
+// +build windows
+
+package routes
+
+import (
+	"net"
+
+	"github.com/kyma-project/cli/internal/cli"
+	"github.com/pkg/errors"
+)
+
+// Local returns the IPv4 networks the local machine has routes for, such as the networks of a VPN.
+// Default routes and the loopback, link-local and multicast ranges are not returned.
+func Local() ([]*net.IPNet, error) {
+	out, err := cli.RunCmdWithTimeout(commandTimeout, "route", "print", "-4")
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to read the routing table with route print")
+	}
+	return relevant(parseRoutePrint(out)), nil
+}
@@ -0,0 +1,287 @@
+// Package selfupdate checks for newer releases of the Kyma CLI and replaces the running executable with the binary of a release.
+package selfupdate
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/blang/semver/v4"
+	"github.com/pkg/errors"
+)
+
+// checksumsAsset lists the SHA-256 checksums of the release assets in the format of sha256sum
+const checksumsAsset = "checksums.txt"
+
+var (
+	// latestURL is the GitHub API endpoint of the newest Kyma CLI release
+	latestURL = "https://api.github.com/repos/kyma-project/cli/releases/latest"
+	// rename moves the binaries, it is replaced in tests to simulate failures
+	rename = os.Rename
+)
+
+// Release is a published release of the Kyma CLI
+type Release struct {
+	Version string
+	URL     string
+	// Assets maps the names of the release assets to their download URLs
+	Assets map[string]string
+}
+
+type githubRelease struct {
+	TagName string `json:"tag_name"`
+	HTMLURL string `json:"html_url"`
+	Assets  []struct {
+		Name string `json:"name"`
+		URL  string `json:"browser_download_url"`
+	} `json:"assets"`
+}
+
+// Latest returns the newest release of the Kyma CLI published on GitHub
+func Latest(client *http.Client) (Release, error) {
+	resp, err := client.Get(latestURL)
+	if err != nil {
+		return Release{}, errors.Wrap(err, "unable to fetch the Kyma CLI releases")
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return Release{}, fmt.Errorf("unable to fetch the Kyma CLI releases: %s", resp.Status)
+	}
+	var gr githubRelease
+	if err := json.NewDecoder(resp.Body).Decode(&gr); err != nil {
+		return Release{}, errors.Wrap(err, "unable to parse the Kyma CLI releases")
+	}
+	r := Release{Version: strings.TrimPrefix(gr.TagName, "v"), URL: gr.HTMLURL, Assets: map[string]string{}}
+	for _, a := range gr.Assets {
+		r.Assets[a.Name] = a.URL
+	}
+	return r, nil
+}
+
+// Newer checks if the release version is newer than the current version. Development builds without a release version are never updated.
+func Newer(current, release string) (bool, error) {
+	c, err := semver.Parse(strings.TrimPrefix(current, "v"))
+	if err != nil {
+		return false, fmt.Errorf("the version '%s' of this Kyma CLI is not a release version", current)
+	}
+	r, err := semver.Parse(strings.TrimPrefix(release, "v"))
+	if err != nil {
+		return false, fmt.Errorf("invalid version '%s' of the Kyma CLI release", release)
+	}
+	return r.GT(c), nil
+}
+
+// AssetName returns the name of the archive holding the binary for the platform, e.g. kyma_Linux_x86_64.tar.gz
+func AssetName(goos, goarch string) string {
+	arch := goarch
+	switch goarch {
+	case "amd64":
+		arch = "x86_64"
+	case "386":
+		arch = "i386"
+	}
+	name := fmt.Sprintf("kyma_%s_%s", strings.ToUpper(goos[:1])+goos[1:], arch)
+	if goos == "windows" {
+		return name + ".zip"
+	}
+	return name + ".tar.gz"
+}
+
+// Method is the way the Kyma CLI was installed, it determines how it is updated
+type Method string
+
+const (
+	// Homebrew installations are updated with brew
+	Homebrew Method = "Homebrew"
+	// Scoop installations are updated with scoop
+	Scoop Method = "Scoop"
+	// Manual installations are binaries downloaded from the GitHub releases, they can replace themselves
+	Manual Method = "manual download"
+)
+
+// DetectMethod returns the install method of the executable, read from its resolved path
+func DetectMethod(executable string) Method {
+	p := strings.ToLower(strings.ReplaceAll(executable, `\`, "/"))
+	switch {
+	case strings.Contains(p, "/cellar/"), strings.Contains(p, "/homebrew/"), strings.Contains(p, "/linuxbrew/"):
+		return Homebrew
+	case strings.Contains(p, "/scoop/"):
+		return Scoop
+	}
+	return Manual
+}
+
+// Instructions explains how to update an installation of the given method to the release
+func Instructions(m Method, r Release) string {
+	switch m {
+	case Homebrew:
+		return "Run: brew upgrade kyma-cli"
+	case Scoop:
+		return "Run: scoop update kyma-cli"
+	}
+	return fmt.Sprintf("Run \"kyma update-cli --apply\", or download the binary for your platform from %s", r.URL)
+}
+
+// CheckWritable ensures that the executable can be replaced, which requires creating files in its directory
+func CheckWritable(executable string) error {
+	f, err := ioutil.TempFile(filepath.Dir(executable), ".kyma-update-")
+	if err != nil {
+		return fmt.Errorf("the Kyma CLI cannot replace itself, as '%s' is read-only: %s", filepath.Dir(executable), err)
+	}
+	f.Close()
+	return os.Remove(f.Name())
+}
+
+// Apply downloads the binary of the release for the current platform, verifies it with the published checksums, and replaces the executable.
+// The executable is renamed to a backup first, which is restored if the new binary cannot be moved into place.
+func Apply(client *http.Client, r Release, executable string) error {
+	return apply(client, r, executable, AssetName(runtime.GOOS, runtime.GOARCH))
+}
+
+func apply(client *http.Client, r Release, executable, asset string) error {
+	if err := CheckWritable(executable); err != nil {
+		return err
+	}
+	archiveURL, ok := r.Assets[asset]
+	if !ok {
+		return fmt.Errorf("the Kyma CLI %s publishes no binary for this platform (%s)", r.Version, asset)
+	}
+	checksumsURL, ok := r.Assets[checksumsAsset]
+	if !ok {
+		return fmt.Errorf("the Kyma CLI %s publishes no checksums, the binary cannot be verified", r.Version)
+	}
+
+	archive, err := download(client, archiveURL)
+	if err != nil {
+		return err
+	}
+	checksums, err := download(client, checksumsURL)
+	if err != nil {
+		return err
+	}
+	if err := verify(archive, checksums, asset); err != nil {
+		return err
+	}
+	binary, err := extractBinary(archive, asset)
+	if err != nil {
+		return err
+	}
+	return replace(executable, binary)
+}
+
+func download(client *http.Client, url string) ([]byte, error) {
+	resp, err := client.Get(url)
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to download '%s'", url)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unable to download '%s': %s", url, resp.Status)
+	}
+	return ioutil.ReadAll(resp.Body)
+}
+
+// verify compares the SHA-256 checksum of the archive with the one listed for the asset
+func verify(archive, checksums []byte, asset string) error {
+	sum := sha256.Sum256(archive)
+	scanner := bufio.NewScanner(bytes.NewReader(checksums))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 2 || strings.TrimPrefix(fields[1], "*") != asset {
+			continue
+		}
+		if !strings.EqualFold(fields[0], hex.EncodeToString(sum[:])) {
+			return fmt.Errorf("the checksum of '%s' does not match the published one, the download is corrupted or was tampered with", asset)
+		}
+		return nil
+	}
+	return fmt.Errorf("no checksum is published for '%s'", asset)
+}
+
+// extractBinary returns the kyma binary of the tar.gz or zip archive
+func extractBinary(archive []byte, asset string) ([]byte, error) {
+	if strings.HasSuffix(asset, ".zip") {
+		zr, err := zip.NewReader(bytes.NewReader(archive), int64(len(archive)))
+		if err != nil {
+			return nil, errors.Wrapf(err, "unable to open '%s'", asset)
+		}
+		for _, f := range zr.File {
+			if path.Base(f.Name) == "kyma.exe" {
+				rc, err := f.Open()
+				if err != nil {
+					return nil, err
+				}
+				defer rc.Close()
+				return ioutil.ReadAll(rc)
+			}
+		}
+		return nil, fmt.Errorf("'%s' does not contain kyma.exe", asset)
+	}
+
+	gz, err := gzip.NewReader(bytes.NewReader(archive))
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to open '%s'", asset)
+	}
+	tr := tar.NewReader(gz)
+	for {
+		h, err := tr.Next()
+		if err == io.EOF {
+			return nil, fmt.Errorf("'%s' does not contain the kyma binary", asset)
+		}
+		if err != nil {
+			return nil, errors.Wrapf(err, "unable to read '%s'", asset)
+		}
+		if h.Typeflag == tar.TypeReg && path.Base(h.Name) == "kyma" {
+			return ioutil.ReadAll(tr)
+		}
+	}
+}
+
+// replace moves the binary into the place of the executable. The new binary is written next to the executable first, so that the final renames stay on one file system.
+func replace(executable string, binary []byte) error {
+	dir := filepath.Dir(executable)
+	tmp, err := ioutil.TempFile(dir, ".kyma-update-")
+	if err != nil {
+		return errors.Wrap(err, "unable to write the new binary")
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(binary); err != nil {
+		tmp.Close()
+		return errors.Wrap(err, "unable to write the new binary")
+	}
+	if err := tmp.Close(); err != nil {
+		return errors.Wrap(err, "unable to write the new binary")
+	}
+	if err := os.Chmod(tmp.Name(), 0755); err != nil {
+		return errors.Wrap(err, "unable to make the new binary executable")
+	}
+
+	backup := fmt.Sprintf("%s.%d.old", executable, time.Now().Unix())
+	if err := rename(executable, backup); err != nil {
+		return errors.Wrap(err, "unable to back up the current binary")
+	}
+	if err := rename(tmp.Name(), executable); err != nil {
+		if rollbackErr := rename(backup, executable); rollbackErr != nil {
+			return fmt.Errorf("unable to replace the binary (%s) and to restore it (%s), the previous binary is kept as '%s'", err, rollbackErr, backup)
+		}
+		return errors.Wrap(err, "unable to replace the binary, the previous binary was restored")
+	}
+	// a running executable cannot be removed on Windows, the backup is left behind there
+	_ = os.Remove(backup)
+	return nil
+}
@@ -0,0 +1,173 @@
+package selfupdate
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewer(t *testing.T) {
+	t.Parallel()
+	newer, err := Newer("1.17.0", "v1.18.1")
+	require.NoError(t, err)
+	require.True(t, newer)
+	newer, err = Newer("v1.18.1", "1.18.1")
+	require.NoError(t, err)
+	require.False(t, newer)
+	newer, err = Newer("1.18.0", "1.18.0-rc1")
+	require.NoError(t, err)
+	require.False(t, newer)
+
+	_, err = Newer("", "1.18.1")
+	require.EqualError(t, err, "the version '' of this Kyma CLI is not a release version")
+	_, err = Newer("1.17.0", "nightly")
+	require.Error(t, err)
+}
+
+func TestAssetName(t *testing.T) {
+	t.Parallel()
+	require.Equal(t, "kyma_Linux_x86_64.tar.gz", AssetName("linux", "amd64"))
+	require.Equal(t, "kyma_Darwin_arm64.tar.gz", AssetName("darwin", "arm64"))
+	require.Equal(t, "kyma_Windows_x86_64.zip", AssetName("windows", "amd64"))
+}
+
+func TestDetectMethod(t *testing.T) {
+	t.Parallel()
+	require.Equal(t, Homebrew, DetectMethod("/usr/local/Cellar/kyma-cli/1.17.0/bin/kyma"))
+	require.Equal(t, Homebrew, DetectMethod("/home/linuxbrew/.linuxbrew/bin/kyma"))
+	require.Equal(t, Scoop, DetectMethod(`C:\Users\dev\scoop\apps\kyma-cli\current\kyma.exe`))
+	require.Equal(t, Manual, DetectMethod("/usr/local/bin/kyma"))
+	require.Contains(t, Instructions(Homebrew, Release{}), "brew upgrade kyma-cli")
+	require.Contains(t, Instructions(Manual, Release{URL: "https://github.com/kyma-project/cli/releases/tag/1.18.1"}), "https://github.com/kyma-project/cli/releases/tag/1.18.1")
+}
+
+func TestLatest(t *testing.T) {
+	// not parallel: the package level URL is modified
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"tag_name":"1.18.1","html_url":"https://github.com/kyma-project/cli/releases/tag/1.18.1","assets":[{"name":"checksums.txt","browser_download_url":"https://example.com/checksums.txt"}]}`))
+	}))
+	defer server.Close()
+	defer func(url string) { latestURL = url }(latestURL)
+	latestURL = server.URL
+
+	r, err := Latest(server.Client())
+	require.NoError(t, err)
+	require.Equal(t, Release{
+		Version: "1.18.1",
+		URL:     "https://github.com/kyma-project/cli/releases/tag/1.18.1",
+		Assets:  map[string]string{"checksums.txt": "https://example.com/checksums.txt"},
+	}, r)
+}
+
+func TestApply(t *testing.T) {
+	t.Parallel()
+	archive := &bytes.Buffer{}
+	gz := gzip.NewWriter(archive)
+	tw := tar.NewWriter(gz)
+	for name, content := range map[string]string{"LICENSE": "Apache", "kyma": "new binary"} {
+		require.NoError(t, tw.WriteHeader(&tar.Header{Name: name, Mode: 0755, Size: int64(len(content)), Typeflag: tar.TypeReg}))
+		_, err := tw.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, tw.Close())
+	require.NoError(t, gz.Close())
+	sum := sha256.Sum256(archive.Bytes())
+	checksums := fmt.Sprintf("%s  kyma_Linux_x86_64.tar.gz\n%s  kyma_Darwin_x86_64.tar.gz\n", hex.EncodeToString(sum[:]), hex.EncodeToString(make([]byte, 32)))
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/checksums.txt":
+			_, _ = w.Write([]byte(checksums))
+		default:
+			_, _ = w.Write(archive.Bytes())
+		}
+	}))
+	defer server.Close()
+	release := Release{Version: "1.18.1", Assets: map[string]string{
+		"kyma_Linux_x86_64.tar.gz":  server.URL + "/kyma_Linux_x86_64.tar.gz",
+		"kyma_Darwin_x86_64.tar.gz": server.URL + "/kyma_Darwin_x86_64.tar.gz",
+		"checksums.txt":             server.URL + "/checksums.txt",
+	}}
+
+	dir, err := ioutil.TempDir("", "kyma-update")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	executable := filepath.Join(dir, "kyma")
+	require.NoError(t, ioutil.WriteFile(executable, []byte("old binary"), 0755))
+
+	// a checksum which does not match leaves the executable untouched
+	err = apply(server.Client(), release, executable, "kyma_Darwin_x86_64.tar.gz")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "does not match the published one")
+	data, err := ioutil.ReadFile(executable)
+	require.NoError(t, err)
+	require.Equal(t, "old binary", string(data))
+
+	err = apply(server.Client(), release, executable, "kyma_Windows_x86_64.zip")
+	require.EqualError(t, err, "the Kyma CLI 1.18.1 publishes no binary for this platform (kyma_Windows_x86_64.zip)")
+
+	require.NoError(t, apply(server.Client(), release, executable, "kyma_Linux_x86_64.tar.gz"))
+	data, err = ioutil.ReadFile(executable)
+	require.NoError(t, err)
+	require.Equal(t, "new binary", string(data))
+	info, err := os.Stat(executable)
+	require.NoError(t, err)
+	require.Equal(t, os.FileMode(0755), info.Mode().Perm())
+	files, err := ioutil.ReadDir(dir)
+	require.NoError(t, err)
+	require.Len(t, files, 1, "neither the backup nor the download must be left behind")
+}
+
+func TestReplaceRollback(t *testing.T) {
+	// not parallel: the package level rename is modified
+	defer func(r func(string, string) error) { rename = r }(rename)
+	rename = func(from, to string) error {
+		if filepath.Base(to) == "kyma" && filepath.Ext(from) != ".old" {
+			return errors.New("text file busy")
+		}
+		return os.Rename(from, to)
+	}
+	dir, err := ioutil.TempDir("", "kyma-update")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	executable := filepath.Join(dir, "kyma")
+	require.NoError(t, ioutil.WriteFile(executable, []byte("old binary"), 0755))
+
+	err = replace(executable, []byte("new binary"))
+	require.EqualError(t, err, "unable to replace the binary, the previous binary was restored: text file busy")
+	data, err := ioutil.ReadFile(executable)
+	require.NoError(t, err)
+	require.Equal(t, "old binary", string(data))
+	files, err := ioutil.ReadDir(dir)
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+}
+
+func TestCheckWritable(t *testing.T) {
+	t.Parallel()
+	if os.Geteuid() == 0 {
+		t.Skip("read-only directories are writable for root")
+	}
+	dir, err := ioutil.TempDir("", "kyma-update")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	require.NoError(t, CheckWritable(filepath.Join(dir, "kyma")))
+
+	require.NoError(t, os.Chmod(dir, 0500))
+	defer os.Chmod(dir, 0700)
+	err = CheckWritable(filepath.Join(dir, "kyma"))
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "is read-only")
+}
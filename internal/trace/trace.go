@@ -0,0 +1,256 @@
+// Package trace records the timings of the CLI as spans (such as stages, executed commands, and polls of the installation state)
+// and writes them as a Chrome trace-viewer file, which can be opened with chrome://tracing or https://ui.perfetto.dev.
+package trace
+
+import (
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/kyma-project/cli/internal/redact"
+)
+
+// Attributes hold additional details of a span, such as the arguments of a command
+type Attributes map[string]interface{}
+
+// Span is a timed operation of the CLI
+type Span struct {
+	ID int
+	// Parent is the ID of the innermost span opened by Start when the span started, 0 for the root span.
+	Parent     int
+	Name       string
+	Start      time.Time
+	Duration   time.Duration
+	Attributes Attributes
+	// Error holds the error the operation failed with.
+	Error string
+
+	tracer *Tracer
+}
+
+// Tracer collects the spans of one CLI invocation
+type Tracer struct {
+	mu     sync.Mutex
+	nextID int
+	// open holds the spans opened by Start which are not ended yet, the last one is the parent of new spans
+	open  []*Span
+	spans []*Span
+}
+
+// New creates an empty tracer
+func New() *Tracer {
+	return &Tracer{}
+}
+
+var (
+	current *Tracer
+	file    string
+	lock    sync.RWMutex
+)
+
+// Enable records the spans of the CLI from now on, they are written to the file by Flush
+func Enable(path string) *Tracer {
+	lock.Lock()
+	defer lock.Unlock()
+	current, file = New(), path
+	return current
+}
+
+// Enabled checks if the spans are recorded, so that expensive attributes are only collected when needed
+func Enabled() bool {
+	lock.RLock()
+	defer lock.RUnlock()
+	return current != nil
+}
+
+// Start opens a span as child of the innermost open span. It returns nil if tracing is not enabled, which can be ended as well.
+// Spans opened by Start become the parent of all spans started until they end, regardless of the goroutine, so Start is meant for the sequential parts
+// of the invocation like the command and its stages. Operations which may run concurrently are added with Record.
+func Start(name string, attrs Attributes) *Span {
+	lock.RLock()
+	t := current
+	lock.RUnlock()
+	if t == nil {
+		return nil
+	}
+	return t.Start(name, attrs)
+}
+
+// Record adds a span which already finished, for example a command whose start time was taken before it ran.
+// The span is never the parent of other spans, so it can be recorded from any goroutine.
+func Record(name string, started time.Time, err error, attrs Attributes) {
+	lock.RLock()
+	t := current
+	lock.RUnlock()
+	if t == nil {
+		return
+	}
+	t.Record(name, started, err, attrs)
+}
+
+// Flush ends all open spans with the error of the CLI and writes the trace file. Nothing is done if tracing is not enabled.
+func Flush(err error) error {
+	lock.Lock()
+	t, path := current, file
+	current = nil
+	lock.Unlock()
+	if t == nil {
+		return nil
+	}
+	t.endAll(err)
+	f, createErr := os.Create(path)
+	if createErr != nil {
+		return createErr
+	}
+	if writeErr := t.WriteChrome(f); writeErr != nil {
+		f.Close()
+		return writeErr
+	}
+	return f.Close()
+}
+
+// Start opens a span as child of the innermost open span of the tracer
+func (t *Tracer) Start(name string, attrs Attributes) *Span {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.nextID++
+	s := &Span{ID: t.nextID, Name: name, Start: time.Now(), Attributes: attrs, tracer: t}
+	if len(t.open) > 0 {
+		s.Parent = t.open[len(t.open)-1].ID
+	}
+	t.open = append(t.open, s)
+	return s
+}
+
+// Record adds a finished span as child of the innermost open span of the tracer
+func (t *Tracer) Record(name string, started time.Time, err error, attrs Attributes) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.nextID++
+	s := &Span{ID: t.nextID, Name: name, Start: started, Duration: time.Since(started), Attributes: attrs, tracer: t}
+	if len(t.open) > 0 {
+		s.Parent = t.open[len(t.open)-1].ID
+	}
+	if err != nil {
+		s.Error = err.Error()
+	}
+	t.spans = append(t.spans, s)
+}
+
+// End finishes the span, err is recorded if the operation failed. Ending a nil span does nothing.
+func (s *Span) End(err error) {
+	if s == nil {
+		return
+	}
+	t := s.tracer
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for n, o := range t.open {
+		if o == s {
+			t.open = append(t.open[:n], t.open[n+1:]...)
+			s.Duration = time.Since(s.Start)
+			if err != nil {
+				s.Error = err.Error()
+			}
+			t.spans = append(t.spans, s)
+			return
+		}
+	}
+}
+
+// Spans returns the ended spans in the order they ended
+func (t *Tracer) Spans() []Span {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	spans := make([]Span, 0, len(t.spans))
+	for _, s := range t.spans {
+		spans = append(spans, *s)
+	}
+	return spans
+}
+
+func (t *Tracer) endAll(err error) {
+	t.mu.Lock()
+	open := append([]*Span(nil), t.open...)
+	t.mu.Unlock()
+	// the innermost spans end first, so that all of them end within their parents
+	for n := len(open) - 1; n >= 0; n-- {
+		open[n].End(err)
+	}
+}
+
+// chromeEvent is a complete event of the Chrome trace event format
+type chromeEvent struct {
+	Name      string                 `json:"name"`
+	Phase     string                 `json:"ph"`
+	Timestamp int64                  `json:"ts"`
+	Duration  int64                  `json:"dur"`
+	PID       int                    `json:"pid"`
+	TID       int                    `json:"tid"`
+	Args      map[string]interface{} `json:"args,omitempty"`
+}
+
+// WriteChrome writes the ended spans in the Chrome trace event format, the times are in microseconds
+func (t *Tracer) WriteChrome(w io.Writer) error {
+	events := []chromeEvent{}
+	for _, s := range t.Spans() {
+		args := map[string]interface{}{"id": s.ID, "parent": s.Parent}
+		for k, v := range s.Attributes {
+			args[k] = v
+		}
+		if s.Error != "" {
+			args["error"] = s.Error
+		}
+		events = append(events, chromeEvent{
+			Name:      s.Name,
+			Phase:     "X",
+			Timestamp: s.Start.UnixNano() / int64(time.Microsecond),
+			Duration:  int64(s.Duration / time.Microsecond),
+			PID:       1,
+			TID:       1,
+			Args:      args,
+		})
+	}
+	data, err := json.MarshalIndent(map[string]interface{}{"traceEvents": events, "displayTimeUnit": "ms"}, "", "  ")
+	if err != nil {
+		return err
+	}
+	// the arguments of the invocation might hold secrets like the GitHub token
+	_, err = w.Write(redact.Bytes(data))
+	return err
+}
+
+// ReadChrome reads the spans of a file written by WriteChrome, e.g. to analyze a trace in tests
+func ReadChrome(path string) ([]Span, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var doc struct {
+		TraceEvents []chromeEvent `json:"traceEvents"`
+	}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	var spans []Span
+	for _, e := range doc.TraceEvents {
+		s := Span{Name: e.Name, Start: time.Unix(0, e.Timestamp*int64(time.Microsecond)), Duration: time.Duration(e.Duration) * time.Microsecond, Attributes: Attributes{}}
+		for k, v := range e.Args {
+			switch k {
+			case "id":
+				s.ID = int(v.(float64))
+			case "parent":
+				s.Parent = int(v.(float64))
+			case "error":
+				s.Error, _ = v.(string)
+			default:
+				s.Attributes[k] = v
+			}
+		}
+		spans = append(spans, s)
+	}
+	return spans, nil
+}
@@ -0,0 +1,126 @@
+package trace
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/kyma-project/cli/internal/redact"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNesting(t *testing.T) {
+	tr := New()
+	root := tr.Start("kyma install", nil)
+	stage := tr.Start("stage: validating the configuration", Attributes{"skipped": false})
+	cmd := tr.Start("kubectl", Attributes{"args": []string{"get", "pods"}})
+	cmd.End(errors.New("failed"))
+	stage.End(nil)
+	sibling := tr.Start("stage: triggering the installation", nil)
+	sibling.End(nil)
+	root.End(nil)
+
+	spans := tr.Spans()
+	require.Len(t, spans, 4)
+	parents := map[string]int{}
+	for _, s := range spans {
+		parents[s.Name] = s.Parent
+	}
+	require.Equal(t, 0, parents["kyma install"])
+	require.Equal(t, root.ID, parents["stage: validating the configuration"])
+	require.Equal(t, stage.ID, parents["kubectl"])
+	require.Equal(t, root.ID, parents["stage: triggering the installation"], "a stage started after another one ended must not be its child")
+	require.Equal(t, "failed", spans[0].Error)
+}
+
+func TestConcurrentRecords(t *testing.T) {
+	tr := New()
+	root := tr.Start("kyma install", nil)
+	stage := tr.Start("stage: triggering the installation", nil)
+	var wg sync.WaitGroup
+	for n := 0; n < 10; n++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			tr.Record("kubectl", time.Now(), nil, nil)
+		}()
+	}
+	wg.Wait()
+	stage.End(nil)
+	root.End(nil)
+
+	for _, s := range tr.Spans() {
+		if s.Name == "kubectl" {
+			require.Equal(t, stage.ID, s.Parent, "a recorded span must never be the parent of a span of another goroutine")
+		}
+	}
+}
+
+func TestDisabled(t *testing.T) {
+	require.False(t, Enabled())
+	s := Start("kubectl", nil)
+	require.Nil(t, s)
+	s.End(nil) // ending the span of a disabled tracer must not panic
+	Record("kubectl", time.Now(), nil, nil)
+	require.NoError(t, Flush(nil))
+}
+
+func TestFlush(t *testing.T) {
+	dir, err := ioutil.TempDir("", "kyma-trace-")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	file := filepath.Join(dir, "trace.json")
+
+	Enable(file)
+	require.True(t, Enabled())
+	Start("kyma install", nil)
+	Start("stage: triggering the installation", nil)
+	Record("helm", time.Now().Add(-time.Second), nil, Attributes{"args": []string{"version"}})
+	require.NoError(t, Flush(errors.New("interrupted")))
+	require.False(t, Enabled(), "tracing must stop once the file is written")
+
+	data, err := ioutil.ReadFile(file)
+	require.NoError(t, err)
+	doc := struct {
+		TraceEvents []map[string]interface{} `json:"traceEvents"`
+	}{}
+	require.NoError(t, json.Unmarshal(data, &doc))
+	require.Len(t, doc.TraceEvents, 3)
+	for _, e := range doc.TraceEvents {
+		require.Equal(t, "X", e["ph"])
+	}
+
+	spans, err := ReadChrome(file)
+	require.NoError(t, err)
+	require.Equal(t, "helm", spans[0].Name)
+	require.Equal(t, []interface{}{"version"}, spans[0].Attributes["args"])
+	require.True(t, spans[0].Duration >= time.Second)
+	// open spans are ended with the error of the CLI, innermost first
+	require.Equal(t, "stage: triggering the installation", spans[1].Name)
+	require.Equal(t, "interrupted", spans[1].Error)
+	require.Equal(t, "kyma install", spans[2].Name)
+	require.Equal(t, spans[2].ID, spans[1].Parent)
+	require.Equal(t, spans[1].ID, spans[0].Parent)
+}
+
+func TestWriteChromeEmpty(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, New().WriteChrome(&buf))
+	require.Contains(t, buf.String(), `"traceEvents": []`)
+}
+
+func TestWriteChromeMasksSecrets(t *testing.T) {
+	redact.Add("ghp_tr4c3")
+	tracer := New()
+	tracer.Start("kyma install", Attributes{"args": []string{"install", "--github-token", "ghp_tr4c3"}}).End(nil)
+	var buf bytes.Buffer
+	require.NoError(t, tracer.WriteChrome(&buf))
+	require.NotContains(t, buf.String(), "ghp_tr4c3")
+	require.Contains(t, buf.String(), `"<redacted>"`)
+}
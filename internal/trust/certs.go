@@ -0,0 +1,83 @@
+package trust
+
+import (
+	"crypto/sha1"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"strings"
+
+	"github.com/kyma-project/cli/internal/cli"
+	"github.com/pkg/errors"
+)
+
+// Command is a command which changes the trusted certificates, the first element is the executable
+type Command []string
+
+// String returns the command as it can be typed in a shell
+func (c Command) String() string {
+	args := make([]string, len(c))
+	for i, a := range c {
+		if a == "" || strings.ContainsAny(a, " \t'\"$*") {
+			a = fmt.Sprintf("'%s'", strings.ReplaceAll(a, "'", `'\''`))
+		}
+		args[i] = a
+	}
+	return strings.Join(args, " ")
+}
+
+// Run executes the command
+func (c Command) Run() error {
+	out, err := cli.RunCmd(c[0], c[1:]...)
+	if err != nil {
+		return errors.Wrapf(err, "'%s' failed: %s", c, strings.TrimSpace(out))
+	}
+	return nil
+}
+
+// ParseCertificate decodes the first certificate of the PEM encoded data
+func ParseCertificate(data []byte) (*x509.Certificate, error) {
+	for {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+		if block == nil {
+			return nil, errors.New("no PEM encoded certificate found")
+		}
+		if block.Type == "CERTIFICATE" {
+			return x509.ParseCertificate(block.Bytes)
+		}
+	}
+}
+
+// CertificateName returns the name the certificate is stored with in the trusted certificates, such as "kyma-local.kyma.dev".
+// It is derived from the domain of the certificate, so that the certificates of several clusters can be told apart and removed again.
+func CertificateName(cert *x509.Certificate) string {
+	domain := cert.Subject.CommonName
+	if len(cert.DNSNames) > 0 {
+		domain = cert.DNSNames[0]
+	}
+	domain = strings.TrimPrefix(domain, "*.")
+	if domain == "" {
+		domain = cert.SerialNumber.Text(16)
+	}
+	return fmt.Sprintf("kyma-%s", domain)
+}
+
+// fingerprint returns the SHA-1 fingerprint of the certificate in upper case hex, as displayed by the certificate managers
+func fingerprint(cert *x509.Certificate) string {
+	return fmt.Sprintf("%X", sha1.Sum(cert.Raw))
+}
+
+// JavaImportCommands returns the commands adding the certificate file to the given Java keystore, such as "$JAVA_HOME/lib/security/cacerts"
+func JavaImportCommands(file, keystore, password string, cert *x509.Certificate) []Command {
+	return []Command{
+		{"keytool", "-importcert", "-noprompt", "-trustcacerts", "-alias", CertificateName(cert), "-file", file, "-keystore", keystore, "-storepass", password},
+	}
+}
+
+// JavaRemoveCommands returns the commands removing the certificate from the given Java keystore
+func JavaRemoveCommands(keystore, password string, cert *x509.Certificate) []Command {
+	return []Command{
+		{"keytool", "-delete", "-noprompt", "-alias", CertificateName(cert), "-keystore", keystore, "-storepass", password},
+	}
+}
@@ -0,0 +1,74 @@
+package trust
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// selfSignedCert creates a PEM encoded self-signed certificate like the one of a local Kyma cluster
+func selfSignedCert(t *testing.T, commonName string, dnsNames ...string) []byte {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(0x2a),
+		Subject:               pkix.Name{CommonName: commonName},
+		DNSNames:              dnsNames,
+		NotBefore:             time.Now(),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	require.NoError(t, err)
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+}
+
+func TestParseCertificate(t *testing.T) {
+	t.Parallel()
+	data := selfSignedCert(t, "kyma", "*.local.kyma.dev")
+
+	cert, err := ParseCertificate(data)
+	require.NoError(t, err)
+	require.Equal(t, "kyma-local.kyma.dev", CertificateName(cert), "the wildcard is not part of the name")
+	require.Len(t, fingerprint(cert), 40)
+
+	// other PEM blocks before the certificate are skipped
+	key := pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: []byte("key")})
+	cert, err = ParseCertificate(append(key, data...))
+	require.NoError(t, err)
+	require.Equal(t, "kyma-local.kyma.dev", CertificateName(cert))
+
+	cert, err = ParseCertificate(selfSignedCert(t, "kyma.example.com"))
+	require.NoError(t, err)
+	require.Equal(t, "kyma-kyma.example.com", CertificateName(cert), "the common name is used without DNS names")
+
+	cert, err = ParseCertificate(selfSignedCert(t, ""))
+	require.NoError(t, err)
+	require.Equal(t, "kyma-2a", CertificateName(cert), "the serial number is used without a domain")
+
+	_, err = ParseCertificate([]byte("not a certificate"))
+	require.Error(t, err)
+}
+
+func TestJavaCommands(t *testing.T) {
+	t.Parallel()
+	cert, err := ParseCertificate(selfSignedCert(t, "kyma", "*.local.kyma.dev"))
+	require.NoError(t, err)
+
+	cmds := JavaImportCommands("kyma.crt", "/usr/lib/jvm/java-11/lib/security/cacerts", "changeit", cert)
+	require.Len(t, cmds, 1)
+	require.Equal(t, "keytool -importcert -noprompt -trustcacerts -alias kyma-local.kyma.dev -file kyma.crt -keystore /usr/lib/jvm/java-11/lib/security/cacerts -storepass changeit", cmds[0].String())
+
+	cmds = JavaRemoveCommands("/opt/java home/cacerts", "changeit", cert)
+	require.Len(t, cmds, 1)
+	require.Equal(t, "keytool -delete -noprompt -alias kyma-local.kyma.dev -keystore '/opt/java home/cacerts' -storepass changeit", cmds[0].String(), "arguments with spaces are quoted")
+}
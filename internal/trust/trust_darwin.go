@@ -3,14 +3,12 @@
 package trust
 
 import (
-	"context"
-	"encoding/base64"
+	"crypto/x509"
 	"fmt"
 
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-
 	"github.com/kyma-project/cli/internal/cli"
 	"github.com/kyma-project/cli/internal/kube"
+	"github.com/kyma-project/cli/internal/metadata"
 
 	"github.com/kyma-project/cli/internal/root"
 	"github.com/pkg/errors"
@@ -27,17 +25,11 @@ func NewCertifier(k kube.KymaKube) Certifier {
 }
 
 func (k keychain) Certificate() ([]byte, error) {
-	cm, err := k.k8s.Static().CoreV1().ConfigMaps("kyma-installer").Get(context.Background(), "net-global-overrides", metav1.GetOptions{})
-	if err != nil {
-		return nil, errors.Wrap(err, fmt.Sprintf("\nCould not retrieve the Kyma root certificate. Follow the instructions to import it manually:\n-----\n%s-----\n", k.Instructions()))
-	}
-
-	decodedCert, err := base64.StdEncoding.DecodeString(cm.Data["global.ingress.tlsCrt"])
+	cert, err := kube.GetResourceField(k.k8s.Static(), "configmap", "net-global-overrides", metadata.Latest().InstallerNamespace, `{.data.global\.ingress\.tlsCrt}`, true)
 	if err != nil {
 		return nil, errors.Wrap(err, fmt.Sprintf("\nCould not retrieve the Kyma root certificate. Follow the instructions to import it manually:\n-----\n%s-----\n", k.Instructions()))
 	}
-
-	return decodedCert, nil
+	return []byte(cert), nil
 }
 
 func (k keychain) StoreCertificate(file string, i Informer) error {
@@ -62,3 +54,21 @@ func (keychain) Instructions() string {
 	return "1. Download the certificate: kubectl get configmap net-global-overrides -n kyma-installer -o jsonpath='{.data.global\\.ingress\\.tlsCrt}' | base64 --decode > kyma.crt\n" +
 		"2. Import the certificate: sudo security add-trusted-cert -d -r trustRoot -k /Library/Keychains/System.keychain kyma.crt\n"
 }
+
+// systemKeychain is the keychain holding the root certificates trusted by all users
+const systemKeychain = "/Library/Keychains/System.keychain"
+
+// ImportCommands returns the commands adding the certificate file to the trusted root certificates of the OS
+func ImportCommands(file string, _ *x509.Certificate) ([]Command, error) {
+	return []Command{
+		{"sudo", "security", "add-trusted-cert", "-d", "-r", "trustRoot", "-k", systemKeychain, file},
+	}, nil
+}
+
+// RemoveCommands returns the commands removing the certificate from the trusted root certificates of the OS.
+// The certificate is identified by its fingerprint, as the keychain may hold other certificates with the same name.
+func RemoveCommands(cert *x509.Certificate) ([]Command, error) {
+	return []Command{
+		{"sudo", "security", "delete-certificate", "-Z", fingerprint(cert), "-t", systemKeychain},
+	}, nil
+}
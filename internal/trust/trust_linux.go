@@ -3,17 +3,18 @@
 package trust
 
 import (
-	"context"
-	"encoding/base64"
+	"crypto/x509"
 	"fmt"
+	"os/exec"
+	"path/filepath"
 	"regexp"
 	"strings"
 
 	"github.com/pkg/errors"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"github.com/kyma-project/cli/internal/cli"
 	"github.com/kyma-project/cli/internal/kube"
+	"github.com/kyma-project/cli/internal/metadata"
 	"github.com/kyma-project/cli/internal/root"
 )
 
@@ -28,17 +29,11 @@ func NewCertifier(k kube.KymaKube) Certifier {
 }
 
 func (c certauth) Certificate() ([]byte, error) {
-	cm, err := c.k8s.Static().CoreV1().ConfigMaps("kyma-installer").Get(context.Background(), "net-global-overrides", metav1.GetOptions{})
+	cert, err := kube.GetResourceField(c.k8s.Static(), "configmap", "net-global-overrides", metadata.Latest().InstallerNamespace, `{.data.global\.ingress\.tlsCrt}`, true)
 	if err != nil {
 		return nil, errors.Wrap(err, fmt.Sprintf("\nCould not retrieve the Kyma root certificate. Follow the instructions to import it manually:\n-----\n%s-----\n", c.Instructions()))
 	}
-
-	decodedCert, err := base64.StdEncoding.DecodeString(cm.Data["global.ingress.tlsCrt"])
-	if err != nil {
-		return nil, err
-	}
-
-	return decodedCert, nil
+	return []byte(cert), nil
 }
 
 func (c certauth) StoreCertificate(file string, i Informer) error {
@@ -92,3 +87,53 @@ func certDomain(certFile string) (string, error) {
 
 	return strings.Replace(matches[1], "'", "", -1), nil
 }
+
+// lookPath finds the tools of the trusted certificate store, it is replaced in tests
+var lookPath = exec.LookPath
+
+// linuxStore is a directory of trusted CA certificates and the tool which updates the certificate bundle from it
+type linuxStore struct {
+	dir    string
+	update Command
+	// fresh rebuilds the certificate bundle, so that removed certificates are dropped from it
+	fresh Command
+}
+
+// linuxStores are the certificate stores of Debian/Ubuntu and of Fedora/RHEL/SUSE, the first one whose tool is installed is used
+var linuxStores = []linuxStore{
+	{dir: "/usr/local/share/ca-certificates", update: Command{"sudo", "update-ca-certificates"}, fresh: Command{"sudo", "update-ca-certificates", "--fresh"}},
+	{dir: "/etc/pki/ca-trust/source/anchors", update: Command{"sudo", "update-ca-trust", "extract"}, fresh: Command{"sudo", "update-ca-trust", "extract"}},
+}
+
+func certificateStore() (linuxStore, error) {
+	for _, s := range linuxStores {
+		if _, err := lookPath(s.update[1]); err == nil {
+			return s, nil
+		}
+	}
+	return linuxStore{}, errors.New("neither update-ca-certificates nor update-ca-trust is installed. Add the certificate to the trusted certificates of your distribution manually")
+}
+
+// ImportCommands returns the commands adding the certificate file to the trusted root certificates of the OS
+func ImportCommands(file string, cert *x509.Certificate) ([]Command, error) {
+	s, err := certificateStore()
+	if err != nil {
+		return nil, err
+	}
+	return []Command{
+		{"sudo", "cp", file, filepath.Join(s.dir, CertificateName(cert)+".crt")},
+		s.update,
+	}, nil
+}
+
+// RemoveCommands returns the commands removing the certificate from the trusted root certificates of the OS
+func RemoveCommands(cert *x509.Certificate) ([]Command, error) {
+	s, err := certificateStore()
+	if err != nil {
+		return nil, err
+	}
+	return []Command{
+		{"sudo", "rm", "-f", filepath.Join(s.dir, CertificateName(cert)+".crt")},
+		s.fresh,
+	}, nil
+}
@@ -0,0 +1,52 @@
+// +build linux
+
+package trust
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLinuxCommands(t *testing.T) {
+	// not parallel: the package level lookPath is replaced
+	defer func(l func(string) (string, error)) { lookPath = l }(lookPath)
+	cert, err := ParseCertificate(selfSignedCert(t, "kyma", "*.local.kyma.dev"))
+	require.NoError(t, err)
+
+	installed := func(tool string) func(string) (string, error) {
+		return func(file string) (string, error) {
+			if file == tool {
+				return "/usr/sbin/" + file, nil
+			}
+			return "", errors.New("not found")
+		}
+	}
+
+	lookPath = installed("update-ca-certificates")
+	cmds, err := ImportCommands("kyma.crt", cert)
+	require.NoError(t, err)
+	require.Equal(t, []Command{
+		{"sudo", "cp", "kyma.crt", "/usr/local/share/ca-certificates/kyma-local.kyma.dev.crt"},
+		{"sudo", "update-ca-certificates"},
+	}, cmds)
+	cmds, err = RemoveCommands(cert)
+	require.NoError(t, err)
+	require.Equal(t, []Command{
+		{"sudo", "rm", "-f", "/usr/local/share/ca-certificates/kyma-local.kyma.dev.crt"},
+		{"sudo", "update-ca-certificates", "--fresh"},
+	}, cmds)
+
+	lookPath = installed("update-ca-trust")
+	cmds, err = ImportCommands("kyma.crt", cert)
+	require.NoError(t, err)
+	require.Equal(t, []Command{
+		{"sudo", "cp", "kyma.crt", "/etc/pki/ca-trust/source/anchors/kyma-local.kyma.dev.crt"},
+		{"sudo", "update-ca-trust", "extract"},
+	}, cmds)
+
+	lookPath = installed("none")
+	_, err = ImportCommands("kyma.crt", cert)
+	require.Error(t, err)
+}
@@ -3,15 +3,14 @@
 package trust
 
 import (
-	"context"
-	"encoding/base64"
+	"crypto/x509"
 	"fmt"
 
 	"github.com/kyma-project/cli/internal/cli"
 	"github.com/kyma-project/cli/internal/kube"
+	"github.com/kyma-project/cli/internal/metadata"
 	"github.com/kyma-project/cli/internal/root"
 	"github.com/pkg/errors"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 type certutil struct {
@@ -25,17 +24,11 @@ func NewCertifier(k kube.KymaKube) Certifier {
 }
 
 func (c certutil) Certificate() ([]byte, error) {
-	cm, err := c.k8s.Static().CoreV1().ConfigMaps("kyma-installer").Get(context.Background(), "net-global-overrides", metav1.GetOptions{})
+	cert, err := kube.GetResourceField(c.k8s.Static(), "configmap", "net-global-overrides", metadata.Latest().InstallerNamespace, `{.data.global\.ingress\.tlsCrt}`, true)
 	if err != nil {
 		return nil, errors.Wrap(err, fmt.Sprintf("\nCould not retrieve the Kyma root certificate. Follow the instructions to import it manually:\n-----\n%s-----\n", c.Instructions()))
 	}
-
-	decodedCert, err := base64.StdEncoding.DecodeString(cm.Data["global.ingress.tlsCrt"])
-	if err != nil {
-		return nil, err
-	}
-
-	return decodedCert, nil
+	return []byte(cert), nil
 }
 
 func (c certutil) StoreCertificate(file string, i Informer) error {
@@ -60,3 +53,18 @@ func (certutil) Instructions() string {
 		"3. Decode the certificate: certutil -decode tmp.txt kyma.crt ; del tmp.txt\n" +
 		"4. Import the certificate: certutil -addstore -f Root kyma.crt\n"
 }
+
+// ImportCommands returns the commands adding the certificate file to the trusted root certificates of the OS.
+// certutil can only change the Root store in a terminal with administrator rights.
+func ImportCommands(file string, _ *x509.Certificate) ([]Command, error) {
+	return []Command{
+		{"certutil", "-addstore", "-f", "Root", file},
+	}, nil
+}
+
+// RemoveCommands returns the commands removing the certificate, identified by its serial number, from the trusted root certificates of the OS
+func RemoveCommands(cert *x509.Certificate) ([]Command, error) {
+	return []Command{
+		{"certutil", "-delstore", "Root", cert.SerialNumber.Text(16)},
+	}, nil
+}
@@ -0,0 +1,135 @@
+package version
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/kyma-project/cli/internal/kube"
+	"github.com/kyma-project/cli/internal/metadata"
+	corev1 "k8s.io/api/core/v1"
+	apiErrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// KymaState tells whether Kyma is installed on a cluster
+type KymaState int
+
+const (
+	// NotInstalled means that the cluster runs no Kyma Installer
+	NotInstalled KymaState = iota
+	// InProgress means that the Kyma Installer still installs or upgrades Kyma
+	InProgress
+	// Installed means that the Kyma Installer finished
+	Installed
+	// Failed means that the Installation CR is in the Error state. The Kyma Installer retries the failed components, so the installation might still succeed.
+	Failed
+)
+
+// ClusterVersion is the version of Kyma on a cluster together with the state of its installation
+type ClusterVersion struct {
+	State KymaState
+	// Version is the version of the Kyma Installer, it is known as soon as the installer runs
+	Version string
+	// Since is the start time of the Kyma Installer, if the installation is in progress
+	Since time.Time
+	// InstallerSelector is the label selector the Kyma Installer pods were found with
+	InstallerSelector string
+}
+
+func (v ClusterVersion) String() string {
+	switch v.State {
+	case Installed:
+		return v.Version
+	case InProgress:
+		if v.Since.IsZero() {
+			return fmt.Sprintf("%s (installation in progress)", v.Version)
+		}
+		return fmt.Sprintf("%s (installation in progress since %s)", v.Version, v.Since.Format(time.RFC3339))
+	case Failed:
+		return fmt.Sprintf("%s (installation failed)", v.Version)
+	}
+	return "not installed"
+}
+
+// ClusterKymaVersion determines the version of Kyma on the cluster and whether its installation finished.
+// The installation is in progress as long as an Installation CR has another state than "Installed", and failed if an Installation CR
+// is in the "Error" state. A missing Installation CR, for example of a cluster installed by older tools, is no error.
+func ClusterKymaVersion(k8s kube.KymaKube) (ClusterVersion, error) {
+	pods, selector, err := installerPods(k8s.Static())
+	if err != nil {
+		return ClusterVersion{}, err
+	}
+	if len(pods) == 0 {
+		return ClusterVersion{State: NotInstalled}, nil
+	}
+	pod := pods[0]
+	result := ClusterVersion{State: Installed, Version: installerVersion(pod), InstallerSelector: selector}
+
+	m := metadata.For(result.Version)
+	crs, err := k8s.Dynamic().Resource(m.InstallationGVR()).Namespace(m.InstallationNamespace).List(context.Background(), metav1.ListOptions{})
+	if apiErrors.IsNotFound(err) {
+		return result, nil
+	}
+	if err != nil {
+		return result, err
+	}
+	for _, cr := range crs.Items {
+		status, _ := cr.Object["status"].(map[string]interface{})
+		state, _ := status["state"].(string)
+		if state == "" || state == "Installed" || result.State == Failed {
+			continue
+		}
+		result.State = InProgress
+		if state == "Error" {
+			result.State = Failed
+		}
+		if pod.Status.StartTime != nil {
+			result.Since = pod.Status.StartTime.Time
+		}
+	}
+	return result, nil
+}
+
+// KymaVersion determines the version of Kyma installed on the cluster, or "N/A" if no Kyma Installer runs
+func KymaVersion(k8s kubernetes.Interface) (string, error) {
+	pods, _, err := installerPods(k8s)
+	if err != nil {
+		return "", err
+	}
+
+	if len(pods) == 0 {
+		return "N/A", nil
+	}
+
+	return installerVersion(pods[0]), nil
+}
+
+// installerPods returns the Kyma Installer pods and the label selector they were found with.
+// The version is not known before the pods are found, so the selectors of all releases listed for the newest ones are tried.
+func installerPods(k8s kubernetes.Interface) ([]corev1.Pod, string, error) {
+	m := metadata.Latest()
+	selector, found, err := kube.FindPodSelector(k8s, m.InstallerNamespace, m.InstallerSelectors)
+	if err != nil || !found {
+		return nil, selector, err
+	}
+	pods, err := k8s.CoreV1().Pods(m.InstallerNamespace).List(context.Background(), metav1.ListOptions{LabelSelector: selector})
+	if err != nil {
+		return nil, selector, err
+	}
+	return pods.Items, selector, nil
+}
+
+// installerVersion returns the tag of the Kyma Installer image, which is the version of Kyma
+func installerVersion(pod corev1.Pod) string {
+	if len(pod.Spec.Containers) == 0 {
+		return "N/A"
+	}
+	imageParts := strings.Split(pod.Spec.Containers[0].Image, ":")
+	if len(imageParts) < 2 {
+		return "N/A"
+	}
+	return imageParts[len(imageParts)-1]
+}
@@ -0,0 +1,117 @@
+package version
+
+import (
+	"testing"
+	"time"
+
+	"github.com/kyma-project/cli/internal/kube/mocks"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	fakeDynamic "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func installerPod(start time.Time) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "kyma-installer", Namespace: "kyma-installer", Labels: map[string]string{"name": "kyma-installer"}},
+		Spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: "installer", Image: "eu.gcr.io/kyma-project/kyma-installer:1.16.0"}}},
+		Status:     corev1.PodStatus{StartTime: &metav1.Time{Time: start}},
+	}
+}
+
+// legacyInstallerPod is the pod of a Kyma Installer labeled with app instead of name
+func legacyInstallerPod(start time.Time) *corev1.Pod {
+	pod := installerPod(start)
+	pod.Labels = map[string]string{"app": "kyma-installer"}
+	return pod
+}
+
+func installationCR(state string) runtime.Object {
+	cr := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "installer.kyma-project.io/v1alpha1",
+		"kind":       "Installation",
+		"metadata":   map[string]interface{}{"name": "kyma-installation", "namespace": "default"},
+	}}
+	if state != "" {
+		cr.Object["status"] = map[string]interface{}{"state": state}
+	}
+	return cr
+}
+
+func TestClusterKymaVersion(t *testing.T) {
+	t.Parallel()
+	start := time.Date(2020, 10, 1, 12, 0, 0, 0, time.UTC)
+
+	cases := []struct {
+		name     string
+		pods     []runtime.Object
+		crs      []runtime.Object
+		expected ClusterVersion
+		text     string
+	}{
+		{
+			name:     "No Kyma Installer",
+			expected: ClusterVersion{State: NotInstalled},
+			text:     "not installed",
+		},
+		{
+			name:     "Installed",
+			pods:     []runtime.Object{installerPod(start)},
+			crs:      []runtime.Object{installationCR("Installed")},
+			expected: ClusterVersion{State: Installed, Version: "1.16.0", InstallerSelector: "name=kyma-installer"},
+			text:     "1.16.0",
+		},
+		{
+			name:     "Installation CR missing",
+			pods:     []runtime.Object{installerPod(start)},
+			expected: ClusterVersion{State: Installed, Version: "1.16.0", InstallerSelector: "name=kyma-installer"},
+			text:     "1.16.0",
+		},
+		{
+			name:     "Installation CR without state",
+			pods:     []runtime.Object{installerPod(start)},
+			crs:      []runtime.Object{installationCR("")},
+			expected: ClusterVersion{State: Installed, Version: "1.16.0", InstallerSelector: "name=kyma-installer"},
+			text:     "1.16.0",
+		},
+		{
+			name:     "Installation in progress",
+			pods:     []runtime.Object{installerPod(start)},
+			crs:      []runtime.Object{installationCR("InProgress")},
+			expected: ClusterVersion{State: InProgress, Version: "1.16.0", Since: start, InstallerSelector: "name=kyma-installer"},
+			text:     "1.16.0 (installation in progress since 2020-10-01T12:00:00Z)",
+		},
+		{
+			name:     "Installation failed",
+			pods:     []runtime.Object{installerPod(start)},
+			crs:      []runtime.Object{installationCR("Error")},
+			expected: ClusterVersion{State: Failed, Version: "1.16.0", Since: start, InstallerSelector: "name=kyma-installer"},
+			text:     "1.16.0 (installation failed)",
+		},
+		{
+			name:     "Kyma Installer with a historical label",
+			pods:     []runtime.Object{legacyInstallerPod(start)},
+			crs:      []runtime.Object{installationCR("Installed")},
+			expected: ClusterVersion{State: Installed, Version: "1.16.0", InstallerSelector: "app=kyma-installer"},
+			text:     "1.16.0",
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			kymaMock := &mocks.KymaKube{}
+			kymaMock.On("Static").Return(fake.NewSimpleClientset(tc.pods...))
+			kymaMock.On("Dynamic").Return(fakeDynamic.NewSimpleDynamicClient(runtime.NewScheme(), tc.crs...))
+
+			v, err := ClusterKymaVersion(kymaMock)
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, v)
+			require.Equal(t, tc.text, v.String())
+		})
+	}
+}
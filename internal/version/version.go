@@ -0,0 +1,127 @@
+// Package version parses Kyma versions, checks them against the Kyma releases supported by the CLI, and reads the version of Kyma on a cluster.
+package version
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/blang/semver/v4"
+)
+
+// MinKymaVersion and MaxKymaVersion are the oldest and the newest Kyma minor versions (e.g. 1.14) the CLI supports.
+// MaxKymaVersion is injected by the build system (MAX_KYMA_VERSION) to match the newest Kyma release the CLI was tested with,
+// the values set here are used by builds which do not inject them.
+var (
+	MinKymaVersion = "1.14"
+	MaxKymaVersion = "1.18"
+)
+
+// DefaultKymaVersion is the Kyma version the install and upgrade commands use if no source is given, injected by the build system.
+var DefaultKymaVersion string
+
+// Version is a Kyma release version.
+type Version struct {
+	semver.Version
+}
+
+// Parse parses a Kyma release version such as "1.16.0", "v1.16.0" or "1.17.0-rc1".
+// A missing patch version is accepted, so "1.16" equals "1.16.0".
+func Parse(s string) (Version, error) {
+	v, err := semver.ParseTolerant(s)
+	if err != nil {
+		return Version{}, fmt.Errorf("'%s' is not a Kyma release version: %s", s, err)
+	}
+	return Version{v}, nil
+}
+
+// IsPrerelease checks if the version is a release candidate or another pre-release, e.g. "1.17.0-rc1".
+func (v Version) IsPrerelease() bool {
+	return len(v.Pre) > 0
+}
+
+// MinorVersion returns the major and minor version, e.g. "1.17" for "1.17.0-rc1".
+func (v Version) MinorVersion() string {
+	return fmt.Sprintf("%d.%d", v.Major, v.Minor)
+}
+
+// Range is a range of Kyma minor versions, including both bounds.
+type Range struct {
+	Min Version
+	Max Version
+}
+
+// SupportedRange returns the range of Kyma versions the CLI supports.
+func SupportedRange() (Range, error) {
+	min, err := Parse(MinKymaVersion)
+	if err != nil {
+		return Range{}, err
+	}
+	max, err := Parse(MaxKymaVersion)
+	if err != nil {
+		return Range{}, err
+	}
+	return Range{Min: min, Max: max}, nil
+}
+
+// Contains checks if the minor version of v is within the range.
+// Patch versions and pre-releases belong to their minor version, so "1.18.0-rc1" and "1.18.3" are in a range ending with 1.18.
+func (r Range) Contains(v Version) bool {
+	return compareMinor(v, r.Min) >= 0 && compareMinor(v, r.Max) <= 0
+}
+
+func (r Range) String() string {
+	return fmt.Sprintf("%s to %s", r.Min.MinorVersion(), r.Max.MinorVersion())
+}
+
+func compareMinor(a, b Version) int {
+	if a.Major != b.Major {
+		return compare(a.Major, b.Major)
+	}
+	return compare(a.Minor, b.Minor)
+}
+
+func compare(a, b uint64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+// IncompatibleError is returned for Kyma versions the CLI does not support.
+type IncompatibleError struct {
+	Version   Version
+	Supported Range
+}
+
+func (e *IncompatibleError) Error() string {
+	return fmt.Sprintf("Kyma %s is not supported by this version of Kyma CLI, which supports Kyma %s", e.Version, e.Supported)
+}
+
+// CheckCompatibility checks if the CLI supports the Kyma version.
+// Sources which are not release versions, such as "master", commits, pull requests or local sources, cannot be checked and are accepted.
+func CheckCompatibility(kymaVersion string) error {
+	if !isReleaseVersion(kymaVersion) {
+		return nil
+	}
+	v, err := Parse(kymaVersion)
+	if err != nil {
+		return nil
+	}
+	supported, err := SupportedRange()
+	if err != nil {
+		return err
+	}
+	if !supported.Contains(v) {
+		return &IncompatibleError{Version: v, Supported: supported}
+	}
+	return nil
+}
+
+// isReleaseVersion filters out sources which the tolerant parsing would accept, but which are no releases, e.g. installer images or commits
+func isReleaseVersion(s string) bool {
+	s = strings.TrimPrefix(s, "v")
+	return s != "" && s[0] >= '0' && s[0] <= '9' && strings.Count(s, ".") >= 1 && !strings.ContainsAny(s, "/:")
+}
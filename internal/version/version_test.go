@@ -0,0 +1,77 @@
+package version
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+	v, err := Parse("v1.17.0-rc1")
+	require.NoError(t, err)
+	require.True(t, v.IsPrerelease())
+	require.Equal(t, "1.17", v.MinorVersion())
+	require.Equal(t, "1.17.0-rc1", v.String())
+
+	v, err = Parse("1.16")
+	require.NoError(t, err)
+	require.False(t, v.IsPrerelease())
+	require.Equal(t, "1.16.0", v.String())
+
+	rc, _ := Parse("1.17.0-rc1")
+	release, _ := Parse("1.17.0")
+	require.True(t, rc.LT(release.Version), "A release candidate precedes its release")
+
+	_, err = Parse("master")
+	require.Error(t, err)
+}
+
+func TestRangeContains(t *testing.T) {
+	t.Parallel()
+	r := Range{Min: mustParse(t, "1.14"), Max: mustParse(t, "1.16")}
+	cases := map[string]bool{
+		"1.13.9":     false,
+		"1.14.0-rc1": true,
+		"1.14.0":     true,
+		"1.15.3":     true,
+		"1.16.0-rc2": true,
+		"1.16.5":     true,
+		"1.17.0-rc1": false,
+		"2.0.0":      false,
+		"0.16.0":     false,
+	}
+	for v, expected := range cases {
+		require.Equal(t, expected, r.Contains(mustParse(t, v)), v)
+	}
+	require.Equal(t, "1.14 to 1.16", r.String())
+}
+
+func TestCheckCompatibility(t *testing.T) {
+	// not parallel: the package level range is modified
+	defaultMin, defaultMax := MinKymaVersion, MaxKymaVersion
+	MinKymaVersion, MaxKymaVersion = "1.15", "1.16"
+	defer func() { MinKymaVersion, MaxKymaVersion = defaultMin, defaultMax }()
+
+	require.NoError(t, CheckCompatibility("1.15.1"))
+	require.NoError(t, CheckCompatibility("1.16.0-rc1"))
+
+	err := CheckCompatibility("1.17.0")
+	require.EqualError(t, err, "Kyma 1.17.0 is not supported by this version of Kyma CLI, which supports Kyma 1.15 to 1.16")
+	require.IsType(t, &IncompatibleError{}, err)
+	require.Error(t, CheckCompatibility("1.14.2"))
+
+	// sources other than release versions cannot be checked
+	for _, source := range []string{"master", "local", "34edf09a", "PR-9486", "user/my-kyma-installer:v1.4.0", "master-34edf09a", ""} {
+		require.NoError(t, CheckCompatibility(source), source)
+	}
+
+	MaxKymaVersion = "invalid"
+	require.Error(t, CheckCompatibility("1.15.1"))
+}
+
+func mustParse(t *testing.T, s string) Version {
+	v, err := Parse(s)
+	require.NoError(t, err)
+	return v
+}
@@ -0,0 +1,113 @@
+package docker
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/filters"
+)
+
+// kubernetesPodLabel is set by the kubelet on the containers of pods
+const kubernetesPodLabel = "io.kubernetes.pod.name"
+
+// DiskInfo is the disk usage of the images stored by a Docker daemon
+type DiskInfo struct {
+	// RootDir is the directory in which the daemon stores its data
+	RootDir string
+	Images  int
+	// ImagesSize is the size of all image layers in bytes
+	ImagesSize int64
+	// UnusedSize is the size of the images not used by any container in bytes, it is an upper bound of the space a prune reclaims
+	UnusedSize int64
+}
+
+// PruneReport summarizes what a prune removed
+type PruneReport struct {
+	Containers     int
+	Images         int
+	SpaceReclaimed uint64
+}
+
+func (k *kymaDockerClient) DiskInfo() (DiskInfo, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(60)*time.Second)
+	defer cancel()
+	k.Docker.NegotiateAPIVersion(ctx)
+
+	info, err := k.Docker.Info(ctx)
+	if err != nil {
+		return DiskInfo{}, err
+	}
+	usage, err := k.Docker.DiskUsage(ctx)
+	if err != nil {
+		return DiskInfo{}, err
+	}
+
+	result := DiskInfo{RootDir: info.DockerRootDir, Images: info.Images, ImagesSize: usage.LayersSize}
+	for _, img := range usage.Images {
+		if img.Containers == 0 {
+			result.UnusedSize += img.Size - img.SharedSize
+		}
+	}
+	return result, nil
+}
+
+// Prune removes the stopped containers which do not belong to Kubernetes pods and the images which are not used by any container.
+// Images matching one of the references to keep (image names, digests or IDs, e.g. of the images of running pods) are never removed.
+func (k *kymaDockerClient) Prune(keep []string) (PruneReport, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(300)*time.Second)
+	defer cancel()
+	k.Docker.NegotiateAPIVersion(ctx)
+
+	var report PruneReport
+	// the kubelet removes the containers of pods itself, they might still be needed to restart a pod
+	containers, err := k.Docker.ContainersPrune(ctx, filters.NewArgs(filters.Arg("label!", kubernetesPodLabel)))
+	if err != nil {
+		return report, err
+	}
+	report.Containers = len(containers.ContainersDeleted)
+	report.SpaceReclaimed += containers.SpaceReclaimed
+
+	kept := map[string]bool{}
+	for _, ref := range keep {
+		kept[normalizeImageRef(ref)] = true
+	}
+
+	usage, err := k.Docker.DiskUsage(ctx)
+	if err != nil {
+		return report, err
+	}
+	for _, img := range usage.Images {
+		if img.Containers != 0 || imageKept(img, kept) {
+			continue
+		}
+		// without force, the daemon refuses to remove images which are still in use
+		if _, err := k.Docker.ImageRemove(ctx, img.ID, types.ImageRemoveOptions{PruneChildren: true}); err != nil {
+			continue
+		}
+		report.Images++
+		report.SpaceReclaimed += uint64(img.Size - img.SharedSize)
+	}
+	return report, nil
+}
+
+func imageKept(img *types.ImageSummary, kept map[string]bool) bool {
+	refs := append([]string{img.ID}, img.RepoTags...)
+	refs = append(refs, img.RepoDigests...)
+	for _, ref := range refs {
+		if kept[normalizeImageRef(ref)] {
+			return true
+		}
+	}
+	return false
+}
+
+// normalizeImageRef removes the parts of an image reference which Kubernetes and Docker represent differently,
+// e.g. "docker-pullable://docker.io/library/nginx@sha256:..." becomes "nginx@sha256:..."
+func normalizeImageRef(ref string) string {
+	for _, prefix := range []string{"docker-pullable://", "docker://", defaultRegistry + "/", "docker.io/", "library/"} {
+		ref = strings.TrimPrefix(ref, prefix)
+	}
+	return ref
+}
@@ -10,21 +10,30 @@ import (
 	"os"
 	"path"
 	"strings"
+	"sync"
 	"time"
 
 	dockerConfig "github.com/docker/cli/cli/config"
 	configTypes "github.com/docker/cli/cli/config/types"
 	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/filters"
 	docker "github.com/docker/docker/client"
 	"github.com/docker/docker/pkg/archive"
+	"github.com/kyma-project/cli/internal/logger"
 	"github.com/kyma-project/cli/internal/minikube"
+	"github.com/kyma-project/cli/internal/trace"
 	"github.com/kyma-project/cli/pkg/step"
 )
 
 const (
 	defaultRegistry = "index.docker.io"
+	// defaultBuildTimeout is the maximum time of the Kyma Installer build if no timeout is given
+	defaultBuildTimeout = 10 * time.Minute
 )
 
+// buildHeartbeatInterval is the time after which the elapsed build time is displayed again
+var buildHeartbeatInterval = time.Minute
+
 type dockerClient struct {
 	*docker.Client
 }
@@ -38,12 +47,24 @@ type Client interface {
 	ArchiveDirectory(srcPath string, options *archive.TarOptions) (io.ReadCloser, error)
 	NegotiateAPIVersion(ctx context.Context)
 	ImagePush(ctx context.Context, image string, options types.ImagePushOptions) (io.ReadCloser, error)
+	ImagePull(ctx context.Context, image string, options types.ImagePullOptions) (io.ReadCloser, error)
 	ImageBuild(ctx context.Context, buildContext io.Reader, options types.ImageBuildOptions) (types.ImageBuildResponse, error)
+	ImageRemove(ctx context.Context, imageID string, options types.ImageRemoveOptions) ([]types.ImageDeleteResponseItem, error)
+	ImageInspectWithRaw(ctx context.Context, imageID string) (types.ImageInspect, []byte, error)
+	ContainersPrune(ctx context.Context, pruneFilters filters.Args) (types.ContainersPruneReport, error)
+	Info(ctx context.Context) (types.Info, error)
+	DiskUsage(ctx context.Context) (types.DiskUsage, error)
 }
 
+// The mock is generated into the installation package, which uses it, as the tests of this package import the mocks of Client
+//go:generate mockery --name KymaClient --output ../installation/mocks
 type KymaClient interface {
 	PushKymaInstaller(image string, currentStep step.Step) error
-	BuildKymaInstaller(localSrcPath, imageName string) error
+	BuildKymaInstaller(localSrcPath, imageName string, timeout time.Duration, currentStep step.Step) error
+	ImageExists(image string) (bool, error)
+	PullImages(images []string, concurrency int, progress func(done, total int)) []error
+	DiskInfo() (DiskInfo, error)
+	Prune(keep []string) (PruneReport, error)
 }
 
 // ErrorMessage is used to parse error messages coming from Docker
@@ -92,16 +113,22 @@ func (d *dockerClient) ArchiveDirectory(srcPath string, options *archive.TarOpti
 	return archive.TarWithOptions(srcPath, &archive.TarOptions{})
 }
 
-func (k *kymaDockerClient) BuildKymaInstaller(localSrcPath, imageName string) error {
+// BuildKymaInstaller builds the Kyma Installer image from the local sources and waits until the build finished or the timeout is reached.
+// While the image is built, the elapsed time is displayed on the step, so that a slow build can be told apart from a stuck Docker daemon.
+func (k *kymaDockerClient) BuildKymaInstaller(localSrcPath, imageName string, timeout time.Duration, currentStep step.Step) error {
 	reader, err := k.Docker.ArchiveDirectory(localSrcPath, &archive.TarOptions{})
 	if err != nil {
 		return err
 	}
-	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(300)*time.Second)
+	if timeout <= 0 {
+		timeout = defaultBuildTimeout
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
 	defer cancel()
 	k.Docker.NegotiateAPIVersion(ctx)
 	args := make(map[string]*string)
-	_, err = k.Docker.ImageBuild(
+	started := time.Now()
+	resp, err := k.Docker.ImageBuild(
 		ctx,
 		reader,
 		types.ImageBuildOptions{
@@ -112,13 +139,77 @@ func (k *kymaDockerClient) BuildKymaInstaller(localSrcPath, imageName string) er
 			BuildArgs:      args,
 		},
 	)
+	if err == nil {
+		// the image is built while the response is streamed
+		err = waitForBuild(ctx, resp.Body, started, currentStep)
+	}
+	if err != nil && ctx.Err() == context.DeadlineExceeded {
+		err = fmt.Errorf("the build of the Docker image '%s' did not finish within %s. Check if the Docker daemon is responsive, for Minikube with: minikube ssh -- docker info", imageName, timeout)
+	}
+	trace.Record("docker build", started, err, trace.Attributes{"image": imageName})
+	fields := logger.Fields{"image": imageName, "context": localSrcPath, "duration": time.Since(started)}
 	if err != nil {
+		fields["error"] = err
+		logger.Error("docker", "image build failed", fields)
 		return err
 	}
+	logger.Info("docker", "image built", fields)
 
 	return nil
 }
 
+// waitForBuild reads the build output until the build finished, and displays the elapsed time on the step every minute
+func waitForBuild(ctx context.Context, body io.ReadCloser, started time.Time, currentStep step.Step) error {
+	defer body.Close()
+	done := make(chan error, 1)
+	go func() { done <- buildError(body) }()
+
+	heartbeat := time.NewTicker(buildHeartbeatInterval)
+	defer heartbeat.Stop()
+	for {
+		select {
+		case err := <-done:
+			return err
+		case <-heartbeat.C:
+			if currentStep != nil {
+				currentStep.Status(fmt.Sprintf("still building the Kyma Installer image, %s elapsed", time.Since(started).Round(time.Second)))
+			}
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// buildError returns the error reported in the build output. The output is only scanned for errors, other lines are ignored.
+func buildError(output io.Reader) error {
+	reader := bufio.NewReader(output)
+	for {
+		line, err := reader.ReadBytes('\n')
+		var errorMessage ErrorMessage
+		if json.Unmarshal(line, &errorMessage) == nil && errorMessage.Error != "" {
+			return fmt.Errorf("failed to build Docker image: %s", errorMessage.Error)
+		}
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// ImageExists checks if the Docker daemon has the image
+func (k *kymaDockerClient) ImageExists(image string) (bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	k.Docker.NegotiateAPIVersion(ctx)
+	_, _, err := k.Docker.ImageInspectWithRaw(ctx, image)
+	if docker.IsErrNotFound(err) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
 func (k *kymaDockerClient) PushKymaInstaller(image string, currentStep step.Step) error {
 	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(300)*time.Second)
 	defer cancel()
@@ -167,6 +258,80 @@ func (k *kymaDockerClient) PushKymaInstaller(image string, currentStep step.Step
 	return nil
 }
 
+// PullImages pulls the images with at most the given number of pulls running in parallel.
+// After each image, progress is called with the number of images processed so far. The errors of all failed pulls are returned.
+func (k *kymaDockerClient) PullImages(images []string, concurrency int, progress func(done, total int)) []error {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	k.Docker.NegotiateAPIVersion(context.Background())
+
+	var (
+		wg     sync.WaitGroup
+		mu     sync.Mutex
+		done   int
+		errs   []error
+		limits = make(chan struct{}, concurrency)
+	)
+	for _, image := range images {
+		wg.Add(1)
+		limits <- struct{}{}
+		go func(image string) {
+			defer wg.Done()
+			err := k.pullImage(image)
+			<-limits
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				errs = append(errs, err)
+			}
+			done++
+			if progress != nil {
+				progress(done, len(images))
+			}
+		}(image)
+	}
+	wg.Wait()
+
+	return errs
+}
+
+func (k *kymaDockerClient) pullImage(image string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(300)*time.Second)
+	defer cancel()
+
+	domain, _ := splitDockerDomain(image)
+	auth, err := resolve(domain)
+	if err != nil {
+		return err
+	}
+	encodedJSON, err := json.Marshal(auth)
+	if err != nil {
+		return err
+	}
+
+	puller, err := k.Docker.ImagePull(ctx, image, types.ImagePullOptions{RegistryAuth: base64.URLEncoding.EncodeToString(encodedJSON)})
+	if err != nil {
+		return fmt.Errorf("failed to pull Docker image '%s': %s", image, err)
+	}
+	defer puller.Close()
+
+	// the pull only finishes once its progress stream is read completely
+	dec := json.NewDecoder(puller)
+	for {
+		var errorMessage ErrorMessage
+		if err := dec.Decode(&errorMessage); err == io.EOF {
+			return nil
+		} else if err != nil {
+			return fmt.Errorf("failed to pull Docker image '%s': %s", image, err)
+		}
+		if errorMessage.Error != "" {
+			return fmt.Errorf("failed to pull Docker image '%s': %s", image, errorMessage.Error)
+		}
+	}
+}
+
 func splitDockerDomain(name string) (domain, remainder string) {
 	i := strings.IndexRune(name, '/')
 	if i == -1 || (!strings.ContainsAny(name[:i], ".:") && name[:i] != "localhost") {
@@ -1,14 +1,18 @@
 package docker
 
 import (
+	"archive/tar"
 	"encoding/base64"
 	"encoding/json"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"os"
 	"path"
+	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	dockerConfigFile "github.com/docker/cli/cli/config/configfile"
 	"github.com/docker/cli/cli/config/types"
@@ -16,6 +20,7 @@ import (
 	"github.com/docker/docker/pkg/archive"
 	"github.com/kyma-project/cli/pkg/docker/mocks"
 	"github.com/kyma-project/cli/pkg/step"
+	stepMocks "github.com/kyma-project/cli/pkg/step/mocks"
 
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
@@ -119,10 +124,42 @@ func Test_BuildKymaInstaller(t *testing.T) {
 	mockDocker.On("ImageBuild", mock.Anything, fooReadCloser, fooImageBuildOptions).Return(fooImageBuildRes, nil)
 
 	// test the function
-	err := k.BuildKymaInstaller(fooLocalSrcPath, imageName)
+	err := k.BuildKymaInstaller(fooLocalSrcPath, imageName, time.Minute, nil)
 	assert.NilError(t, err)
 }
 
+func Test_BuildKymaInstallerOutput(t *testing.T) {
+	// not parallel: the package level heartbeat interval is modified
+	defaultInterval := buildHeartbeatInterval
+	buildHeartbeatInterval = 5 * time.Millisecond
+	defer func() { buildHeartbeatInterval = defaultInterval }()
+
+	newClient := func(body io.ReadCloser) kymaDockerClient {
+		mockDocker := &mocks.Client{}
+		mockDocker.On("ArchiveDirectory", "foo", mock.Anything).Return(ioutil.NopCloser(strings.NewReader("foo")), nil)
+		mockDocker.On("NegotiateAPIVersion", mock.Anything).Return(nil)
+		mockDocker.On("ImageBuild", mock.Anything, mock.Anything, mock.Anything).Return(imageTypes.ImageBuildResponse{Body: body}, nil)
+		return kymaDockerClient{Docker: mockDocker}
+	}
+
+	// errors reported in the build output fail the build
+	k := newClient(ioutil.NopCloser(strings.NewReader("{\"stream\":\"Step 1/4\"}\n{\"error\":\"COPY failed: no such file\"}\n")))
+	err := k.BuildKymaInstaller("foo", "kyma-installer", time.Minute, nil)
+	require.EqualError(t, err, "failed to build Docker image: COPY failed: no such file")
+
+	// a build which does not finish reports the elapsed time and fails with the timeout
+	body, w := io.Pipe()
+	defer w.Close()
+	k = newClient(body)
+	s := &stepMocks.Step{}
+	err = k.BuildKymaInstaller("foo", "kyma-installer", 50*time.Millisecond, s)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "did not finish within 50ms")
+	require.Contains(t, err.Error(), "minikube ssh -- docker info")
+	require.NotEmpty(t, s.Statuses())
+	require.Contains(t, s.Statuses()[0], "still building the Kyma Installer image")
+}
+
 func Test_PushKymaInstaller(t *testing.T) {
 	t.Parallel()
 	tmpHome, err := ioutil.TempDir("/tmp", "config-pus-kyma-test")
@@ -169,3 +206,100 @@ func Test_PushKymaInstaller(t *testing.T) {
 	assert.NilError(t, err)
 
 }
+
+func Test_PullImages(t *testing.T) {
+	t.Parallel()
+	mockDocker := &mocks.Client{}
+	k := kymaDockerClient{
+		Docker: mockDocker,
+	}
+	mockDocker.On("NegotiateAPIVersion", mock.Anything).Return(nil)
+	mockDocker.On("ImagePull", mock.Anything, "example.com/foo:1.0", mock.Anything).Return(ioutil.NopCloser(strings.NewReader(`{"status":"Pulling"}`+"\n")), nil)
+	mockDocker.On("ImagePull", mock.Anything, "example.com/bar:1.0", mock.Anything).Return(ioutil.NopCloser(strings.NewReader(`{"error":"manifest unknown"}`+"\n")), nil)
+	mockDocker.On("ImagePull", mock.Anything, "example.com/baz:1.0", mock.Anything).Return(nil, fmt.Errorf("connection refused"))
+
+	var progress []int
+	errs := k.PullImages([]string{"example.com/foo:1.0", "example.com/bar:1.0", "example.com/baz:1.0"}, 2, func(done, total int) {
+		require.Equal(t, 3, total)
+		progress = append(progress, done)
+	})
+	require.Len(t, errs, 2)
+	require.Equal(t, []int{1, 2, 3}, progress)
+}
+
+func Test_DiskInfo(t *testing.T) {
+	t.Parallel()
+	mockDocker := &mocks.Client{}
+	k := kymaDockerClient{
+		Docker: mockDocker,
+	}
+	mockDocker.On("NegotiateAPIVersion", mock.Anything).Return(nil)
+	mockDocker.On("Info", mock.Anything).Return(imageTypes.Info{DockerRootDir: "/var/lib/docker", Images: 2}, nil)
+	mockDocker.On("DiskUsage", mock.Anything).Return(imageTypes.DiskUsage{
+		LayersSize: 300,
+		Images: []*imageTypes.ImageSummary{
+			{ID: "sha256:used", Size: 200, SharedSize: 50, Containers: 1},
+			{ID: "sha256:unused", Size: 100, SharedSize: 50},
+		},
+	}, nil)
+
+	info, err := k.DiskInfo()
+	require.NoError(t, err)
+	require.Equal(t, DiskInfo{RootDir: "/var/lib/docker", Images: 2, ImagesSize: 300, UnusedSize: 50}, info)
+}
+
+func Test_Prune(t *testing.T) {
+	t.Parallel()
+	mockDocker := &mocks.Client{}
+	k := kymaDockerClient{
+		Docker: mockDocker,
+	}
+	mockDocker.On("NegotiateAPIVersion", mock.Anything).Return(nil)
+	mockDocker.On("ContainersPrune", mock.Anything, mock.Anything).Return(imageTypes.ContainersPruneReport{ContainersDeleted: []string{"c1"}, SpaceReclaimed: 10}, nil)
+	mockDocker.On("DiskUsage", mock.Anything).Return(imageTypes.DiskUsage{
+		Images: []*imageTypes.ImageSummary{
+			{ID: "sha256:container", RepoTags: []string{"example.com/container:1.0"}, Size: 100, Containers: 1},
+			{ID: "sha256:pod", RepoTags: []string{"nginx:1.19"}, Size: 100},
+			{ID: "sha256:digest", RepoDigests: []string{"example.com/digest@sha256:abc"}, Size: 100},
+			{ID: "sha256:unused", RepoTags: []string{"example.com/unused:1.0"}, Size: 100, SharedSize: 20},
+		},
+	}, nil)
+	mockDocker.On("ImageRemove", mock.Anything, "sha256:unused", imageTypes.ImageRemoveOptions{PruneChildren: true}).Return(nil, nil)
+
+	report, err := k.Prune([]string{"docker.io/library/nginx:1.19", "docker-pullable://example.com/digest@sha256:abc"})
+	require.NoError(t, err)
+	require.Equal(t, PruneReport{Containers: 1, Images: 1, SpaceReclaimed: 90}, report)
+	mockDocker.AssertNumberOfCalls(t, "ImageRemove", 1)
+}
+
+func Test_ArchiveDirectoryWithSpaces(t *testing.T) {
+	t.Parallel()
+	dir, err := ioutil.TempDir("", "kyma docker test")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	// a checkout in a directory with spaces and non-ASCII characters, as it is common on macOS
+	src := filepath.Join(dir, "Dev Projects", "kymä – sources")
+	dockerfile := filepath.Join(src, "tools", "kyma-installer", "kyma.Dockerfile")
+	require.NoError(t, os.MkdirAll(filepath.Dir(dockerfile), 0700))
+	require.NoError(t, ioutil.WriteFile(dockerfile, []byte("FROM scratch\n"), 0600))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(src, "résumé notes.md"), []byte("notes\n"), 0600))
+
+	reader, err := (&dockerClient{}).ArchiveDirectory(src, &archive.TarOptions{})
+	require.NoError(t, err)
+	defer reader.Close()
+	entries := map[string]string{}
+	tr := tar.NewReader(reader)
+	for {
+		h, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		data, err := ioutil.ReadAll(tr)
+		require.NoError(t, err)
+		entries[h.Name] = string(data)
+	}
+	// the entries are relative to the build context, so the Dockerfile is found independent of the path of the sources
+	require.Equal(t, "FROM scratch\n", entries[path.Join("tools", "kyma-installer", "kyma.Dockerfile")])
+	require.Equal(t, "notes\n", entries["résumé notes.md"])
+}
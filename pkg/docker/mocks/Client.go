@@ -7,6 +7,8 @@ import (
 
 	archive "github.com/docker/docker/pkg/archive"
 
+	filters "github.com/docker/docker/api/types/filters"
+
 	io "io"
 
 	mock "github.com/stretchr/testify/mock"
@@ -42,6 +44,48 @@ func (_m *Client) ArchiveDirectory(srcPath string, options *archive.TarOptions)
 	return r0, r1
 }
 
+// ContainersPrune provides a mock function with given fields: ctx, pruneFilters
+func (_m *Client) ContainersPrune(ctx context.Context, pruneFilters filters.Args) (types.ContainersPruneReport, error) {
+	ret := _m.Called(ctx, pruneFilters)
+
+	var r0 types.ContainersPruneReport
+	if rf, ok := ret.Get(0).(func(context.Context, filters.Args) types.ContainersPruneReport); ok {
+		r0 = rf(ctx, pruneFilters)
+	} else {
+		r0 = ret.Get(0).(types.ContainersPruneReport)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, filters.Args) error); ok {
+		r1 = rf(ctx, pruneFilters)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DiskUsage provides a mock function with given fields: ctx
+func (_m *Client) DiskUsage(ctx context.Context) (types.DiskUsage, error) {
+	ret := _m.Called(ctx)
+
+	var r0 types.DiskUsage
+	if rf, ok := ret.Get(0).(func(context.Context) types.DiskUsage); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Get(0).(types.DiskUsage)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // ImageBuild provides a mock function with given fields: ctx, buildContext, options
 func (_m *Client) ImageBuild(ctx context.Context, buildContext io.Reader, options types.ImageBuildOptions) (types.ImageBuildResponse, error) {
 	ret := _m.Called(ctx, buildContext, options)
@@ -63,6 +107,59 @@ func (_m *Client) ImageBuild(ctx context.Context, buildContext io.Reader, option
 	return r0, r1
 }
 
+// ImageInspectWithRaw provides a mock function with given fields: ctx, imageID
+func (_m *Client) ImageInspectWithRaw(ctx context.Context, imageID string) (types.ImageInspect, []byte, error) {
+	ret := _m.Called(ctx, imageID)
+
+	var r0 types.ImageInspect
+	if rf, ok := ret.Get(0).(func(context.Context, string) types.ImageInspect); ok {
+		r0 = rf(ctx, imageID)
+	} else {
+		r0 = ret.Get(0).(types.ImageInspect)
+	}
+
+	var r1 []byte
+	if rf, ok := ret.Get(1).(func(context.Context, string) []byte); ok {
+		r1 = rf(ctx, imageID)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).([]byte)
+		}
+	}
+
+	var r2 error
+	if rf, ok := ret.Get(2).(func(context.Context, string) error); ok {
+		r2 = rf(ctx, imageID)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// ImagePull provides a mock function with given fields: ctx, image, options
+func (_m *Client) ImagePull(ctx context.Context, image string, options types.ImagePullOptions) (io.ReadCloser, error) {
+	ret := _m.Called(ctx, image, options)
+
+	var r0 io.ReadCloser
+	if rf, ok := ret.Get(0).(func(context.Context, string, types.ImagePullOptions) io.ReadCloser); ok {
+		r0 = rf(ctx, image, options)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(io.ReadCloser)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, types.ImagePullOptions) error); ok {
+		r1 = rf(ctx, image, options)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // ImagePush provides a mock function with given fields: ctx, image, options
 func (_m *Client) ImagePush(ctx context.Context, image string, options types.ImagePushOptions) (io.ReadCloser, error) {
 	ret := _m.Called(ctx, image, options)
@@ -86,6 +183,50 @@ func (_m *Client) ImagePush(ctx context.Context, image string, options types.Ima
 	return r0, r1
 }
 
+// ImageRemove provides a mock function with given fields: ctx, imageID, options
+func (_m *Client) ImageRemove(ctx context.Context, imageID string, options types.ImageRemoveOptions) ([]types.ImageDeleteResponseItem, error) {
+	ret := _m.Called(ctx, imageID, options)
+
+	var r0 []types.ImageDeleteResponseItem
+	if rf, ok := ret.Get(0).(func(context.Context, string, types.ImageRemoveOptions) []types.ImageDeleteResponseItem); ok {
+		r0 = rf(ctx, imageID, options)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]types.ImageDeleteResponseItem)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, types.ImageRemoveOptions) error); ok {
+		r1 = rf(ctx, imageID, options)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Info provides a mock function with given fields: ctx
+func (_m *Client) Info(ctx context.Context) (types.Info, error) {
+	ret := _m.Called(ctx)
+
+	var r0 types.Info
+	if rf, ok := ret.Get(0).(func(context.Context) types.Info); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Get(0).(types.Info)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // NegotiateAPIVersion provides a mock function with given fields: ctx
 func (_m *Client) NegotiateAPIVersion(ctx context.Context) {
 	_m.Called(ctx)
@@ -0,0 +1,111 @@
+package installation
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	pkgErrors "github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+var (
+	// activationTimeout is the maximum time to wait for the Kyma Installer to pick up the activated Installation CR
+	activationTimeout = time.Minute
+	// activationInterval is the time between two reads of the Installation CR while the activation is verified
+	activationInterval = 2 * time.Second
+)
+
+// installerLogLines is the number of log lines of the Kyma Installer shown if it does not pick up the Installation CR
+const installerLogLines int64 = 20
+
+// checkActivation verifies that the Kyma Installer picked up the Installation CR just activated, unless the check is skipped with the options
+func (i *Installation) checkActivation() error {
+	if i.Options.SkipActivationCheck {
+		return nil
+	}
+	return i.verifyActivation()
+}
+
+// verifyActivation waits until the Kyma Installer picks up the activated Installation CR, for at most activationTimeout.
+// A crash-looping Kyma Installer never removes the action label, so without the check the command would succeed with --wait=false although nothing is installed.
+// If the Installation CR is not picked up, the state of the Kyma Installer pods and their last log lines are returned with the error.
+func (i *Installation) verifyActivation() error {
+	deadline := time.Now().Add(activationTimeout)
+	for {
+		pickedUp, err := i.activationPickedUp()
+		if err != nil {
+			return err
+		}
+		if pickedUp {
+			return nil
+		}
+		if time.Now().After(deadline) {
+			break
+		}
+		time.Sleep(activationInterval)
+	}
+	return fmt.Errorf("the Kyma Installer did not pick up the Installation CR '%s' within %s. To skip this check, use --skip-activation-check\n%s",
+		i.installationName(), activationTimeout, i.installerDiagnostics())
+}
+
+// activationPickedUp checks if the Kyma Installer processes the Installation CR: it removes the action label once it picked it up, and reports its progress in the status
+func (i *Installation) activationPickedUp() (bool, error) {
+	cr, err := i.K8s.Dynamic().Resource(i.installationGVR()).Namespace(i.installationNamespace()).Get(context.Background(), i.installationName(), metav1.GetOptions{})
+	if err != nil {
+		return false, pkgErrors.Wrap(err, "unable to read the Installation CR")
+	}
+	if _, ok := cr.GetLabels()["action"]; !ok {
+		return true, nil
+	}
+	state, _, _ := unstructured.NestedString(cr.Object, "status", "state")
+	return state == "InProgress" || state == "Error", nil
+}
+
+// installerDiagnostics describes the Kyma Installer pods, their containers and their last log lines.
+// Errors are part of the description, as it is only shown together with another error.
+func (i *Installation) installerDiagnostics() string {
+	pods, err := i.K8s.Static().CoreV1().Pods(i.installerNamespace()).List(context.Background(), metav1.ListOptions{LabelSelector: i.installerLabel()})
+	if err != nil {
+		return fmt.Sprintf("Unable to list the Kyma Installer pods: %s", err)
+	}
+	if len(pods.Items) == 0 {
+		return fmt.Sprintf("No Kyma Installer pod exists in the namespace '%s'. Check the Kyma Installer Deployment with: kubectl -n %s describe deployment %s", i.installerNamespace(), i.installerNamespace(), installerDeployment)
+	}
+	var b strings.Builder
+	for _, pod := range pods.Items {
+		fmt.Fprintf(&b, "Pod %s: %s\n", pod.Name, pod.Status.Phase)
+		for _, c := range pod.Status.ContainerStatuses {
+			fmt.Fprintf(&b, "  container %s: %s, %d restarts\n", c.Name, containerState(c.State), c.RestartCount)
+		}
+		logs, err := i.K8s.Static().CoreV1().Pods(i.installerNamespace()).GetLogs(pod.Name, &corev1.PodLogOptions{TailLines: int64Ptr(installerLogLines)}).DoRaw(context.Background())
+		if err != nil {
+			fmt.Fprintf(&b, "  unable to read the logs: %s\n", err)
+			continue
+		}
+		if out := strings.TrimSpace(string(logs)); out != "" {
+			fmt.Fprintf(&b, "  last log lines:\n    %s\n", strings.ReplaceAll(out, "\n", "\n    "))
+		}
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// containerState describes the state of a container with its reason, e.g. "waiting (CrashLoopBackOff)"
+func containerState(s corev1.ContainerState) string {
+	switch {
+	case s.Waiting != nil:
+		return fmt.Sprintf("waiting (%s)", s.Waiting.Reason)
+	case s.Terminated != nil:
+		return fmt.Sprintf("terminated (%s, exit code %d)", s.Terminated.Reason, s.Terminated.ExitCode)
+	case s.Running != nil:
+		return "running"
+	}
+	return "unknown"
+}
+
+func int64Ptr(n int64) *int64 {
+	return &n
+}
@@ -0,0 +1,76 @@
+package installation
+
+import (
+	"testing"
+	"time"
+
+	k8sMocks "github.com/kyma-project/cli/internal/kube/mocks"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	fakeDynamic "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func activatedInstallationCR(labels map[string]interface{}, state string) *unstructured.Unstructured {
+	cr := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "installer.kyma-project.io/v1alpha1",
+		"kind":       "Installation",
+		"metadata":   map[string]interface{}{"name": "kyma-installation", "namespace": "default"},
+	}}
+	if labels != nil {
+		cr.Object["metadata"].(map[string]interface{})["labels"] = labels
+	}
+	if state != "" {
+		cr.Object["status"] = map[string]interface{}{"state": state}
+	}
+	return cr
+}
+
+func TestVerifyActivation(t *testing.T) {
+	// not parallel: the package level timeouts are modified
+	defaultTimeout, defaultInterval := activationTimeout, activationInterval
+	activationTimeout, activationInterval = 50*time.Millisecond, 10*time.Millisecond
+	defer func() { activationTimeout, activationInterval = defaultTimeout, defaultInterval }()
+
+	newInstallation := func(cr *unstructured.Unstructured, objects ...runtime.Object) *Installation {
+		kymaMock := &k8sMocks.KymaKube{}
+		kymaMock.On("Dynamic").Return(fakeDynamic.NewSimpleDynamicClient(runtime.NewScheme(), cr))
+		kymaMock.On("Static").Return(fake.NewSimpleClientset(objects...))
+		return &Installation{K8s: kymaMock, Options: &Options{InstallationName: "kyma-installation", NoWait: true}}
+	}
+
+	// the Kyma Installer removed the action label
+	require.NoError(t, newInstallation(activatedInstallationCR(nil, "")).verifyActivation())
+	// the Kyma Installer reports its progress before the label is removed
+	require.NoError(t, newInstallation(activatedInstallationCR(map[string]interface{}{"action": "install"}, "InProgress")).verifyActivation())
+
+	// the Kyma Installer is crash-looping
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "kyma-installer-6c8b9", Namespace: installerNamespace(), Labels: map[string]string{"name": "kyma-installer"}},
+		Status: corev1.PodStatus{
+			Phase: corev1.PodRunning,
+			ContainerStatuses: []corev1.ContainerStatus{{
+				Name:         "kyma-installer-container",
+				RestartCount: 4,
+				State:        corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}},
+			}},
+		},
+	}
+	err := newInstallation(activatedInstallationCR(map[string]interface{}{"action": "install"}, "Installed"), pod).verifyActivation()
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "the Kyma Installer did not pick up the Installation CR 'kyma-installation' within 50ms")
+	require.Contains(t, err.Error(), "Pod kyma-installer-6c8b9: Running\n  container kyma-installer-container: waiting (CrashLoopBackOff), 4 restarts")
+
+	// the Kyma Installer pod was never created
+	err = newInstallation(activatedInstallationCR(map[string]interface{}{"action": "install"}, "")).verifyActivation()
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "No Kyma Installer pod exists in the namespace 'kyma-installer'")
+
+	// the check can be skipped
+	i := newInstallation(activatedInstallationCR(map[string]interface{}{"action": "install"}, ""))
+	i.Options.SkipActivationCheck = true
+	require.NoError(t, i.checkActivation())
+}
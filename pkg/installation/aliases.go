@@ -0,0 +1,97 @@
+package installation
+
+import (
+	"strings"
+
+	"github.com/blang/semver/v4"
+	"github.com/kyma-project/kyma/components/kyma-operator/pkg/apis/installer/v1alpha1"
+)
+
+// componentName is the name of a component in the releases starting with the given version
+type componentName struct {
+	since string
+	name  string
+}
+
+// componentAliases translates the stable names of components, which are the names of the current releases, to the names used by older releases.
+// The mappings of an alias are ordered by version. Releases before the first mapping do not have the component.
+var componentAliases = map[string][]componentName{
+	"eventing": {
+		{since: "0.6.0", name: "event-bus"},
+		{since: "1.11.0", name: "eventing"},
+	},
+	"serverless": {
+		{since: "1.6.0", name: "function-controller"},
+		{since: "1.11.0", name: "serverless"},
+	},
+	"api-gateway": {
+		{since: "1.8.0", name: "api-gateway"},
+	},
+}
+
+// ComponentAliasMapping is the name of an aliased component in the releases starting with Since
+type ComponentAliasMapping struct {
+	Since string `yaml:"since"`
+	Name  string `yaml:"name"`
+}
+
+// ComponentAliases returns the mappings of the component aliases to the names used by the releases, ordered by version
+func ComponentAliases() map[string][]ComponentAliasMapping {
+	result := map[string][]ComponentAliasMapping{}
+	for alias, mappings := range componentAliases {
+		for _, m := range mappings {
+			result[alias] = append(result[alias], ComponentAliasMapping{Since: m.since, Name: m.name})
+		}
+	}
+	return result
+}
+
+// componentAlias returns the name of the aliased component in the given source, and false if the release has no such component.
+// Sources which are not a release (such as master or local sources) use the names of the current releases.
+func componentAlias(alias, source string) (string, bool) {
+	version, err := semver.Parse(source)
+	if err != nil {
+		return alias, true
+	}
+	name, found := "", false
+	for _, m := range componentAliases[alias] {
+		if version.GTE(semver.MustParse(m.since)) {
+			name, found = m.name, true
+		}
+	}
+	return name, found
+}
+
+// ResolveComponentAliases translates the aliases in the component list to the component names of the given source.
+// Aliases which have no mapping for the release are kept as they are and returned, so that they can be reported.
+func ResolveComponentAliases(components []v1alpha1.KymaComponent, source string) ([]v1alpha1.KymaComponent, []string) {
+	var unmapped []string
+	result := make([]v1alpha1.KymaComponent, 0, len(components))
+	for _, c := range components {
+		if _, ok := componentAliases[c.Name]; ok {
+			if name, found := componentAlias(c.Name, source); found {
+				c.Name = name
+			} else {
+				unmapped = append(unmapped, c.Name)
+			}
+		}
+		result = append(result, c)
+	}
+	return result, unmapped
+}
+
+// warnUnmappedAliases reports the aliases of the explicit component list which the release does not have
+func (i *Installation) warnUnmappedAliases() {
+	if i.componentsSource() == "" || i.currentStep == nil {
+		return
+	}
+	// the list was already loaded for the installation service, so errors are reported there
+	components, err := LoadComponents(i.Options.ComponentsConfig, i.Options.Profile)
+	if err != nil {
+		return
+	}
+	if _, unmapped := ResolveComponentAliases(components, i.Options.Source); len(unmapped) > 0 {
+		i.currentStep.LogErrorf("Warning: the component aliases %s have no mapping for Kyma %s, so they are installed with these names and the installation might fail",
+			strings.Join(unmapped, ", "), i.Options.Source)
+	}
+}
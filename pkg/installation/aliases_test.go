@@ -0,0 +1,52 @@
+package installation
+
+import (
+	"testing"
+
+	"github.com/kyma-project/kyma/components/kyma-operator/pkg/apis/installer/v1alpha1"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolveComponentAliases(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		alias    string
+		source   string
+		name     string
+		unmapped bool
+	}{
+		{alias: "eventing", source: "0.6.0", name: "event-bus"},
+		{alias: "eventing", source: "1.10.1", name: "event-bus"},
+		{alias: "eventing", source: "1.11.0", name: "eventing"},
+		{alias: "eventing", source: "1.15.1", name: "eventing"},
+		{alias: "serverless", source: "1.5.0", name: "serverless", unmapped: true},
+		{alias: "serverless", source: "1.6.0", name: "function-controller"},
+		{alias: "serverless", source: "1.11.0-rc1", name: "function-controller"},
+		{alias: "serverless", source: "1.14.0", name: "serverless"},
+		{alias: "api-gateway", source: "1.7.0", name: "api-gateway", unmapped: true},
+		{alias: "api-gateway", source: "1.8.0", name: "api-gateway"},
+		// sources which are not a release use the current names
+		{alias: "eventing", source: "master", name: "eventing"},
+		{alias: "serverless", source: "local", name: "serverless"},
+		{alias: "eventing", source: "PR-9486", name: "eventing"},
+		// names which are not an alias are kept
+		{alias: "istio", source: "0.6.0", name: "istio"},
+	}
+	for _, tc := range tests {
+		components := []v1alpha1.KymaComponent{{Name: tc.alias, Namespace: "kyma-system"}}
+		result, unmapped := ResolveComponentAliases(components, tc.source)
+		require.Equal(t, []v1alpha1.KymaComponent{{Name: tc.name, Namespace: "kyma-system"}}, result, "%s in %s", tc.alias, tc.source)
+		if tc.unmapped {
+			require.Equal(t, []string{tc.alias}, unmapped, "%s in %s", tc.alias, tc.source)
+		} else {
+			require.Empty(t, unmapped, "%s in %s", tc.alias, tc.source)
+		}
+	}
+}
+
+func TestComponentAliases(t *testing.T) {
+	t.Parallel()
+	aliases := ComponentAliases()
+	require.Len(t, aliases, len(componentAliases))
+	require.Equal(t, []ComponentAliasMapping{{Since: "0.6.0", Name: "event-bus"}, {Since: "1.11.0", Name: "eventing"}}, aliases["eventing"])
+}
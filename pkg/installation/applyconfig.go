@@ -0,0 +1,298 @@
+package installation
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"sort"
+	"strings"
+
+	"github.com/kyma-incubator/hydroform/install/config"
+	installationSDK "github.com/kyma-incubator/hydroform/install/installation"
+	"github.com/kyma-incubator/hydroform/install/scheme"
+	"github.com/kyma-project/kyma/components/kyma-operator/pkg/apis/installer/v1alpha1"
+	pkgErrors "github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apiErrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// appliedOverride is an override of the Kyma Installer found on the cluster, with the ConfigMap or Secret holding it
+type appliedOverride struct {
+	value    string
+	secret   bool
+	resource string
+}
+
+// configChange is the change of an override applied with apply-config
+type configChange struct {
+	component string
+	key       string
+	secret    bool
+	old       *appliedOverride
+	value     string
+}
+
+// String describes the change, the values of Secrets are not shown
+func (c configChange) String() string {
+	name := c.key
+	if c.component != "" {
+		name = fmt.Sprintf("%s:%s", c.component, c.key)
+	}
+	switch {
+	case c.old == nil && c.secret:
+		return fmt.Sprintf("+ %s (secret)", name)
+	case c.old == nil:
+		return fmt.Sprintf("+ %s: %q", name, c.value)
+	case c.secret:
+		return fmt.Sprintf("~ %s (secret changed)", name)
+	default:
+		return fmt.Sprintf("~ %s: %q -> %q", name, c.old.value, c.value)
+	}
+}
+
+// parseOverrideValues converts the --override entries in the format "[component:]key=value" into overrides of the Kyma Installer
+func parseOverrideValues(entries []string) (installationSDK.Configuration, error) {
+	var configuration installationSDK.Configuration
+	for _, entry := range entries {
+		parts := strings.SplitN(entry, "=", 2)
+		if len(parts) != 2 {
+			return configuration, fmt.Errorf("invalid override '%s', use the format key=value or component:key=value", entry)
+		}
+		var component string
+		key := strings.TrimSpace(parts[0])
+		if n := strings.Index(key, ":"); n >= 0 {
+			component, key = strings.TrimSpace(key[:n]), strings.TrimSpace(key[n+1:])
+			if component == "" {
+				return configuration, fmt.Errorf("invalid override '%s': the component must not be empty", entry)
+			}
+		}
+		if key == "" {
+			return configuration, fmt.Errorf("invalid override '%s': the key must not be empty", entry)
+		}
+		if component == "" {
+			configuration.Configuration.Set(key, parts[1], false)
+			continue
+		}
+		n := componentConfigurationIndex(&configuration, component)
+		configuration.ComponentConfiguration[n].Configuration.Set(key, parts[1], false)
+	}
+	return configuration, nil
+}
+
+// applyConfigSources returns the overrides given to apply-config, the override files first and the single values last, so that they take precedence
+func (i *Installation) applyConfigSources() ([]configSource, error) {
+	var sources []configSource
+	decoder, err := scheme.DefaultDecoder()
+	if err != nil {
+		return nil, fmt.Errorf("error: failed to create default decoder: %s", err.Error())
+	}
+	for _, file := range i.Options.OverrideConfigs {
+		data, err := ioutil.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("error: unable to open file: %s", err.Error())
+		}
+		configuration, err := config.YAMLToConfiguration(decoder, string(data))
+		if err != nil {
+			return nil, fmt.Errorf("error: failed to parse configurations of %s: %s", file, err.Error())
+		}
+		sources = append(sources, configSource{name: fmt.Sprintf("--override-file %s", file), configuration: configuration})
+	}
+
+	values, err := parseOverrideValues(i.Options.OverrideValues)
+	if err != nil {
+		return nil, err
+	}
+	sources = append(sources, configSource{name: "--override", configuration: values})
+	return sources, nil
+}
+
+// appliedOverrides reads the overrides of the Kyma Installer from the labeled ConfigMaps and Secrets, by component ("" for global overrides) and key
+func (i *Installation) appliedOverrides() (map[string]map[string]*appliedOverride, error) {
+	overrides := map[string]map[string]*appliedOverride{}
+	add := func(labels map[string]string, resource, key, value string, secret bool) {
+		component := labels[componentOverridesKey]
+		if overrides[component] == nil {
+			overrides[component] = map[string]*appliedOverride{}
+		}
+		overrides[component][key] = &appliedOverride{value: value, secret: secret, resource: resource}
+	}
+	selector := metav1.ListOptions{LabelSelector: fmt.Sprintf("%s=%s", overridesLabelKey, overridesLabelValue)}
+
+	configMaps, err := i.K8s.Static().CoreV1().ConfigMaps(i.installerNamespace()).List(context.Background(), selector)
+	if err != nil {
+		return nil, pkgErrors.Wrap(err, "unable to read the overrides of the Kyma Installer")
+	}
+	// the resources are sorted, so that a key given in several of them is always attributed to the same one
+	sort.Slice(configMaps.Items, func(a, b int) bool { return configMaps.Items[a].Name < configMaps.Items[b].Name })
+	for _, cm := range configMaps.Items {
+		for k, v := range cm.Data {
+			add(cm.Labels, cm.Name, k, v, false)
+		}
+	}
+	secrets, err := i.K8s.Static().CoreV1().Secrets(i.installerNamespace()).List(context.Background(), selector)
+	if err != nil {
+		return nil, pkgErrors.Wrap(err, "unable to read the overrides of the Kyma Installer")
+	}
+	sort.Slice(secrets.Items, func(a, b int) bool { return secrets.Items[a].Name < secrets.Items[b].Name })
+	for _, s := range secrets.Items {
+		for k, v := range s.Data {
+			add(s.Labels, s.Name, k, string(v), true)
+		}
+	}
+	return overrides, nil
+}
+
+// configChanges compares the configuration with the overrides on the cluster and returns the added and changed keys, sorted by component and key
+func configChanges(configuration installationSDK.Configuration, applied map[string]map[string]*appliedOverride) []configChange {
+	var changes []configChange
+	compare := func(component string, entries installationSDK.ConfigEntries) {
+		for _, entry := range entries {
+			old := applied[component][entry.Key]
+			if old != nil && old.value == entry.Value {
+				continue
+			}
+			changes = append(changes, configChange{component: component, key: entry.Key, secret: entry.Secret || (old != nil && old.secret), old: old, value: entry.Value})
+		}
+	}
+	compare("", configuration.Configuration)
+	for _, c := range configuration.ComponentConfiguration {
+		compare(c.Component, c.Configuration)
+	}
+	sort.SliceStable(changes, func(a, b int) bool {
+		if changes[a].component != changes[b].component {
+			return changes[a].component < changes[b].component
+		}
+		return changes[a].key < changes[b].key
+	})
+	return changes
+}
+
+// applyConfigChange writes the change into the ConfigMap or Secret which already holds the key, so that the Kyma Installer does not find it twice,
+// new keys are added to the resources hydroform creates for the overrides.
+func (i *Installation) applyConfigChange(c configChange) error {
+	name := fmt.Sprintf("%s-installer-config", c.component)
+	if c.component == "" {
+		name = "global-installer-config"
+	}
+	secret := c.secret
+	if c.old != nil {
+		name, secret = c.old.resource, c.old.secret
+	}
+	data := map[string]string{c.key: c.value}
+	if secret {
+		data[c.key] = base64.StdEncoding.EncodeToString([]byte(c.value))
+	}
+	patch, err := json.Marshal(map[string]interface{}{"data": data})
+	if err != nil {
+		return err
+	}
+
+	labels := map[string]string{overridesLabelKey: overridesLabelValue}
+	if c.component != "" {
+		labels[componentOverridesKey] = c.component
+	}
+	meta := metav1.ObjectMeta{Name: name, Namespace: i.installerNamespace(), Labels: labels}
+	i.addExtraMetadata(&meta)
+
+	if secret {
+		secrets := i.K8s.Static().CoreV1().Secrets(i.installerNamespace())
+		_, err = secrets.Patch(context.Background(), name, types.MergePatchType, patch, metav1.PatchOptions{})
+		if apiErrors.IsNotFound(err) {
+			_, err = secrets.Create(context.Background(), &corev1.Secret{ObjectMeta: meta, Data: map[string][]byte{c.key: []byte(c.value)}}, metav1.CreateOptions{})
+		}
+	} else {
+		configMaps := i.K8s.Static().CoreV1().ConfigMaps(i.installerNamespace())
+		_, err = configMaps.Patch(context.Background(), name, types.MergePatchType, patch, metav1.PatchOptions{})
+		if apiErrors.IsNotFound(err) {
+			_, err = configMaps.Create(context.Background(), &corev1.ConfigMap{ObjectMeta: meta, Data: map[string]string{c.key: c.value}}, metav1.CreateOptions{})
+		}
+	}
+	return pkgErrors.Wrapf(err, "unable to apply the override '%s'", c.key)
+}
+
+// ApplyConfiguration updates the overrides of the Kyma Installer with the --override values and --override-file files and triggers the installation again,
+// so that the Kyma Installer reconciles the components with the new overrides. The added and changed keys are shown before they are applied.
+// As the Kyma Installer reads the overrides when it starts processing the Installation CR, nothing is applied while an installation is in progress, unless --force is set.
+func (i *Installation) ApplyConfiguration() error {
+	if i.Options.CI || i.Options.NonInteractive {
+		i.Factory.NonInteractive = true
+	}
+	if len(i.Options.OverrideValues) == 0 && len(i.Options.OverrideConfigs) == 0 {
+		return fmt.Errorf("no overrides given, use --override or --override-file")
+	}
+	if err := i.discoverInstallationName(); err != nil {
+		return err
+	}
+	if i.Options.InstallationName == "" {
+		return fmt.Errorf("Kyma is not installed on the cluster")
+	}
+
+	s := i.newStep("Comparing the overrides with the cluster")
+	state, err := i.Service.CheckInstallationState(i.K8s.RestConfig(), i.Options.InstallationName)
+	installErr := installationSDK.InstallationError{}
+	if err != nil && !errors.As(err, &installErr) {
+		s.Failure()
+		return pkgErrors.Wrap(err, "unable to check the installation state")
+	}
+	if state.State == string(v1alpha1.StateInProgress) {
+		if !i.Options.Force {
+			s.Failure()
+			return fmt.Errorf("the installation is in progress, wait until it is done or use --force to apply the overrides anyway")
+		}
+		s.LogErrorf("Warning: the installation is in progress, the Kyma Installer might not use the new overrides before it is triggered again")
+	}
+
+	sources, err := i.applyConfigSources()
+	if err != nil {
+		s.Failure()
+		return err
+	}
+	configuration, _ := mergeConfigurations(sources)
+	applied, err := i.appliedOverrides()
+	if err != nil {
+		s.Failure()
+		return err
+	}
+	changes := configChanges(configuration, applied)
+	if len(changes) == 0 {
+		s.Successf("The overrides are already applied, nothing changed")
+		return nil
+	}
+	for _, c := range changes {
+		s.LogInfof("  %s", c)
+	}
+	s.Successf("%d overrides changed", len(changes))
+
+	if !i.Factory.NonInteractive && !s.PromptYesNo("Apply the changed overrides and install Kyma again? ") {
+		return fmt.Errorf("Aborting the update of the overrides")
+	}
+
+	s = i.newStep("Applying the overrides")
+	for _, c := range changes {
+		if err := i.applyConfigChange(c); err != nil {
+			s.Failure()
+			return err
+		}
+	}
+	patch, err := json.Marshal(activationPatch(i.metadata(), i.Options.InstallationName))
+	if err != nil {
+		s.Failure()
+		return err
+	}
+	if _, err := i.K8s.Dynamic().Resource(i.installationGVR()).Namespace(i.installationNamespace()).Patch(context.Background(), i.Options.InstallationName, types.MergePatchType, patch, metav1.PatchOptions{}); err != nil {
+		s.Failure()
+		return pkgErrors.Wrap(err, "unable to trigger the installation")
+	}
+	s.Successf("Overrides applied, the installation is triggered")
+
+	if i.Options.NoWait {
+		return nil
+	}
+	i.newStep("Waiting for the Kyma installation")
+	return i.waitForInstaller()
+}
@@ -0,0 +1,176 @@
+package installation
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	installSDK "github.com/kyma-incubator/hydroform/install/installation"
+	k8sMocks "github.com/kyma-project/cli/internal/kube/mocks"
+	"github.com/kyma-project/cli/pkg/installation/mocks"
+	"github.com/kyma-project/cli/pkg/step"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	fakeDynamic "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/rest"
+)
+
+func TestParseOverrideValues(t *testing.T) {
+	t.Parallel()
+	configuration, err := parseOverrideValues([]string{"global.domainName=example.com", "ory:hydra.enabled=true", "ory:hydra.url=http://a=b", "global.empty="})
+	require.NoError(t, err)
+	require.Equal(t, installSDK.ConfigEntries{
+		{Key: "global.domainName", Value: "example.com"},
+		{Key: "global.empty", Value: ""},
+	}, configuration.Configuration)
+	require.Equal(t, []installSDK.ComponentConfiguration{{Component: "ory", Configuration: installSDK.ConfigEntries{
+		{Key: "hydra.enabled", Value: "true"},
+		{Key: "hydra.url", Value: "http://a=b"},
+	}}}, configuration.ComponentConfiguration)
+
+	for _, entry := range []string{"no-value", "=value", ":key=value", "ory:=value"} {
+		_, err := parseOverrideValues([]string{entry})
+		require.Error(t, err, entry)
+	}
+}
+
+func TestApplyConfiguration(t *testing.T) {
+	t.Parallel()
+	dir, err := ioutil.TempDir("", "apply-config")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	overrides := filepath.Join(dir, "overrides.yaml")
+	require.NoError(t, ioutil.WriteFile(overrides, []byte(`apiVersion: v1
+kind: Secret
+metadata:
+  name: ory-overrides
+  namespace: kyma-installer
+  labels:
+    installer: overrides
+    component: ory
+type: Opaque
+data:
+  hydra.clientSecret: bmV3
+`), 0600))
+
+	labels := func(component string) map[string]string {
+		l := map[string]string{overridesLabelKey: overridesLabelValue}
+		if component != "" {
+			l[componentOverridesKey] = component
+		}
+		return l
+	}
+	static := fake.NewSimpleClientset(
+		&corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "feature-flags-overrides", Namespace: installerNamespace(), Labels: labels("")},
+			Data:       map[string]string{"global.disableLegacyConnectivity": "false", "global.domainName": "example.com"},
+		},
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "ory-installer-config", Namespace: installerNamespace(), Labels: labels("ory")},
+			Data:       map[string][]byte{"hydra.clientSecret": []byte("old")},
+		},
+	)
+	cr := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "installer.kyma-project.io/v1alpha1",
+		"kind":       "Installation",
+		"metadata":   map[string]interface{}{"name": "kyma-installation", "namespace": "default"},
+	}}
+	dynamic := fakeDynamic.NewSimpleDynamicClient(runtime.NewScheme(), cr)
+	kymaMock := &k8sMocks.KymaKube{}
+	kymaMock.On("Static").Return(static)
+	kymaMock.On("Dynamic").Return(dynamic)
+	kymaMock.On("RestConfig", mock.Anything).Return(&rest.Config{Host: "fake-kubeconfig-host"})
+	serviceMock := &mocks.Service{}
+	serviceMock.On("CheckInstallationState", mock.Anything, "kyma-installation").Return(installSDK.InstallationState{State: "Installed"}, nil)
+
+	i := &Installation{
+		K8s:     kymaMock,
+		Service: serviceMock,
+		Factory: step.Factory{NonInteractive: true},
+		Options: &Options{
+			NoWait:          true,
+			OverrideConfigs: []string{overrides},
+			OverrideValues:  []string{"global.disableLegacyConnectivity=true", "global.domainName=example.com", "ory:hydra.enabled=true"},
+		},
+	}
+
+	sources, err := i.applyConfigSources()
+	require.NoError(t, err)
+	configuration, _ := mergeConfigurations(sources)
+	applied, err := i.appliedOverrides()
+	require.NoError(t, err)
+	var diff []string
+	for _, c := range configChanges(configuration, applied) {
+		diff = append(diff, c.String())
+	}
+	require.Equal(t, []string{
+		`~ global.disableLegacyConnectivity: "false" -> "true"`,
+		"~ ory:hydra.clientSecret (secret changed)",
+		`+ ory:hydra.enabled: "true"`,
+	}, diff, "unchanged keys must not be listed")
+
+	require.NoError(t, i.ApplyConfiguration())
+
+	// changed keys stay in the resources holding them, new keys are added to the overrides of the component
+	flags, err := static.CoreV1().ConfigMaps(installerNamespace()).Get(context.Background(), "feature-flags-overrides", metav1.GetOptions{})
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"global.disableLegacyConnectivity": "true", "global.domainName": "example.com"}, flags.Data)
+	secret, err := static.CoreV1().Secrets(installerNamespace()).Get(context.Background(), "ory-installer-config", metav1.GetOptions{})
+	require.NoError(t, err)
+	require.Equal(t, "new", string(secret.Data["hydra.clientSecret"]))
+	ory, err := static.CoreV1().ConfigMaps(installerNamespace()).Get(context.Background(), "ory-installer-config", metav1.GetOptions{})
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"hydra.enabled": "true"}, ory.Data)
+	require.Equal(t, labels("ory"), ory.Labels)
+
+	// the installation is triggered again
+	installation, err := dynamic.Resource(installationGVR()).Namespace(installationNamespace()).Get(context.Background(), "kyma-installation", metav1.GetOptions{})
+	require.NoError(t, err)
+	require.Equal(t, "install", installation.GetLabels()["action"])
+
+	// applying the same overrides again changes nothing
+	require.NoError(t, i.ApplyConfiguration())
+}
+
+func TestApplyConfigurationInProgress(t *testing.T) {
+	t.Parallel()
+	cr := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "installer.kyma-project.io/v1alpha1",
+		"kind":       "Installation",
+		"metadata":   map[string]interface{}{"name": "kyma-installation", "namespace": "default"},
+	}}
+	dynamic := fakeDynamic.NewSimpleDynamicClient(runtime.NewScheme(), cr)
+	static := fake.NewSimpleClientset()
+	kymaMock := &k8sMocks.KymaKube{}
+	kymaMock.On("Static").Return(static)
+	kymaMock.On("Dynamic").Return(dynamic)
+	kymaMock.On("RestConfig", mock.Anything).Return(&rest.Config{Host: "fake-kubeconfig-host"})
+	serviceMock := &mocks.Service{}
+	serviceMock.On("CheckInstallationState", mock.Anything, "kyma-installation").Return(installSDK.InstallationState{State: "InProgress"}, nil)
+
+	i := &Installation{
+		K8s:     kymaMock,
+		Service: serviceMock,
+		Factory: step.Factory{NonInteractive: true},
+		Options: &Options{NoWait: true, OverrideValues: []string{"global.disableLegacyConnectivity=true"}},
+	}
+	err := i.ApplyConfiguration()
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "--force")
+	configMaps, err := static.CoreV1().ConfigMaps(installerNamespace()).List(context.Background(), metav1.ListOptions{})
+	require.NoError(t, err)
+	require.Empty(t, configMaps.Items, "nothing must be applied while the installation is in progress")
+
+	i.Options.Force = true
+	require.NoError(t, i.ApplyConfiguration())
+	global, err := static.CoreV1().ConfigMaps(installerNamespace()).Get(context.Background(), "global-installer-config", metav1.GetOptions{})
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"global.disableLegacyConnectivity": "true"}, global.Data)
+}
@@ -0,0 +1,207 @@
+package installation
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/kyma-project/cli/pkg/step"
+	pkgErrors "github.com/pkg/errors"
+)
+
+// extractProgressInterval is the number of extracted files after which the progress is updated
+const extractProgressInterval = 500
+
+// isSourceArchive checks if the local sources are given as an archive instead of a directory
+func isSourceArchive(path string) bool {
+	lower := strings.ToLower(path)
+	return strings.HasSuffix(lower, ".tar.gz") || strings.HasSuffix(lower, ".tgz") || strings.HasSuffix(lower, ".zip")
+}
+
+// extractLocalSources extracts the archive of the local sources to a temporary directory and uses it as the local source path.
+// The temporary directory is removed by cleanupLocalSources.
+func (i *Installation) extractLocalSources() error {
+	archive := i.Options.LocalSrcPath
+	if _, err := os.Stat(archive); err != nil {
+		return fmt.Errorf("configured 'src-path=%s' does not exist. Check if you configured a valid path", archive)
+	}
+	dir, err := ioutil.TempDir("", "kyma-sources-")
+	if err != nil {
+		return pkgErrors.Wrap(err, "unable to create a directory for the local sources")
+	}
+	i.extractedSources = dir
+
+	var s step.Step
+	if i.currentStep != nil {
+		s = i.currentStep.SubStep(fmt.Sprintf("Extracting local sources from '%s'", archive))
+	}
+	progress := func(files int) {
+		if s != nil && files%extractProgressInterval == 0 {
+			s.LogInfof("%d files extracted", files)
+		}
+	}
+
+	var files int
+	if strings.HasSuffix(strings.ToLower(archive), ".zip") {
+		files, err = extractZip(archive, dir, progress)
+	} else {
+		files, err = extractTarGz(archive, dir, progress)
+	}
+	if err != nil {
+		if s != nil {
+			s.Failure()
+		}
+		return pkgErrors.Wrapf(err, "unable to extract the local sources from '%s'", archive)
+	}
+	if s != nil {
+		s.Successf("Extracted %d files of the local sources from '%s'", files, archive)
+	}
+
+	i.Options.LocalSrcPath = sourceRoot(dir)
+	return nil
+}
+
+// cleanupLocalSources removes the sources extracted from an archive, unless they should be kept
+func (i *Installation) cleanupLocalSources() {
+	if i.extractedSources == "" {
+		return
+	}
+	if i.Options.KeepSources {
+		if i.currentStep != nil {
+			i.currentStep.LogInfof("Extracted local sources kept in '%s'", i.Options.LocalSrcPath)
+		}
+		return
+	}
+	os.RemoveAll(i.extractedSources)
+	i.extractedSources = ""
+}
+
+// sourceRoot returns the single top-level directory of the extracted archive, as created by most source archives (e.g. kyma-1.16.0/),
+// or the extraction directory itself
+func sourceRoot(dir string) string {
+	if _, err := os.Stat(filepath.Join(dir, "installation", "resources")); err == nil {
+		return dir
+	}
+	entries, err := ioutil.ReadDir(dir)
+	if err != nil || len(entries) != 1 || !entries[0].IsDir() {
+		return dir
+	}
+	return filepath.Join(dir, entries[0].Name())
+}
+
+// extractTarGz extracts a gzipped tar archive to the directory and returns the number of extracted files
+func extractTarGz(archive, dir string, progress func(files int)) (int, error) {
+	f, err := os.Open(archive)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		return 0, err
+	}
+	defer gz.Close()
+
+	var files int
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return files, nil
+		}
+		if err != nil {
+			return files, err
+		}
+		target, err := extractionTarget(dir, hdr.Name)
+		if err != nil {
+			return files, err
+		}
+		switch hdr.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(target, 0755); err != nil {
+				return files, err
+			}
+		case tar.TypeReg, tar.TypeRegA:
+			if err := writeFile(target, tr, os.FileMode(hdr.Mode)); err != nil {
+				return files, err
+			}
+			files++
+			progress(files)
+		case tar.TypeSymlink:
+			if _, err := extractionTarget(dir, filepath.Join(filepath.Dir(hdr.Name), hdr.Linkname)); err != nil || filepath.IsAbs(hdr.Linkname) {
+				return files, fmt.Errorf("the symbolic link '%s' points outside of the archive", hdr.Name)
+			}
+			if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+				return files, err
+			}
+			if err := os.Symlink(hdr.Linkname, target); err != nil {
+				return files, err
+			}
+		}
+	}
+}
+
+// extractZip extracts a zip archive to the directory and returns the number of extracted files
+func extractZip(archive, dir string, progress func(files int)) (int, error) {
+	r, err := zip.OpenReader(archive)
+	if err != nil {
+		return 0, err
+	}
+	defer r.Close()
+
+	var files int
+	for _, zf := range r.File {
+		target, err := extractionTarget(dir, zf.Name)
+		if err != nil {
+			return files, err
+		}
+		if zf.FileInfo().IsDir() {
+			if err := os.MkdirAll(target, 0755); err != nil {
+				return files, err
+			}
+			continue
+		}
+		rc, err := zf.Open()
+		if err != nil {
+			return files, err
+		}
+		err = writeFile(target, rc, zf.Mode())
+		rc.Close()
+		if err != nil {
+			return files, err
+		}
+		files++
+		progress(files)
+	}
+	return files, nil
+}
+
+// extractionTarget returns the path of an archive entry in the directory and rejects entries outside of it
+func extractionTarget(dir, name string) (string, error) {
+	target := filepath.Join(dir, name)
+	if target != filepath.Clean(dir) && !strings.HasPrefix(target, filepath.Clean(dir)+string(os.PathSeparator)) {
+		return "", fmt.Errorf("the archive entry '%s' points outside of the archive", name)
+	}
+	return target, nil
+}
+
+func writeFile(target string, r io.Reader, mode os.FileMode) error {
+	if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, mode.Perm()|0600)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(f, r); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
@@ -0,0 +1,135 @@
+package installation
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	stepMocks "github.com/kyma-project/cli/pkg/step/mocks"
+	"github.com/stretchr/testify/require"
+)
+
+var sourceArchiveFiles = map[string]string{
+	"kyma-1.16.0/installation/resources/installer.yaml":   "kind: Deployment\n",
+	"kyma-1.16.0/installation/resources/installer-cr.tpl": "kind: Installation\n",
+	"kyma-1.16.0/resources/core/Chart.yaml":               "name: core\n",
+}
+
+func writeTarGz(t *testing.T, path string, files map[string]string) {
+	f, err := os.Create(path)
+	require.NoError(t, err)
+	defer f.Close()
+	gz := gzip.NewWriter(f)
+	tw := tar.NewWriter(gz)
+	for name, content := range files {
+		require.NoError(t, tw.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: int64(len(content)), Typeflag: tar.TypeReg}))
+		_, err := tw.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, tw.Close())
+	require.NoError(t, gz.Close())
+}
+
+func writeZip(t *testing.T, path string, files map[string]string) {
+	f, err := os.Create(path)
+	require.NoError(t, err)
+	defer f.Close()
+	zw := zip.NewWriter(f)
+	for name, content := range files {
+		w, err := zw.Create(name)
+		require.NoError(t, err)
+		_, err = w.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, zw.Close())
+}
+
+func TestExtractLocalSources(t *testing.T) {
+	t.Parallel()
+	dir, err := ioutil.TempDir("", "kyma-archive-test")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	tarGz := filepath.Join(dir, "kyma.tar.gz")
+	writeTarGz(t, tarGz, sourceArchiveFiles)
+	zipFile := filepath.Join(dir, "kyma.zip")
+	writeZip(t, zipFile, sourceArchiveFiles)
+
+	for _, archive := range []string{tarGz, zipFile} {
+		t.Run(filepath.Base(archive), func(t *testing.T) {
+			s := &stepMocks.Step{}
+			i := &Installation{currentStep: s, Options: &Options{Source: "local", LocalSrcPath: archive, IsLocal: true}}
+
+			require.NoError(t, i.validateConfigurations())
+			require.Equal(t, filepath.Join(i.extractedSources, "kyma-1.16.0"), i.Options.LocalSrcPath)
+			data, err := ioutil.ReadFile(filepath.Join(i.Options.LocalSrcPath, "resources", "core", "Chart.yaml"))
+			require.NoError(t, err)
+			require.Equal(t, "name: core\n", string(data))
+			require.Len(t, s.SubSteps(), 1)
+			require.True(t, s.SubSteps()[0].IsSuccessful())
+
+			extracted := i.extractedSources
+			i.cleanupLocalSources()
+			_, err = os.Stat(extracted)
+			require.True(t, os.IsNotExist(err), "The extracted sources should be removed")
+		})
+	}
+
+	t.Run("Keep the extracted sources", func(t *testing.T) {
+		s := &stepMocks.Step{}
+		i := &Installation{currentStep: s, Options: &Options{Source: "local", LocalSrcPath: tarGz, IsLocal: true, KeepSources: true}}
+		require.NoError(t, i.validateConfigurations())
+		defer os.RemoveAll(i.extractedSources)
+
+		i.cleanupLocalSources()
+		_, err := os.Stat(i.Options.LocalSrcPath)
+		require.NoError(t, err)
+		require.Equal(t, []string{"Extracted local sources kept in '" + i.Options.LocalSrcPath + "'"}, s.Infos())
+	})
+
+	t.Run("Archive without a Kyma repository", func(t *testing.T) {
+		archive := filepath.Join(dir, "other.tar.gz")
+		writeTarGz(t, archive, map[string]string{"README.md": "no kyma here\n"})
+		i := &Installation{Options: &Options{Source: "local", LocalSrcPath: archive, IsLocal: true}}
+		err := i.validateConfigurations()
+		defer i.cleanupLocalSources()
+		require.EqualError(t, err, "configured 'src-path="+archive+"' does not seem to point to a Kyma repository. Check if your repository contains the 'installation/resources' folder")
+	})
+
+	t.Run("Entries outside of the archive", func(t *testing.T) {
+		archive := filepath.Join(dir, "evil.tar.gz")
+		writeTarGz(t, archive, map[string]string{"../evil.txt": "evil\n"})
+		i := &Installation{Options: &Options{Source: "local", LocalSrcPath: archive, IsLocal: true}}
+		err := i.validateConfigurations()
+		defer i.cleanupLocalSources()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "the archive entry '../evil.txt' points outside of the archive")
+	})
+
+	t.Run("Path with spaces and non-ASCII characters", func(t *testing.T) {
+		// checkouts on macOS are often located in directories like ~/Dev Projects
+		spaced := filepath.Join(dir, "Dev Projects", "kymä – O'Brien")
+		for name, content := range sourceArchiveFiles {
+			file := filepath.Join(spaced, filepath.FromSlash(name))
+			require.NoError(t, os.MkdirAll(filepath.Dir(file), 0700))
+			require.NoError(t, ioutil.WriteFile(file, []byte(content), 0600))
+		}
+		checkout := filepath.Join(spaced, "kyma-1.16.0")
+		i := &Installation{Options: &Options{Source: "local", LocalSrcPath: checkout, IsLocal: true}}
+		require.NoError(t, i.validateConfigurations())
+		require.Equal(t, checkout, i.Options.LocalSrcPath)
+
+		archive := filepath.Join(spaced, "kyma sources 1.16.0.tar.gz")
+		writeTarGz(t, archive, sourceArchiveFiles)
+		i = &Installation{Options: &Options{Source: "local", LocalSrcPath: archive, IsLocal: true}}
+		require.NoError(t, i.validateConfigurations())
+		defer i.cleanupLocalSources()
+		data, err := ioutil.ReadFile(filepath.Join(i.Options.LocalSrcPath, "resources", "core", "Chart.yaml"))
+		require.NoError(t, err)
+		require.Equal(t, "name: core\n", string(data))
+	})
+}
@@ -0,0 +1,122 @@
+package installation
+
+import (
+	"fmt"
+	"io/ioutil"
+	"sort"
+	"strings"
+
+	installationSDK "github.com/kyma-incubator/hydroform/install/installation"
+	"github.com/kyma-project/cli/internal/files"
+	pkgErrors "github.com/pkg/errors"
+	"gopkg.in/yaml.v2"
+)
+
+// chartValues is a Helm values file given for a component with --chart-values
+type chartValues struct {
+	component string
+	path      string
+}
+
+// parseChartValues splits the --chart-values entries in the format "component=path"
+func parseChartValues(entries []string) ([]chartValues, error) {
+	var result []chartValues
+	for _, entry := range entries {
+		parts := strings.SplitN(entry, "=", 2)
+		if len(parts) != 2 || strings.TrimSpace(parts[0]) == "" || strings.TrimSpace(parts[1]) == "" {
+			return nil, fmt.Errorf("invalid chart values '%s', use the format component=path/to/values.yaml", entry)
+		}
+		// the path is part of the value, so it is not normalized with the other path flags
+		path, err := files.NormalizePath(strings.TrimSpace(parts[1]))
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, chartValues{component: strings.TrimSpace(parts[0]), path: path})
+	}
+	return result, nil
+}
+
+// validateChartValues ensures that the --chart-values entries are well-formed and that the values files exist
+func (i *Installation) validateChartValues() error {
+	values, err := parseChartValues(i.Options.ChartValues)
+	if err != nil {
+		return err
+	}
+	for _, v := range values {
+		if _, err := ioutil.ReadFile(v.path); err != nil {
+			return fmt.Errorf("unable to read the chart values of the component '%s': %s", v.component, err)
+		}
+	}
+	return nil
+}
+
+// chartValuesSources converts the values files into component overrides of the Kyma Installer, which creates the override ConfigMap
+// of each component from them. The values are merged into the overrides of the component given in the other sources,
+// several files of the same component are applied in the given order.
+func (i *Installation) chartValuesSources(installerCRFile *File) ([]configSource, error) {
+	values, err := parseChartValues(i.Options.ChartValues)
+	if err != nil || len(values) == 0 {
+		return nil, err
+	}
+	components, err := i.componentList(installerCRFile)
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, c := range components {
+		names = append(names, c.Name)
+	}
+
+	var sources []configSource
+	for _, v := range values {
+		if len(names) > 0 && !contains(names, v.component) {
+			return nil, fmt.Errorf("the component '%s' of --chart-values is not in the component list. Use one of: %s", v.component, strings.Join(names, ", "))
+		}
+		data, err := ioutil.ReadFile(v.path)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read the chart values of the component '%s': %s", v.component, err)
+		}
+		var doc map[interface{}]interface{}
+		if err := yaml.Unmarshal(data, &doc); err != nil {
+			return nil, pkgErrors.Wrapf(err, "unable to parse the chart values '%s'", v.path)
+		}
+		entries, err := flattenValues("", doc)
+		if err != nil {
+			return nil, pkgErrors.Wrapf(err, "unable to convert the chart values '%s'", v.path)
+		}
+		sources = append(sources, configSource{
+			name: fmt.Sprintf("--chart-values %s=%s", v.component, v.path),
+			configuration: installationSDK.Configuration{ComponentConfiguration: []installationSDK.ComponentConfiguration{
+				{Component: v.component, Configuration: entries},
+			}},
+		})
+	}
+	return sources, nil
+}
+
+// flattenValues converts nested Helm values into the dotted keys of the installer overrides, sorted by key
+func flattenValues(prefix string, values map[interface{}]interface{}) (installationSDK.ConfigEntries, error) {
+	var entries installationSDK.ConfigEntries
+	for k, v := range values {
+		key := fmt.Sprintf("%v", k)
+		if prefix != "" {
+			key = prefix + "." + key
+		}
+		switch value := v.(type) {
+		case map[interface{}]interface{}:
+			nested, err := flattenValues(key, value)
+			if err != nil {
+				return nil, err
+			}
+			entries = append(entries, nested...)
+		case []interface{}:
+			return nil, fmt.Errorf("the value of '%s' is a list, which cannot be set as an override", key)
+		case nil:
+			entries = append(entries, installationSDK.ConfigEntry{Key: key, Value: ""})
+		default:
+			entries = append(entries, installationSDK.ConfigEntry{Key: key, Value: fmt.Sprintf("%v", value)})
+		}
+	}
+	sort.Slice(entries, func(a, b int) bool { return entries[a].Key < entries[b].Key })
+	return entries, nil
+}
@@ -0,0 +1,111 @@
+package installation
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	installationSDK "github.com/kyma-incubator/hydroform/install/installation"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChartValues(t *testing.T) {
+	t.Parallel()
+	dir, err := ioutil.TempDir("", "kyma-chart-values-")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	values := func(name, content string) string {
+		file := filepath.Join(dir, name)
+		require.NoError(t, ioutil.WriteFile(file, []byte(content), 0600))
+		return file
+	}
+	pilot := values("pilot.yaml", `
+kyma_istio_operator:
+  components:
+    pilot:
+      k8s:
+        resources:
+          requests:
+            cpu: 50m
+            memory: 128Mi
+`)
+	memory := values("memory.yaml", "kyma_istio_operator.components.pilot.k8s.resources.requests.memory: 256Mi\nreplicas: 2\n")
+	list := values("list.yaml", "hosts:\n  - a\n  - b\n")
+	override := values("override.yaml", `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: istio-overrides
+  namespace: kyma-installer
+  labels:
+    installer: overrides
+    component: istio
+data:
+  global.proxy.enabled: "true"
+  kyma_istio_operator.components.pilot.k8s.resources.requests.cpu: "100m"
+`)
+	crFile := &File{Content: []map[string]interface{}{{
+		"kind": "Installation",
+		"spec": map[interface{}]interface{}{"components": []interface{}{
+			map[interface{}]interface{}{"name": "istio", "namespace": "istio-system"},
+			map[interface{}]interface{}{"name": "core", "namespace": "kyma-system"},
+		}},
+	}}}
+
+	t.Run("Merge into the overrides of the component", func(t *testing.T) {
+		i := &Installation{Options: &Options{
+			OverrideConfigs: []string{override},
+			ChartValues:     []string{"istio=" + pilot, "istio=" + memory},
+		}}
+		require.NoError(t, i.validateChartValues())
+		sources, err := i.loadConfigurationSources(map[string]*File{installerCRFile: crFile})
+		require.NoError(t, err)
+
+		configuration, origins := mergeConfigurations(sources)
+		require.Equal(t, []installationSDK.ComponentConfiguration{{
+			Component: "istio",
+			Configuration: installationSDK.ConfigEntries{
+				{Key: "global.proxy.enabled", Value: "true"},
+				{Key: "kyma_istio_operator.components.pilot.k8s.resources.requests.cpu", Value: "50m"},
+				{Key: "kyma_istio_operator.components.pilot.k8s.resources.requests.memory", Value: "256Mi"},
+				{Key: "replicas", Value: "2"},
+			},
+		}}, configuration.ComponentConfiguration)
+		require.Equal(t, "--chart-values istio="+memory, origins["istio"]["kyma_istio_operator.components.pilot.k8s.resources.requests.memory"])
+	})
+
+	t.Run("Unknown component", func(t *testing.T) {
+		i := &Installation{Options: &Options{ChartValues: []string{"monitoring=" + pilot}}}
+		_, err := i.loadConfigurationSources(map[string]*File{installerCRFile: crFile})
+		require.EqualError(t, err, "the component 'monitoring' of --chart-values is not in the component list. Use one of: istio, core")
+	})
+
+	t.Run("Component list of --components", func(t *testing.T) {
+		components := values("components.yaml", "components:\n  - name: monitoring\n    namespace: kyma-system\n")
+		i := &Installation{Options: &Options{ComponentsConfig: components, ChartValues: []string{"monitoring=" + memory}}}
+		sources, err := i.loadConfigurationSources(map[string]*File{installerCRFile: crFile})
+		require.NoError(t, err, "the component list of --components replaces the one of the Installation CR")
+		configuration, _ := mergeConfigurations(sources)
+		require.Equal(t, "monitoring", configuration.ComponentConfiguration[0].Component)
+
+		i.Options.ChartValues = []string{"istio=" + memory}
+		_, err = i.loadConfigurationSources(map[string]*File{installerCRFile: crFile})
+		require.EqualError(t, err, "the component 'istio' of --chart-values is not in the component list. Use one of: monitoring")
+	})
+
+	t.Run("Lists cannot be set", func(t *testing.T) {
+		i := &Installation{Options: &Options{ChartValues: []string{"core=" + list}}}
+		_, err := i.loadConfigurationSources(map[string]*File{installerCRFile: crFile})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "the value of 'hosts' is a list, which cannot be set as an override")
+	})
+
+	t.Run("Invalid entries", func(t *testing.T) {
+		i := &Installation{Options: &Options{ChartValues: []string{pilot}}}
+		require.EqualError(t, i.validateChartValues(), "invalid chart values '"+pilot+"', use the format component=path/to/values.yaml")
+
+		i.Options.ChartValues = []string{"istio=" + filepath.Join(dir, "missing.yaml")}
+		require.Error(t, i.validateChartValues())
+	})
+}
@@ -0,0 +1,176 @@
+package installation
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"regexp"
+	"strings"
+
+	"github.com/kyma-project/cli/internal/routes"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// clusterNetwork is a CIDR of the cluster, such as the range of the Service IPs, with the source it was read from
+type clusterNetwork struct {
+	kind   string
+	source string
+	cidr   *net.IPNet
+}
+
+// cidrProbeIP is an IP outside of the Service CIDRs of common clusters. Creating a Service with it in dry-run mode fails with an error naming the Service CIDR.
+const cidrProbeIP = "1.1.1.1"
+
+var serviceRangeError = regexp.MustCompile(`range of valid IPs is ([0-9a-fA-F.:/,]+)`)
+
+// checkCIDRs warns if the Service or pod CIDRs of the cluster overlap with the networks the local machine has routes for, for example of a corporate VPN.
+// Requests from the local machine to such addresses are sent to the other network, so the console is not reachable.
+// The check is best effort: the warnings are logged and added to the summary, but the installation never fails because of it.
+func (i *Installation) checkCIDRs() {
+	s := i.currentStep
+	local, err := i.readLocalRoutes()
+	if err != nil {
+		s.LogErrorf("Warning: unable to check the cluster networks for overlaps with the local routes: %s", err)
+		return
+	}
+	cluster, errs := clusterNetworks(i.K8s.Static())
+	for _, err := range errs {
+		s.LogErrorf("Warning: unable to check the cluster networks for overlaps with the local routes: %s", err)
+	}
+	for _, n := range cluster {
+		s.LogInfof("The %s CIDR of the cluster is %s (%s)", n.kind, n.cidr, n.source)
+	}
+	i.cidrOverlaps = cidrOverlaps(cluster, local)
+	for _, o := range i.cidrOverlaps {
+		s.LogErrorf("Warning: %s", o)
+	}
+}
+
+func (i *Installation) readLocalRoutes() ([]*net.IPNet, error) {
+	if i.localRoutes != nil {
+		return i.localRoutes()
+	}
+	return routes.Local()
+}
+
+// cidrOverlaps describes the cluster networks which overlap with a local route
+func cidrOverlaps(cluster []clusterNetwork, local []*net.IPNet) []string {
+	var overlaps []string
+	for _, n := range cluster {
+		var conflicting []string
+		for _, r := range local {
+			if routes.Overlaps(n.cidr, r) {
+				conflicting = append(conflicting, r.String())
+			}
+		}
+		if len(conflicting) > 0 {
+			overlaps = append(overlaps, fmt.Sprintf("The %s CIDR %s of the cluster overlaps with the local routes to %s, for example of a VPN. "+
+				"Addresses of the cluster in this range, such as the console, might not be reachable from this machine. If the routes lead to the cluster, ignore this warning.",
+				n.kind, n.cidr, strings.Join(conflicting, ", ")))
+		}
+	}
+	return overlaps
+}
+
+// clusterNetworks reads the Service and pod CIDRs of the cluster.
+// On self-managed clusters, they are read from the flags of the API server and controller manager pods. On managed clusters, the Service CIDR is read from
+// the error returned for a Service with an IP outside of the range, and the pod CIDRs are read from the nodes. Each CIDR which cannot be determined is returned as an error.
+func clusterNetworks(k8s kubernetes.Interface) ([]clusterNetwork, []error) {
+	var networks []clusterNetwork
+	var errs []error
+
+	services, err := serviceNetworks(k8s)
+	if err != nil {
+		errs = append(errs, err)
+	}
+	networks = append(networks, services...)
+
+	pods, err := podNetworks(k8s)
+	if err != nil {
+		errs = append(errs, err)
+	}
+	return append(networks, pods...), errs
+}
+
+func serviceNetworks(k8s kubernetes.Interface) ([]clusterNetwork, error) {
+	if cidrs := controlPlaneFlag(k8s, "component=kube-apiserver", "--service-cluster-ip-range"); cidrs != "" {
+		return parseNetworks("Service", "API server flag --service-cluster-ip-range", cidrs)
+	}
+
+	probe := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{GenerateName: "kyma-cidr-check-", Namespace: "default"},
+		Spec: corev1.ServiceSpec{
+			ClusterIP: cidrProbeIP,
+			Ports:     []corev1.ServicePort{{Port: 443}},
+		},
+	}
+	_, err := k8s.CoreV1().Services("default").Create(context.Background(), probe, metav1.CreateOptions{DryRun: []string{metav1.DryRunAll}})
+	if err == nil {
+		return nil, fmt.Errorf("the Service CIDR could not be determined, the cluster accepted the Service IP %s", cidrProbeIP)
+	}
+	m := serviceRangeError.FindStringSubmatch(err.Error())
+	if m == nil {
+		return nil, fmt.Errorf("the Service CIDR could not be determined: %s", err)
+	}
+	return parseNetworks("Service", "Service IP validation of the API server", strings.TrimSuffix(m[1], "."))
+}
+
+func podNetworks(k8s kubernetes.Interface) ([]clusterNetwork, error) {
+	if cidrs := controlPlaneFlag(k8s, "component=kube-controller-manager", "--cluster-cidr"); cidrs != "" {
+		return parseNetworks("pod", "controller manager flag --cluster-cidr", cidrs)
+	}
+
+	nodes, err := k8s.CoreV1().Nodes().List(context.Background(), metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("the pod CIDRs could not be read from the nodes: %s", err)
+	}
+	var cidrs []string
+	for _, n := range nodes.Items {
+		cidrs = append(cidrs, n.Spec.PodCIDRs...)
+		if len(n.Spec.PodCIDRs) == 0 && n.Spec.PodCIDR != "" {
+			cidrs = append(cidrs, n.Spec.PodCIDR)
+		}
+	}
+	if len(cidrs) == 0 {
+		return nil, fmt.Errorf("the pod CIDRs could not be determined, the nodes do not list them")
+	}
+	return parseNetworks("pod", "nodes", strings.Join(cidrs, ","))
+}
+
+// controlPlaneFlag returns the value of a flag of the control plane pods with the label selector in kube-system, which only exist on self-managed clusters such as Minikube or kubeadm clusters
+func controlPlaneFlag(k8s kubernetes.Interface, selector, flag string) string {
+	pods, err := k8s.CoreV1().Pods("kube-system").List(context.Background(), metav1.ListOptions{LabelSelector: selector})
+	if err != nil {
+		return ""
+	}
+	for _, pod := range pods.Items {
+		for _, c := range pod.Spec.Containers {
+			for _, arg := range append(c.Command, c.Args...) {
+				if strings.HasPrefix(arg, flag+"=") {
+					return strings.TrimPrefix(arg, flag+"=")
+				}
+			}
+		}
+	}
+	return ""
+}
+
+// parseNetworks parses a comma-separated list of CIDRs, such as the one of a dual-stack cluster. Duplicates are removed.
+func parseNetworks(kind, source, cidrs string) ([]clusterNetwork, error) {
+	var networks []clusterNetwork
+	seen := map[string]bool{}
+	for _, c := range strings.Split(cidrs, ",") {
+		_, cidr, err := net.ParseCIDR(strings.TrimSpace(c))
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s CIDR '%s' in the %s", kind, c, source)
+		}
+		if seen[cidr.String()] {
+			continue
+		}
+		seen[cidr.String()] = true
+		networks = append(networks, clusterNetwork{kind: kind, source: source, cidr: cidr})
+	}
+	return networks, nil
+}
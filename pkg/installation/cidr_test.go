@@ -0,0 +1,109 @@
+package installation
+
+import (
+	"errors"
+	"net"
+	"testing"
+
+	"github.com/kyma-project/cli/internal/kube/mocks"
+	stepMocks "github.com/kyma-project/cli/pkg/step/mocks"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8sTesting "k8s.io/client-go/testing"
+)
+
+func cidr(t *testing.T, s string) *net.IPNet {
+	_, n, err := net.ParseCIDR(s)
+	require.NoError(t, err)
+	return n
+}
+
+func TestClusterNetworks(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Self-managed cluster", func(t *testing.T) {
+		apiServer := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "kube-apiserver-minikube", Namespace: "kube-system", Labels: map[string]string{"component": "kube-apiserver"}},
+			Spec: corev1.PodSpec{Containers: []corev1.Container{{
+				Name:    "kube-apiserver",
+				Command: []string{"kube-apiserver", "--advertise-address=192.168.49.2", "--service-cluster-ip-range=10.96.0.0/12"},
+			}}},
+		}
+		controllerManager := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "kube-controller-manager-minikube", Namespace: "kube-system", Labels: map[string]string{"component": "kube-controller-manager"}},
+			Spec: corev1.PodSpec{Containers: []corev1.Container{{
+				Name:    "kube-controller-manager",
+				Command: []string{"kube-controller-manager", "--allocate-node-cidrs=true", "--cluster-cidr=10.244.0.0/16"},
+			}}},
+		}
+		networks, errs := clusterNetworks(fake.NewSimpleClientset(apiServer, controllerManager))
+		require.Empty(t, errs)
+		require.Equal(t, []clusterNetwork{
+			{kind: "Service", source: "API server flag --service-cluster-ip-range", cidr: cidr(t, "10.96.0.0/12")},
+			{kind: "pod", source: "controller manager flag --cluster-cidr", cidr: cidr(t, "10.244.0.0/16")},
+		}, networks)
+	})
+
+	t.Run("Managed cluster", func(t *testing.T) {
+		nodes := []runtime.Object{
+			&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-1"}, Spec: corev1.NodeSpec{PodCIDR: "100.96.0.0/24", PodCIDRs: []string{"100.96.0.0/24"}}},
+			&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-2"}, Spec: corev1.NodeSpec{PodCIDR: "100.96.1.0/24"}},
+		}
+		k8s := fake.NewSimpleClientset(nodes...)
+		k8s.PrependReactor("create", "services", func(k8sTesting.Action) (bool, runtime.Object, error) {
+			return true, nil, errors.New(`Service "kyma-cidr-check-x7k2p" is invalid: spec.clusterIP: Invalid value: "1.1.1.1": provided IP is not in the valid range. The range of valid IPs is 100.64.0.0/13`)
+		})
+		networks, errs := clusterNetworks(k8s)
+		require.Empty(t, errs)
+		require.Equal(t, []clusterNetwork{
+			{kind: "Service", source: "Service IP validation of the API server", cidr: cidr(t, "100.64.0.0/13")},
+			{kind: "pod", source: "nodes", cidr: cidr(t, "100.96.0.0/24")},
+			{kind: "pod", source: "nodes", cidr: cidr(t, "100.96.1.0/24")},
+		}, networks)
+	})
+
+	t.Run("Unknown CIDRs", func(t *testing.T) {
+		networks, errs := clusterNetworks(fake.NewSimpleClientset())
+		require.Empty(t, networks)
+		require.Len(t, errs, 2, "the fake accepts the Service IP and there are no nodes")
+	})
+}
+
+func TestCheckCIDRs(t *testing.T) {
+	t.Parallel()
+	node := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-1"}, Spec: corev1.NodeSpec{PodCIDRs: []string{"10.244.0.0/24"}}}
+
+	t.Run("Overlapping routes", func(t *testing.T) {
+		kymaMock := &mocks.KymaKube{}
+		kymaMock.On("Static").Return(fake.NewSimpleClientset(node))
+		s := &stepMocks.Step{}
+		i := &Installation{
+			K8s:         kymaMock,
+			currentStep: s,
+			localRoutes: func() ([]*net.IPNet, error) {
+				return []*net.IPNet{cidr(t, "10.0.0.0/8"), cidr(t, "10.244.0.0/16"), cidr(t, "192.168.1.0/24")}, nil
+			},
+			Options: &Options{CIDRCheck: true},
+		}
+		i.checkCIDRs()
+		overlap := "The pod CIDR 10.244.0.0/24 of the cluster overlaps with the local routes to 10.0.0.0/8, 10.244.0.0/16, for example of a VPN. " +
+			"Addresses of the cluster in this range, such as the console, might not be reachable from this machine. If the routes lead to the cluster, ignore this warning."
+		require.Equal(t, []string{overlap}, i.cidrOverlaps)
+		require.Contains(t, s.Errors(), "Warning: "+overlap)
+	})
+
+	t.Run("Routes cannot be read", func(t *testing.T) {
+		s := &stepMocks.Step{}
+		i := &Installation{
+			currentStep: s,
+			localRoutes: func() ([]*net.IPNet, error) { return nil, errors.New("netstat not found") },
+			Options:     &Options{CIDRCheck: true},
+		}
+		i.checkCIDRs()
+		require.Empty(t, i.cidrOverlaps)
+		require.Equal(t, []string{"Warning: unable to check the cluster networks for overlaps with the local routes: netstat not found"}, s.Errors())
+	})
+}
@@ -0,0 +1,307 @@
+package installation
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	pkgErrors "github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apiErrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+const (
+	// installInfoName is the ConfigMap recording the resources created by the CLI during an installation, until the installation succeeded
+	installInfoName         = "kyma-cli-install-info"
+	installInfoResourcesKey = "createdResources"
+)
+
+// clusterScopedKinds are the kinds of the installer files which do not belong to a namespace
+var clusterScopedKinds = map[string]bool{
+	"Namespace":                true,
+	"CustomResourceDefinition": true,
+	"ClusterRole":              true,
+	"ClusterRoleBinding":       true,
+	"PodSecurityPolicy":        true,
+	"PriorityClass":            true,
+}
+
+// CreatedResource is a resource which did not exist before the installation and is deleted by the cleanup
+type CreatedResource struct {
+	APIVersion string `json:"apiVersion"`
+	Kind       string `json:"kind"`
+	Namespace  string `json:"namespace,omitempty"`
+	Name       string `json:"name"`
+}
+
+func (r CreatedResource) String() string {
+	if r.Namespace == "" {
+		return fmt.Sprintf("%s %s", r.Kind, r.Name)
+	}
+	return fmt.Sprintf("%s %s/%s", r.Kind, r.Namespace, r.Name)
+}
+
+func (r CreatedResource) gvr() (schema.GroupVersionResource, error) {
+	gv, err := schema.ParseGroupVersion(r.APIVersion)
+	if err != nil {
+		return schema.GroupVersionResource{}, err
+	}
+	gvr, _ := meta.UnsafeGuessKindToResource(gv.WithKind(r.Kind))
+	return gvr, nil
+}
+
+// documentResource identifies the resource of a document of the installer files
+func documentResource(doc map[string]interface{}) (CreatedResource, bool) {
+	apiVersion, _ := doc["apiVersion"].(string)
+	kind, _ := doc["kind"].(string)
+	metadata, _ := doc["metadata"].(map[interface{}]interface{})
+	name, _ := metadata["name"].(string)
+	namespace, _ := metadata["namespace"].(string)
+	if apiVersion == "" || kind == "" || name == "" {
+		return CreatedResource{}, false
+	}
+	if clusterScopedKinds[kind] {
+		namespace = ""
+	} else if namespace == "" {
+		// hydroform applies namespaced resources without a namespace to the default namespace
+		namespace = "default"
+	}
+	return CreatedResource{APIVersion: apiVersion, Kind: kind, Namespace: namespace, Name: name}, true
+}
+
+// recordCreatedResources looks up which resources of the files do not exist yet, so that they can be deleted if the installation fails.
+// Resources whose existence cannot be determined are not recorded, so that the cleanup never deletes resources which existed before.
+// The resources recorded by a previous failed installation are kept, as they are still cleaned up with the ones of this run.
+func (i *Installation) recordCreatedResources(files ...*File) {
+	i.mergeRecordedResources()
+	for _, file := range files {
+		if file == nil {
+			continue
+		}
+		for _, doc := range file.Content {
+			r, ok := documentResource(doc)
+			if !ok || i.created(r) {
+				continue
+			}
+			gvr, err := r.gvr()
+			if err != nil {
+				continue
+			}
+			_, err = i.K8s.Dynamic().Resource(gvr).Namespace(r.Namespace).Get(context.Background(), r.Name, metav1.GetOptions{})
+			if apiErrors.IsNotFound(err) {
+				i.createdResources = append(i.createdResources, r)
+			}
+		}
+	}
+	if err := i.saveInstallInfo(); err != nil && i.currentStep != nil {
+		i.currentStep.LogErrorf("Warning: unable to record the resources created by the installation, \"kyma install cleanup\" will not find them: %s", err)
+	}
+}
+
+// mergeRecordedResources adds the resources and node changes recorded by a previous run, so that saving the install-info ConfigMap keeps them
+func (i *Installation) mergeRecordedResources() {
+	if previous, err := i.loadInstallInfo(); err == nil {
+		for _, r := range previous {
+			if !i.created(r) {
+				i.createdResources = append(i.createdResources, r)
+			}
+		}
+	}
+	if previous, err := recordedNodeChanges(i.K8s.Static(), i.installerNamespace()); err == nil {
+		i.nodeChanges = mergeNodeChanges(previous, i.nodeChanges)
+	}
+}
+
+// recordCreatedNamespace remembers a namespace the CLI created itself, e.g. for the installation lock
+func (i *Installation) recordCreatedNamespace(name string) {
+	r := CreatedResource{APIVersion: "v1", Kind: "Namespace", Name: name}
+	if !i.created(r) {
+		i.createdResources = append(i.createdResources, r)
+	}
+}
+
+func (i *Installation) created(r CreatedResource) bool {
+	for _, c := range i.createdResources {
+		if c == r {
+			return true
+		}
+	}
+	return false
+}
+
+// saveInstallInfo writes the created resources and the completed stages to the install-info ConfigMap,
+// so that they can be cleaned up or skipped by a later CLI invocation
+func (i *Installation) saveInstallInfo() error {
+	data, err := json.Marshal(i.createdResources)
+	if err != nil {
+		return err
+	}
+	stages, err := json.Marshal(i.completedStages)
+	if err != nil {
+		return err
+	}
+	info := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: installInfoName, Namespace: i.installerNamespace()},
+		Data:       map[string]string{installInfoResourcesKey: string(data), installInfoStagesKey: string(stages)},
+	}
+	if len(i.nodeChanges) > 0 {
+		nodes, err := json.Marshal(i.nodeChanges)
+		if err != nil {
+			return err
+		}
+		info.Data[installInfoNodesKey] = string(nodes)
+	}
+	i.addExtraMetadata(&info.ObjectMeta)
+	configMaps := i.K8s.Static().CoreV1().ConfigMaps(i.installerNamespace())
+	_, err = configMaps.Create(context.Background(), info, metav1.CreateOptions{})
+	if apiErrors.IsAlreadyExists(err) {
+		_, err = configMaps.Update(context.Background(), info, metav1.UpdateOptions{})
+	}
+	return err
+}
+
+// loadInstallInfo reads the resources created by a previous installation from the install-info ConfigMap
+func (i *Installation) loadInstallInfo() ([]CreatedResource, error) {
+	info, err := i.K8s.Static().CoreV1().ConfigMaps(i.installerNamespace()).Get(context.Background(), installInfoName, metav1.GetOptions{})
+	if apiErrors.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, pkgErrors.Wrap(err, "unable to read the resources created by the installation")
+	}
+	var resources []CreatedResource
+	if err := json.Unmarshal([]byte(info.Data[installInfoResourcesKey]), &resources); err != nil {
+		return nil, pkgErrors.Wrapf(err, "unable to parse the resources recorded in the ConfigMap '%s'", installInfoName)
+	}
+	return resources, nil
+}
+
+// forgetCreatedResources removes the install-info ConfigMap once the installation succeeded, so that its resources are never cleaned up
+// and the next installation starts from scratch. The labels and taints added to the nodes stay recorded, so that they are removed when Kyma is deleted.
+func (i *Installation) forgetCreatedResources() {
+	i.createdResources = nil
+	i.completedStages = nil
+	if len(i.nodeChanges) > 0 {
+		if err := i.saveInstallInfo(); err != nil && i.currentStep != nil {
+			i.currentStep.LogErrorf("Warning: unable to update the ConfigMap '%s/%s': %s", i.installerNamespace(), installInfoName, err)
+		}
+		return
+	}
+	err := i.K8s.Static().CoreV1().ConfigMaps(i.installerNamespace()).Delete(context.Background(), installInfoName, metav1.DeleteOptions{})
+	if err != nil && !apiErrors.IsNotFound(err) && i.currentStep != nil {
+		i.currentStep.LogErrorf("Warning: unable to delete the ConfigMap '%s/%s': %s", i.installerNamespace(), installInfoName, err)
+	}
+}
+
+// Cleanup deletes the resources created by the installation in the reverse order of their creation.
+// The resources of the current run are used, if there are none, the resources recorded by a previous run are read from the cluster.
+// Resources which are already gone are skipped. The deleted resources are returned.
+func (i *Installation) Cleanup() ([]CreatedResource, error) {
+	resources := i.createdResources
+	if len(resources) == 0 {
+		var err error
+		if resources, err = i.loadInstallInfo(); err != nil {
+			return nil, err
+		}
+	}
+	if err := i.revertNodeChanges(); err != nil {
+		return nil, err
+	}
+
+	var deleted []CreatedResource
+	var failed []string
+	propagation := metav1.DeletePropagationBackground
+	for n := len(resources) - 1; n >= 0; n-- {
+		r := resources[n]
+		gvr, err := r.gvr()
+		if err == nil {
+			err = i.K8s.Dynamic().Resource(gvr).Namespace(r.Namespace).Delete(context.Background(), r.Name, metav1.DeleteOptions{PropagationPolicy: &propagation})
+		}
+		switch {
+		case apiErrors.IsNotFound(err):
+		case err != nil:
+			failed = append(failed, fmt.Sprintf("%s: %s", r, err))
+		default:
+			deleted = append(deleted, r)
+		}
+	}
+	if len(failed) > 0 {
+		return deleted, errors.New("unable to delete " + strings.Join(failed, "; "))
+	}
+	i.forgetCreatedResources()
+	return deleted, nil
+}
+
+// revertNodeChanges removes the labels and taints the installation added to the nodes
+func (i *Installation) revertNodeChanges() error {
+	changes := i.nodeChanges
+	if len(changes) == 0 {
+		var err error
+		if changes, err = recordedNodeChanges(i.K8s.Static(), i.installerNamespace()); err != nil || len(changes) == 0 {
+			return err
+		}
+	}
+	reverted, err := revertRecordedNodeChanges(i.K8s.Static(), i.installerNamespace(), changes)
+	for _, r := range reverted {
+		if i.currentStep != nil {
+			i.currentStep.LogInfof("Reverted the %s", r)
+		}
+	}
+	if err != nil {
+		return err
+	}
+	i.nodeChanges = nil
+	return nil
+}
+
+// cleanupAfterFailure deletes the resources created by the failed installation if --cleanup-on-failure is set
+func (i *Installation) cleanupAfterFailure() {
+	if !i.Options.CleanupOnFailure || (len(i.createdResources) == 0 && len(i.nodeChanges) == 0) {
+		return
+	}
+	s := i.newStep("Cleaning up the resources created by the failed installation")
+	deleted, err := i.Cleanup()
+	for _, r := range deleted {
+		s.LogInfof("Deleted %s", r)
+	}
+	if err != nil {
+		s.Failuref("Cleanup failed, run \"kyma install cleanup\" to try again: %s", err)
+		return
+	}
+	s.Successf("Deleted %d resources created by the failed installation", len(deleted))
+}
+
+// CleanupInstallation deletes the resources recorded by a failed installation, e.g. with "kyma install cleanup".
+// Unless --force is set, the resources of a completed installation are not deleted.
+func (i *Installation) CleanupInstallation() ([]CreatedResource, error) {
+	if err := i.discoverInstallationName(); err != nil {
+		return nil, err
+	}
+	if i.Options.InstallationName != "" && !i.Options.Force {
+		state, err := i.Service.CheckInstallationState(i.K8s.RestConfig(), i.Options.InstallationName)
+		if err == nil && state.State == "Installed" {
+			return nil, errors.New("Kyma is installed on the cluster, so the resources of the installation are kept. Use --force to delete them anyway")
+		}
+	}
+
+	s := i.newStep("Deleting the resources created by the failed installation")
+	deleted, err := i.Cleanup()
+	for _, r := range deleted {
+		s.LogInfof("Deleted %s", r)
+	}
+	if err != nil {
+		s.Failure()
+		return deleted, err
+	}
+	if len(deleted) == 0 {
+		s.Successf("No resources of a failed installation found")
+		return nil, nil
+	}
+	s.Successf("Deleted %d resources created by the failed installation", len(deleted))
+	return deleted, nil
+}
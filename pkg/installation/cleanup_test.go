@@ -0,0 +1,91 @@
+package installation
+
+import (
+	"context"
+	"testing"
+
+	k8sMocks "github.com/kyma-project/cli/internal/kube/mocks"
+	stepMocks "github.com/kyma-project/cli/pkg/step/mocks"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	fakeDynamic "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestCleanupCreatedResources(t *testing.T) {
+	t.Parallel()
+	// the ServiceAccount existed before the installation, e.g. because it is shared with other tools
+	existing := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "ServiceAccount",
+		"metadata":   map[string]interface{}{"name": "kyma-installer", "namespace": "kyma-installer"},
+	}}
+	dynamic := fakeDynamic.NewSimpleDynamicClient(runtime.NewScheme(), existing)
+	static := fake.NewSimpleClientset(&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: installerNamespace()}})
+	kymaMock := &k8sMocks.KymaKube{}
+	kymaMock.On("Dynamic").Return(dynamic)
+	kymaMock.On("Static").Return(static)
+
+	s := &stepMocks.Step{}
+	i := &Installation{K8s: kymaMock, Options: &Options{CleanupOnFailure: true}, currentStep: s}
+	i.recordCreatedNamespace(installerNamespace())
+	installer := &File{Content: []map[string]interface{}{
+		{"apiVersion": "v1", "kind": "Namespace", "metadata": map[interface{}]interface{}{"name": installerNamespace()}},
+		{"apiVersion": "apiextensions.k8s.io/v1", "kind": "CustomResourceDefinition", "metadata": map[interface{}]interface{}{"name": "installations.installer.kyma-project.io"}},
+		{"apiVersion": "v1", "kind": "ServiceAccount", "metadata": map[interface{}]interface{}{"name": "kyma-installer", "namespace": "kyma-installer"}},
+		{"apiVersion": "apps/v1", "kind": "Deployment", "metadata": map[interface{}]interface{}{"name": "kyma-installer", "namespace": "kyma-installer"}},
+	}}
+	cr := &File{Content: []map[string]interface{}{
+		{"apiVersion": "installer.kyma-project.io/v1alpha1", "kind": "Installation", "metadata": map[interface{}]interface{}{"name": "kyma-installation"}},
+	}}
+	i.recordCreatedResources(installer, cr)
+	require.Equal(t, []CreatedResource{
+		{APIVersion: "v1", Kind: "Namespace", Name: installerNamespace()},
+		{APIVersion: "apiextensions.k8s.io/v1", Kind: "CustomResourceDefinition", Name: "installations.installer.kyma-project.io"},
+		{APIVersion: "apps/v1", Kind: "Deployment", Namespace: "kyma-installer", Name: "kyma-installer"},
+		{APIVersion: "installer.kyma-project.io/v1alpha1", Kind: "Installation", Namespace: "default", Name: "kyma-installation"},
+	}, i.createdResources, "pre-existing resources must not be recorded")
+	require.Empty(t, s.Errors())
+
+	// a later CLI invocation finds the resources in the install-info ConfigMap
+	later := &Installation{K8s: kymaMock, Options: &Options{}}
+	recorded, err := later.loadInstallInfo()
+	require.NoError(t, err)
+	require.Equal(t, i.createdResources, recorded)
+
+	// the resources are created by the apply, the Installation CR never made it to the cluster
+	for _, r := range i.createdResources[:3] {
+		gvr, err := r.gvr()
+		require.NoError(t, err)
+		obj := &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": r.APIVersion,
+			"kind":       r.Kind,
+			"metadata":   map[string]interface{}{"name": r.Name, "namespace": r.Namespace},
+		}}
+		_, err = dynamic.Resource(gvr).Namespace(r.Namespace).Create(context.Background(), obj, metav1.CreateOptions{})
+		require.NoError(t, err)
+	}
+
+	deleted, err := later.Cleanup()
+	require.NoError(t, err)
+	require.Equal(t, []CreatedResource{
+		{APIVersion: "apps/v1", Kind: "Deployment", Namespace: "kyma-installer", Name: "kyma-installer"},
+		{APIVersion: "apiextensions.k8s.io/v1", Kind: "CustomResourceDefinition", Name: "installations.installer.kyma-project.io"},
+		{APIVersion: "v1", Kind: "Namespace", Name: installerNamespace()},
+	}, deleted, "the resources are deleted in the reverse order and missing ones are skipped")
+
+	_, err = dynamic.Resource(serviceAccountGVR()).Namespace("kyma-installer").Get(context.Background(), "kyma-installer", metav1.GetOptions{})
+	require.NoError(t, err, "the pre-existing ServiceAccount must be kept")
+	recorded, err = later.loadInstallInfo()
+	require.NoError(t, err)
+	require.Empty(t, recorded, "the install-info is removed after the cleanup")
+}
+
+func serviceAccountGVR() schema.GroupVersionResource {
+	gvr, _ := CreatedResource{APIVersion: "v1", Kind: "ServiceAccount"}.gvr()
+	return gvr
+}
@@ -3,8 +3,10 @@ package installation
 import (
 	"context"
 	"strconv"
+	"time"
 
 	"github.com/kyma-project/cli/internal/kube"
+	"github.com/kyma-project/cli/internal/minikube"
 	apiErrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
@@ -17,6 +19,18 @@ type ClusterInfo struct {
 	LocalVMDriver string
 }
 
+// ResolveMinikubeProfile sets the Minikube profile of a local Minikube cluster, so that its Docker daemon, IP and status are read from the right VM.
+// The given profile takes precedence over the one recorded when the cluster was provisioned, without both the profile is detected from the kubeconfig context.
+func (c *ClusterInfo) ResolveMinikubeProfile(profile, kubeconfigPath string, verbose bool, timeout time.Duration) {
+	if !c.IsLocal || (c.Provider != "" && c.Provider != providerMinikube) {
+		return
+	}
+	c.Profile = minikube.ResolveProfile(verbose, timeout, profile, c.Profile, kube.CurrentContext(kubeconfigPath))
+}
+
+// GetClusterInfoFromConfigMap reads the cluster type written to the kube-system namespace when the cluster was provisioned.
+// If the ConfigMap does not exist, the cluster is a remote cluster. Errors are returned unchanged, so that callers can check
+// with apiErrors.IsForbidden if the user is not permitted to read the kube-system namespace.
 func GetClusterInfoFromConfigMap(kymaKube kube.KymaKube) (ClusterInfo, error) {
 	cm, err := kymaKube.Static().CoreV1().ConfigMaps("kube-system").Get(context.Background(), "kyma-cluster-info", metav1.GetOptions{})
 	if err != nil {
@@ -9,8 +9,10 @@ import (
 	"github.com/kyma-project/cli/internal/kube/mocks"
 	"github.com/stretchr/testify/require"
 	v1 "k8s.io/api/core/v1"
+	apiErrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/client-go/kubernetes/fake"
 )
 
@@ -83,4 +85,14 @@ func TestGetClusterInfoFromConfigMap(t *testing.T) {
 
 	ci, err = GetClusterInfoFromConfigMap(kymaMock)
 	require.Error(t, err, "Test case: Error getting cluster info")
+
+	// not permitted to read the kube-system namespace
+	k8sMock = fake.NewSimpleClientset()
+	k8sMock.PrependReactor("get", "configmaps", func(action k8sTesting.Action) (handled bool, ret runtime.Object, err error) {
+		return true, nil, apiErrors.NewForbidden(schema.GroupResource{Resource: "configmaps"}, "kyma-cluster-info", errors.New("no access"))
+	})
+	kymaMock.On("Static").Return(k8sMock).Once()
+
+	_, err = GetClusterInfoFromConfigMap(kymaMock)
+	require.True(t, apiErrors.IsForbidden(err), "Test case: Not permitted to get cluster info")
 }
@@ -0,0 +1,275 @@
+package installation
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/kyma-project/cli/internal/github"
+	"github.com/kyma-project/cli/internal/kube"
+	"github.com/kyma-project/cli/internal/release"
+	"github.com/kyma-project/kyma/components/kyma-operator/pkg/apis/installer/v1alpha1"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apiErrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilYaml "k8s.io/apimachinery/pkg/util/yaml"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	// ComponentStatusFailed is the status of a component without Helm release, for which the Kyma Installer reported an error
+	ComponentStatusFailed = "failed"
+	// ComponentStatusPending is the status of a component which has no Helm release yet
+	ComponentStatusPending = "pending"
+)
+
+// InstalledComponent is a component of the Installation CR and the Helm release installing it.
+type InstalledComponent struct {
+	// Name is the name of the component, which is also the name of its Helm release.
+	Name string `json:"name"`
+	// Namespace is the namespace the component is installed in.
+	Namespace string `json:"namespace"`
+	// Version is the version of the chart of the Helm release. It is empty for Helm 2 releases, as Tiller does not expose it.
+	Version string `json:"version,omitempty"`
+	// Revision is the revision of the Helm release, it is 0 if the component has no release.
+	Revision int `json:"revision,omitempty"`
+	// Status is the status of the Helm release (e.g. deployed), or ComponentStatusFailed or ComponentStatusPending if there is none.
+	Status string `json:"status"`
+}
+
+// ComponentDiff lists the components an upgrade to a release would add and remove.
+type ComponentDiff struct {
+	// Release is the version the installed components are compared with.
+	Release string `json:"release"`
+	// Added holds the components of the release which are not installed.
+	Added []string `json:"added"`
+	// Removed holds the installed components which are not part of the release.
+	Removed []string `json:"removed"`
+}
+
+// helmRelease is the part of a Helm release needed to describe a component
+type helmRelease struct {
+	name      string
+	namespace string
+	version   string
+	revision  int
+	status    string
+}
+
+// helm3Release holds the fields read from the JSON encoded release in the secrets of Helm 3
+type helm3Release struct {
+	Name      string `json:"name"`
+	Namespace string `json:"namespace"`
+	Version   int    `json:"version"`
+	Info      struct {
+		Status string `json:"status"`
+	} `json:"info"`
+	Chart struct {
+		Metadata struct {
+			Version string `json:"version"`
+		} `json:"metadata"`
+	} `json:"chart"`
+}
+
+// InstalledComponents returns the components of the Installation CR with the given name, together with their Helm releases.
+// The releases are read from the secrets of Helm 3 and, for older Kyma releases, from the ConfigMaps of Tiller.
+// If the name is empty, the Installation CR is discovered on the cluster. Without Installation CR, an empty list is returned.
+// Helm releases which cannot be read are skipped and reported with warn.
+func InstalledComponents(k8s kube.KymaKube, name string, warn func(format string, args ...interface{})) ([]InstalledComponent, error) {
+	var err error
+	m := clusterMetadata(k8s.Static())
+	if name == "" {
+		if name, err = findInstallationName(k8s, m); err != nil {
+			return nil, err
+		}
+		if name == "" {
+			return []InstalledComponent{}, nil
+		}
+	}
+
+	cr, err := k8s.Dynamic().Resource(m.InstallationGVR()).Namespace(m.InstallationNamespace).Get(context.Background(), name, metav1.GetOptions{})
+	if apiErrors.IsNotFound(err) {
+		return []InstalledComponent{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	helmReleases, err := listHelmReleases(k8s.Static(), warn)
+	if err != nil {
+		return nil, err
+	}
+	// like the status, the spec is read without its typed schema, as it differs across Kyma releases
+	spec, _ := cr.Object["spec"].(map[string]interface{})
+	status, _ := cr.Object["status"].(map[string]interface{})
+	failed := map[string]bool{}
+	for _, e := range parseComponentErrors(status) {
+		failed[e.Component] = true
+	}
+
+	result := []InstalledComponent{}
+	for _, entry := range listField(spec, "components") {
+		c := InstalledComponent{
+			Name:      firstString(entry, "name"),
+			Namespace: firstString(entry, "namespace"),
+			Status:    ComponentStatusPending,
+		}
+		if r, ok := helmReleases[c.Name]; ok {
+			c.Version, c.Revision, c.Status = r.version, r.revision, r.status
+			if r.namespace != "" {
+				c.Namespace = r.namespace
+			}
+		} else if failed[c.Name] {
+			c.Status = ComponentStatusFailed
+		}
+		result = append(result, c)
+	}
+	return result, nil
+}
+
+// listHelmReleases returns the latest revision of each Helm release by name.
+// The Helm 3 releases take precedence over the Tiller releases, as components are only migrated from Helm 2 to Helm 3.
+// A secret which cannot be decoded, e.g. of a release stored by another Helm version, is skipped with a warning.
+func listHelmReleases(k8s kubernetes.Interface, warn func(format string, args ...interface{})) (map[string]helmRelease, error) {
+	result := map[string]helmRelease{}
+	keepLatest := func(r helmRelease) {
+		if prev, ok := result[r.name]; !ok || prev.revision < r.revision {
+			result[r.name] = r
+		}
+	}
+
+	tiller, err := k8s.CoreV1().ConfigMaps(tillerNamespace()).List(context.Background(), metav1.ListOptions{LabelSelector: "OWNER=TILLER"})
+	// clusters on which Kyma runs on Helm 3 might not allow reading kube-system
+	if err != nil && !apiErrors.IsForbidden(err) {
+		return nil, errors.Wrap(err, "unable to list the Helm 2 releases")
+	}
+	if err == nil {
+		for _, cm := range tiller.Items {
+			keepLatest(tillerRelease(cm))
+		}
+	}
+	tillerReleases := result
+
+	result = map[string]helmRelease{}
+	secrets, err := k8s.CoreV1().Secrets("").List(context.Background(), metav1.ListOptions{LabelSelector: "owner=helm"})
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to list the Helm 3 releases")
+	}
+	for _, s := range secrets.Items {
+		r, err := decodeHelm3Release(s)
+		if err != nil {
+			warn("skipping the Helm release '%s/%s', which cannot be read: %s", s.Namespace, s.Name, err)
+			continue
+		}
+		keepLatest(r)
+	}
+
+	for name, r := range tillerReleases {
+		if _, ok := result[name]; !ok {
+			result[name] = r
+		}
+	}
+	return result, nil
+}
+
+// tillerRelease reads a Helm 2 release from the labels of its ConfigMap, the release itself is protobuf encoded
+func tillerRelease(cm corev1.ConfigMap) helmRelease {
+	revision, _ := strconv.Atoi(cm.Labels["VERSION"])
+	return helmRelease{
+		name:     cm.Labels["NAME"],
+		revision: revision,
+		status:   strings.ToLower(cm.Labels["STATUS"]),
+	}
+}
+
+// decodeHelm3Release reads a Helm 3 release from its secret, which holds the base64 encoded and gzipped JSON of the release
+func decodeHelm3Release(s corev1.Secret) (helmRelease, error) {
+	data, err := base64.StdEncoding.DecodeString(string(s.Data["release"]))
+	if err != nil {
+		return helmRelease{}, err
+	}
+	// releases are gzipped, unless they were stored by very early Helm 3 versions
+	if bytes.HasPrefix(data, []byte{0x1f, 0x8b}) {
+		reader, err := gzip.NewReader(bytes.NewReader(data))
+		if err != nil {
+			return helmRelease{}, err
+		}
+		defer reader.Close()
+		if data, err = ioutil.ReadAll(reader); err != nil {
+			return helmRelease{}, err
+		}
+	}
+	var r helm3Release
+	if err := json.Unmarshal(data, &r); err != nil {
+		return helmRelease{}, err
+	}
+	return helmRelease{
+		name:      r.Name,
+		namespace: r.Namespace,
+		version:   r.Chart.Metadata.Version,
+		revision:  r.Version,
+		status:    r.Info.Status,
+	}, nil
+}
+
+// ReleaseComponents returns the component list of the Installation CR of the release for the kind of cluster
+func ReleaseComponents(r *release.Release, profile release.ClusterProfile) ([]v1alpha1.KymaComponent, error) {
+	artifact := r.InstallerCR(profile)
+	reader, _, err := artifact.Open(github.DefaultClient)
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to read the Installation CR of Kyma %s", r.Version)
+	}
+	defer reader.Close()
+	return installationCRComponents(reader, artifact.Name)
+}
+
+// installationCRComponents returns the component list of the first Installation CR in the documents
+func installationCRComponents(reader io.Reader, name string) ([]v1alpha1.KymaComponent, error) {
+	decoder := utilYaml.NewYAMLOrJSONDecoder(reader, 4096)
+	for {
+		var cr v1alpha1.Installation
+		err := decoder.Decode(&cr)
+		if err == io.EOF {
+			return nil, fmt.Errorf("'%s' does not contain an Installation CR", name)
+		}
+		if err != nil {
+			return nil, errors.Wrapf(err, "unable to decode '%s'", name)
+		}
+		if cr.Kind == "Installation" {
+			return cr.Spec.Components, nil
+		}
+	}
+}
+
+// DiffComponents compares the installed components with the components of a release
+func DiffComponents(installed []InstalledComponent, version string, target []v1alpha1.KymaComponent) ComponentDiff {
+	diff := ComponentDiff{Release: version, Added: []string{}, Removed: []string{}}
+	current := map[string]bool{}
+	for _, c := range installed {
+		current[c.Name] = true
+	}
+	wanted := map[string]bool{}
+	for _, c := range target {
+		wanted[c.Name] = true
+		if !current[c.Name] {
+			diff.Added = append(diff.Added, c.Name)
+		}
+	}
+	for _, c := range installed {
+		if !wanted[c.Name] {
+			diff.Removed = append(diff.Removed, c.Name)
+		}
+	}
+	sort.Strings(diff.Added)
+	sort.Strings(diff.Removed)
+	return diff
+}
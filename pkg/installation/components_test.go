@@ -0,0 +1,136 @@
+package installation
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"fmt"
+	"strings"
+	"testing"
+
+	k8sMocks "github.com/kyma-project/cli/internal/kube/mocks"
+	"github.com/kyma-project/kyma/components/kyma-operator/pkg/apis/installer/v1alpha1"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	fakeDynamic "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func helm3Secret(t *testing.T, name, namespace string, revision string, release string) *corev1.Secret {
+	var b bytes.Buffer
+	w := gzip.NewWriter(&b)
+	_, err := w.Write([]byte(release))
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "sh.helm.release.v1." + name + ".v" + revision,
+			Namespace: namespace,
+			Labels:    map[string]string{"owner": "helm", "name": name, "version": revision},
+		},
+		Data: map[string][]byte{"release": []byte(base64.StdEncoding.EncodeToString(b.Bytes()))},
+	}
+}
+
+func TestInstalledComponents(t *testing.T) {
+	t.Parallel()
+	cr := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "installer.kyma-project.io/v1alpha1",
+		"kind":       "Installation",
+		"metadata":   map[string]interface{}{"name": "kyma-installation", "namespace": "default"},
+		"spec": map[string]interface{}{
+			"components": []interface{}{
+				map[string]interface{}{"name": "cluster-essentials", "namespace": "kyma-system"},
+				map[string]interface{}{"name": "istio", "namespace": "istio-system"},
+				map[string]interface{}{"name": "dex", "namespace": "kyma-system"},
+				map[string]interface{}{"name": "console", "namespace": "kyma-system"},
+			},
+		},
+		"status": map[string]interface{}{
+			"errorLog": []interface{}{
+				map[string]interface{}{"component": "dex", "log": "release dex failed", "occurrences": int64(1)},
+			},
+		},
+	}}
+	static := fake.NewSimpleClientset(
+		helm3Secret(t, "cluster-essentials", "kyma-system", "1", `{"name":"cluster-essentials","namespace":"kyma-system","version":1,"info":{"status":"superseded"},"chart":{"metadata":{"version":"1.15.0"}}}`),
+		helm3Secret(t, "cluster-essentials", "kyma-system", "2", `{"name":"cluster-essentials","namespace":"kyma-system","version":2,"info":{"status":"deployed"},"chart":{"metadata":{"version":"1.16.0"}}}`),
+		// the Helm 3 release of a migrated component takes precedence over the Tiller release
+		helm3Secret(t, "istio", "istio-system", "1", `{"name":"istio","namespace":"istio-system","version":1,"info":{"status":"deployed"},"chart":{"metadata":{"version":"1.4.10"}}}`),
+		&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "istio.v3", Namespace: "kube-system", Labels: map[string]string{"OWNER": "TILLER", "NAME": "istio", "STATUS": "DEPLOYED", "VERSION": "3"}}},
+		&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "console.v1", Namespace: "kube-system", Labels: map[string]string{"OWNER": "TILLER", "NAME": "console", "STATUS": "SUPERSEDED", "VERSION": "1"}}},
+		&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "console.v2", Namespace: "kube-system", Labels: map[string]string{"OWNER": "TILLER", "NAME": "console", "STATUS": "DEPLOYED", "VERSION": "2"}}},
+		// a secret which cannot be decoded is skipped
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "sh.helm.release.v1.broken.v1", Namespace: "kyma-system", Labels: map[string]string{"owner": "helm"}},
+			Data:       map[string][]byte{"release": []byte("not base64")},
+		},
+	)
+
+	kymaMock := k8sMocks.KymaKube{}
+	kymaMock.On("Dynamic").Return(fakeDynamic.NewSimpleDynamicClient(runtime.NewScheme(), cr))
+	kymaMock.On("Static").Return(static)
+	var warnings []string
+	warn := func(format string, args ...interface{}) { warnings = append(warnings, fmt.Sprintf(format, args...)) }
+	components, err := InstalledComponents(&kymaMock, "", warn)
+	require.NoError(t, err)
+	require.Equal(t, []InstalledComponent{
+		{Name: "cluster-essentials", Namespace: "kyma-system", Version: "1.16.0", Revision: 2, Status: "deployed"},
+		{Name: "istio", Namespace: "istio-system", Version: "1.4.10", Revision: 1, Status: "deployed"},
+		{Name: "dex", Namespace: "kyma-system", Status: ComponentStatusFailed},
+		{Name: "console", Namespace: "kyma-system", Revision: 2, Status: "deployed"},
+	}, components)
+	require.Len(t, warnings, 1)
+	require.Contains(t, warnings[0], "skipping the Helm release 'kyma-system/sh.helm.release.v1.broken.v1'")
+
+	// no installation on the cluster
+	kymaMock = k8sMocks.KymaKube{}
+	kymaMock.On("Dynamic").Return(fakeDynamic.NewSimpleDynamicClient(runtime.NewScheme()))
+	kymaMock.On("Static").Return(fake.NewSimpleClientset())
+	components, err = InstalledComponents(&kymaMock, "", warn)
+	require.NoError(t, err)
+	require.NotNil(t, components, "an empty list is printed as [] in JSON")
+	require.Empty(t, components)
+}
+
+func TestInstallationCRComponents(t *testing.T) {
+	t.Parallel()
+	cr := `apiVersion: v1
+kind: Namespace
+metadata:
+  name: kyma-installer
+---
+apiVersion: installer.kyma-project.io/v1alpha1
+kind: Installation
+metadata:
+  name: kyma-installation
+spec:
+  components:
+    - name: cluster-essentials
+      namespace: kyma-system
+    - name: istio
+      namespace: istio-system
+`
+	components, err := installationCRComponents(strings.NewReader(cr), "kyma-installer-cr-cluster.yaml")
+	require.NoError(t, err)
+	require.Equal(t, []v1alpha1.KymaComponent{{Name: "cluster-essentials", Namespace: "kyma-system"}, {Name: "istio", Namespace: "istio-system"}}, components)
+
+	_, err = installationCRComponents(strings.NewReader("kind: Namespace\n"), "kyma-installer-cr-cluster.yaml")
+	require.EqualError(t, err, "'kyma-installer-cr-cluster.yaml' does not contain an Installation CR")
+}
+
+func TestDiffComponents(t *testing.T) {
+	t.Parallel()
+	installed := []InstalledComponent{{Name: "istio"}, {Name: "knative-eventing"}, {Name: "dex"}}
+	target := []v1alpha1.KymaComponent{{Name: "istio"}, {Name: "eventing"}, {Name: "dex"}, {Name: "api-gateway"}}
+
+	diff := DiffComponents(installed, "1.16.0", target)
+	require.Equal(t, ComponentDiff{Release: "1.16.0", Added: []string{"api-gateway", "eventing"}, Removed: []string{"knative-eventing"}}, diff)
+
+	diff = DiffComponents(installed, "1.16.0", []v1alpha1.KymaComponent{{Name: "istio"}, {Name: "knative-eventing"}, {Name: "dex"}})
+	require.Empty(t, diff.Added)
+	require.Empty(t, diff.Removed)
+}
@@ -0,0 +1,310 @@
+package installation
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"io/ioutil"
+	"sort"
+	"strings"
+
+	"github.com/kyma-incubator/hydroform/install/config"
+	installationSDK "github.com/kyma-incubator/hydroform/install/installation"
+	"github.com/kyma-incubator/hydroform/install/scheme"
+	"github.com/kyma-project/kyma/components/kyma-operator/pkg/apis/installer/v1alpha1"
+	pkgErrors "github.com/pkg/errors"
+	"gopkg.in/yaml.v2"
+)
+
+// configSource is a set of overrides for the Kyma Installer together with its origin, e.g. an override file or a flag
+type configSource struct {
+	name          string
+	configuration installationSDK.Configuration
+}
+
+// configOrigins maps the component ("" for global overrides) and the key of each override to the name of the source which set it
+type configOrigins map[string]map[string]string
+
+func (o configOrigins) set(component, key, source string) {
+	if o[component] == nil {
+		o[component] = map[string]string{}
+	}
+	o[component][key] = source
+}
+
+// loadConfigurationSources returns the sources of the installer configuration in the order they are applied, later sources override earlier ones
+func (i *Installation) loadConfigurationSources(files map[string]*File) ([]configSource, error) {
+	var sources []configSource
+	decoder, err := scheme.DefaultDecoder()
+	if err != nil {
+		return nil, fmt.Errorf("error: failed to create default decoder: %s", err.Error())
+	}
+	parse := func(name, content string) error {
+		if strings.TrimSpace(content) == "" {
+			return nil
+		}
+		configuration, err := config.YAMLToConfiguration(decoder, content)
+		if err != nil {
+			return fmt.Errorf("error: failed to parse configurations: %s", err.Error())
+		}
+		sortConfiguration(&configuration)
+		sources = append(sources, configSource{name: name, configuration: configuration})
+		return nil
+	}
+
+	if i.Options.IsLocal {
+		//Start with the local config file
+		if err := parse(fmt.Sprintf("release configuration %s", files[installerConfigFile].Path), files[installerConfigFile].StringContent); err != nil {
+			return nil, err
+		}
+	}
+
+	if p, ok := profiles[i.Options.Profile]; ok {
+		if err := parse(fmt.Sprintf("--profile %s", p.Name), p.Overrides); err != nil {
+			return nil, err
+		}
+	}
+
+	// the --config files are layered in the given order, so later files override earlier ones
+	configs, err := i.configDocuments()
+	if err != nil {
+		return nil, err
+	}
+	for _, c := range configs {
+		if err := parse(fmt.Sprintf("--config %s", c.path), c.overrides); err != nil {
+			return nil, err
+		}
+	}
+
+	// the override files given first take precedence, so they are applied last
+	for n := len(i.Options.OverrideConfigs) - 1; n >= 0; n-- {
+		file := i.Options.OverrideConfigs[n]
+		data, err := ioutil.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("error: unable to open file: %s", err.Error())
+		}
+		if err := parse(fmt.Sprintf("--override %s", file), string(data)); err != nil {
+			return nil, err
+		}
+	}
+
+	chartValues, err := i.chartValuesSources(files[installerCRFile])
+	if err != nil {
+		return nil, err
+	}
+	sources = append(sources, chartValues...)
+
+	dexUsers, err := i.dexUsersSource(files[installerCRFile])
+	if err != nil {
+		return nil, err
+	}
+	sources = append(sources, dexUsers...)
+
+	flag := func(name string, entries ...installationSDK.ConfigEntry) {
+		sources = append(sources, configSource{name: name, configuration: installationSDK.Configuration{Configuration: entries}})
+	}
+	if entries := featureOverrides(i.Options.EnableFeatures, i.Options.DisableFeatures); len(entries) > 0 {
+		flag("--enable/--disable", entries...)
+	}
+	if i.Options.IsLocal {
+		flag("local cluster IP", installationSDK.ConfigEntry{Key: "global.minikubeIP", Value: i.Options.LocalCluster.IP})
+	}
+	if i.Options.Password != "" {
+		flag("--password", installationSDK.ConfigEntry{Key: "global.adminPassword", Value: base64.StdEncoding.EncodeToString([]byte(i.Options.Password))})
+	}
+	if i.Options.Domain != "" && i.Options.Domain != defaultDomain {
+		flag("--domain",
+			installationSDK.ConfigEntry{Key: "global.domainName", Value: i.Options.Domain},
+			installationSDK.ConfigEntry{Key: "global.tlsCrt", Value: i.Options.TLSCert},
+			installationSDK.ConfigEntry{Key: "global.tlsKey", Value: i.Options.TLSKey},
+		)
+	}
+
+	return sources, nil
+}
+
+// mergeConfigurations applies the sources in the given order and records which source set each value.
+// Applying them one by one equals parsing the concatenated sources, as later documents override earlier ones also in hydroform.
+func mergeConfigurations(sources []configSource) (installationSDK.Configuration, configOrigins) {
+	var merged installationSDK.Configuration
+	origins := configOrigins{}
+	for _, source := range sources {
+		for _, entry := range source.configuration.Configuration {
+			merged.Configuration.Set(entry.Key, entry.Value, entry.Secret)
+			origins.set("", entry.Key, source.name)
+		}
+		for _, component := range source.configuration.ComponentConfiguration {
+			n := componentConfigurationIndex(&merged, component.Component)
+			for _, entry := range component.Configuration {
+				merged.ComponentConfiguration[n].Configuration.Set(entry.Key, entry.Value, entry.Secret)
+				origins.set(component.Component, entry.Key, source.name)
+			}
+		}
+	}
+	return merged, origins
+}
+
+// sortConfiguration sorts the entries of a parsed configuration by key. The data of ConfigMaps and Secrets is a map,
+// so without it the merged configuration and the manifests applied for it would differ between runs.
+func sortConfiguration(configuration *installationSDK.Configuration) {
+	sortEntries := func(entries installationSDK.ConfigEntries) {
+		sort.SliceStable(entries, func(a, b int) bool { return entries[a].Key < entries[b].Key })
+	}
+	sortEntries(configuration.Configuration)
+	for _, c := range configuration.ComponentConfiguration {
+		sortEntries(c.Configuration)
+	}
+}
+
+// componentConfigurationIndex returns the index of the configuration of the component, which is added if it does not exist yet
+func componentConfigurationIndex(configuration *installationSDK.Configuration, component string) int {
+	for n, c := range configuration.ComponentConfiguration {
+		if c.Component == component {
+			return n
+		}
+	}
+	configuration.ComponentConfiguration = append(configuration.ComponentConfiguration, installationSDK.ComponentConfiguration{Component: component})
+	return len(configuration.ComponentConfiguration) - 1
+}
+
+// EffectiveConfiguration prepares the installation like a dry-run and returns the configuration the Kyma Installer would get as YAML.
+// Every value is annotated with the source which set it. Nothing is applied to the cluster.
+func (i *Installation) EffectiveConfiguration() (string, error) {
+	i.Options.DryRun = true
+	if i.Options.CI || i.Options.NonInteractive {
+		i.Factory.NonInteractive = true
+	}
+
+	s := i.newStep("Resolving installation configuration")
+	defer i.cleanupLocalSources()
+	if err := i.validateConfigurations(); err != nil {
+		s.Failure()
+		return "", err
+	}
+	i.checkInstallationSource()
+
+	files, err := i.prepareFiles()
+	if err != nil {
+		s.Failure()
+		return "", err
+	}
+	if files, err = loadStringContent(files); err != nil {
+		s.Failure()
+		return "", fmt.Errorf("Failed to load installation files: %s", err.Error())
+	}
+	sources, err := i.loadConfigurationSources(files)
+	if err != nil {
+		s.Failure()
+		return "", pkgErrors.Wrap(err, "unable to load the configurations")
+	}
+
+	componentsSource := fmt.Sprintf("release Installation CR %s", files[installerCRFile].Path)
+	if source := i.componentsSource(); source != "" {
+		componentsSource = source
+	}
+	components, err := i.componentList(files[installerCRFile])
+	if err != nil {
+		s.Failure()
+		return "", err
+	}
+
+	configuration, origins := mergeConfigurations(sources)
+	out := &bytes.Buffer{}
+	fmt.Fprintf(out, "# Components from %s:\n", componentsSource)
+	for _, c := range components {
+		fmt.Fprintf(out, "#   - %s (%s)\n", c.Name, c.Namespace)
+	}
+	if err := renderConfiguration(out, configuration, i.installerNamespace(), origins, i.Options.RedactSecrets); err != nil {
+		s.Failure()
+		return "", err
+	}
+
+	s.Successf("Configuration resolved, nothing applied")
+	return out.String(), nil
+}
+
+// renderConfiguration writes the ConfigMaps and Secrets applied for the configuration, with a comment naming the source above each value.
+// Documents without values are left out.
+func renderConfiguration(out *bytes.Buffer, configuration installationSDK.Configuration, namespace string, origins configOrigins, redact bool) error {
+	docs := configurationManifests(configuration, namespace)
+	for _, doc := range docs {
+		component, _ := docMetadata(doc)["labels"].(map[string]interface{})[componentOverridesKey].(string)
+		field := "data"
+		if stringField(doc, "kind") == "Secret" {
+			field = "stringData"
+		}
+		values, _ := doc[field].(map[string]interface{})
+		if len(values) == 0 {
+			continue
+		}
+
+		header, err := yaml.Marshal(yaml.MapSlice{
+			{Key: "apiVersion", Value: doc["apiVersion"]},
+			{Key: "kind", Value: doc["kind"]},
+			{Key: "metadata", Value: doc["metadata"]},
+		})
+		if err != nil {
+			return pkgErrors.Wrap(err, "unable to render the configuration")
+		}
+		fmt.Fprintf(out, "---\n%s%s:\n", header, field)
+
+		keys := make([]string, 0, len(values))
+		for k := range values {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			value := values[k]
+			if redact && field == "stringData" {
+				value = redactedValue
+			}
+			entry, err := yaml.Marshal(yaml.MapSlice{{Key: k, Value: value}})
+			if err != nil {
+				return pkgErrors.Wrapf(err, "unable to render the configuration value '%s'", k)
+			}
+			fmt.Fprintf(out, "  # from %s\n", origins[component][k])
+			for _, line := range strings.SplitAfter(strings.TrimSuffix(string(entry), "\n"), "\n") {
+				fmt.Fprintf(out, "  %s", line)
+			}
+			fmt.Fprintln(out)
+		}
+	}
+	return nil
+}
+
+// installerCRComponents returns the components listed in the Installation CR file
+func installerCRComponents(installerCRFile *File) []v1alpha1.KymaComponent {
+	if installerCRFile == nil {
+		return nil
+	}
+	var components []v1alpha1.KymaComponent
+	for _, doc := range installerCRFile.Content {
+		if kind, ok := doc["kind"]; !ok || kind != "Installation" {
+			continue
+		}
+		spec, _ := doc["spec"].(map[interface{}]interface{})
+		list, _ := spec["components"].([]interface{})
+		for _, c := range list {
+			component, _ := c.(map[interface{}]interface{})
+			components = append(components, v1alpha1.KymaComponent{Name: fmt.Sprintf("%v", component["name"]), Namespace: fmt.Sprintf("%v", component["namespace"])})
+		}
+	}
+	return components
+}
+
+// componentList returns the components the Kyma Installer installs: the component list of --components or the profile
+// with the feature toggles applied and the aliases translated if one is given, otherwise the list of the Installation CR file
+func (i *Installation) componentList(installerCRFile *File) ([]v1alpha1.KymaComponent, error) {
+	if i.componentsSource() == "" {
+		return installerCRComponents(installerCRFile), nil
+	}
+	list, err := LoadComponents(i.Options.ComponentsConfig, i.Options.Profile)
+	if err != nil {
+		return nil, pkgErrors.Wrap(err, "unable to load the component list")
+	}
+	if list, err = ApplyFeatures(list, i.Options.EnableFeatures, i.Options.DisableFeatures); err != nil {
+		return nil, err
+	}
+	list, _ = ResolveComponentAliases(list, i.Options.Source)
+	return list, nil
+}
@@ -0,0 +1,95 @@
+package installation
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	installationSDK "github.com/kyma-incubator/hydroform/install/installation"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMergeConfigurations(t *testing.T) {
+	t.Parallel()
+	dir, err := ioutil.TempDir("", "kyma-config-")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	override := func(name, value string) string {
+		file := filepath.Join(dir, name)
+		require.NoError(t, ioutil.WriteFile(file, []byte(`apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: overrides
+  namespace: kyma-installer
+  labels:
+    installer: overrides
+    component: istio
+    kyma-project.io/installation: ""
+data:
+  global.ingress.domainName: `+value+`
+`), 0600))
+		return file
+	}
+	first := override("first.yaml", "first")
+	second := override("second.yaml", "second")
+
+	i := &Installation{Options: &Options{
+		OverrideConfigs: []string{first, second},
+		Password:        "secret",
+	}}
+	sources, err := i.loadConfigurationSources(map[string]*File{})
+	require.NoError(t, err)
+	configuration, origins := mergeConfigurations(sources)
+
+	// the override file given first takes precedence
+	require.Len(t, configuration.ComponentConfiguration, 1)
+	entry, ok := configuration.ComponentConfiguration[0].Configuration.Get("global.ingress.domainName")
+	require.True(t, ok)
+	require.Equal(t, "first", entry.Value)
+	require.Equal(t, "--override "+first, origins["istio"]["global.ingress.domainName"])
+
+	entry, ok = configuration.Configuration.Get("global.adminPassword")
+	require.True(t, ok)
+	require.Equal(t, "c2VjcmV0", entry.Value)
+	require.Equal(t, "--password", origins[""]["global.adminPassword"])
+}
+
+func TestSortConfiguration(t *testing.T) {
+	t.Parallel()
+	configuration := installationSDK.Configuration{
+		Configuration: installationSDK.ConfigEntries{{Key: "global.tlsKey"}, {Key: "global.domainName"}},
+		ComponentConfiguration: []installationSDK.ComponentConfiguration{
+			{Component: "istio", Configuration: installationSDK.ConfigEntries{{Key: "replicas"}, {Key: "global.proxy.enabled"}}},
+		},
+	}
+	sortConfiguration(&configuration)
+	require.Equal(t, installationSDK.ConfigEntries{{Key: "global.domainName"}, {Key: "global.tlsKey"}}, configuration.Configuration)
+	require.Equal(t, installationSDK.ConfigEntries{{Key: "global.proxy.enabled"}, {Key: "replicas"}}, configuration.ComponentConfiguration[0].Configuration)
+}
+
+func TestRenderConfiguration(t *testing.T) {
+	t.Parallel()
+	configuration, origins := mergeConfigurations([]configSource{
+		{name: "defaults", configuration: installationSDK.Configuration{
+			Configuration: installationSDK.ConfigEntries{
+				{Key: "global.domainName", Value: "kyma.local"},
+				{Key: "global.tlsKey", Value: "key", Secret: true},
+			},
+		}},
+		{name: "--domain", configuration: installationSDK.Configuration{
+			Configuration: installationSDK.ConfigEntries{{Key: "global.domainName", Value: "kyma.example.com"}},
+		}},
+	})
+
+	out := &bytes.Buffer{}
+	require.NoError(t, renderConfiguration(out, configuration, installerNamespace(), origins, true))
+	require.Contains(t, out.String(), "data:\n  # from --domain\n  global.domainName: kyma.example.com\n")
+	require.Contains(t, out.String(), "stringData:\n  # from defaults\n  global.tlsKey: <redacted>\n")
+
+	out.Reset()
+	require.NoError(t, renderConfiguration(out, configuration, installerNamespace(), origins, false))
+	require.Contains(t, out.String(), "global.tlsKey: key\n")
+}
@@ -0,0 +1,99 @@
+package installation
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+
+	pkgErrors "github.com/pkg/errors"
+)
+
+// configDocument is a --config file split into the installer overrides and the Installation CR it contains
+type configDocument struct {
+	path string
+	// overrides holds the ConfigMaps and Secrets of the file as YAML documents
+	overrides      string
+	installationCR map[string]interface{}
+	rawCR          string
+}
+
+// configDocuments reads the --config files in the given order. The files are only read once, as they might be downloaded.
+func (i *Installation) configDocuments() ([]configDocument, error) {
+	if i.configDocs != nil || len(i.Options.Configs) == 0 {
+		return i.configDocs, nil
+	}
+	var docs []configDocument
+	for _, path := range i.Options.Configs {
+		doc, err := readConfigDocument(path)
+		if err != nil {
+			return nil, err
+		}
+		docs = append(docs, doc)
+	}
+	i.configDocs = docs
+	return docs, nil
+}
+
+func readConfigDocument(path string) (configDocument, error) {
+	doc := configDocument{path: path}
+	var reader io.ReadCloser
+	var err error
+	doc.path, reader, err = openSource(path)
+	if err != nil {
+		return doc, pkgErrors.Wrapf(err, "unable to read the configuration '%s'", path)
+	}
+	defer reader.Close()
+
+	content, raw, _, err := decodeDocuments(reader)
+	if err != nil {
+		return doc, pkgErrors.Wrapf(err, "unable to decode the configuration '%s'", path)
+	}
+	var overrides []string
+	for n, d := range content {
+		switch kind, _ := d["kind"].(string); kind {
+		case "ConfigMap", "Secret":
+			overrides = append(overrides, raw[n])
+		case "Installation":
+			if doc.installationCR != nil {
+				return doc, fmt.Errorf("the configuration '%s' contains more than one Installation CR", path)
+			}
+			doc.installationCR, doc.rawCR = d, raw[n]
+		default:
+			return doc, fmt.Errorf("the configuration '%s' contains a resource of kind '%s'. Only ConfigMaps and Secrets with installer overrides and an Installation CR are supported", path, kind)
+		}
+	}
+	doc.overrides = strings.Join(overrides, "\n---\n")
+	return doc, nil
+}
+
+// applyConfigInstallationCR replaces the Installation CR of the release with the one given in the --config files.
+// Installation CRs cannot be merged, so different Installation CRs in several files are an error.
+func (i *Installation) applyConfigInstallationCR(installationFiles map[string]*File) error {
+	docs, err := i.configDocuments()
+	if err != nil {
+		return err
+	}
+	var cr *configDocument
+	for n := range docs {
+		doc := &docs[n]
+		if doc.installationCR == nil {
+			continue
+		}
+		if cr != nil && !reflect.DeepEqual(cr.installationCR, doc.installationCR) {
+			return fmt.Errorf("the configurations '%s' and '%s' contain different Installation CRs, which cannot be merged. Keep the Installation CR in one of them", cr.path, doc.path)
+		}
+		cr = doc
+	}
+	if cr == nil {
+		return nil
+	}
+	file, ok := installationFiles[installerCRFile]
+	if !ok {
+		return nil
+	}
+	file.Path = cr.path
+	file.Content = []map[string]interface{}{cr.installationCR}
+	file.raw = []string{cr.rawCR}
+	return nil
+}
@@ -0,0 +1,85 @@
+package installation
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const configOverrides = `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: overrides
+  namespace: kyma-installer
+  labels:
+    installer: overrides
+    kyma-project.io/installation: ""
+data:
+`
+
+const configInstallationCR = `apiVersion: installer.kyma-project.io/v1alpha1
+kind: Installation
+metadata:
+  name: kyma-installation
+  namespace: default
+spec:
+  profile: %s
+`
+
+func TestConfigDocuments(t *testing.T) {
+	t.Parallel()
+	dir, err := ioutil.TempDir("", "kyma-config-")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	write := func(name, content string) string {
+		file := filepath.Join(dir, name)
+		require.NoError(t, ioutil.WriteFile(file, []byte(content), 0600))
+		return file
+	}
+
+	base := write("base.yaml", configOverrides+"  global.domainName: base.example.com\n  global.isBEBEnabled: \"false\"\n---\n"+fmt.Sprintf(configInstallationCR, "evaluation"))
+	patch := write("patch.yaml", configOverrides+"  global.domainName: prod.example.com\n")
+
+	t.Run("Merge in order", func(t *testing.T) {
+		i := &Installation{Options: &Options{Configs: []string{base, patch}}}
+		sources, err := i.loadConfigurationSources(map[string]*File{})
+		require.NoError(t, err)
+		configuration, origins := mergeConfigurations(sources)
+
+		// the later file overrides the values it sets and keeps the others
+		entry, ok := configuration.Configuration.Get("global.domainName")
+		require.True(t, ok)
+		require.Equal(t, "prod.example.com", entry.Value)
+		require.Equal(t, "--config "+patch, origins[""]["global.domainName"])
+		entry, ok = configuration.Configuration.Get("global.isBEBEnabled")
+		require.True(t, ok)
+		require.Equal(t, "false", entry.Value)
+		require.Equal(t, "--config "+base, origins[""]["global.isBEBEnabled"])
+
+		// the Installation CR of the configuration replaces the one of the release
+		files := map[string]*File{installerCRFile: {Path: "kyma-installer-cr-cluster.yaml"}}
+		require.NoError(t, i.applyConfigInstallationCR(files))
+		require.Equal(t, base, files[installerCRFile].Path)
+		require.Equal(t, "Installation 'kyma-installation'", documentKinds(files[installerCRFile]))
+	})
+
+	t.Run("Different Installation CRs", func(t *testing.T) {
+		other := write("other.yaml", fmt.Sprintf(configInstallationCR, "production"))
+		i := &Installation{Options: &Options{Configs: []string{base, other}}}
+		err := i.applyConfigInstallationCR(map[string]*File{installerCRFile: {}})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "contain different Installation CRs")
+	})
+
+	t.Run("Unsupported kind", func(t *testing.T) {
+		deployment := write("deployment.yaml", "apiVersion: apps/v1\nkind: Deployment\nmetadata:\n  name: app\n")
+		i := &Installation{Options: &Options{Configs: []string{deployment}}}
+		_, err := i.loadConfigurationSources(map[string]*File{})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "contains a resource of kind 'Deployment'")
+	})
+}
@@ -0,0 +1,124 @@
+package installation
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/blang/semver/v4"
+	apiErrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+var crdResource = schema.GroupVersionResource{Group: "apiextensions.k8s.io", Version: "v1beta1", Resource: "customresourcedefinitions"}
+
+// conflict is software installed on the cluster from another source, which conflicts with the components Kyma brings itself
+type conflict struct {
+	// name is the value of --allow-conflict acknowledging the conflict
+	name        string
+	consequence string
+	// since is the first Kyma release with the conflict, empty if all releases have it
+	since       string
+	namespaces  []string
+	crds        []string
+	deployments []string // namespace/name
+}
+
+// knownConflicts lists the software which makes the installation fail. Add an entry, or restrict one with since, when a release brings a new component.
+var knownConflicts = []conflict{
+	{
+		name:        "istio",
+		consequence: "Kyma installs its own Istio, so the webhooks and CRDs of both installations conflict and the Kyma Installer fails to install the istio component",
+		namespaces:  []string{"istio-system"},
+		crds:        []string{"virtualservices.networking.istio.io", "gateways.networking.istio.io"},
+		deployments: []string{"istio-system/istiod", "istio-system/istio-pilot"},
+	},
+	{
+		name:        "cert-manager",
+		consequence: "Kyma installs its own cert-manager, so the webhooks of both installations reject each other's certificates",
+		since:       "1.14.0",
+		namespaces:  []string{"cert-manager"},
+		crds:        []string{"certificates.cert-manager.io", "issuers.cert-manager.io", "certificates.certmanager.k8s.io"},
+		deployments: []string{"cert-manager/cert-manager-webhook"},
+	},
+	{
+		name:        "knative",
+		consequence: "Kyma installs its own Knative, so the installation fails on the existing Knative webhooks and CRDs",
+		namespaces:  []string{"knative-serving", "knative-eventing"},
+		crds:        []string{"services.serving.knative.dev", "brokers.eventing.knative.dev"},
+	},
+}
+
+// applies checks if the Kyma version has the conflict, versions other than releases are treated like the newest release
+func (c conflict) applies(source string) bool {
+	if c.since == "" {
+		return true
+	}
+	v, err := semver.ParseTolerant(source)
+	if err != nil {
+		return true
+	}
+	return v.GTE(semver.MustParse(c.since))
+}
+
+// foundConflict is a conflict detected on the cluster, with the resources which revealed it
+type foundConflict struct {
+	conflict
+	evidence []string
+}
+
+// findConflicts scans the cluster for the known conflicts of the Kyma version.
+// Resources which cannot be read are skipped, so that restricted users are not blocked.
+func (i *Installation) findConflicts() []foundConflict {
+	var found []foundConflict
+	for _, c := range knownConflicts {
+		if !c.applies(i.Options.Source) {
+			continue
+		}
+		var evidence []string
+		for _, ns := range c.namespaces {
+			if _, err := i.K8s.Static().CoreV1().Namespaces().Get(context.Background(), ns, metav1.GetOptions{}); err == nil {
+				evidence = append(evidence, fmt.Sprintf("namespace %s", ns))
+			}
+		}
+		for _, crd := range c.crds {
+			if _, err := i.K8s.Dynamic().Resource(crdResource).Get(context.Background(), crd, metav1.GetOptions{}); err == nil {
+				evidence = append(evidence, fmt.Sprintf("CRD %s", crd))
+			}
+		}
+		for _, d := range c.deployments {
+			parts := strings.SplitN(d, "/", 2)
+			if _, err := i.K8s.Static().AppsV1().Deployments(parts[0]).Get(context.Background(), parts[1], metav1.GetOptions{}); err == nil {
+				evidence = append(evidence, fmt.Sprintf("deployment %s", d))
+			} else if !apiErrors.IsNotFound(err) && i.currentStep != nil {
+				i.currentStep.LogErrorf("Warning: unable to check the deployment %s for conflicts: %s", d, err)
+			}
+		}
+		if len(evidence) > 0 {
+			found = append(found, foundConflict{conflict: c, evidence: evidence})
+		}
+	}
+	return found
+}
+
+// checkConflicts fails the installation if the cluster runs software Kyma brings itself, unless the conflict is acknowledged
+// with --allow-conflict or --force is set, in which case only a warning is logged.
+func (i *Installation) checkConflicts() error {
+	var blocking []string
+	for _, c := range i.findConflicts() {
+		msg := fmt.Sprintf("%s is already installed (found %s): %s", c.name, strings.Join(c.evidence, ", "), c.consequence)
+		if i.Options.Force || contains(i.Options.AllowedConflicts, c.name) {
+			if i.currentStep != nil {
+				i.currentStep.LogErrorf("Warning: %s", msg)
+			}
+			continue
+		}
+		blocking = append(blocking, msg)
+	}
+	if len(blocking) > 0 {
+		return fmt.Errorf("the cluster runs software which conflicts with Kyma:\n  - %s\nRemove it, or use --allow-conflict=<name> or --force to install anyway",
+			strings.Join(blocking, "\n  - "))
+	}
+	return nil
+}
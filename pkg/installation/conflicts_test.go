@@ -0,0 +1,73 @@
+package installation
+
+import (
+	"testing"
+
+	"github.com/kyma-project/cli/internal/kube/mocks"
+	stepMocks "github.com/kyma-project/cli/pkg/step/mocks"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	fakeDynamic "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func conflictingCluster() *mocks.KymaKube {
+	kymaMock := &mocks.KymaKube{}
+	kymaMock.On("Static").Return(fake.NewSimpleClientset(
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "istio-system"}},
+		&appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "istiod", Namespace: "istio-system"}},
+	))
+	kymaMock.On("Dynamic").Return(fakeDynamic.NewSimpleDynamicClient(runtime.NewScheme(), &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "apiextensions.k8s.io/v1beta1",
+		"kind":       "CustomResourceDefinition",
+		"metadata":   map[string]interface{}{"name": "certificates.cert-manager.io"},
+	}}))
+	return kymaMock
+}
+
+func TestCheckConflicts(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Conflicts stop the installation", func(t *testing.T) {
+		i := &Installation{K8s: conflictingCluster(), Options: &Options{Source: "1.16.0"}}
+		err := i.checkConflicts()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "istio is already installed (found namespace istio-system, deployment istio-system/istiod)")
+		require.Contains(t, err.Error(), "cert-manager is already installed (found CRD certificates.cert-manager.io)")
+		require.NotContains(t, err.Error(), "knative")
+	})
+
+	t.Run("Conflicts of newer releases are ignored", func(t *testing.T) {
+		i := &Installation{K8s: conflictingCluster(), Options: &Options{Source: "1.13.0", AllowedConflicts: []string{"istio"}}}
+		require.NoError(t, i.checkConflicts())
+	})
+
+	t.Run("Allowed conflicts are warnings", func(t *testing.T) {
+		s := &stepMocks.Step{}
+		i := &Installation{K8s: conflictingCluster(), currentStep: s, Options: &Options{Source: "master", AllowedConflicts: []string{"istio"}}}
+		err := i.checkConflicts()
+		require.Error(t, err)
+		require.NotContains(t, err.Error(), "istio")
+		require.Len(t, s.Errors(), 1)
+		require.Contains(t, s.Errors()[0], "Warning: istio is already installed")
+	})
+
+	t.Run("Force allows all conflicts", func(t *testing.T) {
+		s := &stepMocks.Step{}
+		i := &Installation{K8s: conflictingCluster(), currentStep: s, Options: &Options{Source: "1.16.0", Force: true}}
+		require.NoError(t, i.checkConflicts())
+		require.Len(t, s.Errors(), 2)
+	})
+
+	t.Run("Clean cluster", func(t *testing.T) {
+		kymaMock := &mocks.KymaKube{}
+		kymaMock.On("Static").Return(fake.NewSimpleClientset())
+		kymaMock.On("Dynamic").Return(fakeDynamic.NewSimpleDynamicClient(runtime.NewScheme()))
+		i := &Installation{K8s: kymaMock, Options: &Options{Source: "1.16.0"}}
+		require.NoError(t, i.checkConflicts())
+	})
+}
@@ -0,0 +1,118 @@
+package installation
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	pkgErrors "github.com/pkg/errors"
+	"gopkg.in/yaml.v2"
+	apiErrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	k8sYaml "sigs.k8s.io/yaml"
+)
+
+const installationCRDName = "installations.installer.kyma-project.io"
+
+var (
+	// crdEstablishedTimeout is the maximum time to wait for a CRD to be established
+	crdEstablishedTimeout = 2 * time.Minute
+	// crdCheckInterval is the time between two checks of the CRD status
+	crdCheckInterval = 2 * time.Second
+)
+
+// ensureInstallationCRD creates the Installation CRD from the installer file and waits until the API server has established it.
+// Otherwise, the Installation CR might be created before its resource type is served, which would abort the installation.
+// If the installer file does not contain the CRD, nothing is done.
+func (i *Installation) ensureInstallationCRD(installerFile *File) error {
+	crd, err := installationCRD(installerFile)
+	if err != nil || crd == nil {
+		return err
+	}
+
+	gvr := schema.GroupVersionResource{
+		Group:    crd.GroupVersionKind().Group,
+		Version:  crd.GroupVersionKind().Version,
+		Resource: "customresourcedefinitions",
+	}
+	crdClient := i.K8s.Dynamic().Resource(gvr)
+
+	_, err = crdClient.Create(context.Background(), crd, metav1.CreateOptions{})
+	// the Kyma Installer cannot run without the CRD, and it is applied again with the installer file, so a missing permission is fatal
+	if apiErrors.IsForbidden(err) {
+		return forbiddenError(err, fmt.Sprintf("create the CRD '%s'", installationCRDName))
+	}
+	if err != nil && !apiErrors.IsAlreadyExists(err) {
+		return pkgErrors.Wrapf(err, "unable to create the CRD '%s'", installationCRDName)
+	}
+
+	return waitForCRD(crdClient, installationCRDName)
+}
+
+// waitForCRD waits until the API server has established the CRD, so that its custom resources can be created
+func waitForCRD(crdClient dynamic.ResourceInterface, name string) error {
+	timeout := time.After(crdEstablishedTimeout)
+	for {
+		select {
+		case <-timeout:
+			return fmt.Errorf("the CRD '%s' was not established within %s. Make sure the API server of your cluster is healthy, and run the command again", name, crdEstablishedTimeout)
+		default:
+			u, err := crdClient.Get(context.Background(), name, metav1.GetOptions{})
+			if err != nil && !apiErrors.IsNotFound(err) {
+				return pkgErrors.Wrapf(err, "unable to check the CRD '%s'", name)
+			}
+			if err == nil && crdEstablished(u) {
+				return nil
+			}
+			time.Sleep(crdCheckInterval)
+		}
+	}
+}
+
+// installationCRD extracts the Installation CRD from the installer file
+func installationCRD(installerFile *File) (*unstructured.Unstructured, error) {
+	for _, config := range installerFile.Content {
+		if kind, ok := config["kind"]; !ok || kind != "CustomResourceDefinition" {
+			continue
+		}
+		if metadata, ok := config["metadata"].(map[interface{}]interface{}); !ok || metadata["name"] != installationCRDName {
+			continue
+		}
+
+		crd, err := toUnstructured(config)
+		if err != nil {
+			return nil, pkgErrors.Wrapf(err, "unable to read the CRD '%s'", installationCRDName)
+		}
+		return crd, nil
+	}
+	return nil, nil
+}
+
+// toUnstructured converts a document of an installation file, the installer file content uses YAML maps, which must be converted to JSON compatible maps
+func toUnstructured(doc map[string]interface{}) (*unstructured.Unstructured, error) {
+	data, err := yaml.Marshal(doc)
+	if err != nil {
+		return nil, err
+	}
+	u := &unstructured.Unstructured{}
+	if err := k8sYaml.Unmarshal(data, &u.Object); err != nil {
+		return nil, err
+	}
+	return u, nil
+}
+
+func crdEstablished(crd *unstructured.Unstructured) bool {
+	conditions, _, err := unstructured.NestedSlice(crd.Object, "status", "conditions")
+	if err != nil {
+		return false
+	}
+	for _, c := range conditions {
+		if condition, ok := c.(map[string]interface{}); ok && condition["type"] == "Established" {
+			return condition["status"] == "True"
+		}
+	}
+	return false
+}
@@ -0,0 +1,104 @@
+package installation
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	k8sMocks "github.com/kyma-project/cli/internal/kube/mocks"
+	"github.com/stretchr/testify/require"
+	apiErrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	fakeDynamic "k8s.io/client-go/dynamic/fake"
+	k8sTesting "k8s.io/client-go/testing"
+)
+
+// fakeDynamicWithInstallationCRD returns a fake dynamic client containing an established Installation CRD
+func fakeDynamicWithInstallationCRD() dynamic.Interface {
+	var objects []runtime.Object
+	for _, apiVersion := range []string{"apiextensions.k8s.io/v1beta1", "apiextensions.k8s.io/v1"} {
+		crd := &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": apiVersion,
+			"kind":       "CustomResourceDefinition",
+			"metadata": map[string]interface{}{
+				"name": installationCRDName,
+			},
+			"status": map[string]interface{}{
+				"conditions": []interface{}{
+					map[string]interface{}{"type": "NamesAccepted", "status": "True"},
+					map[string]interface{}{"type": "Established", "status": "True"},
+				},
+			},
+		}}
+		objects = append(objects, crd)
+	}
+	return fakeDynamic.NewSimpleDynamicClient(runtime.NewScheme(), objects...)
+}
+
+func installerFileWithCRD() *File {
+	return &File{Content: []map[string]interface{}{
+		{
+			"apiVersion": "v1",
+			"kind":       "Namespace",
+			"metadata": map[interface{}]interface{}{
+				"name": "kyma-installer",
+			},
+		},
+		{
+			"apiVersion": "apiextensions.k8s.io/v1beta1",
+			"kind":       "CustomResourceDefinition",
+			"metadata": map[interface{}]interface{}{
+				"name": installationCRDName,
+			},
+			"spec": map[interface{}]interface{}{
+				"group": "installer.kyma-project.io",
+				"names": map[interface{}]interface{}{
+					"kind":   "Installation",
+					"plural": "installations",
+				},
+			},
+		},
+	}}
+}
+
+func TestEnsureInstallationCRD(t *testing.T) {
+	// not parallel: the package level timeouts are modified
+	defaultTimeout, defaultInterval := crdEstablishedTimeout, crdCheckInterval
+	crdEstablishedTimeout, crdCheckInterval = 100*time.Millisecond, 10*time.Millisecond
+	defer func() { crdEstablishedTimeout, crdCheckInterval = defaultTimeout, defaultInterval }()
+
+	// CRD is established
+	kymaMock := k8sMocks.KymaKube{}
+	kymaMock.On("Dynamic").Return(fakeDynamicWithInstallationCRD())
+	i := &Installation{K8s: &kymaMock, Options: &Options{}}
+	require.NoError(t, i.ensureInstallationCRD(installerFileWithCRD()))
+
+	// CRD is created but never established
+	kymaMock = k8sMocks.KymaKube{}
+	kymaMock.On("Dynamic").Return(fakeDynamic.NewSimpleDynamicClient(runtime.NewScheme()))
+	i = &Installation{K8s: &kymaMock, Options: &Options{}}
+	err := i.ensureInstallationCRD(installerFileWithCRD())
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "was not established")
+
+	// not permitted to create the CRD
+	dynamicMock := fakeDynamic.NewSimpleDynamicClient(runtime.NewScheme())
+	dynamicMock.PrependReactor("create", "customresourcedefinitions", func(action k8sTesting.Action) (bool, runtime.Object, error) {
+		return true, nil, apiErrors.NewForbidden(schema.GroupResource{Resource: "customresourcedefinitions"}, installationCRDName, errors.New("no access"))
+	})
+	kymaMock = k8sMocks.KymaKube{}
+	kymaMock.On("Dynamic").Return(dynamicMock)
+	i = &Installation{K8s: &kymaMock, Options: &Options{}}
+	err = i.ensureInstallationCRD(installerFileWithCRD())
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "not permitted to create the CRD")
+
+	// installer file without CRD does not touch the cluster
+	kymaMock = k8sMocks.KymaKube{}
+	i = &Installation{K8s: &kymaMock, Options: &Options{}}
+	require.NoError(t, i.ensureInstallationCRD(&File{}))
+	kymaMock.AssertNotCalled(t, "Dynamic")
+}
@@ -0,0 +1,182 @@
+package installation
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	pkgErrors "github.com/pkg/errors"
+	apiErrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// crdReport lists the CRDs of the installer file by what was done with them
+type crdReport struct {
+	created   []string
+	unchanged []string
+	updated   []string
+	// changed holds the CRDs which differ from the ones on the cluster but were not replaced, as --upgrade-crds is not set
+	changed []string
+}
+
+func (r crdReport) String() string {
+	var parts []string
+	for _, p := range []struct {
+		label string
+		names []string
+	}{
+		{"created", r.created},
+		{"skipped (unchanged)", r.unchanged},
+		{"updated", r.updated},
+		{"skipped (changed, use --upgrade-crds to replace them)", r.changed},
+	} {
+		if len(p.names) > 0 {
+			parts = append(parts, fmt.Sprintf("%s: %s", p.label, strings.Join(p.names, ", ")))
+		}
+	}
+	return "CRDs " + strings.Join(parts, "; ")
+}
+
+// reconcileCRDs compares the CRDs of the installer file with the ones already on the cluster, before the installer file is applied.
+// Re-applying a CRD whose schema changed conflicts with the existing one and aborts the installation, so existing CRDs are removed from the installer file.
+// A changed CRD is replaced only if --upgrade-crds is set, because the existing custom resources must match the new schema.
+func (i *Installation) reconcileCRDs(installerFile *File) error {
+	if installerFile == nil {
+		return nil
+	}
+	report := crdReport{}
+	var keep []bool
+	for _, doc := range installerFile.Content {
+		apply, err := i.reconcileCRD(doc, &report)
+		if err != nil {
+			return err
+		}
+		keep = append(keep, apply)
+	}
+	removeDocuments(installerFile, keep)
+
+	if i.currentStep != nil && (len(report.created)+len(report.unchanged)+len(report.updated)+len(report.changed)) > 0 {
+		i.currentStep.LogInfo(report.String())
+		if len(report.changed) > 0 {
+			i.currentStep.LogErrorf("Warning: the CRDs %s differ from the ones on the cluster, the installation continues with the existing CRDs", strings.Join(report.changed, ", "))
+		}
+	}
+	return nil
+}
+
+// reconcileCRD handles a single document of the installer file and returns whether it must still be applied with the installer file
+func (i *Installation) reconcileCRD(doc map[string]interface{}, report *crdReport) (bool, error) {
+	if kind, _ := doc["kind"].(string); kind != "CustomResourceDefinition" {
+		return true, nil
+	}
+	desired, err := toUnstructured(doc)
+	if err != nil {
+		return false, pkgErrors.Wrap(err, "unable to read a CRD of the installer file")
+	}
+	name := desired.GetName()
+	gvr := desired.GroupVersionKind().GroupVersion().WithResource("customresourcedefinitions")
+	crdClient := i.K8s.Dynamic().Resource(gvr)
+
+	existing, err := crdClient.Get(context.Background(), name, metav1.GetOptions{})
+	if apiErrors.IsNotFound(err) {
+		report.created = append(report.created, name)
+		return true, nil
+	}
+	if apiErrors.IsForbidden(err) {
+		// without read access, the CRD is applied with the installer file as before
+		if i.currentStep != nil {
+			i.currentStep.LogErrorf("Warning: %s", forbiddenError(err, fmt.Sprintf("check the CRD '%s'", name)))
+		}
+		return true, nil
+	}
+	if err != nil {
+		return false, pkgErrors.Wrapf(err, "unable to check the CRD '%s'", name)
+	}
+
+	same, err := sameCRDVersions(existing, desired)
+	if err != nil {
+		return false, pkgErrors.Wrapf(err, "unable to compare the CRD '%s'", name)
+	}
+	if same {
+		report.unchanged = append(report.unchanged, name)
+		return false, nil
+	}
+	if !i.Options.UpgradeCRDs {
+		report.changed = append(report.changed, name)
+		return false, nil
+	}
+
+	if i.currentStep != nil {
+		i.currentStep.LogErrorf("Warning: replacing the CRD '%s'. The existing custom resources are not migrated, fields removed from the schema might be pruned and removed versions can no longer be read", name)
+	}
+	desired.SetResourceVersion(existing.GetResourceVersion())
+	if _, err := crdClient.Update(context.Background(), desired, metav1.UpdateOptions{}); err != nil {
+		if apiErrors.IsForbidden(err) {
+			return false, forbiddenError(err, fmt.Sprintf("update the CRD '%s'", name))
+		}
+		return false, pkgErrors.Wrapf(err, "unable to update the CRD '%s'", name)
+	}
+	report.updated = append(report.updated, name)
+	return false, nil
+}
+
+// sameCRDVersions checks if both CRDs serve the same versions with the same schemas.
+// Fields defaulted by the API server are ignored, and the schemas are compared as JSON, as numbers are decoded differently.
+func sameCRDVersions(existing, desired *unstructured.Unstructured) (bool, error) {
+	a, err := json.Marshal(crdVersionSchemas(existing))
+	if err != nil {
+		return false, err
+	}
+	b, err := json.Marshal(crdVersionSchemas(desired))
+	if err != nil {
+		return false, err
+	}
+	return bytes.Equal(a, b), nil
+}
+
+// crdVersionSchemas maps the versions of the CRD to their schemas, the schema of all versions is used if a version has none (like in apiextensions.k8s.io/v1beta1)
+func crdVersionSchemas(crd *unstructured.Unstructured) map[string]interface{} {
+	common, _, _ := unstructured.NestedFieldNoCopy(crd.Object, "spec", "validation", "openAPIV3Schema")
+	result := map[string]interface{}{}
+	versions, _, _ := unstructured.NestedSlice(crd.Object, "spec", "versions")
+	for _, v := range versions {
+		version, ok := v.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		name, _ := version["name"].(string)
+		schema, found, _ := unstructured.NestedFieldNoCopy(version, "schema", "openAPIV3Schema")
+		if !found {
+			schema = common
+		}
+		result[name] = schema
+	}
+	if len(versions) == 0 {
+		if name, found, _ := unstructured.NestedString(crd.Object, "spec", "version"); found {
+			result[name] = common
+		}
+	}
+	return result
+}
+
+// removeDocuments drops the documents of the file which are not kept, with their sources
+func removeDocuments(file *File, keep []bool) {
+	var content []map[string]interface{}
+	var raw []string
+	for n, doc := range file.Content {
+		if !keep[n] {
+			continue
+		}
+		content = append(content, doc)
+		if len(file.raw) == len(file.Content) {
+			raw = append(raw, file.raw[n])
+		}
+	}
+	if len(file.raw) == len(file.Content) {
+		file.raw = raw
+	}
+	file.Content = content
+}
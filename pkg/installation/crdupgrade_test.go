@@ -0,0 +1,148 @@
+package installation
+
+import (
+	"context"
+	"testing"
+
+	k8sMocks "github.com/kyma-project/cli/internal/kube/mocks"
+	stepMocks "github.com/kyma-project/cli/pkg/step/mocks"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	fakeDynamic "k8s.io/client-go/dynamic/fake"
+)
+
+var crdGVR = schema.GroupVersionResource{Group: "apiextensions.k8s.io", Version: "v1", Resource: "customresourcedefinitions"}
+
+// clusterCRD is a CRD as returned by the API server, with defaulted fields and a status
+func clusterCRD(name string, maxReplicas int64) *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "apiextensions.k8s.io/v1",
+		"kind":       "CustomResourceDefinition",
+		"metadata":   map[string]interface{}{"name": name, "resourceVersion": "42"},
+		"spec": map[string]interface{}{
+			"group":      "example.com",
+			"conversion": map[string]interface{}{"strategy": "None"},
+			"versions": []interface{}{
+				map[string]interface{}{
+					"name":    "v1",
+					"served":  true,
+					"storage": true,
+					"schema": map[string]interface{}{"openAPIV3Schema": map[string]interface{}{
+						"type":       "object",
+						"properties": map[string]interface{}{"replicas": map[string]interface{}{"type": "integer", "maximum": maxReplicas}},
+					}},
+				},
+			},
+		},
+		"status": map[string]interface{}{"storedVersions": []interface{}{"v1"}},
+	}}
+}
+
+// fileCRD is a CRD of the installer file
+func fileCRD(name string, maxReplicas int) map[string]interface{} {
+	return map[string]interface{}{
+		"apiVersion": "apiextensions.k8s.io/v1",
+		"kind":       "CustomResourceDefinition",
+		"metadata":   map[interface{}]interface{}{"name": name},
+		"spec": map[interface{}]interface{}{
+			"group": "example.com",
+			"versions": []interface{}{
+				map[interface{}]interface{}{
+					"name":    "v1",
+					"served":  true,
+					"storage": true,
+					"schema": map[interface{}]interface{}{"openAPIV3Schema": map[interface{}]interface{}{
+						"type":       "object",
+						"properties": map[interface{}]interface{}{"replicas": map[interface{}]interface{}{"type": "integer", "maximum": maxReplicas}},
+					}},
+				},
+			},
+		},
+	}
+}
+
+func installerFileWithCRDs() *File {
+	return &File{
+		Content: []map[string]interface{}{
+			{"apiVersion": "v1", "kind": "Namespace", "metadata": map[interface{}]interface{}{"name": "kyma-installer"}},
+			fileCRD("unchanged.example.com", 3),
+			fileCRD("changed.example.com", 5),
+			fileCRD("new.example.com", 3),
+		},
+		raw: []string{"namespace", "unchanged", "changed", "new"},
+	}
+}
+
+func TestReconcileCRDs(t *testing.T) {
+	t.Parallel()
+
+	newInstallation := func(upgrade bool) (*Installation, *fakeDynamic.FakeDynamicClient, *stepMocks.Step) {
+		dynamic := fakeDynamic.NewSimpleDynamicClient(runtime.NewScheme(), clusterCRD("unchanged.example.com", 3), clusterCRD("changed.example.com", 3))
+		kymaMock := &k8sMocks.KymaKube{}
+		kymaMock.On("Dynamic").Return(dynamic)
+		s := &stepMocks.Step{}
+		return &Installation{K8s: kymaMock, Options: &Options{UpgradeCRDs: upgrade}, currentStep: s}, dynamic, s
+	}
+
+	t.Run("existing CRDs are kept", func(t *testing.T) {
+		i, dynamic, s := newInstallation(false)
+		file := installerFileWithCRDs()
+		require.NoError(t, i.reconcileCRDs(file))
+
+		require.Equal(t, []string{"namespace", "new"}, file.raw)
+		require.Len(t, file.Content, 2)
+		require.Equal(t, []string{"CRDs created: new.example.com; skipped (unchanged): unchanged.example.com; skipped (changed, use --upgrade-crds to replace them): changed.example.com"}, s.Infos())
+		require.Len(t, s.Errors(), 1)
+		require.Contains(t, s.Errors()[0], "changed.example.com")
+
+		crd, err := dynamic.Resource(crdGVR).Get(context.Background(), "changed.example.com", metav1.GetOptions{})
+		require.NoError(t, err)
+		require.Equal(t, clusterCRD("changed.example.com", 3).Object["spec"], crd.Object["spec"], "changed CRDs must not be replaced without --upgrade-crds")
+	})
+
+	t.Run("changed CRDs are replaced", func(t *testing.T) {
+		i, dynamic, s := newInstallation(true)
+		file := installerFileWithCRDs()
+		require.NoError(t, i.reconcileCRDs(file))
+
+		require.Equal(t, []string{"namespace", "new"}, file.raw)
+		require.Equal(t, []string{"CRDs created: new.example.com; skipped (unchanged): unchanged.example.com; updated: changed.example.com"}, s.Infos())
+		require.Len(t, s.Errors(), 1)
+		require.Contains(t, s.Errors()[0], "Warning: replacing the CRD 'changed.example.com'")
+
+		crd, err := dynamic.Resource(crdGVR).Get(context.Background(), "changed.example.com", metav1.GetOptions{})
+		require.NoError(t, err)
+		same, err := sameCRDVersions(crd, clusterCRD("changed.example.com", 5))
+		require.NoError(t, err)
+		require.True(t, same, "the CRD must be replaced with the one of the installer file")
+	})
+
+	t.Run("no CRDs", func(t *testing.T) {
+		i, _, s := newInstallation(false)
+		file := &File{Content: []map[string]interface{}{{"apiVersion": "v1", "kind": "Namespace"}}}
+		require.NoError(t, i.reconcileCRDs(file))
+		require.Len(t, file.Content, 1)
+		require.Empty(t, s.Infos())
+	})
+}
+
+func TestCRDVersionSchemas(t *testing.T) {
+	t.Parallel()
+
+	// apiextensions.k8s.io/v1beta1 CRDs may define one schema for all versions
+	schemaDoc := map[string]interface{}{"type": "object"}
+	legacy := &unstructured.Unstructured{Object: map[string]interface{}{
+		"spec": map[string]interface{}{
+			"version":    "v1alpha1",
+			"validation": map[string]interface{}{"openAPIV3Schema": schemaDoc},
+		},
+	}}
+	require.Equal(t, map[string]interface{}{"v1alpha1": schemaDoc}, crdVersionSchemas(legacy))
+
+	// the API server lists the version in the versions as well
+	legacy.Object["spec"].(map[string]interface{})["versions"] = []interface{}{map[string]interface{}{"name": "v1alpha1", "served": true, "storage": true}}
+	require.Equal(t, map[string]interface{}{"v1alpha1": schemaDoc}, crdVersionSchemas(legacy))
+}
@@ -0,0 +1,105 @@
+package installation
+
+import (
+	"context"
+	"fmt"
+
+	pkgErrors "github.com/pkg/errors"
+	apiErrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+)
+
+// deferredResource is a custom resource of the installer file, which is applied once its CRD is established
+type deferredResource struct {
+	crd      *unstructured.Unstructured
+	resource *unstructured.Unstructured
+}
+
+// deferCustomResources removes the custom resources, whose CRD is defined in the same file, from the installer file.
+// The API server rejects them until their CRD is established, so they are applied in a second pass by applyCustomResources.
+func deferCustomResources(installerFile *File) ([]deferredResource, error) {
+	if installerFile == nil {
+		return nil, nil
+	}
+	crds := map[schema.GroupKind]*unstructured.Unstructured{}
+	for _, doc := range installerFile.Content {
+		if kind, _ := doc["kind"].(string); kind != "CustomResourceDefinition" {
+			continue
+		}
+		crd, err := toUnstructured(doc)
+		if err != nil {
+			return nil, pkgErrors.Wrap(err, "unable to read a CRD of the installer file")
+		}
+		group, _, _ := unstructured.NestedString(crd.Object, "spec", "group")
+		kind, _, _ := unstructured.NestedString(crd.Object, "spec", "names", "kind")
+		crds[schema.GroupKind{Group: group, Kind: kind}] = crd
+	}
+	if len(crds) == 0 {
+		return nil, nil
+	}
+
+	var deferred []deferredResource
+	keep := make([]bool, len(installerFile.Content))
+	for n, doc := range installerFile.Content {
+		keep[n] = true
+		if documentOrder(doc) != customResourceOrder {
+			continue
+		}
+		apiVersion, _ := doc["apiVersion"].(string)
+		kind, _ := doc["kind"].(string)
+		crd, ok := crds[schema.FromAPIVersionAndKind(apiVersion, kind).GroupKind()]
+		if !ok {
+			continue
+		}
+		resource, err := toUnstructured(doc)
+		if err != nil {
+			return nil, pkgErrors.Wrapf(err, "unable to read the %s of the installer file", kind)
+		}
+		deferred = append(deferred, deferredResource{crd: crd, resource: resource})
+		keep[n] = false
+	}
+	removeDocuments(installerFile, keep)
+	return deferred, nil
+}
+
+// applyCustomResources waits for the CRD of each deferred custom resource to be established, and creates or updates the custom resource
+func (i *Installation) applyCustomResources(deferred []deferredResource) error {
+	for _, d := range deferred {
+		crdGVK := d.crd.GroupVersionKind()
+		crdClient := i.K8s.Dynamic().Resource(schema.GroupVersionResource{Group: crdGVK.Group, Version: crdGVK.Version, Resource: "customresourcedefinitions"})
+		if err := waitForCRD(crdClient, d.crd.GetName()); err != nil {
+			return err
+		}
+
+		plural, _, _ := unstructured.NestedString(d.crd.Object, "spec", "names", "plural")
+		gvk := d.resource.GroupVersionKind()
+		resourceClient := i.K8s.Dynamic().Resource(schema.GroupVersionResource{Group: gvk.Group, Version: gvk.Version, Resource: plural})
+		var client dynamic.ResourceInterface = resourceClient
+		if scope, _, _ := unstructured.NestedString(d.crd.Object, "spec", "scope"); scope == "Namespaced" {
+			if d.resource.GetNamespace() == "" {
+				d.resource.SetNamespace("default")
+			}
+			client = resourceClient.Namespace(d.resource.GetNamespace())
+		}
+
+		_, err := client.Create(context.Background(), d.resource, metav1.CreateOptions{})
+		if apiErrors.IsAlreadyExists(err) {
+			var existing *unstructured.Unstructured
+			existing, err = client.Get(context.Background(), d.resource.GetName(), metav1.GetOptions{})
+			if err == nil {
+				d.resource.SetResourceVersion(existing.GetResourceVersion())
+				_, err = client.Update(context.Background(), d.resource, metav1.UpdateOptions{})
+			}
+		}
+		if apiErrors.IsForbidden(err) {
+			return forbiddenError(err, fmt.Sprintf("apply the %s '%s'", gvk.Kind, d.resource.GetName()))
+		}
+		if err != nil {
+			return pkgErrors.Wrapf(err, "unable to apply the %s '%s'", gvk.Kind, d.resource.GetName())
+		}
+	}
+	return nil
+}
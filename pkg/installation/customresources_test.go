@@ -0,0 +1,104 @@
+package installation
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	k8sMocks "github.com/kyma-project/cli/internal/kube/mocks"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	fakeDynamic "k8s.io/client-go/dynamic/fake"
+)
+
+func installerFileWithCustomResources() *File {
+	return &File{
+		Content: []map[string]interface{}{
+			{
+				"apiVersion": "apiextensions.k8s.io/v1",
+				"kind":       "CustomResourceDefinition",
+				"metadata":   map[interface{}]interface{}{"name": "releases.release.kyma-project.io"},
+				"spec": map[interface{}]interface{}{
+					"group": "release.kyma-project.io",
+					"scope": "Namespaced",
+					"names": map[interface{}]interface{}{"kind": "Release", "plural": "releases"},
+				},
+			},
+			{
+				"apiVersion": "apps/v1",
+				"kind":       "Deployment",
+				"metadata":   map[interface{}]interface{}{"name": "kyma-installer", "namespace": "kyma-installer"},
+			},
+			{
+				"apiVersion": "release.kyma-project.io/v1alpha1",
+				"kind":       "Release",
+				"metadata":   map[interface{}]interface{}{"name": "cluster-essentials", "namespace": "kyma-installer"},
+			},
+			{
+				"apiVersion": "installer.kyma-project.io/v1alpha1",
+				"kind":       "Installation",
+				"metadata":   map[interface{}]interface{}{"name": "kyma-installation"},
+			},
+		},
+		raw: []string{"crd", "deployment", "release", "installation"},
+	}
+}
+
+func TestDeferCustomResources(t *testing.T) {
+	file := installerFileWithCustomResources()
+	deferred, err := deferCustomResources(file)
+	require.NoError(t, err)
+
+	require.Len(t, deferred, 1, "only the custom resources with a CRD in the file are deferred")
+	require.Equal(t, "cluster-essentials", deferred[0].resource.GetName())
+	require.Equal(t, "releases.release.kyma-project.io", deferred[0].crd.GetName())
+	require.Len(t, file.Content, 3)
+	require.Equal(t, []string{"crd", "deployment", "installation"}, file.raw, "the sources stay with their documents")
+
+	// a file without CRDs is not changed
+	file = &File{Content: installerFileWithCustomResources().Content[1:]}
+	deferred, err = deferCustomResources(file)
+	require.NoError(t, err)
+	require.Empty(t, deferred)
+	require.Len(t, file.Content, 3)
+}
+
+func TestApplyCustomResources(t *testing.T) {
+	defaultTimeout, defaultInterval := crdEstablishedTimeout, crdCheckInterval
+	crdEstablishedTimeout, crdCheckInterval = 100*time.Millisecond, 10*time.Millisecond
+	defer func() { crdEstablishedTimeout, crdCheckInterval = defaultTimeout, defaultInterval }()
+
+	deferred, err := deferCustomResources(installerFileWithCustomResources())
+	require.NoError(t, err)
+	releases := schema.GroupVersionResource{Group: "release.kyma-project.io", Version: "v1alpha1", Resource: "releases"}
+
+	// the CRD is established, so the custom resource is created, and updated when it is applied again
+	established := deferred[0].crd.DeepCopy()
+	require.NoError(t, unstructured.SetNestedSlice(established.Object, []interface{}{
+		map[string]interface{}{"type": "Established", "status": "True"},
+	}, "status", "conditions"))
+	dynamicMock := fakeDynamic.NewSimpleDynamicClient(runtime.NewScheme(), established)
+	kymaMock := k8sMocks.KymaKube{}
+	kymaMock.On("Dynamic").Return(dynamicMock)
+	i := &Installation{K8s: &kymaMock, Options: &Options{}}
+	require.NoError(t, i.applyCustomResources(deferred))
+	_, err = dynamicMock.Resource(releases).Namespace("kyma-installer").Get(context.Background(), "cluster-essentials", metav1.GetOptions{})
+	require.NoError(t, err)
+
+	deferred[0].resource.SetLabels(map[string]string{"updated": "true"})
+	require.NoError(t, i.applyCustomResources(deferred))
+	release, err := dynamicMock.Resource(releases).Namespace("kyma-installer").Get(context.Background(), "cluster-essentials", metav1.GetOptions{})
+	require.NoError(t, err)
+	require.Equal(t, "true", release.GetLabels()["updated"])
+
+	// the CRD is never established
+	kymaMock = k8sMocks.KymaKube{}
+	kymaMock.On("Dynamic").Return(fakeDynamic.NewSimpleDynamicClient(runtime.NewScheme(), deferred[0].crd))
+	i = &Installation{K8s: &kymaMock, Options: &Options{}}
+	err = i.applyCustomResources(deferred)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "was not established")
+}
@@ -0,0 +1,182 @@
+package installation
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"net/mail"
+	"strings"
+
+	installationSDK "github.com/kyma-incubator/hydroform/install/installation"
+	"github.com/kyma-project/cli/internal/metadata"
+	"golang.org/x/crypto/bcrypt"
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	// dexComponent is the component whose overrides configure Dex
+	dexComponent = "dex"
+	// defaultAdminEmail is the email of the admin user which Kyma creates itself
+	defaultAdminEmail = "admin@kyma.cx"
+)
+
+// DexUser is an additional static user of Dex, given with --add-user
+type DexUser struct {
+	Email    string
+	Password string
+	Groups   []string
+}
+
+// String describes the user without the password, as it is shown in the summary
+func (u DexUser) String() string {
+	if len(u.Groups) == 0 {
+		return u.Email
+	}
+	return fmt.Sprintf("%s (groups: %s)", u.Email, strings.Join(u.Groups, ", "))
+}
+
+// ParseDexUser reads an --add-user entry in the format "email=...,password=...,groups=...".
+// A part without "=" continues the value of the previous field, so that groups and passwords can contain commas.
+func ParseDexUser(entry string) (DexUser, error) {
+	fields := map[string][]string{}
+	var current string
+	for _, part := range strings.Split(entry, ",") {
+		kv := strings.SplitN(part, "=", 2)
+		if len(kv) == 2 {
+			current = strings.TrimSpace(kv[0])
+			if _, ok := fields[current]; ok {
+				return DexUser{}, fmt.Errorf("invalid user '%s': the field '%s' is given twice", redactedDexUser(entry), current)
+			}
+			fields[current] = []string{kv[1]}
+			continue
+		}
+		if current == "" {
+			return DexUser{}, fmt.Errorf("invalid user '%s', use the format email=...,password=...,groups=...", redactedDexUser(entry))
+		}
+		fields[current] = append(fields[current], part)
+	}
+
+	var user DexUser
+	for name, value := range fields {
+		switch name {
+		case "email":
+			user.Email = strings.TrimSpace(strings.Join(value, ","))
+		case "password":
+			user.Password = strings.Join(value, ",")
+		case "groups":
+			for _, g := range value {
+				if g = strings.TrimSpace(g); g != "" {
+					user.Groups = append(user.Groups, g)
+				}
+			}
+		default:
+			return DexUser{}, fmt.Errorf("invalid user '%s': unknown field '%s', use email, password and groups", redactedDexUser(entry), name)
+		}
+	}
+
+	if addr, err := mail.ParseAddress(user.Email); err != nil || addr.Address != user.Email {
+		return DexUser{}, fmt.Errorf("invalid user '%s': '%s' is not a valid email address", redactedDexUser(entry), user.Email)
+	}
+	if user.Password == "" {
+		return DexUser{}, fmt.Errorf("invalid user '%s': the password must not be empty", redactedDexUser(entry))
+	}
+	return user, nil
+}
+
+// redactedDexUser hides the password of an --add-user entry, so that it can be part of an error
+func redactedDexUser(entry string) string {
+	start := strings.Index(entry, "password=")
+	if start < 0 {
+		return entry
+	}
+	start += len("password=")
+	end := len(entry)
+	for _, field := range []string{",email=", ",groups="} {
+		if n := strings.Index(entry[start:], field); n >= 0 && start+n < end {
+			end = start + n
+		}
+	}
+	return entry[:start] + "***" + entry[end:]
+}
+
+// dexUsers parses the --add-user entries
+func (i *Installation) dexUsers() ([]DexUser, error) {
+	var users []DexUser
+	emails := map[string]bool{}
+	for _, entry := range i.Options.DexUsers {
+		user, err := ParseDexUser(entry)
+		if err != nil {
+			return nil, err
+		}
+		email := strings.ToLower(user.Email)
+		if emails[email] {
+			return nil, fmt.Errorf("the user '%s' is given twice with --add-user", user.Email)
+		}
+		if email == defaultAdminEmail {
+			return nil, fmt.Errorf("the user '%s' is the admin user of Kyma, use --password to set its password", user.Email)
+		}
+		emails[email] = true
+		users = append(users, user)
+	}
+	return users, nil
+}
+
+// validateDexUsers ensures that the --add-user entries are well-formed
+func (i *Installation) validateDexUsers() error {
+	_, err := i.dexUsers()
+	return err
+}
+
+// dexUsersSource converts the users into the static users override of the Dex chart of the installed release. The passwords are hashed, as Dex expects them,
+// and the override is a Secret. Overrides cannot hold lists, so the users are passed as one YAML document. If the component list is known, it must contain Dex.
+func (i *Installation) dexUsersSource(installerCRFile *File) ([]configSource, error) {
+	users, err := i.dexUsers()
+	if err != nil || len(users) == 0 {
+		return nil, err
+	}
+	key := metadata.For(i.Options.Source).DexStaticUsersOverride
+	if key == "" {
+		return nil, fmt.Errorf("--add-user is not supported by the Dex chart of Kyma '%s'. If the chart has an override for static users, set it as dexStaticUsersOverride with --metadata-file", i.Options.Source)
+	}
+	components, err := i.componentList(installerCRFile)
+	if err != nil {
+		return nil, err
+	}
+	if len(components) > 0 && componentIndex(components, dexComponent) < 0 {
+		return nil, fmt.Errorf("--add-user needs the component '%s', which is not in the component list", dexComponent)
+	}
+
+	var staticUsers []map[string]interface{}
+	for _, u := range users {
+		hash, err := bcrypt.GenerateFromPassword([]byte(u.Password), bcrypt.DefaultCost)
+		if err != nil {
+			return nil, fmt.Errorf("unable to hash the password of the user '%s': %s", u.Email, err)
+		}
+		staticUser := map[string]interface{}{
+			"email":    u.Email,
+			"hash":     string(hash),
+			"username": strings.SplitN(u.Email, "@", 2)[0],
+			"userID":   dexUserID(u.Email),
+		}
+		if len(u.Groups) > 0 {
+			staticUser["groups"] = u.Groups
+		}
+		staticUsers = append(staticUsers, staticUser)
+	}
+	doc, err := yaml.Marshal(staticUsers)
+	if err != nil {
+		return nil, err
+	}
+
+	return []configSource{{
+		name: "--add-user",
+		configuration: installationSDK.Configuration{ComponentConfiguration: []installationSDK.ComponentConfiguration{
+			{Component: dexComponent, Configuration: installationSDK.ConfigEntries{{Key: key, Value: string(doc), Secret: true}}},
+		}},
+	}}, nil
+}
+
+// dexUserID derives a stable user ID from the email, so that installing again keeps the identity of the user
+func dexUserID(email string) string {
+	sum := sha256.Sum256([]byte(strings.ToLower(email)))
+	return fmt.Sprintf("%x-%x-%x-%x-%x", sum[0:4], sum[4:6], sum[6:8], sum[8:10], sum[10:16])
+}
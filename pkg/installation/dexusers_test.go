@@ -0,0 +1,111 @@
+package installation
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/kyma-project/cli/internal/metadata"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+	"gopkg.in/yaml.v2"
+)
+
+func TestParseDexUser(t *testing.T) {
+	t.Parallel()
+	user, err := ParseDexUser("email=dev@example.com,password=s3,cr3t,groups=developers,viewers")
+	require.NoError(t, err)
+	require.Equal(t, DexUser{Email: "dev@example.com", Password: "s3,cr3t", Groups: []string{"developers", "viewers"}}, user)
+	require.Equal(t, "dev@example.com (groups: developers, viewers)", user.String())
+
+	user, err = ParseDexUser("password=s3cr3t,email=ops@example.com")
+	require.NoError(t, err)
+	require.Equal(t, "ops@example.com", user.String())
+
+	for entry, msg := range map[string]string{
+		"email=dev,password=s3cr3t":                    "invalid user 'email=dev,password=***': 'dev' is not a valid email address",
+		"email=dev@example.com,password=":              "invalid user 'email=dev@example.com,password=***': the password must not be empty",
+		"email=dev@example.com,password=s3cr3t,role=x": "invalid user 'email=dev@example.com,password=***': unknown field 'role', use email, password and groups",
+		"dev@example.com":                              "invalid user 'dev@example.com', use the format email=...,password=...,groups=...",
+		"email=a@example.com,email=b@example.com":      "invalid user 'email=a@example.com,email=b@example.com': the field 'email' is given twice",
+	} {
+		_, err := ParseDexUser(entry)
+		require.EqualError(t, err, msg)
+	}
+}
+
+func TestDexUsersSource(t *testing.T) {
+	t.Parallel()
+	crFile := func(components ...string) *File {
+		var list []interface{}
+		for _, c := range components {
+			list = append(list, map[interface{}]interface{}{"name": c, "namespace": "kyma-system"})
+		}
+		return &File{Content: []map[string]interface{}{{
+			"kind": "Installation",
+			"spec": map[interface{}]interface{}{"components": list},
+		}}}
+	}
+
+	i := &Installation{Options: &Options{Source: "1.17.0", DexUsers: []string{"email=dev@example.com,password=s3cr3t,groups=developers"}}}
+	sources, err := i.dexUsersSource(crFile("core", "dex"))
+	require.NoError(t, err)
+	require.Len(t, sources, 1)
+	component := sources[0].configuration.ComponentConfiguration[0]
+	require.Equal(t, "dex", component.Component)
+	entry := component.Configuration[0]
+	require.Equal(t, metadata.For("1.17.0").DexStaticUsersOverride, entry.Key, "the override of the release is used")
+	require.True(t, entry.Secret)
+
+	// the Kyma Installer nests the override keys into the values of the chart
+	values := renderOverride(entry.Key, entry.Value)
+	dex, _ := values["dex"].(map[string]interface{})
+	staticPasswords, ok := dex["staticPasswords"].(string)
+	require.True(t, ok, "the users must be rendered as the static passwords of Dex: %v", values)
+	var users []struct {
+		Email    string
+		Hash     string
+		Username string
+		UserID   string `yaml:"userID"`
+		Groups   []string
+	}
+	require.NoError(t, yaml.Unmarshal([]byte(staticPasswords), &users))
+	require.Len(t, users, 1)
+	require.Equal(t, "dev@example.com", users[0].Email)
+	require.Equal(t, "dev", users[0].Username)
+	require.Equal(t, dexUserID("dev@example.com"), users[0].UserID)
+	require.Equal(t, []string{"developers"}, users[0].Groups)
+	require.NoError(t, bcrypt.CompareHashAndPassword([]byte(users[0].Hash), []byte("s3cr3t")))
+	require.NotContains(t, entry.Value, "s3cr3t")
+
+	// the releases installed with Helm 2 have the same override
+	i.Options.Source = "1.15.1"
+	sources, err = i.dexUsersSource(crFile("core", "dex"))
+	require.NoError(t, err)
+	require.Equal(t, "dex.staticPasswords", sources[0].configuration.ComponentConfiguration[0].Configuration[0].Key)
+
+	_, err = i.dexUsersSource(crFile("core"))
+	require.EqualError(t, err, "--add-user needs the component 'dex', which is not in the component list")
+
+	i.Options.DexUsers = append(i.Options.DexUsers, "email=DEV@example.com,password=other")
+	require.EqualError(t, i.validateDexUsers(), "the user 'DEV@example.com' is given twice with --add-user")
+	i.Options.DexUsers = []string{"email=admin@kyma.cx,password=other"}
+	require.EqualError(t, i.validateDexUsers(), "the user 'admin@kyma.cx' is the admin user of Kyma, use --password to set its password")
+
+	sources, err = (&Installation{Options: &Options{}}).dexUsersSource(nil)
+	require.NoError(t, err)
+	require.Empty(t, sources)
+}
+
+// renderOverride nests the value under the dot separated key, as the Kyma Installer passes the overrides to the chart
+func renderOverride(key, value string) map[string]interface{} {
+	values := map[string]interface{}{}
+	parts := strings.Split(key, ".")
+	current := values
+	for _, p := range parts[:len(parts)-1] {
+		next := map[string]interface{}{}
+		current[p] = next
+		current = next
+	}
+	current[parts[len(parts)-1]] = value
+	return values
+}
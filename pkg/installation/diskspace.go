@@ -0,0 +1,104 @@
+package installation
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/kyma-project/cli/internal/minikube"
+	pkgErrors "github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// minDockerFreeSpace is the free disk space in bytes below which building the Kyma Installer image is likely to fail
+const minDockerFreeSpace = 5 << 30
+
+// checkDockerDiskSpace warns if the Docker daemon of Minikube is short of disk space to build the Kyma Installer image.
+// If configured, the unused images are pruned first. Failing checks are logged as warnings, as they do not prevent the build.
+func (i *Installation) checkDockerDiskSpace() error {
+	if i.Options.PruneDocker {
+		if err := i.pruneDocker(); err != nil {
+			return err
+		}
+	}
+
+	info, err := i.Docker.DiskInfo()
+	if err != nil {
+		i.currentStep.LogErrorf("Warning: unable to check the disk usage of the Docker daemon: %s", err)
+		return nil
+	}
+	free, err := i.dockerFreeSpace(info.RootDir)
+	if err != nil {
+		i.currentStep.LogErrorf("Warning: unable to check the free disk space of the Docker daemon: %s", err)
+		return nil
+	}
+	i.currentStep.LogInfof("The Docker daemon stores %d images with %s, %s are free", info.Images, formatSize(uint64(info.ImagesSize)), formatSize(free))
+
+	if free < minDockerFreeSpace {
+		hint := "Use --prune-docker to remove the images not used by any container"
+		if i.Options.PruneDocker {
+			hint = "Free up space in the Minikube VM or increase its disk size"
+		}
+		i.currentStep.LogErrorf("Warning: only %s of disk space are free in the Docker daemon of Minikube, which stores %d images with %s not used by any container. "+
+			"Building the Kyma Installer image might fail with 'no space left on device'. %s", formatSize(free), info.Images, formatSize(uint64(info.UnusedSize)), hint)
+	}
+	return nil
+}
+
+// pruneDocker removes the unused images and stopped containers from the Docker daemon of Minikube, keeping the images of the running pods
+func (i *Installation) pruneDocker() error {
+	keep, err := i.imagesInUse()
+	if err != nil {
+		return pkgErrors.Wrap(err, "unable to determine the images used by the running pods. Retry without --prune-docker")
+	}
+	report, err := i.Docker.Prune(keep)
+	if err != nil {
+		return pkgErrors.Wrap(err, "unable to prune the Docker daemon of Minikube")
+	}
+	i.currentStep.LogInfof("Removed %d images and %d stopped containers from the Docker daemon, %s reclaimed", report.Images, report.Containers, formatSize(report.SpaceReclaimed))
+	return nil
+}
+
+// imagesInUse returns the images of all running pods, both as given in the pod specs and as resolved by the container runtime
+func (i *Installation) imagesInUse() ([]string, error) {
+	pods, err := i.K8s.Static().CoreV1().Pods("").List(context.Background(), metav1.ListOptions{FieldSelector: fmt.Sprintf("status.phase=%s", corev1.PodRunning)})
+	if err != nil {
+		return nil, err
+	}
+	var images []string
+	for _, pod := range pods.Items {
+		for _, c := range append(pod.Spec.InitContainers, pod.Spec.Containers...) {
+			images = append(images, c.Image)
+		}
+		for _, status := range append(pod.Status.InitContainerStatuses, pod.Status.ContainerStatuses...) {
+			images = append(images, status.Image, status.ImageID)
+		}
+	}
+	return images, nil
+}
+
+func (i *Installation) dockerFreeSpace(path string) (uint64, error) {
+	if i.freeDiskSpace != nil {
+		return i.freeDiskSpace(path)
+	}
+	if path == "" {
+		path = "/var/lib/docker"
+	}
+	return minikube.FreeDiskSpace(i.Options.Verbose, i.Options.LocalCluster.Profile, i.Options.Timeout, path)
+}
+
+// formatSize formats a size in bytes with a binary unit, e.g. "1.5 GiB"
+func formatSize(bytes uint64) string {
+	units := []string{"B", "KiB", "MiB", "GiB", "TiB"}
+	size := float64(bytes)
+	n := 0
+	for size >= 1024 && n < len(units)-1 {
+		size /= 1024
+		n++
+	}
+	if n == 0 {
+		return fmt.Sprintf("%d B", bytes)
+	}
+	return strings.TrimSuffix(fmt.Sprintf("%.1f", size), ".0") + " " + units[n]
+}
@@ -0,0 +1,101 @@
+package installation
+
+import (
+	"testing"
+
+	k8sMocks "github.com/kyma-project/cli/internal/kube/mocks"
+	"github.com/kyma-project/cli/pkg/docker"
+	"github.com/kyma-project/cli/pkg/installation/mocks"
+	stepMocks "github.com/kyma-project/cli/pkg/step/mocks"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestCheckDockerDiskSpace(t *testing.T) {
+	t.Parallel()
+	disk := docker.DiskInfo{RootDir: "/var/lib/docker", Images: 42, ImagesSize: 12 << 30, UnusedSize: 7 << 30}
+
+	newDocker := func() *mocks.KymaClient {
+		d := &mocks.KymaClient{}
+		d.On("DiskInfo").Return(disk, nil)
+		return d
+	}
+
+	t.Run("Enough free space", func(t *testing.T) {
+		s := &stepMocks.Step{}
+		i := &Installation{
+			Docker:        newDocker(),
+			currentStep:   s,
+			freeDiskSpace: func(string) (uint64, error) { return 20 << 30, nil },
+			Options:       &Options{},
+		}
+		require.NoError(t, i.checkDockerDiskSpace())
+		require.Empty(t, s.Errors())
+	})
+
+	t.Run("Low free space", func(t *testing.T) {
+		s := &stepMocks.Step{}
+		var path string
+		i := &Installation{
+			Docker:        newDocker(),
+			currentStep:   s,
+			freeDiskSpace: func(p string) (uint64, error) { path = p; return 1536 << 20, nil },
+			Options:       &Options{},
+		}
+		require.NoError(t, i.checkDockerDiskSpace())
+		require.Equal(t, "/var/lib/docker", path)
+		require.Equal(t, []string{"Warning: only 1.5 GiB of disk space are free in the Docker daemon of Minikube, which stores 42 images with 7 GiB not used by any container. " +
+			"Building the Kyma Installer image might fail with 'no space left on device'. Use --prune-docker to remove the images not used by any container"}, s.Errors())
+	})
+
+	t.Run("Prune keeps the images of running pods", func(t *testing.T) {
+		pod := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "core", Namespace: "kyma-system"},
+			Spec: corev1.PodSpec{
+				InitContainers: []corev1.Container{{Name: "init", Image: "eu.gcr.io/kyma-project/init:1.0"}},
+				Containers:     []corev1.Container{{Name: "core", Image: "eu.gcr.io/kyma-project/core:1.0"}},
+			},
+			Status: corev1.PodStatus{
+				Phase:             corev1.PodRunning,
+				ContainerStatuses: []corev1.ContainerStatus{{Name: "core", Image: "eu.gcr.io/kyma-project/core:1.0", ImageID: "docker-pullable://eu.gcr.io/kyma-project/core@sha256:abc"}},
+			},
+		}
+		kymaMock := &k8sMocks.KymaKube{}
+		kymaMock.On("Static").Return(fake.NewSimpleClientset(pod))
+
+		s := &stepMocks.Step{}
+		var kept []string
+		d := newDocker()
+		d.On("Prune", mock.Anything).Return(docker.PruneReport{Images: 3, Containers: 1, SpaceReclaimed: 2 << 30}, nil).
+			Run(func(args mock.Arguments) { kept = args.Get(0).([]string) })
+		i := &Installation{
+			Docker:        d,
+			K8s:           kymaMock,
+			currentStep:   s,
+			freeDiskSpace: func(string) (uint64, error) { return 20 << 30, nil },
+			Options:       &Options{PruneDocker: true},
+		}
+		require.NoError(t, i.checkDockerDiskSpace())
+		d.AssertCalled(t, "Prune", mock.Anything)
+		require.ElementsMatch(t, []string{
+			"eu.gcr.io/kyma-project/init:1.0",
+			"eu.gcr.io/kyma-project/core:1.0",
+			"eu.gcr.io/kyma-project/core:1.0",
+			"docker-pullable://eu.gcr.io/kyma-project/core@sha256:abc",
+		}, kept)
+		require.Equal(t, []string{
+			"Removed 3 images and 1 stopped containers from the Docker daemon, 2 GiB reclaimed",
+			"The Docker daemon stores 42 images with 12 GiB, 20 GiB are free",
+		}, s.Infos())
+	})
+}
+
+func TestFormatSize(t *testing.T) {
+	t.Parallel()
+	require.Equal(t, "512 B", formatSize(512))
+	require.Equal(t, "1.5 KiB", formatSize(1536))
+	require.Equal(t, "5 GiB", formatSize(5<<30))
+}
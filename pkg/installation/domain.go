@@ -0,0 +1,141 @@
+package installation
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+
+	pkgErrors "github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apiErrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+const (
+	// nipIOSuffix is the wildcard DNS service resolving "<ip>.nip.io" to the IP
+	nipIOSuffix = "nip.io"
+	// nipIOOverridesName is the name of the overrides ConfigMap holding the nip.io domain
+	nipIOOverridesName = "kyma-nip-io-overrides"
+
+	ingressGatewayNamespace = "istio-system"
+	ingressGatewayName      = "istio-ingressgateway"
+
+	errorNipIOLocal    = "You specified --use-nip-io, which is only supported for installations on remote clusters"
+	errorNipIODomain   = "You specified --use-nip-io, the flag --domain cannot be used with it"
+	errorNipIONoWait   = "You specified --use-nip-io, the flag --wait=false cannot be used with it, because the domain is determined while waiting for the installation"
+	errorNipIOTLSCerts = "You specified --use-nip-io, the flags --tls-cert and --tls-key cannot be used with it, because the certificate is generated for the determined domain"
+)
+
+// ValidateDomain ensures that the domain is a valid DNS subdomain (RFC 1123), as the host names of all Kyma services are derived from it.
+// Domains given as URLs, with a scheme, a port or a path, are rejected with the plain domain as an example.
+func ValidateDomain(domain string) error {
+	host := domain
+	if n := strings.Index(host, "://"); n >= 0 {
+		host = host[n+3:]
+	}
+	if n := strings.IndexAny(host, "/?#"); n >= 0 {
+		host = host[:n]
+	}
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	if strings.Contains(domain, "://") {
+		return fmt.Errorf("invalid domain '%s': the domain must not contain a scheme, use for example '%s'", domain, host)
+	}
+	if host != domain {
+		return fmt.Errorf("invalid domain '%s': the domain must not contain a port or a path, use for example '%s'", domain, host)
+	}
+	if strings.HasSuffix(domain, ".") {
+		return fmt.Errorf("invalid domain '%s': the domain must not end with a dot", domain)
+	}
+	if errs := validation.IsDNS1123Subdomain(domain); len(errs) > 0 {
+		return fmt.Errorf("invalid domain '%s': %s", domain, strings.Join(errs, ", "))
+	}
+	return nil
+}
+
+// validateNipIO ensures that the options can be used together with a nip.io domain
+func (i *Installation) validateNipIO() error {
+	if !i.Options.UseNipIO {
+		return nil
+	}
+	switch {
+	case i.Options.IsLocal:
+		return pkgErrors.New(errorNipIOLocal)
+	case i.Options.Domain != "" && i.Options.Domain != defaultDomain:
+		return pkgErrors.New(errorNipIODomain)
+	case i.Options.NoWait:
+		return pkgErrors.New(errorNipIONoWait)
+	case i.Options.TLSCert != "" || i.Options.TLSKey != "":
+		return pkgErrors.New(errorNipIOTLSCerts)
+	}
+	return nil
+}
+
+// applyNipIODomain sets the domain to "<ip>.nip.io" as soon as the load balancer of the Istio ingress gateway has an IP.
+// The domain is stored as an override for the Kyma Installer, so that all components installed afterwards use it.
+// It returns false if the IP is not assigned yet, for example because the gateway is not installed.
+func (i *Installation) applyNipIODomain() (bool, error) {
+	if i.nipIODomain != "" {
+		return true, nil
+	}
+
+	svc, err := i.K8s.Static().CoreV1().Services(ingressGatewayNamespace).Get(context.Background(), ingressGatewayName, metav1.GetOptions{})
+	if apiErrors.IsNotFound(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, pkgErrors.Wrap(err, "unable to get the load balancer of the Istio ingress gateway")
+	}
+	ip := loadBalancerIP(svc)
+	if ip == "" {
+		return false, nil
+	}
+
+	domain := fmt.Sprintf("%s.%s", ip, nipIOSuffix)
+	overrides := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      nipIOOverridesName,
+			Namespace: i.installerNamespace(),
+			Labels:    map[string]string{overridesLabelKey: overridesLabelValue},
+		},
+		Data: map[string]string{"global.domainName": domain},
+	}
+	configMaps := i.K8s.Static().CoreV1().ConfigMaps(i.installerNamespace())
+	if _, err := configMaps.Create(context.Background(), overrides, metav1.CreateOptions{}); err != nil {
+		if !apiErrors.IsAlreadyExists(err) {
+			return false, pkgErrors.Wrapf(err, "unable to configure the domain '%s'", domain)
+		}
+		if _, err := configMaps.Update(context.Background(), overrides, metav1.UpdateOptions{}); err != nil {
+			return false, pkgErrors.Wrapf(err, "unable to configure the domain '%s'", domain)
+		}
+	}
+
+	i.nipIODomain = domain
+	i.Options.Domain = domain
+	i.currentStep.LogInfof("Using the domain '%s' of the ingress gateway IP %s", domain, ip)
+	return true, nil
+}
+
+// loadBalancerIP returns the IPv4 address of the load balancer of the service, as nip.io only resolves IPv4 addresses.
+// Load balancers only providing a host name (e.g. on AWS) are resolved.
+func loadBalancerIP(svc *corev1.Service) string {
+	for _, ingress := range svc.Status.LoadBalancer.Ingress {
+		if ip := net.ParseIP(ingress.IP); ip != nil && ip.To4() != nil {
+			return ip.String()
+		}
+		if ingress.Hostname == "" {
+			continue
+		}
+		// the DNS entry of a new load balancer might not be propagated yet, then it is resolved at the next check
+		ips, _ := net.LookupIP(ingress.Hostname)
+		for _, ip := range ips {
+			if ip.To4() != nil {
+				return ip.String()
+			}
+		}
+	}
+	return ""
+}
@@ -0,0 +1,126 @@
+package installation
+
+import (
+	"context"
+	"testing"
+
+	k8sMocks "github.com/kyma-project/cli/internal/kube/mocks"
+	stepMocks "github.com/kyma-project/cli/pkg/step/mocks"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestValidateDomain(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		domain string
+		valid  bool
+	}{
+		{domain: "kyma.local", valid: true},
+		{domain: "my-cluster.example.com", valid: true},
+		{domain: "35.204.10.3.nip.io", valid: true},
+		{domain: "https://kyma.example.com"},
+		{domain: "kyma.example.com."},
+		{domain: "Kyma.Example.com"},
+		{domain: "kyma_example.com"},
+		{domain: "-kyma.example.com"},
+		{domain: "kyma.example.com:443"},
+		{domain: "kyma.example.com/console"},
+	}
+	for _, tc := range tests {
+		err := ValidateDomain(tc.domain)
+		if tc.valid {
+			require.NoError(t, err, tc.domain)
+		} else {
+			require.Error(t, err, tc.domain)
+		}
+	}
+
+	err := ValidateDomain("https://kyma.example.com")
+	require.EqualError(t, err, "invalid domain 'https://kyma.example.com': the domain must not contain a scheme, use for example 'kyma.example.com'")
+	err = ValidateDomain("kyma.example.com:8443/console")
+	require.EqualError(t, err, "invalid domain 'kyma.example.com:8443/console': the domain must not contain a port or a path, use for example 'kyma.example.com'")
+}
+
+func TestValidateNipIO(t *testing.T) {
+	t.Parallel()
+	i := &Installation{Options: &Options{UseNipIO: true, Domain: defaultDomain}}
+	require.NoError(t, i.validateNipIO())
+
+	i.Options.IsLocal = true
+	require.EqualError(t, i.validateNipIO(), errorNipIOLocal)
+
+	i.Options.IsLocal = false
+	i.Options.Domain = "kyma.example.com"
+	require.EqualError(t, i.validateNipIO(), errorNipIODomain)
+
+	i.Options.Domain = ""
+	i.Options.NoWait = true
+	require.EqualError(t, i.validateNipIO(), errorNipIONoWait)
+
+	i.Options.NoWait = false
+	i.Options.TLSCert = "fake-cert"
+	require.EqualError(t, i.validateNipIO(), errorNipIOTLSCerts)
+
+	// without the flag, nothing is checked
+	i.Options.UseNipIO = false
+	require.NoError(t, i.validateNipIO())
+}
+
+func TestApplyNipIODomain(t *testing.T) {
+	t.Parallel()
+	gateway := func(ingress ...corev1.LoadBalancerIngress) *corev1.Service {
+		return &corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{Name: ingressGatewayName, Namespace: ingressGatewayNamespace},
+			Status:     corev1.ServiceStatus{LoadBalancer: corev1.LoadBalancerStatus{Ingress: ingress}},
+		}
+	}
+	newInstallation := func(objects ...runtime.Object) (*Installation, *fake.Clientset) {
+		k8sMock := fake.NewSimpleClientset(objects...)
+		kymaMock := k8sMocks.KymaKube{}
+		kymaMock.On("Static").Return(k8sMock)
+		return &Installation{K8s: &kymaMock, currentStep: &stepMocks.Step{}, Options: &Options{UseNipIO: true, Domain: defaultDomain}}, k8sMock
+	}
+
+	// gateway not installed yet
+	i, _ := newInstallation()
+	applied, err := i.applyNipIODomain()
+	require.NoError(t, err)
+	require.False(t, applied)
+	require.Equal(t, defaultDomain, i.Options.Domain)
+
+	// load balancer without IP yet
+	i, _ = newInstallation(gateway())
+	applied, err = i.applyNipIODomain()
+	require.NoError(t, err)
+	require.False(t, applied)
+
+	// IPv6 addresses cannot be used with nip.io
+	i, _ = newInstallation(gateway(corev1.LoadBalancerIngress{IP: "2001:db8::1"}))
+	applied, err = i.applyNipIODomain()
+	require.NoError(t, err)
+	require.False(t, applied)
+
+	// IP assigned, the domain override is created
+	i, k8sMock := newInstallation(gateway(corev1.LoadBalancerIngress{IP: "35.204.10.3"}))
+	applied, err = i.applyNipIODomain()
+	require.NoError(t, err)
+	require.True(t, applied)
+	require.Equal(t, "35.204.10.3.nip.io", i.Options.Domain)
+	cm, err := k8sMock.CoreV1().ConfigMaps(installerNamespace()).Get(context.Background(), nipIOOverridesName, metav1.GetOptions{})
+	require.NoError(t, err)
+	require.Equal(t, "35.204.10.3.nip.io", cm.Data["global.domainName"])
+	require.Equal(t, overridesLabelValue, cm.Labels[overridesLabelKey])
+
+	// an existing override of a previous run is updated
+	i, k8sMock = newInstallation(gateway(corev1.LoadBalancerIngress{IP: "35.204.10.4"}), cm)
+	applied, err = i.applyNipIODomain()
+	require.NoError(t, err)
+	require.True(t, applied)
+	cm, err = k8sMock.CoreV1().ConfigMaps(installerNamespace()).Get(context.Background(), nipIOOverridesName, metav1.GetOptions{})
+	require.NoError(t, err)
+	require.Equal(t, "35.204.10.4.nip.io", cm.Data["global.domainName"])
+}
@@ -0,0 +1,79 @@
+package installation
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/kyma-project/cli/internal/github"
+	"github.com/kyma-project/cli/internal/release"
+)
+
+const (
+	// defaultApplyTimeout is used if no --apply-timeout is configured
+	defaultApplyTimeout = 5 * time.Minute
+	// downloadProgressInterval is the minimum time between two progress updates of a download on the step
+	downloadProgressInterval = 500 * time.Millisecond
+)
+
+// applyTimeout returns the time the download of a release artifact and the apply of the Kyma Installer may take
+func (i *Installation) applyTimeout() time.Duration {
+	if i.Options.ApplyTimeout > 0 {
+		return i.Options.ApplyTimeout
+	}
+	return defaultApplyTimeout
+}
+
+// downloadReleaseFile downloads a release artifact and shows the progress on the current step, artifacts stored in a local directory are read without progress.
+// The whole download must finish within the apply timeout, so that slow connections do not stall the installation.
+func (i *Installation) downloadReleaseFile(artifact release.Artifact) (io.ReadCloser, error) {
+	reader, size, err := artifact.Open(github.NewClient(i.applyTimeout()))
+	if err != nil {
+		return nil, fmt.Errorf("download of '%s' failed: %s", artifact.Name, err)
+	}
+	if i.currentStep == nil || artifact.Local() {
+		return reader, nil
+	}
+	return &downloadProgress{ReadCloser: reader, name: artifact.Name, total: size, status: i.currentStep.Status}, nil
+}
+
+// downloadProgress reports the downloaded bytes, and the percentage if the size is known, while the download is read
+type downloadProgress struct {
+	io.ReadCloser
+	name string
+	// total is the Content-Length of the download, it is -1 if unknown
+	total  int64
+	read   int64
+	last   time.Time
+	status func(msg string)
+}
+
+func (p *downloadProgress) Read(b []byte) (int, error) {
+	n, err := p.ReadCloser.Read(b)
+	p.read += int64(n)
+	if now := time.Now(); err == io.EOF || now.Sub(p.last) >= downloadProgressInterval {
+		p.last = now
+		p.status(p.String())
+	}
+	return n, err
+}
+
+func (p *downloadProgress) String() string {
+	if p.total > 0 {
+		return fmt.Sprintf("Downloading %s: %s of %s (%d%%)", p.name, formatSize(uint64(p.read)), formatSize(uint64(p.total)), p.read*100/p.total)
+	}
+	return fmt.Sprintf("Downloading %s: %s", p.name, formatSize(uint64(p.read)))
+}
+
+// applyWithTimeout runs the apply of the Kyma Installer with a context, which is canceled if the apply does not finish within the apply timeout.
+// It returns once the apply stopped, so that a failed installation is not cleaned up while resources are still applied.
+func (i *Installation) applyWithTimeout(apply func(ctx context.Context) error) error {
+	ctx, cancel := context.WithTimeout(i.installCtx(), i.applyTimeout())
+	defer cancel()
+	err := apply(ctx)
+	if err != nil && ctx.Err() == context.DeadlineExceeded {
+		return fmt.Errorf("apply of the Kyma Installer did not finish within %s. Check the connection to the cluster, or use --apply-timeout to wait longer", i.applyTimeout())
+	}
+	return err
+}
@@ -0,0 +1,80 @@
+package installation
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kyma-project/cli/internal/release"
+	stepMocks "github.com/kyma-project/cli/pkg/step/mocks"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDownloadReleaseFile(t *testing.T) {
+	t.Parallel()
+	content := strings.Repeat("kind: ConfigMap\n", 1024)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/kyma-config-local.yaml" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Length", strconv.Itoa(len(content)))
+		_, _ = w.Write([]byte(content))
+	}))
+	defer server.Close()
+
+	r, err := release.FromURL(server.URL, "1.15.1")
+	require.NoError(t, err)
+	s := &stepMocks.Step{}
+	i := &Installation{currentStep: s, Options: &Options{}}
+	reader, err := i.downloadReleaseFile(r.Artifact("kyma-config-local.yaml"))
+	require.NoError(t, err)
+	data, err := ioutil.ReadAll(reader)
+	require.NoError(t, err)
+	require.NoError(t, reader.Close())
+	require.Equal(t, content, string(data))
+	require.NotEmpty(t, s.Statuses())
+	require.Equal(t, "Downloading kyma-config-local.yaml: 16 KiB of 16 KiB (100%)", s.Statuses()[len(s.Statuses())-1])
+
+	_, err = i.downloadReleaseFile(r.Artifact("missing.yaml"))
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "download of 'missing.yaml' failed")
+	require.Contains(t, err.Error(), "404 Not Found")
+}
+
+func TestDownloadProgress(t *testing.T) {
+	t.Parallel()
+	p := &downloadProgress{name: "kyma-installer-cluster.yaml", total: 4 << 20, read: 1 << 20}
+	require.Equal(t, "Downloading kyma-installer-cluster.yaml: 1 MiB of 4 MiB (25%)", p.String())
+
+	// without Content-Length only the downloaded bytes are known
+	p.total = -1
+	require.Equal(t, "Downloading kyma-installer-cluster.yaml: 1 MiB", p.String())
+}
+
+func TestApplyWithTimeout(t *testing.T) {
+	t.Parallel()
+	i := &Installation{Options: &Options{ApplyTimeout: 50 * time.Millisecond}}
+	require.NoError(t, i.applyWithTimeout(func(ctx context.Context) error { return nil }))
+	require.EqualError(t, i.applyWithTimeout(func(ctx context.Context) error { return errors.New("forbidden") }), "forbidden")
+
+	stopped := false
+	err := i.applyWithTimeout(func(ctx context.Context) error {
+		<-ctx.Done()
+		// the apply takes a while to stop, which must be awaited before a failed installation is cleaned up
+		time.Sleep(10 * time.Millisecond)
+		stopped = true
+		return ctx.Err()
+	})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "did not finish within 50ms")
+	require.True(t, stopped, "the apply must have stopped")
+
+	require.Equal(t, defaultApplyTimeout, (&Installation{Options: &Options{}}).applyTimeout())
+}
@@ -0,0 +1,149 @@
+package installation
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/kyma-project/cli/internal/kube"
+	"github.com/kyma-project/cli/internal/metadata"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ComponentError is an error the Kyma Installer reported while installing a component.
+type ComponentError struct {
+	// Component is the name of the failing component.
+	Component string `json:"component"`
+	// Log holds the error message, usually the Helm error.
+	Log string `json:"log"`
+	// Occurrences indicates how often the error occurred.
+	Occurrences int `json:"occurrences"`
+}
+
+// ComponentErrors returns the component errors reported in the status of the Installation CR with the given name.
+// The CR is read without its typed schema, so that the status of all Kyma releases can be read. Repeated errors are merged.
+func ComponentErrors(k8s kube.KymaKube, name string) ([]ComponentError, error) {
+	return componentErrors(k8s, clusterMetadata(k8s.Static()), name)
+}
+
+// componentErrors reads the component errors of the Installation CR in the namespace of the metadata
+func componentErrors(k8s kube.KymaKube, m metadata.Metadata, name string) ([]ComponentError, error) {
+	cr, err := k8s.Dynamic().Resource(m.InstallationGVR()).Namespace(m.InstallationNamespace).Get(context.Background(), name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	status, _ := cr.Object["status"].(map[string]interface{})
+	return parseComponentErrors(status), nil
+}
+
+// parseComponentErrors reads the errors from the errorLog of the status, as well as failed entries of component or condition lists used by other releases
+func parseComponentErrors(status map[string]interface{}) []ComponentError {
+	var result []ComponentError
+	index := map[string]int{}
+	add := func(e ComponentError) {
+		if e.Log == "" {
+			return
+		}
+		if e.Occurrences < 1 {
+			e.Occurrences = 1
+		}
+		key := e.Component + "\x00" + e.Log
+		if n, ok := index[key]; ok {
+			result[n].Occurrences += e.Occurrences
+			return
+		}
+		index[key] = len(result)
+		result = append(result, e)
+	}
+
+	for _, entry := range listField(status, "errorLog") {
+		add(ComponentError{
+			Component:   firstString(entry, "component", "name"),
+			Log:         firstString(entry, "log", "message", "error"),
+			Occurrences: intField(entry, "occurrences"),
+		})
+	}
+	for _, field := range []string{"components", "conditions"} {
+		for _, entry := range listField(status, field) {
+			state := strings.ToLower(firstString(entry, "state", "status", "type"))
+			if !strings.Contains(state, "error") && !strings.Contains(state, "fail") {
+				continue
+			}
+			add(ComponentError{
+				Component: firstString(entry, "component", "name"),
+				Log:       firstString(entry, "log", "message", "reason"),
+			})
+		}
+	}
+	return result
+}
+
+// FormatComponentErrors returns a readable description of the component errors, starting with the first failing component.
+func FormatComponentErrors(errs []ComponentError) string {
+	if len(errs) == 0 {
+		return ""
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "Component '%s' failed first%s:\n%s\n", errs[0].Component, occurrences(errs[0]), indent(errs[0].Log))
+	if len(errs) > 1 {
+		b.WriteString("Further errors:\n")
+		for _, e := range errs[1:] {
+			fmt.Fprintf(&b, "- %s%s:\n%s\n", e.Component, occurrences(e), indent(e.Log))
+		}
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+func occurrences(e ComponentError) string {
+	if e.Occurrences > 1 {
+		return fmt.Sprintf(" (%d times)", e.Occurrences)
+	}
+	return ""
+}
+
+func indent(s string) string {
+	return "    " + strings.Replace(strings.TrimSpace(s), "\n", "\n    ", -1)
+}
+
+func listField(m map[string]interface{}, field string) []map[string]interface{} {
+	items, _ := m[field].([]interface{})
+	var result []map[string]interface{}
+	for _, item := range items {
+		if entry, ok := item.(map[string]interface{}); ok {
+			result = append(result, entry)
+		}
+	}
+	return result
+}
+
+func firstString(m map[string]interface{}, fields ...string) string {
+	for _, f := range fields {
+		if value, ok := m[f]; ok && value != nil {
+			if s := strings.TrimSpace(fmt.Sprint(value)); s != "" {
+				return s
+			}
+		}
+	}
+	return ""
+}
+
+func intField(m map[string]interface{}, field string) int {
+	switch value := m[field].(type) {
+	case int64:
+		return int(value)
+	case float64:
+		return int(value)
+	case string:
+		n, _ := strconv.Atoi(value)
+		return n
+	}
+	return 0
+}
+
+// forbiddenError explains that the user of the kubeconfig lacks a permission which the installation cannot do without.
+// Each pre-check decides if a Forbidden error is fatal: reading the cluster type and creating existing namespaces are skipped,
+// whereas the installation lock, the Installation CR lookup, and the Installation CRD are required.
+func forbiddenError(err error, action string) error {
+	return fmt.Errorf("The user of the kubeconfig is not permitted to %s, which is required for the installation. Ask your cluster administrator for the permission: %s", action, err)
+}
@@ -0,0 +1,80 @@
+package installation
+
+import (
+	"testing"
+
+	installationSDK "github.com/kyma-incubator/hydroform/install/installation"
+	k8sMocks "github.com/kyma-project/cli/internal/kube/mocks"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	fakeDynamic "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestParseComponentErrors(t *testing.T) {
+	t.Parallel()
+	status := map[string]interface{}{
+		"errorLog": []interface{}{
+			map[string]interface{}{"component": "cluster-essentials", "log": "helm: timed out waiting for the condition", "occurrences": int64(2)},
+			map[string]interface{}{"component": "istio", "log": "release istio failed", "occurrences": "1"},
+			map[string]interface{}{"component": "cluster-essentials", "log": "helm: timed out waiting for the condition", "occurrences": float64(3)},
+			map[string]interface{}{"component": "empty"},
+			"not an entry",
+		},
+		// schema used by other releases
+		"components": []interface{}{
+			map[string]interface{}{"name": "dex", "state": "Error", "message": "dex failed"},
+			map[string]interface{}{"name": "core", "state": "Installed", "message": "done"},
+		},
+	}
+
+	errs := parseComponentErrors(status)
+	require.Equal(t, []ComponentError{
+		{Component: "cluster-essentials", Log: "helm: timed out waiting for the condition", Occurrences: 5},
+		{Component: "istio", Log: "release istio failed", Occurrences: 1},
+		{Component: "dex", Log: "dex failed", Occurrences: 1},
+	}, errs)
+
+	formatted := FormatComponentErrors(errs)
+	require.Contains(t, formatted, "Component 'cluster-essentials' failed first (5 times):")
+	require.Contains(t, formatted, "- istio:\n    release istio failed")
+
+	require.Empty(t, parseComponentErrors(nil))
+	require.Empty(t, FormatComponentErrors(nil))
+}
+
+func TestGetStatus(t *testing.T) {
+	t.Parallel()
+	cr := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "installer.kyma-project.io/v1alpha1",
+		"kind":       "Installation",
+		"metadata":   map[string]interface{}{"name": "kyma-installation", "namespace": "default"},
+		"status": map[string]interface{}{
+			"state":       "Error",
+			"description": "Install component istio",
+			"errorLog": []interface{}{
+				map[string]interface{}{"component": "istio", "log": "release istio failed", "occurrences": int64(1)},
+			},
+		},
+	}}
+
+	kymaMock := k8sMocks.KymaKube{}
+	kymaMock.On("Dynamic").Return(fakeDynamic.NewSimpleDynamicClient(runtime.NewScheme(), cr))
+	kymaMock.On("Static").Return(fake.NewSimpleClientset())
+	status, err := GetStatus(&kymaMock, "")
+	require.NoError(t, err)
+	require.Equal(t, "kyma-installation", status.Name)
+	require.Equal(t, "Error", status.State)
+	require.Equal(t, "Install component istio", status.Description)
+	require.Equal(t, "N/A", status.KymaVersion)
+	require.Equal(t, []ComponentError{{Component: "istio", Log: "release istio failed", Occurrences: 1}}, status.Errors)
+
+	// no installation on the cluster
+	kymaMock = k8sMocks.KymaKube{}
+	kymaMock.On("Dynamic").Return(fakeDynamic.NewSimpleDynamicClient(runtime.NewScheme()))
+	kymaMock.On("Static").Return(fake.NewSimpleClientset())
+	status, err = GetStatus(&kymaMock, "")
+	require.NoError(t, err)
+	require.Equal(t, installationSDK.NoInstallationState, status.State)
+}
@@ -0,0 +1,155 @@
+package installation
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/kubernetes"
+)
+
+var (
+	// watchedEventNamespaces are the namespaces whose warning events are displayed with --watch-events
+	watchedEventNamespaces = []string{"kyma-installer", "kyma-system"}
+	// eventBurst is the number of warning events displayed within eventRateInterval, further events are only counted
+	eventBurst = 5
+	// eventRateInterval is the time window of eventBurst
+	eventRateInterval = time.Minute
+	// eventRewatchDelay is the time before the events are watched again once the watch failed or was closed by the API server
+	eventRewatchDelay = 2 * time.Second
+)
+
+// eventWatcher collects the warning events of some namespaces while the installation is waited for.
+// The events are only collected by the watches, they are logged by the caller, so that the steps are only changed by one goroutine.
+type eventWatcher struct {
+	k8s kubernetes.Interface
+	// since is the time the watcher started, older events are ignored
+	since  time.Time
+	cancel context.CancelFunc
+	done   sync.WaitGroup
+
+	mu sync.Mutex
+	// seen holds the events collected so far, repeated events are only collected once
+	seen    map[string]bool
+	pending []string
+	// window is the start of the current rate limit window, and inWindow the number of events collected in it
+	window     time.Time
+	inWindow   int
+	suppressed int
+}
+
+// startEventWatcher watches the warning events of the namespaces until stop is called
+func startEventWatcher(k8s kubernetes.Interface, namespaces []string) *eventWatcher {
+	ctx, cancel := context.WithCancel(context.Background())
+	// the times of events are recorded in seconds
+	w := &eventWatcher{k8s: k8s, since: time.Now().Truncate(time.Second), cancel: cancel, seen: map[string]bool{}}
+	for _, ns := range namespaces {
+		w.done.Add(1)
+		go func(ns string) {
+			defer w.done.Done()
+			w.watch(ctx, ns)
+		}(ns)
+	}
+	return w
+}
+
+// stop ends the watches and waits until they returned
+func (w *eventWatcher) stop() {
+	w.cancel()
+	w.done.Wait()
+}
+
+// watch lists the events of the namespace and watches them from there on.
+// If the watch fails or the API server closes it, e.g. because the resource version expired, the events are listed and watched again.
+func (w *eventWatcher) watch(ctx context.Context, namespace string) {
+	for {
+		list, err := w.k8s.CoreV1().Events(namespace).List(ctx, metav1.ListOptions{})
+		if err == nil {
+			for n := range list.Items {
+				w.add(&list.Items[n])
+			}
+			var wi watch.Interface
+			if wi, err = w.k8s.CoreV1().Events(namespace).Watch(ctx, metav1.ListOptions{ResourceVersion: list.ResourceVersion}); err == nil {
+				w.receive(ctx, wi)
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(eventRewatchDelay):
+		}
+	}
+}
+
+// receive collects the events of the watch until it is closed or the context is done
+func (w *eventWatcher) receive(ctx context.Context, wi watch.Interface) {
+	defer wi.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case e, ok := <-wi.ResultChan():
+			if !ok || e.Type == watch.Error {
+				return
+			}
+			if event, ok := e.Object.(*corev1.Event); ok {
+				w.add(event)
+			}
+		}
+	}
+}
+
+// add collects a warning event which happened after the watcher started and was not collected before, unless the rate limit is reached
+func (w *eventWatcher) add(e *corev1.Event) {
+	if e.Type != corev1.EventTypeWarning || eventTime(e).Before(w.since) {
+		return
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	key := strings.Join([]string{e.InvolvedObject.Namespace, e.InvolvedObject.Kind, e.InvolvedObject.Name, e.Reason, e.Message}, "/")
+	if w.seen[key] {
+		return
+	}
+	w.seen[key] = true
+
+	if now := time.Now(); now.Sub(w.window) >= eventRateInterval {
+		w.window = now
+		w.inWindow = 0
+	}
+	if w.inWindow >= eventBurst {
+		w.suppressed++
+		return
+	}
+	w.inWindow++
+	w.pending = append(w.pending, fmt.Sprintf("Warning event %s of the %s '%s/%s': %s",
+		e.Reason, strings.ToLower(e.InvolvedObject.Kind), e.InvolvedObject.Namespace, e.InvolvedObject.Name, strings.TrimSpace(e.Message)))
+}
+
+// drain returns the events collected since the last call, and how many of them were suppressed by the rate limit
+func (w *eventWatcher) drain() []string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	msgs := w.pending
+	if w.suppressed > 0 {
+		msgs = append(msgs, fmt.Sprintf("%d more warning events are not displayed. To list them, run: kubectl get events --all-namespaces --field-selector type=Warning", w.suppressed))
+	}
+	w.pending = nil
+	w.suppressed = 0
+	return msgs
+}
+
+// eventTime returns the time the event last occurred, depending on the API version that recorded it
+func eventTime(e *corev1.Event) time.Time {
+	switch {
+	case !e.LastTimestamp.IsZero():
+		return e.LastTimestamp.Time
+	case !e.EventTime.IsZero():
+		return e.EventTime.Time
+	}
+	return e.CreationTimestamp.Time
+}
@@ -0,0 +1,102 @@
+package installation
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/kubernetes/fake"
+	k8sTesting "k8s.io/client-go/testing"
+)
+
+func warningEvent(name, reason, message string, at time.Time) *corev1.Event {
+	return &corev1.Event{
+		ObjectMeta:     metav1.ObjectMeta{Name: name, Namespace: "kyma-system"},
+		InvolvedObject: corev1.ObjectReference{Kind: "Pod", Namespace: "kyma-system", Name: "logging-loki-0"},
+		Type:           corev1.EventTypeWarning,
+		Reason:         reason,
+		Message:        message,
+		LastTimestamp:  metav1.NewTime(at),
+	}
+}
+
+func TestEventWatcherAdd(t *testing.T) {
+	// not parallel: the package level rate limit is modified
+	defer func(burst int) { eventBurst = burst }(eventBurst)
+	eventBurst = 2
+
+	now := time.Now()
+	w := &eventWatcher{since: now.Add(-time.Second), seen: map[string]bool{}}
+	w.add(warningEvent("old", "FailedScheduling", "0/1 nodes are available", now.Add(-time.Hour)))
+	normal := warningEvent("normal", "Pulled", "image pulled", now)
+	normal.Type = corev1.EventTypeNormal
+	w.add(normal)
+	w.add(warningEvent("backoff", "BackOff", "Back-off pulling image", now))
+	// the count of a repeated event is increased, it is only displayed once
+	w.add(warningEvent("backoff", "BackOff", "Back-off pulling image", now.Add(time.Second)))
+	w.add(warningEvent("webhook", "FailedCreate", "admission webhook denied the request", now))
+	w.add(warningEvent("scheduling", "FailedScheduling", "0/1 nodes are available", now))
+	w.add(warningEvent("mount", "FailedMount", "secret not found", now))
+
+	require.Equal(t, []string{
+		"Warning event BackOff of the pod 'kyma-system/logging-loki-0': Back-off pulling image",
+		"Warning event FailedCreate of the pod 'kyma-system/logging-loki-0': admission webhook denied the request",
+		"2 more warning events are not displayed. To list them, run: kubectl get events --all-namespaces --field-selector type=Warning",
+	}, w.drain())
+	require.Empty(t, w.drain())
+}
+
+func TestEventWatcherWatch(t *testing.T) {
+	// not parallel: the package level delay is modified
+	defer func(delay time.Duration) { eventRewatchDelay = delay }(eventRewatchDelay)
+	eventRewatchDelay = time.Millisecond
+
+	static := fake.NewSimpleClientset(warningEvent("old", "FailedScheduling", "0/1 nodes are available", time.Now().Add(-time.Hour)))
+	// the first watch is closed by the API server, the events are watched again
+	watchers := []*watch.FakeWatcher{watch.NewFake(), watch.NewFake()}
+	watches := make(chan int, 10)
+	static.PrependWatchReactor("events", func(a k8sTesting.Action) (bool, watch.Interface, error) {
+		if a.GetNamespace() != "kyma-system" {
+			return true, watch.NewEmptyWatch(), nil
+		}
+		n := len(watches)
+		watches <- n
+		if n >= len(watchers) {
+			return true, watch.NewFake(), nil
+		}
+		return true, watchers[n], nil
+	})
+	lists := 0
+	static.PrependReactor("list", "events", func(a k8sTesting.Action) (bool, runtime.Object, error) {
+		if a.GetNamespace() == "kyma-system" {
+			lists++
+		}
+		return false, nil, nil
+	})
+
+	w := startEventWatcher(static, watchedEventNamespaces)
+	<-watches
+	watchers[0].Add(warningEvent("backoff", "BackOff", "Back-off pulling image", time.Now()))
+	watchers[0].Stop()
+	<-watches
+	watchers[1].Add(warningEvent("webhook", "FailedCreate", "admission webhook denied the request", time.Now()))
+	// the event is delivered again after the reset, it is not displayed twice
+	watchers[1].Add(warningEvent("backoff", "BackOff", "Back-off pulling image", time.Now()))
+
+	var msgs []string
+	require.Eventually(t, func() bool {
+		msgs = append(msgs, w.drain()...)
+		return len(msgs) >= 2
+	}, 5*time.Second, 10*time.Millisecond)
+	w.stop()
+	msgs = append(msgs, w.drain()...)
+	require.Equal(t, []string{
+		"Warning event BackOff of the pod 'kyma-system/logging-loki-0': Back-off pulling image",
+		"Warning event FailedCreate of the pod 'kyma-system/logging-loki-0': admission webhook denied the request",
+	}, msgs)
+	require.True(t, lists >= 2, "the events must be listed again after the watch was closed")
+}
@@ -0,0 +1,126 @@
+package installation
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// describeStage returns the operations the stage performs on the cluster and the local machine, as shown with --explain.
+// The stages are described right before they run, so that the results of the previous stages, like the resolved version or the loaded files, are included.
+func (i *Installation) describeStage(name string, files map[string]*File) []string {
+	switch name {
+	case stageLock:
+		return []string{
+			fmt.Sprintf("create the namespace '%s' if it does not exist", i.installerNamespace()),
+			fmt.Sprintf("create the ConfigMap '%s' in the namespace '%s' to keep other CLI instances from changing the cluster, it is deleted when the CLI exits", lockName, i.installerNamespace()),
+		}
+
+	case stagePrevInstallation:
+		return []string{
+			fmt.Sprintf("list the Installation CRs in the namespace '%s'", i.installationNamespace()),
+			fmt.Sprintf("list the pods with the label %s in the namespace '%s' to read the installed version", strings.Join(i.metadata().InstallerSelectors, " or "), i.installerNamespace()),
+		}
+
+	case stageValidation:
+		var ops []string
+		switch {
+		case strings.EqualFold(i.Options.Source, sourceLocal):
+			src := i.Options.LocalSrcPath
+			if src == "" {
+				src = "the default location of the Kyma sources"
+			}
+			ops = append(ops, fmt.Sprintf("read the local sources at %s", src))
+		case strings.EqualFold(i.Options.Source, sourceMaster), isDockerImage(i.Options.Source):
+			ops = append(ops, "clone https://github.com/kyma-project/kyma into memory to find the newest master commit")
+		}
+		if i.Options.IsLocal && i.Options.LocalCluster != nil && i.Options.LocalCluster.Provider == providerMinikube {
+			ops = append(ops, "list the pods with the label component=kube-apiserver in the namespace 'kube-system' and run 'minikube addons list' to check the Minikube settings")
+		}
+		if i.Options.CIDRCheck {
+			ops = append(ops, "read the local routing table, the control plane pods in the namespace 'kube-system' and the nodes, and create a Service in dry-run mode to compare the cluster CIDRs with the local routes")
+		}
+		var conflicts []string
+		for _, c := range knownConflicts {
+			conflicts = append(conflicts, c.name)
+		}
+		return append(ops, fmt.Sprintf("get the namespaces, CRDs and deployments of %s to check for conflicting installations", strings.Join(conflicts, ", ")))
+
+	case stagePreparation:
+		var ops []string
+		set := i.installationFileSet()
+		for _, name := range sortedFileNames(set) {
+			path := set[name].Path
+			switch {
+			case name == installerFile && i.Options.InstallerManifest != "":
+				ops = append(ops, fmt.Sprintf("read the installer manifest %s", i.Options.InstallerManifest))
+			case i.Options.fromLocalSources:
+				ops = append(ops, fmt.Sprintf("read %s", filepath.Join(i.Options.LocalSrcPath, "installation", "resources", path)))
+			case i.Options.release.Artifact(path).Local():
+				ops = append(ops, fmt.Sprintf("read %s", i.Options.release.Artifact(path)))
+			default:
+				ops = append(ops, fmt.Sprintf("download %s", i.Options.release.Artifact(path)))
+			}
+		}
+		if i.Options.fromLocalSources && !i.Options.DryRun {
+			if i.Options.IsLocal {
+				ops = append(ops, fmt.Sprintf("build the Kyma Installer image with the Docker daemon of Minikube, using the build context %s", i.Options.LocalSrcPath))
+			} else {
+				ops = append(ops, fmt.Sprintf("build the Kyma Installer image '%s' with the local Docker daemon, using the build context %s, and push it", i.Options.CustomImage, i.Options.LocalSrcPath))
+			}
+		}
+		if i.imagePullSecretConfigured() && !i.Options.DryRun {
+			ops = append(ops, fmt.Sprintf("create or update the Secret '%s' in the Kyma namespaces", imagePullSecretName))
+		}
+		return ops
+
+	case stageExport:
+		return []string{fmt.Sprintf("write the manifests to a new directory in %s", i.Options.ExportManifests)}
+
+	case stageTrigger:
+		var ops []string
+		if i.nodeLabelingConfigured() {
+			selector := "all nodes"
+			if i.Options.NodeLabelSelector != "" {
+				selector = fmt.Sprintf("the nodes matching '%s'", i.Options.NodeLabelSelector)
+			}
+			ops = append(ops, fmt.Sprintf("add the labels and taints to %s and record them in the ConfigMap '%s' in the namespace '%s'", selector, installInfoName, i.installerNamespace()))
+		}
+		if f := files[installerFile]; f != nil {
+			ops = append(ops, applyOperations(f)...)
+		}
+		ops = append(ops, "create or update the ConfigMaps and Secrets with the installation configuration")
+		if f := files[installerCRFile]; f != nil {
+			ops = append(ops, applyOperations(f)...)
+		}
+		return append(ops, "wait for the Kyma Installer pod to run")
+	}
+	return nil
+}
+
+// applyOperations lists the resources of the documents of the file, the way kubectl refers to them
+func applyOperations(f *File) []string {
+	var ops []string
+	for _, doc := range f.Content {
+		r, ok := documentResource(doc)
+		if !ok {
+			continue
+		}
+		op := fmt.Sprintf("apply %s/%s", strings.ToLower(r.Kind), r.Name)
+		if r.Namespace != "" {
+			op += fmt.Sprintf(" in the namespace '%s'", r.Namespace)
+		}
+		ops = append(ops, op)
+	}
+	return ops
+}
+
+func sortedFileNames(files map[string]*File) []string {
+	var names []string
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
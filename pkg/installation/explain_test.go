@@ -0,0 +1,43 @@
+package installation
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDescribeStage(t *testing.T) {
+	t.Parallel()
+	i := &Installation{Options: &Options{Source: "1.15.1", IsLocal: true, LocalCluster: &LocalCluster{Provider: providerMinikube}}}
+	require.NoError(t, i.resolveReleaseArtifacts("1.15.1"))
+
+	require.Contains(t, i.describeStage(stageValidation, nil), "get the namespaces, CRDs and deployments of istio, cert-manager, knative to check for conflicting installations")
+	require.Len(t, i.describeStage(stageValidation, nil), 2, "the Minikube settings are checked for local Minikube clusters")
+
+	require.Equal(t, []string{
+		"download https://storage.googleapis.com/kyma-prow-artifacts/1.15.1/kyma-installer-cluster.yaml",
+		"download https://storage.googleapis.com/kyma-prow-artifacts/1.15.1/kyma-installer-cr-local.yaml",
+		"download https://storage.googleapis.com/kyma-prow-artifacts/1.15.1/kyma-config-local.yaml",
+	}, i.describeStage(stagePreparation, nil))
+
+	i.Options.fromLocalSources = true
+	i.Options.LocalSrcPath = "/src/kyma"
+	require.Contains(t, i.describeStage(stagePreparation, nil), "build the Kyma Installer image with the Docker daemon of Minikube, using the build context /src/kyma")
+
+	files := map[string]*File{
+		installerFile: {Content: []map[string]interface{}{
+			{"apiVersion": "v1", "kind": "Namespace", "metadata": map[interface{}]interface{}{"name": "kyma-installer"}},
+			{"apiVersion": "apps/v1", "kind": "Deployment", "metadata": map[interface{}]interface{}{"name": "kyma-installer", "namespace": "kyma-installer"}},
+		}},
+		installerCRFile: {Content: []map[string]interface{}{
+			{"apiVersion": "installer.kyma-project.io/v1alpha1", "kind": "Installation", "metadata": map[interface{}]interface{}{"name": "kyma-installation", "namespace": "default"}},
+		}},
+	}
+	require.Equal(t, []string{
+		"apply namespace/kyma-installer",
+		"apply deployment/kyma-installer in the namespace 'kyma-installer'",
+		"create or update the ConfigMaps and Secrets with the installation configuration",
+		"apply installation/kyma-installation in the namespace 'default'",
+		"wait for the Kyma Installer pod to run",
+	}, i.describeStage(stageTrigger, files))
+}
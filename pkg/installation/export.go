@@ -0,0 +1,252 @@
+package installation
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	installationSDK "github.com/kyma-incubator/hydroform/install/installation"
+	"github.com/kyma-project/cli/internal/metadata"
+	pkgErrors "github.com/pkg/errors"
+	"gopkg.in/yaml.v2"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+const (
+	exportDirPrefix  = "kyma-manifests-"
+	exportReleaseDir = "release"
+	redactedValue    = "<redacted>"
+
+	// labels hydroform puts on the configuration it applies
+	overridesLabelKey     = "installer"
+	overridesLabelValue   = "overrides"
+	componentOverridesKey = installationSDK.ComponentOverridesLabelKey
+)
+
+// manifestExporter writes the documents passed to it as numbered YAML files, in the order they are applied.
+type manifestExporter struct {
+	dir    string
+	redact bool
+	count  int
+}
+
+// exportManifests writes every manifest applied by the installation into a new timestamped directory and returns its path.
+// For installations from a release, the downloaded release artifacts are stored unmodified in a subdirectory.
+func (i *Installation) exportManifests(files map[string]*File) (string, error) {
+	files, err := loadStringContent(files)
+	if err != nil {
+		return "", fmt.Errorf("Failed to load installation files: %s", err.Error())
+	}
+	configuration, err := i.loadConfigurations(files)
+	if err != nil {
+		return "", pkgErrors.Wrap(err, "unable to load the configurations")
+	}
+
+	dir := filepath.Join(i.Options.ExportManifests, exportDirPrefix+time.Now().Format("20060102-150405"))
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return "", pkgErrors.Wrapf(err, "unable to create the export directory '%s'", dir)
+	}
+	e := &manifestExporter{dir: dir, redact: i.Options.RedactSecrets}
+
+	if i.imagePullSecretConfigured() {
+		secrets, err := i.imagePullSecrets()
+		if err != nil {
+			return "", err
+		}
+		for _, s := range secrets {
+			if err := e.writeObject(s); err != nil {
+				return "", err
+			}
+		}
+	}
+
+	for _, doc := range files[installerFile].Content {
+		if err := e.write(doc); err != nil {
+			return "", err
+		}
+	}
+
+	for _, doc := range configurationManifests(configuration, i.installerNamespace()) {
+		if err := e.write(doc); err != nil {
+			return "", err
+		}
+	}
+
+	for _, doc := range files[installerCRFile].Content {
+		if err := e.write(doc); err != nil {
+			return "", err
+		}
+	}
+
+	if err := e.writePatch(activationPatch(i.metadata(), i.installationName()), "installation", i.installationName()); err != nil {
+		return "", err
+	}
+
+	if !i.Options.fromLocalSources {
+		if err := exportReleaseArtifacts(dir, files); err != nil {
+			return "", err
+		}
+	}
+
+	return dir, nil
+}
+
+// write stores a single document in the next numbered file
+func (e *manifestExporter) write(doc map[string]interface{}) error {
+	return e.writeFile(doc, strings.ToLower(stringField(doc, "kind")), stringField(docMetadata(doc), "name"))
+}
+
+// writeObject stores a typed Kubernetes object in the next numbered file
+func (e *manifestExporter) writeObject(obj runtime.Object) error {
+	doc, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+	if err != nil {
+		return pkgErrors.Wrap(err, "unable to convert the object for the export")
+	}
+	return e.write(doc)
+}
+
+// writePatch stores a patch of the resource with the given kind and name in the next numbered file
+func (e *manifestExporter) writePatch(patch map[string]interface{}, kind, name string) error {
+	return e.writeFile(patch, kind, name+"-patch")
+}
+
+func (e *manifestExporter) writeFile(doc map[string]interface{}, kind, name string) error {
+	if e.redact {
+		doc = redactSecretData(doc)
+	}
+	data, err := yaml.Marshal(doc)
+	if err != nil {
+		return pkgErrors.Wrapf(err, "unable to export the %s '%s'", kind, name)
+	}
+
+	e.count++
+	file := filepath.Join(e.dir, fmt.Sprintf("%03d-%s-%s.yaml", e.count, kind, name))
+	if err := ioutil.WriteFile(file, data, 0600); err != nil {
+		return pkgErrors.Wrapf(err, "unable to write the exported manifest '%s'", file)
+	}
+	return nil
+}
+
+// exportReleaseArtifacts stores the release artifacts as they were downloaded
+func exportReleaseArtifacts(dir string, files map[string]*File) error {
+	releaseDir := filepath.Join(dir, exportReleaseDir)
+	for _, f := range files {
+		if f.Downloaded == nil {
+			continue
+		}
+		if err := os.MkdirAll(releaseDir, 0700); err != nil {
+			return pkgErrors.Wrapf(err, "unable to create the export directory '%s'", releaseDir)
+		}
+		file := filepath.Join(releaseDir, filepath.Base(f.Path))
+		if err := ioutil.WriteFile(file, f.Downloaded.Bytes(), 0600); err != nil {
+			return pkgErrors.Wrapf(err, "unable to write the release artifact '%s'", file)
+		}
+	}
+	return nil
+}
+
+// configurationManifests returns the ConfigMaps and Secrets the Kyma Installer gets its overrides from, as they are applied by hydroform.
+// They are created in the namespace of the Kyma Installer.
+func configurationManifests(configuration installationSDK.Configuration, namespace string) []map[string]interface{} {
+	docs := configurationResources("global", "", namespace, configuration.Configuration)
+	for _, c := range configuration.ComponentConfiguration {
+		docs = append(docs, configurationResources(c.Component, c.Component, namespace, c.Configuration)...)
+	}
+	return docs
+}
+
+func configurationResources(prefix, component, namespace string, entries installationSDK.ConfigEntries) []map[string]interface{} {
+	labels := map[string]interface{}{overridesLabelKey: overridesLabelValue}
+	if component != "" {
+		labels[componentOverridesKey] = component
+	}
+	meta := func() map[string]interface{} {
+		return map[string]interface{}{
+			"name":      fmt.Sprintf("%s-installer-config", prefix),
+			"namespace": namespace,
+			"labels":    labels,
+		}
+	}
+
+	configMapData := map[string]interface{}{}
+	secretData := map[string]interface{}{}
+	for _, entry := range entries {
+		if entry.Secret {
+			secretData[entry.Key] = entry.Value
+			continue
+		}
+		configMapData[entry.Key] = entry.Value
+	}
+
+	return []map[string]interface{}{
+		{"apiVersion": "v1", "kind": "ConfigMap", "metadata": meta(), "data": configMapData},
+		{"apiVersion": "v1", "kind": "Secret", "metadata": meta(), "stringData": secretData},
+	}
+}
+
+// activationPatch returns the merge patch of the Installation CR that starts the Kyma Installer
+func activationPatch(m metadata.Metadata, name string) map[string]interface{} {
+	return map[string]interface{}{
+		"apiVersion": m.InstallationGVR().GroupVersion().String(),
+		"kind":       "Installation",
+		"metadata": map[string]interface{}{
+			"name":      name,
+			"namespace": m.InstallationNamespace,
+			"labels": map[string]interface{}{
+				"action": "install",
+			},
+		},
+	}
+}
+
+// redactSecretData returns a copy of a Secret document with all its values replaced, other documents are returned as they are.
+// The passed document is not modified, as it is still applied after the export.
+func redactSecretData(doc map[string]interface{}) map[string]interface{} {
+	if stringField(doc, "kind") != "Secret" {
+		return doc
+	}
+	redacted := make(map[string]interface{}, len(doc))
+	for k, v := range doc {
+		redacted[k] = v
+	}
+	for _, field := range []string{"data", "stringData"} {
+		switch values := doc[field].(type) {
+		case map[string]interface{}:
+			result := make(map[string]interface{}, len(values))
+			for k := range values {
+				result[k] = redactedValue
+			}
+			redacted[field] = result
+		case map[interface{}]interface{}:
+			result := make(map[interface{}]interface{}, len(values))
+			for k := range values {
+				result[k] = redactedValue
+			}
+			redacted[field] = result
+		}
+	}
+	return redacted
+}
+
+// docMetadata returns the metadata of a document decoded either by yaml or by the unstructured converter
+func docMetadata(doc map[string]interface{}) map[string]interface{} {
+	switch meta := doc["metadata"].(type) {
+	case map[string]interface{}:
+		return meta
+	case map[interface{}]interface{}:
+		result := make(map[string]interface{}, len(meta))
+		for k, v := range meta {
+			result[fmt.Sprint(k)] = v
+		}
+		return result
+	}
+	return nil
+}
+
+func stringField(doc map[string]interface{}, field string) string {
+	value, _ := doc[field].(string)
+	return value
+}
@@ -0,0 +1,102 @@
+package installation
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v2"
+)
+
+func TestExportManifests(t *testing.T) {
+	t.Parallel()
+	dir, err := ioutil.TempDir("", "export-manifests")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	installerSecret := map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "Secret",
+		"metadata":   map[interface{}]interface{}{"name": "installer-secret", "namespace": "kyma-installer"},
+		"data":       map[interface{}]interface{}{"token": "c2VjcmV0"},
+	}
+	files := map[string]*File{
+		installerFile: {
+			Path: "kyma-installer-cluster.yaml",
+			Content: []map[string]interface{}{
+				{"apiVersion": "v1", "kind": "Namespace", "metadata": map[interface{}]interface{}{"name": "kyma-installer"}},
+				installerSecret,
+			},
+			Downloaded: bytes.NewBufferString("original installer"),
+		},
+		installerCRFile: {
+			Path: "kyma-installer-cr-cluster.yaml",
+			Content: []map[string]interface{}{
+				{"apiVersion": "installer.kyma-project.io/v1alpha1", "kind": "Installation", "metadata": map[interface{}]interface{}{"name": "kyma-installation"}},
+			},
+			Downloaded: bytes.NewBufferString("original installer CR"),
+		},
+	}
+
+	i := &Installation{Options: &Options{
+		ExportManifests:  dir,
+		RedactSecrets:    true,
+		InstallationName: "kyma-installation",
+		RegistryServer:   "reg",
+		RegistryUser:     "user",
+		RegistryPassword: "pwd",
+	}}
+	exportDir, err := i.exportManifests(files)
+	require.NoError(t, err)
+	require.Equal(t, dir, filepath.Dir(exportDir))
+
+	exported, err := filepath.Glob(filepath.Join(exportDir, "*.yaml"))
+	require.NoError(t, err)
+	var names []string
+	for _, f := range exported {
+		names = append(names, filepath.Base(f))
+	}
+	require.Equal(t, []string{
+		"001-secret-kyma-image-pull-secret.yaml",
+		"002-namespace-kyma-installer.yaml",
+		"003-secret-installer-secret.yaml",
+		"004-configmap-global-installer-config.yaml",
+		"005-secret-global-installer-config.yaml",
+		"006-installation-kyma-installation.yaml",
+		"007-installation-kyma-installation-patch.yaml",
+	}, names)
+
+	// secret values are redacted in the export only
+	for _, f := range []string{"001-secret-kyma-image-pull-secret.yaml", "003-secret-installer-secret.yaml"} {
+		data, err := ioutil.ReadFile(filepath.Join(exportDir, f))
+		require.NoError(t, err)
+		secret := map[string]interface{}{}
+		require.NoError(t, yaml.Unmarshal(data, &secret))
+		for _, v := range secret["data"].(map[interface{}]interface{}) {
+			require.Equal(t, redactedValue, v, f)
+		}
+	}
+	require.Equal(t, "c2VjcmV0", installerSecret["data"].(map[interface{}]interface{})["token"])
+
+	patch, err := ioutil.ReadFile(filepath.Join(exportDir, "007-installation-kyma-installation-patch.yaml"))
+	require.NoError(t, err)
+	require.Contains(t, string(patch), "action: install")
+
+	artifact, err := ioutil.ReadFile(filepath.Join(exportDir, exportReleaseDir, "kyma-installer-cluster.yaml"))
+	require.NoError(t, err)
+	require.Equal(t, "original installer", string(artifact))
+
+	// without redaction the values are kept
+	i.Options.RedactSecrets = false
+	exportDir, err = ioutil.TempDir(dir, "plain")
+	require.NoError(t, err)
+	i.Options.ExportManifests = exportDir
+	exportDir, err = i.exportManifests(files)
+	require.NoError(t, err)
+	data, err := ioutil.ReadFile(filepath.Join(exportDir, "003-secret-installer-secret.yaml"))
+	require.NoError(t, err)
+	require.Contains(t, string(data), "c2VjcmV0")
+}
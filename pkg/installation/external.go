@@ -0,0 +1,159 @@
+package installation
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	"github.com/kyma-incubator/hydroform/install/config"
+	installationSDK "github.com/kyma-incubator/hydroform/install/installation"
+	"github.com/kyma-incubator/hydroform/install/scheme"
+	pkgErrors "github.com/pkg/errors"
+	apiErrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// installerDeployment is the name of the Deployment of the Kyma Installer in the installer namespace
+const installerDeployment = "kyma-installer"
+
+// prepareExternalInstaller checks the Kyma Installer and the Installation CR deployed outside of the CLI, applies the overrides and activates the Installation CR.
+// Nothing is applied in dry-run mode, the changed overrides are only listed.
+func (i *Installation) prepareExternalInstaller(stages *stages) error {
+	var changes []configChange
+	if err := stages.run(stageValidation, nil, func() (err error) {
+		if err = i.Options.Validate(); err != nil {
+			return err
+		}
+		if err = i.checkExternalInstaller(); err != nil {
+			return err
+		}
+		changes, err = i.externalConfigChanges()
+		return err
+	}); err != nil {
+		return err
+	}
+	if stages.ok(stageValidation) {
+		i.currentStep.LogInfof("Using the externally managed Kyma Installer and the Installation CR '%s'", i.Options.InstallationName)
+		for _, c := range changes {
+			i.currentStep.LogInfof("  %s", c)
+		}
+	}
+	if i.Options.DryRun {
+		return stages.err()
+	}
+
+	if err := stages.run(stageTrigger, []string{stageLock, stagePrevInstallation, stageValidation}, func() error {
+		return i.activateExternalInstaller(changes)
+	}); err != nil {
+		return err
+	}
+	return stages.err()
+}
+
+// checkExternalInstaller ensures that the Kyma Installer Deployment and the Installation CR exist, as they are not applied with an external installer
+func (i *Installation) checkExternalInstaller() error {
+	_, err := i.K8s.Static().AppsV1().Deployments(i.installerNamespace()).Get(context.Background(), installerDeployment, metav1.GetOptions{})
+	if apiErrors.IsNotFound(err) {
+		return fmt.Errorf("the Deployment '%s/%s' of the Kyma Installer does not exist. Deploy the Kyma Installer before using --external-installer, or omit the flag to let the CLI deploy it", i.installerNamespace(), installerDeployment)
+	}
+	if err != nil {
+		return pkgErrors.Wrap(err, "unable to check the Deployment of the Kyma Installer")
+	}
+	missingCR := fmt.Errorf("no Installation CR exists in the namespace '%s'. Apply the Installation CR before using --external-installer, or omit the flag to let the CLI apply it", i.installationNamespace())
+	if i.Options.InstallationName == "" {
+		return missingCR
+	}
+	_, err = i.K8s.Dynamic().Resource(i.installationGVR()).Namespace(i.installationNamespace()).Get(context.Background(), i.Options.InstallationName, metav1.GetOptions{})
+	if apiErrors.IsNotFound(err) {
+		return missingCR
+	}
+	return pkgErrors.Wrap(err, "unable to check the Installation CR")
+}
+
+// externalConfigChanges returns the overrides of --config, --override and the flags which differ from the ones on the cluster.
+// The release configuration, the profile and the component flags are not used, as they are managed together with the external Kyma Installer.
+func (i *Installation) externalConfigChanges() ([]configChange, error) {
+	var sources []configSource
+	decoder, err := scheme.DefaultDecoder()
+	if err != nil {
+		return nil, fmt.Errorf("error: failed to create default decoder: %s", err.Error())
+	}
+	parse := func(name, content string) error {
+		if strings.TrimSpace(content) == "" {
+			return nil
+		}
+		configuration, err := config.YAMLToConfiguration(decoder, content)
+		if err != nil {
+			return fmt.Errorf("error: failed to parse configurations: %s", err.Error())
+		}
+		sources = append(sources, configSource{name: name, configuration: configuration})
+		return nil
+	}
+
+	configs, err := i.configDocuments()
+	if err != nil {
+		return nil, err
+	}
+	for _, c := range configs {
+		if err := parse(fmt.Sprintf("--config %s", c.path), c.overrides); err != nil {
+			return nil, err
+		}
+	}
+	// the override files given first take precedence, like for an installation with the Kyma Installer of the CLI
+	for n := len(i.Options.OverrideConfigs) - 1; n >= 0; n-- {
+		file := i.Options.OverrideConfigs[n]
+		data, err := ioutil.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("error: unable to open file: %s", err.Error())
+		}
+		if err := parse(fmt.Sprintf("--override %s", file), string(data)); err != nil {
+			return nil, err
+		}
+	}
+
+	flag := func(name string, entries ...installationSDK.ConfigEntry) {
+		sources = append(sources, configSource{name: name, configuration: installationSDK.Configuration{Configuration: entries}})
+	}
+	if i.Options.IsLocal && i.Options.LocalCluster != nil {
+		flag("local cluster IP", installationSDK.ConfigEntry{Key: "global.minikubeIP", Value: i.Options.LocalCluster.IP})
+	}
+	// the password is applied as a Secret, so that it is not listed with the changes
+	if i.Options.Password != "" {
+		flag("--password", installationSDK.ConfigEntry{Key: "global.adminPassword", Value: base64.StdEncoding.EncodeToString([]byte(i.Options.Password)), Secret: true})
+	}
+	if i.Options.Domain != "" && i.Options.Domain != defaultDomain {
+		flag("--domain",
+			installationSDK.ConfigEntry{Key: "global.domainName", Value: i.Options.Domain},
+			installationSDK.ConfigEntry{Key: "global.tlsCrt", Value: i.Options.TLSCert},
+			installationSDK.ConfigEntry{Key: "global.tlsKey", Value: i.Options.TLSKey},
+		)
+	}
+
+	configuration, _ := mergeConfigurations(sources)
+	applied, err := i.appliedOverrides()
+	if err != nil {
+		return nil, err
+	}
+	return configChanges(configuration, applied), nil
+}
+
+// activateExternalInstaller applies the changed overrides and labels the Installation CR, so that the Kyma Installer starts the installation
+func (i *Installation) activateExternalInstaller(changes []configChange) error {
+	for _, c := range changes {
+		if err := i.applyConfigChange(c); err != nil {
+			return err
+		}
+	}
+	patch, err := json.Marshal(activationPatch(i.metadata(), i.Options.InstallationName))
+	if err != nil {
+		return err
+	}
+	if _, err := i.K8s.Dynamic().Resource(i.installationGVR()).Namespace(i.installationNamespace()).Patch(context.Background(), i.Options.InstallationName, types.MergePatchType, patch, metav1.PatchOptions{}); err != nil {
+		return pkgErrors.Wrap(err, "unable to activate the Installation CR")
+	}
+	return i.waitForInstallerPod()
+}
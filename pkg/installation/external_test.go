@@ -0,0 +1,111 @@
+package installation
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	k8sMocks "github.com/kyma-project/cli/internal/kube/mocks"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	fakeDynamic "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func externalInstallationCR() *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "installer.kyma-project.io/v1alpha1",
+		"kind":       "Installation",
+		"metadata":   map[string]interface{}{"name": "kyma-installation", "namespace": "default"},
+	}}
+}
+
+func TestCheckExternalInstaller(t *testing.T) {
+	t.Parallel()
+	deployment := &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: installerDeployment, Namespace: installerNamespace()}}
+
+	kymaMock := &k8sMocks.KymaKube{}
+	kymaMock.On("Static").Return(fake.NewSimpleClientset())
+	i := &Installation{K8s: kymaMock, Options: &Options{ExternalInstaller: true, InstallationName: "kyma-installation"}}
+	require.EqualError(t, i.checkExternalInstaller(), "the Deployment 'kyma-installer/kyma-installer' of the Kyma Installer does not exist. Deploy the Kyma Installer before using --external-installer, or omit the flag to let the CLI deploy it")
+
+	// no Installation CR was discovered
+	kymaMock = &k8sMocks.KymaKube{}
+	kymaMock.On("Static").Return(fake.NewSimpleClientset(deployment))
+	i = &Installation{K8s: kymaMock, Options: &Options{ExternalInstaller: true}}
+	require.EqualError(t, i.checkExternalInstaller(), "no Installation CR exists in the namespace 'default'. Apply the Installation CR before using --external-installer, or omit the flag to let the CLI apply it")
+
+	// the configured Installation CR does not exist
+	kymaMock.On("Dynamic").Return(fakeDynamic.NewSimpleDynamicClient(runtime.NewScheme()))
+	i = &Installation{K8s: kymaMock, Options: &Options{ExternalInstaller: true, InstallationName: "kyma-installation"}}
+	require.Error(t, i.checkExternalInstaller())
+
+	kymaMock = &k8sMocks.KymaKube{}
+	kymaMock.On("Static").Return(fake.NewSimpleClientset(deployment))
+	kymaMock.On("Dynamic").Return(fakeDynamic.NewSimpleDynamicClient(runtime.NewScheme(), externalInstallationCR()))
+	i = &Installation{K8s: kymaMock, Options: &Options{ExternalInstaller: true, InstallationName: "kyma-installation"}}
+	require.NoError(t, i.checkExternalInstaller())
+}
+
+func TestActivateExternalInstaller(t *testing.T) {
+	t.Parallel()
+	dir, err := ioutil.TempDir("", "external-installer")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	overrides := filepath.Join(dir, "overrides.yaml")
+	require.NoError(t, ioutil.WriteFile(overrides, []byte(`apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: ory-overrides
+  namespace: kyma-installer
+  labels:
+    installer: overrides
+    component: ory
+data:
+  hydra.enabled: "true"
+`), 0600))
+
+	static := fake.NewSimpleClientset(&corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "ory-overrides", Namespace: installerNamespace(), Labels: map[string]string{overridesLabelKey: overridesLabelValue, componentOverridesKey: "ory"}},
+		Data:       map[string]string{"hydra.enabled": "false"},
+	})
+	dynamic := fakeDynamic.NewSimpleDynamicClient(runtime.NewScheme(), externalInstallationCR())
+	kymaMock := &k8sMocks.KymaKube{}
+	kymaMock.On("Static").Return(static)
+	kymaMock.On("Dynamic").Return(dynamic)
+	kymaMock.On("IsPodReadyByLabel", "kyma-installer", "name", "kyma-installer").Return(true, nil)
+
+	i := &Installation{K8s: kymaMock, Options: &Options{
+		ExternalInstaller: true,
+		InstallationName:  "kyma-installation",
+		OverrideConfigs:   []string{overrides},
+		Password:          "s3cr3t",
+	}}
+	changes, err := i.externalConfigChanges()
+	require.NoError(t, err)
+	var diff []string
+	for _, c := range changes {
+		diff = append(diff, c.String())
+	}
+	require.Equal(t, []string{
+		"+ global.adminPassword (secret)",
+		`~ ory:hydra.enabled: "false" -> "true"`,
+	}, diff, "the release configuration is not compared, only the given overrides and flags")
+
+	require.NoError(t, i.activateExternalInstaller(changes))
+	cm, err := static.CoreV1().ConfigMaps(installerNamespace()).Get(context.Background(), "ory-overrides", metav1.GetOptions{})
+	require.NoError(t, err)
+	require.Equal(t, "true", cm.Data["hydra.enabled"])
+	secret, err := static.CoreV1().Secrets(installerNamespace()).Get(context.Background(), "global-installer-config", metav1.GetOptions{})
+	require.NoError(t, err)
+	require.Equal(t, "czNjcjN0", string(secret.Data["global.adminPassword"]))
+	cr, err := dynamic.Resource(installationGVR()).Namespace(installationNamespace()).Get(context.Background(), "kyma-installation", metav1.GetOptions{})
+	require.NoError(t, err)
+	require.Equal(t, "install", cr.GetLabels()["action"], "the Installation CR must be activated")
+}
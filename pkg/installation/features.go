@@ -0,0 +1,255 @@
+package installation
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	installationSDK "github.com/kyma-incubator/hydroform/install/installation"
+	"github.com/kyma-project/kyma/components/kyma-operator/pkg/apis/installer/v1alpha1"
+	"github.com/pkg/errors"
+)
+
+// feature is an optional part of Kyma which can be enabled or disabled as a whole, without knowing its components
+type feature struct {
+	// components are installed in the given order
+	components []v1alpha1.KymaComponent
+	// requires holds the features which must be installed for this feature to work
+	requires []string
+	// override is the global override which tells the other components whether the feature is installed
+	override string
+}
+
+var features = map[string]feature{
+	"istio": {
+		components: []v1alpha1.KymaComponent{{Name: "istio", Namespace: "istio-system"}, {Name: "istio-kyma-patch", Namespace: "istio-system"}},
+		override:   "global.istio.enabled",
+	},
+	"monitoring": {
+		components: []v1alpha1.KymaComponent{{Name: "monitoring", Namespace: "kyma-system"}},
+		requires:   []string{"istio"},
+		override:   "global.monitoring.enabled",
+	},
+	"logging": {
+		components: []v1alpha1.KymaComponent{{Name: "logging", Namespace: "kyma-system"}},
+		override:   "global.logging.enabled",
+	},
+	"tracing": {
+		components: []v1alpha1.KymaComponent{{Name: "tracing", Namespace: "kyma-system"}},
+		requires:   []string{"istio"},
+		override:   "global.tracing.enabled",
+	},
+	"kiali": {
+		components: []v1alpha1.KymaComponent{{Name: "kiali", Namespace: "kyma-system"}},
+		requires:   []string{"istio", "monitoring"},
+		override:   "global.kiali.enabled",
+	},
+	"service-catalog": {
+		components: []v1alpha1.KymaComponent{{Name: "service-catalog", Namespace: "kyma-system"}, {Name: "service-catalog-addons", Namespace: "kyma-system"}, {Name: "helm-broker", Namespace: "kyma-system"}},
+		override:   "global.serviceCatalog.enabled",
+	},
+	"serverless": {
+		components: []v1alpha1.KymaComponent{{Name: "serverless", Namespace: "kyma-system"}},
+		requires:   []string{"istio"},
+		override:   "global.serverless.enabled",
+	},
+}
+
+// featureNames returns the sorted names of all features which can be toggled
+func featureNames() []string {
+	names := make([]string, 0, len(features))
+	for name := range features {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// validateFeatures ensures that only known features are toggled and that no feature is enabled and disabled at the same time
+func validateFeatures(enable, disable []string) error {
+	disabled := map[string]bool{}
+	for _, name := range disable {
+		if _, ok := features[name]; !ok {
+			return fmt.Errorf("unknown feature '%s' in --disable. Use one of: %s", name, strings.Join(featureNames(), ", "))
+		}
+		disabled[name] = true
+	}
+	for _, name := range enable {
+		if _, ok := features[name]; !ok {
+			return fmt.Errorf("unknown feature '%s' in --enable. Use one of: %s", name, strings.Join(featureNames(), ", "))
+		}
+		if disabled[name] {
+			return fmt.Errorf("the feature '%s' cannot be enabled and disabled at the same time", name)
+		}
+	}
+	return nil
+}
+
+// ApplyFeatures removes the components of the disabled features from the component list and adds the missing components of the enabled features.
+// Added components are inserted after the components of the features they require, so that the installation order is kept.
+// An error is returned if a feature in the resulting list requires a feature which is not installed.
+func ApplyFeatures(components []v1alpha1.KymaComponent, enable, disable []string) ([]v1alpha1.KymaComponent, error) {
+	if err := validateFeatures(enable, disable); err != nil {
+		return nil, err
+	}
+
+	removed := map[string]bool{}
+	for _, name := range disable {
+		for _, c := range features[name].components {
+			removed[c.Name] = true
+		}
+	}
+	result := make([]v1alpha1.KymaComponent, 0, len(components))
+	for _, c := range components {
+		if !removed[c.Name] {
+			result = append(result, c)
+		}
+	}
+
+	for _, name := range dependenciesFirst(enable) {
+		f := features[name]
+		for _, r := range f.requires {
+			if !featureInstalled(result, r) {
+				if contains(disable, r) {
+					return nil, fmt.Errorf("the feature '%s' requires '%s', which is disabled", name, r)
+				}
+				return nil, fmt.Errorf("the feature '%s' requires '%s', which is not in the component list. Add --enable %s", name, r, r)
+			}
+		}
+		for _, c := range f.components {
+			if componentIndex(result, c.Name) >= 0 {
+				continue
+			}
+			pos := len(result)
+			if len(f.requires) > 0 {
+				pos = 0
+				for _, r := range f.requires {
+					for _, rc := range features[r].components {
+						if n := componentIndex(result, rc.Name); n+1 > pos {
+							pos = n + 1
+						}
+					}
+				}
+			}
+			result = append(result[:pos], append([]v1alpha1.KymaComponent{c}, result[pos:]...)...)
+		}
+	}
+
+	// features which stay installed must not lose the features they require
+	for _, name := range disable {
+		for _, other := range featureNames() {
+			if contains(features[other].requires, name) && featureInstalled(result, other) {
+				return nil, fmt.Errorf("the feature '%s' requires '%s'. Add --disable %s to disable both", other, name, other)
+			}
+		}
+	}
+
+	return result, nil
+}
+
+// dependenciesFirst orders the features so that each feature comes after the features it requires
+func dependenciesFirst(names []string) []string {
+	var ordered []string
+	visited := map[string]bool{}
+	var visit func(name string)
+	visit = func(name string) {
+		if visited[name] {
+			return
+		}
+		visited[name] = true
+		for _, r := range features[name].requires {
+			if contains(names, r) {
+				visit(r)
+			}
+		}
+		ordered = append(ordered, name)
+	}
+	for _, name := range names {
+		visit(name)
+	}
+	return ordered
+}
+
+// featureOverrides returns the global overrides of the toggled features
+func featureOverrides(enable, disable []string) []installationSDK.ConfigEntry {
+	var entries []installationSDK.ConfigEntry
+	for _, name := range enable {
+		entries = append(entries, installationSDK.ConfigEntry{Key: features[name].override, Value: "true"})
+	}
+	for _, name := range disable {
+		entries = append(entries, installationSDK.ConfigEntry{Key: features[name].override, Value: "false"})
+	}
+	return entries
+}
+
+// insertFeatures applies the feature toggles to the component list of the Installation CR
+func insertFeatures(installationCRFile *File, enable, disable []string) error {
+	for _, config := range installationCRFile.Content {
+		if kind, ok := config["kind"]; !ok || kind != "Installation" {
+			continue
+		}
+		spec, ok := config["spec"].(map[interface{}]interface{})
+		if !ok {
+			break
+		}
+		entries, _ := spec["components"].([]interface{})
+
+		// the entries are kept as they are, so that fields like the release name and the source are not lost
+		byName := map[string]interface{}{}
+		var components []v1alpha1.KymaComponent
+		for _, e := range entries {
+			entry, ok := e.(map[interface{}]interface{})
+			if !ok {
+				continue
+			}
+			name, _ := entry["name"].(string)
+			namespace, _ := entry["namespace"].(string)
+			byName[name] = entry
+			components = append(components, v1alpha1.KymaComponent{Name: name, Namespace: namespace})
+		}
+
+		toggled, err := ApplyFeatures(components, enable, disable)
+		if err != nil {
+			return err
+		}
+		var result []interface{}
+		for _, c := range toggled {
+			if entry, ok := byName[c.Name]; ok {
+				result = append(result, entry)
+				continue
+			}
+			result = append(result, map[interface{}]interface{}{"name": c.Name, "namespace": c.Namespace})
+		}
+		spec["components"] = result
+		return nil
+	}
+	return errors.New("unable to set the components of the toggled features in the 'Installation' kind")
+}
+
+// featureInstalled checks if all components of the feature are in the component list
+func featureInstalled(components []v1alpha1.KymaComponent, name string) bool {
+	for _, c := range features[name].components {
+		if componentIndex(components, c.Name) < 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func componentIndex(components []v1alpha1.KymaComponent, name string) int {
+	for n, c := range components {
+		if c.Name == name {
+			return n
+		}
+	}
+	return -1
+}
+
+func contains(list []string, s string) bool {
+	for _, e := range list {
+		if e == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,133 @@
+package installation
+
+import (
+	"testing"
+
+	"github.com/kyma-project/kyma/components/kyma-operator/pkg/apis/installer/v1alpha1"
+	"github.com/stretchr/testify/require"
+)
+
+func componentNames(components []v1alpha1.KymaComponent) []string {
+	var names []string
+	for _, c := range components {
+		names = append(names, c.Name)
+	}
+	return names
+}
+
+func TestApplyFeatures(t *testing.T) {
+	t.Parallel()
+	base := []v1alpha1.KymaComponent{
+		{Name: "cluster-essentials", Namespace: "kyma-system"},
+		{Name: "istio", Namespace: "istio-system"},
+		{Name: "istio-kyma-patch", Namespace: "istio-system"},
+		{Name: "core", Namespace: "kyma-system"},
+		{Name: "logging", Namespace: "kyma-system"},
+		{Name: "console", Namespace: "kyma-system"},
+	}
+
+	cases := []struct {
+		name     string
+		enable   []string
+		disable  []string
+		expected []string
+		err      string
+	}{
+		{
+			name:     "No toggles",
+			expected: []string{"cluster-essentials", "istio", "istio-kyma-patch", "core", "logging", "console"},
+		},
+		{
+			name:     "Enable a feature after the features it requires",
+			enable:   []string{"kiali", "monitoring"},
+			expected: []string{"cluster-essentials", "istio", "istio-kyma-patch", "monitoring", "kiali", "core", "logging", "console"},
+		},
+		{
+			name:     "Enable a feature without requirements at the end",
+			enable:   []string{"service-catalog"},
+			expected: []string{"cluster-essentials", "istio", "istio-kyma-patch", "core", "logging", "console", "service-catalog", "service-catalog-addons", "helm-broker"},
+		},
+		{
+			name:     "Enabling an installed feature keeps the list",
+			enable:   []string{"logging"},
+			expected: []string{"cluster-essentials", "istio", "istio-kyma-patch", "core", "logging", "console"},
+		},
+		{
+			name:     "Disable a feature",
+			disable:  []string{"logging"},
+			expected: []string{"cluster-essentials", "istio", "istio-kyma-patch", "core", "console"},
+		},
+		{
+			name:    "Enable a feature requiring a disabled feature",
+			enable:  []string{"monitoring"},
+			disable: []string{"istio"},
+			err:     "the feature 'monitoring' requires 'istio', which is disabled",
+		},
+		{
+			name:   "Enable a feature requiring a missing feature",
+			enable: []string{"kiali"},
+			err:    "the feature 'kiali' requires 'monitoring', which is not in the component list. Add --enable monitoring",
+		},
+		{
+			name:    "Disable a feature required by an installed feature",
+			enable:  []string{"tracing"},
+			disable: []string{"logging", "istio"},
+			err:     "the feature 'tracing' requires 'istio', which is disabled",
+		},
+		{
+			name:    "Enable and disable the same feature",
+			enable:  []string{"logging"},
+			disable: []string{"logging"},
+			err:     "the feature 'logging' cannot be enabled and disabled at the same time",
+		},
+		{
+			name:   "Unknown feature",
+			enable: []string{"backup"},
+			err:    "unknown feature 'backup' in --enable. Use one of: istio, kiali, logging, monitoring, serverless, service-catalog, tracing",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			result, err := ApplyFeatures(base, tc.enable, tc.disable)
+			if tc.err != "" {
+				require.EqualError(t, err, tc.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, componentNames(result))
+		})
+	}
+
+	t.Run("Disable a feature required by a feature in the list", func(t *testing.T) {
+		components := append(base, v1alpha1.KymaComponent{Name: "monitoring", Namespace: "kyma-system"})
+		_, err := ApplyFeatures(components, nil, []string{"istio"})
+		require.EqualError(t, err, "the feature 'monitoring' requires 'istio'. Add --disable monitoring to disable both")
+	})
+}
+
+func TestInsertFeatures(t *testing.T) {
+	t.Parallel()
+	crFile := &File{Content: []map[string]interface{}{
+		{
+			"kind": "Installation",
+			"spec": map[interface{}]interface{}{
+				"components": []interface{}{
+					map[interface{}]interface{}{"name": "istio", "namespace": "istio-system"},
+					map[interface{}]interface{}{"name": "istio-kyma-patch", "namespace": "istio-system"},
+					map[interface{}]interface{}{"name": "logging", "namespace": "kyma-system", "release": "kyma-logging"},
+				},
+			},
+		},
+	}}
+
+	require.NoError(t, insertFeatures(crFile, []string{"monitoring"}, nil))
+	require.Equal(t, []interface{}{
+		map[interface{}]interface{}{"name": "istio", "namespace": "istio-system"},
+		map[interface{}]interface{}{"name": "istio-kyma-patch", "namespace": "istio-system"},
+		map[interface{}]interface{}{"name": "monitoring", "namespace": "kyma-system"},
+		map[interface{}]interface{}{"name": "logging", "namespace": "kyma-system", "release": "kyma-logging"},
+	}, crFile.Content[0]["spec"].(map[interface{}]interface{})["components"])
+
+	require.Error(t, insertFeatures(&File{}, []string{"monitoring"}, nil))
+}
@@ -1,17 +1,20 @@
 package installation
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"fmt"
-	"os"
-	"path/filepath"
+	"net"
 	"strings"
 	"time"
 
 	installationSDK "github.com/kyma-incubator/hydroform/install/installation"
-	"github.com/kyma-project/cli/cmd/kyma/version"
 	"github.com/kyma-project/cli/internal/kube"
+	"github.com/kyma-project/cli/internal/metadata"
+	"github.com/kyma-project/cli/internal/minikube"
+	"github.com/kyma-project/cli/internal/release"
+	kymaVersion "github.com/kyma-project/cli/internal/version"
 	"github.com/kyma-project/cli/pkg/docker"
 	"github.com/kyma-project/cli/pkg/step"
 	"github.com/kyma-project/kyma/components/kyma-operator/pkg/apis/installer/v1alpha1"
@@ -22,20 +25,22 @@ import (
 )
 
 const (
-	developmentBucket      = "kyma-development-artifacts"
-	releaseBucket          = "kyma-prow-artifacts"
-	releaseResourcePattern = "https://storage.googleapis.com/%s/%s/%s"
-	defaultDomain          = "kyma.local"
-	sourceMaster           = "master"
-	sourceLocal            = "local"
+	defaultDomain = "kyma.local"
+	sourceMaster  = "master"
+	sourceLocal   = "local"
 
 	installerFile       = "installer"
 	installerCRFile     = "installerCR"
 	installerConfigFile = "installerConfig"
 
+	defaultInstallationName = "kyma-installation"
+
 	errorCustomDomainCertMissing = "You specified --domain, also --tls-key and --tls-cert has to be specified"
 	errorCertIncomplete          = "To use a custom certificate --tls-key and --tls-cert must be specified together"
-	errorProfileNotSupported     = "You specified an invalid profile. It can take one of the following: 'evaluation' or 'production'"
+	errorProfileNotSupported     = "You specified an invalid profile. It can take one of the following: 'evaluation', 'full', 'minimal' or 'production'"
+
+	errorPullSecretConflict            = "You specified --set-image-pull-secret, the flags --registry-server, --registry-user and --registry-password cannot be used with it"
+	errorRegistryCredentialsIncomplete = "To create an image pull secret --registry-server, --registry-user and --registry-password must be specified together"
 )
 
 // ComponentsConfig is used to parse component list from the configuration
@@ -49,6 +54,40 @@ type Installation struct {
 	K8s         kube.KymaKube
 	Service     Service
 	currentStep step.Step
+	progress    *progress
+	// ctx is canceled if the CLI is interrupted while it holds the installation lock
+	ctx context.Context
+	// installerSelector holds the label selector of the Kyma Installer pods once it matched a pod
+	installerSelector string
+	// nipIODomain holds the nip.io domain once it is determined
+	nipIODomain string
+	// report publishes the progress if a status port or file is configured
+	report *progressReporter
+	// pollInterval overrides the time between two checks of the installation state
+	pollInterval time.Duration
+	// extractedSources holds the temporary directory of the local sources extracted from an archive
+	extractedSources string
+	// configDocs holds the --config files once they are read
+	configDocs []configDocument
+	// stepTimes collects the durations of the steps shown in the summary
+	stepTimes stepTimes
+	// createdResources holds the resources which did not exist before they were applied, in the order of their creation
+	createdResources []CreatedResource
+	// completedStages holds the stages completed by this and previous runs of the installation, by stage name
+	completedStages map[string]completedStage
+	// nodeChanges holds the labels and taints added to the nodes by this and previous runs of the installation
+	nodeChanges []NodeChange
+	// unreadyWorkloads holds the workloads which were not ready after the installation, and workloadsErr the reason if they could not be verified
+	unreadyWorkloads []string
+	workloadsErr     error
+	// cidrOverlaps describes the cluster networks overlapping with the routes of the local machine, if they were checked
+	cidrOverlaps []string
+	// localRoutes overrides how the routes of the local machine are read
+	localRoutes func() ([]*net.IPNet, error)
+	// freeDiskSpace overrides how the free disk space of the Docker daemon of Minikube is determined
+	freeDiskSpace func(path string) (uint64, error)
+	// minikubeSettings overrides how the settings of a Minikube cluster are read
+	minikubeSettings func() (minikube.Settings, []error)
 	// Factory contains the option to determine the interactivity of a Step.
 	// +optional
 	Factory step.Factory `json:"factory,omitempty"`
@@ -62,34 +101,42 @@ type File struct {
 	Path          string
 	Content       []map[string]interface{}
 	StringContent string
+	// Downloaded holds the unmodified content of a release artifact, if the file was downloaded.
+	Downloaded *bytes.Buffer
+	// raw holds the source of each document in Content, unmodified documents are applied as they are in the source.
+	raw []string
 }
 
-// Result contains the resulting details related to the installation.
-type Result struct {
-	// KymaVersion indicates the installed Kyma version.
-	KymaVersion string
-	// Host indicates the host address where Kyma is installed.
-	Host string
-	// Console holds the address of Kyma console.
-	Console string
-	// AdminEmail indicates the Email address of the Admin user which can be used to login Kyma.
-	AdminEmail string
-	// AdminPassword indicates the password of the Admin user which can be used to login Kyma.
-	AdminPassword string
-	// Warnings includes a set of any warnings from the installation.
-	Warnings []string
-	// Duration indicates the duration of the installation.
-	Duration time.Duration
+// installCtx returns the context of the installation, which is canceled if the CLI is interrupted while it holds the installation lock
+func (i *Installation) installCtx() context.Context {
+	if i.ctx == nil {
+		return context.Background()
+	}
+	return i.ctx
 }
 
 func (i *Installation) newStep(msg string) step.Step {
-	s := i.Factory.NewStep(msg)
+	s := withTiming(i.Factory.NewStep(msg), msg, &i.stepTimes)
 	i.currentStep = s
+	i.report.stage(msg)
 	return s
 }
 
 // InstallKyma triggers the installation of a Kyma cluster.
+// If a status port or file is configured, the progress is published until the installation returns.
 func (i *Installation) InstallKyma() (*Result, error) {
+	report, err := i.startProgressReport()
+	if err != nil {
+		return nil, err
+	}
+	i.report = report
+	defer i.cleanupLocalSources()
+	result, err := i.installKyma()
+	report.stop(err)
+	return result, err
+}
+
+func (i *Installation) installKyma() (*Result, error) {
 	// Start timer for the installation
 	installationTimer := time.Now()
 
@@ -98,65 +145,206 @@ func (i *Installation) InstallKyma() (*Result, error) {
 	}
 
 	s := i.newStep("Preparing installation")
+	stages := newStages(i.Options.ContinueOnError, s)
+	var files map[string]*File
+	if i.Options.Explain {
+		stages.explain(func(name string) []string { return i.describeStage(name, files) }, !i.Factory.NonInteractive)
+	}
+	// Making sure no other CLI instance changes the cluster at the same time
+	if !i.Options.DryRun {
+		var releaseLock func()
+		if err := stages.run(stageLock, nil, func() (err error) {
+			releaseLock, err = i.acquireLock()
+			return err
+		}); err != nil {
+			s.Failure()
+			return nil, err
+		}
+		if releaseLock != nil {
+			defer releaseLock()
+		}
+	}
+	// the stages completed by an interrupted run are recorded in the install-info ConfigMap, it is only read while holding the lock
+	i.loadCompletedStages()
+
 	// Checking existence of previous installation
-	prevInstallationState, kymaVersion, err := i.checkPrevInstallation()
-	if err != nil {
+	var prevInstallationState, kymaVersion string
+	if err := stages.run(stagePrevInstallation, nil, func() (err error) {
+		prevInstallationState, kymaVersion, err = i.checkPrevInstallation()
+		return err
+	}); err != nil {
 		s.Failure()
 		return nil, err
 	}
+	if i.Options.AttachOnly {
+		if err := i.checkTriggered(prevInstallationState); err != nil {
+			s.Failure()
+			return nil, err
+		}
+	}
 	logInfo := i.getInstallationLogInfo(prevInstallationState, kymaVersion)
 
-	if prevInstallationState == installationSDK.NoInstallationState || prevInstallationState == "" {
+	var manifestsDir string
+
+	newInstallation := prevInstallationState == installationSDK.NoInstallationState || prevInstallationState == ""
+	if i.Options.ExternalInstaller && newInstallation {
+		// the Kyma Installer and the Installation CR were deployed outside of the CLI, only the overrides are applied
+		if err := i.prepareExternalInstaller(stages); err != nil {
+			s.Failure()
+			return nil, err
+		}
+		if i.Options.DryRun {
+			s.Successf("Preparations done, nothing applied in dry-run mode")
+			return nil, nil
+		}
+		if stages.userSkipped(stageTrigger) {
+			s.Successf("Preparations done, the installation was skipped")
+			return nil, nil
+		}
+		if err := i.checkActivation(); err != nil {
+			s.Failure()
+			return nil, err
+		}
+		s.Successf("Preparations done, the externally managed Kyma Installer was activated")
+	} else if newInstallation {
+		// the stages of a new installation are recorded until it succeeds, so that a later run knows how far this one got
+		stages.completed = func(name string) { i.completeStage(name, "") }
+
 		// Validating configurations
-		if err := i.validateConfigurations(); err != nil {
+		if err := stages.run(stageValidation, nil, func() error {
+			if err := i.validateConfigurations(); err != nil {
+				return err
+			}
+			if i.Options.CIDRCheck {
+				i.checkCIDRs()
+			}
+			// the software of a previous Kyma installation is expected when upgrading, so only installations are checked
+			return i.checkConflicts()
+		}); err != nil {
 			s.Failure()
 			return nil, err
 		}
 
 		// Checking installation source
-		i.checkInstallationSource()
+		if stages.ok(stageValidation) {
+			i.checkInstallationSource()
+		}
 
 		// Loading installation files
-		files, err := i.prepareFiles()
-		if err != nil {
+		if err := stages.run(stagePreparation, []string{stageValidation}, func() (err error) {
+			files, err = i.prepareFiles()
+			return err
+		}); err != nil {
 			s.Failure()
 			return nil, err
 		}
 
-		// Requesting Kyma Installer to install Kyma
-		if err := i.triggerInstallation(files); err != nil {
+		// Saving a copy of everything applied
+		if i.Options.ExportManifests != "" {
+			if err := stages.run(stageExport, []string{stagePreparation}, func() (err error) {
+				manifestsDir, err = i.exportManifests(files)
+				return err
+			}); err != nil {
+				s.Failure()
+				return nil, err
+			}
+			if stages.ok(stageExport) {
+				s.LogInfof("Manifests exported to '%s'", manifestsDir)
+			}
+		}
+
+		if i.Options.DryRun {
+			if err := stages.err(); err != nil {
+				s.Failure()
+				return nil, err
+			}
+			if err := i.labelNodes(); err != nil {
+				s.Failure()
+				return nil, err
+			}
+			s.Successf("Preparations done, nothing applied in dry-run mode")
+			return nil, nil
+		}
+
+		// Requesting Kyma Installer to install Kyma, only if all preparations succeeded
+		if err := stages.run(stageTrigger, []string{stageLock, stagePrevInstallation, stagePreparation, stageExport}, func() error {
+			if i.installCtx().Err() != nil {
+				return errors.New("Interrupted, the installation was not triggered")
+			}
+			return i.triggerInstallation(files)
+		}); err != nil {
+			s.Failure()
+			i.cleanupAfterFailure()
+			return nil, err
+		}
+		if err := stages.err(); err != nil {
+			s.Failure()
+			return nil, err
+		}
+		if stages.userSkipped(stageTrigger) {
+			s.Successf("Preparations done, the installation was skipped")
+			return nil, nil
+		}
+		if err := i.checkActivation(); err != nil {
 			s.Failure()
 			return nil, err
 		}
 		s.Successf("Preparations done")
 
+		// Pulling the component images while the Kyma Installer initializes
+		if i.Options.PrePullImages {
+			i.prePullImages()
+		}
+
 	} else {
+		// with --fail-fast=false, the stages which failed so far are only reported here
+		if err := stages.err(); err != nil {
+			s.Failure()
+			return nil, err
+		}
 		s.Successf(logInfo)
+		if i.Options.DryRun {
+			return nil, nil
+		}
 	}
 
 	if prevInstallationState != "Installed" && !i.Options.NoWait {
 		if prevInstallationState == installationSDK.NoInstallationState || prevInstallationState == "" {
-			i.newStep("Waiting for installation to start")
+			i.newStep("Installing Kyma")
 		} else {
 			i.newStep("Re-attaching installation status")
 		}
 		if err := i.waitForInstaller(); err != nil {
+			i.cleanupAfterFailure()
 			return nil, err
 		}
+		// the resources belong to the installed Kyma from now on
+		i.forgetCreatedResources()
 	}
 
 	duration := time.Since(installationTimer)
 
+	if !i.Options.NoWait && !i.Options.SkipPodVerification {
+		i.unreadyWorkloads, i.workloadsErr = i.verifyWorkloads()
+	}
+
 	result, err := i.buildResult(duration)
 	if err != nil {
 		return nil, err
 	}
+	if result != nil {
+		result.ManifestsDir = manifestsDir
+	}
 
 	return result, nil
 }
 
 func (i *Installation) checkPrevInstallation() (string, string, error) {
-	prevInstallationState, err := i.Service.CheckInstallationState(i.K8s.RestConfig())
+	if err := i.discoverInstallationName(); err != nil {
+		return "", "", err
+	}
+
+	prevInstallationState, err := i.Service.CheckInstallationState(i.K8s.RestConfig(), i.Options.InstallationName)
 	if err != nil {
 		installErr := installationSDK.InstallationError{}
 		if errors.As(err, &installErr) {
@@ -166,15 +354,134 @@ func (i *Installation) checkPrevInstallation() (string, string, error) {
 		}
 	}
 
-	var kymaVersion string
+	var clusterVersion string
 	if prevInstallationState.State != installationSDK.NoInstallationState && prevInstallationState.State != "" {
-		kymaVersion, err = version.KymaVersion(i.K8s)
+		clusterVersion, err = kymaVersion.KymaVersion(i.K8s.Static())
 		if err != nil {
 			return "", "", err
 		}
 	}
 
-	return prevInstallationState.State, kymaVersion, nil
+	return prevInstallationState.State, clusterVersion, nil
+}
+
+// checkTriggered ensures that a previous run triggered the installation and that the Kyma Installer is running, before the installation is waited for
+func (i *Installation) checkTriggered(prevInstallationState string) error {
+	if prevInstallationState == installationSDK.NoInstallationState || prevInstallationState == "" {
+		return errors.New("no Kyma installation was triggered on the cluster. Run \"kyma install installer\" first")
+	}
+	if prevInstallationState == "Installed" {
+		return nil
+	}
+	labelName, labelValue := kube.SplitSelector(i.installerLabel())
+	ready, err := i.K8s.IsPodReadyByLabel(i.installerNamespace(), labelName, labelValue)
+	if err != nil {
+		return pkgErrors.Wrap(err, "unable to check the Kyma Installer pod")
+	}
+	if !ready {
+		return fmt.Errorf("the Kyma Installer pod is not ready. Check it with \"kubectl -n %s get pods\", or run \"kyma install installer\" again", i.installerNamespace())
+	}
+	return nil
+}
+
+// discoverInstallationName looks up the Installation CR on the cluster, unless its name was explicitly configured.
+// If no Installation CR exists yet, the name is taken from the Installation CR file later on.
+func (i *Installation) discoverInstallationName() error {
+	if i.Options.InstallationName != "" {
+		return nil
+	}
+
+	name, err := findInstallationName(i.K8s, i.metadata())
+	if err != nil {
+		return err
+	}
+	i.Options.InstallationName = name
+	return nil
+}
+
+// FindInstallationName returns the name of the Kyma Installation CR on the cluster, or an empty string if there is none yet.
+// If several Installation CRs exist, an error listing them is returned.
+func FindInstallationName(k8s kube.KymaKube) (string, error) {
+	return findInstallationName(k8s, clusterMetadata(k8s.Static()))
+}
+
+// findInstallationName looks up the Installation CR in the namespace of the metadata
+func findInstallationName(k8s kube.KymaKube, m metadata.Metadata) (string, error) {
+	list, err := k8s.Dynamic().Resource(m.InstallationGVR()).Namespace(m.InstallationNamespace).List(context.Background(), metav1.ListOptions{})
+	if err != nil {
+		// the Installation CRD does not exist before the first installation
+		if apiErrors.IsNotFound(err) {
+			return "", nil
+		}
+		// without the Installation CR, an existing installation could be overwritten, so a missing permission is fatal
+		if apiErrors.IsForbidden(err) {
+			return "", forbiddenError(err, fmt.Sprintf("list the Installation CRs in the namespace '%s'", m.InstallationNamespace))
+		}
+		return "", pkgErrors.Wrap(err, "Failed to look up the Kyma Installation CR")
+	}
+
+	switch len(list.Items) {
+	case 0:
+		return "", nil
+	case 1:
+		return list.Items[0].GetName(), nil
+	default:
+		var names []string
+		for _, item := range list.Items {
+			names = append(names, item.GetName())
+		}
+		return "", fmt.Errorf("Found multiple Kyma Installation CRs: %s. Use --installation-name to select one of them", strings.Join(names, ", "))
+	}
+}
+
+// logComponentErrors prints the errors of the failing components reported in the Installation CR.
+// If the status cannot be read, the command to fetch the errors manually is printed instead.
+func (i *Installation) logComponentErrors() {
+	componentErrors, err := componentErrors(i.K8s, i.metadata(), i.installationName())
+	if err != nil || len(componentErrors) == 0 {
+		i.currentStep.LogInfof("To fetch the error logs from the installer, run: kubectl get installation %s -o go-template --template='{{- range .status.errorLog }}{{printf \"%%s:\\n %%s\\n\" .component .log}}{{- end}}'", i.installationName())
+		return
+	}
+	i.currentStep.LogError(FormatComponentErrors(componentErrors))
+}
+
+// waitForInstallerPod waits until the Kyma Installer pod is running. The wait is skipped if the pod is already running and ready,
+// for example because the Kyma Installer of a previous run was applied unchanged.
+// If the cluster rejects the pod, the reason is returned with a hint how to allow it.
+func (i *Installation) waitForInstallerPod() error {
+	labelName, labelValue := kube.SplitSelector(i.installerLabel())
+	if ready, err := i.K8s.IsPodReadyByLabel(i.installerNamespace(), labelName, labelValue); err == nil && ready {
+		return nil
+	}
+	err := i.K8s.WaitPodStatusByLabel(i.installerNamespace(), labelName, labelValue, corev1.PodRunning)
+	failure := &kube.PodCreationError{}
+	if errors.As(err, &failure) {
+		if failure.Forbidden() && !i.Options.CreatePSP {
+			return fmt.Errorf("the Kyma Installer pod cannot be created: %s. If the cluster uses PodSecurityPolicies, use --create-psp to allow the Kyma Installer pod", err)
+		}
+		return fmt.Errorf("the Kyma Installer pod cannot be created: %s", err)
+	}
+	return err
+}
+
+// FailedComponents returns the component errors reported in the Installation CR, e.g. to report them after the installation failed.
+// Errors reading the CR are ignored, as the installation error is reported anyway.
+func (i *Installation) FailedComponents() []ComponentError {
+	componentErrors, _ := componentErrors(i.K8s, i.metadata(), i.installationName())
+	return componentErrors
+}
+
+// StepDurations returns the durations of the steps which finished so far.
+func (i *Installation) StepDurations() []StepDuration {
+	return i.stepTimes.list()
+}
+
+// installationName returns the name of the Kyma Installation CR
+func (i *Installation) installationName() string {
+	if i.Options.InstallationName != "" {
+		return i.Options.InstallationName
+	}
+	return defaultInstallationName
 }
 
 func (i *Installation) getInstallationLogInfo(prevInstallationState string, kymaVersion string) string {
@@ -193,26 +500,37 @@ func (i *Installation) getInstallationLogInfo(prevInstallationState string, kyma
 }
 
 func (i *Installation) validateConfigurations() error {
+	if err := i.Options.Validate(); err != nil {
+		return err
+	}
+	if i.Options.LocalSrcPath != "" && !strings.EqualFold(i.Options.Source, sourceLocal) && i.currentStep != nil {
+		i.currentStep.LogErrorf("Warning: --src-path is ignored, as it is only used with --source=local")
+	}
+	if i.Options.ReleaseArtifacts != "" && strings.EqualFold(i.Options.Source, sourceLocal) && i.currentStep != nil {
+		i.currentStep.LogErrorf("Warning: --release-artifacts is ignored, as the installation files are read from the local sources")
+	}
+
+	var configVersion string
+
 	switch {
 	//Install from local sources
 	case strings.EqualFold(i.Options.Source, sourceLocal):
 		i.Options.fromLocalSources = true
 		if i.Options.LocalSrcPath == "" {
-			goPath := os.Getenv("GOPATH")
-			if goPath == "" {
-				return fmt.Errorf("no 'src-path' configured and no applicable default found. Check if you exported a GOPATH")
+			srcPath, err := defaultSourcePath()
+			if err != nil {
+				return err
 			}
-			i.Options.LocalSrcPath = filepath.Join(goPath, "src", "github.com", "kyma-project", "kyma")
+			i.Options.LocalSrcPath = srcPath
 		}
-		if _, err := os.Stat(i.Options.LocalSrcPath); err != nil {
-			return fmt.Errorf("configured 'src-path=%s' does not exist. Check if you configured a valid path", i.Options.LocalSrcPath)
+		srcPath := i.Options.LocalSrcPath
+		if isSourceArchive(srcPath) && i.extractedSources == "" {
+			if err := i.extractLocalSources(); err != nil {
+				return err
+			}
 		}
-		if _, err := os.Stat(filepath.Join(i.Options.LocalSrcPath, "installation", "resources")); err != nil {
-			return fmt.Errorf("configured 'src-path=%s' does not seem to point to a Kyma repository. Check if your repository contains the 'installation/resources' folder", i.Options.LocalSrcPath)
-		}
-
-		if !i.Options.IsLocal && i.Options.CustomImage == "" {
-			return pkgErrors.New("You must specify --custom-image to install Kyma from local sources to a remote cluster.")
+		if err := checkSrcPath(i.Options.LocalSrcPath, srcPath); err != nil {
+			return err
 		}
 
 	//Install the master version
@@ -222,26 +540,22 @@ func (i *Installation) validateConfigurations() error {
 			return pkgErrors.Wrap(err, "unable to get master version of kyma")
 		}
 		i.Options.releaseVersion = fmt.Sprintf("master-%s", masterHash)
-		i.Options.configVersion = fmt.Sprintf("master-%s", masterHash)
-		i.Options.bucket = developmentBucket
+		configVersion = i.Options.releaseVersion
 
 	//Install the specific commit hash (e.g. 34edf09a)
 	case isHex(i.Options.Source):
 		i.Options.releaseVersion = fmt.Sprintf("master-%s", i.Options.Source[:8])
-		i.Options.configVersion = fmt.Sprintf("master-%s", i.Options.Source[:8])
-		i.Options.bucket = developmentBucket
+		configVersion = i.Options.releaseVersion
 
 	//Install the specific version from release (ex: 1.15.1)
 	case isSemVer(i.Options.Source):
 		i.Options.releaseVersion = i.Options.Source
-		i.Options.configVersion = i.Options.Source
-		i.Options.bucket = releaseBucket
+		configVersion = i.Options.Source
 
 	//Install the specific pull request (e.g. PR-9486)
 	case strings.HasPrefix(i.Options.Source, "PR-"):
 		i.Options.releaseVersion = i.Options.Source
-		i.Options.configVersion = i.Options.Source
-		i.Options.bucket = developmentBucket
+		configVersion = i.Options.Source
 
 	//Install the kyma with the specific installer image (docker image URL)
 	case isDockerImage(i.Options.Source):
@@ -250,26 +564,40 @@ func (i *Installation) validateConfigurations() error {
 			return pkgErrors.Wrap(err, "unable to get master version of kyma")
 		}
 		i.Options.remoteImage = i.Options.Source
-		i.Options.configVersion = fmt.Sprintf("master-%s", masterHash)
-		i.Options.bucket = developmentBucket
+		configVersion = fmt.Sprintf("master-%s", masterHash)
 	default:
-		return fmt.Errorf("failed to parse the source flag. It can take one of the following: 'local', 'master', release version (e.g. 1.4.1), commit hash (e.g. 34edf09a) or installer image")
+		return pkgErrors.New(errorSourceInvalid)
 	}
-
-	//If custom domain name is provided, also certificates have to be provided
-	if i.Options.Domain != defaultDomain && i.Options.Domain != "" && !i.certificateProvided() {
-		return pkgErrors.New(errorCustomDomainCertMissing)
+	if !i.Options.fromLocalSources {
+		if err := i.resolveReleaseArtifacts(configVersion); err != nil {
+			return err
+		}
 	}
 
-	//Ensure that tls-key or tls-cert are always specified together
-	if (i.Options.TLSKey != "" || i.Options.TLSCert != "") && !i.certificateProvided() {
-		return pkgErrors.New(errorCertIncomplete)
-	}
+	i.warnUnmappedAliases()
 
-	if i.Options.Profile != "" && !(i.Options.Profile == "evaluation" || i.Options.Profile == "production") {
-		return pkgErrors.New(errorProfileNotSupported)
+	if err := i.CheckClusterRequirements(context.Background()); err != nil {
+		return err
 	}
+	if err := i.checkMinikubeRequirements(); err != nil {
+		return err
+	}
+
+	return i.checkCLICompatibility(i.Options.Source)
+}
 
+// checkCLICompatibility fails for Kyma versions which are not supported by the CLI version, if forced only a warning is logged
+func (i *Installation) checkCLICompatibility(v string) error {
+	err := kymaVersion.CheckCompatibility(v)
+	if err == nil {
+		return nil
+	}
+	if !i.Options.Force {
+		return fmt.Errorf("%s. Use --force to continue anyway", err)
+	}
+	if i.currentStep != nil {
+		i.currentStep.LogErrorf("Warning: %s", err)
+	}
 	return nil
 }
 
@@ -282,8 +610,11 @@ func (i *Installation) checkInstallationSource() {
 	if i.Options.fromLocalSources {
 		i.currentStep.LogInfof("Installing Kyma from local path: '%s'", i.Options.LocalSrcPath)
 	} else {
-		if i.Options.releaseVersion != i.Options.configVersion {
-			i.currentStep.LogInfof("Using the installation configuration from '%s'", i.Options.configVersion)
+		if i.Options.releaseVersion != i.Options.release.Version {
+			i.currentStep.LogInfof("Using the installation configuration from '%s'", i.Options.release.Version)
+		}
+		if i.Options.ReleaseArtifacts != "" {
+			i.currentStep.LogInfof("Reading the release artifacts from '%s'", i.Options.release.Location())
 		}
 		if i.Options.remoteImage != "" {
 			i.currentStep.LogInfof("Installing Kyma with installer image '%s' ", i.Options.remoteImage)
@@ -311,9 +642,10 @@ func (i *Installation) prepareFiles() (map[string]*File, error) {
 				return nil, err
 			}
 
-			err = i.Docker.BuildKymaInstaller(i.Options.LocalSrcPath, imageName)
-			if err != nil {
-				return nil, err
+			if !i.Options.DryRun {
+				if err := i.buildInstallerImage(imageName, false); err != nil {
+					return nil, err
+				}
 			}
 			//In case of remote cluster installation from local sources, build installer image using default Docker client and push the image.
 		} else {
@@ -321,14 +653,10 @@ func (i *Installation) prepareFiles() (map[string]*File, error) {
 			if err != nil {
 				return nil, err
 			}
-			err = i.Docker.BuildKymaInstaller(i.Options.LocalSrcPath, i.Options.CustomImage)
-			if err != nil {
-				return nil, err
-			}
-
-			err = i.Docker.PushKymaInstaller(i.Options.CustomImage, i.currentStep)
-			if err != nil {
-				return nil, err
+			if !i.Options.DryRun {
+				if err := i.buildInstallerImage(i.Options.CustomImage, true); err != nil {
+					return nil, err
+				}
 			}
 
 			err = replaceInstallerImage(files[installerFile], i.Options.CustomImage)
@@ -345,152 +673,179 @@ func (i *Installation) prepareFiles() (map[string]*File, error) {
 		}
 	}
 
-	if i.Options.Profile != "" {
-		err = insertProfile(files[installerCRFile], i.Options.Profile)
+	if installerProfile := profiles[i.Options.Profile].InstallerProfile; installerProfile != "" {
+		err = insertProfile(files[installerCRFile], installerProfile)
 		if err != nil {
 			return nil, err
 		}
 	}
 
-	return files, nil
-}
+	// an explicit component list replaces the one of the Installation CR, the toggles are applied to it when it is loaded
+	if (len(i.Options.EnableFeatures) > 0 || len(i.Options.DisableFeatures) > 0) && i.componentsSource() == "" {
+		err = insertFeatures(files[installerCRFile], i.Options.EnableFeatures, i.Options.DisableFeatures)
+		if err != nil {
+			return nil, err
+		}
+	}
 
-func (i *Installation) triggerInstallation(files map[string]*File) error {
-	var err error
-	files, err = loadStringContent(files)
-	if err != nil {
-		return fmt.Errorf("Failed to load installation files: %s", err.Error())
+	if i.Options.InstallationName != "" {
+		err = replaceInstallationName(files[installerCRFile], i.Options.InstallationName)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		i.Options.InstallationName = getInstallationName(files[installerCRFile])
 	}
 
-	installerFileContent := files[installerFile].StringContent
-	installerCRFileContent := files[installerCRFile].StringContent
-	configuration, err := i.loadConfigurations(files)
-	if err != nil {
-		return pkgErrors.Wrap(err, "unable to load the configurations")
+	if i.imagePullSecretConfigured() {
+		err = insertImagePullSecret(files[installerFile], imagePullSecretName)
+		if err != nil {
+			return nil, err
+		}
 	}
 
-	err = i.Service.TriggerInstallation(installerFileContent, installerCRFileContent, configuration)
-	if err != nil {
-		return fmt.Errorf("Failed to start installation: %s", err.Error())
+	if i.extraMetadataConfigured() {
+		insertExtraMetadata(files[installerFile], i.Options.ExtraLabels, i.Options.ExtraAnnotations)
+		insertExtraMetadata(files[installerCRFile], i.Options.ExtraLabels, i.Options.ExtraAnnotations)
 	}
 
-	return i.K8s.WaitPodStatusByLabel("kyma-installer", "name", "kyma-installer", corev1.PodRunning)
-}
+	if i.schedulingConfigured() {
+		err = insertScheduling(files[installerFile], i.Options.InstallerNodeSelector, i.Options.InstallerTolerations, i.Options.InstallerPriorityClass)
+		if err != nil {
+			return nil, err
+		}
+		i.currentStep.LogInfof("Scheduling the Kyma Installer with node selector %v, tolerations %v and priority class '%s'",
+			i.Options.InstallerNodeSelector, i.Options.InstallerTolerations, i.Options.InstallerPriorityClass)
+	}
 
-func (i *Installation) waitForInstaller() error {
-	currentDesc := ""
-	var errorOccured bool
-	var timeout <-chan time.Time
-	if i.Options.Timeout > 0 {
-		timeout = time.After(i.Options.Timeout)
-	}
-
-	for {
-		select {
-		case <-timeout:
-			i.currentStep.Failure()
-			if _, err := i.Service.CheckInstallationState(i.K8s.RestConfig()); err != nil {
-				installationError := installationSDK.InstallationError{}
-				if ok := errors.As(err, &installationError); ok {
-					i.currentStep.LogErrorf("Installation error occurred while installing Kyma: %s. Details: %s", installationError.Error(), installationError.Details())
-				}
-			}
-			return errors.New("Timeout reached while waiting for installation to complete")
-		default:
-			installationState, err := i.Service.CheckInstallationState(i.K8s.RestConfig())
-			if err != nil {
-				if !errorOccured {
-					errorOccured = true
-					installErr := installationSDK.InstallationError{}
-					if errors.As(err, &installErr) {
-						i.currentStep.LogErrorf("%s, which may be OK. Will retry later...", installErr.Error())
-						i.currentStep.LogInfo("To fetch the error logs from the installer, run: kubectl get installation kyma-installation -o go-template --template='{{- range .status.errorLog }}{{printf \"%s:\\n %s\\n\" .component .log}}{{- end}}'")
-						i.currentStep.LogInfo("To fetch the application logs from the installer, run: kubectl logs -n kyma-installer -l name=kyma-installer")
-					} else {
-						i.currentStep.LogErrorf("Failed to get installation state, which may be OK. Will retry later...\nError: %s", err)
-					}
-				}
-				time.Sleep(10 * time.Second)
-				continue
+	if i.installerResourcesConfigured() {
+		belowMinimum, err := insertInstallerResources(files[installerFile], i.installerResources())
+		if err != nil {
+			return nil, err
+		}
+		var set []string
+		for _, r := range i.installerResources() {
+			if r.value != "" {
+				set = append(set, fmt.Sprintf("%s.%s=%s", r.field, r.resource, r.value))
 			}
+		}
+		i.currentStep.LogInfof("Setting the resources of the Kyma Installer: %s", strings.Join(set, ", "))
+		if len(belowMinimum) > 0 {
+			i.currentStep.LogErrorf("Warning: the Kyma Installer might be evicted or fail with %s", strings.Join(belowMinimum, ", "))
+		}
+	}
 
-			switch installationState.State {
-			case "Installed":
-				i.currentStep.Success()
-				return nil
-
-			case "InProgress":
-				errorOccured = false
-				// only do something if the description has changed
-				if installationState.Description != currentDesc {
-					i.currentStep.Success()
-					i.currentStep = i.newStep(installationState.Description)
-					currentDesc = installationState.Description
-				}
-
-			case "":
-				i.currentStep.LogInfo("Failed to get the installation status. Will retry later...")
-
-			default:
-				i.currentStep.Failure()
-				return fmt.Errorf("unexpected status: %s", installationState.State)
-			}
-			time.Sleep(10 * time.Second)
+	if i.Options.InstallerLogLevel != "" {
+		if err := insertInstallerLogLevel(files[installerFile], i.Options.InstallerLogLevel); err != nil {
+			return nil, err
 		}
 	}
-}
+	if i.Options.DryRun {
+		level := installerLogLevel(files[installerFile])
+		if level == "" {
+			level = "default of the Kyma Installer"
+		}
+		i.currentStep.LogInfof("Log level of the Kyma Installer: %s", level)
+	} else if i.Options.InstallerLogLevel != "" {
+		i.currentStep.LogInfof("Setting the log level of the Kyma Installer to '%s'", i.Options.InstallerLogLevel)
+	}
 
-func (i *Installation) buildResult(duration time.Duration) (*Result, error) {
-	// In case that noWait flag is set, check that Kyma was actually installed before building the Result
-	if i.Options.NoWait {
-		installationState, err := i.Service.CheckInstallationState(i.K8s.RestConfig())
+	if i.Options.CreatePSP {
+		account, err := insertInstallerPSP(files[installerFile], i.installerNamespace())
 		if err != nil {
 			return nil, err
 		}
-		if installationState.State != "Installed" {
-			return nil, nil
+		i.currentStep.LogInfof("Creating the PodSecurityPolicy '%s' for the service account '%s' of the Kyma Installer", installerPSPName, account)
+	}
+
+	return files, nil
+}
+
+// buildInstallerImage builds the Kyma Installer image from the local sources, and pushes it for remote clusters.
+// The build is skipped if a previous installation attempt built the image from the same sources.
+func (i *Installation) buildInstallerImage(imageName string, push bool) error {
+	fingerprint, err := i.installerImageFingerprint(imageName)
+	if err != nil {
+		i.currentStep.LogErrorf("Warning: unable to identify the state of the local sources, the image is built in any case: %s", err)
+	} else if i.installerImageBuilt(imageName, fingerprint) {
+		i.currentStep.LogInfof("Skipping the build of the Kyma Installer image '%s', as a previous installation attempt built it from the same sources", imageName)
+		return nil
+	}
+
+	if !push {
+		if err := i.checkDockerDiskSpace(); err != nil {
+			return err
 		}
 	}
+	if err := i.Docker.BuildKymaInstaller(i.Options.LocalSrcPath, imageName, i.Options.DockerTimeout, i.currentStep); err != nil {
+		return err
+	}
+	if push {
+		if err := i.Docker.PushKymaInstaller(imageName, i.currentStep); err != nil {
+			return err
+		}
+	}
+	if fingerprint != "" {
+		i.completeStage(stageInstallerImage, fingerprint)
+	}
+	return nil
+}
 
-	v, err := version.KymaVersion(i.K8s)
+func (i *Installation) triggerInstallation(files map[string]*File) error {
+	// the Kyma Installer is applied in the order of the documents, so dependencies must come first
+	sortDocuments(files[installerFile])
+	if err := i.applyImagePullSecrets(); err != nil {
+		return err
+	}
+	// the resources are recorded before anything is applied, so that only the resources created by this run are cleaned up
+	i.recordCreatedResources(files[installerFile], files[installerCRFile])
+	if err := i.labelNodes(); err != nil {
+		return err
+	}
+	// the custom resources are taken out before the CRDs are reconciled, which removes the CRDs already on the cluster from the file
+	deferred, err := deferCustomResources(files[installerFile])
 	if err != nil {
-		return nil, err
+		return err
+	}
+	if err := i.reconcileCRDs(files[installerFile]); err != nil {
+		return err
+	}
+	files, err = loadStringContent(files)
+	if err != nil {
+		return fmt.Errorf("Failed to load installation files: %s", err.Error())
 	}
 
-	adm, err := i.K8s.Static().CoreV1().Secrets("kyma-system").Get(context.Background(), "admin-user", metav1.GetOptions{})
+	installerFileContent := files[installerFile].StringContent
+	installerCRFileContent := files[installerCRFile].StringContent
+	configuration, err := i.loadConfigurations(files)
 	if err != nil {
-		return nil, err
+		return pkgErrors.Wrap(err, "unable to load the configurations")
 	}
 
-	var consoleURL string
-	vs, err := i.K8s.Istio().NetworkingV1alpha3().VirtualServices("kyma-system").Get(context.Background(), "console-web", metav1.GetOptions{})
-	switch {
-	case apiErrors.IsNotFound(err):
-		consoleURL = "not installed"
-	case err != nil:
-		return nil, err
-	case vs != nil && len(vs.Spec.Hosts) > 0:
-		consoleURL = fmt.Sprintf("https://%s", vs.Spec.Hosts[0])
-	default:
-		return nil, pkgErrors.New("console host could not be obtained")
+	if err := i.ensureInstallationCRD(files[installerFile]); err != nil {
+		return err
 	}
 
-	var warning string
-	if !i.Options.IsLocal && i.Options.Domain != defaultDomain {
-		warning = "To access the console, configure DNS for the cluster load balancer: https://kyma-project.io/docs/#installation-install-kyma-with-your-own-domain-configure-dns-for-the-cluster-load-balancer"
+	err = i.applyWithTimeout(func(ctx context.Context) error {
+		return i.Service.TriggerInstallation(ctx, installerFileContent, installerCRFileContent, configuration)
+	})
+	if err != nil {
+		return fmt.Errorf("Failed to start installation: apply of the Kyma Installer failed: %s", err.Error())
+	}
+	if err := i.applyCustomResources(deferred); err != nil {
+		return fmt.Errorf("Failed to start installation: apply of the custom resources of the Kyma Installer failed: %s", err.Error())
 	}
 
-	return &Result{
-		KymaVersion:   v,
-		Host:          i.K8s.RestConfig().Host,
-		Console:       consoleURL,
-		AdminEmail:    string(adm.Data["email"]),
-		AdminPassword: string(adm.Data["password"]),
-		Warnings:      []string{warning},
-		Duration:      duration,
-	}, nil
+	return i.waitForInstallerPod()
 }
 
-func (i *Installation) releaseFile(path string) string {
-	return fmt.Sprintf(releaseResourcePattern, i.Options.bucket, i.Options.configVersion, path)
+// resolveReleaseArtifacts sets the release providing the installation files of the version, its artifacts are read from --release-artifacts if given
+func (i *Installation) resolveReleaseArtifacts(version string) error {
+	var err error
+	if i.Options.ReleaseArtifacts != "" {
+		i.Options.release, err = release.FromLocation(i.Options.ReleaseArtifacts, version)
+	} else {
+		i.Options.release, err = release.FromVersion(version)
+	}
+	return err
 }
@@ -7,17 +7,25 @@ import (
 	"time"
 
 	installSDK "github.com/kyma-incubator/hydroform/install/installation"
+	"github.com/kyma-project/cli/internal/kube"
 	k8sMocks "github.com/kyma-project/cli/internal/kube/mocks"
+	"github.com/kyma-project/cli/internal/version"
 	"github.com/kyma-project/cli/pkg/installation/mocks"
+	stepMocks "github.com/kyma-project/cli/pkg/step/mocks"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 	networkingv1alpha3 "istio.io/api/networking/v1alpha3"
 	"istio.io/client-go/pkg/apis/networking/v1alpha3"
 	fakeIstio "istio.io/client-go/pkg/clientset/versioned/fake"
 	v1 "k8s.io/api/core/v1"
+	apiErrors "k8s.io/apimachinery/pkg/api/errors"
 	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	fakeDynamic "k8s.io/client-go/dynamic/fake"
 	"k8s.io/client-go/kubernetes/fake"
 	"k8s.io/client-go/rest"
+	k8sTesting "k8s.io/client-go/testing"
 )
 
 func TestInstallKyma(t *testing.T) {
@@ -80,15 +88,18 @@ func TestInstallKyma(t *testing.T) {
 			ComponentsConfig: "",
 			IsLocal:          false,
 			Source:           "1.15.1",
+			// the mocked service does not apply the Installation CR, so the Kyma Installer cannot pick it up
+			SkipActivationCheck: true,
 		},
 	}
 
 	kymaMock.On("Static").Return(k8sMock)
 	kymaMock.On("Istio").Return(istioMock)
+	kymaMock.On("Dynamic").Return(fakeDynamicWithInstallationCRD())
 	kymaMock.On("RestConfig", mock.Anything).Return(&rest.Config{Host: "fake-kubeconfig-host"})
 
 	// There is an existing installation
-	iServiceMock.On("CheckInstallationState", mock.Anything).Return(installSDK.InstallationState{State: "Installed"}, nil).Once()
+	iServiceMock.On("CheckInstallationState", mock.Anything, mock.Anything).Return(installSDK.InstallationState{State: "Installed"}, nil).Once()
 
 	r, err := i.InstallKyma()
 	require.NoError(t, err)
@@ -96,23 +107,24 @@ func TestInstallKyma(t *testing.T) {
 
 	// Installation in progress
 	i.Options.NoWait = true // no need to wait for installation here
-	iServiceMock.On("CheckInstallationState", mock.Anything).Return(installSDK.InstallationState{State: "InProgress"}, nil).Times(2)
+	iServiceMock.On("CheckInstallationState", mock.Anything, mock.Anything).Return(installSDK.InstallationState{State: "InProgress"}, nil).Times(2)
 
 	r, err = i.InstallKyma()
 	require.NoError(t, err)
-	require.Empty(t, r)
+	require.Equal(t, version.InProgress, r.ClusterVersion.State, "The summary shows the installation in progress")
 
 	// Error getting installation status
-	iServiceMock.On("CheckInstallationState", mock.Anything).Return(installSDK.InstallationState{}, errors.New("installation is hiding from us")).Once()
+	iServiceMock.On("CheckInstallationState", mock.Anything, mock.Anything).Return(installSDK.InstallationState{}, errors.New("installation is hiding from us")).Once()
 
 	r, err = i.InstallKyma()
 	require.Error(t, err)
 	require.Empty(t, r)
 
 	// Empty installation status will be treated the same way as a cluster with no installation, so we should have a happy path
-	iServiceMock.On("CheckInstallationState", mock.Anything).Return(installSDK.InstallationState{}, nil).Once()
-	iServiceMock.On("TriggerInstallation", mock.Anything, mock.Anything, mock.Anything).Return(nil)
-	iServiceMock.On("CheckInstallationState", mock.Anything).Return(installSDK.InstallationState{State: "Installed"}, nil).Once()
+	iServiceMock.On("CheckInstallationState", mock.Anything, mock.Anything).Return(installSDK.InstallationState{}, nil).Once()
+	iServiceMock.On("TriggerInstallation", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	iServiceMock.On("CheckInstallationState", mock.Anything, mock.Anything).Return(installSDK.InstallationState{State: "Installed"}, nil).Once()
+	kymaMock.On("IsPodReadyByLabel", "kyma-installer", "name", "kyma-installer").Return(false, nil)
 	kymaMock.On("WaitPodStatusByLabel", "kyma-installer", "name", "kyma-installer", v1.PodRunning).Return(nil)
 
 	r, err = i.InstallKyma()
@@ -120,9 +132,10 @@ func TestInstallKyma(t *testing.T) {
 	require.NotEmpty(t, r)
 
 	// Happy path
-	iServiceMock.On("CheckInstallationState", mock.Anything).Return(installSDK.InstallationState{State: installSDK.NoInstallationState}, nil).Once()
-	iServiceMock.On("TriggerInstallation", mock.Anything, mock.Anything, mock.Anything).Return(nil)
-	iServiceMock.On("CheckInstallationState", mock.Anything).Return(installSDK.InstallationState{State: "Installed"}, nil).Once()
+	iServiceMock.On("CheckInstallationState", mock.Anything, mock.Anything).Return(installSDK.InstallationState{State: installSDK.NoInstallationState}, nil).Once()
+	iServiceMock.On("TriggerInstallation", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	iServiceMock.On("CheckInstallationState", mock.Anything, mock.Anything).Return(installSDK.InstallationState{State: "Installed"}, nil).Once()
+	kymaMock.On("IsPodReadyByLabel", "kyma-installer", "name", "kyma-installer").Return(false, nil)
 	kymaMock.On("WaitPodStatusByLabel", "kyma-installer", "name", "kyma-installer", v1.PodRunning).Return(nil)
 
 	r, err = i.InstallKyma()
@@ -130,9 +143,10 @@ func TestInstallKyma(t *testing.T) {
 	require.NotEmpty(t, r)
 
 	// Happy path with commit ID
-	iServiceMock.On("CheckInstallationState", mock.Anything).Return(installSDK.InstallationState{State: installSDK.NoInstallationState}, nil).Once()
-	iServiceMock.On("TriggerInstallation", mock.Anything, mock.Anything, mock.Anything).Return(nil)
-	iServiceMock.On("CheckInstallationState", mock.Anything).Return(installSDK.InstallationState{State: "Installed"}, nil).Once()
+	iServiceMock.On("CheckInstallationState", mock.Anything, mock.Anything).Return(installSDK.InstallationState{State: installSDK.NoInstallationState}, nil).Once()
+	iServiceMock.On("TriggerInstallation", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	iServiceMock.On("CheckInstallationState", mock.Anything, mock.Anything).Return(installSDK.InstallationState{State: "Installed"}, nil).Once()
+	kymaMock.On("IsPodReadyByLabel", "kyma-installer", "name", "kyma-installer").Return(false, nil)
 	kymaMock.On("WaitPodStatusByLabel", "kyma-installer", "name", "kyma-installer", v1.PodRunning).Return(nil)
 
 	i.Options.Source = "23554405"
@@ -141,6 +155,44 @@ func TestInstallKyma(t *testing.T) {
 	require.NotEmpty(t, r)
 }
 
+func TestInstallKymaContinueOnError(t *testing.T) {
+	t.Parallel()
+	// another CLI instance holds the lock of a cluster with an installed Kyma
+	k8sMock := fake.NewSimpleClientset(
+		&v1.ConfigMap{
+			ObjectMeta: metaV1.ObjectMeta{Name: lockName, Namespace: installerNamespace()},
+			Data: map[string]string{
+				lockHolderKey:     "someone@elsewhere (pid 1)",
+				lockAcquiredAtKey: time.Now().UTC().Format(time.RFC3339),
+			},
+		},
+		&v1.Pod{
+			ObjectMeta: metaV1.ObjectMeta{Name: "kyma-installer", Namespace: "kyma-installer", Labels: map[string]string{"name": "kyma-installer"}},
+			Status:     v1.PodStatus{Phase: v1.PodRunning},
+			Spec:       v1.PodSpec{Containers: []v1.Container{{Name: "Installer", Image: "fake-registry/installer:1.15.1"}}},
+		},
+	)
+	kymaMock := k8sMocks.KymaKube{}
+	kymaMock.On("Static").Return(k8sMock)
+	kymaMock.On("Dynamic").Return(fakeDynamicWithInstallationCRD())
+	kymaMock.On("RestConfig", mock.Anything).Return(&rest.Config{Host: "fake-kubeconfig-host"})
+	iServiceMock := mocks.Service{}
+	iServiceMock.On("CheckInstallationState", mock.Anything, mock.Anything).Return(installSDK.InstallationState{State: "Installed"}, nil)
+
+	i := &Installation{
+		K8s:     &kymaMock,
+		Service: &iServiceMock,
+		Options: &Options{NonInteractive: true, ContinueOnError: true, Timeout: time.Minute},
+	}
+
+	// the failed lock stage is reported, although the installation itself is not changed
+	_, err := i.InstallKyma()
+	stageErrs := StageErrors{}
+	require.True(t, errors.As(err, &stageErrs), "expected the collected stage errors, got: %v", err)
+	require.Len(t, stageErrs, 1)
+	require.Equal(t, stageLock, stageErrs[0].Stage)
+}
+
 func TestValidateConfigurations(t *testing.T) {
 	t.Parallel()
 	// Domain is passed, but certificate and key are missing
@@ -262,3 +314,136 @@ func TestValidateConfigurations(t *testing.T) {
 	err = i.validateConfigurations()
 	require.Error(t, err)
 }
+
+func TestDiscoverInstallationName(t *testing.T) {
+	t.Parallel()
+	installationCR := func(name string) runtime.Object {
+		return &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "installer.kyma-project.io/v1alpha1",
+			"kind":       "Installation",
+			"metadata": map[string]interface{}{
+				"name":      name,
+				"namespace": "default",
+			},
+		}}
+	}
+
+	// No Installation CR on the cluster
+	kymaMock := k8sMocks.KymaKube{}
+	kymaMock.On("Dynamic").Return(fakeDynamic.NewSimpleDynamicClient(runtime.NewScheme()))
+	i := &Installation{K8s: &kymaMock, Options: &Options{}}
+	require.NoError(t, i.discoverInstallationName())
+	require.Equal(t, "", i.Options.InstallationName)
+	require.Equal(t, "kyma-installation", i.installationName())
+
+	// One Installation CR with a custom name
+	kymaMock = k8sMocks.KymaKube{}
+	kymaMock.On("Dynamic").Return(fakeDynamic.NewSimpleDynamicClient(runtime.NewScheme(), installationCR("custom-installation")))
+	i = &Installation{K8s: &kymaMock, Options: &Options{}}
+	require.NoError(t, i.discoverInstallationName())
+	require.Equal(t, "custom-installation", i.installationName())
+
+	// Multiple Installation CRs
+	kymaMock = k8sMocks.KymaKube{}
+	kymaMock.On("Dynamic").Return(fakeDynamic.NewSimpleDynamicClient(runtime.NewScheme(), installationCR("installation-a"), installationCR("installation-b")))
+	i = &Installation{K8s: &kymaMock, Options: &Options{}}
+	err := i.discoverInstallationName()
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "installation-a, installation-b")
+
+	// Not permitted to list the Installation CRs
+	dynamicMock := fakeDynamic.NewSimpleDynamicClient(runtime.NewScheme())
+	dynamicMock.PrependReactor("list", "installations", func(action k8sTesting.Action) (bool, runtime.Object, error) {
+		return true, nil, apiErrors.NewForbidden(installationGVR().GroupResource(), "", errors.New("no access"))
+	})
+	kymaMock = k8sMocks.KymaKube{}
+	kymaMock.On("Dynamic").Return(dynamicMock)
+	i = &Installation{K8s: &kymaMock, Options: &Options{}}
+	err = i.discoverInstallationName()
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "not permitted to list the Installation CRs")
+
+	// Explicitly configured name is not discovered
+	kymaMock = k8sMocks.KymaKube{}
+	i = &Installation{K8s: &kymaMock, Options: &Options{InstallationName: "installation-b"}}
+	require.NoError(t, i.discoverInstallationName())
+	require.Equal(t, "installation-b", i.installationName())
+	kymaMock.AssertNotCalled(t, "Dynamic")
+}
+
+func TestCheckCLICompatibility(t *testing.T) {
+	t.Parallel()
+	s := &stepMocks.Step{}
+	i := &Installation{currentStep: s, Options: &Options{}}
+
+	require.NoError(t, i.checkCLICompatibility("1.15.1"))
+	require.NoError(t, i.checkCLICompatibility("master"))
+
+	err := i.checkCLICompatibility("0.9.0")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "Kyma 0.9.0 is not supported by this version of Kyma CLI")
+	require.Contains(t, err.Error(), "Use --force to continue anyway")
+
+	i.Options.Force = true
+	require.NoError(t, i.checkCLICompatibility("0.9.0"))
+	require.Len(t, s.Errors(), 1)
+	require.Contains(t, s.Errors()[0], "Warning: Kyma 0.9.0 is not supported")
+}
+
+func TestWaitForInstallerPod(t *testing.T) {
+	t.Parallel()
+	// a running and ready installer is not waited for
+	kymaMock := &k8sMocks.KymaKube{}
+	kymaMock.On("Static").Return(fake.NewSimpleClientset())
+	kymaMock.On("IsPodReadyByLabel", "kyma-installer", "name", "kyma-installer").Return(true, nil)
+	i := &Installation{K8s: kymaMock, Options: &Options{}}
+	require.NoError(t, i.waitForInstallerPod())
+	kymaMock.AssertNotCalled(t, "WaitPodStatusByLabel", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+
+	// an installer which is not ready yet is waited for as before
+	kymaMock = &k8sMocks.KymaKube{}
+	kymaMock.On("Static").Return(fake.NewSimpleClientset())
+	kymaMock.On("IsPodReadyByLabel", "kyma-installer", "name", "kyma-installer").Return(false, nil)
+	kymaMock.On("WaitPodStatusByLabel", "kyma-installer", "name", "kyma-installer", v1.PodRunning).Return(nil).Once()
+	i = &Installation{K8s: kymaMock, Options: &Options{}}
+	require.NoError(t, i.waitForInstallerPod())
+	kymaMock.AssertExpectations(t)
+
+	// a pod rejected by a PodSecurityPolicy is reported with a hint
+	kymaMock = &k8sMocks.KymaKube{}
+	kymaMock.On("Static").Return(fake.NewSimpleClientset())
+	kymaMock.On("IsPodReadyByLabel", "kyma-installer", "name", "kyma-installer").Return(false, nil)
+	kymaMock.On("WaitPodStatusByLabel", "kyma-installer", "name", "kyma-installer", v1.PodRunning).Return(&kube.PodCreationError{
+		ReplicaSet: "kyma-installer-5d8f",
+		Messages:   []string{`Error creating: pods "kyma-installer-5d8f-" is forbidden: unable to validate against any pod security policy: []`},
+	})
+	i = &Installation{K8s: kymaMock, Options: &Options{}}
+	err := i.waitForInstallerPod()
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "unable to validate against any pod security policy")
+	require.Contains(t, err.Error(), "--create-psp")
+
+	i.Options.CreatePSP = true
+	require.NotContains(t, i.waitForInstallerPod().Error(), "--create-psp")
+}
+
+func TestCheckTriggered(t *testing.T) {
+	t.Parallel()
+	kymaMock := &k8sMocks.KymaKube{}
+	kymaMock.On("Static").Return(fake.NewSimpleClientset())
+	i := &Installation{K8s: kymaMock, Options: &Options{AttachOnly: true}}
+	err := i.checkTriggered("")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), `Run "kyma install installer" first`)
+	// an installed Kyma is not waited for, so the Kyma Installer does not matter
+	require.NoError(t, i.checkTriggered("Installed"))
+
+	kymaMock.On("IsPodReadyByLabel", "kyma-installer", "name", "kyma-installer").Return(false, nil).Once()
+	err = i.checkTriggered("InProgress")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "the Kyma Installer pod is not ready")
+
+	kymaMock.On("IsPodReadyByLabel", "kyma-installer", "name", "kyma-installer").Return(true, nil).Once()
+	require.NoError(t, i.checkTriggered("InProgress"))
+	kymaMock.AssertExpectations(t)
+}
@@ -0,0 +1,82 @@
+package installation
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// installerDebugFlag is the flag of the Kyma Installer enabling the debug mode of its Helm client, which is the only verbosity setting of the Kyma Installer
+const installerDebugFlag = "helmDebugMode"
+
+// installerLogLevels are the log levels the Kyma Installer supports, "debug" enables the debug mode and "info" disables it
+var installerLogLevels = []string{"debug", "info"}
+
+// validateInstallerLogLevel ensures that the Kyma Installer supports the log level
+func (i *Installation) validateInstallerLogLevel() error {
+	if i.Options.InstallerLogLevel == "" {
+		return nil
+	}
+	for _, l := range installerLogLevels {
+		if i.Options.InstallerLogLevel == l {
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid value '%s' of --installer-log-level, use one of: %s", i.Options.InstallerLogLevel, strings.Join(installerLogLevels, ", "))
+}
+
+// insertInstallerLogLevel sets the debug mode argument of the kyma-installer-container, an existing argument is replaced
+func insertInstallerLogLevel(installerFile *File, level string) error {
+	container, ok := installerContainer(installerFile)
+	if !ok {
+		return errors.New("unable to set the log level of the Kyma Installer 'Deployment'")
+	}
+
+	arg := fmt.Sprintf("-%s=%t", installerDebugFlag, level == "debug")
+	args, _ := container["args"].([]interface{})
+	for n, a := range args {
+		if _, ok := installerDebugArg(a); ok {
+			args[n] = arg
+			return nil
+		}
+	}
+	container["args"] = append(args, arg)
+	return nil
+}
+
+// installerLogLevel returns the log level configured in the kyma-installer-container, or "" if the Kyma Installer uses its default
+func installerLogLevel(installerFile *File) string {
+	container, ok := installerContainer(installerFile)
+	if !ok {
+		return ""
+	}
+	args, _ := container["args"].([]interface{})
+	for _, a := range args {
+		if debug, ok := installerDebugArg(a); ok {
+			if debug {
+				return "debug"
+			}
+			return "info"
+		}
+	}
+	return ""
+}
+
+// installerDebugArg checks if the argument is the debug mode flag, in any of the forms the Go flag package accepts, and returns its value
+func installerDebugArg(a interface{}) (bool, bool) {
+	arg, _ := a.(string)
+	name := strings.TrimPrefix(strings.TrimPrefix(arg, "-"), "-")
+	if name == arg {
+		return false, false
+	}
+	if name == installerDebugFlag {
+		return true, true
+	}
+	if !strings.HasPrefix(name, installerDebugFlag+"=") {
+		return false, false
+	}
+	debug, err := strconv.ParseBool(strings.TrimPrefix(name, installerDebugFlag+"="))
+	return debug && err == nil, true
+}
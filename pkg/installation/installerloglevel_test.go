@@ -0,0 +1,56 @@
+package installation
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateInstallerLogLevel(t *testing.T) {
+	t.Parallel()
+	i := &Installation{Options: &Options{}}
+	require.NoError(t, i.validateInstallerLogLevel())
+	i.Options.InstallerLogLevel = "debug"
+	require.NoError(t, i.validateInstallerLogLevel())
+	i.Options.InstallerLogLevel = "warn"
+	require.EqualError(t, i.validateInstallerLogLevel(), "invalid value 'warn' of --installer-log-level, use one of: debug, info")
+}
+
+func TestInsertInstallerLogLevel(t *testing.T) {
+	t.Parallel()
+	installer := func(container map[interface{}]interface{}) *File {
+		container["name"] = "kyma-installer-container"
+		return &File{Content: []map[string]interface{}{{
+			"kind": "Deployment",
+			"spec": map[interface{}]interface{}{
+				"template": map[interface{}]interface{}{
+					"spec": map[interface{}]interface{}{"containers": []interface{}{container}},
+				},
+			},
+		}}}
+	}
+
+	// the argument is added
+	file := installer(map[interface{}]interface{}{"args": []interface{}{"-overrideLogFile=/app/overrides.txt"}})
+	require.Equal(t, "", installerLogLevel(file))
+	require.NoError(t, insertInstallerLogLevel(file, "debug"))
+	require.Equal(t, "debug", installerLogLevel(file))
+	container, _ := installerContainer(file)
+	require.Equal(t, []interface{}{"-overrideLogFile=/app/overrides.txt", "-helmDebugMode=true"}, container["args"])
+
+	// an existing argument is replaced, in any form the Go flag package accepts
+	for _, arg := range []string{"-helmDebugMode=true", "--helmDebugMode=true", "-helmDebugMode"} {
+		file = installer(map[interface{}]interface{}{"args": []interface{}{"-overrideLogFile=/app/overrides.txt", arg}})
+		require.Equal(t, "debug", installerLogLevel(file))
+		require.NoError(t, insertInstallerLogLevel(file, "info"))
+		require.Equal(t, "info", installerLogLevel(file))
+		container, _ = installerContainer(file)
+		require.Equal(t, []interface{}{"-overrideLogFile=/app/overrides.txt", "-helmDebugMode=false"}, container["args"])
+	}
+
+	// other flags starting with the name are kept
+	file = installer(map[interface{}]interface{}{"args": []interface{}{"-helmDebugModeX=true"}})
+	require.Equal(t, "", installerLogLevel(file))
+
+	require.Error(t, insertInstallerLogLevel(&File{Content: []map[string]interface{}{{"kind": "ServiceAccount"}}}, "debug"))
+}
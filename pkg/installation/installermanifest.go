@@ -0,0 +1,53 @@
+package installation
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/kyma-project/cli/internal/files"
+)
+
+// installerManifestAnnotation records the source of the Kyma Installer manifest given with --installer-manifest, so that it can be traced on the cluster
+const installerManifestAnnotation = "cli.kyma-project.io/installer-manifest"
+
+// openSource opens a file given by a flag which accepts URLs as well as paths, and returns the normalized location
+func openSource(source string) (string, io.ReadCloser, error) {
+	if strings.HasPrefix(source, "http://") || strings.HasPrefix(source, "https://") {
+		reader, err := downloadFile(source)
+		return source, reader, err
+	}
+	// URLs are accepted as well, so the path is not normalized with the other path flags
+	path, err := files.NormalizePath(source)
+	if err != nil {
+		return source, nil, err
+	}
+	reader, err := os.Open(path)
+	return path, reader, err
+}
+
+// validateInstallerManifest ensures that the Kyma Installer manifest given with --installer-manifest contains the resources the Kyma Installer cannot run without
+func validateInstallerManifest(file *File) error {
+	if _, err := getInstallerImage(file); err != nil {
+		return fmt.Errorf("the installer manifest '%s' does not contain the deployment of the Kyma Installer with the container 'kyma-installer-container'. Found: %s", file.Path, documentKinds(file))
+	}
+	for _, doc := range file.Content {
+		if kind, _ := doc["kind"].(string); kind == "ServiceAccount" {
+			return nil
+		}
+	}
+	return fmt.Errorf("the installer manifest '%s' does not contain the ServiceAccount of the Kyma Installer. Found: %s", file.Path, documentKinds(file))
+}
+
+// annotateInstallerManifest records the source of the manifest on the deployments of the installer file
+func annotateInstallerManifest(file *File, source string) {
+	for _, doc := range file.Content {
+		if kind, _ := doc["kind"].(string); kind != "Deployment" {
+			continue
+		}
+		if meta, ok := doc["metadata"].(map[interface{}]interface{}); ok {
+			mergeMetadata(meta, "annotations", map[string]string{installerManifestAnnotation: source})
+		}
+	}
+}
@@ -0,0 +1,84 @@
+package installation
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const installerManifest = `apiVersion: v1
+kind: ServiceAccount
+metadata:
+  name: kyma-installer
+  namespace: kyma-installer
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: kyma-installer
+  namespace: kyma-installer
+spec:
+  template:
+    spec:
+      serviceAccountName: kyma-installer
+      containers:
+      - name: kyma-installer-container
+        image: mirror.example.com/kyma-installer:patched
+`
+
+func TestInstallerManifest(t *testing.T) {
+	t.Parallel()
+	dir, err := ioutil.TempDir("", "kyma-installer-manifest-")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	write := func(name, content string) string {
+		file := filepath.Join(dir, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(file), 0700))
+		require.NoError(t, ioutil.WriteFile(file, []byte(content), 0600))
+		return file
+	}
+	// the local sources hold an installer which must not be used
+	write("src/installation/resources/installer.yaml", "apiVersion: v1\nkind: Namespace\nmetadata:\n  name: kyma-installer\n")
+	write("src/installation/resources/installer-cr-cluster.yaml.tpl", "apiVersion: installer.kyma-project.io/v1alpha1\nkind: Installation\nmetadata:\n  name: kyma-installation\n")
+
+	t.Run("Manifest replaces the installer file", func(t *testing.T) {
+		manifest := write("mirror/kyma-installer-cluster.yaml", installerManifest)
+		i := &Installation{Options: &Options{fromLocalSources: true, LocalSrcPath: filepath.Join(dir, "src"), InstallerManifest: manifest}}
+		files, err := i.loadInstallationFiles()
+		require.NoError(t, err)
+		require.Equal(t, manifest, files[installerFile].Path)
+		image, err := getInstallerImage(files[installerFile])
+		require.NoError(t, err)
+		require.Equal(t, "mirror.example.com/kyma-installer:patched", image)
+
+		// the source is recorded on the Kyma Installer deployment
+		annotations := docMetadata(files[installerFile].Content[1])["annotations"].(map[interface{}]interface{})
+		require.Equal(t, manifest, annotations[installerManifestAnnotation])
+	})
+
+	t.Run("Manifest without ServiceAccount", func(t *testing.T) {
+		manifest := write("mirror/no-sa.yaml", installerManifest[len("apiVersion: v1\nkind: ServiceAccount\nmetadata:\n  name: kyma-installer\n  namespace: kyma-installer\n---\n"):])
+		i := &Installation{Options: &Options{fromLocalSources: true, LocalSrcPath: filepath.Join(dir, "src"), InstallerManifest: manifest}}
+		_, err := i.loadInstallationFiles()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "does not contain the ServiceAccount")
+	})
+
+	t.Run("Manifest without deployment", func(t *testing.T) {
+		manifest := write("mirror/no-deployment.yaml", "apiVersion: v1\nkind: ServiceAccount\nmetadata:\n  name: kyma-installer\n")
+		i := &Installation{Options: &Options{fromLocalSources: true, LocalSrcPath: filepath.Join(dir, "src"), InstallerManifest: manifest}}
+		_, err := i.loadInstallationFiles()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "does not contain the deployment of the Kyma Installer")
+	})
+
+	t.Run("Missing manifest", func(t *testing.T) {
+		i := &Installation{Options: &Options{fromLocalSources: true, LocalSrcPath: filepath.Join(dir, "src"), InstallerManifest: filepath.Join(dir, "missing.yaml")}}
+		_, err := i.loadInstallationFiles()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "unable to read the installer manifest")
+	})
+}
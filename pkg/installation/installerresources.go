@@ -0,0 +1,163 @@
+package installation
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// installerMinimums are the resources below which the Kyma Installer runs out of memory or takes very long to install the components
+var installerMinimums = map[corev1.ResourceName]resource.Quantity{
+	corev1.ResourceCPU:    resource.MustParse("100m"),
+	corev1.ResourceMemory: resource.MustParse("256Mi"),
+}
+
+// installerResource is a resource request or limit of the Kyma Installer container given with a flag
+type installerResource struct {
+	flag     string
+	field    string
+	resource corev1.ResourceName
+	value    string
+}
+
+func (i *Installation) installerResources() []installerResource {
+	return []installerResource{
+		{flag: "installer-cpu", field: "requests", resource: corev1.ResourceCPU, value: i.Options.InstallerCPURequest},
+		{flag: "installer-memory", field: "requests", resource: corev1.ResourceMemory, value: i.Options.InstallerMemoryRequest},
+		{flag: "installer-cpu-limit", field: "limits", resource: corev1.ResourceCPU, value: i.Options.InstallerCPULimit},
+		{flag: "installer-memory-limit", field: "limits", resource: corev1.ResourceMemory, value: i.Options.InstallerMemoryLimit},
+	}
+}
+
+// installerResourcesConfigured checks if the resources of the Kyma Installer container are customized
+func (i *Installation) installerResourcesConfigured() bool {
+	for _, r := range i.installerResources() {
+		if r.value != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// validateInstallerResources ensures that the resources of the Kyma Installer are Kubernetes quantities and that the requests do not exceed the limits
+func (i *Installation) validateInstallerResources() error {
+	values := map[string]resource.Quantity{}
+	for _, r := range i.installerResources() {
+		if r.value == "" {
+			continue
+		}
+		q, err := resource.ParseQuantity(r.value)
+		if err != nil {
+			return fmt.Errorf("invalid value '%s' of --%s: %s", r.value, r.flag, err)
+		}
+		if q.Sign() <= 0 {
+			return fmt.Errorf("invalid value '%s' of --%s: the quantity must be greater than zero", r.value, r.flag)
+		}
+		values[r.flag] = q
+	}
+	for request, limit := range map[string]string{"installer-cpu": "installer-cpu-limit", "installer-memory": "installer-memory-limit"} {
+		r, hasRequest := values[request]
+		l, hasLimit := values[limit]
+		if hasRequest && hasLimit && r.Cmp(l) > 0 {
+			return fmt.Errorf("the value of --%s exceeds the value of --%s", request, limit)
+		}
+	}
+	return nil
+}
+
+// insertInstallerResources sets the resource requests and limits of the Kyma Installer container.
+// It returns the resources set below the minimums of the Kyma Installer, so that a warning can be displayed.
+func insertInstallerResources(installerFile *File, resources []installerResource) ([]string, error) {
+	container, ok := installerContainer(installerFile)
+	if !ok {
+		return nil, errors.New("unable to set the resources of the Kyma Installer 'Deployment'")
+	}
+
+	var belowMinimum []string
+	spec, _ := container["resources"].(map[interface{}]interface{})
+	if spec == nil {
+		spec = map[interface{}]interface{}{}
+	}
+	for _, r := range resources {
+		if r.value == "" {
+			continue
+		}
+		q, err := resource.ParseQuantity(r.value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value '%s' of --%s: %s", r.value, r.flag, err)
+		}
+		field, _ := spec[r.field].(map[interface{}]interface{})
+		if field == nil {
+			field = map[interface{}]interface{}{}
+		}
+		field[string(r.resource)] = q.String()
+		spec[r.field] = field
+		if min := installerMinimums[r.resource]; q.Cmp(min) < 0 {
+			belowMinimum = append(belowMinimum, fmt.Sprintf("--%s=%s (minimum %s)", r.flag, q.String(), min.String()))
+		}
+	}
+	container["resources"] = spec
+	if err := checkInstallerLimits(spec, resources); err != nil {
+		return nil, err
+	}
+	return belowMinimum, nil
+}
+
+// checkInstallerLimits ensures that the requests of the Kyma Installer container do not exceed its limits, once the flags are merged with the resources of the installer file.
+// Otherwise, Kubernetes rejects the deployment.
+func checkInstallerLimits(spec map[interface{}]interface{}, resources []installerResource) error {
+	flags := map[string]string{}
+	for _, r := range resources {
+		if r.value != "" {
+			flags[r.field+"."+string(r.resource)] = r.flag
+		}
+	}
+	requests, _ := spec["requests"].(map[interface{}]interface{})
+	limits, _ := spec["limits"].(map[interface{}]interface{})
+	for _, name := range []corev1.ResourceName{corev1.ResourceCPU, corev1.ResourceMemory} {
+		request, hasRequest := quantity(requests, name)
+		limit, hasLimit := quantity(limits, name)
+		if !hasRequest || !hasLimit || request.Cmp(limit) <= 0 {
+			continue
+		}
+		requestFlag, limitFlag := flags["requests."+string(name)], flags["limits."+string(name)]
+		switch {
+		case requestFlag != "" && limitFlag == "":
+			return fmt.Errorf("the value of --%s exceeds the %s limit %s of the Kyma Installer in the installer file, set a higher limit with --%s-limit", requestFlag, name, limit.String(), requestFlag)
+		case limitFlag != "" && requestFlag == "":
+			return fmt.Errorf("the value of --%s is below the %s request %s of the Kyma Installer in the installer file, set a lower request with --%s", limitFlag, name, request.String(), strings.TrimSuffix(limitFlag, "-limit"))
+		}
+	}
+	return nil
+}
+
+// quantity reads the quantity of the resource from the requests or limits of a container
+func quantity(values map[interface{}]interface{}, name corev1.ResourceName) (resource.Quantity, bool) {
+	value, ok := values[string(name)]
+	if !ok {
+		return resource.Quantity{}, false
+	}
+	q, err := resource.ParseQuantity(fmt.Sprint(value))
+	if err != nil {
+		return resource.Quantity{}, false
+	}
+	return q, true
+}
+
+// installerContainer returns the kyma-installer-container of the Kyma Installer deployment
+func installerContainer(installerFile *File) (map[interface{}]interface{}, bool) {
+	spec, ok := installerPodSpec(installerFile)
+	if !ok {
+		return nil, false
+	}
+	containers, _ := spec["containers"].([]interface{})
+	for _, c := range containers {
+		if container, ok := c.(map[interface{}]interface{}); ok && container["name"] == "kyma-installer-container" {
+			return container, true
+		}
+	}
+	return nil, false
+}
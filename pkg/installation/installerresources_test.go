@@ -0,0 +1,88 @@
+package installation
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateInstallerResources(t *testing.T) {
+	t.Parallel()
+	i := &Installation{Options: &Options{
+		InstallerCPURequest:    "200m",
+		InstallerMemoryRequest: "512Mi",
+		InstallerCPULimit:      "1",
+		InstallerMemoryLimit:   "1Gi",
+	}}
+	require.NoError(t, i.validateInstallerResources())
+	require.True(t, i.installerResourcesConfigured())
+
+	i.Options.InstallerMemoryRequest = "lots"
+	require.EqualError(t, i.validateInstallerResources(), "invalid value 'lots' of --installer-memory: quantities must match the regular expression '^([+-]?[0-9.]+)([eEinumkKMGTP]*[-+]?[0-9]*)$'")
+
+	i.Options.InstallerMemoryRequest = "0"
+	require.Error(t, i.validateInstallerResources())
+
+	i.Options.InstallerMemoryRequest = "2Gi"
+	require.EqualError(t, i.validateInstallerResources(), "the value of --installer-memory exceeds the value of --installer-memory-limit")
+
+	require.False(t, (&Installation{Options: &Options{}}).installerResourcesConfigured())
+}
+
+// installerFileWithResources returns an installer file whose Kyma Installer requests 400m CPU and 1Gi memory, with a memory limit of 2Gi
+func installerFileWithResources() *File {
+	return &File{Content: []map[string]interface{}{
+		{"kind": "ServiceAccount"},
+		{
+			"kind": "Deployment",
+			"spec": map[interface{}]interface{}{
+				"template": map[interface{}]interface{}{
+					"spec": map[interface{}]interface{}{
+						"containers": []interface{}{
+							map[interface{}]interface{}{
+								"name":  "kyma-installer-container",
+								"image": "installer:1.15.1",
+								"resources": map[interface{}]interface{}{
+									"requests": map[interface{}]interface{}{"cpu": "400m", "memory": "1Gi"},
+									"limits":   map[interface{}]interface{}{"memory": "2Gi"},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}}
+}
+
+func TestInsertInstallerResources(t *testing.T) {
+	t.Parallel()
+	file := installerFileWithResources()
+
+	i := &Installation{Options: &Options{InstallerMemoryRequest: "128Mi", InstallerCPULimit: "1000m"}}
+	belowMinimum, err := insertInstallerResources(file, i.installerResources())
+	require.NoError(t, err)
+	require.Equal(t, []string{"--installer-memory=128Mi (minimum 256Mi)"}, belowMinimum)
+	container, ok := installerContainer(file)
+	require.True(t, ok)
+	require.Equal(t, map[interface{}]interface{}{
+		"requests": map[interface{}]interface{}{"cpu": "400m", "memory": "128Mi"},
+		"limits":   map[interface{}]interface{}{"cpu": "1", "memory": "2Gi"},
+	}, container["resources"], "resources not given with a flag must be kept")
+
+	_, err = insertInstallerResources(&File{Content: []map[string]interface{}{{"kind": "ServiceAccount"}}}, i.installerResources())
+	require.Error(t, err)
+
+	// the requests are checked against the limits of the installer file
+	i = &Installation{Options: &Options{InstallerMemoryRequest: "3Gi"}}
+	_, err = insertInstallerResources(installerFileWithResources(), i.installerResources())
+	require.EqualError(t, err, "the value of --installer-memory exceeds the memory limit 2Gi of the Kyma Installer in the installer file, set a higher limit with --installer-memory-limit")
+
+	i = &Installation{Options: &Options{InstallerMemoryLimit: "512Mi"}}
+	_, err = insertInstallerResources(installerFileWithResources(), i.installerResources())
+	require.EqualError(t, err, "the value of --installer-memory-limit is below the memory request 1Gi of the Kyma Installer in the installer file, set a lower request with --installer-memory")
+
+	i = &Installation{Options: &Options{InstallerMemoryRequest: "3Gi", InstallerMemoryLimit: "4Gi"}}
+	_, err = insertInstallerResources(installerFileWithResources(), i.installerResources())
+	require.NoError(t, err)
+}
@@ -0,0 +1,66 @@
+package installation
+
+import (
+	"github.com/kyma-project/cli/internal/kube"
+	"github.com/kyma-project/cli/internal/metadata"
+	kymaVersion "github.com/kyma-project/cli/internal/version"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes"
+)
+
+// The namespaces and resources of the Kyma Installer are read from the metadata table, as they changed between Kyma releases.
+
+// metadata returns the metadata of the release being installed, resolved from --source until the release version is known
+func (i *Installation) metadata() metadata.Metadata {
+	if i.Options.releaseVersion != "" {
+		return metadata.For(i.Options.releaseVersion)
+	}
+	return metadata.For(i.Options.Source)
+}
+
+func (i *Installation) installerNamespace() string {
+	return i.metadata().InstallerNamespace
+}
+
+func (i *Installation) installationNamespace() string {
+	return i.metadata().InstallationNamespace
+}
+
+func (i *Installation) installationGVR() schema.GroupVersionResource {
+	return i.metadata().InstallationGVR()
+}
+
+// clusterMetadata returns the metadata of the Kyma version on the cluster, which is read from the image of the Kyma Installer.
+// If no Kyma Installer runs or its version cannot be read, the metadata of the newest releases is returned.
+func clusterMetadata(k8s kubernetes.Interface) metadata.Metadata {
+	v, err := kymaVersion.KymaVersion(k8s)
+	if err != nil {
+		return metadata.Latest()
+	}
+	return metadata.For(v)
+}
+
+// tillerNamespace holds the ConfigMaps in which Tiller stores the Helm 2 releases
+func tillerNamespace() string {
+	return metadata.TillerNamespace()
+}
+
+// installerLabel returns the label selector of the Kyma Installer pods in the format "key=value".
+// The selectors of the release being installed are tried in order, and the first one matching a pod is kept.
+// If a historical selector matched, it is logged, as the Kyma Installer might not be of the expected release.
+// If no pod exists yet, the selector of newly created pods is returned.
+func (i *Installation) installerLabel() string {
+	if i.installerSelector != "" {
+		return i.installerSelector
+	}
+	selectors := i.metadata().InstallerSelectors
+	selector, found, err := kube.FindPodSelector(i.K8s.Static(), i.installerNamespace(), selectors)
+	if err != nil || !found {
+		return selectors[0]
+	}
+	if selector != selectors[0] && i.currentStep != nil {
+		i.currentStep.LogInfof("The Kyma Installer pods were found with the label selector '%s' instead of '%s'", selector, selectors[0])
+	}
+	i.installerSelector = selector
+	return selector
+}
@@ -0,0 +1,73 @@
+package installation
+
+import (
+	"testing"
+
+	k8sMocks "github.com/kyma-project/cli/internal/kube/mocks"
+	"github.com/kyma-project/cli/internal/metadata"
+	stepMocks "github.com/kyma-project/cli/pkg/step/mocks"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+// The installations of the tests have no source, so their resources are in the namespaces of the newest releases.
+
+func installerNamespace() string {
+	return metadata.Latest().InstallerNamespace
+}
+
+func installationNamespace() string {
+	return metadata.Latest().InstallationNamespace
+}
+
+func installationGVR() schema.GroupVersionResource {
+	return metadata.Latest().InstallationGVR()
+}
+
+func TestInstallationMetadata(t *testing.T) {
+	t.Parallel()
+	// the source is used until the release version is resolved
+	i := &Installation{Options: &Options{Source: "1.15.1"}}
+	require.Equal(t, metadata.For("1.15.1"), i.metadata())
+	i.Options.releaseVersion = "1.16.0"
+	require.Equal(t, metadata.For("1.16.0"), i.metadata())
+}
+
+func TestClusterMetadata(t *testing.T) {
+	t.Parallel()
+	// no Kyma Installer runs
+	require.Equal(t, metadata.Latest(), clusterMetadata(fake.NewSimpleClientset()))
+
+	// the version is read from the image of the Kyma Installer
+	static := fake.NewSimpleClientset(&corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "kyma-installer-6c8b9", Namespace: "kyma-installer", Labels: map[string]string{"name": "kyma-installer"}},
+		Spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: "kyma-installer", Image: "eu.gcr.io/kyma-project/kyma-installer:1.15.1"}}},
+	})
+	require.Equal(t, metadata.For("1.15.1"), clusterMetadata(static))
+	require.NotEqual(t, metadata.Latest(), clusterMetadata(static), "the Tiller namespace is only set for releases installed with Helm 2")
+}
+
+func TestInstallerLabel(t *testing.T) {
+	t.Parallel()
+	// no Kyma Installer pod exists yet
+	kymaMock := &k8sMocks.KymaKube{}
+	kymaMock.On("Static").Return(fake.NewSimpleClientset())
+	i := &Installation{K8s: kymaMock, Options: &Options{}}
+	require.Equal(t, "name=kyma-installer", i.installerLabel())
+	require.Empty(t, i.installerSelector, "the selector is looked up again once the pod exists")
+
+	// the Kyma Installer of an older release is labeled differently
+	kymaMock = &k8sMocks.KymaKube{}
+	kymaMock.On("Static").Return(fake.NewSimpleClientset(&corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "kyma-installer-6c8b9", Namespace: "kyma-installer", Labels: map[string]string{"app": "kyma-installer"}},
+	})).Once()
+	s := &stepMocks.Step{}
+	i = &Installation{K8s: kymaMock, Options: &Options{releaseVersion: "1.15.1"}, currentStep: s}
+	require.Equal(t, "app=kyma-installer", i.installerLabel())
+	require.Equal(t, []string{"The Kyma Installer pods were found with the label selector 'app=kyma-installer' instead of 'name=kyma-installer'"}, s.Infos())
+	require.Equal(t, "app=kyma-installer", i.installerLabel(), "the matching selector is kept")
+	kymaMock.AssertExpectations(t)
+}
@@ -0,0 +1,157 @@
+package installation
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"os/user"
+	"syscall"
+	"time"
+
+	"github.com/kyma-project/cli/internal/cli"
+	pkgErrors "github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apiErrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	lockName          = "kyma-cli-lock"
+	lockHolderKey     = "holder"
+	lockAcquiredAtKey = "acquiredAt"
+
+	// DefaultLockTTL is the time after which a lock of another CLI instance is considered stale
+	DefaultLockTTL = 2 * time.Hour
+)
+
+// acquireLock makes sure that only one CLI instance changes the cluster at a time.
+// The lock is a ConfigMap in the installer namespace holding the identity of the CLI instance and the time it was acquired.
+// The returned function releases the lock; it is also released if the CLI is interrupted, which stops the installation.
+func (i *Installation) acquireLock() (func(), error) {
+	if err := i.ensureNamespace(i.installerNamespace()); err != nil {
+		return nil, err
+	}
+
+	holder := lockHolder()
+	lock := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      lockName,
+			Namespace: i.installerNamespace(),
+		},
+		Data: map[string]string{
+			lockHolderKey:     holder,
+			lockAcquiredAtKey: time.Now().UTC().Format(time.RFC3339),
+		},
+	}
+
+	configMaps := i.K8s.Static().CoreV1().ConfigMaps(i.installerNamespace())
+	_, err := configMaps.Create(context.Background(), lock, metav1.CreateOptions{})
+	if apiErrors.IsAlreadyExists(err) {
+		var existing *corev1.ConfigMap
+		existing, err = configMaps.Get(context.Background(), lockName, metav1.GetOptions{})
+		if err != nil {
+			return nil, pkgErrors.Wrap(err, "Failed to check the installation lock")
+		}
+		if err := i.checkLock(existing); err != nil {
+			return nil, err
+		}
+		// the resource version makes sure that only one instance takes over the lock
+		lock.ResourceVersion = existing.ResourceVersion
+		_, err = configMaps.Update(context.Background(), lock, metav1.UpdateOptions{})
+	}
+	if err != nil {
+		if apiErrors.IsConflict(err) {
+			return nil, fmt.Errorf("Another Kyma CLI instance acquired the installation lock at the same time. Try again later")
+		}
+		// installing without the lock could run two installations at the same time, so a missing permission is fatal
+		if apiErrors.IsForbidden(err) {
+			return nil, forbiddenError(err, fmt.Sprintf("manage ConfigMaps in the namespace '%s'", i.installerNamespace()))
+		}
+		return nil, pkgErrors.Wrap(err, "Failed to acquire the installation lock")
+	}
+
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, os.Interrupt, syscall.SIGTERM)
+	return i.watchInterrupt(interrupt, holder), nil
+}
+
+// watchInterrupt releases the lock and cancels the context of the installation once a signal is received, so that the installation
+// stops and its cleanup runs before the CLI exits. The returned function stops the watching and releases the lock.
+func (i *Installation) watchInterrupt(interrupt chan os.Signal, holder string) func() {
+	parent := i.installCtx()
+	ctx, cancel := context.WithCancel(parent)
+	i.ctx = ctx
+	endHandling := cli.HandleInterrupts()
+
+	done := make(chan struct{})
+	go func() {
+		select {
+		case <-interrupt:
+			signal.Stop(interrupt)
+			i.releaseLock(holder)
+			cancel()
+		case <-done:
+		}
+	}()
+
+	return func() {
+		signal.Stop(interrupt)
+		close(done)
+		cancel()
+		i.ctx = parent
+		i.releaseLock(holder)
+		endHandling()
+	}
+}
+
+// checkLock returns an error if the lock is held by another CLI instance and is not expired
+func (i *Installation) checkLock(lock *corev1.ConfigMap) error {
+	if i.Options.ForceUnlock {
+		i.currentStep.LogInfof("Overriding the installation lock held by '%s'", lock.Data[lockHolderKey])
+		return nil
+	}
+
+	acquiredAt, err := time.Parse(time.RFC3339, lock.Data[lockAcquiredAtKey])
+	if err != nil {
+		// a corrupted lock cannot be fresh
+		return nil
+	}
+
+	ttl := i.Options.LockTTL
+	if ttl == 0 {
+		ttl = DefaultLockTTL
+	}
+	if time.Since(acquiredAt) > ttl {
+		i.currentStep.LogInfof("Taking over the expired installation lock held by '%s'", lock.Data[lockHolderKey])
+		return nil
+	}
+
+	return fmt.Errorf("Another Kyma CLI instance ('%s') is changing the cluster since %s. Wait until it is finished, or use --force-unlock if it is no longer running",
+		lock.Data[lockHolderKey], acquiredAt.Local().Format(time.RFC1123))
+}
+
+// releaseLock deletes the lock if it is still held by the given holder
+func (i *Installation) releaseLock(holder string) {
+	configMaps := i.K8s.Static().CoreV1().ConfigMaps(i.installerNamespace())
+	lock, err := configMaps.Get(context.Background(), lockName, metav1.GetOptions{})
+	if err != nil || lock.Data[lockHolderKey] != holder {
+		return
+	}
+	precondition := metav1.NewPreconditionDeleteOptions(string(lock.UID))
+	precondition.Preconditions.ResourceVersion = &lock.ResourceVersion
+	_ = configMaps.Delete(context.Background(), lockName, *precondition)
+}
+
+// lockHolder identifies the running CLI instance
+func lockHolder() string {
+	name := "unknown"
+	if u, err := user.Current(); err == nil {
+		name = u.Username
+	}
+	host, err := os.Hostname()
+	if err != nil {
+		host = "unknown"
+	}
+	return fmt.Sprintf("%s@%s (pid %d)", name, host, os.Getpid())
+}
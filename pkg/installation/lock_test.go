@@ -0,0 +1,122 @@
+package installation
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/kyma-project/cli/internal/cli"
+	k8sMocks "github.com/kyma-project/cli/internal/kube/mocks"
+	stepMocks "github.com/kyma-project/cli/pkg/step/mocks"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	apiErrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes/fake"
+	k8sTesting "k8s.io/client-go/testing"
+)
+
+func TestLock(t *testing.T) {
+	t.Parallel()
+	foreignLock := func(acquiredAt time.Time) *corev1.ConfigMap {
+		return &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: lockName, Namespace: installerNamespace()},
+			Data: map[string]string{
+				lockHolderKey:     "someone@elsewhere (pid 1)",
+				lockAcquiredAtKey: acquiredAt.UTC().Format(time.RFC3339),
+			},
+		}
+	}
+	newInstallation := func(opts *Options, objects ...runtime.Object) (*Installation, *fake.Clientset) {
+		k8sMock := fake.NewSimpleClientset(objects...)
+		kymaMock := k8sMocks.KymaKube{}
+		kymaMock.On("Static").Return(k8sMock)
+		return &Installation{K8s: &kymaMock, currentStep: &stepMocks.Step{}, Options: opts}, k8sMock
+	}
+
+	// no lock exists
+	i, k8sMock := newInstallation(&Options{})
+	release, err := i.acquireLock()
+	require.NoError(t, err)
+	lock, err := k8sMock.CoreV1().ConfigMaps(installerNamespace()).Get(context.Background(), lockName, metav1.GetOptions{})
+	require.NoError(t, err)
+	require.Equal(t, lockHolder(), lock.Data[lockHolderKey])
+	release()
+	_, err = k8sMock.CoreV1().ConfigMaps(installerNamespace()).Get(context.Background(), lockName, metav1.GetOptions{})
+	require.Error(t, err, "lock must be released")
+
+	// fresh lock of another instance
+	i, k8sMock = newInstallation(&Options{LockTTL: time.Hour}, foreignLock(time.Now()))
+	_, err = i.acquireLock()
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "someone@elsewhere")
+
+	// fresh lock of another instance is forcibly taken over
+	i.Options.ForceUnlock = true
+	release, err = i.acquireLock()
+	require.NoError(t, err)
+	release()
+
+	// expired lock of another instance is taken over
+	i, _ = newInstallation(&Options{LockTTL: time.Hour}, foreignLock(time.Now().Add(-2*time.Hour)))
+	release, err = i.acquireLock()
+	require.NoError(t, err)
+	release()
+
+	// the lock of another instance is not released
+	i, k8sMock = newInstallation(&Options{}, foreignLock(time.Now()))
+	i.releaseLock(lockHolder())
+	_, err = k8sMock.CoreV1().ConfigMaps(installerNamespace()).Get(context.Background(), lockName, metav1.GetOptions{})
+	require.NoError(t, err)
+
+	// the namespace is managed by the cluster administrators
+	i, k8sMock = newInstallation(&Options{})
+	k8sMock.PrependReactor("create", "namespaces", func(action k8sTesting.Action) (bool, runtime.Object, error) {
+		return true, nil, apiErrors.NewForbidden(schema.GroupResource{Resource: "namespaces"}, installerNamespace(), errors.New("no access"))
+	})
+	release, err = i.acquireLock()
+	require.NoError(t, err)
+	release()
+	require.Len(t, i.currentStep.(*stepMocks.Step).Errors(), 1)
+
+	// not permitted to create the lock
+	i, k8sMock = newInstallation(&Options{})
+	k8sMock.PrependReactor("create", "configmaps", func(action k8sTesting.Action) (bool, runtime.Object, error) {
+		return true, nil, apiErrors.NewForbidden(schema.GroupResource{Resource: "configmaps"}, lockName, errors.New("no access"))
+	})
+	_, err = i.acquireLock()
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "not permitted to manage ConfigMaps")
+}
+
+func TestLockInterrupt(t *testing.T) {
+	t.Parallel()
+	lock := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: lockName, Namespace: installerNamespace()},
+		Data:       map[string]string{lockHolderKey: lockHolder()},
+	}
+	k8sMock := fake.NewSimpleClientset(lock)
+	kymaMock := k8sMocks.KymaKube{}
+	kymaMock.On("Static").Return(k8sMock)
+	i := &Installation{K8s: &kymaMock, currentStep: &stepMocks.Step{}, Options: &Options{}}
+
+	interrupt := make(chan os.Signal, 1)
+	release := i.watchInterrupt(interrupt, lockHolder())
+	require.True(t, cli.InterruptsHandled(), "the close handler of the CLI must not exit while the installation stops")
+	interrupt <- os.Interrupt
+
+	select {
+	case <-i.installCtx().Done():
+	case <-time.After(10 * time.Second):
+		require.Fail(t, "the context of the installation is not canceled")
+	}
+	_, err := k8sMock.CoreV1().ConfigMaps(installerNamespace()).Get(context.Background(), lockName, metav1.GetOptions{})
+	require.True(t, apiErrors.IsNotFound(err), "the lock must be released")
+
+	release()
+	require.NoError(t, i.installCtx().Err(), "the context must be reset once the lock is released")
+}
@@ -0,0 +1,69 @@
+package installation
+
+import (
+	"fmt"
+
+	apiValidation "k8s.io/apimachinery/pkg/api/validation"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	metaValidation "k8s.io/apimachinery/pkg/apis/meta/v1/validation"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+// validateExtraMetadata ensures that the extra labels and annotations pass the validation of the Kubernetes API server,
+// so that no resource is rejected in the middle of the installation
+func (i *Installation) validateExtraMetadata() error {
+	if errs := metaValidation.ValidateLabels(i.Options.ExtraLabels, field.NewPath("extra-label")); len(errs) > 0 {
+		return fmt.Errorf("invalid label: %s", errs.ToAggregate())
+	}
+	if errs := apiValidation.ValidateAnnotations(i.Options.ExtraAnnotations, field.NewPath("extra-annotation")); len(errs) > 0 {
+		return fmt.Errorf("invalid annotation: %s", errs.ToAggregate())
+	}
+	return nil
+}
+
+// extraMetadataConfigured checks if labels or annotations should be added to the created resources
+func (i *Installation) extraMetadataConfigured() bool {
+	return len(i.Options.ExtraLabels) > 0 || len(i.Options.ExtraAnnotations) > 0
+}
+
+// insertExtraMetadata adds the labels and annotations to the metadata of every document of the file, existing values are overridden
+func insertExtraMetadata(file *File, labels, annotations map[string]string) {
+	for _, doc := range file.Content {
+		meta, ok := doc["metadata"].(map[interface{}]interface{})
+		if !ok {
+			continue
+		}
+		mergeMetadata(meta, "labels", labels)
+		mergeMetadata(meta, "annotations", annotations)
+	}
+}
+
+func mergeMetadata(meta map[interface{}]interface{}, field string, values map[string]string) {
+	if len(values) == 0 {
+		return
+	}
+	existing, _ := meta[field].(map[interface{}]interface{})
+	if existing == nil {
+		existing = map[interface{}]interface{}{}
+	}
+	for k, v := range values {
+		existing[k] = v
+	}
+	meta[field] = existing
+}
+
+// addExtraMetadata adds the labels and annotations to resources the CLI creates itself, like namespaces and secrets
+func (i *Installation) addExtraMetadata(meta *metav1.ObjectMeta) {
+	if len(i.Options.ExtraLabels) > 0 && meta.Labels == nil {
+		meta.Labels = map[string]string{}
+	}
+	for k, v := range i.Options.ExtraLabels {
+		meta.Labels[k] = v
+	}
+	if len(i.Options.ExtraAnnotations) > 0 && meta.Annotations == nil {
+		meta.Annotations = map[string]string{}
+	}
+	for k, v := range i.Options.ExtraAnnotations {
+		meta.Annotations[k] = v
+	}
+}
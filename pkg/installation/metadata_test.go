@@ -0,0 +1,52 @@
+package installation
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestValidateExtraMetadata(t *testing.T) {
+	t.Parallel()
+	i := &Installation{Options: &Options{
+		ExtraLabels:      map[string]string{"team": "kyma", "example.com/cost-center": "1234", "empty": ""},
+		ExtraAnnotations: map[string]string{"owner": "Kyma team <kyma@example.com>"},
+	}}
+	require.NoError(t, i.validateExtraMetadata())
+	require.True(t, i.extraMetadataConfigured())
+
+	i.Options.ExtraLabels = map[string]string{"team!": "kyma"}
+	require.EqualError(t, i.validateExtraMetadata(), `invalid label: extra-label: Invalid value: "team!": name part must consist of alphanumeric characters, '-', '_' or '.', and must start and end with an alphanumeric character (e.g. 'MyName',  or 'my.name',  or '123-abc', regex used for validation is '([A-Za-z0-9][-A-Za-z0-9_.]*)?[A-Za-z0-9]')`)
+
+	i.Options.ExtraLabels = map[string]string{"team": "kyma team"}
+	require.Error(t, i.validateExtraMetadata())
+
+	i.Options.ExtraLabels = nil
+	i.Options.ExtraAnnotations = map[string]string{"-owner": "kyma"}
+	require.Error(t, i.validateExtraMetadata())
+
+	require.False(t, (&Installation{Options: &Options{}}).extraMetadataConfigured())
+}
+
+func TestInsertExtraMetadata(t *testing.T) {
+	t.Parallel()
+	file := &File{Content: []map[string]interface{}{
+		{"kind": "Namespace", "metadata": map[interface{}]interface{}{"name": "kyma-installer", "labels": map[interface{}]interface{}{"istio-injection": "disabled"}}},
+		{"kind": "List"},
+	}}
+	insertExtraMetadata(file, map[string]string{"team": "kyma"}, map[string]string{"owner": "kyma-team"})
+
+	require.Equal(t, map[interface{}]interface{}{
+		"name":        "kyma-installer",
+		"labels":      map[interface{}]interface{}{"istio-injection": "disabled", "team": "kyma"},
+		"annotations": map[interface{}]interface{}{"owner": "kyma-team"},
+	}, file.Content[0]["metadata"])
+	require.NotContains(t, file.Content[1], "metadata", "Documents without metadata are left as they are")
+
+	i := &Installation{Options: &Options{ExtraLabels: map[string]string{"team": "kyma"}}}
+	meta := metav1.ObjectMeta{Name: "kyma-installer"}
+	i.addExtraMetadata(&meta)
+	require.Equal(t, map[string]string{"team": "kyma"}, meta.Labels)
+	require.Nil(t, meta.Annotations)
+}
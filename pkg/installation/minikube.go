@@ -0,0 +1,53 @@
+package installation
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/kyma-project/cli/internal/minikube"
+)
+
+// providerMinikube is the provider written to the cluster info by "kyma provision minikube"
+const providerMinikube = "minikube"
+
+// checkMinikubeRequirements verifies the settings of a Minikube cluster which Kyma depends on, such as the admission plugins of the API server.
+// Disabled admission webhooks fail the installation, the other discrepancies are logged as warnings. Both name the minikube commands fixing them.
+func (i *Installation) checkMinikubeRequirements() error {
+	if !i.Options.IsLocal || i.Options.LocalCluster == nil || i.Options.LocalCluster.Provider != providerMinikube {
+		return nil
+	}
+	settings, errs := i.readMinikubeSettings()
+	for _, err := range errs {
+		if i.currentStep != nil {
+			i.currentStep.LogErrorf("Warning: unable to verify the Minikube settings: %s", err)
+		}
+	}
+	discrepancies := minikube.Verify(settings)
+	if len(discrepancies) == 0 {
+		return nil
+	}
+
+	var required, optional []string
+	for _, d := range discrepancies {
+		if d.Required {
+			required = append(required, d.Problem)
+		} else {
+			optional = append(optional, d.Problem)
+		}
+	}
+	fix := strings.Join(minikube.FixCommands(i.Options.LocalCluster.Profile, discrepancies), " && ")
+	if len(required) > 0 {
+		return fmt.Errorf("Minikube does not meet the requirements of Kyma: %s. Fix the cluster with: %s", strings.Join(append(required, optional...), "; "), fix)
+	}
+	if i.currentStep != nil {
+		i.currentStep.LogErrorf("Warning: Minikube does not meet the requirements of Kyma: %s. Fix the cluster with: %s", strings.Join(optional, "; "), fix)
+	}
+	return nil
+}
+
+func (i *Installation) readMinikubeSettings() (minikube.Settings, []error) {
+	if i.minikubeSettings != nil {
+		return i.minikubeSettings()
+	}
+	return minikube.ReadSettings(i.Options.Verbose, i.Options.LocalCluster.Profile, i.Options.Timeout, i.K8s.Static(), i.K8s.RestConfig().Host, i.Options.LocalCluster.IP)
+}
@@ -0,0 +1,54 @@
+package installation
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/kyma-project/cli/internal/minikube"
+	stepMocks "github.com/kyma-project/cli/pkg/step/mocks"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckMinikubeRequirements(t *testing.T) {
+	t.Parallel()
+	local := func(settings minikube.Settings, errs ...error) (*Installation, *stepMocks.Step) {
+		s := &stepMocks.Step{}
+		return &Installation{
+			currentStep:      s,
+			minikubeSettings: func() (minikube.Settings, []error) { return settings, errs },
+			Options:          &Options{IsLocal: true, LocalCluster: &LocalCluster{Provider: "minikube", Profile: "kyma"}},
+		}, s
+	}
+
+	t.Run("Remote clusters and other providers are not checked", func(t *testing.T) {
+		i, s := local(minikube.Settings{DisabledAdmissionPlugins: []string{}})
+		i.Options.LocalCluster.Provider = "k3d"
+		require.NoError(t, i.checkMinikubeRequirements())
+		i.Options.IsLocal = false
+		i.Options.LocalCluster.Provider = "minikube"
+		require.NoError(t, i.checkMinikubeRequirements())
+		require.Empty(t, s.Errors())
+	})
+
+	t.Run("Requirements met", func(t *testing.T) {
+		i, s := local(minikube.Settings{DisabledAdmissionPlugins: []string{}, Addons: []string{"metrics-server"}})
+		require.NoError(t, i.checkMinikubeRequirements())
+		require.Empty(t, s.Errors())
+	})
+
+	t.Run("Disabled admission plugins", func(t *testing.T) {
+		i, _ := local(minikube.Settings{DisabledAdmissionPlugins: []string{"MutatingAdmissionWebhook", "ValidatingAdmissionWebhook"}, Addons: []string{"ingress"}})
+		err := i.checkMinikubeRequirements()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "the API server disables the admission plugins MutatingAdmissionWebhook, ValidatingAdmissionWebhook")
+		require.Contains(t, err.Error(), "minikube start --profile kyma --extra-config=apiserver.disable-admission-plugins= && minikube addons disable ingress --profile kyma")
+	})
+
+	t.Run("Conflicting addon and unreadable settings are warnings", func(t *testing.T) {
+		i, s := local(minikube.Settings{Addons: []string{"ingress"}}, errors.New("unable to find the API server pod"))
+		require.NoError(t, i.checkMinikubeRequirements())
+		require.Len(t, s.Errors(), 2)
+		require.Contains(t, s.Errors()[0], "unable to find the API server pod")
+		require.Contains(t, s.Errors()[1], "minikube addons disable ingress --profile kyma")
+	})
+}
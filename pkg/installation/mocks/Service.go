@@ -3,6 +3,8 @@
 package mocks
 
 import (
+	context "context"
+
 	installation "github.com/kyma-incubator/hydroform/install/installation"
 	mock "github.com/stretchr/testify/mock"
 
@@ -14,20 +16,20 @@ type Service struct {
 	mock.Mock
 }
 
-// CheckInstallationState provides a mock function with given fields: kubeconfig
-func (_m *Service) CheckInstallationState(kubeconfig *rest.Config) (installation.InstallationState, error) {
-	ret := _m.Called(kubeconfig)
+// CheckInstallationState provides a mock function with given fields: kubeconfig, name
+func (_m *Service) CheckInstallationState(kubeconfig *rest.Config, name string) (installation.InstallationState, error) {
+	ret := _m.Called(kubeconfig, name)
 
 	var r0 installation.InstallationState
-	if rf, ok := ret.Get(0).(func(*rest.Config) installation.InstallationState); ok {
-		r0 = rf(kubeconfig)
+	if rf, ok := ret.Get(0).(func(*rest.Config, string) installation.InstallationState); ok {
+		r0 = rf(kubeconfig, name)
 	} else {
 		r0 = ret.Get(0).(installation.InstallationState)
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(*rest.Config) error); ok {
-		r1 = rf(kubeconfig)
+	if rf, ok := ret.Get(1).(func(*rest.Config, string) error); ok {
+		r1 = rf(kubeconfig, name)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -35,13 +37,27 @@ func (_m *Service) CheckInstallationState(kubeconfig *rest.Config) (installation
 	return r0, r1
 }
 
-// TriggerInstallation provides a mock function with given fields: installerYaml, installerCRYaml, configuration
-func (_m *Service) TriggerInstallation(installerYaml string, installerCRYaml string, configuration installation.Configuration) error {
-	ret := _m.Called(installerYaml, installerCRYaml, configuration)
+// RetryInstallation provides a mock function with given fields: name
+func (_m *Service) RetryInstallation(name string) error {
+	ret := _m.Called(name)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string) error); ok {
+		r0 = rf(name)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// TriggerInstallation provides a mock function with given fields: ctx, installerYaml, installerCRYaml, configuration
+func (_m *Service) TriggerInstallation(ctx context.Context, installerYaml string, installerCRYaml string, configuration installation.Configuration) error {
+	ret := _m.Called(ctx, installerYaml, installerCRYaml, configuration)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(string, string, installation.Configuration) error); ok {
-		r0 = rf(installerYaml, installerCRYaml, configuration)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, installation.Configuration) error); ok {
+		r0 = rf(ctx, installerYaml, installerCRYaml, configuration)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -63,13 +79,13 @@ func (_m *Service) TriggerUninstall(kubeconfig *rest.Config) error {
 	return r0
 }
 
-// TriggerUpgrade provides a mock function with given fields: installerYaml, installerCRYaml, configuration
-func (_m *Service) TriggerUpgrade(installerYaml string, installerCRYaml string, configuration installation.Configuration) error {
-	ret := _m.Called(installerYaml, installerCRYaml, configuration)
+// TriggerUpgrade provides a mock function with given fields: ctx, installerYaml, installerCRYaml, configuration
+func (_m *Service) TriggerUpgrade(ctx context.Context, installerYaml string, installerCRYaml string, configuration installation.Configuration) error {
+	ret := _m.Called(ctx, installerYaml, installerCRYaml, configuration)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(string, string, installation.Configuration) error); ok {
-		r0 = rf(installerYaml, installerCRYaml, configuration)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, installation.Configuration) error); ok {
+		r0 = rf(ctx, installerYaml, installerCRYaml, configuration)
 	} else {
 		r0 = ret.Error(0)
 	}
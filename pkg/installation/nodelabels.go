@@ -0,0 +1,297 @@
+package installation
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	pkgErrors "github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apiErrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/client-go/kubernetes"
+)
+
+// installInfoNodesKey holds the labels and taints the installation added to the nodes, it is kept after the installation succeeded
+const installInfoNodesKey = "nodeChanges"
+
+// NodeChange records the labels and taints an installation added to a node, so that they can be removed when Kyma is deleted
+type NodeChange struct {
+	Node string `json:"node"`
+	// Labels maps the added or changed labels to their previous values, which are nil for labels the node did not have
+	Labels map[string]*string `json:"labels,omitempty"`
+	Taints []corev1.Taint     `json:"taints,omitempty"`
+}
+
+// nodeLabelingConfigured checks if labels or taints are added to the nodes
+func (i *Installation) nodeLabelingConfigured() bool {
+	return len(i.Options.NodeLabels) > 0 || len(i.Options.NodeTaints) > 0
+}
+
+// validateNodeLabeling ensures that the labels, the taints and the selector of the nodes to label are valid
+func (i *Installation) validateNodeLabeling() error {
+	for k, v := range i.Options.NodeLabels {
+		if errs := validation.IsQualifiedName(k); len(errs) > 0 {
+			return fmt.Errorf("invalid node label key '%s': %s", k, strings.Join(errs, ", "))
+		}
+		if errs := validation.IsValidLabelValue(v); len(errs) > 0 {
+			return fmt.Errorf("invalid node label value '%s': %s", v, strings.Join(errs, ", "))
+		}
+	}
+	for _, t := range i.Options.NodeTaints {
+		if _, err := parseTaint(t); err != nil {
+			return err
+		}
+	}
+	if i.Options.NodeLabelSelector == "" {
+		return nil
+	}
+	if !i.nodeLabelingConfigured() {
+		return fmt.Errorf("--node-selector-for-labeling requires --label-nodes or --taint-nodes")
+	}
+	if _, err := labels.Parse(i.Options.NodeLabelSelector); err != nil {
+		return fmt.Errorf("invalid value '%s' of --node-selector-for-labeling: %s", i.Options.NodeLabelSelector, err)
+	}
+	return nil
+}
+
+// parseTaint parses a taint in the format "key=value:Effect" or "key:Effect"
+func parseTaint(taint string) (corev1.Taint, error) {
+	t, err := parseTaintFormat("taint", taint)
+	if err != nil {
+		return corev1.Taint{}, err
+	}
+	return corev1.Taint{Key: t.Key, Value: t.Value, Effect: t.Effect}, nil
+}
+
+// labelNodes adds the labels and taints to all nodes matching the selector, before the Kyma Installer is activated.
+// Labels and taints the nodes already have are left untouched, and the changes are recorded in the install-info ConfigMap,
+// so that they are removed when the installation is cleaned up or Kyma is deleted. In dry-run mode, the changes are only displayed.
+func (i *Installation) labelNodes() error {
+	if !i.nodeLabelingConfigured() {
+		return nil
+	}
+	var taints []corev1.Taint
+	for _, t := range i.Options.NodeTaints {
+		taint, err := parseTaint(t)
+		if err != nil {
+			return err
+		}
+		taints = append(taints, taint)
+	}
+	keys := make([]string, 0, len(i.Options.NodeLabels))
+	for k := range i.Options.NodeLabels {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	nodeClient := i.K8s.Static().CoreV1().Nodes()
+	nodes, err := nodeClient.List(context.Background(), metav1.ListOptions{LabelSelector: i.Options.NodeLabelSelector})
+	if err != nil {
+		return pkgErrors.Wrap(err, "unable to list the nodes to label")
+	}
+	if len(nodes.Items) == 0 {
+		return fmt.Errorf("no nodes match the selector '%s' of --node-selector-for-labeling", i.Options.NodeLabelSelector)
+	}
+
+	var changed int
+	for _, node := range nodes.Items {
+		change := NodeChange{Node: node.Name, Labels: map[string]*string{}}
+		var ops []string
+		for _, k := range keys {
+			v := i.Options.NodeLabels[k]
+			old, ok := node.Labels[k]
+			if ok && old == v {
+				continue
+			}
+			if ok {
+				previous := old
+				change.Labels[k] = &previous
+			} else {
+				change.Labels[k] = nil
+			}
+			if node.Labels == nil {
+				node.Labels = map[string]string{}
+			}
+			node.Labels[k] = v
+			ops = append(ops, fmt.Sprintf("label %s=%s", k, v))
+		}
+		for _, t := range taints {
+			if hasTaint(node.Spec.Taints, t) {
+				continue
+			}
+			node.Spec.Taints = append(node.Spec.Taints, t)
+			change.Taints = append(change.Taints, t)
+			ops = append(ops, fmt.Sprintf("taint %s", t.ToString()))
+		}
+		if len(ops) == 0 {
+			continue
+		}
+		changed++
+		if i.Options.DryRun {
+			i.currentStep.LogInfof("Would add to the node '%s': %s", node.Name, strings.Join(ops, ", "))
+			continue
+		}
+		if _, err := nodeClient.Update(context.Background(), &node, metav1.UpdateOptions{}); err != nil {
+			return pkgErrors.Wrapf(err, "unable to label the node '%s'", node.Name)
+		}
+		i.nodeChanges = mergeNodeChanges(i.nodeChanges, []NodeChange{change})
+		i.currentStep.LogInfof("Added to the node '%s': %s", node.Name, strings.Join(ops, ", "))
+	}
+	if changed == 0 {
+		i.currentStep.LogInfof("The %d nodes already have the labels and taints", len(nodes.Items))
+		return nil
+	}
+	if i.Options.DryRun {
+		return nil
+	}
+	if err := i.saveInstallInfo(); err != nil {
+		i.currentStep.LogErrorf("Warning: unable to record the labels and taints added to the nodes, they are not removed when Kyma is deleted: %s", err)
+	}
+	return nil
+}
+
+// hasTaint checks if the taints contain the taint, taints are identified by their key, value and effect
+func hasTaint(taints []corev1.Taint, taint corev1.Taint) bool {
+	for _, t := range taints {
+		if t.Key == taint.Key && t.Value == taint.Value && t.Effect == taint.Effect {
+			return true
+		}
+	}
+	return false
+}
+
+// mergeNodeChanges adds the later changes to the recorded ones. The previous value of a label recorded first is kept, as it is the value before any installation changed it.
+func mergeNodeChanges(recorded, later []NodeChange) []NodeChange {
+	for _, l := range later {
+		n := -1
+		for m := range recorded {
+			if recorded[m].Node == l.Node {
+				n = m
+			}
+		}
+		if n < 0 {
+			recorded = append(recorded, NodeChange{Node: l.Node, Labels: map[string]*string{}})
+			n = len(recorded) - 1
+		}
+		if recorded[n].Labels == nil {
+			recorded[n].Labels = map[string]*string{}
+		}
+		for k, v := range l.Labels {
+			if _, ok := recorded[n].Labels[k]; !ok {
+				recorded[n].Labels[k] = v
+			}
+		}
+		for _, t := range l.Taints {
+			if !hasTaint(recorded[n].Taints, t) {
+				recorded[n].Taints = append(recorded[n].Taints, t)
+			}
+		}
+	}
+	return recorded
+}
+
+// RecordedNodeChanges reads the labels and taints added to the nodes by the installation from the install-info ConfigMap
+func RecordedNodeChanges(k8s kubernetes.Interface) ([]NodeChange, error) {
+	return recordedNodeChanges(k8s, clusterMetadata(k8s).InstallerNamespace)
+}
+
+// recordedNodeChanges reads the node changes from the install-info ConfigMap in the namespace of the Kyma Installer
+func recordedNodeChanges(k8s kubernetes.Interface, namespace string) ([]NodeChange, error) {
+	info, err := k8s.CoreV1().ConfigMaps(namespace).Get(context.Background(), installInfoName, metav1.GetOptions{})
+	if apiErrors.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, pkgErrors.Wrap(err, "unable to read the labels and taints added to the nodes")
+	}
+	data := info.Data[installInfoNodesKey]
+	if data == "" {
+		return nil, nil
+	}
+	var changes []NodeChange
+	if err := json.Unmarshal([]byte(data), &changes); err != nil {
+		return nil, pkgErrors.Wrapf(err, "unable to parse the node changes recorded in the ConfigMap '%s'", installInfoName)
+	}
+	return changes, nil
+}
+
+// RevertNodeChanges removes the recorded labels and taints from the nodes, changed labels get their previous values again.
+// Nodes which no longer exist are skipped. Once all nodes are reverted, the record is removed from the install-info ConfigMap.
+// It returns a description of each reverted node.
+func RevertNodeChanges(k8s kubernetes.Interface, changes []NodeChange) ([]string, error) {
+	return revertRecordedNodeChanges(k8s, clusterMetadata(k8s).InstallerNamespace, changes)
+}
+
+// revertRecordedNodeChanges reverts the node changes and removes their record from the install-info ConfigMap in the namespace of the Kyma Installer
+func revertRecordedNodeChanges(k8s kubernetes.Interface, namespace string, changes []NodeChange) ([]string, error) {
+	var reverted, failed []string
+	nodeClient := k8s.CoreV1().Nodes()
+	for _, c := range changes {
+		node, err := nodeClient.Get(context.Background(), c.Node, metav1.GetOptions{})
+		if apiErrors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			failed = append(failed, fmt.Sprintf("node %s: %s", c.Node, err))
+			continue
+		}
+		var ops []string
+		for k, previous := range c.Labels {
+			if previous == nil {
+				delete(node.Labels, k)
+				ops = append(ops, fmt.Sprintf("removed the label %s", k))
+			} else {
+				node.Labels[k] = *previous
+				ops = append(ops, fmt.Sprintf("restored the label %s=%s", k, *previous))
+			}
+		}
+		var taints []corev1.Taint
+		for _, t := range node.Spec.Taints {
+			if hasTaint(c.Taints, t) {
+				ops = append(ops, fmt.Sprintf("removed the taint %s", t.ToString()))
+				continue
+			}
+			taints = append(taints, t)
+		}
+		node.Spec.Taints = taints
+		if _, err := nodeClient.Update(context.Background(), node, metav1.UpdateOptions{}); err != nil {
+			failed = append(failed, fmt.Sprintf("node %s: %s", c.Node, err))
+			continue
+		}
+		sort.Strings(ops)
+		reverted = append(reverted, fmt.Sprintf("node %s: %s", c.Node, strings.Join(ops, ", ")))
+	}
+	if len(failed) > 0 {
+		return reverted, fmt.Errorf("unable to revert the labels and taints of the %s", strings.Join(failed, "; "))
+	}
+	return reverted, forgetNodeChanges(k8s, namespace)
+}
+
+// forgetNodeChanges removes the node changes from the install-info ConfigMap, which is deleted if it records nothing else
+func forgetNodeChanges(k8s kubernetes.Interface, namespace string) error {
+	configMaps := k8s.CoreV1().ConfigMaps(namespace)
+	info, err := configMaps.Get(context.Background(), installInfoName, metav1.GetOptions{})
+	if apiErrors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return pkgErrors.Wrap(err, "unable to remove the record of the node changes")
+	}
+	delete(info.Data, installInfoNodesKey)
+	for _, key := range []string{installInfoResourcesKey, installInfoStagesKey} {
+		if v := info.Data[key]; v != "" && v != "null" && v != "[]" && v != "{}" {
+			_, err = configMaps.Update(context.Background(), info, metav1.UpdateOptions{})
+			return pkgErrors.Wrap(err, "unable to remove the record of the node changes")
+		}
+	}
+	err = configMaps.Delete(context.Background(), installInfoName, metav1.DeleteOptions{})
+	if apiErrors.IsNotFound(err) {
+		return nil
+	}
+	return pkgErrors.Wrap(err, "unable to remove the record of the node changes")
+}
@@ -0,0 +1,105 @@
+package installation
+
+import (
+	"context"
+	"testing"
+
+	k8sMocks "github.com/kyma-project/cli/internal/kube/mocks"
+	stepMocks "github.com/kyma-project/cli/pkg/step/mocks"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestValidateNodeLabeling(t *testing.T) {
+	t.Parallel()
+	i := &Installation{Options: &Options{
+		NodeLabels:        map[string]string{"local-storage": "enabled"},
+		NodeTaints:        []string{"dedicated=istio:NoSchedule"},
+		NodeLabelSelector: "pool in (istio, infra)",
+	}}
+	require.NoError(t, i.validateNodeLabeling())
+
+	i.Options.NodeTaints = []string{"dedicated=istio"}
+	require.EqualError(t, i.validateNodeLabeling(), "invalid taint 'dedicated=istio': the effect is missing. Use the format 'key=value:Effect' or 'key:Effect'")
+
+	i.Options.NodeTaints = nil
+	i.Options.NodeLabels = map[string]string{"local storage": "enabled"}
+	require.Error(t, i.validateNodeLabeling())
+
+	i.Options.NodeLabels = nil
+	require.EqualError(t, i.validateNodeLabeling(), "--node-selector-for-labeling requires --label-nodes or --taint-nodes")
+}
+
+func TestLabelNodes(t *testing.T) {
+	t.Parallel()
+	static := fake.NewSimpleClientset(
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: installerNamespace()}},
+		&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "istio-1", Labels: map[string]string{"pool": "istio", "tier": "old"}}},
+		&corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: "istio-2", Labels: map[string]string{"pool": "istio", "local-storage": "enabled", "tier": "gold"}},
+			Spec:       corev1.NodeSpec{Taints: []corev1.Taint{{Key: "dedicated", Value: "istio", Effect: corev1.TaintEffectNoSchedule}}},
+		},
+		&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "worker", Labels: map[string]string{"pool": "default"}}},
+	)
+	kymaMock := &k8sMocks.KymaKube{}
+	kymaMock.On("Static").Return(static)
+	node := func(name string) *corev1.Node {
+		n, err := static.CoreV1().Nodes().Get(context.Background(), name, metav1.GetOptions{})
+		require.NoError(t, err)
+		return n
+	}
+	options := &Options{
+		NodeLabels:        map[string]string{"local-storage": "enabled", "tier": "gold"},
+		NodeTaints:        []string{"dedicated=istio:NoSchedule"},
+		NodeLabelSelector: "pool=istio",
+	}
+
+	// the dry run only renders the changes
+	s := &stepMocks.Step{}
+	dryRun := *options
+	dryRun.DryRun = true
+	i := &Installation{K8s: kymaMock, Options: &dryRun, currentStep: s}
+	require.NoError(t, i.labelNodes())
+	require.Equal(t, []string{"Would add to the node 'istio-1': label local-storage=enabled, label tier=gold, taint dedicated=istio:NoSchedule"}, s.Infos())
+	require.Equal(t, map[string]string{"pool": "istio", "tier": "old"}, node("istio-1").Labels)
+
+	// only the missing labels and taints are added, and only to the selected nodes
+	i = &Installation{K8s: kymaMock, Options: options, currentStep: &stepMocks.Step{}}
+	require.NoError(t, i.labelNodes())
+	require.Equal(t, map[string]string{"pool": "istio", "local-storage": "enabled", "tier": "gold"}, node("istio-1").Labels)
+	require.Equal(t, []corev1.Taint{{Key: "dedicated", Value: "istio", Effect: corev1.TaintEffectNoSchedule}}, node("istio-1").Spec.Taints)
+	require.Len(t, node("istio-2").Spec.Taints, 1)
+	require.Equal(t, map[string]string{"pool": "default"}, node("worker").Labels)
+
+	// applying them again changes nothing
+	s = &stepMocks.Step{}
+	again := &Installation{K8s: kymaMock, Options: options, currentStep: s}
+	require.NoError(t, again.labelNodes())
+	require.Equal(t, []string{"The 2 nodes already have the labels and taints"}, s.Infos())
+
+	// the record survives the successful installation
+	i.forgetCreatedResources()
+	changes, err := RecordedNodeChanges(static)
+	require.NoError(t, err)
+	old := "old"
+	require.Equal(t, []NodeChange{{
+		Node:   "istio-1",
+		Labels: map[string]*string{"local-storage": nil, "tier": &old},
+		Taints: []corev1.Taint{{Key: "dedicated", Value: "istio", Effect: corev1.TaintEffectNoSchedule}},
+	}}, changes)
+
+	// deleting Kyma removes what was added and the record
+	reverted, err := RevertNodeChanges(static, changes)
+	require.NoError(t, err)
+	require.Equal(t, []string{"node istio-1: removed the label local-storage, removed the taint dedicated=istio:NoSchedule, restored the label tier=old"}, reverted)
+	require.Equal(t, map[string]string{"pool": "istio", "tier": "old"}, node("istio-1").Labels)
+	require.Empty(t, node("istio-1").Spec.Taints)
+	require.Len(t, node("istio-2").Spec.Taints, 1, "taints the node had before must be kept")
+	_, err = static.CoreV1().ConfigMaps(installerNamespace()).Get(context.Background(), installInfoName, metav1.GetOptions{})
+	require.Error(t, err)
+
+	options.NodeLabelSelector = "pool=missing"
+	require.EqualError(t, i.labelNodes(), "no nodes match the selector 'pool=missing' of --node-selector-for-labeling")
+}
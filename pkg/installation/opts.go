@@ -1,6 +1,10 @@
 package installation
 
-import "time"
+import (
+	"time"
+
+	"github.com/kyma-project/cli/internal/release"
+)
 
 // Options holds the configuration options for the installation.
 type Options struct {
@@ -10,30 +14,62 @@ type Options struct {
 
 	// releaseVersion is set to the version of the release being installed.
 	releaseVersion string
-	// configVersion is set to the version of the configuration files being used.
-	configVersion string
-	// bucket is set to the name of the bucket where installation artifacts being stored.
-	bucket string
+	// release is set to the release whose artifacts are used as installation files, its version is the version of the configuration files.
+	release *release.Release
 	// remoteImage holds the image URL if the installation source is an image.
 	remoteImage string
 	// fromLocalSources is set if the installation source is local.
 	fromLocalSources bool
 
-	// LocalSrcPath specifies the absolute path to local sources.
+	// ReleaseArtifacts specifies the URL or the local directory the release artifacts are read from instead of the Kyma artifact buckets, e.g. a mirror or a copy for air-gapped installations.
+	// +optional
+	ReleaseArtifacts string `json:"releaseArtifacts,omitempty"`
+
+	// LocalSrcPath specifies the absolute path to local sources, either a directory or a .tar.gz, .tgz or .zip archive.
 	// +optional
 	LocalSrcPath string `json:"localSrcPath,omitempty"`
+	// KeepSources keeps the directory the archive of the local sources is extracted to after the installation.
+	// +optional
+	KeepSources bool `json:"keepSources,omitempty"`
 	// OverrideConfigs specifies the path to a yaml file with parameters to override.
 	// +optional
 	OverrideConfigs []string `json:"overrideConfigs,omitempty"`
+	// OverrideValues specifies single overrides in the format "[component:]key=value", which are applied by apply-config on top of the OverrideConfigs.
+	// +optional
+	OverrideValues []string `json:"overrideValues,omitempty"`
+	// Configs specifies the paths or URLs of YAML files with installer overrides and an optional Installation CR, which are merged in the given order.
+	// +optional
+	Configs []string `json:"configs,omitempty"`
+	// InstallerManifest specifies the path or URL of the Kyma Installer manifest applied instead of the one of the Kyma sources or release.
+	// +optional
+	InstallerManifest string `json:"installerManifest,omitempty"`
+	// UpgradeCRDs replaces the CRDs on the cluster which differ from the ones of the installer file, otherwise they are kept.
+	// +optional
+	UpgradeCRDs bool `json:"upgradeCRDs,omitempty"`
+	// DexUsers specifies additional static users of Dex in the format "email=...,password=...,groups=...".
+	// +optional
+	DexUsers []string `json:"-"`
+	// ChartValues specifies Helm values files of components in the format "component=path", which are applied as overrides of the component.
+	// +optional
+	ChartValues []string `json:"chartValues,omitempty"`
 	// ComponentsConfig specifies the path to a yaml file with components to override.
 	// +optional
 	ComponentsConfig string `json:"componentsConfig,omitempty"`
+	// EnableFeatures specifies optional features (e.g. monitoring) whose components are added to the component list.
+	// +optional
+	EnableFeatures []string `json:"enableFeatures,omitempty"`
+	// DisableFeatures specifies optional features (e.g. logging) whose components are removed from the component list.
+	// +optional
+	DisableFeatures []string `json:"disableFeatures,omitempty"`
 	// Password specifies the predefined cluster password.
 	// +optional
 	Password string `json:"password,omitempty"`
 	// Domain specifies the domain used for installation.
 	// +optional
 	Domain string `json:"domain,omitempty"`
+	// UseNipIO enables using the "<ip>.nip.io" wildcard domain of the ingress gateway IP, which is determined during the installation.
+	// +optional
+	UseNipIO bool `json:"useNipIO,omitempty"`
 	// TLSCert specifies the TLS certificate for the domain used for installation
 	// +optional
 	TLSCert string `json:"tlsCert,omitempty"`
@@ -50,6 +86,15 @@ type Options struct {
 	// Timeout specifies the time-out after which watching the installation progress stops.
 	// +optional
 	Timeout time.Duration `json:"timeout,omitempty"`
+	// Retries specifies how often a failed installation is triggered again before the CLI gives up.
+	// +optional
+	Retries int `json:"retries,omitempty"`
+	// ApplyTimeout specifies the time-out of each download of a release artifact and of the apply of the Kyma Installer.
+	// +optional
+	ApplyTimeout time.Duration `json:"applyTimeout,omitempty"`
+	// DockerTimeout specifies the time-out of the Docker build of the Kyma Installer image from local sources.
+	// +optional
+	DockerTimeout time.Duration `json:"dockerTimeout,omitempty"`
 	// CI enables skipping some steps not needed on CI/CD systems.
 	// +optional
 	CI bool `json:"ci,omitempty"`
@@ -59,6 +104,17 @@ type Options struct {
 	// NoWait determines if the Kyma installation should be waited to complete.
 	// +optional
 	NoWait bool `json:"noWait,omitempty"`
+	// AttachOnly only waits for an installation which was triggered before, e.g. by a separate run of the installer stage.
+	// The installation fails without changing the cluster if no installation was triggered or the Kyma Installer pod is not ready.
+	// +optional
+	AttachOnly bool `json:"attachOnly,omitempty"`
+	// SkipActivationCheck disables the check that the Kyma Installer picks up the Installation CR within a minute after it was activated, which is also done with NoWait.
+	// +optional
+	SkipActivationCheck bool `json:"skipActivationCheck,omitempty"`
+	// ExternalInstaller uses the Kyma Installer and the Installation CR deployed outside of the CLI, e.g. with GitOps.
+	// They are not applied, only the overrides are applied before the Installation CR is activated.
+	// +optional
+	ExternalInstaller bool `json:"externalInstaller,omitempty"`
 	// Verbose enables displaying details of actions triggered.
 	// +optional
 	Verbose bool `json:"verbose,omitempty"`
@@ -68,9 +124,135 @@ type Options struct {
 	// If source=master, defines how many commits from master branch are taken into account if artifacts for newer commits does not exist yet
 	// +optional
 	FallbackLevel int `json:"fallback_level,omitempty"`
-	// Profile specifies the Kyma installation profile (evaluation|production).
+	// Profile specifies the profile shipped with the CLI whose components and overrides are installed (evaluation|full|minimal|production).
 	// +optional
 	Profile string `json:"profile,omitempty"`
+	// PrePullImages enables pulling the component images into the local cluster before the components are installed.
+	// +optional
+	PrePullImages bool `json:"prePullImages,omitempty"`
+	// PrePullConcurrency specifies the number of images pulled in parallel if PrePullImages is set.
+	// +optional
+	PrePullConcurrency int `json:"prePullConcurrency,omitempty"`
+	// SkipPodVerification disables the check that the Deployments and StatefulSets of the Kyma namespaces are ready after the installation.
+	// +optional
+	SkipPodVerification bool `json:"skipPodVerification,omitempty"`
+	// WatchEvents enables displaying the warning events of the kyma-installer and kyma-system namespaces while the installation is waited for.
+	// +optional
+	WatchEvents bool `json:"watchEvents,omitempty"`
+	// PruneDocker enables removing the unused images and stopped containers from the Docker daemon of Minikube before the Kyma Installer image is built.
+	// +optional
+	PruneDocker bool `json:"pruneDocker,omitempty"`
+	// ExportManifests specifies the directory in which a copy of all applied manifests is stored.
+	// +optional
+	ExportManifests string `json:"exportManifests,omitempty"`
+	// RedactSecrets enables replacing the values of exported Secrets.
+	// +optional
+	RedactSecrets bool `json:"redactSecrets,omitempty"`
+	// DryRun prepares the installation without applying anything to the cluster.
+	// +optional
+	DryRun bool `json:"dryRun,omitempty"`
+	// Force enables installing or upgrading Kyma versions which are not supported by the CLI version, only a warning is logged for them.
+	// It also installs Kyma on clusters running software which conflicts with Kyma, such as another Istio.
+	// +optional
+	Force bool `json:"force,omitempty"`
+	// CIDRCheck enables warning about Service and pod CIDRs of the cluster which overlap with the routes of the local machine, such as the networks of a VPN.
+	// +optional
+	CIDRCheck bool `json:"cidrCheck,omitempty"`
+	// AllowedConflicts lists the conflicting software found on the cluster (istio, cert-manager, knative) which does not stop the installation.
+	// +optional
+	AllowedConflicts []string `json:"allowedConflicts,omitempty"`
+	// ContinueOnError runs all preparation stages even if one of them fails and reports all errors at the end.
+	// Stages depending on a failed stage are skipped, and the installation is only triggered if all stages succeeded.
+	// +optional
+	ContinueOnError bool `json:"continueOnError,omitempty"`
+	// FromScratch ignores the stages completed by a previous run of the installation which did not finish, so that all of them run again.
+	// +optional
+	FromScratch bool `json:"fromScratch,omitempty"`
+	// Explain logs the operations of each stage before it runs. In interactive mode, it asks for each stage whether to continue, skip or abort.
+	// Skipping a stage also skips the stages depending on it.
+	// +optional
+	Explain bool `json:"explain,omitempty"`
+	// StatusPort specifies the port of an HTTP endpoint serving the installation progress, 0 disables it.
+	// +optional
+	StatusPort int `json:"statusPort,omitempty"`
+	// StatusFile specifies the path of a JSON file which is rewritten with the installation progress at every check.
+	// +optional
+	StatusFile string `json:"statusFile,omitempty"`
+	// ForceUnlock enables overriding the lock held by another CLI instance changing the cluster.
+	// +optional
+	ForceUnlock bool `json:"forceUnlock,omitempty"`
+	// LockTTL specifies the time after which the lock held by another CLI instance is considered stale.
+	// +optional
+	LockTTL time.Duration `json:"lockTTL,omitempty"`
+	// InstallationName specifies the name of the Kyma Installation CR. If not set, the name is discovered from the cluster or the Installation CR file.
+	// +optional
+	InstallationName string `json:"installationName,omitempty"`
+	// ImagePullSecret specifies the path to a docker config JSON file with the credentials for a private registry.
+	// +optional
+	ImagePullSecret string `json:"imagePullSecret,omitempty"`
+	// RegistryServer specifies the private registry server used to build the image pull secret.
+	// +optional
+	RegistryServer string `json:"registryServer,omitempty"`
+	// RegistryUser specifies the user of the private registry used to build the image pull secret.
+	// +optional
+	RegistryUser string `json:"registryUser,omitempty"`
+	// RegistryPassword specifies the password of the private registry used to build the image pull secret.
+	// +optional
+	RegistryPassword string `json:"-"`
+	// ImagePullSecretNamespaces specifies additional namespaces (e.g. kyma-system) in which the image pull secret is created.
+	// +optional
+	ImagePullSecretNamespaces []string `json:"imagePullSecretNamespaces,omitempty"`
+	// InstallerNodeSelector specifies node labels the Kyma Installer pod is scheduled on.
+	// +optional
+	InstallerNodeSelector map[string]string `json:"installerNodeSelector,omitempty"`
+	// InstallerTolerations specifies taints the Kyma Installer pod tolerates, in the format "key=value:Effect" or "key:Effect".
+	// +optional
+	InstallerTolerations []string `json:"installerTolerations,omitempty"`
+	// InstallerPriorityClass specifies the priority class of the Kyma Installer pod.
+	// +optional
+	InstallerPriorityClass string `json:"installerPriorityClass,omitempty"`
+	// InstallerCPURequest specifies the CPU request of the Kyma Installer container, as a Kubernetes quantity (e.g. 200m).
+	// +optional
+	InstallerCPURequest string `json:"installerCPURequest,omitempty"`
+	// InstallerMemoryRequest specifies the memory request of the Kyma Installer container, as a Kubernetes quantity (e.g. 512Mi).
+	// +optional
+	InstallerMemoryRequest string `json:"installerMemoryRequest,omitempty"`
+	// InstallerCPULimit specifies the CPU limit of the Kyma Installer container.
+	// +optional
+	InstallerCPULimit string `json:"installerCPULimit,omitempty"`
+	// InstallerMemoryLimit specifies the memory limit of the Kyma Installer container.
+	// +optional
+	InstallerMemoryLimit string `json:"installerMemoryLimit,omitempty"`
+	// InstallerLogLevel specifies the log level of the Kyma Installer container, debug or info. It sets the Helm debug mode of the Kyma Installer.
+	// +optional
+	InstallerLogLevel string `json:"installerLogLevel,omitempty"`
+	// NodeLabels specifies labels added to the nodes before the Kyma Installer is activated.
+	// +optional
+	NodeLabels map[string]string `json:"nodeLabels,omitempty"`
+	// NodeTaints specifies taints added to the nodes before the Kyma Installer is activated, in the format "key=value:Effect" or "key:Effect".
+	// +optional
+	NodeTaints []string `json:"nodeTaints,omitempty"`
+	// NodeLabelSelector specifies the label selector of the nodes the labels and taints are added to, all nodes are changed if it is empty.
+	// +optional
+	NodeLabelSelector string `json:"nodeLabelSelector,omitempty"`
+	// CleanupOnFailure deletes the resources created by the installation in the reverse order if the installation fails after they were applied.
+	// +optional
+	CleanupOnFailure bool `json:"cleanupOnFailure,omitempty"`
+	// CreatePSP adds a PodSecurityPolicy for the Kyma Installer pod and allows the service account of the Kyma Installer to use it.
+	// +optional
+	CreatePSP bool `json:"createPSP,omitempty"`
+	// ExtraLabels specifies labels added to all resources created for the installation.
+	// +optional
+	ExtraLabels map[string]string `json:"extraLabels,omitempty"`
+	// ExtraAnnotations specifies annotations added to all resources created for the installation.
+	// +optional
+	ExtraAnnotations map[string]string `json:"extraAnnotations,omitempty"`
+	// BackupDir specifies the directory in which the installation state is backed up before an upgrade, an empty value disables the backup.
+	// +optional
+	BackupDir string `json:"backupDir,omitempty"`
+	// BackupRedactSecrets enables replacing the values of the backed up Secrets.
+	// +optional
+	BackupRedactSecrets bool `json:"backupRedactSecrets,omitempty"`
 }
 
 // LocalCluster includes the configuration options of a local cluster.
@@ -0,0 +1,92 @@
+package installation
+
+import (
+	"sort"
+	"strings"
+)
+
+// applyOrder ranks the kinds of the installer file in the order in which they must be applied, kinds not listed are ranked before the workloads.
+// Custom resources are applied last, once the CRDs and the Kyma Installer exist.
+var applyOrder = map[string]int{
+	"Namespace":                1,
+	"CustomResourceDefinition": 2,
+	"PodSecurityPolicy":        3,
+	"ServiceAccount":           3,
+	"ClusterRole":              3,
+	"ClusterRoleBinding":       3,
+	"Role":                     3,
+	"RoleBinding":              3,
+	"ConfigMap":                4,
+	"Secret":                   4,
+	"Deployment":               6,
+	"DaemonSet":                6,
+	"StatefulSet":              6,
+	"Job":                      6,
+}
+
+const (
+	// otherKindsOrder is the rank of built-in kinds not listed in applyOrder, such as Services or PersistentVolumeClaims
+	otherKindsOrder = 5
+	// customResourceOrder is the rank of the kinds defined by CRDs, like the Installation CR
+	customResourceOrder = 7
+)
+
+// sortDocuments orders the documents of the file so that each resource is applied after the resources it depends on.
+// Documents of the same rank keep the order of the file.
+func sortDocuments(file *File) {
+	if file == nil || len(file.Content) < 2 {
+		return
+	}
+	ranks := make([]int, len(file.Content))
+	for n, doc := range file.Content {
+		ranks[n] = documentOrder(doc)
+	}
+	index := make([]int, len(file.Content))
+	for n := range index {
+		index[n] = n
+	}
+	sort.SliceStable(index, func(a, b int) bool { return ranks[index[a]] < ranks[index[b]] })
+
+	content := make([]map[string]interface{}, len(index))
+	for n, from := range index {
+		content[n] = file.Content[from]
+	}
+	// the sources belong to the documents at the same index, they are only reordered if each document has one
+	if len(file.raw) == len(file.Content) {
+		raw := make([]string, len(index))
+		for n, from := range index {
+			raw[n] = file.raw[from]
+		}
+		file.raw = raw
+	}
+	file.Content = content
+}
+
+// documentOrder returns the rank of the document in the apply order
+func documentOrder(doc map[string]interface{}) int {
+	kind, _ := doc["kind"].(string)
+	if rank, ok := applyOrder[kind]; ok {
+		return rank
+	}
+	if apiVersion, _ := doc["apiVersion"].(string); customResource(apiVersion) {
+		return customResourceOrder
+	}
+	return otherKindsOrder
+}
+
+// builtinGroups are the API groups of the Kubernetes resources usually found in installer files
+var builtinGroups = []string{"", "apps", "batch", "policy", "autoscaling", "extensions"}
+
+// customResource checks if the API version belongs to a group defined by a CRD. The groups served by Kubernetes end with k8s.io, apart from the core and a few legacy groups.
+func customResource(apiVersion string) bool {
+	group := ""
+	if n := strings.LastIndex(apiVersion, "/"); n >= 0 {
+		group = apiVersion[:n]
+	}
+	for _, g := range builtinGroups {
+		if group == g {
+			return false
+		}
+	}
+	return !strings.HasSuffix(group, ".k8s.io")
+}
@@ -0,0 +1,88 @@
+package installation
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// unorderedInstaller lists the resources of the Kyma Installer in an order in which the first apply fails
+const unorderedInstaller = `apiVersion: installer.kyma-project.io/v1alpha1
+kind: Installation
+metadata:
+  name: kyma-installation
+  namespace: default
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: kyma-installer
+  namespace: kyma-installer
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: kyma-installer
+  namespace: kyma-installer
+---
+apiVersion: rbac.authorization.k8s.io/v1
+kind: ClusterRoleBinding
+metadata:
+  name: kyma-installer
+---
+apiVersion: v1
+kind: ServiceAccount
+metadata:
+  name: kyma-installer
+  namespace: kyma-installer
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: installer-config
+  namespace: kyma-installer
+---
+apiVersion: apiextensions.k8s.io/v1beta1
+kind: CustomResourceDefinition
+metadata:
+  name: installations.installer.kyma-project.io
+---
+apiVersion: v1
+kind: Namespace
+metadata:
+  name: kyma-installer
+`
+
+func TestSortDocuments(t *testing.T) {
+	t.Parallel()
+	docs, raw, _, err := decodeDocuments(strings.NewReader(unorderedInstaller))
+	require.NoError(t, err)
+	file := &File{Content: docs, raw: raw}
+
+	sortDocuments(file)
+	require.Equal(t, "Namespace 'kyma-installer', CustomResourceDefinition 'installations.installer.kyma-project.io', ClusterRoleBinding 'kyma-installer', ServiceAccount 'kyma-installer', ConfigMap 'installer-config', Service 'kyma-installer', Deployment 'kyma-installer', Installation 'kyma-installation'", documentKinds(file))
+
+	// the applied content follows the new order and keeps the unmodified sources
+	files, err := loadStringContent(map[string]*File{installerFile: file})
+	require.NoError(t, err)
+	applied, _, _, err := decodeDocuments(strings.NewReader(files[installerFile].StringContent))
+	require.NoError(t, err)
+	require.Equal(t, file.Content, applied)
+	require.True(t, strings.HasPrefix(files[installerFile].StringContent, "apiVersion: v1\nkind: Namespace\n"))
+
+	// an ordered file is not changed
+	ordered := &File{Content: append([]map[string]interface{}{}, file.Content...)}
+	sortDocuments(ordered)
+	require.Equal(t, file.Content, ordered.Content)
+}
+
+func TestCustomResource(t *testing.T) {
+	t.Parallel()
+	require.True(t, customResource("installer.kyma-project.io/v1alpha1"))
+	require.True(t, customResource("networking.istio.io/v1alpha3"))
+	require.False(t, customResource("v1"))
+	require.False(t, customResource("apps/v1"))
+	require.False(t, customResource("rbac.authorization.k8s.io/v1"))
+	require.False(t, customResource("apiextensions.k8s.io/v1beta1"))
+}
@@ -0,0 +1,129 @@
+package installation
+
+import (
+	"bufio"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/kyma-project/cli/internal/github"
+	"github.com/kyma-project/cli/pkg/docker"
+	"github.com/kyma-project/cli/pkg/step"
+)
+
+// imageListAsset is the release artifact listing all images of a release, one per line
+const imageListAsset = "kyma-images.txt"
+
+// imageReferencePattern matches image references with a tag in the charts, template expressions are skipped
+var imageReferencePattern = regexp.MustCompile(`image:\s*["']?([^\s"'{}]+:[^\s"'{}]+)["']?\s*$`)
+
+// prePullImages pulls the images of the Kyma components into the docker daemon of the local cluster, so that the components do not wait for them one by one.
+// Pre-pulling is best effort: failures are reported as warnings and never fail the installation.
+func (i *Installation) prePullImages() {
+	if !i.Options.IsLocal {
+		i.currentStep.LogInfof("Skipped pre-pulling the component images, as it is only supported on local clusters")
+		return
+	}
+
+	images, err := i.componentImages()
+	if err != nil {
+		i.currentStep.LogErrorf("Warning: skipped pre-pulling the component images, as they could not be determined: %s", err)
+		return
+	}
+	if len(images) == 0 {
+		i.currentStep.LogInfof("Skipped pre-pulling the component images, as no images were found")
+		return
+	}
+
+	if i.Docker == nil {
+		if i.Docker, err = docker.NewKymaClient(i.Options.IsLocal, i.Options.Verbose, i.Options.LocalCluster.Profile, i.Options.Timeout); err != nil {
+			i.currentStep.LogErrorf("Warning: skipped pre-pulling the component images, as the docker daemon of the cluster is not reachable: %s", err)
+			return
+		}
+	}
+
+	i.pullImages(i.newStep("Pre-pulling component images"), images)
+}
+
+// pullImages pulls the images, showing the progress in the given step
+func (i *Installation) pullImages(s step.Step, images []string) {
+	errs := i.Docker.PullImages(images, i.Options.PrePullConcurrency, func(done, total int) {
+		s.Status(fmt.Sprintf("Pre-pulling component images (%d/%d)", done, total))
+	})
+	for _, err := range errs {
+		s.LogErrorf("Warning: %s", err)
+	}
+	if len(errs) > 0 {
+		s.Successf("%d of %d component images pre-pulled, the installer pulls the missing ones", len(images)-len(errs), len(images))
+		return
+	}
+	s.Successf("%d component images pre-pulled", len(images))
+}
+
+// componentImages returns the images used by the components, either from the charts of the local sources or from the image list published with the release
+func (i *Installation) componentImages() ([]string, error) {
+	if i.Options.fromLocalSources {
+		return chartImages(filepath.Join(i.Options.LocalSrcPath, "resources"))
+	}
+
+	reader, _, err := i.Options.release.Artifact(imageListAsset).Open(github.NewClient(5 * time.Second))
+	if err != nil {
+		return nil, fmt.Errorf("no image list is published for this release: %s", err)
+	}
+	defer reader.Close()
+
+	images := map[string]bool{}
+	scanner := bufio.NewScanner(reader)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		images[line] = true
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return sortedImages(images), nil
+}
+
+// chartImages collects the image references with a fixed tag from all YAML files of the charts
+func chartImages(chartsDir string) ([]string, error) {
+	images := map[string]bool{}
+	err := filepath.Walk(chartsDir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() || (filepath.Ext(path) != ".yaml" && filepath.Ext(path) != ".yml") {
+			return nil
+		}
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		for _, line := range strings.Split(string(data), "\n") {
+			if m := imageReferencePattern.FindStringSubmatch(line); m != nil {
+				images[m[1]] = true
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return sortedImages(images), nil
+}
+
+func sortedImages(images map[string]bool) []string {
+	result := make([]string, 0, len(images))
+	for image := range images {
+		result = append(result, image)
+	}
+	sort.Strings(result)
+	return result
+}
@@ -0,0 +1,96 @@
+package installation
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/kyma-project/cli/pkg/installation/mocks"
+	"github.com/kyma-project/cli/pkg/step"
+	stepMocks "github.com/kyma-project/cli/pkg/step/mocks"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChartImages(t *testing.T) {
+	t.Parallel()
+	dir, err := ioutil.TempDir("", "chart-images")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "core", "templates"), 0700))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "core", "values.yaml"), []byte(`
+image: "eu.gcr.io/kyma-project/core:1.0"
+sidecar:
+  image: eu.gcr.io/kyma-project/sidecar:2.1
+untagged:
+  image: eu.gcr.io/kyma-project/untagged
+`), 0600))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "core", "templates", "deployment.yaml"), []byte(`
+      containers:
+      - image: "{{ .Values.global.containerRegistry.path }}/core:{{ .Values.version }}"
+      - image: eu.gcr.io/kyma-project/core:1.0
+`), 0600))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "core", "README.md"), []byte("image: ignored/readme:1.0\n"), 0600))
+
+	images, err := chartImages(dir)
+	require.NoError(t, err)
+	require.Equal(t, []string{"eu.gcr.io/kyma-project/core:1.0", "eu.gcr.io/kyma-project/sidecar:2.1"}, images)
+}
+
+func TestPrePullImages(t *testing.T) {
+	t.Parallel()
+	dir, err := ioutil.TempDir("", "pre-pull-images")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "resources", "core"), 0700))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "resources", "core", "values.yaml"), []byte("image: example.com/core:1.0\n"), 0600))
+
+	// failed pulls do not fail the installation
+	d := &mocks.KymaClient{}
+	d.On("PullImages", []string{"example.com/core:1.0"}, 4, mock.Anything).Return([]error{errors.New("pull failed")})
+	i := &Installation{
+		Docker:      d,
+		Factory:     step.Factory{NonInteractive: true},
+		currentStep: &stepMocks.Step{},
+		Options:     &Options{IsLocal: true, fromLocalSources: true, LocalSrcPath: dir, PrePullConcurrency: 4},
+	}
+	i.prePullImages()
+	d.AssertExpectations(t)
+
+	// remote clusters are skipped
+	d = &mocks.KymaClient{}
+	previous := &stepMocks.Step{}
+	i.Docker = d
+	i.currentStep = previous
+	i.Options.IsLocal = false
+	i.prePullImages()
+	d.AssertNotCalled(t, "PullImages", mock.Anything, mock.Anything, mock.Anything)
+	require.Equal(t, []string{"Skipped pre-pulling the component images, as it is only supported on local clusters"}, previous.Infos())
+}
+
+func TestPullImages(t *testing.T) {
+	t.Parallel()
+	images := []string{"example.com/core:1.0", "example.com/sidecar:2.1"}
+	d := &mocks.KymaClient{}
+	d.On("PullImages", images, 2, mock.Anything).Return([]error{errors.New("pull failed")}).
+		Run(func(args mock.Arguments) {
+			progress := args.Get(2).(func(int, int))
+			progress(1, 2)
+			progress(2, 2)
+		})
+	i := &Installation{Docker: d, Options: &Options{PrePullConcurrency: 2}}
+
+	// the warnings are shown without --verbose
+	s := &stepMocks.Step{}
+	i.pullImages(s, images)
+	require.Equal(t, []string{
+		"Pre-pulling component images (1/2)",
+		"Pre-pulling component images (2/2)",
+		"1 of 2 component images pre-pulled, the installer pulls the missing ones",
+	}, s.Statuses())
+	require.Equal(t, []string{"Warning: pull failed"}, s.Errors())
+	require.True(t, s.IsSuccessful())
+}
@@ -0,0 +1,250 @@
+package installation
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/kyma-project/kyma/components/kyma-operator/pkg/apis/installer/v1alpha1"
+	pkgErrors "github.com/pkg/errors"
+	"gopkg.in/yaml.v2"
+)
+
+// Profile is a named set of components and overrides shipped with the CLI
+type Profile struct {
+	Name        string `yaml:"name"`
+	Description string `yaml:"description,omitempty"`
+	// InstallerProfile is the profile of the Kyma Installer set in the Installation CR, an empty value keeps the one of the release
+	InstallerProfile string `yaml:"installerProfile,omitempty"`
+	// Components is the component list in the format of --components, an empty value keeps the components of the release
+	Components string `yaml:"components,omitempty"`
+	// Overrides holds the override ConfigMaps in the format of --override, they are applied before the --override files
+	Overrides string `yaml:"overrides,omitempty"`
+}
+
+// profiles holds the embedded profiles and the ones loaded from a profile directory, by name
+var profiles = copyProfiles(embeddedProfiles)
+
+// embeddedProfiles holds the profiles shipped with the CLI, by name
+var embeddedProfiles = map[string]Profile{
+	"minimal": {
+		Name:             "minimal",
+		Description:      "Core, Dex and the API Gateway with reduced resource requests, for demos on small machines.",
+		InstallerProfile: "evaluation",
+		Components:       minimalComponents,
+		Overrides:        minimalOverrides,
+	},
+	"evaluation": {
+		Name:             "evaluation",
+		Description:      "All components of the release with the reduced settings of the evaluation profile of the Kyma Installer.",
+		InstallerProfile: "evaluation",
+	},
+	"production": {
+		Name:             "production",
+		Description:      "All components of the release with the settings of the production profile of the Kyma Installer.",
+		InstallerProfile: "production",
+	},
+	"full": {
+		Name:        "full",
+		Description: "All components of the release with the default settings of the release.",
+	},
+}
+
+const minimalComponents = `components:
+  - name: "cluster-essentials"
+    namespace: "kyma-system"
+  - name: "istio"
+    namespace: "istio-system"
+  - name: "xip-patch"
+    namespace: "kyma-installer"
+  - name: "istio-kyma-patch"
+    namespace: "istio-system"
+  - name: "dex"
+    namespace: "kyma-system"
+  - name: "ory"
+    namespace: "kyma-system"
+  - name: "api-gateway"
+    namespace: "kyma-system"
+  - name: "core"
+    namespace: "kyma-system"
+`
+
+const minimalOverrides = `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: minimal-profile-istio-overrides
+  namespace: kyma-installer
+  labels:
+    installer: overrides
+    component: istio
+    kyma-project.io/installation: ""
+data:
+  kyma_istio_operator.components.pilot.k8s.resources.requests.cpu: "50m"
+  kyma_istio_operator.components.pilot.k8s.resources.requests.memory: "128Mi"
+  kyma_istio_operator.components.ingressGateways.k8s.resources.requests.cpu: "50m"
+  kyma_istio_operator.components.ingressGateways.k8s.resources.requests.memory: "64Mi"
+  kyma_istio_operator.components.ingressGateways.k8s.hpaSpec.maxReplicas: "1"
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: minimal-profile-dex-overrides
+  namespace: kyma-installer
+  labels:
+    installer: overrides
+    component: dex
+    kyma-project.io/installation: ""
+data:
+  dex.resources.requests.cpu: "10m"
+  dex.resources.requests.memory: "32Mi"
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: minimal-profile-ory-overrides
+  namespace: kyma-installer
+  labels:
+    installer: overrides
+    component: ory
+    kyma-project.io/installation: ""
+data:
+  global.ory.hydra.persistence.enabled: "false"
+  oathkeeper.deployment.resources.requests.cpu: "10m"
+  oathkeeper.deployment.resources.requests.memory: "32Mi"
+  hydra.deployment.resources.requests.cpu: "10m"
+  hydra.deployment.resources.requests.memory: "32Mi"
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: minimal-profile-api-gateway-overrides
+  namespace: kyma-installer
+  labels:
+    installer: overrides
+    component: api-gateway
+    kyma-project.io/installation: ""
+data:
+  deployment.resources.requests.cpu: "10m"
+  deployment.resources.requests.memory: "32Mi"
+`
+
+// Profiles returns all profiles shipped with the CLI and loaded from a profile directory, sorted by name
+func Profiles() []Profile {
+	return sortedProfiles(profiles)
+}
+
+// EmbeddedProfiles returns the profiles shipped with the CLI, without the ones loaded from a profile directory, sorted by name
+func EmbeddedProfiles() []Profile {
+	return sortedProfiles(embeddedProfiles)
+}
+
+func sortedProfiles(m map[string]Profile) []Profile {
+	result := make([]Profile, 0, len(m))
+	for _, p := range m {
+		result = append(result, p)
+	}
+	sort.Slice(result, func(a, b int) bool { return result[a].Name < result[b].Name })
+	return result
+}
+
+func copyProfiles(m map[string]Profile) map[string]Profile {
+	result := make(map[string]Profile, len(m))
+	for name, p := range m {
+		result[name] = p
+	}
+	return result
+}
+
+// LoadProfiles reads the profiles of a directory as with ReadProfiles. They replace the embedded profiles of the same name, or are added as new profiles.
+func LoadProfiles(dir string) error {
+	loaded, err := ReadProfiles(dir)
+	if err != nil {
+		return err
+	}
+	result := copyProfiles(embeddedProfiles)
+	for _, p := range loaded {
+		result[p.Name] = p
+	}
+	profiles = result
+	return nil
+}
+
+// ReadProfiles reads the profiles of a directory without applying them. Each YAML file holds one profile in the format written by "kyma defaults --write-dir",
+// profiles without a name are named after their file.
+func ReadProfiles(dir string) ([]Profile, error) {
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, pkgErrors.Wrap(err, "unable to read the profile directory")
+	}
+	var result []Profile
+	for _, f := range files {
+		ext := filepath.Ext(f.Name())
+		if f.IsDir() || (ext != ".yaml" && ext != ".yml") {
+			continue
+		}
+		path := filepath.Join(dir, f.Name())
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			return nil, pkgErrors.Wrapf(err, "unable to read the profile '%s'", path)
+		}
+		var p Profile
+		if err := yaml.UnmarshalStrict(data, &p); err != nil {
+			return nil, pkgErrors.Wrapf(err, "unable to parse the profile '%s'", path)
+		}
+		if p.Name == "" {
+			p.Name = strings.TrimSuffix(f.Name(), ext)
+		}
+		if _, err := p.ComponentList(); err != nil {
+			return nil, err
+		}
+		result = append(result, p)
+	}
+	sort.Slice(result, func(a, b int) bool { return result[a].Name < result[b].Name })
+	return result, nil
+}
+
+// profileNames returns the sorted names of all profiles
+func profileNames() []string {
+	var names []string
+	for _, p := range Profiles() {
+		names = append(names, p.Name)
+	}
+	return names
+}
+
+// ComponentList parses the component list of the profile, an empty list keeps the components of the release
+func (p Profile) ComponentList() ([]v1alpha1.KymaComponent, error) {
+	if p.Components == "" {
+		return nil, nil
+	}
+	var config ComponentsConfig
+	if err := yaml.Unmarshal([]byte(p.Components), &config); err != nil {
+		return nil, pkgErrors.Wrapf(err, "unable to parse the component list of the profile '%s'", p.Name)
+	}
+	return config.Components, nil
+}
+
+// LoadComponents returns the component list replacing the one of the release: the list of the components file if given, otherwise the list of the profile.
+// An empty list keeps the components of the release.
+func LoadComponents(componentsConfig, profile string) ([]v1alpha1.KymaComponent, error) {
+	if componentsConfig != "" {
+		return LoadComponentsConfig(componentsConfig)
+	}
+	if p, ok := profiles[profile]; ok {
+		return p.ComponentList()
+	}
+	return []v1alpha1.KymaComponent{}, nil
+}
+
+// componentsSource describes where the component list of the installation comes from, or returns an empty string if the list of the release is used
+func (i *Installation) componentsSource() string {
+	if i.Options.ComponentsConfig != "" {
+		return fmt.Sprintf("--components %s", i.Options.ComponentsConfig)
+	}
+	if p, ok := profiles[i.Options.Profile]; ok && p.Components != "" {
+		return fmt.Sprintf("--profile %s", p.Name)
+	}
+	return ""
+}
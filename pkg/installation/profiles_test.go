@@ -0,0 +1,125 @@
+package installation
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestProfiles(t *testing.T) {
+	t.Parallel()
+	require.Equal(t, []string{"evaluation", "full", "minimal", "production"}, profileNames())
+
+	for _, p := range Profiles() {
+		t.Run(p.Name, func(t *testing.T) {
+			_, err := p.ComponentList()
+			require.NoError(t, err)
+
+			i := &Installation{Options: &Options{Profile: p.Name}}
+			_, err = i.loadConfigurationSources(map[string]*File{})
+			require.NoError(t, err)
+		})
+	}
+
+	components, err := profiles["minimal"].ComponentList()
+	require.NoError(t, err)
+	require.Equal(t, []string{"cluster-essentials", "istio", "xip-patch", "istio-kyma-patch", "dex", "ory", "api-gateway", "core"}, componentNames(components))
+}
+
+func TestProfileOverrides(t *testing.T) {
+	t.Parallel()
+	dir, err := ioutil.TempDir("", "kyma-profile-")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	override := filepath.Join(dir, "dex.yaml")
+	require.NoError(t, ioutil.WriteFile(override, []byte(`apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: dex-overrides
+  namespace: kyma-installer
+  labels:
+    installer: overrides
+    component: dex
+    kyma-project.io/installation: ""
+data:
+  dex.resources.requests.cpu: "20m"
+`), 0600))
+
+	i := &Installation{Options: &Options{Profile: "minimal", OverrideConfigs: []string{override}}}
+	sources, err := i.loadConfigurationSources(map[string]*File{})
+	require.NoError(t, err)
+	configuration, origins := mergeConfigurations(sources)
+
+	// the override files take precedence over the profile
+	require.Equal(t, "--override "+override, origins["dex"]["dex.resources.requests.cpu"])
+	require.Equal(t, "--profile minimal", origins["dex"]["dex.resources.requests.memory"])
+	for _, c := range configuration.ComponentConfiguration {
+		if c.Component != "dex" {
+			continue
+		}
+		entry, ok := c.Configuration.Get("dex.resources.requests.cpu")
+		require.True(t, ok)
+		require.Equal(t, "20m", entry.Value)
+	}
+}
+
+func TestLoadComponents(t *testing.T) {
+	t.Parallel()
+	dir, err := ioutil.TempDir("", "kyma-profile-")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	file := filepath.Join(dir, "components.yaml")
+	require.NoError(t, ioutil.WriteFile(file, []byte("components:\n  - name: core\n    namespace: kyma-system\n"), 0600))
+
+	components, err := LoadComponents(file, "minimal")
+	require.NoError(t, err)
+	require.Equal(t, []string{"core"}, componentNames(components), "The components file takes precedence over the profile")
+
+	components, err = LoadComponents("", "minimal")
+	require.NoError(t, err)
+	require.Len(t, components, 8)
+
+	components, err = LoadComponents("", "evaluation")
+	require.NoError(t, err)
+	require.Empty(t, components, "Profiles without a component list keep the components of the release")
+
+	i := &Installation{Options: &Options{Profile: "minimal"}}
+	require.Equal(t, "--profile minimal", i.componentsSource())
+	i = &Installation{Options: &Options{Profile: "full"}}
+	require.Equal(t, "", i.componentsSource())
+}
+
+func TestLoadProfiles(t *testing.T) {
+	// not parallel: the loaded profiles are package level
+	defer func() { profiles = copyProfiles(embeddedProfiles) }()
+	dir, err := ioutil.TempDir("", "kyma-profiles-")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "minimal.yaml"), []byte(`name: minimal
+description: Core only.
+components: |
+  components:
+    - name: "core"
+      namespace: "kyma-system"
+`), 0600))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "tiny.yml"), []byte(`installerProfile: evaluation`), 0600))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "README.md"), []byte(`not a profile`), 0600))
+	require.NoError(t, LoadProfiles(dir))
+
+	require.Equal(t, []string{"evaluation", "full", "minimal", "production", "tiny"}, profileNames())
+	components, err := profiles["minimal"].ComponentList()
+	require.NoError(t, err)
+	require.Equal(t, []string{"core"}, componentNames(components), "the loaded profile replaces the embedded one")
+	require.Equal(t, "evaluation", profiles["tiny"].InstallerProfile, "the profile is named after the file")
+	require.Equal(t, embeddedProfiles["minimal"], EmbeddedProfiles()[2], "the embedded profiles are kept")
+
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "typo.yaml"), []byte(`installerProfiles: evaluation`), 0600))
+	require.Error(t, LoadProfiles(dir), "unknown fields are rejected")
+	require.Error(t, LoadProfiles(filepath.Join(dir, "missing")))
+}
@@ -0,0 +1,129 @@
+package installation
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// etaWindow is the number of most recent component durations used to estimate the remaining time
+const etaWindow = 5
+
+// ComponentDuration holds the time it took to install a single Kyma component.
+type ComponentDuration struct {
+	// Name is the name of the component.
+	Name string
+	// Duration indicates how long the installation of the component took.
+	Duration time.Duration
+}
+
+// progress estimates the installation progress based on the component list of the Installation CR
+// and the component names contained in the descriptions reported by the Kyma Installer.
+type progress struct {
+	components []string
+	// current is the index of the component being installed, -1 if none was seen yet
+	current   int
+	started   time.Time
+	durations []ComponentDuration
+}
+
+func newProgress(components []string) *progress {
+	return &progress{components: components, current: -1}
+}
+
+// update maps the installer description to a component and records the duration of the previous component.
+// It returns false if the description does not refer to a known component.
+func (p *progress) update(description string, now time.Time) bool {
+	idx := p.componentIndex(description)
+	if idx < 0 || idx == p.current {
+		return false
+	}
+	p.finishCurrent(now)
+	p.current = idx
+	p.started = now
+	return true
+}
+
+// finish records the duration of the last component once the installation is done
+func (p *progress) finish(now time.Time) {
+	p.finishCurrent(now)
+	p.current = len(p.components)
+}
+
+func (p *progress) finishCurrent(now time.Time) {
+	if p.current >= 0 && p.current < len(p.components) {
+		p.durations = append(p.durations, ComponentDuration{Name: p.components[p.current], Duration: now.Sub(p.started)})
+	}
+}
+
+func (p *progress) componentIndex(description string) int {
+	fields := strings.Fields(strings.ToLower(description))
+	for n, f := range fields {
+		if strings.TrimSuffix(f, ":") != "component" || n+1 >= len(fields) {
+			continue
+		}
+		name := strings.Trim(fields[n+1], ":'\".,")
+		for idx, c := range p.components {
+			if strings.EqualFold(c, name) {
+				return idx
+			}
+		}
+	}
+	return -1
+}
+
+// eta estimates the remaining time based on the moving average of the most recent component durations
+func (p *progress) eta() (time.Duration, bool) {
+	if len(p.durations) == 0 {
+		return 0, false
+	}
+	recent := p.durations
+	if len(recent) > etaWindow {
+		recent = recent[len(recent)-etaWindow:]
+	}
+	var sum time.Duration
+	for _, d := range recent {
+		sum += d.Duration
+	}
+	remaining := len(p.components) - p.current
+	return sum / time.Duration(len(recent)) * time.Duration(remaining), true
+}
+
+// String returns the progress in the format "(done/total components, ETA ...)"
+func (p *progress) String() string {
+	done := p.current
+	if done < 0 {
+		done = 0
+	}
+	status := fmt.Sprintf("(%d/%d components", done, len(p.components))
+	if eta, ok := p.eta(); ok {
+		status += fmt.Sprintf(", ETA %s", eta.Round(time.Minute))
+	}
+	return status + ")"
+}
+
+// installationComponents returns the names of the components listed in the Installation CR on the cluster
+func (i *Installation) installationComponents() []string {
+	cr, err := i.K8s.Dynamic().Resource(i.installationGVR()).Namespace(i.installationNamespace()).Get(context.Background(), i.installationName(), metav1.GetOptions{})
+	if err != nil {
+		return nil
+	}
+	components, _, err := unstructured.NestedSlice(cr.Object, "spec", "components")
+	if err != nil {
+		return nil
+	}
+
+	var names []string
+	for _, c := range components {
+		if component, ok := c.(map[string]interface{}); ok {
+			if name, ok := component["name"].(string); ok && name != "" {
+				names = append(names, name)
+			}
+		}
+	}
+	return names
+}
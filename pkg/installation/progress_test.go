@@ -0,0 +1,68 @@
+package installation
+
+import (
+	"testing"
+	"time"
+
+	k8sMocks "github.com/kyma-project/cli/internal/kube/mocks"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	fakeDynamic "k8s.io/client-go/dynamic/fake"
+)
+
+func TestProgress(t *testing.T) {
+	t.Parallel()
+	start := time.Now()
+	p := newProgress([]string{"cluster-essentials", "istio", "xip-patch", "core"})
+	require.Equal(t, "(0/4 components)", p.String())
+
+	require.True(t, p.update("install component cluster-essentials", start))
+	require.Equal(t, "(0/4 components)", p.String())
+
+	// same component or unknown descriptions do not change the progress
+	require.False(t, p.update("install component cluster-essentials", start.Add(time.Minute)))
+	require.False(t, p.update("Kyma is being prepared", start.Add(time.Minute)))
+
+	require.True(t, p.update("Upgrade component: istio", start.Add(2*time.Minute)))
+	require.Equal(t, "(1/4 components, ETA 6m0s)", p.String())
+
+	require.True(t, p.update("install component core", start.Add(6*time.Minute)))
+	require.Equal(t, "(3/4 components, ETA 3m0s)", p.String())
+
+	p.finish(start.Add(7 * time.Minute))
+	require.Equal(t, []ComponentDuration{
+		{Name: "cluster-essentials", Duration: 2 * time.Minute},
+		{Name: "istio", Duration: 4 * time.Minute},
+		{Name: "core", Duration: 1 * time.Minute},
+	}, p.durations)
+}
+
+func TestInstallationComponents(t *testing.T) {
+	t.Parallel()
+	cr := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "installer.kyma-project.io/v1alpha1",
+		"kind":       "Installation",
+		"metadata": map[string]interface{}{
+			"name":      "kyma-installation",
+			"namespace": "default",
+		},
+		"spec": map[string]interface{}{
+			"components": []interface{}{
+				map[string]interface{}{"name": "cluster-essentials", "namespace": "kyma-system"},
+				map[string]interface{}{"name": "istio", "namespace": "istio-system"},
+			},
+		},
+	}}
+
+	kymaMock := k8sMocks.KymaKube{}
+	kymaMock.On("Dynamic").Return(fakeDynamic.NewSimpleDynamicClient(runtime.NewScheme(), cr))
+	i := &Installation{K8s: &kymaMock, Options: &Options{}}
+	require.Equal(t, []string{"cluster-essentials", "istio"}, i.installationComponents())
+
+	// Installation CR is missing
+	kymaMock = k8sMocks.KymaKube{}
+	kymaMock.On("Dynamic").Return(fakeDynamic.NewSimpleDynamicClient(runtime.NewScheme()))
+	i = &Installation{K8s: &kymaMock, Options: &Options{}}
+	require.Empty(t, i.installationComponents())
+}
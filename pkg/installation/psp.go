@@ -0,0 +1,112 @@
+package installation
+
+import (
+	"github.com/pkg/errors"
+)
+
+const (
+	// installerPSPName is the name of the PodSecurityPolicy, ClusterRole and ClusterRoleBinding created with --create-psp
+	installerPSPName        = "kyma-installer"
+	installerPSPRoleName    = "kyma-installer-psp"
+	defaultInstallerAccount = "kyma-installer"
+)
+
+// insertInstallerPSP adds a PodSecurityPolicy for the Kyma Installer pod to the installer file, and allows the service account
+// of the Kyma Installer to use it. It returns the service account, so that it can be logged.
+// The defaultNamespace is used if the deployment of the Kyma Installer sets no namespace.
+func insertInstallerPSP(installerFile *File, defaultNamespace string) (string, error) {
+	podSpec, ok := installerPodSpec(installerFile)
+	if !ok {
+		return "", errors.New("unable to find the Kyma Installer 'Deployment' to create its PodSecurityPolicy")
+	}
+	account, _ := podSpec["serviceAccountName"].(string)
+	if account == "" {
+		account = defaultInstallerAccount
+	}
+	namespace := installerDeploymentNamespace(installerFile, defaultNamespace)
+
+	for _, doc := range installerFile.Content {
+		if doc["kind"] == "PodSecurityPolicy" && documentName(doc) == installerPSPName {
+			// the manifest already brings its policy
+			return account, nil
+		}
+	}
+
+	appendDocuments(installerFile,
+		map[string]interface{}{
+			"apiVersion": "policy/v1beta1",
+			"kind":       "PodSecurityPolicy",
+			"metadata":   map[interface{}]interface{}{"name": installerPSPName},
+			"spec": map[interface{}]interface{}{
+				"privileged":               false,
+				"allowPrivilegeEscalation": false,
+				"hostNetwork":              false,
+				"hostIPC":                  false,
+				"hostPID":                  false,
+				"volumes":                  []interface{}{"configMap", "secret", "emptyDir", "projected", "downwardAPI", "persistentVolumeClaim"},
+				"runAsUser":                map[interface{}]interface{}{"rule": "RunAsAny"},
+				"seLinux":                  map[interface{}]interface{}{"rule": "RunAsAny"},
+				"supplementalGroups":       map[interface{}]interface{}{"rule": "RunAsAny"},
+				"fsGroup":                  map[interface{}]interface{}{"rule": "RunAsAny"},
+			},
+		},
+		map[string]interface{}{
+			"apiVersion": "rbac.authorization.k8s.io/v1",
+			"kind":       "ClusterRole",
+			"metadata":   map[interface{}]interface{}{"name": installerPSPRoleName},
+			"rules": []interface{}{
+				map[interface{}]interface{}{
+					"apiGroups":     []interface{}{"policy"},
+					"resources":     []interface{}{"podsecuritypolicies"},
+					"verbs":         []interface{}{"use"},
+					"resourceNames": []interface{}{installerPSPName},
+				},
+			},
+		},
+		map[string]interface{}{
+			"apiVersion": "rbac.authorization.k8s.io/v1",
+			"kind":       "ClusterRoleBinding",
+			"metadata":   map[interface{}]interface{}{"name": installerPSPRoleName},
+			"roleRef": map[interface{}]interface{}{
+				"apiGroup": "rbac.authorization.k8s.io",
+				"kind":     "ClusterRole",
+				"name":     installerPSPRoleName,
+			},
+			"subjects": []interface{}{
+				map[interface{}]interface{}{"kind": "ServiceAccount", "name": account, "namespace": namespace},
+			},
+		},
+	)
+	return account, nil
+}
+
+// installerDeploymentNamespace returns the namespace of the Kyma Installer deployment, or the defaultNamespace if it sets none
+func installerDeploymentNamespace(installerFile *File, defaultNamespace string) string {
+	for _, doc := range installerFile.Content {
+		if doc["kind"] != "Deployment" {
+			continue
+		}
+		metadata, _ := doc["metadata"].(map[interface{}]interface{})
+		if ns, ok := metadata["namespace"].(string); ok && ns != "" {
+			return ns
+		}
+	}
+	return defaultNamespace
+}
+
+func documentName(doc map[string]interface{}) string {
+	metadata, _ := doc["metadata"].(map[interface{}]interface{})
+	name, _ := metadata["name"].(string)
+	return name
+}
+
+// appendDocuments adds documents to the file. The documents have no source, so they are encoded when the file is applied.
+func appendDocuments(file *File, docs ...map[string]interface{}) {
+	aligned := len(file.raw) == len(file.Content)
+	for _, doc := range docs {
+		file.Content = append(file.Content, doc)
+		if aligned {
+			file.raw = append(file.raw, "")
+		}
+	}
+}
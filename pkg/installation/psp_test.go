@@ -0,0 +1,51 @@
+package installation
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestInsertInstallerPSP(t *testing.T) {
+	t.Parallel()
+	file := &File{
+		Content: []map[string]interface{}{
+			{"kind": "ServiceAccount"},
+			{
+				"kind":     "Deployment",
+				"metadata": map[interface{}]interface{}{"name": "kyma-installer", "namespace": "installer-ns"},
+				"spec": map[interface{}]interface{}{
+					"template": map[interface{}]interface{}{
+						"spec": map[interface{}]interface{}{
+							"serviceAccountName": "installer-sa",
+							"containers":         []interface{}{map[interface{}]interface{}{"name": "kyma-installer-container"}},
+						},
+					},
+				},
+			},
+		},
+		raw: []string{"kind: ServiceAccount\n", "kind: Deployment\n"},
+	}
+
+	account, err := insertInstallerPSP(file, installerNamespace())
+	require.NoError(t, err)
+	require.Equal(t, "installer-sa", account)
+	require.Len(t, file.Content, 5)
+	require.Len(t, file.raw, 5, "the sources must stay aligned with the documents")
+	require.Equal(t, "PodSecurityPolicy", file.Content[2]["kind"])
+	require.Equal(t, "ClusterRole", file.Content[3]["kind"])
+	binding := file.Content[4]
+	require.Equal(t, "ClusterRoleBinding", binding["kind"])
+	subject := binding["subjects"].([]interface{})[0].(map[interface{}]interface{})
+	require.Equal(t, "installer-sa", subject["name"])
+	require.Equal(t, "installer-ns", subject["namespace"])
+
+	// the policy is not added twice
+	_, err = insertInstallerPSP(file, installerNamespace())
+	require.NoError(t, err)
+	require.Len(t, file.Content, 5)
+
+	// without the Kyma Installer no policy can be created
+	_, err = insertInstallerPSP(&File{Content: []map[string]interface{}{{"kind": "ServiceAccount"}}}, installerNamespace())
+	require.Error(t, err)
+}
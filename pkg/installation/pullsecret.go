@@ -0,0 +1,163 @@
+package installation
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+
+	pkgErrors "github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apiErrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	imagePullSecretName = "kyma-image-pull-secret"
+)
+
+// imagePullSecretConfigured checks if credentials for a private registry were provided
+func (i *Installation) imagePullSecretConfigured() bool {
+	return i.Options.ImagePullSecret != "" || i.Options.RegistryServer != "" || i.Options.RegistryUser != "" || i.Options.RegistryPassword != ""
+}
+
+func (i *Installation) validateImagePullSecret() error {
+	registryCredentials := i.Options.RegistryServer != "" || i.Options.RegistryUser != "" || i.Options.RegistryPassword != ""
+	if i.Options.ImagePullSecret != "" && registryCredentials {
+		return pkgErrors.New(errorPullSecretConflict)
+	}
+	if registryCredentials && (i.Options.RegistryServer == "" || i.Options.RegistryUser == "" || i.Options.RegistryPassword == "") {
+		return pkgErrors.New(errorRegistryCredentialsIncomplete)
+	}
+	if i.Options.ImagePullSecret != "" {
+		if _, err := i.dockerConfigJSON(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// dockerConfigJSON returns the content of the docker config JSON either read from the provided file or built from the registry credentials
+func (i *Installation) dockerConfigJSON() ([]byte, error) {
+	if i.Options.ImagePullSecret != "" {
+		data, err := ioutil.ReadFile(i.Options.ImagePullSecret)
+		if err != nil {
+			return nil, pkgErrors.Wrapf(err, "unable to read the image pull secret file '%s'", i.Options.ImagePullSecret)
+		}
+		if !json.Valid(data) {
+			return nil, fmt.Errorf("the image pull secret file '%s' is not a valid docker config JSON", i.Options.ImagePullSecret)
+		}
+		return data, nil
+	}
+
+	auth := base64.StdEncoding.EncodeToString([]byte(i.Options.RegistryUser + ":" + i.Options.RegistryPassword))
+	return json.Marshal(map[string]interface{}{
+		"auths": map[string]interface{}{
+			i.Options.RegistryServer: map[string]string{
+				"username": i.Options.RegistryUser,
+				"password": i.Options.RegistryPassword,
+				"auth":     auth,
+			},
+		},
+	})
+}
+
+// imagePullSecrets builds the image pull secret for the installer namespace and all additionally requested namespaces.
+func (i *Installation) imagePullSecrets() ([]*corev1.Secret, error) {
+	dockerConfig, err := i.dockerConfigJSON()
+	if err != nil {
+		return nil, err
+	}
+
+	namespaces := []string{i.installerNamespace()}
+	for _, ns := range i.Options.ImagePullSecretNamespaces {
+		if ns != i.installerNamespace() {
+			namespaces = append(namespaces, ns)
+		}
+	}
+
+	secrets := make([]*corev1.Secret, 0, len(namespaces))
+	for _, ns := range namespaces {
+		secrets = append(secrets, &corev1.Secret{
+			TypeMeta: metav1.TypeMeta{
+				APIVersion: "v1",
+				Kind:       "Secret",
+			},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      imagePullSecretName,
+				Namespace: ns,
+			},
+			Type: corev1.SecretTypeDockerConfigJson,
+			Data: map[string][]byte{
+				corev1.DockerConfigJsonKey: dockerConfig,
+			},
+		})
+	}
+	return secrets, nil
+}
+
+// applyImagePullSecrets creates the image pull secrets if credentials for a private registry were provided.
+// It is called when the Kyma Installer is applied, so that the preparations of --dry-run and --get-config write nothing to the cluster.
+func (i *Installation) applyImagePullSecrets() error {
+	if !i.imagePullSecretConfigured() {
+		return nil
+	}
+	return i.createImagePullSecrets()
+}
+
+// createImagePullSecrets creates or updates the image pull secrets.
+// Missing namespaces are created, so that the secret is in place before the installer starts pulling images.
+func (i *Installation) createImagePullSecrets() error {
+	pullSecrets, err := i.imagePullSecrets()
+	if err != nil {
+		return err
+	}
+
+	for _, secret := range pullSecrets {
+		i.addExtraMetadata(&secret.ObjectMeta)
+		ns := secret.Namespace
+		if err := i.ensureNamespace(ns); err != nil {
+			return err
+		}
+
+		secrets := i.K8s.Static().CoreV1().Secrets(ns)
+		_, err := secrets.Create(context.Background(), secret, metav1.CreateOptions{})
+		if apiErrors.IsAlreadyExists(err) {
+			_, err = secrets.Update(context.Background(), secret, metav1.UpdateOptions{})
+		}
+		if err != nil {
+			return pkgErrors.Wrapf(err, "unable to create the image pull secret '%s' in namespace '%s'", imagePullSecretName, ns)
+		}
+		if i.Options.Verbose {
+			i.currentStep.LogInfof("Image pull secret '%s' created in namespace '%s'", imagePullSecretName, ns)
+		}
+	}
+
+	return nil
+}
+
+func (i *Installation) ensureNamespace(name string) error {
+	ns := &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: name,
+		},
+	}
+	i.addExtraMetadata(&ns.ObjectMeta)
+	_, err := i.K8s.Static().CoreV1().Namespaces().Create(context.Background(), ns, metav1.CreateOptions{})
+	if apiErrors.IsForbidden(err) {
+		// on restricted clusters, the namespaces are created by the cluster administrators. If the namespace is missing,
+		// the resources created in it fail with a clear error later on, so the installation continues.
+		if i.currentStep != nil {
+			i.currentStep.LogErrorf("Warning: not permitted to create the namespace '%s', assuming that it already exists", name)
+		}
+		return nil
+	}
+	if err != nil && !apiErrors.IsAlreadyExists(err) {
+		return pkgErrors.Wrapf(err, "unable to create namespace '%s'", name)
+	}
+	if err == nil {
+		i.recordCreatedNamespace(name)
+	}
+	return nil
+}
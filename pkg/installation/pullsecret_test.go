@@ -0,0 +1,118 @@
+package installation
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	k8sMocks "github.com/kyma-project/cli/internal/kube/mocks"
+	stepMocks "github.com/kyma-project/cli/pkg/step/mocks"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestValidateImagePullSecret(t *testing.T) {
+	t.Parallel()
+	dir, err := ioutil.TempDir("", "pull-secret-validation")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	validFile := filepath.Join(dir, "config.json")
+	require.NoError(t, ioutil.WriteFile(validFile, []byte(`{"auths":{}}`), 0600))
+	invalidFile := filepath.Join(dir, "invalid.json")
+	require.NoError(t, ioutil.WriteFile(invalidFile, []byte("not json"), 0600))
+
+	testData := []struct {
+		testName   string
+		opts       Options
+		shouldFail bool
+	}{
+		{testName: "nothing configured", opts: Options{}},
+		{testName: "valid docker config file", opts: Options{ImagePullSecret: validFile}},
+		{testName: "complete registry credentials", opts: Options{RegistryServer: "reg", RegistryUser: "user", RegistryPassword: "pwd"}},
+		{testName: "missing docker config file", opts: Options{ImagePullSecret: filepath.Join(dir, "missing.json")}, shouldFail: true},
+		{testName: "invalid docker config file", opts: Options{ImagePullSecret: invalidFile}, shouldFail: true},
+		{testName: "incomplete registry credentials", opts: Options{RegistryServer: "reg", RegistryUser: "user"}, shouldFail: true},
+		{testName: "file and registry credentials", opts: Options{ImagePullSecret: validFile, RegistryServer: "reg", RegistryUser: "user", RegistryPassword: "pwd"}, shouldFail: true},
+	}
+
+	for _, tt := range testData {
+		opts := tt.opts
+		i := &Installation{Options: &opts}
+		err := i.validateImagePullSecret()
+		if tt.shouldFail {
+			require.Error(t, err, tt.testName)
+		} else {
+			require.NoError(t, err, tt.testName)
+		}
+	}
+}
+
+func TestCreateImagePullSecrets(t *testing.T) {
+	t.Parallel()
+	k8sMock := fake.NewSimpleClientset(
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "kyma-installer"}},
+	)
+	kymaMock := k8sMocks.KymaKube{}
+	kymaMock.On("Static").Return(k8sMock)
+
+	i := &Installation{
+		K8s:         &kymaMock,
+		currentStep: &stepMocks.Step{},
+		Options: &Options{
+			RegistryServer:            "fake-registry",
+			RegistryUser:              "fake-user",
+			RegistryPassword:          "fake-password",
+			ImagePullSecretNamespaces: []string{"kyma-system", "kyma-installer"},
+		},
+	}
+
+	// secrets are created and missing namespaces too
+	require.NoError(t, i.createImagePullSecrets())
+	// re-runs update the existing secrets
+	i.Options.RegistryPassword = "new-fake-password"
+	require.NoError(t, i.createImagePullSecrets())
+
+	for _, ns := range []string{"kyma-installer", "kyma-system"} {
+		_, err := k8sMock.CoreV1().Namespaces().Get(context.Background(), ns, metav1.GetOptions{})
+		require.NoError(t, err)
+
+		secret, err := k8sMock.CoreV1().Secrets(ns).Get(context.Background(), imagePullSecretName, metav1.GetOptions{})
+		require.NoError(t, err)
+		require.Equal(t, corev1.SecretTypeDockerConfigJson, secret.Type)
+
+		dockerCfg := struct {
+			Auths map[string]struct {
+				Username string `json:"username"`
+				Password string `json:"password"`
+				Auth     string `json:"auth"`
+			} `json:"auths"`
+		}{}
+		require.NoError(t, json.Unmarshal(secret.Data[corev1.DockerConfigJsonKey], &dockerCfg))
+		auth := dockerCfg.Auths["fake-registry"]
+		require.Equal(t, "fake-user", auth.Username)
+		require.Equal(t, "new-fake-password", auth.Password)
+		require.Equal(t, base64.StdEncoding.EncodeToString([]byte("fake-user:new-fake-password")), auth.Auth)
+	}
+}
+
+func TestApplyImagePullSecrets(t *testing.T) {
+	t.Parallel()
+	// without registry credentials, the cluster is not accessed
+	i := &Installation{K8s: &k8sMocks.KymaKube{}, currentStep: &stepMocks.Step{}, Options: &Options{}}
+	require.NoError(t, i.applyImagePullSecrets())
+
+	k8sMock := fake.NewSimpleClientset()
+	kymaMock := k8sMocks.KymaKube{}
+	kymaMock.On("Static").Return(k8sMock)
+	i = &Installation{K8s: &kymaMock, currentStep: &stepMocks.Step{}, Options: &Options{RegistryServer: "fake-registry", RegistryUser: "fake-user", RegistryPassword: "fake-password"}}
+	require.NoError(t, i.applyImagePullSecrets())
+	_, err := k8sMock.CoreV1().Secrets("kyma-installer").Get(context.Background(), imagePullSecretName, metav1.GetOptions{})
+	require.NoError(t, err)
+}
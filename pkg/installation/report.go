@@ -0,0 +1,208 @@
+package installation
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	pkgErrors "github.com/pkg/errors"
+)
+
+// progressStallTimeout is the time without any change of the installation description after which the installation is reported as stalled
+const progressStallTimeout = 20 * time.Minute
+
+// ProgressReport is the progress of a running installation, as served by the status endpoint and written to the status file.
+type ProgressReport struct {
+	// Stage is the step the CLI is running.
+	Stage string `json:"stage"`
+	// Started is the time the installation started.
+	Started time.Time `json:"started"`
+	// ElapsedSeconds indicates how long the installation has been running.
+	ElapsedSeconds int64 `json:"elapsedSeconds"`
+	// State is the last installation state reported by the Kyma Installer.
+	State string `json:"state,omitempty"`
+	// Description is the last description reported by the Kyma Installer.
+	Description string `json:"description,omitempty"`
+	// ComponentsInstalled is the number of installed components, if the progress can be tracked.
+	ComponentsInstalled int `json:"componentsInstalled"`
+	// ComponentsTotal is the number of components to install, if the progress can be tracked.
+	ComponentsTotal int `json:"componentsTotal"`
+	// LastProgress is the time the stage or the description last changed.
+	LastProgress time.Time `json:"lastProgress"`
+	// Done is set once the CLI stopped watching the installation.
+	Done bool `json:"done"`
+	// Error holds the error the installation failed with.
+	Error string `json:"error,omitempty"`
+}
+
+// progressReporter publishes the progress of the installation on an HTTP endpoint and/or in a file.
+// All methods can be called on a nil reporter, which does nothing.
+type progressReporter struct {
+	mu     sync.Mutex
+	report ProgressReport
+	file   string
+	server *http.Server
+}
+
+// startProgressReport starts serving the progress on the configured status port, if any
+func (i *Installation) startProgressReport() (*progressReporter, error) {
+	if i.Options.StatusPort == 0 && i.Options.StatusFile == "" {
+		return nil, nil
+	}
+	now := time.Now()
+	r := &progressReporter{file: i.Options.StatusFile, report: ProgressReport{Started: now, LastProgress: now}}
+
+	if i.Options.StatusPort != 0 {
+		listener, err := net.Listen("tcp", fmt.Sprintf("127.0.0.1:%d", i.Options.StatusPort))
+		if err != nil {
+			return nil, pkgErrors.Wrapf(err, "unable to serve the installation status on port %d", i.Options.StatusPort)
+		}
+		mux := http.NewServeMux()
+		mux.HandleFunc("/status", r.serveStatus)
+		mux.HandleFunc("/healthz", r.serveHealth)
+		mux.HandleFunc("/metrics", r.serveMetrics)
+		r.server = &http.Server{Handler: mux}
+		go func() {
+			_ = r.server.Serve(listener)
+		}()
+	}
+
+	return r, r.write()
+}
+
+// stage records the stage the CLI is running
+func (r *progressReporter) stage(name string) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	if r.report.Stage != name {
+		r.report.Stage = name
+		r.report.LastProgress = time.Now()
+	}
+	r.mu.Unlock()
+	r.writeOrWarn()
+}
+
+// poll records the installation state read in a single check of the Kyma Installer
+func (r *progressReporter) poll(state, description string, p *progress) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	r.report.State = state
+	if r.report.Description != description {
+		r.report.Description = description
+		r.report.LastProgress = time.Now()
+	}
+	if p != nil {
+		r.report.ComponentsTotal = len(p.components)
+		r.report.ComponentsInstalled = p.current
+		if r.report.ComponentsInstalled < 0 {
+			r.report.ComponentsInstalled = 0
+		}
+	}
+	r.mu.Unlock()
+	r.writeOrWarn()
+}
+
+// stop publishes the final result and shuts the status endpoint down
+func (r *progressReporter) stop(err error) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	r.report.Done = true
+	if err != nil {
+		r.report.Error = err.Error()
+	}
+	r.mu.Unlock()
+	r.writeOrWarn()
+
+	if r.server != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = r.server.Shutdown(ctx)
+	}
+}
+
+// snapshot returns a copy of the report with the elapsed time set
+func (r *progressReporter) snapshot() ProgressReport {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	report := r.report
+	report.ElapsedSeconds = int64(time.Since(report.Started).Seconds())
+	return report
+}
+
+// healthy checks if the installation is still making progress
+func (r *progressReporter) healthy() bool {
+	report := r.snapshot()
+	return report.Error == "" && time.Since(report.LastProgress) < progressStallTimeout
+}
+
+// write atomically replaces the status file, so that readers never see a partially written file
+func (r *progressReporter) write() error {
+	if r == nil || r.file == "" {
+		return nil
+	}
+	data, err := json.MarshalIndent(r.snapshot(), "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp, err := ioutil.TempFile(filepath.Dir(r.file), filepath.Base(r.file)+".*")
+	if err != nil {
+		return pkgErrors.Wrapf(err, "unable to write the status file '%s'", r.file)
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return pkgErrors.Wrapf(err, "unable to write the status file '%s'", r.file)
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return pkgErrors.Wrapf(err, "unable to write the status file '%s'", r.file)
+	}
+	return os.Rename(tmp.Name(), r.file)
+}
+
+// writeOrWarn writes the status file, failing writes must not stop the installation
+func (r *progressReporter) writeOrWarn() {
+	if err := r.write(); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %s\n", err)
+	}
+}
+
+func (r *progressReporter) serveStatus(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(r.snapshot())
+}
+
+func (r *progressReporter) serveHealth(w http.ResponseWriter, _ *http.Request) {
+	if !r.healthy() {
+		http.Error(w, "the installation is not making progress", http.StatusServiceUnavailable)
+		return
+	}
+	fmt.Fprintln(w, "ok")
+}
+
+// serveMetrics serves the progress in the Prometheus text format
+func (r *progressReporter) serveMetrics(w http.ResponseWriter, _ *http.Request) {
+	report := r.snapshot()
+	healthy := 0
+	if r.healthy() {
+		healthy = 1
+	}
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+	fmt.Fprintf(w, "# HELP kyma_install_elapsed_seconds Time since the installation started.\n# TYPE kyma_install_elapsed_seconds gauge\nkyma_install_elapsed_seconds %d\n", report.ElapsedSeconds)
+	fmt.Fprintf(w, "# HELP kyma_install_components_installed Number of installed components.\n# TYPE kyma_install_components_installed gauge\nkyma_install_components_installed %d\n", report.ComponentsInstalled)
+	fmt.Fprintf(w, "# HELP kyma_install_components_total Number of components to install.\n# TYPE kyma_install_components_total gauge\nkyma_install_components_total %d\n", report.ComponentsTotal)
+	fmt.Fprintf(w, "# HELP kyma_install_healthy Whether the installation is making progress.\n# TYPE kyma_install_healthy gauge\nkyma_install_healthy %d\n", healthy)
+}
@@ -0,0 +1,105 @@
+package installation
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestProgressReport(t *testing.T) {
+	t.Parallel()
+	dir, err := ioutil.TempDir("", "kyma-status-")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	file := filepath.Join(dir, "status.json")
+
+	// nothing is reported without port and file
+	i := &Installation{Options: &Options{}}
+	r, err := i.startProgressReport()
+	require.NoError(t, err)
+	require.Nil(t, r)
+	r.stage("Installing Kyma")
+
+	i.Options.StatusFile = file
+	r, err = i.startProgressReport()
+	require.NoError(t, err)
+	r.stage("Installing Kyma")
+	p := newProgress([]string{"cluster-essentials", "istio", "console"})
+	p.update("install component istio", time.Now())
+	r.poll("InProgress", "install component istio", p)
+
+	readFile := func() ProgressReport {
+		data, err := ioutil.ReadFile(file)
+		require.NoError(t, err)
+		report := ProgressReport{}
+		require.NoError(t, json.Unmarshal(data, &report))
+		return report
+	}
+	report := readFile()
+	require.Equal(t, "Installing Kyma", report.Stage)
+	require.Equal(t, "install component istio", report.Description)
+	require.Equal(t, 1, report.ComponentsInstalled)
+	require.Equal(t, 3, report.ComponentsTotal)
+	require.False(t, report.Done)
+
+	// the endpoints serve the same data
+	rec := httptest.NewRecorder()
+	r.serveStatus(rec, httptest.NewRequest(http.MethodGet, "/status", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Contains(t, rec.Body.String(), `"description":"install component istio"`)
+	rec = httptest.NewRecorder()
+	r.serveMetrics(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	require.Contains(t, rec.Body.String(), "kyma_install_components_total 3\n")
+	require.Contains(t, rec.Body.String(), "kyma_install_healthy 1\n")
+
+	rec = httptest.NewRecorder()
+	r.serveHealth(rec, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	// no progress for too long
+	r.report.LastProgress = time.Now().Add(-progressStallTimeout)
+	rec = httptest.NewRecorder()
+	r.serveHealth(rec, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+	require.Equal(t, http.StatusServiceUnavailable, rec.Code)
+
+	r.stop(errors.New("timeout"))
+	report = readFile()
+	require.True(t, report.Done)
+	require.Equal(t, "timeout", report.Error)
+
+	files, err := ioutil.ReadDir(dir)
+	require.NoError(t, err)
+	require.Len(t, files, 1, "temporary files must be removed")
+}
+
+func TestProgressReportServer(t *testing.T) {
+	t.Parallel()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	port := listener.Addr().(*net.TCPAddr).Port
+	require.NoError(t, listener.Close())
+
+	i := &Installation{Options: &Options{StatusPort: port}}
+	r, err := i.startProgressReport()
+	require.NoError(t, err)
+
+	resp, err := http.Get(fmt.Sprintf("http://127.0.0.1:%d/healthz", port))
+	require.NoError(t, err)
+	resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	// the server is shut down once the installation returns
+	r.stop(nil)
+	_, err = http.Get(fmt.Sprintf("http://127.0.0.1:%d/healthz", port))
+	require.Error(t, err)
+}
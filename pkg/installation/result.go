@@ -0,0 +1,204 @@
+package installation
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/kyma-project/cli/internal/kube"
+	"github.com/kyma-project/cli/internal/metadata"
+	"github.com/kyma-project/cli/internal/version"
+	pkgErrors "github.com/pkg/errors"
+	apiErrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/client-go/kubernetes"
+)
+
+var (
+	// adminSecretTimeout is the maximum time to wait for the admin-user Secret once Kyma is installed, as it is populated shortly after the installation
+	adminSecretTimeout = time.Minute
+	// adminSecretInterval is the time between two reads of the admin-user Secret
+	adminSecretInterval = 5 * time.Second
+)
+
+// ErrNotInstalled is returned by Summary if no Kyma Installer runs on the cluster
+var ErrNotInstalled = errors.New("Kyma is not installed on the cluster")
+
+// adminCredentialsHint explains how to read the admin credentials if they are not available when the summary is displayed
+func adminCredentialsHint(m metadata.Metadata) string {
+	return fmt.Sprintf(`The admin credentials are not available yet. To read them later, run: kubectl get secret %s -n %s -o jsonpath="{.data.password}" | base64 --decode`, m.AdminSecret, m.AdminSecretNamespace)
+}
+
+// Result contains the resulting details related to the installation.
+type Result struct {
+	// KymaVersion indicates the installed Kyma version.
+	KymaVersion string
+	// ClusterVersion holds the Kyma version together with the state of the installation, which is in progress if the command did not wait for it.
+	ClusterVersion version.ClusterVersion
+	// Host indicates the host address where Kyma is installed.
+	Host string
+	// Console holds the address of Kyma console.
+	Console string
+	// ConsoleAssumed is set if the console address could not be read from the cluster, so that it was composed from the domain.
+	ConsoleAssumed bool
+	// Domain holds the domain of the cluster as read from the console address, or the domain of the options if it could not be read.
+	Domain string
+	// AdminEmail indicates the Email address of the Admin user which can be used to login Kyma.
+	AdminEmail string
+	// AdminPassword indicates the password of the Admin user which can be used to login Kyma.
+	AdminPassword string
+	// DexUsers lists the additional users created with the options, without their passwords.
+	DexUsers []string
+	// UnreadyWorkloads lists the Deployments and StatefulSets of the Kyma namespaces which were not ready after the installation.
+	UnreadyWorkloads []string
+	// Warnings includes a set of any warnings from the installation.
+	Warnings []string
+	// Duration indicates the duration of the installation.
+	Duration time.Duration
+	// ComponentDurations holds the installation time of each component, if the progress could be tracked.
+	ComponentDurations []ComponentDuration
+	// StepDurations holds the time each step of the command took until the result was built.
+	StepDurations []StepDuration
+	// ExternalInstaller is set if the Kyma Installer was deployed outside of the CLI and only activated by it.
+	ExternalInstaller bool
+	// ManifestsDir indicates the directory in which the applied manifests were exported, if requested.
+	ManifestsDir string
+}
+
+// buildResult collects the details shown in the summary. Details which are not available yet, for example because the installation
+// is still in progress, are left empty and the lookups failing for other reasons are reported as warnings instead of failing the command.
+func (i *Installation) buildResult(duration time.Duration) (*Result, error) {
+	var warnings []string
+	cv, err := version.ClusterKymaVersion(i.K8s)
+	if err != nil {
+		warnings = append(warnings, fmt.Sprintf("Unable to determine the Kyma version: %s", err))
+	}
+	// In case of --wait=false, the installation just triggered is in progress until the installer reports it as installed
+	if i.Options.NoWait {
+		installationState, err := i.Service.CheckInstallationState(i.K8s.RestConfig(), i.Options.InstallationName)
+		if err != nil {
+			return nil, err
+		}
+		if installationState.State != "Installed" && cv.State == version.Installed {
+			cv.State = version.InProgress
+		}
+	}
+
+	var email, password string
+	m := metadata.For(cv.Version)
+	if cv.State == version.Installed {
+		email, password, err = waitForAdminCredentials(i.K8s.Static(), m)
+	} else {
+		email, password, err = adminCredentials(i.K8s.Static(), m)
+	}
+	switch {
+	case err != nil:
+		warnings = append(warnings, fmt.Sprintf("Unable to read the admin credentials: %s", err))
+	case cv.State == version.Installed && password == "":
+		warnings = append(warnings, adminCredentialsHint(m))
+	}
+
+	var consoleURL string
+	var consoleAssumed bool
+	domain := i.Options.Domain
+	host, err := kube.ConsoleHost(i.K8s)
+	switch {
+	case apiErrors.IsNotFound(err) && (cv.State == version.InProgress || cv.State == version.Failed):
+		consoleURL = "not available yet"
+	case apiErrors.IsNotFound(err):
+		consoleURL = "not installed"
+	case err != nil:
+		warnings = append(warnings, fmt.Sprintf("Unable to determine the console address: %s", err))
+		// without the domain of the options, e.g. for the summary of an earlier installation, the address cannot be assumed
+		if i.Options.Domain != "" {
+			consoleURL, consoleAssumed = fmt.Sprintf("https://console.%s", i.Options.Domain), true
+		}
+	default:
+		consoleURL = fmt.Sprintf("https://%s", host)
+		domain = kube.ConsoleDomain(host)
+	}
+
+	// nip.io domains need no DNS configuration
+	if !i.Options.IsLocal && i.Options.Domain != "" && i.Options.Domain != defaultDomain && !i.Options.UseNipIO {
+		warnings = append(warnings, "To access the console, configure DNS for the cluster load balancer: https://kyma-project.io/docs/#installation-install-kyma-with-your-own-domain-configure-dns-for-the-cluster-load-balancer")
+	}
+
+	if i.workloadsErr != nil {
+		warnings = append(warnings, fmt.Sprintf("Unable to verify the workloads: %s", i.workloadsErr))
+	}
+
+	warnings = append(warnings, i.cidrOverlaps...)
+
+	var componentDurations []ComponentDuration
+	if i.progress != nil {
+		componentDurations = i.progress.durations
+	}
+
+	// the users were validated before the installation, so they can be parsed
+	var dexUsers []string
+	users, _ := i.dexUsers()
+	for _, u := range users {
+		dexUsers = append(dexUsers, u.String())
+	}
+
+	return &Result{
+		KymaVersion:        cv.Version,
+		ClusterVersion:     cv,
+		Host:               i.K8s.RestConfig().Host,
+		Console:            consoleURL,
+		ConsoleAssumed:     consoleAssumed,
+		Domain:             domain,
+		AdminEmail:         email,
+		AdminPassword:      password,
+		DexUsers:           dexUsers,
+		UnreadyWorkloads:   i.unreadyWorkloads,
+		Warnings:           warnings,
+		Duration:           duration,
+		ComponentDurations: componentDurations,
+		StepDurations:      i.StepDurations(),
+		ExternalInstaller:  i.Options.ExternalInstaller,
+	}, nil
+}
+
+// Summary builds the result of the Kyma installed on the cluster without changing the cluster, e.g. to display the summary of an earlier installation.
+// The domain is read from the console address, and the admin credentials are waited for like after an installation.
+// ErrNotInstalled is returned if Kyma is not installed.
+func Summary(k8s kube.KymaKube) (*Result, error) {
+	cv, err := version.ClusterKymaVersion(k8s)
+	if err != nil {
+		return nil, pkgErrors.Wrap(err, "unable to determine the Kyma version")
+	}
+	if cv.State == version.NotInstalled {
+		return nil, ErrNotInstalled
+	}
+	i := &Installation{K8s: k8s, Options: &Options{}}
+	return i.buildResult(0)
+}
+
+// adminCredentials reads the email and password of the admin user, which are empty until they are created during the installation
+func adminCredentials(k8s kubernetes.Interface, m metadata.Metadata) (string, string, error) {
+	values := make([]string, 2)
+	for n, key := range []string{"email", "password"} {
+		v, err := kube.GetResourceField(k8s, "secret", m.AdminSecret, m.AdminSecretNamespace, fmt.Sprintf("{.data.%s}", key), true)
+		switch {
+		case errors.Is(err, kube.ErrResourceNotFound), errors.Is(err, kube.ErrFieldNotSet):
+		case err != nil:
+			return "", "", err
+		default:
+			values[n] = v
+		}
+	}
+	return values[0], values[1], nil
+}
+
+// waitForAdminCredentials reads the admin credentials of an installed Kyma until the admin-user Secret is populated, for at most adminSecretTimeout.
+// Once the time is over, the credentials read last are returned, even if they are empty.
+func waitForAdminCredentials(k8s kubernetes.Interface, m metadata.Metadata) (string, string, error) {
+	deadline := time.Now().Add(adminSecretTimeout)
+	for {
+		email, password, err := adminCredentials(k8s, m)
+		if (err == nil && email != "" && password != "") || time.Now().After(deadline) {
+			return email, password, err
+		}
+		time.Sleep(adminSecretInterval)
+	}
+}
@@ -0,0 +1,192 @@
+package installation
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	installSDK "github.com/kyma-incubator/hydroform/install/installation"
+	k8sMocks "github.com/kyma-project/cli/internal/kube/mocks"
+	"github.com/kyma-project/cli/internal/metadata"
+	"github.com/kyma-project/cli/internal/version"
+	"github.com/kyma-project/cli/pkg/installation/mocks"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	networkingv1alpha3 "istio.io/api/networking/v1alpha3"
+	"istio.io/client-go/pkg/apis/networking/v1alpha3"
+	fakeIstio "istio.io/client-go/pkg/clientset/versioned/fake"
+	v1 "k8s.io/api/core/v1"
+	apiErrors "k8s.io/apimachinery/pkg/api/errors"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	fakeDynamic "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/rest"
+	k8sTesting "k8s.io/client-go/testing"
+)
+
+func TestBuildResult(t *testing.T) {
+	t.Parallel()
+	installerPod := &v1.Pod{
+		ObjectMeta: metaV1.ObjectMeta{Name: "kyma-installer", Namespace: "kyma-installer", Labels: map[string]string{"name": "kyma-installer"}},
+		Status:     v1.PodStatus{Phase: v1.PodRunning},
+		Spec:       v1.PodSpec{Containers: []v1.Container{{Name: "installer", Image: "fake-registry/installer:1.15.1"}}},
+	}
+	adminSecret := &v1.Secret{
+		ObjectMeta: metaV1.ObjectMeta{Name: "admin-user", Namespace: "kyma-system"},
+		Data:       map[string][]byte{"email": []byte("admin@fake.com"), "password": []byte("1234-super-secure")},
+	}
+	consoleService := &v1alpha3.VirtualService{
+		ObjectMeta: metaV1.ObjectMeta{Name: "console-web", Namespace: "kyma-system"},
+		Spec:       networkingv1alpha3.VirtualService{Hosts: []string{"console.fake.com"}},
+	}
+
+	newInstallation := func(o *Options, istio *fakeIstio.Clientset, objs ...runtime.Object) (*Installation, *mocks.Service) {
+		kymaMock := &k8sMocks.KymaKube{}
+		kymaMock.On("Static").Return(fake.NewSimpleClientset(objs...))
+		kymaMock.On("Istio").Return(istio)
+		kymaMock.On("Dynamic").Return(fakeDynamic.NewSimpleDynamicClient(runtime.NewScheme()))
+		kymaMock.On("RestConfig", mock.Anything).Return(&rest.Config{Host: "fake-kubeconfig-host"})
+		iServiceMock := &mocks.Service{}
+		return &Installation{K8s: kymaMock, Service: iServiceMock, Options: o}, iServiceMock
+	}
+
+	t.Run("Installed", func(t *testing.T) {
+		i, iServiceMock := newInstallation(&Options{Domain: defaultDomain, IsLocal: true}, fakeIstio.NewSimpleClientset(consoleService), installerPod, adminSecret)
+
+		r, err := i.buildResult(time.Minute)
+		require.NoError(t, err)
+		require.Equal(t, &Result{
+			KymaVersion:    "1.15.1",
+			ClusterVersion: version.ClusterVersion{State: version.Installed, Version: "1.15.1", InstallerSelector: "name=kyma-installer"},
+			Host:           "fake-kubeconfig-host",
+			Console:        "https://console.fake.com",
+			Domain:         "fake.com",
+			AdminEmail:     "admin@fake.com",
+			AdminPassword:  "1234-super-secure",
+			Duration:       time.Minute,
+		}, r)
+		iServiceMock.AssertNotCalled(t, "CheckInstallationState", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Triggered without waiting", func(t *testing.T) {
+		i, iServiceMock := newInstallation(&Options{Domain: defaultDomain, IsLocal: true, NoWait: true}, fakeIstio.NewSimpleClientset(), installerPod)
+		iServiceMock.On("CheckInstallationState", mock.Anything, mock.Anything).Return(installSDK.InstallationState{State: "InProgress"}, nil).Once()
+
+		r, err := i.buildResult(time.Minute)
+		require.NoError(t, err)
+		require.Equal(t, version.InProgress, r.ClusterVersion.State)
+		require.Equal(t, "not available yet", r.Console)
+		require.Empty(t, r.AdminEmail)
+		require.Empty(t, r.Warnings)
+		iServiceMock.AssertExpectations(t)
+	})
+
+	t.Run("Console address not readable", func(t *testing.T) {
+		istio := fakeIstio.NewSimpleClientset()
+		istio.PrependReactor("get", "virtualservices", func(k8sTesting.Action) (bool, runtime.Object, error) {
+			return true, nil, errors.New("connection refused")
+		})
+		i, _ := newInstallation(&Options{Domain: "kyma.example.com", UseNipIO: true}, istio, installerPod, adminSecret)
+
+		r, err := i.buildResult(time.Minute)
+		require.NoError(t, err)
+		require.Equal(t, "https://console.kyma.example.com", r.Console)
+		require.True(t, r.ConsoleAssumed)
+		require.Equal(t, "kyma.example.com", r.Domain)
+		require.Equal(t, []string{"Unable to determine the console address: connection refused"}, r.Warnings)
+	})
+
+	t.Run("Custom domain without DNS", func(t *testing.T) {
+		i, _ := newInstallation(&Options{Domain: "kyma.example.com"}, fakeIstio.NewSimpleClientset(consoleService), installerPod, adminSecret)
+
+		r, err := i.buildResult(time.Minute)
+		require.NoError(t, err)
+		require.Len(t, r.Warnings, 1)
+		require.Contains(t, r.Warnings[0], "configure DNS for the cluster load balancer")
+	})
+}
+
+func TestWaitForAdminCredentials(t *testing.T) {
+	// not parallel: the package level timeouts are modified
+	defaultTimeout, defaultInterval := adminSecretTimeout, adminSecretInterval
+	adminSecretTimeout, adminSecretInterval = 500*time.Millisecond, 10*time.Millisecond
+	defer func() { adminSecretTimeout, adminSecretInterval = defaultTimeout, defaultInterval }()
+
+	adminSecret := &v1.Secret{
+		ObjectMeta: metaV1.ObjectMeta{Name: "admin-user", Namespace: "kyma-system"},
+		Data:       map[string][]byte{"email": []byte("admin@fake.com"), "password": []byte("1234-super-secure")},
+	}
+
+	// the Secret appears on the third poll, each poll reads the email and the password
+	k8s := fake.NewSimpleClientset(adminSecret)
+	var reads int
+	k8s.PrependReactor("get", "secrets", func(k8sTesting.Action) (bool, runtime.Object, error) {
+		reads++
+		if reads <= 4 {
+			return true, nil, apiErrors.NewNotFound(v1.Resource("secrets"), "admin-user")
+		}
+		return false, nil, nil
+	})
+	email, password, err := waitForAdminCredentials(k8s, metadata.Latest())
+	require.NoError(t, err)
+	require.Equal(t, "admin@fake.com", email)
+	require.Equal(t, "1234-super-secure", password)
+	require.Equal(t, 6, reads)
+
+	// the summary is built without the credentials if the Secret does not appear
+	kymaMock := &k8sMocks.KymaKube{}
+	kymaMock.On("Static").Return(fake.NewSimpleClientset(&v1.Pod{
+		ObjectMeta: metaV1.ObjectMeta{Name: "kyma-installer", Namespace: "kyma-installer", Labels: map[string]string{"name": "kyma-installer"}},
+		Status:     v1.PodStatus{Phase: v1.PodRunning},
+		Spec:       v1.PodSpec{Containers: []v1.Container{{Name: "installer", Image: "fake-registry/installer:1.15.1"}}},
+	}))
+	kymaMock.On("Istio").Return(fakeIstio.NewSimpleClientset())
+	kymaMock.On("Dynamic").Return(fakeDynamic.NewSimpleDynamicClient(runtime.NewScheme()))
+	kymaMock.On("RestConfig", mock.Anything).Return(&rest.Config{Host: "fake-kubeconfig-host"})
+	i := &Installation{K8s: kymaMock, Service: &mocks.Service{}, Options: &Options{Domain: defaultDomain, IsLocal: true}}
+	r, err := i.buildResult(time.Minute)
+	require.NoError(t, err)
+	require.Equal(t, version.Installed, r.ClusterVersion.State)
+	require.Empty(t, r.AdminPassword)
+	require.Equal(t, []string{adminCredentialsHint(metadata.Latest())}, r.Warnings)
+}
+
+func TestSummary(t *testing.T) {
+	t.Parallel()
+	kymaMock := &k8sMocks.KymaKube{}
+	kymaMock.On("Static").Return(fake.NewSimpleClientset())
+	_, err := Summary(kymaMock)
+	require.Equal(t, ErrNotInstalled, err)
+
+	kymaMock = &k8sMocks.KymaKube{}
+	kymaMock.On("Static").Return(fake.NewSimpleClientset(
+		&v1.Pod{
+			ObjectMeta: metaV1.ObjectMeta{Name: "kyma-installer", Namespace: "kyma-installer", Labels: map[string]string{"name": "kyma-installer"}},
+			Status:     v1.PodStatus{Phase: v1.PodRunning},
+			Spec:       v1.PodSpec{Containers: []v1.Container{{Name: "installer", Image: "fake-registry/installer:1.15.1"}}},
+		},
+		&v1.Secret{
+			ObjectMeta: metaV1.ObjectMeta{Name: "admin-user", Namespace: "kyma-system"},
+			Data:       map[string][]byte{"email": []byte("admin@fake.com"), "password": []byte("1234-super-secure")},
+		},
+	))
+	kymaMock.On("Istio").Return(fakeIstio.NewSimpleClientset(&v1alpha3.VirtualService{
+		ObjectMeta: metaV1.ObjectMeta{Name: "console-web", Namespace: "kyma-system"},
+		Spec:       networkingv1alpha3.VirtualService{Hosts: []string{"console.kyma.example.com"}},
+	}))
+	kymaMock.On("Dynamic").Return(fakeDynamic.NewSimpleDynamicClient(runtime.NewScheme()))
+	kymaMock.On("RestConfig", mock.Anything).Return(&rest.Config{Host: "fake-kubeconfig-host"})
+
+	r, err := Summary(kymaMock)
+	require.NoError(t, err)
+	require.Equal(t, &Result{
+		KymaVersion:    "1.15.1",
+		ClusterVersion: version.ClusterVersion{State: version.Installed, Version: "1.15.1", InstallerSelector: "name=kyma-installer"},
+		Host:           "fake-kubeconfig-host",
+		Console:        "https://console.kyma.example.com",
+		Domain:         "kyma.example.com",
+		AdminEmail:     "admin@fake.com",
+		AdminPassword:  "1234-super-secure",
+	}, r, "the summary of an earlier installation has no duration and no warning about the DNS of the domain")
+}
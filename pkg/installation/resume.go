@@ -0,0 +1,146 @@
+package installation
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/kyma-project/cli/internal/cli"
+	apiErrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// installInfoStagesKey holds the stages completed by the runs of an installation, next to the created resources
+	installInfoStagesKey = "completedStages"
+
+	// stageInstallerImage is the part of the preparation which a later run skips if the local sources did not change
+	stageInstallerImage = "building the Kyma Installer image"
+)
+
+// completedStage marks a stage which completed in a run of the installation
+type completedStage struct {
+	// Fingerprint identifies the inputs of the stage, the stage is only skipped by later runs with the same inputs
+	Fingerprint string    `json:"fingerprint,omitempty"`
+	CompletedAt time.Time `json:"completedAt"`
+	CLIVersion  string    `json:"cliVersion,omitempty"`
+}
+
+// loadCompletedStages reads the stages completed by a previous run of the installation which did not finish, unless --from-scratch is set.
+// The previous run is logged, so that users know what is skipped.
+func (i *Installation) loadCompletedStages() {
+	i.completedStages = map[string]completedStage{}
+	if i.Options.DryRun {
+		return
+	}
+	info, err := i.K8s.Static().CoreV1().ConfigMaps(i.installerNamespace()).Get(context.Background(), installInfoName, metav1.GetOptions{})
+	if err != nil {
+		if !apiErrors.IsNotFound(err) && i.currentStep != nil {
+			i.currentStep.LogErrorf("Warning: unable to read the stages of a previous installation, all stages run again: %s", err)
+		}
+		return
+	}
+	previous := map[string]completedStage{}
+	if data := info.Data[installInfoStagesKey]; data != "" {
+		if err := json.Unmarshal([]byte(data), &previous); err != nil {
+			if i.currentStep != nil {
+				i.currentStep.LogErrorf("Warning: unable to parse the stages of a previous installation, all stages run again: %s", err)
+			}
+			return
+		}
+	}
+	if len(previous) == 0 {
+		return
+	}
+	if i.Options.FromScratch {
+		if i.currentStep != nil {
+			i.currentStep.LogInfo("Ignoring the stages completed by a previous installation attempt, as --from-scratch is set")
+		}
+		return
+	}
+	i.completedStages = previous
+	if i.currentStep == nil {
+		return
+	}
+
+	var names []string
+	var last completedStage
+	for name, s := range previous {
+		names = append(names, name)
+		if s.CompletedAt.After(last.CompletedAt) {
+			last = s
+		}
+	}
+	sort.Strings(names)
+	cli := last.CLIVersion
+	if cli == "" {
+		cli = "N/A"
+	}
+	i.currentStep.LogInfof("A previous installation attempt (Kyma CLI %s, %s) completed: %s. The build of the Kyma Installer image is skipped if its sources are unchanged, use --from-scratch to build it again",
+		cli, last.CompletedAt.Format(time.RFC3339), strings.Join(names, ", "))
+}
+
+// completeStage records that the stage completed with the given inputs, so that a later run can skip it.
+// A marker which cannot be written only makes the next run slower, so the error is logged as a warning.
+func (i *Installation) completeStage(name, fingerprint string) {
+	if i.Options.DryRun {
+		return
+	}
+	if i.completedStages == nil {
+		i.completedStages = map[string]completedStage{}
+	}
+	i.completedStages[name] = completedStage{Fingerprint: fingerprint, CompletedAt: time.Now().UTC(), CLIVersion: cli.Version}
+	i.mergeRecordedResources()
+	if err := i.saveInstallInfo(); err != nil && i.currentStep != nil {
+		i.currentStep.LogErrorf("Warning: unable to record the completed stage '%s': %s", name, err)
+	}
+}
+
+// stageCompleted checks if a previous run completed the stage with the same inputs
+func (i *Installation) stageCompleted(name, fingerprint string) bool {
+	s, ok := i.completedStages[name]
+	return ok && !i.Options.FromScratch && s.Fingerprint == fingerprint
+}
+
+// installerImageFingerprint identifies the Kyma Installer image built from the local sources, by the image name and the state of the source files.
+// The files are identified by their path, size and modification time, so that the sources need not be read.
+func (i *Installation) installerImageFingerprint(imageName string) (string, error) {
+	h := sha256.New()
+	fmt.Fprintf(h, "%s\n", imageName)
+	err := filepath.Walk(i.Options.LocalSrcPath, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() && info.Name() == ".git" {
+			return filepath.SkipDir
+		}
+		if info.Mode().IsRegular() {
+			rel, _ := filepath.Rel(i.Options.LocalSrcPath, path)
+			fmt.Fprintf(h, "%s %d %d\n", filepath.ToSlash(rel), info.Size(), info.ModTime().UnixNano())
+		}
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%x", h.Sum(nil)), nil
+}
+
+// installerImageBuilt checks if a previous run built the image from the same sources. Images built for the local cluster
+// must still be known to its Docker daemon, pushed images are expected in the registry.
+func (i *Installation) installerImageBuilt(imageName, fingerprint string) bool {
+	if !i.stageCompleted(stageInstallerImage, fingerprint) {
+		return false
+	}
+	if !i.Options.IsLocal {
+		return true
+	}
+	exists, err := i.Docker.ImageExists(imageName)
+	return err == nil && exists
+}
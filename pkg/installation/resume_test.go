@@ -0,0 +1,108 @@
+package installation
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	k8sMocks "github.com/kyma-project/cli/internal/kube/mocks"
+	"github.com/kyma-project/cli/pkg/installation/mocks"
+	stepMocks "github.com/kyma-project/cli/pkg/step/mocks"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestCompletedStages(t *testing.T) {
+	t.Parallel()
+	static := fake.NewSimpleClientset(&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: installerNamespace()}})
+	kymaMock := &k8sMocks.KymaKube{}
+	kymaMock.On("Static").Return(static)
+
+	// nothing recorded yet
+	first := &Installation{K8s: kymaMock, Options: &Options{}, currentStep: &stepMocks.Step{}}
+	first.loadCompletedStages()
+	require.Empty(t, first.completedStages)
+	first.recordCreatedNamespace(installerNamespace())
+	first.completeStage(stageValidation, "")
+	first.completeStage(stageInstallerImage, "abc")
+
+	// a later CLI invocation skips the stages with the same inputs
+	s := &stepMocks.Step{}
+	later := &Installation{K8s: kymaMock, Options: &Options{}, currentStep: s}
+	later.loadCompletedStages()
+	require.Len(t, later.completedStages, 2)
+	require.True(t, later.stageCompleted(stageInstallerImage, "abc"))
+	require.False(t, later.stageCompleted(stageInstallerImage, "changed"), "stages with different inputs must run again")
+	require.False(t, later.stageCompleted(stagePreparation, ""))
+	require.Len(t, s.Infos(), 1)
+	require.Contains(t, s.Infos()[0], "building the Kyma Installer image, validating the configuration")
+
+	// recording a stage keeps the resources of the previous run
+	later.completeStage(stagePreparation, "")
+	recorded, err := later.loadInstallInfo()
+	require.NoError(t, err)
+	require.Equal(t, []CreatedResource{{APIVersion: "v1", Kind: "Namespace", Name: installerNamespace()}}, recorded)
+
+	// --from-scratch ignores them
+	fromScratch := &Installation{K8s: kymaMock, Options: &Options{FromScratch: true}, currentStep: &stepMocks.Step{}}
+	fromScratch.loadCompletedStages()
+	require.Empty(t, fromScratch.completedStages)
+
+	// the markers are gone once the installation succeeded
+	later.forgetCreatedResources()
+	_, err = static.CoreV1().ConfigMaps(installerNamespace()).Get(context.Background(), installInfoName, metav1.GetOptions{})
+	require.Error(t, err)
+}
+
+func TestCompletedStagesDryRun(t *testing.T) {
+	t.Parallel()
+	static := fake.NewSimpleClientset()
+	kymaMock := &k8sMocks.KymaKube{}
+	kymaMock.On("Static").Return(static)
+
+	i := &Installation{K8s: kymaMock, Options: &Options{DryRun: true}}
+	i.loadCompletedStages()
+	i.completeStage(stageValidation, "")
+	_, err := static.CoreV1().ConfigMaps(installerNamespace()).Get(context.Background(), installInfoName, metav1.GetOptions{})
+	require.Error(t, err, "a dry run must not write to the cluster")
+}
+
+func TestInstallerImageBuilt(t *testing.T) {
+	t.Parallel()
+	dir, err := ioutil.TempDir("", "installer-image")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, ".git"), 0700))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "Dockerfile"), []byte("FROM scratch"), 0600))
+
+	d := &mocks.KymaClient{}
+	i := &Installation{Docker: d, Options: &Options{IsLocal: true, LocalSrcPath: dir}}
+	fp, err := i.installerImageFingerprint("kyma-installer:local")
+	require.NoError(t, err)
+	other, err := i.installerImageFingerprint("eu.gcr.io/kyma-installer:local")
+	require.NoError(t, err)
+	require.NotEqual(t, fp, other, "the fingerprint must depend on the image")
+
+	// changes of the Git metadata do not change the image
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, ".git", "HEAD"), []byte("ref: refs/heads/master"), 0600))
+	unchanged, err := i.installerImageFingerprint("kyma-installer:local")
+	require.NoError(t, err)
+	require.Equal(t, fp, unchanged)
+
+	i.completedStages = map[string]completedStage{stageInstallerImage: {Fingerprint: fp, CompletedAt: time.Now()}}
+	d.On("ImageExists", "kyma-installer:local").Return(false, nil).Once()
+	require.False(t, i.installerImageBuilt("kyma-installer:local", fp), "the image is no longer known to the Docker daemon")
+	d.On("ImageExists", "kyma-installer:local").Return(true, nil)
+	require.True(t, i.installerImageBuilt("kyma-installer:local", fp))
+
+	// the sources changed
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "Dockerfile"), []byte("FROM alpine"), 0600))
+	changed, err := i.installerImageFingerprint("kyma-installer:local")
+	require.NoError(t, err)
+	require.False(t, i.installerImageBuilt("kyma-installer:local", changed))
+}
@@ -0,0 +1,140 @@
+package installation
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// validateScheduling ensures that the node selector and the tolerations for the Kyma Installer pod are valid
+func (i *Installation) validateScheduling() error {
+	for k, v := range i.Options.InstallerNodeSelector {
+		if errs := validation.IsQualifiedName(k); len(errs) > 0 {
+			return fmt.Errorf("invalid node selector key '%s': %s", k, strings.Join(errs, ", "))
+		}
+		if errs := validation.IsValidLabelValue(v); len(errs) > 0 {
+			return fmt.Errorf("invalid node selector value '%s': %s", v, strings.Join(errs, ", "))
+		}
+	}
+	for _, t := range i.Options.InstallerTolerations {
+		if _, err := parseToleration(t); err != nil {
+			return err
+		}
+	}
+	if errs := validation.IsDNS1123Subdomain(i.Options.InstallerPriorityClass); i.Options.InstallerPriorityClass != "" && len(errs) > 0 {
+		return fmt.Errorf("invalid priority class '%s': %s", i.Options.InstallerPriorityClass, strings.Join(errs, ", "))
+	}
+	return nil
+}
+
+// schedulingConfigured checks if the scheduling of the Kyma Installer pod is customized
+func (i *Installation) schedulingConfigured() bool {
+	return len(i.Options.InstallerNodeSelector) > 0 || len(i.Options.InstallerTolerations) > 0 || i.Options.InstallerPriorityClass != ""
+}
+
+// parseToleration parses a toleration in the taint format "key=value:Effect", or "key:Effect" to tolerate the taint with any value
+func parseToleration(toleration string) (corev1.Toleration, error) {
+	return parseTaintFormat("toleration", toleration)
+}
+
+// parseTaintFormat parses the format "key=value:Effect" or "key:Effect" shared by tolerations and taints, kind is the name used in errors
+func parseTaintFormat(kind, toleration string) (corev1.Toleration, error) {
+	invalid := func(reason string) (corev1.Toleration, error) {
+		return corev1.Toleration{}, fmt.Errorf("invalid %s '%s': %s. Use the format 'key=value:Effect' or 'key:Effect'", kind, toleration, reason)
+	}
+
+	n := strings.LastIndex(toleration, ":")
+	if n < 0 {
+		return invalid("the effect is missing")
+	}
+	t := corev1.Toleration{Key: toleration[:n], Operator: corev1.TolerationOpExists, Effect: corev1.TaintEffect(toleration[n+1:])}
+	if n := strings.Index(t.Key, "="); n >= 0 {
+		t.Key, t.Value, t.Operator = t.Key[:n], t.Key[n+1:], corev1.TolerationOpEqual
+		if errs := validation.IsValidLabelValue(t.Value); len(errs) > 0 {
+			return invalid(strings.Join(errs, ", "))
+		}
+	}
+	if errs := validation.IsQualifiedName(t.Key); len(errs) > 0 {
+		return invalid(strings.Join(errs, ", "))
+	}
+	switch t.Effect {
+	case corev1.TaintEffectNoSchedule, corev1.TaintEffectPreferNoSchedule, corev1.TaintEffectNoExecute:
+	default:
+		return invalid(fmt.Sprintf("the effect must be one of %s, %s or %s", corev1.TaintEffectNoSchedule, corev1.TaintEffectPreferNoSchedule, corev1.TaintEffectNoExecute))
+	}
+	return t, nil
+}
+
+// insertScheduling adds the node selector, the tolerations and the priority class to the pod spec of the Kyma Installer deployment
+func insertScheduling(installerFile *File, nodeSelector map[string]string, tolerations []string, priorityClass string) error {
+	spec, ok := installerPodSpec(installerFile)
+	if !ok {
+		return errors.New("unable to set the scheduling of the Kyma Installer 'Deployment'")
+	}
+
+	if len(nodeSelector) > 0 {
+		selector, _ := spec["nodeSelector"].(map[interface{}]interface{})
+		if selector == nil {
+			selector = map[interface{}]interface{}{}
+		}
+		for k, v := range nodeSelector {
+			selector[k] = v
+		}
+		spec["nodeSelector"] = selector
+	}
+
+	existing, _ := spec["tolerations"].([]interface{})
+	for _, t := range tolerations {
+		toleration, err := parseToleration(t)
+		if err != nil {
+			return err
+		}
+		entry := map[interface{}]interface{}{"key": toleration.Key, "operator": string(toleration.Operator), "effect": string(toleration.Effect)}
+		if toleration.Value != "" {
+			entry["value"] = toleration.Value
+		}
+		if !containsEntry(existing, entry) {
+			existing = append(existing, entry)
+		}
+	}
+	if len(existing) > 0 {
+		spec["tolerations"] = existing
+	}
+
+	if priorityClass != "" {
+		spec["priorityClassName"] = priorityClass
+	}
+	return nil
+}
+
+// installerPodSpec returns the pod spec of the deployment running the kyma-installer-container
+func installerPodSpec(installerFile *File) (map[interface{}]interface{}, bool) {
+	for _, config := range installerFile.Content {
+		if kind, ok := config["kind"]; !ok || kind != "Deployment" {
+			continue
+		}
+		spec, _ := config["spec"].(map[interface{}]interface{})
+		template, _ := spec["template"].(map[interface{}]interface{})
+		podSpec, _ := template["spec"].(map[interface{}]interface{})
+		containers, _ := podSpec["containers"].([]interface{})
+		for _, c := range containers {
+			if container, ok := c.(map[interface{}]interface{}); ok && container["name"] == "kyma-installer-container" {
+				return podSpec, true
+			}
+		}
+	}
+	return nil, false
+}
+
+func containsEntry(list []interface{}, entry map[interface{}]interface{}) bool {
+	for _, e := range list {
+		if reflect.DeepEqual(e, entry) {
+			return true
+		}
+	}
+	return false
+}
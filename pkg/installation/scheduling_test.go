@@ -0,0 +1,90 @@
+package installation
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestParseToleration(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		toleration string
+		expected   corev1.Toleration
+		valid      bool
+	}{
+		{toleration: "dedicated=infra:NoSchedule", valid: true, expected: corev1.Toleration{Key: "dedicated", Operator: corev1.TolerationOpEqual, Value: "infra", Effect: corev1.TaintEffectNoSchedule}},
+		{toleration: "node.kubernetes.io/unreachable:NoExecute", valid: true, expected: corev1.Toleration{Key: "node.kubernetes.io/unreachable", Operator: corev1.TolerationOpExists, Effect: corev1.TaintEffectNoExecute}},
+		{toleration: "dedicated=:PreferNoSchedule", valid: true, expected: corev1.Toleration{Key: "dedicated", Operator: corev1.TolerationOpEqual, Effect: corev1.TaintEffectPreferNoSchedule}},
+		{toleration: "dedicated=infra"},
+		{toleration: "dedicated=infra:Sometimes"},
+		{toleration: "=infra:NoSchedule"},
+		{toleration: "dedicated=in fra:NoSchedule"},
+	}
+	for _, tc := range tests {
+		toleration, err := parseToleration(tc.toleration)
+		if !tc.valid {
+			require.Error(t, err, tc.toleration)
+			continue
+		}
+		require.NoError(t, err, tc.toleration)
+		require.Equal(t, tc.expected, toleration)
+	}
+}
+
+func TestValidateScheduling(t *testing.T) {
+	t.Parallel()
+	i := &Installation{Options: &Options{
+		InstallerNodeSelector:  map[string]string{"pool": "infra"},
+		InstallerTolerations:   []string{"dedicated=infra:NoSchedule"},
+		InstallerPriorityClass: "system-cluster-critical",
+	}}
+	require.NoError(t, i.validateScheduling())
+	require.True(t, i.schedulingConfigured())
+
+	i.Options.InstallerNodeSelector = map[string]string{"pool": "in fra"}
+	require.Error(t, i.validateScheduling())
+
+	i.Options.InstallerNodeSelector = nil
+	i.Options.InstallerPriorityClass = "Critical"
+	require.Error(t, i.validateScheduling())
+
+	require.False(t, (&Installation{Options: &Options{}}).schedulingConfigured())
+}
+
+func TestInsertScheduling(t *testing.T) {
+	t.Parallel()
+	file := &File{Content: []map[string]interface{}{
+		{"kind": "ServiceAccount"},
+		{
+			"kind": "Deployment",
+			"spec": map[interface{}]interface{}{
+				"template": map[interface{}]interface{}{
+					"spec": map[interface{}]interface{}{
+						"containers": []interface{}{
+							map[interface{}]interface{}{"name": "kyma-installer-container", "image": "installer:1.15.1"},
+						},
+						"tolerations": []interface{}{
+							map[interface{}]interface{}{"key": "dedicated", "operator": "Equal", "value": "infra", "effect": "NoSchedule"},
+						},
+					},
+				},
+			},
+		},
+	}}
+
+	err := insertScheduling(file, map[string]string{"pool": "infra"}, []string{"dedicated=infra:NoSchedule", "gpu:NoExecute"}, "system-cluster-critical")
+	require.NoError(t, err)
+	spec, ok := installerPodSpec(file)
+	require.True(t, ok)
+	require.Equal(t, map[interface{}]interface{}{"pool": "infra"}, spec["nodeSelector"])
+	require.Equal(t, []interface{}{
+		map[interface{}]interface{}{"key": "dedicated", "operator": "Equal", "value": "infra", "effect": "NoSchedule"},
+		map[interface{}]interface{}{"key": "gpu", "operator": "Exists", "effect": "NoExecute"},
+	}, spec["tolerations"], "existing tolerations must not be duplicated")
+	require.Equal(t, "system-cluster-critical", spec["priorityClassName"])
+
+	err = insertScheduling(&File{Content: []map[string]interface{}{{"kind": "ServiceAccount"}}}, map[string]string{"pool": "infra"}, nil, "")
+	require.Error(t, err)
+}
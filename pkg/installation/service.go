@@ -3,12 +3,20 @@ package installation
 import (
 	"context"
 	"fmt"
+	"net/http"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/pkg/errors"
+	"gopkg.in/yaml.v2"
 
 	"github.com/kyma-incubator/hydroform/install/installation"
 	"github.com/kyma-project/kyma/components/kyma-operator/pkg/apis/installer/v1alpha1"
+	installationClientset "github.com/kyma-project/kyma/components/kyma-operator/pkg/client/clientset/versioned"
+	apiErrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 )
 
@@ -20,10 +28,11 @@ const (
 //go:generate mockery --name Service
 
 type Service interface {
-	CheckInstallationState(kubeconfig *rest.Config) (installation.InstallationState, error)
-	TriggerInstallation(installerYaml string, installerCRYaml string, configuration installation.Configuration) error
-	TriggerUpgrade(installerYaml string, installerCRYaml string, configuration installation.Configuration) error
+	CheckInstallationState(kubeconfig *rest.Config, name string) (installation.InstallationState, error)
+	TriggerInstallation(ctx context.Context, installerYaml string, installerCRYaml string, configuration installation.Configuration) error
+	TriggerUpgrade(ctx context.Context, installerYaml string, installerCRYaml string, configuration installation.Configuration) error
 	TriggerUninstall(kubeconfig *rest.Config) error
+	RetryInstallation(name string) error
 }
 
 func NewInstallationService(kubeconfig *rest.Config, installationTimeout time.Duration, clusterCleanupResourceSelector string, componentsConfig []v1alpha1.KymaComponent) (Service, error) {
@@ -31,12 +40,20 @@ func NewInstallationService(kubeconfig *rest.Config, installationTimeout time.Du
 	if len(componentsConfig) > 0 {
 		opts = append(opts, installation.WithInstallationCRModification(GetInstallationCRModificationFunc(componentsConfig)))
 	}
+	// the installer SDK takes no context, so the requests of its clients are bound to the context of the running trigger
+	apply := &applyContext{}
+	kubeconfig = rest.CopyConfig(kubeconfig)
+	kubeconfig.Wrap(func(rt http.RoundTripper) http.RoundTripper {
+		return &contextTransport{apply: apply, next: rt}
+	})
 	installer, err := installation.NewKymaInstaller(kubeconfig, opts...)
 	if err != nil {
 		return nil, err
 	}
 
 	return &installationService{
+		apply:                          apply,
+		kubeconfig:                     kubeconfig,
 		kymaInstallationTimeout:        installationTimeout,
 		kymaInstaller:                  *installer,
 		clusterCleanupResourceSelector: clusterCleanupResourceSelector,
@@ -44,26 +61,34 @@ func NewInstallationService(kubeconfig *rest.Config, installationTimeout time.Du
 }
 
 type installationService struct {
+	apply                          *applyContext
+	kubeconfig                     *rest.Config
 	kymaInstallationTimeout        time.Duration
 	kymaInstaller                  installation.Installer
 	clusterCleanupResourceSelector string
 }
 
-func (s *installationService) TriggerInstallation(installerYaml string, installerCRYaml string, configuration installation.Configuration) error {
-	return s.triggerAction(installerYaml, installerCRYaml, configuration, s.kymaInstaller.PrepareInstallation, installAction)
+func (s *installationService) TriggerInstallation(ctx context.Context, installerYaml string, installerCRYaml string, configuration installation.Configuration) error {
+	return s.triggerAction(ctx, installerYaml, installerCRYaml, configuration, s.kymaInstaller.PrepareInstallation, installAction)
 }
 
-func (s *installationService) TriggerUpgrade(installerYaml string, installerCRYaml string, configuration installation.Configuration) error {
-	return s.triggerAction(installerYaml, installerCRYaml, configuration, s.kymaInstaller.PrepareUpgrade, upgradeAction)
+func (s *installationService) TriggerUpgrade(ctx context.Context, installerYaml string, installerCRYaml string, configuration installation.Configuration) error {
+	return s.triggerAction(ctx, installerYaml, installerCRYaml, configuration, s.kymaInstaller.PrepareUpgrade, upgradeAction)
 }
 
 func (s *installationService) triggerAction(
+	ctx context.Context,
 	installerYaml string,
 	installerCRYaml string,
 	configuration installation.Configuration,
 	prepareFunction func(installation.Installation) error,
 	actionName string) error {
 
+	if s.apply != nil {
+		s.apply.set(ctx)
+		defer s.apply.set(nil)
+	}
+
 	installationConfig := installation.Installation{
 		InstallerYaml:   installerYaml,
 		InstallerCRYaml: installerCRYaml,
@@ -75,7 +100,15 @@ func (s *installationService) triggerAction(
 		return errors.Wrap(err, fmt.Sprintf("Failed to prepare %s", actionName))
 	}
 
-	installationCtx, cancel := context.WithCancel(context.Background())
+	// The installer SDK only starts Installation CRs with the default name
+	if name := installationNameFromYaml(installerCRYaml); name != "" && name != defaultInstallationName {
+		if err := s.startInstallation(name); err != nil {
+			return errors.Wrap(err, fmt.Sprintf("Failed to start Kyma %s", actionName))
+		}
+		return nil
+	}
+
+	installationCtx, cancel := context.WithCancel(ctx)
 	defer cancel()
 
 	// We are not waiting for events, just triggering installation
@@ -87,8 +120,154 @@ func (s *installationService) triggerAction(
 	return nil
 }
 
-func (s *installationService) CheckInstallationState(kubeconfig *rest.Config) (installation.InstallationState, error) {
-	return installation.CheckInstallationState(kubeconfig)
+// applyContext holds the context of the running trigger, the requests sent without it are not bound to any context
+type applyContext struct {
+	mu  sync.RWMutex
+	ctx context.Context
+}
+
+func (a *applyContext) set(ctx context.Context) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.ctx = ctx
+}
+
+func (a *applyContext) get() context.Context {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return a.ctx
+}
+
+// contextTransport cancels the requests of the installer SDK once the context of the running trigger is done
+type contextTransport struct {
+	apply *applyContext
+	next  http.RoundTripper
+}
+
+func (t *contextTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if ctx := t.apply.get(); ctx != nil {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		req = req.WithContext(ctx)
+	}
+	return t.next.RoundTrip(req)
+}
+
+func (s *installationService) CheckInstallationState(kubeconfig *rest.Config, name string) (installation.InstallationState, error) {
+	if name == "" || name == defaultInstallationName {
+		return installation.CheckInstallationState(kubeconfig)
+	}
+
+	installationClient, err := installationClientset.NewForConfig(kubeconfig)
+	if err != nil {
+		return installation.InstallationState{}, err
+	}
+	namespace, err := clusterInstallationNamespace(kubeconfig)
+	if err != nil {
+		return installation.InstallationState{}, err
+	}
+
+	installationCR, err := installationClient.InstallerV1alpha1().Installations(namespace).Get(context.Background(), name, metav1.GetOptions{})
+	if err != nil {
+		if apiErrors.IsNotFound(err) {
+			return installation.InstallationState{
+				State:       installation.NoInstallationState,
+				Description: fmt.Sprintf("Kyma Installation CR '%s' not found on the cluster", name),
+			}, nil
+		}
+		return installation.InstallationState{}, err
+	}
+
+	return getInstallationState(installationCR)
+}
+
+// RetryInstallation labels the Installation CR again, so that the Kyma Installer processes it again after it failed
+func (s *installationService) RetryInstallation(name string) error {
+	return s.startInstallation(name)
+}
+
+// startInstallation labels the Installation CR with the given name, so that the Kyma Installer starts processing it
+func (s *installationService) startInstallation(name string) error {
+	installationClient, err := installationClientset.NewForConfig(s.kubeconfig)
+	if err != nil {
+		return err
+	}
+	namespace, err := clusterInstallationNamespace(s.kubeconfig)
+	if err != nil {
+		return err
+	}
+	installations := installationClient.InstallerV1alpha1().Installations(namespace)
+
+	installationCR, err := installations.Get(context.Background(), name, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	if installationCR.Status.State == v1alpha1.StateInProgress {
+		return fmt.Errorf("failed to trigger installation, installation already in progress")
+	}
+
+	if installationCR.Labels == nil {
+		installationCR.Labels = map[string]string{}
+	}
+	installationCR.Labels["action"] = "install"
+
+	_, err = installations.Update(context.Background(), installationCR, metav1.UpdateOptions{})
+	return err
+}
+
+// clusterInstallationNamespace returns the namespace of the Installation CRs of the Kyma version on the cluster
+func clusterInstallationNamespace(kubeconfig *rest.Config) (string, error) {
+	k8s, err := kubernetes.NewForConfig(kubeconfig)
+	if err != nil {
+		return "", err
+	}
+	return clusterMetadata(k8s).InstallationNamespace, nil
+}
+
+// getInstallationState converts the status of an Installation CR to the installation state used by the installer SDK
+func getInstallationState(installationCR *v1alpha1.Installation) (installation.InstallationState, error) {
+	switch installationCR.Status.State {
+	case v1alpha1.StateEmpty, v1alpha1.StateInstalled, v1alpha1.StateInProgress:
+		return installation.InstallationState{
+			State:       string(installationCR.Status.State),
+			Description: installationCR.Status.Description,
+		}, nil
+	case v1alpha1.StateError:
+		installationErr := installation.InstallationError{
+			ShortMessage: fmt.Sprintf("installation error occurred: %s", installationCR.Status.Description),
+			ErrorEntries: make([]installation.ErrorEntry, 0, len(installationCR.Status.ErrorLog)),
+		}
+		for _, errLog := range installationCR.Status.ErrorLog {
+			installationErr.ErrorEntries = append(installationErr.ErrorEntries, installation.ErrorEntry{
+				Component:   errLog.Component,
+				Log:         errLog.Log,
+				Occurrences: errLog.Occurrences,
+			})
+		}
+		return installation.InstallationState{}, installationErr
+	default:
+		return installation.InstallationState{}, fmt.Errorf("invalid installation state: %s", installationCR.Status.State)
+	}
+}
+
+// installationNameFromYaml returns the name of the Installation CR defined in the given YAML, or an empty string if no name is found
+func installationNameFromYaml(installerCRYaml string) string {
+	decoder := yaml.NewDecoder(strings.NewReader(installerCRYaml))
+	for {
+		cr := struct {
+			Kind     string `yaml:"kind"`
+			Metadata struct {
+				Name string `yaml:"name"`
+			} `yaml:"metadata"`
+		}{}
+		if err := decoder.Decode(&cr); err != nil {
+			return ""
+		}
+		if cr.Kind == "Installation" {
+			return cr.Metadata.Name
+		}
+	}
 }
 
 func (s *installationService) TriggerUninstall(kubeconfig *rest.Config) error {
@@ -1,8 +1,11 @@
 package installation
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 	"time"
 
@@ -73,7 +76,7 @@ func TestTriggerInstallation(t *testing.T) {
 	}
 
 	for _, c := range cases {
-		err := c.service.TriggerInstallation(c.installCfg.InstallerYaml, c.installCfg.InstallerCRYaml, c.installCfg.Configuration)
+		err := c.service.TriggerInstallation(context.Background(), c.installCfg.InstallerYaml, c.installCfg.InstallerCRYaml, c.installCfg.Configuration)
 		if c.expectedErr != "" {
 			require.EqualError(t, err, c.expectedErr, fmt.Sprintf("Test Case: %s", c.name))
 		} else {
@@ -141,7 +144,7 @@ func TestTriggerUpgrade(t *testing.T) {
 	}
 
 	for _, c := range cases {
-		err := c.service.TriggerUpgrade(c.installCfg.InstallerYaml, c.installCfg.InstallerCRYaml, c.installCfg.Configuration)
+		err := c.service.TriggerUpgrade(context.Background(), c.installCfg.InstallerYaml, c.installCfg.InstallerCRYaml, c.installCfg.Configuration)
 		if c.expectedErr != "" {
 			require.EqualError(t, err, c.expectedErr, fmt.Sprintf("Test Case: %s", c.name))
 		} else {
@@ -150,6 +153,31 @@ func TestTriggerUpgrade(t *testing.T) {
 	}
 }
 
+func TestContextTransport(t *testing.T) {
+	t.Parallel()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+	apply := &applyContext{}
+	client := &http.Client{Transport: &contextTransport{apply: apply, next: http.DefaultTransport}}
+
+	// without a running trigger the requests are sent as they are
+	resp, err := client.Get(server.URL)
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	apply.set(ctx)
+	resp, err = client.Get(server.URL)
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	// once the trigger is canceled, the requests fail
+	cancel()
+	_, err = client.Get(server.URL)
+	require.Error(t, err)
+	require.True(t, errors.Is(err, context.Canceled))
+}
+
 func TestGetInstallationCRModificationFunc(t *testing.T) {
 	t.Parallel()
 	comps := []v1alpha1.KymaComponent{
@@ -168,3 +196,56 @@ func TestGetInstallationCRModificationFunc(t *testing.T) {
 
 	require.Equal(t, comps, i.Spec.Components)
 }
+
+func TestInstallationNameFromYaml(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		name     string
+		yaml     string
+		expected string
+	}{
+		{
+			name:     "Installation CR",
+			yaml:     "apiVersion: installer.kyma-project.io/v1alpha1\nkind: Installation\nmetadata:\n  name: custom-installation\n",
+			expected: "custom-installation",
+		},
+		{
+			name:     "Installation CR after other documents",
+			yaml:     "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: cm\n---\nkind: Installation\nmetadata:\n  name: custom-installation\n",
+			expected: "custom-installation",
+		},
+		{
+			name:     "No Installation CR",
+			yaml:     "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: cm\n",
+			expected: "",
+		},
+		{
+			name:     "Invalid YAML",
+			yaml:     "Installer CR stuff",
+			expected: "",
+		},
+	}
+
+	for _, c := range cases {
+		require.Equal(t, c.expected, installationNameFromYaml(c.yaml), fmt.Sprintf("Test Case: %s", c.name))
+	}
+}
+
+func TestGetInstallationState(t *testing.T) {
+	t.Parallel()
+	state, err := getInstallationState(&v1alpha1.Installation{Status: v1alpha1.InstallationStatus{State: v1alpha1.StateInProgress, Description: "install component"}})
+	require.NoError(t, err)
+	require.Equal(t, installation.InstallationState{State: "InProgress", Description: "install component"}, state)
+
+	_, err = getInstallationState(&v1alpha1.Installation{Status: v1alpha1.InstallationStatus{
+		State:       v1alpha1.StateError,
+		Description: "failed",
+		ErrorLog:    []v1alpha1.ErrorLogEntry{{Component: "cmp", Log: "boom", Occurrences: 2}},
+	}})
+	installationErr := installation.InstallationError{}
+	require.True(t, errors.As(err, &installationErr))
+	require.Equal(t, []installation.ErrorEntry{{Component: "cmp", Log: "boom", Occurrences: 2}}, installationErr.ErrorEntries)
+
+	_, err = getInstallationState(&v1alpha1.Installation{Status: v1alpha1.InstallationStatus{State: "Unknown"}})
+	require.Error(t, err)
+}
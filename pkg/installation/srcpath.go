@@ -0,0 +1,49 @@
+package installation
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// defaultSourcePath finds the Kyma sources if --src-path is not set. The locations are tried in order:
+// the GOPATH layout, the conventional ~/.kyma/sources/kyma directory, and the current working directory.
+// A location is only used if it looks like a Kyma repository, otherwise the error lists all locations which were tried.
+func defaultSourcePath() (string, error) {
+	var tried []string
+	for _, candidate := range sourcePathCandidates() {
+		if looksLikeKymaSources(candidate) {
+			return candidate, nil
+		}
+		tried = append(tried, candidate)
+	}
+	return "", fmt.Errorf("no 'src-path' configured and no applicable default found. Tried: %s. Use --src-path to set the path to the Kyma sources", strings.Join(tried, ", "))
+}
+
+func sourcePathCandidates() []string {
+	var candidates []string
+	home, _ := os.UserHomeDir()
+	goPath := os.Getenv("GOPATH")
+	if goPath == "" && home != "" {
+		// the default GOPATH of the Go tools
+		goPath = filepath.Join(home, "go")
+	}
+	// GOPATH can hold several directories, each of them may contain the sources
+	for _, dir := range filepath.SplitList(goPath) {
+		candidates = append(candidates, filepath.Join(dir, "src", "github.com", "kyma-project", "kyma"))
+	}
+	if home != "" {
+		candidates = append(candidates, filepath.Join(home, ".kyma", "sources", "kyma"))
+	}
+	if wd, err := os.Getwd(); err == nil {
+		candidates = append(candidates, wd)
+	}
+	return candidates
+}
+
+// looksLikeKymaSources checks if the directory contains the installation resources of a Kyma repository
+func looksLikeKymaSources(dir string) bool {
+	info, err := os.Stat(filepath.Join(dir, "installation", "resources"))
+	return err == nil && info.IsDir()
+}
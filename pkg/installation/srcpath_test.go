@@ -0,0 +1,56 @@
+package installation
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDefaultSourcePath(t *testing.T) {
+	// not parallel: the environment variables are modified
+	dir, err := ioutil.TempDir("", "kyma-sources-")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	for _, env := range []string{"GOPATH", "HOME"} {
+		value, ok := os.LookupEnv(env)
+		defer func(env string) {
+			if ok {
+				os.Setenv(env, value)
+			} else {
+				os.Unsetenv(env)
+			}
+		}(env)
+	}
+	home := filepath.Join(dir, "home")
+	require.NoError(t, os.Setenv("HOME", home))
+
+	// no sources in any location
+	require.NoError(t, os.Setenv("GOPATH", filepath.Join(dir, "gopath")))
+	_, err = defaultSourcePath()
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "no 'src-path' configured and no applicable default found")
+	require.Contains(t, err.Error(), filepath.Join(dir, "gopath", "src", "github.com", "kyma-project", "kyma"))
+	require.Contains(t, err.Error(), filepath.Join(home, ".kyma", "sources", "kyma"))
+	require.Contains(t, err.Error(), "--src-path")
+
+	// sources in the conventional location
+	conventional := filepath.Join(home, ".kyma", "sources", "kyma")
+	require.NoError(t, os.MkdirAll(filepath.Join(conventional, "installation", "resources"), 0700))
+	srcPath, err := defaultSourcePath()
+	require.NoError(t, err)
+	require.Equal(t, conventional, srcPath)
+
+	// the GOPATH layout takes precedence
+	goPathSources := filepath.Join(dir, "gopath", "src", "github.com", "kyma-project", "kyma")
+	require.NoError(t, os.MkdirAll(filepath.Join(goPathSources, "installation", "resources"), 0700))
+	srcPath, err = defaultSourcePath()
+	require.NoError(t, err)
+	require.Equal(t, goPathSources, srcPath)
+
+	// without GOPATH, the default GOPATH of the Go tools is used
+	require.NoError(t, os.Unsetenv("GOPATH"))
+	require.Equal(t, filepath.Join(home, "go", "src", "github.com", "kyma-project", "kyma"), sourcePathCandidates()[0])
+}
@@ -0,0 +1,171 @@
+package installation
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/kyma-project/cli/internal/trace"
+	"github.com/kyma-project/cli/pkg/step"
+)
+
+const (
+	stageLock             = "acquiring the cluster lock"
+	stagePrevInstallation = "checking the previous installation"
+	stageValidation       = "validating the configuration"
+	stagePreparation      = "preparing the installation files"
+	stageExport           = "exporting the manifests"
+	stageTrigger          = "triggering the installation"
+)
+
+// StageError is the error of a single stage of the installation.
+type StageError struct {
+	// Stage is the name of the stage.
+	Stage string
+	// Err holds the error of the stage, it is nil if the stage was skipped.
+	Err error
+	// SkippedBecause holds the failed stage the skipped stage depends on.
+	SkippedBecause string
+}
+
+// StageErrors is the consolidated report of all failed and skipped stages, if the installation continues after errors.
+type StageErrors []StageError
+
+func (e StageErrors) Error() string {
+	lines := []string{fmt.Sprintf("%d stages of the installation did not succeed:", len(e))}
+	for _, s := range e {
+		if s.Err != nil {
+			lines = append(lines, fmt.Sprintf("  - %s failed: %s", s.Stage, s.Err))
+		} else {
+			lines = append(lines, fmt.Sprintf("  - %s skipped, because %s failed", s.Stage, s.SkippedBecause))
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// StageAborted is returned if the user aborts the installation when a stage is explained
+type StageAborted struct {
+	Stage string
+}
+
+func (e StageAborted) Error() string {
+	return fmt.Sprintf("installation aborted before %s", e.Stage)
+}
+
+// stages runs the stages of the installation.
+// By default the first failing stage stops the installation. With continueOnError, failed stages are recorded and logged on the step,
+// and the stages depending on them are skipped, so that all problems are reported in one run.
+type stages struct {
+	continueOnError bool
+	step            step.Step
+	failed          map[string]bool
+	errs            StageErrors
+	// describe returns the operations of a stage, if set they are logged before the stage runs
+	describe func(name string) []string
+	// prompt asks the user whether to continue, skip or abort an explained stage, it is nil in non-interactive mode
+	prompt func(msg string) (string, error)
+	// skipped holds the stages skipped by the user, and the stages depending on them
+	skipped map[string]bool
+	// completed is called once a stage succeeded, e.g. to record it for a later run of the installation
+	completed func(name string)
+}
+
+func newStages(continueOnError bool, s step.Step) *stages {
+	return &stages{continueOnError: continueOnError, step: s, failed: map[string]bool{}, skipped: map[string]bool{}}
+}
+
+// explain describes the stages before they run. If interactive, the user decides for each stage whether to continue, skip or abort.
+func (s *stages) explain(describe func(name string) []string, interactive bool) {
+	s.describe = describe
+	if interactive {
+		s.prompt = s.step.Prompt
+	}
+}
+
+// run executes the stage unless one of its dependencies failed.
+// The error is returned only if the installation stops at the first error.
+func (s *stages) run(name string, dependsOn []string, fn func() error) error {
+	for _, d := range dependsOn {
+		if s.failed[d] {
+			s.failed[name] = true
+			s.errs = append(s.errs, StageError{Stage: name, SkippedBecause: d})
+			s.step.LogErrorf("Skipped %s, because %s failed", name, d)
+			trace.Start("stage: "+name, trace.Attributes{"skipped": true, "skippedBecause": d}).End(nil)
+			return nil
+		}
+		if s.skipped[d] {
+			s.skipped[name] = true
+			s.step.LogInfof("Skipped %s, because %s was skipped", name, d)
+			return nil
+		}
+	}
+	if s.describe != nil {
+		proceed, err := s.confirm(name)
+		if err != nil {
+			return err
+		}
+		if !proceed {
+			s.skipped[name] = true
+			s.step.LogInfof("Skipped %s", name)
+			return nil
+		}
+	}
+	span := trace.Start("stage: "+name, trace.Attributes{"skipped": false})
+	err := fn()
+	span.End(err)
+	if err == nil {
+		if s.completed != nil {
+			s.completed(name)
+		}
+		return nil
+	}
+	if !s.continueOnError {
+		return err
+	}
+	s.failed[name] = true
+	s.errs = append(s.errs, StageError{Stage: name, Err: err})
+	s.step.LogErrorf("Failed %s: %s", name, err)
+	return nil
+}
+
+// confirm logs the operations of the stage and asks whether to run it. A failing prompt, e.g. without a terminal, aborts the installation.
+func (s *stages) confirm(name string) (bool, error) {
+	s.step.LogInfof("Next: %s", name)
+	for _, op := range s.describe(name) {
+		s.step.LogInfof("  - %s", op)
+	}
+	if s.prompt == nil {
+		return true, nil
+	}
+	for {
+		answer, err := s.prompt("Continue, skip or abort? [C/s/a]: ")
+		if err != nil {
+			return false, StageAborted{Stage: name}
+		}
+		switch strings.ToLower(answer) {
+		case "", "c", "continue":
+			return true, nil
+		case "s", "skip":
+			return false, nil
+		case "a", "abort":
+			return false, StageAborted{Stage: name}
+		}
+	}
+}
+
+// ok checks if the stage ran successfully, or was not needed in this run
+func (s *stages) ok(name string) bool {
+	return !s.failed[name] && !s.skipped[name]
+}
+
+// userSkipped checks if the user skipped the stage, or a stage it depends on
+func (s *stages) userSkipped(name string) bool {
+	return s.skipped[name]
+}
+
+// err returns the consolidated report of the recorded errors, or nil if all stages succeeded
+func (s *stages) err() error {
+	if len(s.errs) == 0 {
+		return nil
+	}
+	return s.errs
+}
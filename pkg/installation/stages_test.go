@@ -0,0 +1,89 @@
+package installation
+
+import (
+	"errors"
+	"testing"
+
+	stepMocks "github.com/kyma-project/cli/pkg/step/mocks"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStages(t *testing.T) {
+	t.Parallel()
+	// by default the first error is returned as it is
+	s := newStages(false, &stepMocks.Step{})
+	err := s.run(stageValidation, nil, func() error { return errors.New("invalid profile") })
+	require.EqualError(t, err, "invalid profile")
+
+	// all stages run and their errors are reported together
+	step := &stepMocks.Step{}
+	s = newStages(true, step)
+	require.NoError(t, s.run(stageLock, nil, func() error { return errors.New("cluster locked") }))
+	require.NoError(t, s.run(stageValidation, nil, func() error { return errors.New("invalid profile") }))
+	ran := false
+	require.NoError(t, s.run(stagePreparation, []string{stageValidation}, func() error {
+		ran = true
+		return nil
+	}))
+	require.False(t, ran, "stages depending on a failed stage must be skipped")
+	require.False(t, s.ok(stagePreparation))
+	require.True(t, s.ok(stageExport))
+	require.Len(t, step.Errors(), 3)
+
+	err = s.err()
+	require.Error(t, err)
+	var report StageErrors
+	require.True(t, errors.As(err, &report))
+	require.Equal(t, StageErrors{
+		{Stage: stageLock, Err: errors.New("cluster locked")},
+		{Stage: stageValidation, Err: errors.New("invalid profile")},
+		{Stage: stagePreparation, SkippedBecause: stageValidation},
+	}, report)
+	require.Contains(t, err.Error(), "preparing the installation files skipped, because validating the configuration failed")
+
+	require.NoError(t, newStages(true, &stepMocks.Step{}).err())
+}
+
+func TestStagesExplain(t *testing.T) {
+	t.Parallel()
+	describe := func(name string) []string { return []string{"do " + name} }
+	answers := func(a ...string) func(string) (string, error) {
+		return func(string) (string, error) {
+			next := a[0]
+			a = a[1:]
+			return next, nil
+		}
+	}
+
+	// non-interactive, the descriptions are logged and the stages run
+	step := &stepMocks.Step{}
+	s := newStages(false, step)
+	s.explain(describe, false)
+	ran := false
+	require.NoError(t, s.run(stageLock, nil, func() error {
+		ran = true
+		return nil
+	}))
+	require.True(t, ran)
+	require.Equal(t, []string{"Next: acquiring the cluster lock", "  - do acquiring the cluster lock"}, step.Infos())
+
+	// skipping a stage skips the stages depending on it, invalid answers are asked again
+	s = newStages(false, &stepMocks.Step{})
+	s.explain(describe, true)
+	s.prompt = answers("x", "s", "")
+	require.NoError(t, s.run(stagePreparation, nil, func() error { return errors.New("must not run") }))
+	require.NoError(t, s.run(stageTrigger, []string{stagePreparation}, func() error { return errors.New("must not run") }))
+	require.True(t, s.userSkipped(stageTrigger))
+	require.False(t, s.ok(stagePreparation))
+	require.NoError(t, s.run(stageExport, nil, func() error { return nil }))
+	require.True(t, s.ok(stageExport))
+	require.NoError(t, s.err(), "skipped stages are no errors")
+
+	// aborting stops the installation even if it continues on errors
+	s = newStages(true, &stepMocks.Step{})
+	s.explain(describe, true)
+	s.prompt = answers("a")
+	err := s.run(stageValidation, nil, func() error { return nil })
+	require.Equal(t, StageAborted{Stage: stageValidation}, err)
+	require.EqualError(t, err, "installation aborted before validating the configuration")
+}
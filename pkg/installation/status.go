@@ -0,0 +1,61 @@
+package installation
+
+import (
+	"context"
+
+	installationSDK "github.com/kyma-incubator/hydroform/install/installation"
+	"github.com/kyma-project/cli/internal/kube"
+	"github.com/kyma-project/cli/internal/version"
+	apiErrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Status describes the Kyma installation on a cluster.
+type Status struct {
+	// Name is the name of the Installation CR.
+	Name string `json:"name"`
+	// State is the state of the installation as reported by the Kyma Installer (e.g. InProgress, Installed, Error).
+	State string `json:"state"`
+	// Description is the latest description of the installation progress.
+	Description string `json:"description,omitempty"`
+	// KymaVersion is the version of the installed Kyma.
+	KymaVersion string `json:"kymaVersion,omitempty"`
+	// Errors holds the errors reported for the components.
+	Errors []ComponentError `json:"errors,omitempty"`
+}
+
+// GetStatus reads the status of the Kyma installation from the Installation CR with the given name.
+// If the name is empty, the Installation CR is discovered on the cluster.
+func GetStatus(k8s kube.KymaKube, name string) (*Status, error) {
+	var err error
+	m := clusterMetadata(k8s.Static())
+	if name == "" {
+		if name, err = findInstallationName(k8s, m); err != nil {
+			return nil, err
+		}
+		if name == "" {
+			return &Status{State: installationSDK.NoInstallationState}, nil
+		}
+	}
+
+	cr, err := k8s.Dynamic().Resource(m.InstallationGVR()).Namespace(m.InstallationNamespace).Get(context.Background(), name, metav1.GetOptions{})
+	if apiErrors.IsNotFound(err) {
+		return &Status{Name: name, State: installationSDK.NoInstallationState}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// the status is read without its typed schema, as it differs across Kyma releases
+	status, _ := cr.Object["status"].(map[string]interface{})
+	result := &Status{
+		Name:        name,
+		State:       firstString(status, "state"),
+		Description: firstString(status, "description"),
+		Errors:      parseComponentErrors(status),
+	}
+	if result.KymaVersion, err = version.KymaVersion(k8s.Static()); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
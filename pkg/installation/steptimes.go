@@ -0,0 +1,85 @@
+package installation
+
+import (
+	"sync"
+	"time"
+
+	"github.com/kyma-project/cli/pkg/step"
+)
+
+// StepDuration holds the time a step of the installation took.
+type StepDuration struct {
+	// Name is the message the step started with.
+	Name string
+	// Duration indicates how long the step ran until it was stopped.
+	Duration time.Duration
+	// Success indicates whether the step succeeded.
+	Success bool
+}
+
+// stepTimes collects the durations of the stopped steps, in the order they stopped
+type stepTimes struct {
+	mu        sync.Mutex
+	durations []StepDuration
+}
+
+func (t *stepTimes) record(d StepDuration) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.durations = append(t.durations, d)
+}
+
+func (t *stepTimes) list() []StepDuration {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return append([]StepDuration(nil), t.durations...)
+}
+
+// timedStep records the duration of a step the first time it is stopped
+type timedStep struct {
+	step.Step
+	name    string
+	started time.Time
+	times   *stepTimes
+	once    sync.Once
+}
+
+func withTiming(s step.Step, name string, times *stepTimes) step.Step {
+	return &timedStep{Step: s, name: name, started: time.Now(), times: times}
+}
+
+func (s *timedStep) stopped(success bool) {
+	s.once.Do(func() {
+		s.times.record(StepDuration{Name: s.name, Duration: time.Since(s.started), Success: success})
+	})
+}
+
+func (s *timedStep) Success() {
+	s.Step.Success()
+	s.stopped(true)
+}
+
+func (s *timedStep) Successf(format string, args ...interface{}) {
+	s.Step.Successf(format, args...)
+	s.stopped(true)
+}
+
+func (s *timedStep) Failure() {
+	s.Step.Failure()
+	s.stopped(false)
+}
+
+func (s *timedStep) Failuref(format string, args ...interface{}) {
+	s.Step.Failuref(format, args...)
+	s.stopped(false)
+}
+
+func (s *timedStep) Stop(success bool) {
+	s.Step.Stop(success)
+	s.stopped(success)
+}
+
+func (s *timedStep) Stopf(success bool, format string, args ...interface{}) {
+	s.Step.Stopf(success, format, args...)
+	s.stopped(success)
+}
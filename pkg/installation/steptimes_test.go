@@ -0,0 +1,31 @@
+package installation
+
+import (
+	"testing"
+
+	stepMocks "github.com/kyma-project/cli/pkg/step/mocks"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTimedStep(t *testing.T) {
+	t.Parallel()
+	times := &stepTimes{}
+
+	s := withTiming(&stepMocks.Step{}, "Preparing installation", times)
+	s.Successf("Preparations done")
+	// stopping a step twice keeps the first duration
+	s.Failure()
+
+	f := withTiming(&stepMocks.Step{}, "Installing Kyma", times)
+	f.Failuref("failed")
+
+	// running steps are not recorded
+	withTiming(&stepMocks.Step{}, "Pre-pulling component images", times)
+
+	durations := times.list()
+	require.Len(t, durations, 2)
+	require.Equal(t, "Preparing installation", durations[0].Name)
+	require.True(t, durations[0].Success)
+	require.Equal(t, "Installing Kyma", durations[1].Name)
+	require.False(t, durations[1].Success)
+}
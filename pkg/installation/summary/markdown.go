@@ -0,0 +1,112 @@
+package summary
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/kyma-project/cli/internal/nice"
+	"github.com/kyma-project/cli/internal/version"
+	"github.com/kyma-project/cli/pkg/installation"
+)
+
+// Markdown renders the summary as Markdown, for example for comments of pull request bots.
+// The result is nil if the installation failed. The admin password is never rendered, as such comments are usually public.
+func Markdown(result *installation.Result, steps []installation.StepDuration, installErr error, failed []installation.ComponentError) string {
+	var b strings.Builder
+	b.WriteString("## Kyma installation\n\n")
+	b.WriteString("| | |\n|---|---|\n")
+	if result == nil {
+		markdownRow(&b, "Status", ":x: Failed")
+		markdownRow(&b, "Error", installErr.Error())
+	} else {
+		switch result.ClusterVersion.State {
+		case version.NotInstalled:
+			markdownRow(&b, "Status", "Triggered")
+		case version.InProgress:
+			markdownRow(&b, "Status", "In progress")
+			markdownRow(&b, "Version", result.ClusterVersion.Version)
+		case version.Failed:
+			markdownRow(&b, "Status", ":x: Failed")
+			markdownRow(&b, "Version", result.ClusterVersion.Version)
+		default:
+			markdownRow(&b, "Status", ":white_check_mark: Installed")
+			markdownRow(&b, "Version", result.KymaVersion)
+			// the duration is only known if the installation was waited for
+			if result.Duration > 0 {
+				markdownRow(&b, "Duration", result.Duration.Round(time.Second).String())
+			}
+		}
+		markdownRow(&b, "Cluster", result.Host)
+		if strings.HasPrefix(result.Console, "https://") && result.ConsoleAssumed {
+			markdownRow(&b, "Console", result.Console+" (assumed)")
+		} else if strings.HasPrefix(result.Console, "https://") {
+			markdownLinkRow(&b, "Console", result.Console)
+		} else if result.Console != "" {
+			markdownRow(&b, "Console", result.Console)
+		}
+		if result.AdminEmail != "" {
+			markdownRow(&b, "Admin email", result.AdminEmail)
+		}
+		if len(result.DexUsers) > 0 {
+			markdownRow(&b, "Additional users", strings.Join(result.DexUsers, ", "))
+		}
+		if result.ExternalInstaller {
+			markdownRow(&b, "Kyma Installer", "Externally managed")
+		}
+	}
+
+	if len(steps) > 0 {
+		b.WriteString("\n### Steps\n\n| Step | Duration | Result |\n|---|---|---|\n")
+		for _, s := range steps {
+			status := ":white_check_mark:"
+			if !s.Success {
+				status = ":x:"
+			}
+			fmt.Fprintf(&b, "| %s | %s | %s |\n", markdownCell(s.Name), s.Duration.Round(time.Second), status)
+		}
+	}
+
+	if result != nil && len(result.ComponentDurations) > 0 {
+		b.WriteString("\n### Components\n\n| Component | Duration |\n|---|---|\n")
+		for _, c := range result.ComponentDurations {
+			fmt.Fprintf(&b, "| %s | %s |\n", markdownCell(c.Name), c.Duration.Round(time.Second))
+		}
+	}
+
+	if len(failed) > 0 {
+		b.WriteString("\n### Failed components\n\n| Component | Error | Occurrences |\n|---|---|---|\n")
+		for _, c := range failed {
+			fmt.Fprintf(&b, "| %s | %s | %d |\n", markdownCell(c.Component), markdownCell(c.Log), c.Occurrences)
+		}
+	}
+
+	if result != nil && len(result.UnreadyWorkloads) > 0 {
+		b.WriteString("\n### Workloads not ready\n\n")
+		for _, w := range result.UnreadyWorkloads {
+			fmt.Fprintf(&b, "- %s\n", w)
+		}
+	}
+
+	if result != nil && len(result.Warnings) > 0 {
+		b.WriteString("\n### Warnings\n\n")
+		for _, w := range result.Warnings {
+			fmt.Fprintf(&b, "- %s\n", strings.ReplaceAll(w, "\n", " "))
+		}
+	}
+	return b.String()
+}
+
+func markdownRow(b *strings.Builder, key, value string) {
+	fmt.Fprintf(b, "| %s | %s |\n", key, markdownCell(value))
+}
+
+func markdownLinkRow(b *strings.Builder, key, url string) {
+	fmt.Fprintf(b, "| %s | [%s](%s) |\n", key, markdownCell(strings.TrimPrefix(url, "https://")), url)
+}
+
+// markdownCell keeps a value in a single table cell, without the colors of the output of tools like kubectl
+func markdownCell(value string) string {
+	value = strings.ReplaceAll(nice.StripANSI(value), "|", "\\|")
+	return strings.Join(strings.Fields(value), " ")
+}
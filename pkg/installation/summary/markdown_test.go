@@ -0,0 +1,65 @@
+package summary
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/kyma-project/cli/internal/version"
+	"github.com/kyma-project/cli/pkg/installation"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMarkdown(t *testing.T) {
+	t.Parallel()
+	steps := []installation.StepDuration{
+		{Name: "Preparing installation", Duration: 65 * time.Second, Success: true},
+		{Name: "Installing Kyma", Duration: 20 * time.Minute, Success: true},
+	}
+	result := &installation.Result{
+		KymaVersion:    "1.17.0",
+		ClusterVersion: version.ClusterVersion{Version: "1.17.0", State: version.Installed},
+		Host:           "https://api.cluster.example.com",
+		Console:        "https://console.kyma.example.com",
+		AdminEmail:     "admin@kyma.cx",
+		AdminPassword:  "s3cr3t",
+		Duration:       21*time.Minute + 5*time.Second,
+		ComponentDurations: []installation.ComponentDuration{
+			{Name: "istio", Duration: 3 * time.Minute},
+		},
+	}
+
+	md := Markdown(result, steps, nil, nil)
+	require.Contains(t, md, "| Version | 1.17.0 |")
+	require.Contains(t, md, "| Duration | 21m5s |")
+	require.Contains(t, md, "| Console | [console.kyma.example.com](https://console.kyma.example.com) |")
+	require.Contains(t, md, "| Preparing installation | 1m5s | :white_check_mark: |")
+	require.Contains(t, md, "| istio | 3m0s |")
+	require.NotContains(t, md, "s3cr3t", "The admin password must never be part of the Markdown summary.")
+	require.NotContains(t, md, "Failed components")
+
+	result.ConsoleAssumed = true
+	md = Markdown(result, steps, nil, nil)
+	require.Contains(t, md, "| Console | https://console.kyma.example.com (assumed) |")
+	require.NotContains(t, md, "Workloads not ready")
+
+	result.UnreadyWorkloads = []string{"deployment kyma-system/console-backend: 0 of 1 replicas ready"}
+	md = Markdown(result, steps, nil, nil)
+	require.Contains(t, md, "### Workloads not ready\n\n- deployment kyma-system/console-backend: 0 of 1 replicas ready\n")
+	require.NotContains(t, md, "Kyma Installer")
+
+	result.ExternalInstaller = true
+	md = Markdown(result, steps, nil, nil)
+	require.Contains(t, md, "| Kyma Installer | Externally managed |")
+
+	// failed installation
+	failed := []installation.ComponentError{{Component: "monitoring", Log: "timed out waiting | for\nthe condition", Occurrences: 2}}
+	md = Markdown(nil, steps[:1], errors.New("installation failed"), failed)
+	require.Contains(t, md, "| Status | :x: Failed |")
+	require.Contains(t, md, "| Error | installation failed |")
+	require.Contains(t, md, "| monitoring | timed out waiting \\| for the condition | 2 |")
+
+	// colored output of kubectl does not end up in the summary
+	md = Markdown(nil, nil, errors.New("executing 'kubectl get installation' failed: \x1b[31mError from server\x1b[0m"), nil)
+	require.Contains(t, md, "| Error | executing 'kubectl get installation' failed: Error from server |")
+}
@@ -0,0 +1,143 @@
+// Package summary renders the result of a Kyma installation, either for the terminal or as Markdown.
+package summary
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/kyma-project/cli/internal/nice"
+	"github.com/kyma-project/cli/internal/version"
+	"github.com/kyma-project/cli/pkg/installation"
+)
+
+// Resolver looks up the host of the console address, net.DefaultResolver satisfies it
+type Resolver interface {
+	LookupHost(ctx context.Context, host string) ([]string, error)
+}
+
+// Options selects the details displayed by Print
+type Options struct {
+	// NonInteractive disables the colors and hides the admin password, e.g. on CI systems
+	NonInteractive bool
+	// PasswordKnown hides the admin password, as the user set it
+	PasswordKnown bool
+	// Resolver checks if the console address can be opened, net.DefaultResolver if not set
+	Resolver Resolver
+}
+
+// Print displays the version, the console address, the admin credentials and the warnings of the installation result,
+// e.g. also for a cluster installed earlier. The colors are only printed to os.Stdout.
+func Print(w io.Writer, result *installation.Result, o Options) error {
+	nicePrint := nice.Nice{
+		NonInteractive: o.NonInteractive,
+		Out:            w,
+	}
+	if o.Resolver == nil {
+		o.Resolver = net.DefaultResolver
+	}
+	// row prints a line like "Kyma is running at: <value>" with the highlighted value
+	row := func(label, value string) {
+		nicePrint.PrintKyma()
+		fmt.Fprint(w, label)
+		nicePrint.PrintImportant(value)
+	}
+
+	fmt.Fprintln(w)
+	switch result.ClusterVersion.State {
+	case version.NotInstalled:
+		row(" installation:\t\t", "triggered, the Kyma Installer is starting")
+	case version.InProgress:
+		row(" is being installed in version:\t", result.ClusterVersion.Version)
+		if !result.ClusterVersion.Since.IsZero() {
+			row(" installation in progress since:\t", result.ClusterVersion.Since.Format(time.RFC1123))
+		}
+	case version.Failed:
+		row(" installation failed in version:\t", result.ClusterVersion.Version)
+	default:
+		row(" is installed in version:\t", result.KymaVersion)
+
+		// the duration is only known if the installation was waited for
+		if result.Duration > 0 {
+			row(" installation took:\t\t", fmt.Sprintf("%d hours %d minutes",
+				int64(result.Duration.Hours()), int64(result.Duration.Minutes())))
+		}
+	}
+
+	if len(result.ComponentDurations) > 0 {
+		nicePrint.PrintKyma()
+		fmt.Fprintln(w, " component durations:")
+		for _, c := range result.ComponentDurations {
+			fmt.Fprintf(w, "\t%-30s", c.Name)
+			nicePrint.PrintImportant(c.Duration.Round(time.Second).String())
+		}
+	}
+
+	row(" is running at:\t\t", result.Host)
+
+	if result.Console != "" {
+		if result.ConsoleAssumed {
+			row(" console:\t\t\t", fmt.Sprintf("%s (assumed)", result.Console))
+		} else {
+			row(" console:\t\t\t", result.Console)
+		}
+		if strings.HasPrefix(result.Console, "https://") && !resolvable(o.Resolver, strings.TrimPrefix(result.Console, "https://")) {
+			fmt.Fprintln(w, "\tThe console address cannot be resolved, to open the console through port-forwards run: kyma console --port-forward")
+		}
+	}
+
+	// the admin credentials are only available once the installation created them
+	if result.AdminEmail != "" {
+		row(" admin email:\t\t", result.AdminEmail)
+	}
+
+	if !o.PasswordKnown && !o.NonInteractive && result.AdminPassword != "" {
+		row(" admin password:\t\t", result.AdminPassword)
+	}
+
+	for n, user := range result.DexUsers {
+		if n == 0 {
+			row(" additional users:\t\t", user)
+		} else {
+			row("\t\t\t\t", user)
+		}
+	}
+
+	if result.ExternalInstaller {
+		row(" installer:\t\t\t", "externally managed")
+	}
+
+	if result.ManifestsDir != "" {
+		row(" manifests exported to:\t", result.ManifestsDir)
+	}
+
+	if len(result.UnreadyWorkloads) > 0 {
+		fmt.Fprintln(w)
+		nicePrint.PrintImportant("Warning: these workloads are not ready, check their pods before using Kyma:")
+		for _, wl := range result.UnreadyWorkloads {
+			fmt.Fprintf(w, "\t%s\n", wl)
+		}
+		fmt.Fprintln(w)
+	}
+
+	for _, warning := range result.Warnings {
+		nicePrint.PrintImportant(warning)
+	}
+
+	fmt.Fprint(w, "\nHappy ")
+	nicePrint.PrintKyma()
+	fmt.Fprint(w, "-ing! :)\n\n")
+
+	return nil
+}
+
+// resolvable checks if the host of the console can be resolved, so that the printed address can be opened
+func resolvable(r Resolver, host string) bool {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	_, err := r.LookupHost(ctx, host)
+	return err == nil
+}
@@ -0,0 +1,109 @@
+package summary
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/kyma-project/cli/internal/version"
+	"github.com/kyma-project/cli/pkg/installation"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeResolver map[string]bool
+
+func (r fakeResolver) LookupHost(_ context.Context, host string) ([]string, error) {
+	if !r[host] {
+		return nil, errors.New("no such host")
+	}
+	return []string{"10.0.0.1"}, nil
+}
+
+func TestPrint(t *testing.T) {
+	t.Parallel()
+	result := &installation.Result{
+		KymaVersion:    "1.17.0",
+		ClusterVersion: version.ClusterVersion{Version: "1.17.0", State: version.Installed},
+		Host:           "https://api.cluster.example.com",
+		Console:        "https://console.kyma.example.com",
+		AdminEmail:     "admin@kyma.cx",
+		AdminPassword:  "s3cr3t",
+		Duration:       45 * time.Minute,
+		DexUsers:       []string{"dev@example.com", "ops@example.com"},
+	}
+	resolver := fakeResolver{"console.kyma.example.com": true}
+
+	var out bytes.Buffer
+	require.NoError(t, Print(&out, result, Options{Resolver: resolver}))
+	require.Equal(t, "\n"+
+		"Kyma is installed in version:\t1.17.0\n"+
+		"Kyma installation took:\t\t0 hours 45 minutes\n"+
+		"Kyma is running at:\t\thttps://api.cluster.example.com\n"+
+		"Kyma console:\t\t\thttps://console.kyma.example.com\n"+
+		"Kyma admin email:\t\tadmin@kyma.cx\n"+
+		"Kyma admin password:\t\ts3cr3t\n"+
+		"Kyma additional users:\t\tdev@example.com\n"+
+		"Kyma\t\t\t\tops@example.com\n"+
+		"\nHappy Kyma-ing! :)\n\n", out.String())
+
+	// the password is hidden if the user set it or on CI systems
+	out.Reset()
+	require.NoError(t, Print(&out, result, Options{PasswordKnown: true, Resolver: resolver}))
+	require.NotContains(t, out.String(), "s3cr3t")
+	out.Reset()
+	require.NoError(t, Print(&out, result, Options{NonInteractive: true, Resolver: resolver}))
+	require.NotContains(t, out.String(), "s3cr3t")
+
+	// an unresolvable console address points to the port-forwards
+	out.Reset()
+	require.NoError(t, Print(&out, result, Options{Resolver: fakeResolver{}}))
+	require.Contains(t, out.String(), "Kyma console:\t\t\thttps://console.kyma.example.com\n"+
+		"\tThe console address cannot be resolved, to open the console through port-forwards run: kyma console --port-forward\n")
+}
+
+func TestPrintStates(t *testing.T) {
+	t.Parallel()
+	since := time.Date(2020, 11, 3, 10, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name     string
+		version  version.ClusterVersion
+		expected string
+	}{
+		{
+			name:     "not installed",
+			version:  version.ClusterVersion{State: version.NotInstalled},
+			expected: "Kyma installation:\t\ttriggered, the Kyma Installer is starting\n",
+		},
+		{
+			name:    "in progress",
+			version: version.ClusterVersion{Version: "1.17.0", State: version.InProgress, Since: since},
+			expected: "Kyma is being installed in version:\t1.17.0\n" +
+				"Kyma installation in progress since:\tTue, 03 Nov 2020 10:00:00 UTC\n",
+		},
+		{
+			name:     "failed",
+			version:  version.ClusterVersion{Version: "1.17.0", State: version.Failed},
+			expected: "Kyma installation failed in version:\t1.17.0\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := &installation.Result{
+				ClusterVersion:   tt.version,
+				Host:             "https://api.cluster.example.com",
+				UnreadyWorkloads: []string{"deployment kyma-system/console-backend: 0 of 1 replicas ready"},
+				Warnings:         []string{"Warning: the certificate could not be imported."},
+			}
+			var out bytes.Buffer
+			require.NoError(t, Print(&out, result, Options{Resolver: fakeResolver{}}))
+			require.Equal(t, "\n"+tt.expected+
+				"Kyma is running at:\t\thttps://api.cluster.example.com\n"+
+				"\nWarning: these workloads are not ready, check their pods before using Kyma:\n"+
+				"\tdeployment kyma-system/console-backend: 0 of 1 replicas ready\n\n"+
+				"Warning: the certificate could not be imported.\n"+
+				"\nHappy Kyma-ing! :)\n\n", out.String())
+		})
+	}
+}
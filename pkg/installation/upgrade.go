@@ -1,26 +1,37 @@
 package installation
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"time"
 
 	"github.com/blang/semver/v4"
 	installationSDK "github.com/kyma-incubator/hydroform/install/installation"
+	"github.com/kyma-project/cli/internal/backup"
 	"github.com/kyma-project/cli/internal/net"
 	pkgErrors "github.com/pkg/errors"
-	corev1 "k8s.io/api/core/v1"
 )
 
 // UpgradeKyma triggers the upgrade of a Kyma cluster.
 func (i *Installation) UpgradeKyma() (*Result, error) {
 	// Start timer for the upgrade
 	upgradeTimer := time.Now()
+	defer i.cleanupLocalSources()
 
 	if i.Options.CI || i.Options.NonInteractive {
 		i.Factory.NonInteractive = true
 	}
 
 	s := i.newStep("Preparing Upgrade")
+	// Making sure no other CLI instance changes the cluster at the same time
+	releaseLock, err := i.acquireLock()
+	if err != nil {
+		s.Failure()
+		return nil, err
+	}
+	defer releaseLock()
+
 	// Checking existence of previous installation
 	prevInstallationState, currVersion, err := i.checkPrevInstallation()
 	if err != nil {
@@ -40,6 +51,12 @@ func (i *Installation) UpgradeKyma() (*Result, error) {
 			return nil, err
 		}
 
+		// Checking that the CLI supports the Kyma version running on the cluster
+		if err := i.checkCLICompatibility(currVersion); err != nil {
+			s.Failure()
+			return nil, err
+		}
+
 		// Getting target Kyma version in a suitable format to be shown to the user
 		targetVersion := i.getTargetVersion()
 
@@ -76,6 +93,16 @@ func (i *Installation) UpgradeKyma() (*Result, error) {
 		// Logging current Kyma version and upgrade target version
 		i.logVersionUpgrade(currVersion, targetVersion)
 
+		// Backing up the current installation state before anything is changed
+		if i.Options.BackupDir != "" {
+			dir, err := backup.Create(i.K8s, backup.Options{Dir: i.Options.BackupDir, InstallationName: i.installationName(), RedactSecrets: i.Options.BackupRedactSecrets})
+			if err != nil {
+				s.Failure()
+				return nil, pkgErrors.Wrap(err, "Failed to back up the installation. To upgrade without a backup, use the --skip-backup flag")
+			}
+			s.LogInfof("Backup of the installation stored in '%s'", dir)
+		}
+
 		// Loading upgrade files
 		files, err := i.prepareFiles()
 		if err != nil {
@@ -84,6 +111,10 @@ func (i *Installation) UpgradeKyma() (*Result, error) {
 		}
 
 		// Requesting Kyma Installer to upgrade Kyma
+		if i.installCtx().Err() != nil {
+			s.Failure()
+			return nil, errors.New("Interrupted, the upgrade was not triggered")
+		}
 		if err := i.triggerUpgrade(files); err != nil {
 			s.Failure()
 			return nil, err
@@ -96,7 +127,7 @@ func (i *Installation) UpgradeKyma() (*Result, error) {
 
 	if !i.Options.NoWait {
 		if prevInstallationState == "Installed" {
-			i.newStep("Waiting for upgrade to start")
+			i.newStep("Upgrading Kyma")
 		} else {
 			i.newStep("Re-attaching installation status")
 		}
@@ -217,7 +248,17 @@ func (i *Installation) logVersionUpgrade(currVersion string, targetVersion strin
 }
 
 func (i *Installation) triggerUpgrade(files map[string]*File) error {
-	var err error
+	sortDocuments(files[installerFile])
+	if err := i.applyImagePullSecrets(); err != nil {
+		return err
+	}
+	deferred, err := deferCustomResources(files[installerFile])
+	if err != nil {
+		return err
+	}
+	if err := i.reconcileCRDs(files[installerFile]); err != nil {
+		return err
+	}
 	files, err = loadStringContent(files)
 	if err != nil {
 		return fmt.Errorf("Failed to load installation files: %s", err.Error())
@@ -230,10 +271,19 @@ func (i *Installation) triggerUpgrade(files map[string]*File) error {
 		return pkgErrors.Wrap(err, "unable to load the configurations")
 	}
 
-	err = i.Service.TriggerUpgrade(installerFileContent, installerCRFileContent, configuration)
+	if err := i.ensureInstallationCRD(files[installerFile]); err != nil {
+		return err
+	}
+
+	err = i.applyWithTimeout(func(ctx context.Context) error {
+		return i.Service.TriggerUpgrade(ctx, installerFileContent, installerCRFileContent, configuration)
+	})
 	if err != nil {
-		return fmt.Errorf("Failed to start upgrade: %s", err.Error())
+		return fmt.Errorf("Failed to start upgrade: apply of the Kyma Installer failed: %s", err.Error())
+	}
+	if err := i.applyCustomResources(deferred); err != nil {
+		return fmt.Errorf("Failed to start upgrade: apply of the custom resources of the Kyma Installer failed: %s", err.Error())
 	}
 
-	return i.K8s.WaitPodStatusByLabel("kyma-installer", "name", "kyma-installer", corev1.PodRunning)
+	return i.waitForInstallerPod()
 }
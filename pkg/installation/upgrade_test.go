@@ -7,6 +7,7 @@ import (
 
 	installSDK "github.com/kyma-incubator/hydroform/install/installation"
 	k8sMocks "github.com/kyma-project/cli/internal/kube/mocks"
+	"github.com/kyma-project/cli/internal/version"
 	"github.com/kyma-project/cli/pkg/installation/mocks"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
@@ -66,7 +67,9 @@ func TestUpgradeKyma(t *testing.T) {
 
 	kymaMock.On("Static").Return(k8sMock)
 	kymaMock.On("Istio").Return(istioMock)
+	kymaMock.On("Dynamic").Return(fakeDynamicWithInstallationCRD())
 	kymaMock.On("RestConfig", mock.Anything).Return(&rest.Config{Host: "fake-kubeconfig-host"})
+	kymaMock.On("IsPodReadyByLabel", "kyma-installer", "name", "kyma-installer").Return(false, nil)
 	kymaMock.On("WaitPodStatusByLabel", "kyma-installer", "name", "kyma-installer", v1.PodRunning).Return(nil)
 
 	i := &Installation{
@@ -88,8 +91,8 @@ func TestUpgradeKyma(t *testing.T) {
 	}
 
 	// Happy path
-	iServiceMock.On("CheckInstallationState", mock.Anything).Return(installSDK.InstallationState{State: "Installed"}, nil).Times(3)
-	iServiceMock.On("TriggerUpgrade", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	iServiceMock.On("CheckInstallationState", mock.Anything, mock.Anything).Return(installSDK.InstallationState{State: "Installed"}, nil).Times(3)
+	iServiceMock.On("TriggerUpgrade", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
 
 	r, err := i.UpgradeKyma()
 	require.NoError(t, err)
@@ -97,28 +100,28 @@ func TestUpgradeKyma(t *testing.T) {
 
 	// Installation in progress
 	i.Options.NoWait = true // no need to wait for upgrade in all test cases from here on
-	iServiceMock.On("CheckInstallationState", mock.Anything).Return(installSDK.InstallationState{State: "InProgress"}, nil).Once()
+	iServiceMock.On("CheckInstallationState", mock.Anything, mock.Anything).Return(installSDK.InstallationState{State: "InProgress"}, nil).Times(2)
 
 	r, err = i.UpgradeKyma()
 	require.NoError(t, err)
-	require.Empty(t, r)
+	require.Equal(t, version.InProgress, r.ClusterVersion.State, "The summary shows the upgrade in progress")
 
 	// No Kyma on cluster
-	iServiceMock.On("CheckInstallationState", mock.Anything).Return(installSDK.InstallationState{State: installSDK.NoInstallationState}, nil).Once()
+	iServiceMock.On("CheckInstallationState", mock.Anything, mock.Anything).Return(installSDK.InstallationState{State: installSDK.NoInstallationState}, nil).Once()
 
 	r, err = i.UpgradeKyma()
 	require.Error(t, err)
 	require.Empty(t, r)
 
 	// Error getting installation status
-	iServiceMock.On("CheckInstallationState", mock.Anything).Return(installSDK.InstallationState{}, errors.New("installation is hiding from us")).Once()
+	iServiceMock.On("CheckInstallationState", mock.Anything, mock.Anything).Return(installSDK.InstallationState{}, errors.New("installation is hiding from us")).Once()
 
 	r, err = i.UpgradeKyma()
 	require.Error(t, err)
 	require.Empty(t, r)
 
 	// Empty installation status
-	iServiceMock.On("CheckInstallationState", mock.Anything).Return(installSDK.InstallationState{}, nil).Once()
+	iServiceMock.On("CheckInstallationState", mock.Anything, mock.Anything).Return(installSDK.InstallationState{}, nil).Once()
 
 	r, err = i.UpgradeKyma()
 	require.Error(t, err)
@@ -1,9 +1,9 @@
 package installation
 
 import (
+	"bufio"
 	"bytes"
 	"context"
-	"encoding/base64"
 	"encoding/hex"
 	"fmt"
 	"io"
@@ -11,19 +11,21 @@ import (
 	"net/http"
 	"os"
 	"path/filepath"
+	"reflect"
 	"strings"
 	"time"
 
 	"github.com/blang/semver/v4"
-	"github.com/kyma-incubator/hydroform/install/config"
 	installationSDK "github.com/kyma-incubator/hydroform/install/installation"
-	"github.com/kyma-incubator/hydroform/install/scheme"
+	"github.com/kyma-project/cli/internal/github"
+	"github.com/kyma-project/cli/internal/release"
 	"github.com/kyma-project/cli/pkg/step"
 	"github.com/kyma-project/kyma/components/kyma-operator/pkg/apis/installer/v1alpha1"
 	"github.com/pkg/errors"
 	"gopkg.in/src-d/go-git.v4"
 	"gopkg.in/src-d/go-git.v4/storage/memory"
 	"gopkg.in/yaml.v2"
+	utilYaml "k8s.io/apimachinery/pkg/util/yaml"
 )
 
 func getLatestAvailableMasterHash(currentStep step.Step, fallbackLevel int, nonInteractive bool) (string, error) {
@@ -93,22 +95,19 @@ func getLatestAvailableMasterHash(currentStep step.Step, fallbackLevel int, nonI
 }
 
 func checkArtifactsAvailability(abbrevHash string) (bool, error) {
-	resp, err := http.Head(fmt.Sprintf(releaseResourcePattern, developmentBucket, "master-"+abbrevHash, "kyma-installer-cluster.yaml"))
+	r, err := release.FromVersion("master-" + abbrevHash)
 	if err != nil {
-		return false, errors.Wrap(err, "while fetching example file from kyma-development-artifacts")
-	}
-	if err = resp.Body.Close(); err != nil {
-		return false, errors.Wrap(err, "while closing body")
+		return false, err
 	}
-	if resp.StatusCode == http.StatusOK {
-		return true, nil
-	} else if resp.StatusCode != http.StatusNotFound {
-		return false, fmt.Errorf("got unexpected status code when fetching example file from kyma-development artifacts, got: [%d] ", resp.StatusCode)
+	available, err := r.InstallerManifest().Available(github.DefaultClient)
+	if err != nil {
+		return false, errors.Wrap(err, "while fetching example file from kyma-development-artifacts")
 	}
-	return false, nil
+	return available, nil
 }
 
-func (i *Installation) loadInstallationFiles() (map[string]*File, error) {
+// installationFileSet returns the installation files of the source, with the paths relative to the release artifacts or the installation resources of the local sources
+func (i *Installation) installationFileSet() map[string]*File {
 	var installationFiles map[string]*File
 	if i.Options.fromLocalSources {
 		if i.Options.IsLocal {
@@ -126,127 +125,207 @@ func (i *Installation) loadInstallationFiles() (map[string]*File, error) {
 				}
 		}
 	} else {
+		profile := release.RemoteCluster
 		if i.Options.IsLocal {
-			installationFiles =
-				map[string]*File{
-					installerFile:       {Path: "kyma-installer-cluster.yaml"},
-					installerCRFile:     {Path: "kyma-installer-cr-local.yaml"},
-					installerConfigFile: {Path: "kyma-config-local.yaml"},
-				}
-		} else {
-			installationFiles =
-				map[string]*File{
-					installerFile:   {Path: "kyma-installer-cluster.yaml"},
-					installerCRFile: {Path: "kyma-installer-cr-cluster.yaml"},
-				}
+			profile = release.LocalCluster
+		}
+		installationFiles =
+			map[string]*File{
+				installerFile:   {Path: release.InstallerManifestAsset},
+				installerCRFile: {Path: i.Options.release.InstallerCR(profile).Name},
+			}
+		if config, ok := i.Options.release.InstallerConfig(profile); ok {
+			installationFiles[installerConfigFile] = &File{Path: config.Name}
 		}
 	}
+	return installationFiles
+}
 
-	for _, file := range installationFiles {
-		resources := make([]map[string]interface{}, 0)
+func (i *Installation) loadInstallationFiles() (map[string]*File, error) {
+	installationFiles := i.installationFileSet()
+	for name, file := range installationFiles {
 		var reader io.ReadCloser
 		var err error
-		if i.Options.fromLocalSources {
+		fromRelease := !i.Options.fromLocalSources
+		if name == installerFile && i.Options.InstallerManifest != "" {
+			fromRelease = false
+			if file.Path, reader, err = openSource(i.Options.InstallerManifest); err != nil {
+				return nil, errors.Wrapf(err, "unable to read the installer manifest '%s'", i.Options.InstallerManifest)
+			}
+		} else if i.Options.fromLocalSources {
 			path := filepath.Join(i.Options.LocalSrcPath, "installation",
 				"resources", file.Path)
 			reader, err = os.Open(path)
 		} else {
-			reader, err = downloadFile(i.releaseFile(file.Path))
+			reader, err = i.downloadReleaseFile(i.Options.release.Artifact(file.Path))
 		}
 
 		if err != nil {
 			return nil, err
 		}
 
-		dec := yaml.NewDecoder(reader)
-		for {
-			m := make(map[string]interface{})
-			err := dec.Decode(m)
-			if err == io.EOF {
-				break
-			} else if err != nil {
-				return nil, err
-			}
-			resources = append(resources, m)
+		var src io.Reader = reader
+		if fromRelease {
+			// keep the release artifact as downloaded, it is exported together with the applied manifests
+			downloaded := &bytes.Buffer{}
+			src = io.TeeReader(reader, downloaded)
+			file.Downloaded = downloaded
 		}
 
+		docs, raw, duplicates, err := decodeDocuments(src)
 		reader.Close()
-		file.Content = resources
+		if err != nil {
+			return nil, errors.Wrapf(err, "unable to decode '%s'", file.Path)
+		}
+		for _, n := range duplicates {
+			if i.currentStep != nil {
+				i.currentStep.LogErrorf("Document %d of '%s' contains duplicate keys, the last value of each key is used", n+1, file.Path)
+			}
+		}
+		file.Content = docs
+		file.raw = raw
 	}
 
+	if err := i.applyConfigInstallationCR(installationFiles); err != nil {
+		return nil, err
+	}
+	if i.Options.InstallerManifest != "" {
+		if err := validateInstallerManifest(installationFiles[installerFile]); err != nil {
+			return nil, err
+		}
+		annotateInstallerManifest(installationFiles[installerFile], installationFiles[installerFile].Path)
+	}
+	if err := validateInstallationFiles(installationFiles); err != nil {
+		return nil, err
+	}
 	return installationFiles, nil
 }
 
-func loadStringContent(installationFiles map[string]*File) (map[string]*File, error) {
-	for _, file := range installationFiles {
-		if file.Content != nil {
-			buf := &bytes.Buffer{}
-			enc := yaml.NewEncoder(buf)
-			for _, y := range file.Content {
-				err := enc.Encode(y)
-				if err != nil {
-					return installationFiles, err
-				}
-			}
-
-			err := enc.Close()
-			if err != nil {
-				return installationFiles, err
+// validateInstallationFiles ensures that the installer file holds the Kyma Installer deployment and the Installation CR file an Installation CR.
+// A wrong file would otherwise be applied without errors and only end in a timeout while waiting for the installation.
+func validateInstallationFiles(installationFiles map[string]*File) error {
+	if file, ok := installationFiles[installerFile]; ok {
+		if _, err := getInstallerImage(file); err != nil {
+			return fmt.Errorf("'%s' does not contain the deployment of the Kyma Installer with the container 'kyma-installer-container'. Found: %s", file.Path, documentKinds(file))
+		}
+	}
+	if file, ok := installationFiles[installerCRFile]; ok {
+		for _, doc := range file.Content {
+			if kind, ok := doc["kind"]; ok && kind == "Installation" {
+				return nil
 			}
-
-			file.StringContent = buf.String()
 		}
+		return fmt.Errorf("'%s' does not contain an Installation CR. Found: %s", file.Path, documentKinds(file))
 	}
-
-	return installationFiles, nil
+	return nil
 }
 
-func (i *Installation) loadConfigurations(files map[string]*File) (installationSDK.Configuration, error) {
-	var configuration installationSDK.Configuration
-	var configFileContent string
-	for _, file := range i.Options.OverrideConfigs {
-		oFile, err := os.Open(file)
-		if err != nil {
-			return configuration, fmt.Errorf("error: unable to open file: %s", err.Error())
+// documentKinds lists the kind and name of each document of the file
+func documentKinds(file *File) string {
+	var kinds []string
+	for _, doc := range file.Content {
+		kind, _ := doc["kind"].(string)
+		if kind == "" {
+			kind = "<no kind>"
 		}
-
-		var rawData bytes.Buffer
-		if _, err = io.Copy(&rawData, oFile); err != nil {
-			fmt.Printf("unable to read data from file: %s.\n", file)
+		if name, _ := docMetadata(doc)["name"].(string); name != "" {
+			kind = fmt.Sprintf("%s '%s'", kind, name)
 		}
-
-		configFileContent = rawData.String() + "\n---\n" + configFileContent
+		kinds = append(kinds, kind)
 	}
-
-	if i.Options.IsLocal {
-		//Merge with local config file
-		configFileContent = files[installerConfigFile].StringContent + "\n---\n" + configFileContent
+	if len(kinds) == 0 {
+		return "no resources"
 	}
+	return strings.Join(kinds, ", ")
+}
 
-	if configFileContent != "" {
-		decoder, err := scheme.DefaultDecoder()
-		if err != nil {
-			return configuration, fmt.Errorf("error: failed to create default decoder: %s", err.Error())
+// decodeDocuments splits a multi-document YAML stream and decodes each document, keeping the source of each of them.
+// Documents with duplicate keys are decoded with the last value of each key, like the Kubernetes decoder does, and their indexes are returned.
+// Empty documents are skipped.
+func decodeDocuments(r io.Reader) ([]map[string]interface{}, []string, []int, error) {
+	var docs []map[string]interface{}
+	var raw []string
+	var duplicates []int
+	reader := utilYaml.NewYAMLReader(bufio.NewReader(r))
+	for {
+		data, err := reader.Read()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, nil, nil, err
 		}
+		source := trimDocumentSeparator(string(data))
 
-		configuration, err = config.YAMLToConfiguration(decoder, configFileContent)
-		if err != nil {
-			return configuration, fmt.Errorf("error: failed to parse configurations: %s", err.Error())
+		m := make(map[string]interface{})
+		if err := yaml.Unmarshal([]byte(source), &m); err != nil {
+			return nil, nil, nil, err
+		}
+		if len(m) == 0 {
+			continue
 		}
+		if err := yaml.UnmarshalStrict([]byte(source), &map[string]interface{}{}); err != nil {
+			duplicates = append(duplicates, len(docs))
+		}
+		docs = append(docs, m)
+		raw = append(raw, source)
 	}
+	return docs, raw, duplicates, nil
+}
 
-	if i.Options.IsLocal {
-		configuration.Configuration.Set("global.minikubeIP", i.Options.LocalCluster.IP, false)
+// trimDocumentSeparator removes the separator line the YAML reader keeps at the start of the first document
+func trimDocumentSeparator(doc string) string {
+	if n := strings.Index(doc, "\n"); n >= 0 && strings.TrimSpace(doc[:n]) == "---" {
+		return doc[n+1:]
 	}
-	if i.Options.Password != "" {
-		configuration.Configuration.Set("global.adminPassword", base64.StdEncoding.EncodeToString([]byte(i.Options.Password)), false)
+	return doc
+}
+
+// loadStringContent encodes the documents of the files to the content sent to the cluster.
+// Documents which were not modified are taken from the source as they are, so anchors, comments and formatting are kept.
+func loadStringContent(installationFiles map[string]*File) (map[string]*File, error) {
+	for _, file := range installationFiles {
+		if file.Content != nil {
+			buf := &bytes.Buffer{}
+			for n, doc := range file.Content {
+				if n > 0 {
+					buf.WriteString("---\n")
+				}
+				if n < len(file.raw) && unmodified(doc, file.raw[n]) {
+					buf.WriteString(file.raw[n])
+					if !strings.HasSuffix(file.raw[n], "\n") {
+						buf.WriteString("\n")
+					}
+					continue
+				}
+				data, err := yaml.Marshal(doc)
+				if err != nil {
+					return installationFiles, err
+				}
+				buf.Write(data)
+			}
+
+			file.StringContent = buf.String()
+		}
 	}
-	if i.Options.Domain != "" && i.Options.Domain != defaultDomain {
-		configuration.Configuration.Set("global.domainName", i.Options.Domain, false)
-		configuration.Configuration.Set("global.tlsCrt", i.Options.TLSCert, false)
-		configuration.Configuration.Set("global.tlsKey", i.Options.TLSKey, false)
+
+	return installationFiles, nil
+}
+
+// unmodified checks if the document still equals its source
+func unmodified(doc map[string]interface{}, source string) bool {
+	original := make(map[string]interface{})
+	if err := yaml.Unmarshal([]byte(source), &original); err != nil {
+		return false
 	}
+	return reflect.DeepEqual(doc, original)
+}
 
+func (i *Installation) loadConfigurations(files map[string]*File) (installationSDK.Configuration, error) {
+	sources, err := i.loadConfigurationSources(files)
+	if err != nil {
+		return installationSDK.Configuration{}, err
+	}
+	configuration, _ := mergeConfigurations(sources)
 	return configuration, nil
 }
 
@@ -287,11 +366,33 @@ func insertProfile(installationCRFile *File, profile string) error {
 	return errors.New("unable to set 'profile' field for Kyma Installation CR")
 }
 
-func downloadFile(path string) (io.ReadCloser, error) {
-	client := &http.Client{
-		Timeout: 5 * time.Second,
+func getInstallationName(installationCRFile *File) string {
+	for _, config := range installationCRFile.Content {
+		if kind, ok := config["kind"]; ok && kind == "Installation" {
+			if metadata, ok := config["metadata"].(map[interface{}]interface{}); ok {
+				if name, ok := metadata["name"].(string); ok {
+					return name
+				}
+			}
+		}
 	}
-	resp, err := client.Get(path)
+	return ""
+}
+
+func replaceInstallationName(installationCRFile *File, name string) error {
+	for _, config := range installationCRFile.Content {
+		if kind, ok := config["kind"]; ok && kind == "Installation" {
+			if metadata, ok := config["metadata"].(map[interface{}]interface{}); ok {
+				metadata["name"] = name
+				return nil
+			}
+		}
+	}
+	return errors.New("unable to set 'metadata.name' field for Kyma Installation CR")
+}
+
+func downloadFile(path string) (io.ReadCloser, error) {
+	resp, err := github.NewClient(5 * time.Second).Get(path)
 	if err != nil {
 		return nil, err
 	}
@@ -354,6 +455,37 @@ func replaceInstallerImage(installerFile *File, imageURL string) error {
 	return errors.New("unable to find 'image' field for Kyma Installer 'Deployment'")
 }
 
+func insertImagePullSecret(installerFile *File, secretName string) error {
+	// Check if installer deployment has all the necessary fields and a container named kyma-installer-container.
+	// If so, reference the image pull secret in the pod spec of the deployment.
+	for _, config := range installerFile.Content {
+		if kind, ok := config["kind"]; ok && kind == "Deployment" {
+			if spec, ok := config["spec"].(map[interface{}]interface{}); ok {
+				if template, ok := spec["template"].(map[interface{}]interface{}); ok {
+					if spec, ok = template["spec"].(map[interface{}]interface{}); ok {
+						if containers, ok := spec["containers"].([]interface{}); ok {
+							for _, c := range containers {
+								container := c.(map[interface{}]interface{})
+								if cName, ok := container["name"]; ok && cName == "kyma-installer-container" {
+									pullSecrets, _ := spec["imagePullSecrets"].([]interface{})
+									for _, ps := range pullSecrets {
+										if pullSecret, ok := ps.(map[interface{}]interface{}); ok && pullSecret["name"] == secretName {
+											return nil
+										}
+									}
+									spec["imagePullSecrets"] = append(pullSecrets, map[interface{}]interface{}{"name": secretName})
+									return nil
+								}
+							}
+						}
+					}
+				}
+			}
+		}
+	}
+	return errors.New("unable to set 'imagePullSecrets' field for Kyma Installer 'Deployment'")
+}
+
 func isDockerImage(s string) bool {
 	return len(strings.Split(s, "/")) > 1
 }
@@ -1,8 +1,12 @@
 package installation
 
 import (
+	"bytes"
 	"encoding/base64"
+	"fmt"
+	"io/ioutil"
 	"path"
+	"strings"
 	"testing"
 
 	stepMocks "github.com/kyma-project/cli/pkg/step/mocks"
@@ -22,11 +26,10 @@ func Test_LoadInstallationFiles(t *testing.T) {
 		Options: &Options{
 			IsLocal:          true,
 			fromLocalSources: false,
-			configVersion:    "master-6dba1d2c",
-			bucket:           developmentBucket,
 		},
 	}
 
+	require.NoError(t, localInstallation.resolveReleaseArtifacts("master-6dba1d2c"))
 	m, err := localInstallation.loadInstallationFiles()
 	require.NoError(t, err)
 	require.Equal(t, 3, len(m))
@@ -35,11 +38,10 @@ func Test_LoadInstallationFiles(t *testing.T) {
 		Options: &Options{
 			IsLocal:          false,
 			fromLocalSources: false,
-			configVersion:    "master-6dba1d2c",
-			bucket:           developmentBucket,
 		},
 	}
 
+	require.NoError(t, clusterInstallation.resolveReleaseArtifacts("master-6dba1d2c"))
 	m, err = clusterInstallation.loadInstallationFiles()
 	require.NoError(t, err)
 	require.Equal(t, 2, len(m))
@@ -49,6 +51,31 @@ func Test_LoadInstallationFiles(t *testing.T) {
 	require.Equal(t, 2, len(f))
 }
 
+func TestValidateInstallationFiles(t *testing.T) {
+	t.Parallel()
+	installer := &File{Path: "kyma-installer-cluster.yaml", Content: []map[string]interface{}{
+		{"kind": "Namespace", "metadata": map[interface{}]interface{}{"name": "kyma-installer"}},
+		{"kind": "Deployment", "spec": map[interface{}]interface{}{"template": map[interface{}]interface{}{"spec": map[interface{}]interface{}{
+			"containers": []interface{}{map[interface{}]interface{}{"name": "kyma-installer-container", "image": "eu.gcr.io/kyma-project/kyma-installer:1.15.1"}},
+		}}}},
+	}}
+	cr := &File{Path: "kyma-installer-cr-cluster.yaml", Content: []map[string]interface{}{
+		{"kind": "Installation", "metadata": map[interface{}]interface{}{"name": "kyma-installation"}},
+	}}
+	values := &File{Path: "values.yaml", Content: []map[string]interface{}{
+		{"global": map[interface{}]interface{}{"domainName": "kyma.local"}},
+		{"kind": "ConfigMap", "metadata": map[interface{}]interface{}{"name": "overrides"}},
+	}}
+
+	require.NoError(t, validateInstallationFiles(map[string]*File{installerFile: installer, installerCRFile: cr}))
+
+	err := validateInstallationFiles(map[string]*File{installerFile: values, installerCRFile: cr})
+	require.EqualError(t, err, "'values.yaml' does not contain the deployment of the Kyma Installer with the container 'kyma-installer-container'. Found: <no kind>, ConfigMap 'overrides'")
+
+	err = validateInstallationFiles(map[string]*File{installerFile: installer, installerCRFile: {Path: "empty.yaml"}})
+	require.EqualError(t, err, "'empty.yaml' does not contain an Installation CR. Found: no resources")
+}
+
 func Test_LoadConfigurations(t *testing.T) {
 	t.Parallel()
 	domain := "test.kyma"
@@ -265,6 +292,136 @@ func Test_InsertProfile(t *testing.T) {
 	}
 }
 
+func Test_ReplaceInstallationName(t *testing.T) {
+	t.Parallel()
+	const replacedWithData = "custom-installation"
+	testData := []struct {
+		testName       string
+		data           File
+		expectedResult File
+		shouldFail     bool
+	}{
+		{
+			testName: "correct data test",
+			data: File{Content: []map[string]interface{}{{
+				"apiVersion": "installer.kyma-project.io/v1alpha1",
+				"kind":       "Installation",
+				"metadata": map[interface{}]interface{}{
+					"name": "kyma-installation",
+				},
+			},
+			}},
+			expectedResult: File{Content: []map[string]interface{}{
+				{
+					"apiVersion": "installer.kyma-project.io/v1alpha1",
+					"kind":       "Installation",
+					"metadata": map[interface{}]interface{}{
+						"name": replacedWithData,
+					},
+				},
+			}},
+			shouldFail: false,
+		},
+		{
+			testName: "incorrect data test",
+			data: File{Content: []map[string]interface{}{{
+				"apiVersion": "apps/v1",
+				"kind":       "Deployment",
+			},
+			}},
+			shouldFail: true,
+		},
+	}
+
+	for _, tt := range testData {
+		err := replaceInstallationName(&tt.data, replacedWithData)
+		if !tt.shouldFail {
+			require.NoError(t, err, tt.testName)
+			require.Equal(t, tt.expectedResult, tt.data, tt.testName)
+			require.Equal(t, replacedWithData, getInstallationName(&tt.data), tt.testName)
+		} else {
+			require.Error(t, err, tt.testName)
+			require.Empty(t, getInstallationName(&tt.data), tt.testName)
+		}
+	}
+}
+
+func Test_InsertImagePullSecret(t *testing.T) {
+	t.Parallel()
+	const secretName = "test-pull-secret"
+	deployment := func(pullSecrets ...string) File {
+		podSpec := map[interface{}]interface{}{
+			"serviceAccountName": "kyma-installer",
+			"containers": []interface{}{
+				map[interface{}]interface{}{
+					"name":  "kyma-installer-container",
+					"image": "eu.gcr.io/kyma-project/kyma-installer:63f27f76",
+				},
+			},
+		}
+		if len(pullSecrets) > 0 {
+			var refs []interface{}
+			for _, ps := range pullSecrets {
+				refs = append(refs, map[interface{}]interface{}{"name": ps})
+			}
+			podSpec["imagePullSecrets"] = refs
+		}
+		return File{Content: []map[string]interface{}{{
+			"apiVersion": "apps/v1",
+			"kind":       "Deployment",
+			"spec": map[interface{}]interface{}{
+				"template": map[interface{}]interface{}{
+					"spec": podSpec,
+				},
+			},
+		}}}
+	}
+	testData := []struct {
+		testName       string
+		data           File
+		expectedResult File
+		shouldFail     bool
+	}{
+		{
+			testName:       "correct data test",
+			data:           deployment(),
+			expectedResult: deployment(secretName),
+			shouldFail:     false,
+		},
+		{
+			testName:       "existing pull secrets are kept",
+			data:           deployment("other-secret"),
+			expectedResult: deployment("other-secret", secretName),
+			shouldFail:     false,
+		},
+		{
+			testName:       "pull secret is not added twice",
+			data:           deployment(secretName),
+			expectedResult: deployment(secretName),
+			shouldFail:     false,
+		},
+		{
+			testName: "incorrect data test",
+			data: File{Content: []map[string]interface{}{{
+				"apiVersion": "v1",
+				"kind":       "Namespace",
+			},
+			}},
+			shouldFail: true,
+		},
+	}
+
+	for _, tt := range testData {
+		err := insertImagePullSecret(&tt.data, secretName)
+		if !tt.shouldFail {
+			require.NoError(t, err, tt.testName)
+			require.Equal(t, tt.expectedResult, tt.data, tt.testName)
+		} else {
+			require.Error(t, err, tt.testName)
+		}
+	}
+}
+
 func Test_IsDockerImage(t *testing.T) {
 	t.Parallel()
 	ok := isDockerImage("testRegistry/testImage:tag")
@@ -285,3 +442,54 @@ func Test_IsSemVer(t *testing.T) {
 	ok = isSemVer("12345")
 	require.False(t, ok)
 }
+
+func Test_LoadStringContentKeepsSource(t *testing.T) {
+	t.Parallel()
+	source, err := ioutil.ReadFile(path.Join("../../internal/testdata", "installer-anchors.yaml"))
+	require.NoError(t, err)
+	docs, raw, duplicates, err := decodeDocuments(bytes.NewReader(source))
+	require.NoError(t, err)
+	require.Len(t, docs, 3)
+	require.Equal(t, []int{2}, duplicates)
+
+	// aliases are resolved and the last value of duplicate keys is used
+	template := docs[1]["spec"].(map[interface{}]interface{})["template"].(map[interface{}]interface{})
+	require.Equal(t, map[interface{}]interface{}{"name": "kyma-installer"}, template["metadata"].(map[interface{}]interface{})["labels"])
+	require.Equal(t, "debug", docs[2]["data"].(map[interface{}]interface{})["logLevel"])
+
+	// unmodified documents are passed through as they are
+	files, err := loadStringContent(map[string]*File{installerFile: {Content: docs, raw: raw}})
+	require.NoError(t, err)
+	require.Equal(t, string(source), files[installerFile].StringContent)
+
+	// only the modified document is encoded again
+	require.NoError(t, replaceInstallerImage(files[installerFile], "fake-registry/installer:1.15.1"))
+	files, err = loadStringContent(files)
+	require.NoError(t, err)
+	content := strings.Split(files[installerFile].StringContent, "---\n")
+	require.Len(t, content, 3)
+	require.Equal(t, raw[0], content[0])
+	require.NotContains(t, content[1], "*selector")
+	require.Contains(t, content[1], "image: fake-registry/installer:1.15.1")
+	require.Equal(t, raw[2], content[2])
+}
+
+func Test_LoadStringContentLargeDocuments(t *testing.T) {
+	t.Parallel()
+	// several megabytes of documents, including a single line longer than the default buffer sizes of line readers
+	source := &bytes.Buffer{}
+	value := strings.Repeat("x", 1500)
+	for n := 0; n < 2000; n++ {
+		fmt.Fprintf(source, "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: config-%d\n  namespace: kyma-installer\ndata:\n  value: %s\n---\n", n, value)
+	}
+	fmt.Fprintf(source, "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: large-value\n  namespace: kyma-installer\ndata:\n  value: %s\n", strings.Repeat("y", 2<<20))
+	require.True(t, source.Len() > 5<<20)
+
+	docs, raw, _, err := decodeDocuments(bytes.NewReader(source.Bytes()))
+	require.NoError(t, err)
+	require.Len(t, docs, 2001)
+
+	files, err := loadStringContent(map[string]*File{installerFile: {Content: docs, raw: raw}})
+	require.NoError(t, err)
+	require.Equal(t, source.String(), files[installerFile].StringContent)
+}
@@ -0,0 +1,145 @@
+package installation
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	kymaVersion "github.com/kyma-project/cli/internal/version"
+	pkgErrors "github.com/pkg/errors"
+)
+
+const (
+	errorSourceInvalid    = "failed to parse the source flag. It can take one of the following: 'local', 'master', release version (e.g. 1.4.1), commit hash (e.g. 34edf09a) or installer image"
+	errorLocalCustomImage = "You must specify --custom-image to install Kyma from local sources to a remote cluster."
+)
+
+// Validate checks the options for consistency and ensures that the given files exist, without accessing the cluster or the network.
+// It lets programs embedding the installation reject invalid input before anything is started.
+// The checks depending on the cluster type are done by CheckClusterRequirements once IsLocal is set from the cluster.
+func (o *Options) Validate() error {
+	i := &Installation{Options: o}
+
+	if err := validateSource(o.Source); err != nil {
+		return err
+	}
+	if err := validateSrcPath(o.Source, o.LocalSrcPath); err != nil {
+		return err
+	}
+
+	if o.Domain != "" {
+		if err := ValidateDomain(o.Domain); err != nil {
+			return err
+		}
+	}
+
+	if err := i.validateNipIO(); err != nil {
+		return err
+	}
+
+	//If custom domain name is provided, also certificates have to be provided
+	if o.Domain != defaultDomain && o.Domain != "" && !i.certificateProvided() {
+		return pkgErrors.New(errorCustomDomainCertMissing)
+	}
+
+	//Ensure that tls-key or tls-cert are always specified together
+	if (o.TLSKey != "" || o.TLSCert != "") && !i.certificateProvided() {
+		return pkgErrors.New(errorCertIncomplete)
+	}
+
+	if _, ok := profiles[o.Profile]; o.Profile != "" && !ok {
+		return pkgErrors.New(errorProfileNotSupported)
+	}
+
+	if err := i.validateImagePullSecret(); err != nil {
+		return err
+	}
+
+	if err := i.validateScheduling(); err != nil {
+		return err
+	}
+
+	if err := i.validateInstallerResources(); err != nil {
+		return err
+	}
+
+	if err := i.validateNodeLabeling(); err != nil {
+		return err
+	}
+
+	if err := i.validateInstallerLogLevel(); err != nil {
+		return err
+	}
+
+	if err := i.validateExtraMetadata(); err != nil {
+		return err
+	}
+
+	if err := i.validateDexUsers(); err != nil {
+		return err
+	}
+
+	if err := i.validateChartValues(); err != nil {
+		return err
+	}
+
+	if err := validateFeatures(o.EnableFeatures, o.DisableFeatures); err != nil {
+		return err
+	}
+
+	// with --force, the incompatibility is logged as a warning once the installation runs
+	if err := kymaVersion.CheckCompatibility(o.Source); err != nil && !o.Force {
+		return fmt.Errorf("%s. Use --force to continue anyway", err)
+	}
+	return nil
+}
+
+// CheckClusterRequirements checks the options which depend on the cluster the installation runs on, such as the cluster type given with IsLocal.
+// The context is checked before, so that programs embedding the installation can abort it.
+func (i *Installation) CheckClusterRequirements(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if err := i.validateNipIO(); err != nil {
+		return err
+	}
+	if strings.EqualFold(i.Options.Source, sourceLocal) && !i.Options.IsLocal && i.Options.CustomImage == "" {
+		return pkgErrors.New(errorLocalCustomImage)
+	}
+	return nil
+}
+
+// validateSource ensures that the source is one of the supported kinds, the versions are resolved by validateConfigurations
+func validateSource(source string) error {
+	switch {
+	case strings.EqualFold(source, sourceLocal), strings.EqualFold(source, sourceMaster), isHex(source), isSemVer(source), strings.HasPrefix(source, "PR-"), isDockerImage(source):
+		return nil
+	}
+	return pkgErrors.New(errorSourceInvalid)
+}
+
+// validateSrcPath ensures that --src-path points to a Kyma repository or an archive of it, if local sources are installed.
+// For other sources the path is ignored, which is logged once the installation runs. If no path is given, the default locations are looked up.
+func validateSrcPath(source, srcPath string) error {
+	if srcPath == "" || !strings.EqualFold(source, sourceLocal) {
+		return nil
+	}
+	return checkSrcPath(srcPath, srcPath)
+}
+
+// checkSrcPath ensures that the directory of the local sources contains a Kyma repository, archives are only checked for existence.
+// The configured path is used in the errors, as the directory might be the extraction of the configured archive.
+func checkSrcPath(dir, configured string) error {
+	if _, err := os.Stat(dir); err != nil {
+		return fmt.Errorf("configured 'src-path=%s' does not exist. Check if you configured a valid path", configured)
+	}
+	if isSourceArchive(dir) {
+		return nil
+	}
+	if _, err := os.Stat(filepath.Join(dir, "installation", "resources")); err != nil {
+		return fmt.Errorf("configured 'src-path=%s' does not seem to point to a Kyma repository. Check if your repository contains the 'installation/resources' folder", configured)
+	}
+	return nil
+}
@@ -0,0 +1,126 @@
+package installation
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidate(t *testing.T) {
+	t.Parallel()
+	dir, err := ioutil.TempDir("", "kyma-validate-")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	repo := filepath.Join(dir, "kyma")
+	require.NoError(t, os.MkdirAll(filepath.Join(repo, "installation", "resources"), 0700))
+	archive := filepath.Join(dir, "kyma.tar.gz")
+	require.NoError(t, ioutil.WriteFile(archive, []byte("archive"), 0600))
+	values := filepath.Join(dir, "values.yaml")
+	require.NoError(t, ioutil.WriteFile(values, []byte("replicas: 2"), 0600))
+	pullSecret := filepath.Join(dir, "config.json")
+	require.NoError(t, ioutil.WriteFile(pullSecret, []byte(`{"auths":{}}`), 0600))
+
+	tests := []struct {
+		name    string
+		options func(o *Options)
+		// err is a part of the expected error, the options must be valid if it is empty
+		err string
+	}{
+		{name: "release", options: func(o *Options) {}},
+		{name: "master", options: func(o *Options) { o.Source = "master" }},
+		{name: "commit", options: func(o *Options) { o.Source = "34edf09a" }},
+		{name: "pull request", options: func(o *Options) { o.Source = "PR-9486" }},
+		{name: "installer image", options: func(o *Options) { o.Source = "test-registry/test-image:1.0.0" }},
+		{name: "unknown source", options: func(o *Options) { o.Source = "fake-source" }, err: "failed to parse the source flag"},
+		{name: "empty source", options: func(o *Options) { o.Source = "" }, err: "failed to parse the source flag"},
+
+		{name: "local sources without src-path", options: func(o *Options) { o.Source = "local" }},
+		{name: "local sources", options: func(o *Options) { o.Source, o.LocalSrcPath = "local", repo }},
+		{name: "local sources in upper case", options: func(o *Options) { o.Source, o.LocalSrcPath = "LOCAL", repo }},
+		{name: "local sources archive", options: func(o *Options) { o.Source, o.LocalSrcPath = "local", archive }},
+		{name: "missing src-path", options: func(o *Options) { o.Source, o.LocalSrcPath = "local", filepath.Join(dir, "missing") }, err: "does not exist"},
+		{name: "missing archive", options: func(o *Options) { o.Source, o.LocalSrcPath = "local", filepath.Join(dir, "missing.zip") }, err: "does not exist"},
+		{name: "src-path without Kyma repository", options: func(o *Options) { o.Source, o.LocalSrcPath = "local", dir }, err: "does not seem to point to a Kyma repository"},
+		{name: "src-path without local sources", options: func(o *Options) { o.LocalSrcPath = filepath.Join(dir, "missing") }},
+		{name: "custom image for local sources is checked with the cluster type", options: func(o *Options) { o.Source, o.LocalSrcPath = "local", repo }},
+
+		{name: "default domain", options: func(o *Options) { o.Domain = "kyma.local" }},
+		{name: "custom domain with certificate", options: func(o *Options) { o.Domain, o.TLSCert, o.TLSKey = "kyma.example.com", "cert", "key" }},
+		{name: "custom domain without certificate", options: func(o *Options) { o.Domain = "kyma.example.com" }, err: errorCustomDomainCertMissing},
+		{name: "domain with scheme", options: func(o *Options) { o.Domain = "https://kyma.example.com" }, err: "the domain must not contain a scheme"},
+		{name: "domain with trailing dot", options: func(o *Options) { o.Domain = "kyma.example.com." }, err: "must not end with a dot"},
+		{name: "invalid domain", options: func(o *Options) { o.Domain = "Kyma_Example" }, err: "invalid domain"},
+		{name: "certificate without key", options: func(o *Options) { o.TLSCert = "cert" }, err: errorCertIncomplete},
+		{name: "key without certificate", options: func(o *Options) { o.TLSKey = "key" }, err: errorCertIncomplete},
+
+		{name: "nip.io", options: func(o *Options) { o.UseNipIO = true }},
+		{name: "nip.io with custom domain", options: func(o *Options) { o.UseNipIO, o.Domain = true, "kyma.example.com" }, err: errorNipIODomain},
+		{name: "nip.io without wait", options: func(o *Options) { o.UseNipIO, o.NoWait = true, true }, err: errorNipIONoWait},
+		{name: "nip.io with certificate", options: func(o *Options) { o.UseNipIO, o.TLSCert, o.TLSKey = true, "cert", "key" }, err: errorNipIOTLSCerts},
+		{name: "nip.io on a local cluster", options: func(o *Options) { o.UseNipIO, o.IsLocal = true, true }, err: errorNipIOLocal},
+
+		{name: "profile", options: func(o *Options) { o.Profile = "evaluation" }},
+		{name: "unknown profile", options: func(o *Options) { o.Profile = "tiny" }, err: errorProfileNotSupported},
+
+		{name: "image pull secret file", options: func(o *Options) { o.ImagePullSecret = pullSecret }},
+		{name: "missing image pull secret file", options: func(o *Options) { o.ImagePullSecret = filepath.Join(dir, "missing.json") }, err: "unable to read the image pull secret file"},
+		{name: "image pull secret file with registry credentials", options: func(o *Options) { o.ImagePullSecret, o.RegistryServer = pullSecret, "registry.example.com" }, err: errorPullSecretConflict},
+		{name: "registry credentials", options: func(o *Options) {
+			o.RegistryServer, o.RegistryUser, o.RegistryPassword = "registry.example.com", "user", "secret"
+		}},
+		{name: "incomplete registry credentials", options: func(o *Options) { o.RegistryServer, o.RegistryUser = "registry.example.com", "user" }, err: errorRegistryCredentialsIncomplete},
+
+		{name: "scheduling", options: func(o *Options) {
+			o.InstallerNodeSelector, o.InstallerTolerations, o.InstallerPriorityClass = map[string]string{"pool": "infra"}, []string{"dedicated=infra:NoSchedule"}, "system-cluster-critical"
+		}},
+		{name: "invalid toleration", options: func(o *Options) { o.InstallerTolerations = []string{"dedicated=infra"} }, err: "invalid toleration"},
+		{name: "installer resources", options: func(o *Options) { o.InstallerCPURequest, o.InstallerMemoryLimit = "200m", "1Gi" }},
+		{name: "invalid installer resources", options: func(o *Options) { o.InstallerCPURequest = "fast" }, err: "invalid value 'fast' of --installer-cpu"},
+		{name: "labels", options: func(o *Options) { o.ExtraLabels = map[string]string{"team": "kyma"} }},
+		{name: "invalid label", options: func(o *Options) { o.ExtraLabels = map[string]string{"team": "ky ma"} }, err: "invalid label"},
+
+		{name: "chart values", options: func(o *Options) { o.ChartValues = []string{"istio=" + values} }},
+		{name: "chart values without component", options: func(o *Options) { o.ChartValues = []string{values} }, err: "invalid chart values"},
+		{name: "missing chart values", options: func(o *Options) { o.ChartValues = []string{"istio=" + filepath.Join(dir, "missing.yaml")} }, err: "unable to read the chart values of the component 'istio'"},
+		{name: "features", options: func(o *Options) { o.EnableFeatures, o.DisableFeatures = []string{"monitoring"}, []string{"logging"} }},
+		{name: "unknown feature", options: func(o *Options) { o.EnableFeatures = []string{"teleport"} }, err: "unknown feature 'teleport'"},
+
+		{name: "unsupported Kyma version", options: func(o *Options) { o.Source = "0.9.0" }, err: "Use --force to continue anyway"},
+		{name: "unsupported Kyma version with force", options: func(o *Options) { o.Source, o.Force = "0.9.0", true }},
+	}
+	for _, tc := range tests {
+		o := &Options{Source: "1.15.1"}
+		tc.options(o)
+		err := o.Validate()
+		if tc.err == "" {
+			require.NoError(t, err, tc.name)
+			continue
+		}
+		require.Error(t, err, tc.name)
+		require.Contains(t, err.Error(), tc.err, tc.name)
+	}
+}
+
+func TestCheckClusterRequirements(t *testing.T) {
+	t.Parallel()
+	i := &Installation{Options: &Options{Source: "local"}}
+	require.EqualError(t, i.CheckClusterRequirements(context.Background()), errorLocalCustomImage)
+
+	i.Options.IsLocal = true
+	require.NoError(t, i.CheckClusterRequirements(context.Background()))
+
+	i.Options.UseNipIO = true
+	require.EqualError(t, i.CheckClusterRequirements(context.Background()), errorNipIOLocal)
+
+	i.Options.IsLocal, i.Options.UseNipIO, i.Options.CustomImage = false, false, "test-registry/test-image:1.0.0"
+	require.NoError(t, i.CheckClusterRequirements(context.Background()))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	require.Equal(t, context.Canceled, i.CheckClusterRequirements(ctx))
+}
@@ -0,0 +1,284 @@
+package installation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"time"
+
+	installationSDK "github.com/kyma-incubator/hydroform/install/installation"
+	"github.com/kyma-project/cli/internal/trace"
+	pkgErrors "github.com/pkg/errors"
+	apiErrors "k8s.io/apimachinery/pkg/api/errors"
+	utilnet "k8s.io/apimachinery/pkg/util/net"
+)
+
+const (
+	// installerPollInterval is the time between two checks of the installation state
+	installerPollInterval = 10 * time.Second
+	// unreachableRetries is the number of consecutive checks of the installation state that may fail because the cluster is unreachable
+	unreachableRetries = 5
+)
+
+// ErrInstallationTimeout is returned if the installation does not complete within the timeout of the options
+var ErrInstallationTimeout = errors.New("Timeout reached while waiting for installation to complete")
+
+// ErrInterrupted is returned if the CLI is interrupted while it waits for the installation. The Kyma Installer continues in the cluster.
+var ErrInterrupted = errors.New("Interrupted while waiting for the installation. The Kyma Installer continues, run \"kyma wait installation\" to wait for it again")
+
+// WaitForInstallation waits until the Kyma Installer reports Kyma as installed, without triggering an installation.
+// With --retries, a failed installation is triggered again as during the installation.
+func (i *Installation) WaitForInstallation() error {
+	if err := i.discoverInstallationName(); err != nil {
+		return err
+	}
+	if i.Options.InstallationName == "" {
+		return errors.New("Kyma is not installed on the cluster")
+	}
+	i.newStep("Waiting for the Kyma installation")
+	return i.waitForInstaller()
+}
+
+// waitForInstaller polls the installation state until the Kyma Installer reports Kyma as installed, showing one sub-step per installation description
+func (i *Installation) waitForInstaller() error {
+	currentDesc := ""
+	// the current step becomes the parent of one sub-step per installation description
+	parent := i.currentStep
+	fail := func() {
+		i.currentStep.Failure()
+		if i.currentStep != parent {
+			parent.Failure()
+		}
+	}
+	var errorOccured bool
+	// the installation might oscillate between Error and InProgress while the Kyma Installer retries components, only new errors are logged
+	installerErrs := &installerErrors{logged: map[string]bool{}}
+	// number of consecutive checks that failed because the cluster is unreachable
+	var unreachable int
+	// number of times the failed installation was triggered again with --retries, and whether the last one is not picked up yet
+	var retries int
+	var retrying bool
+	// without a component list, the progress cannot be estimated
+	if components := i.installationComponents(); len(components) > 0 {
+		i.progress = newProgress(components)
+	}
+	var timeout <-chan time.Time
+	if i.Options.Timeout > 0 {
+		timeout = time.After(i.Options.Timeout)
+	}
+	// the warning events are logged on the current step at every check, the watches end once the waiting is over
+	var events *eventWatcher
+	if i.Options.WatchEvents {
+		events = startEventWatcher(i.K8s.Static(), watchedEventNamespaces)
+		defer events.stop()
+	}
+
+	for {
+		if events != nil {
+			for _, msg := range events.drain() {
+				i.currentStep.LogError(msg)
+			}
+		}
+		select {
+		case <-i.installCtx().Done():
+			fail()
+			return ErrInterrupted
+		case <-timeout:
+			fail()
+			if _, err := i.Service.CheckInstallationState(i.K8s.RestConfig(), i.Options.InstallationName); err != nil {
+				installationError := installationSDK.InstallationError{}
+				if ok := errors.As(err, &installationError); ok {
+					i.currentStep.LogErrorf("Installation error occurred while installing Kyma: %s", installationError.Error())
+					i.logComponentErrors()
+				}
+			}
+			return ErrInstallationTimeout
+		default:
+			// the domain can only be determined once the ingress gateway is installed
+			if i.Options.UseNipIO {
+				if _, err := i.applyNipIODomain(); err != nil {
+					fail()
+					return err
+				}
+			}
+
+			polled := time.Now()
+			installationState, err := i.Service.CheckInstallationState(i.K8s.RestConfig(), i.Options.InstallationName)
+			trace.Record("poll installation state", polled, err, nil)
+			if err != nil && clusterUnreachable(err) {
+				unreachable++
+				if unreachable > unreachableRetries {
+					fail()
+					return pkgErrors.Wrapf(err, "Cluster unreachable after %d retries", unreachableRetries)
+				}
+				i.currentStep.LogErrorf("Cluster unreachable, retrying (%d/%d)", unreachable, unreachableRetries)
+				i.pause()
+				continue
+			}
+			unreachable = 0
+			if err != nil {
+				installErr := installationSDK.InstallationError{}
+				if errors.As(err, &installErr) && (retrying || retries < i.Options.Retries) {
+					// the Installation CR keeps the Error state until the Kyma Installer picks up the new label
+					if !retrying {
+						retries++
+						retrying = true
+						i.currentStep.LogErrorf("Attempt %d/%d failed: %s", retries, i.Options.Retries+1, installErr.Error())
+						i.logComponentErrors()
+						i.currentStep.LogInfof("Triggering the Kyma Installer again (attempt %d/%d)", retries+1, i.Options.Retries+1)
+						if err := i.Service.RetryInstallation(i.installationName()); err != nil {
+							fail()
+							return pkgErrors.Wrap(err, "Failed to trigger the Kyma Installer again")
+						}
+					}
+					i.pause()
+					continue
+				}
+				if !errorOccured {
+					errorOccured = true
+					if errors.As(err, &installErr) {
+						i.logInstallerError(installErr, installerErrs)
+						// the sub-step is re-opened once the installation is in progress again
+						if i.currentStep != parent {
+							i.currentStep.Failure()
+							i.currentStep = parent
+						}
+					} else {
+						i.currentStep.LogErrorf("Failed to get installation state, which may be OK. Will retry later...\nError: %s", err)
+					}
+				}
+				i.pause()
+				continue
+			}
+
+			switch installationState.State {
+			case "Installed":
+				if i.progress != nil {
+					i.progress.finish(time.Now())
+				}
+				i.report.poll(installationState.State, installationState.Description, i.progress)
+				if i.Options.UseNipIO && i.nipIODomain == "" {
+					i.currentStep.LogErrorf("Warning: the load balancer of the Istio ingress gateway has no IP, so the nip.io domain could not be configured")
+				}
+				i.currentStep.Success()
+				if i.currentStep != parent {
+					parent.Success()
+				}
+				return nil
+
+			case "InProgress":
+				errorOccured = false
+				retrying = false
+				// only do something if the description has changed, or the sub-step was closed by an error
+				if installationState.Description != currentDesc || i.currentStep == parent {
+					if i.currentStep != parent {
+						i.currentStep.Success()
+					}
+					msg := installationState.Description
+					if i.progress != nil {
+						i.progress.update(installationState.Description, time.Now())
+						msg = fmt.Sprintf("%s %s", msg, i.progress)
+					}
+					i.currentStep = parent.SubStep(msg)
+					currentDesc = installationState.Description
+				}
+
+			case "":
+				i.currentStep.LogInfo("Failed to get the installation status. Will retry later...")
+
+			default:
+				fail()
+				return fmt.Errorf("unexpected status: %s", installationState.State)
+			}
+			i.report.poll(installationState.State, installationState.Description, i.progress)
+			i.pause()
+		}
+	}
+}
+
+// installerErrors tracks the errors logged while waiting for the Kyma Installer
+type installerErrors struct {
+	// last identifies the last logged error, with the component errors
+	last string
+	// repeats counts how often the last error occurred again
+	repeats int
+	// logged holds the component errors logged so far
+	logged map[string]bool
+	// loggedAt is the time the last error was logged, the installer logs are fetched from then on
+	loggedAt time.Time
+}
+
+// record returns how often the error was seen in a row before, and the component errors which were not logged yet
+func (e *installerErrors) record(msg string, componentErrors []ComponentError) (int, []ComponentError) {
+	key := msg
+	for _, c := range componentErrors {
+		key += "\n" + c.Component + ": " + c.Log
+	}
+	if key == e.last {
+		e.repeats++
+		return e.repeats, nil
+	}
+	e.last = key
+	e.repeats = 0
+
+	var fresh []ComponentError
+	for _, c := range componentErrors {
+		id := c.Component + ": " + c.Log
+		if !e.logged[id] {
+			e.logged[id] = true
+			fresh = append(fresh, c)
+		}
+	}
+	return 0, fresh
+}
+
+// logInstallerError logs an error of the Kyma Installer. An error which repeats the previous one is collapsed into one line,
+// otherwise only the component errors are logged which were not logged before, and the installer logs are fetched since the previous error.
+func (i *Installation) logInstallerError(installErr installationSDK.InstallationError, errs *installerErrors) {
+	componentErrors, _ := componentErrors(i.K8s, i.metadata(), i.installationName())
+	repeats, fresh := errs.record(installErr.Error(), componentErrors)
+	if repeats > 0 {
+		i.currentStep.LogErrorf("%s, which may be OK. Same error repeated %d times, will retry later...", installErr.Error(), repeats+1)
+		return
+	}
+
+	i.currentStep.LogErrorf("%s, which may be OK. Will retry later...", installErr.Error())
+	switch {
+	case len(fresh) > 0:
+		i.currentStep.LogError(FormatComponentErrors(fresh))
+	case len(componentErrors) == 0:
+		i.logComponentErrors()
+	}
+	cmd := fmt.Sprintf("kubectl logs -n %s -l %s", i.installerNamespace(), i.installerLabel())
+	if !errs.loggedAt.IsZero() {
+		cmd += " --since-time=" + errs.loggedAt.UTC().Format(time.RFC3339)
+	}
+	i.currentStep.LogInfof("To fetch the application logs from the installer, run: %s", cmd)
+	errs.loggedAt = time.Now()
+}
+
+// pause waits for the poll interval, or until the installation is interrupted
+func (i *Installation) pause() {
+	select {
+	case <-time.After(i.installerPollInterval()):
+	case <-i.installCtx().Done():
+	}
+}
+
+func (i *Installation) installerPollInterval() time.Duration {
+	if i.pollInterval > 0 {
+		return i.pollInterval
+	}
+	return installerPollInterval
+}
+
+// clusterUnreachable checks if the error is caused by a request which did not reach the API server or timed out
+func clusterUnreachable(err error) bool {
+	var netErr net.Error
+	if errors.As(err, &netErr) || errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+	return apiErrors.IsTimeout(err) || apiErrors.IsServerTimeout(err) || apiErrors.IsServiceUnavailable(err) ||
+		utilnet.IsConnectionRefused(err) || utilnet.IsConnectionReset(err) || utilnet.IsProbableEOF(err)
+}
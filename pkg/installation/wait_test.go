@@ -0,0 +1,304 @@
+package installation
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"testing"
+	"time"
+
+	installSDK "github.com/kyma-incubator/hydroform/install/installation"
+	k8sMocks "github.com/kyma-project/cli/internal/kube/mocks"
+	"github.com/kyma-project/cli/pkg/installation/mocks"
+	"github.com/kyma-project/cli/pkg/step"
+	stepMocks "github.com/kyma-project/cli/pkg/step/mocks"
+	pkgErrors "github.com/pkg/errors"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	apiErrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	fakeDynamic "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/rest"
+)
+
+func TestWaitForInstallerUnreachableCluster(t *testing.T) {
+	t.Parallel()
+	unreachable := &url.Error{Op: "Get", URL: "https://fake-kubeconfig-host", Err: context.DeadlineExceeded}
+
+	newInstallation := func(iServiceMock *mocks.Service) (*Installation, *stepMocks.Step) {
+		kymaMock := &k8sMocks.KymaKube{}
+		kymaMock.On("Static").Return(fake.NewSimpleClientset())
+		kymaMock.On("Dynamic").Return(fakeDynamic.NewSimpleDynamicClient(runtime.NewScheme()))
+		kymaMock.On("RestConfig", mock.Anything).Return(&rest.Config{Host: "fake-kubeconfig-host"})
+		s := &stepMocks.Step{}
+		return &Installation{
+			K8s:          kymaMock,
+			Service:      iServiceMock,
+			currentStep:  s,
+			pollInterval: time.Millisecond,
+			Options:      &Options{Timeout: time.Minute},
+		}, s
+	}
+
+	t.Run("Recover before the retry budget is exhausted", func(t *testing.T) {
+		iServiceMock := &mocks.Service{}
+		iServiceMock.On("CheckInstallationState", mock.Anything, mock.Anything).Return(installSDK.InstallationState{}, unreachable).Twice()
+		iServiceMock.On("CheckInstallationState", mock.Anything, mock.Anything).Return(installSDK.InstallationState{State: "Installed"}, nil).Once()
+		i, s := newInstallation(iServiceMock)
+
+		require.NoError(t, i.waitForInstaller())
+		require.Equal(t, []string{"Cluster unreachable, retrying (1/5)", "Cluster unreachable, retrying (2/5)"}, s.Errors())
+	})
+
+	t.Run("Fail once the retry budget is exhausted", func(t *testing.T) {
+		iServiceMock := &mocks.Service{}
+		iServiceMock.On("CheckInstallationState", mock.Anything, mock.Anything).Return(installSDK.InstallationState{}, unreachable).Times(unreachableRetries + 1)
+		i, s := newInstallation(iServiceMock)
+
+		err := i.waitForInstaller()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "Cluster unreachable after 5 retries")
+		require.Len(t, s.Errors(), unreachableRetries)
+		require.False(t, s.IsSuccessful())
+		iServiceMock.AssertExpectations(t)
+	})
+}
+
+func TestWaitForInstallerRetries(t *testing.T) {
+	t.Parallel()
+	failed := installSDK.InstallationError{ShortMessage: "installation error occurred: webhook not ready"}
+
+	newInstallation := func(iServiceMock *mocks.Service, retries int) (*Installation, *stepMocks.Step) {
+		kymaMock := &k8sMocks.KymaKube{}
+		kymaMock.On("Static").Return(fake.NewSimpleClientset())
+		kymaMock.On("Dynamic").Return(fakeDynamic.NewSimpleDynamicClient(runtime.NewScheme()))
+		kymaMock.On("RestConfig", mock.Anything).Return(&rest.Config{Host: "fake-kubeconfig-host"})
+		s := &stepMocks.Step{}
+		return &Installation{
+			K8s:          kymaMock,
+			Service:      iServiceMock,
+			currentStep:  s,
+			pollInterval: time.Millisecond,
+			Options:      &Options{Timeout: time.Minute, Retries: retries},
+		}, s
+	}
+
+	t.Run("Trigger the installer again after an error", func(t *testing.T) {
+		iServiceMock := &mocks.Service{}
+		iServiceMock.On("CheckInstallationState", mock.Anything, mock.Anything).Return(installSDK.InstallationState{}, failed).Twice()
+		iServiceMock.On("CheckInstallationState", mock.Anything, mock.Anything).Return(installSDK.InstallationState{State: "InProgress", Description: "istio"}, nil).Once()
+		iServiceMock.On("CheckInstallationState", mock.Anything, mock.Anything).Return(installSDK.InstallationState{State: "Installed"}, nil).Once()
+		iServiceMock.On("RetryInstallation", defaultInstallationName).Return(nil).Once()
+		i, s := newInstallation(iServiceMock, 2)
+
+		require.NoError(t, i.waitForInstaller())
+		require.Contains(t, s.Errors(), "Attempt 1/3 failed: installation error occurred: webhook not ready")
+		require.Contains(t, s.Infos(), "Triggering the Kyma Installer again (attempt 2/3)")
+		// the error seen before the installer picked up the label does not trigger another retry
+		iServiceMock.AssertExpectations(t)
+	})
+
+	t.Run("Keep waiting once the retries are used up", func(t *testing.T) {
+		iServiceMock := &mocks.Service{}
+		iServiceMock.On("CheckInstallationState", mock.Anything, mock.Anything).Return(installSDK.InstallationState{}, failed).Once()
+		iServiceMock.On("CheckInstallationState", mock.Anything, mock.Anything).Return(installSDK.InstallationState{State: "InProgress", Description: "istio"}, nil).Once()
+		iServiceMock.On("CheckInstallationState", mock.Anything, mock.Anything).Return(installSDK.InstallationState{}, failed).Once()
+		iServiceMock.On("CheckInstallationState", mock.Anything, mock.Anything).Return(installSDK.InstallationState{State: "Installed"}, nil).Once()
+		iServiceMock.On("RetryInstallation", defaultInstallationName).Return(nil).Once()
+		i, s := newInstallation(iServiceMock, 1)
+
+		require.NoError(t, i.waitForInstaller())
+		require.Len(t, s.SubSteps(), 1)
+		// the later error is logged on the sub-step of the installation description
+		require.Contains(t, s.SubSteps()[0].Errors(), "installation error occurred: webhook not ready, which may be OK. Will retry later...")
+		iServiceMock.AssertExpectations(t)
+	})
+
+	t.Run("Fail if the installer cannot be triggered", func(t *testing.T) {
+		iServiceMock := &mocks.Service{}
+		iServiceMock.On("CheckInstallationState", mock.Anything, mock.Anything).Return(installSDK.InstallationState{}, failed).Once()
+		iServiceMock.On("RetryInstallation", defaultInstallationName).Return(errors.New("forbidden")).Once()
+		i, s := newInstallation(iServiceMock, 1)
+
+		require.EqualError(t, i.waitForInstaller(), "Failed to trigger the Kyma Installer again: forbidden")
+		require.False(t, s.IsSuccessful())
+	})
+}
+
+func TestWaitForInstallerOscillation(t *testing.T) {
+	t.Parallel()
+	failed := installSDK.InstallationError{ShortMessage: "installation error occurred: webhook not ready"}
+	cr := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "installer.kyma-project.io/v1alpha1",
+		"kind":       "Installation",
+		"metadata":   map[string]interface{}{"name": "kyma-installation", "namespace": "default"},
+		"status": map[string]interface{}{
+			"errorLog": []interface{}{
+				map[string]interface{}{"component": "istio", "log": "release istio failed", "occurrences": int64(1)},
+			},
+		},
+	}}
+	kymaMock := &k8sMocks.KymaKube{}
+	kymaMock.On("Static").Return(fake.NewSimpleClientset())
+	kymaMock.On("Dynamic").Return(fakeDynamic.NewSimpleDynamicClient(runtime.NewScheme(), cr))
+	kymaMock.On("RestConfig", mock.Anything).Return(&rest.Config{Host: "fake-kubeconfig-host"})
+	iServiceMock := &mocks.Service{}
+	iServiceMock.On("CheckInstallationState", mock.Anything, mock.Anything).Return(installSDK.InstallationState{}, failed).Once()
+	iServiceMock.On("CheckInstallationState", mock.Anything, mock.Anything).Return(installSDK.InstallationState{State: "InProgress", Description: "istio"}, nil).Once()
+	iServiceMock.On("CheckInstallationState", mock.Anything, mock.Anything).Return(installSDK.InstallationState{}, failed).Once()
+	iServiceMock.On("CheckInstallationState", mock.Anything, mock.Anything).Return(installSDK.InstallationState{State: "InProgress", Description: "istio"}, nil).Once()
+	iServiceMock.On("CheckInstallationState", mock.Anything, mock.Anything).Return(installSDK.InstallationState{State: "Installed"}, nil).Once()
+	s := &stepMocks.Step{}
+	i := &Installation{
+		K8s:          kymaMock,
+		Service:      iServiceMock,
+		currentStep:  s,
+		pollInterval: time.Millisecond,
+		Options:      &Options{Timeout: time.Minute},
+	}
+
+	require.NoError(t, i.waitForInstaller())
+	require.Equal(t, []string{
+		"installation error occurred: webhook not ready, which may be OK. Will retry later...",
+		"Component 'istio' failed first:\n    release istio failed",
+	}, s.Errors())
+	require.Equal(t, []string{"To fetch the application logs from the installer, run: kubectl logs -n kyma-installer -l name=kyma-installer"}, s.Infos())
+
+	// the repeated error is collapsed, and the sub-step is re-opened once the installation is in progress again
+	require.Len(t, s.SubSteps(), 2)
+	require.Equal(t, []string{"installation error occurred: webhook not ready, which may be OK. Same error repeated 2 times, will retry later..."}, s.SubSteps()[0].Errors())
+	require.False(t, s.SubSteps()[0].IsSuccessful())
+	require.True(t, s.SubSteps()[1].IsSuccessful())
+	iServiceMock.AssertExpectations(t)
+}
+
+func TestWaitForInstallerSubSteps(t *testing.T) {
+	t.Parallel()
+	kymaMock := &k8sMocks.KymaKube{}
+	kymaMock.On("Static").Return(fake.NewSimpleClientset())
+	kymaMock.On("Dynamic").Return(fakeDynamic.NewSimpleDynamicClient(runtime.NewScheme()))
+	kymaMock.On("RestConfig", mock.Anything).Return(&rest.Config{Host: "fake-kubeconfig-host"})
+	iServiceMock := &mocks.Service{}
+	iServiceMock.On("CheckInstallationState", mock.Anything, mock.Anything).Return(installSDK.InstallationState{State: "InProgress", Description: "istio"}, nil).Once()
+	iServiceMock.On("CheckInstallationState", mock.Anything, mock.Anything).Return(installSDK.InstallationState{}, nil).Once()
+	iServiceMock.On("CheckInstallationState", mock.Anything, mock.Anything).Return(installSDK.InstallationState{State: "InProgress", Description: "core"}, nil).Once()
+	iServiceMock.On("CheckInstallationState", mock.Anything, mock.Anything).Return(installSDK.InstallationState{State: "Installed"}, nil).Once()
+	s := &stepMocks.Step{}
+	i := &Installation{
+		K8s:          kymaMock,
+		Service:      iServiceMock,
+		currentStep:  s,
+		pollInterval: time.Millisecond,
+		Options:      &Options{Timeout: time.Minute},
+	}
+
+	require.NoError(t, i.waitForInstaller())
+	// each installation description is a sub-step of the waiting step
+	require.Len(t, s.SubSteps(), 2)
+	require.Equal(t, "istio", s.SubSteps()[0].Statuses()[0])
+	require.Equal(t, []string{"Failed to get the installation status. Will retry later..."}, s.SubSteps()[0].Infos())
+	require.True(t, s.SubSteps()[0].IsSuccessful())
+	require.Equal(t, "core", s.SubSteps()[1].Statuses()[0])
+	require.True(t, s.SubSteps()[1].IsSuccessful())
+	require.True(t, s.IsSuccessful())
+	iServiceMock.AssertExpectations(t)
+}
+
+func TestInstallerErrors(t *testing.T) {
+	t.Parallel()
+	e := &installerErrors{logged: map[string]bool{}}
+	istio := ComponentError{Component: "istio", Log: "release istio failed", Occurrences: 1}
+	dex := ComponentError{Component: "dex", Log: "dex failed", Occurrences: 1}
+
+	repeats, fresh := e.record("failed", []ComponentError{istio})
+	require.Zero(t, repeats)
+	require.Equal(t, []ComponentError{istio}, fresh)
+
+	// the occurrences do not make the error a new one
+	istio.Occurrences = 2
+	repeats, fresh = e.record("failed", []ComponentError{istio})
+	require.Equal(t, 1, repeats)
+	require.Empty(t, fresh)
+
+	// only the component errors which were not logged yet are returned
+	repeats, fresh = e.record("failed", []ComponentError{istio, dex})
+	require.Zero(t, repeats)
+	require.Equal(t, []ComponentError{dex}, fresh)
+}
+
+func TestWaitForInstallation(t *testing.T) {
+	t.Parallel()
+	newInstallation := func(iServiceMock *mocks.Service) *Installation {
+		kymaMock := &k8sMocks.KymaKube{}
+		kymaMock.On("Static").Return(fake.NewSimpleClientset())
+		kymaMock.On("Dynamic").Return(fakeDynamic.NewSimpleDynamicClient(runtime.NewScheme()))
+		kymaMock.On("RestConfig", mock.Anything).Return(&rest.Config{Host: "fake-kubeconfig-host"})
+		return &Installation{
+			K8s:          kymaMock,
+			Service:      iServiceMock,
+			Factory:      step.Factory{NonInteractive: true},
+			pollInterval: time.Millisecond,
+			Options:      &Options{Timeout: 20 * time.Millisecond},
+		}
+	}
+
+	t.Run("Installed", func(t *testing.T) {
+		iServiceMock := &mocks.Service{}
+		iServiceMock.On("CheckInstallationState", mock.Anything, "kyma-installation").Return(installSDK.InstallationState{State: "Installed"}, nil).Once()
+		i := newInstallation(iServiceMock)
+		i.Options.InstallationName = "kyma-installation"
+
+		require.NoError(t, i.WaitForInstallation())
+		iServiceMock.AssertExpectations(t)
+	})
+
+	t.Run("Timeout", func(t *testing.T) {
+		iServiceMock := &mocks.Service{}
+		iServiceMock.On("CheckInstallationState", mock.Anything, "kyma-installation").Return(installSDK.InstallationState{State: "InProgress", Description: "istio"}, nil)
+		i := newInstallation(iServiceMock)
+		i.Options.InstallationName = "kyma-installation"
+
+		require.True(t, errors.Is(i.WaitForInstallation(), ErrInstallationTimeout))
+	})
+
+	t.Run("Not installed", func(t *testing.T) {
+		i := newInstallation(&mocks.Service{})
+		require.EqualError(t, i.WaitForInstallation(), "Kyma is not installed on the cluster")
+	})
+}
+
+func TestWaitForInstallerWatchEvents(t *testing.T) {
+	t.Parallel()
+	// the event happens while the installation is waited for
+	static := fake.NewSimpleClientset(warningEvent("scheduling", "FailedScheduling", "0/1 nodes are available: insufficient memory", time.Now().Add(time.Second)))
+	kymaMock := &k8sMocks.KymaKube{}
+	kymaMock.On("Dynamic").Return(fakeDynamic.NewSimpleDynamicClient(runtime.NewScheme()))
+	kymaMock.On("RestConfig", mock.Anything).Return(&rest.Config{Host: "fake-kubeconfig-host"})
+	kymaMock.On("Static").Return(static)
+	iServiceMock := &mocks.Service{}
+	iServiceMock.On("CheckInstallationState", mock.Anything, mock.Anything).Return(installSDK.InstallationState{State: "InProgress", Description: "istio"}, nil).Times(10)
+	iServiceMock.On("CheckInstallationState", mock.Anything, mock.Anything).Return(installSDK.InstallationState{State: "Installed"}, nil).Once()
+	s := &stepMocks.Step{}
+	i := &Installation{
+		K8s:          kymaMock,
+		Service:      iServiceMock,
+		currentStep:  s,
+		pollInterval: 20 * time.Millisecond,
+		Options:      &Options{Timeout: time.Minute, WatchEvents: true},
+	}
+
+	require.NoError(t, i.waitForInstaller())
+	require.Len(t, s.SubSteps(), 1)
+	require.Equal(t, []string{"Warning event FailedScheduling of the pod 'kyma-system/logging-loki-0': 0/1 nodes are available: insufficient memory"}, s.SubSteps()[0].Errors())
+}
+
+func TestClusterUnreachable(t *testing.T) {
+	t.Parallel()
+	require.True(t, clusterUnreachable(&url.Error{Op: "Get", URL: "https://fake", Err: context.DeadlineExceeded}))
+	require.True(t, clusterUnreachable(pkgErrors.Wrap(context.DeadlineExceeded, "request failed")))
+	require.True(t, clusterUnreachable(apiErrors.NewTimeoutError("request timed out", 1)))
+	require.False(t, clusterUnreachable(apiErrors.NewNotFound(installationGVR().GroupResource(), "kyma-installation")))
+	require.False(t, clusterUnreachable(errors.New("installation is hiding from us")))
+}
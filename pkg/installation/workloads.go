@@ -0,0 +1,91 @@
+package installation
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	pkgErrors "github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+var (
+	// workloadGracePeriod is the time the workloads may take to become ready once the Kyma Installer reports Kyma as installed
+	workloadGracePeriod = 3 * time.Minute
+	// workloadInterval is the time between two checks of the workloads
+	workloadInterval = 5 * time.Second
+)
+
+// verifiedNamespaces are the namespaces whose workloads are verified after the installation
+var verifiedNamespaces = []string{"kyma-system", "kyma-integration"}
+
+// verifyWorkloads waits for the Deployments and StatefulSets of the Kyma namespaces to be ready, for at most workloadGracePeriod.
+// The Kyma Installer reports Kyma as installed once the Helm releases succeeded, also if pods are still crash-looping, e.g. because of a bad configuration.
+// It returns the workloads which are not ready once the grace period is over. The verification never fails the installation.
+func (i *Installation) verifyWorkloads() ([]string, error) {
+	s := i.newStep(fmt.Sprintf("Verifying the workloads in %s", strings.Join(verifiedNamespaces, ", ")))
+	deadline := time.Now().Add(workloadGracePeriod)
+	for {
+		total, unready, err := unreadyWorkloads(i.K8s.Static())
+		if err != nil {
+			s.Failuref("Unable to verify the workloads")
+			return nil, err
+		}
+		if len(unready) == 0 {
+			s.Successf("All %d workloads are ready", total)
+			return nil, nil
+		}
+		if time.Now().After(deadline) {
+			s.Failuref("%d of %d workloads are not ready after %s", len(unready), total, workloadGracePeriod)
+			return unready, nil
+		}
+		s.Status(fmt.Sprintf("Waiting for %d of %d workloads to be ready", len(unready), total))
+		time.Sleep(workloadInterval)
+	}
+}
+
+// unreadyWorkloads returns the number of Deployments and StatefulSets in the verified namespaces, and a description of the ones which are not fully ready.
+// A Deployment is ready once all replicas are updated and ready, a StatefulSet once all replicas are ready.
+func unreadyWorkloads(k8s kubernetes.Interface) (int, []string, error) {
+	var total int
+	var unready []string
+	for _, ns := range verifiedNamespaces {
+		deployments, err := k8s.AppsV1().Deployments(ns).List(context.Background(), metav1.ListOptions{})
+		if err != nil {
+			return 0, nil, pkgErrors.Wrapf(err, "unable to list the deployments in the namespace '%s'", ns)
+		}
+		for _, d := range deployments.Items {
+			total++
+			desired := replicas(d.Spec.Replicas)
+			switch {
+			case d.Status.ReadyReplicas < desired:
+				unready = append(unready, fmt.Sprintf("deployment %s/%s: %d of %d replicas ready", ns, d.Name, d.Status.ReadyReplicas, desired))
+			case d.Status.UpdatedReplicas < desired:
+				unready = append(unready, fmt.Sprintf("deployment %s/%s: %d of %d replicas updated", ns, d.Name, d.Status.UpdatedReplicas, desired))
+			}
+		}
+
+		statefulSets, err := k8s.AppsV1().StatefulSets(ns).List(context.Background(), metav1.ListOptions{})
+		if err != nil {
+			return 0, nil, pkgErrors.Wrapf(err, "unable to list the statefulsets in the namespace '%s'", ns)
+		}
+		for _, s := range statefulSets.Items {
+			total++
+			desired := replicas(s.Spec.Replicas)
+			if s.Status.ReadyReplicas < desired {
+				unready = append(unready, fmt.Sprintf("statefulset %s/%s: %d of %d replicas ready", ns, s.Name, s.Status.ReadyReplicas, desired))
+			}
+		}
+	}
+	return total, unready, nil
+}
+
+// replicas returns the desired replicas of a workload, which default to one
+func replicas(r *int32) int32 {
+	if r == nil {
+		return 1
+	}
+	return *r
+}
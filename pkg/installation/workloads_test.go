@@ -0,0 +1,76 @@
+package installation
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	k8sMocks "github.com/kyma-project/cli/internal/kube/mocks"
+	"github.com/kyma-project/cli/pkg/step"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8sTesting "k8s.io/client-go/testing"
+)
+
+func TestVerifyWorkloads(t *testing.T) {
+	// not parallel: the package level timeouts are modified
+	defer func(period, interval time.Duration) { workloadGracePeriod, workloadInterval = period, interval }(workloadGracePeriod, workloadInterval)
+	workloadGracePeriod, workloadInterval = 50*time.Millisecond, time.Millisecond
+
+	two := int32(2)
+	ready := appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "logging-loki", Namespace: "kyma-system"},
+		Spec:       appsv1.StatefulSetSpec{Replicas: &two},
+		Status:     appsv1.StatefulSetStatus{ReadyReplicas: 2},
+	}
+	starting := ready
+	starting.Status.ReadyReplicas = 1
+	static := fake.NewSimpleClientset(
+		&appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: "console-backend", Namespace: "kyma-system"},
+			Status:     appsv1.DeploymentStatus{ReadyReplicas: 1, UpdatedReplicas: 1},
+		},
+		&appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: "connector-service", Namespace: "kyma-integration"},
+			Spec:       appsv1.DeploymentSpec{Replicas: &two},
+			Status:     appsv1.DeploymentStatus{ReadyReplicas: 2, UpdatedReplicas: 1},
+		},
+		// workloads of other namespaces are not verified
+		&appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "broken", Namespace: "default"}},
+		&starting,
+	)
+	kymaMock := &k8sMocks.KymaKube{}
+	kymaMock.On("Static").Return(static)
+	i := &Installation{K8s: kymaMock, Factory: step.Factory{NonInteractive: true}, Options: &Options{}}
+
+	// the statefulset becomes ready during the grace period, the deployment in the middle of a rollout does not
+	lists := 0
+	static.PrependReactor("list", "statefulsets", func(a k8sTesting.Action) (bool, runtime.Object, error) {
+		if a.GetNamespace() != "kyma-system" {
+			return false, nil, nil
+		}
+		lists++
+		if lists < 3 {
+			return false, nil, nil
+		}
+		return true, &appsv1.StatefulSetList{Items: []appsv1.StatefulSet{ready}}, nil
+	})
+	unready, err := i.verifyWorkloads()
+	require.NoError(t, err)
+	require.Equal(t, []string{"deployment kyma-integration/connector-service: 1 of 2 replicas updated"}, unready)
+	require.True(t, lists >= 3)
+
+	total, unready, err := unreadyWorkloads(static)
+	require.NoError(t, err)
+	require.Equal(t, 3, total)
+	require.Len(t, unready, 1)
+
+	static.PrependReactor("list", "deployments", func(k8sTesting.Action) (bool, runtime.Object, error) {
+		return true, nil, errors.New("forbidden")
+	})
+	_, err = i.verifyWorkloads()
+	require.EqualError(t, err, "unable to list the deployments in the namespace 'kyma-system': forbidden")
+}
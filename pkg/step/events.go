@@ -0,0 +1,100 @@
+package step
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/kyma-project/cli/internal/logger"
+)
+
+// eventStep logs the progress of a step as structured log entries, in addition to rendering it
+type eventStep struct {
+	Step
+	mu      sync.Mutex
+	msg     string
+	started time.Time
+}
+
+func withEvents(s Step, msg string) Step {
+	logger.Info("step", msg, logger.Fields{"event": "start"})
+	return &eventStep{Step: s, msg: msg, started: time.Now()}
+}
+
+func (s *eventStep) stopped(success bool, msg string) {
+	s.mu.Lock()
+	if msg != "" {
+		s.msg = msg
+	}
+	fields := logger.Fields{"event": "stop", "success": success, "duration": time.Since(s.started)}
+	msg = s.msg
+	s.mu.Unlock()
+	if success {
+		logger.Info("step", msg, fields)
+	} else {
+		logger.Error("step", msg, fields)
+	}
+}
+
+func (s *eventStep) Success() {
+	s.Step.Success()
+	s.stopped(true, "")
+}
+
+func (s *eventStep) Successf(format string, args ...interface{}) {
+	s.Step.Successf(format, args...)
+	s.stopped(true, fmt.Sprintf(format, args...))
+}
+
+func (s *eventStep) Failure() {
+	s.Step.Failure()
+	s.stopped(false, "")
+}
+
+func (s *eventStep) Failuref(format string, args ...interface{}) {
+	s.Step.Failuref(format, args...)
+	s.stopped(false, fmt.Sprintf(format, args...))
+}
+
+func (s *eventStep) Stop(success bool) {
+	s.Step.Stop(success)
+	s.stopped(success, "")
+}
+
+func (s *eventStep) Stopf(success bool, format string, args ...interface{}) {
+	s.Step.Stopf(success, format, args...)
+	s.stopped(success, fmt.Sprintf(format, args...))
+}
+
+func (s *eventStep) Status(msg string) {
+	s.Step.Status(msg)
+	logger.Info("step", msg, logger.Fields{"event": "status", "step": s.name()})
+}
+
+func (s *eventStep) LogInfo(msg string) {
+	s.Step.LogInfo(msg)
+	logger.Info("step", msg, logger.Fields{"step": s.name()})
+}
+
+func (s *eventStep) LogInfof(format string, args ...interface{}) {
+	s.LogInfo(fmt.Sprintf(format, args...))
+}
+
+func (s *eventStep) LogError(msg string) {
+	s.Step.LogError(msg)
+	logger.Error("step", msg, logger.Fields{"step": s.name()})
+}
+
+func (s *eventStep) LogErrorf(format string, args ...interface{}) {
+	s.LogError(fmt.Sprintf(format, args...))
+}
+
+func (s *eventStep) SubStep(msg string) Step {
+	return withEvents(s.Step.SubStep(msg), msg)
+}
+
+func (s *eventStep) name() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.msg
+}
@@ -0,0 +1,79 @@
+package step
+
+import (
+	"bytes"
+	"sync"
+	"testing"
+
+	"github.com/kyma-project/cli/internal/logger"
+	"github.com/stretchr/testify/require"
+)
+
+// recorder keeps the logged entries, so that the events of the steps can be checked
+type recorder struct {
+	mu      sync.Mutex
+	entries []logger.Entry
+}
+
+func (r *recorder) Log(e logger.Entry) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.entries = append(r.entries, e)
+}
+
+func TestEventStep(t *testing.T) {
+	r := &recorder{}
+	logger.Set(r)
+	defer logger.Set(logger.Discard)
+
+	var out bytes.Buffer
+	f := Factory{NonInteractive: true, Out: &out}
+	s := f.NewStep("Installing Kyma")
+	s.Status("waiting")
+	s.LogInfof("%d components left", 2)
+	istio := s.SubStep("istio")
+	istio.Successf("istio installed")
+	s.Failure()
+
+	require.Equal(t, "Installing Kyma: waiting\n"+
+		"  2 components left\n"+
+		"  Installing Kyma\n"+
+		"  - istio installed\n"+
+		"X Installing Kyma\n", out.String(), "the steps are rendered on the writer of the factory")
+
+	require.Len(t, r.entries, 6)
+	expected := []struct {
+		level logger.Level
+		msg   string
+		event interface{}
+		step  interface{}
+	}{
+		{logger.LevelInfo, "Installing Kyma", "start", nil},
+		{logger.LevelInfo, "waiting", "status", "Installing Kyma"},
+		{logger.LevelInfo, "2 components left", nil, "Installing Kyma"},
+		{logger.LevelInfo, "istio", "start", nil},
+		{logger.LevelInfo, "istio installed", "stop", nil},
+		{logger.LevelError, "Installing Kyma", "stop", nil},
+	}
+	for n, e := range expected {
+		entry := r.entries[n]
+		require.Equal(t, "step", entry.Component)
+		require.Equal(t, e.level, entry.Level, "level of entry %d", n)
+		require.Equal(t, e.msg, entry.Message, "message of entry %d", n)
+		require.Equal(t, e.event, entry.Fields["event"], "event of entry %d", n)
+		require.Equal(t, e.step, entry.Fields["step"], "step of entry %d", n)
+	}
+	require.Equal(t, true, r.entries[4].Fields["success"])
+	require.Equal(t, false, r.entries[5].Fields["success"])
+	require.Contains(t, r.entries[5].Fields, "duration")
+}
+
+func TestEventStepDisabled(t *testing.T) {
+	var out bytes.Buffer
+	f := Factory{NonInteractive: true, Out: &out}
+	s := f.NewStep("Installing Kyma")
+	_, ok := s.(*eventStep)
+	require.False(t, ok, "the steps are only wrapped if structured logs are enabled")
+	s.Success()
+	require.Equal(t, "- Installing Kyma\n", out.String())
+}
@@ -1,7 +1,10 @@
 package step
 
 import (
+	"io"
 	"runtime"
+
+	"github.com/kyma-project/cli/internal/logger"
 )
 
 //FactoryInterface is an abstraction for step factory
@@ -13,15 +16,27 @@ type FactoryInterface interface {
 type Factory struct {
 	NonInteractive bool
 	UseLogger      bool
+	// Out is the writer the steps are displayed on, os.Stdout if not set. The warnings of the steps are displayed on os.Stderr.
+	Out io.Writer `json:"-"`
 }
 
 // NewStep creates a new Step to print out the current status with or without a spinner.
+// If structured logs are enabled, the progress of the step is logged as well.
 func (f *Factory) NewStep(msg string) Step {
+	s := f.newStep(msg)
+	if logger.Enabled() {
+		return withEvents(s, msg)
+	}
+	return s
+}
+
+func (f *Factory) newStep(msg string) Step {
 	if f.UseLogger {
 		return newLogStep(msg)
 	}
+	out, spinnerOut := outputs(f.Out)
 	if f.NonInteractive || runtime.GOOS != "darwin" {
-		return newSimpleStep(msg)
+		return newSimpleStep(msg, out)
 	}
-	return newStepWithSpinner(msg)
+	return newStepWithSpinner(msg, out, spinnerOut)
 }
@@ -11,19 +11,20 @@ import (
 )
 
 func newLogStep(msg string) Step {
-	return &logStep{msg}
+	return &logStep{msg: msg}
 }
 
 type logStep struct {
-	msg string
+	msg    string
+	indent string
 }
 
 func (s *logStep) Start() {
-	log.Println(s.msg)
+	log.Println(s.indent + s.msg)
 }
 
 func (s *logStep) Status(msg string) {
-	log.Printf("%s: %s\n", s.msg, msg)
+	log.Printf("%s%s: %s\n", s.indent, s.msg, msg)
 }
 
 func (s *logStep) Success() {
@@ -48,11 +49,11 @@ func (s *logStep) Stopf(success bool, format string, args ...interface{}) {
 }
 
 func (s *logStep) Stop(success bool) {
-	log.Println(s.msg)
+	log.Println(s.indent + s.msg)
 }
 
 func (s *logStep) LogInfo(msg string) {
-	log.Println(msg)
+	log.Println(s.indent + msg)
 }
 
 func (s *logStep) LogInfof(format string, args ...interface{}) {
@@ -60,13 +61,19 @@ func (s *logStep) LogInfof(format string, args ...interface{}) {
 }
 
 func (s *logStep) LogError(msg string) {
-	log.Println(msg)
+	log.Println(s.indent + msg)
 }
 
 func (s *logStep) LogErrorf(format string, args ...interface{}) {
 	s.LogError(fmt.Sprintf(format, args...))
 }
 
+func (s *logStep) SubStep(msg string) Step {
+	sub := &logStep{msg: msg, indent: s.indent + subStepIndent}
+	sub.Start()
+	return sub
+}
+
 func (s *logStep) Prompt(msg string) (string, error) {
 	reader := bufio.NewReader(os.Stdin)
 	log.Print(msg)
@@ -1,12 +1,17 @@
 package mocks
 
-import "fmt"
+import (
+	"fmt"
+
+	"github.com/kyma-project/cli/pkg/step"
+)
 
 // Mock of a CLI step.
 // All logged messages and status are stored and can be retreived later for validation.
 type Step struct {
 	status, infos, errs []string
 	success, stopped    bool
+	subSteps            []*Step
 }
 
 func (s *Step) Start() {
@@ -81,6 +86,17 @@ func (s *Step) Errors() []string {
 	return s.errs
 }
 
+func (s *Step) SubStep(msg string) step.Step {
+	sub := &Step{status: []string{msg}}
+	s.subSteps = append(s.subSteps, sub)
+	return sub
+}
+
+// SubSteps returns the mocks of all sub-steps, their first status is the message they were created with.
+func (s *Step) SubSteps() []*Step {
+	return s.subSteps
+}
+
 func (s *Step) Prompt(msg string) (string, error) {
 	return msg, nil
 }
@@ -90,6 +106,6 @@ func (s *Step) PromptYesNo(msg string) bool {
 }
 
 func (s *Step) Reset() {
-	s.errs, s.infos, s.status = nil, nil, nil
+	s.errs, s.infos, s.status, s.subSteps = nil, nil, nil, nil
 	s.stopped, s.success = false, false
 }
@@ -3,26 +3,33 @@ package step
 import (
 	"bufio"
 	"fmt"
+	"io"
 	"os"
 	"strings"
+	"sync"
 
 	"github.com/kyma-project/cli/internal/root"
 )
 
-func newSimpleStep(msg string) Step {
-	return &simpleStep{msg}
+func newSimpleStep(msg string, out io.Writer) Step {
+	return &simpleStep{msg: msg, out: out}
 }
 
 type simpleStep struct {
-	msg string
+	mu     sync.Mutex
+	msg    string
+	indent string
+	out    io.Writer
+	// parent is set once the step is rendered as the parent of sub-steps
+	parent bool
 }
 
 func (s *simpleStep) Start() {
-	fmt.Println(s.msg)
+	fmt.Fprintf(s.out, "%s%s\n", s.indent, s.String())
 }
 
 func (s *simpleStep) Status(msg string) {
-	fmt.Printf("%s: %s\n", s.msg, msg)
+	fmt.Fprintf(s.out, "%s%s: %s\n", s.indent, s.String(), msg)
 }
 
 func (s *simpleStep) Success() {
@@ -42,7 +49,9 @@ func (s *simpleStep) Failuref(format string, args ...interface{}) {
 }
 
 func (s *simpleStep) Stopf(success bool, format string, args ...interface{}) {
+	s.mu.Lock()
 	s.msg = fmt.Sprintf(format, args...)
+	s.mu.Unlock()
 	s.Stop(success)
 }
 
@@ -53,11 +62,11 @@ func (s *simpleStep) Stop(success bool) {
 	} else {
 		glyph = failureGlyph
 	}
-	fmt.Printf("%s%s\n", glyph, s.msg)
+	fmt.Fprintf(s.out, "%s%s%s\n", s.indent, glyph, s.String())
 }
 
 func (s *simpleStep) LogInfo(msg string) {
-	fmt.Printf("%s%s\n", infoGlyph, msg)
+	fmt.Fprintf(s.out, "%s%s%s\n", s.indent, infoGlyph, msg)
 }
 
 func (s *simpleStep) LogInfof(format string, args ...interface{}) {
@@ -65,26 +74,39 @@ func (s *simpleStep) LogInfof(format string, args ...interface{}) {
 }
 
 func (s *simpleStep) LogError(msg string) {
-	fmt.Fprintf(os.Stderr, "%s%s\n", warningGlyph, msg)
+	fmt.Fprintf(stderr, "%s%s%s\n", s.indent, warningGlyph, msg)
 }
 
 func (s *simpleStep) LogErrorf(format string, args ...interface{}) {
 	s.LogError(fmt.Sprintf(format, args...))
 }
 
+func (s *simpleStep) SubStep(msg string) Step {
+	s.mu.Lock()
+	// the header is written while holding the lock, so that no concurrent sub-step is rendered before it
+	if !s.parent {
+		s.parent = true
+		fmt.Fprintf(s.out, "%s%s%s\n", s.indent, infoGlyph, s.msg)
+	}
+	s.mu.Unlock()
+	return &simpleStep{msg: msg, indent: s.indent + subStepIndent, out: s.out}
+}
+
 func (s *simpleStep) Prompt(msg string) (string, error) {
 	reader := bufio.NewReader(os.Stdin)
-	fmt.Printf("%s%s", questionGlyph, msg)
+	fmt.Fprintf(s.out, "%s%s%s", s.indent, questionGlyph, msg)
 	answer, err := reader.ReadString('\n')
 	return strings.TrimSpace(answer), err
 }
 
 func (s *simpleStep) PromptYesNo(msg string) bool {
-	fmt.Printf("%s%s", questionGlyph, msg)
+	fmt.Fprintf(s.out, "%s%s%s", s.indent, questionGlyph, msg)
 	answer := root.PromptUser()
 	return answer
 }
 
 func (s *simpleStep) String() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
 	return s.msg
 }
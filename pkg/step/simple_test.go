@@ -0,0 +1,51 @@
+package step
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSimpleStepSubSteps(t *testing.T) {
+	var out bytes.Buffer
+	s := newSimpleStep("Installing Kyma", &out)
+	istio := s.SubStep("istio")
+	istio.LogInfo("Failed to get the installation status. Will retry later...")
+	istio.Success()
+	core := s.SubStep("core")
+	core.Failure()
+	s.Failure()
+
+	require.Equal(t, "  Installing Kyma\n"+
+		"    Failed to get the installation status. Will retry later...\n"+
+		"  - istio\n"+
+		"  X core\n"+
+		"X Installing Kyma\n", out.String())
+}
+
+func TestConcurrentSubSteps(t *testing.T) {
+	var out bytes.Buffer
+	s := newSimpleStep("Installing Kyma", lockedWriter(func() io.Writer { return &out }))
+	var wg sync.WaitGroup
+	for n := 0; n < 10; n++ {
+		wg.Add(1)
+		go func(n int) {
+			defer wg.Done()
+			s.SubStep(fmt.Sprintf("component-%d", n)).Success()
+		}(n)
+	}
+	wg.Wait()
+
+	lines := strings.Split(strings.TrimSuffix(out.String(), "\n"), "\n")
+	require.Equal(t, "  Installing Kyma", lines[0], "the parent is rendered once, before its sub-steps")
+	expected := []string{}
+	for n := 0; n < 10; n++ {
+		expected = append(expected, fmt.Sprintf("  - component-%d", n))
+	}
+	require.ElementsMatch(t, expected, lines[1:])
+}
@@ -6,6 +6,7 @@ import (
 	"io"
 	"os"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/kyma-project/cli/internal/root"
@@ -14,20 +15,34 @@ import (
 	"github.com/fatih/color"
 )
 
-func newStepWithSpinner(msg string) Step {
+func newStepWithSpinner(msg string, out, spinnerOut io.Writer) Step {
+	return newIndentedStepWithSpinner(msg, "", out, spinnerOut)
+}
+
+func newIndentedStepWithSpinner(msg, indent string, out, spinnerOut io.Writer) Step {
 	s := spinner.New(
 		[]string{"/", "-", "\\", "|"},
 		time.Millisecond*200,
 		spinner.WithColor("reset"),
 		spinner.WithSuffix(" "+msg),
+		spinner.WithWriter(spinnerOut),
 	)
+	s.Prefix = indent
 	s.Start()
-	return &stepWithSpinner{s, msg}
+	return &stepWithSpinner{spinner: s, msg: msg, indent: indent, out: out, spinnerOut: spinnerOut}
 }
 
 type stepWithSpinner struct {
+	mu      sync.Mutex
 	spinner *spinner.Spinner
 	msg     string
+	indent  string
+	// out is the writer of the regular output, spinnerOut the one of the spinner and the final message of the step
+	out        io.Writer
+	spinnerOut io.Writer
+	// parent is set once the step is rendered as the parent of sub-steps, its spinner then gives way to the ones of the sub-steps
+	parent  bool
+	stopped bool
 }
 
 func (s *stepWithSpinner) Start() {
@@ -35,6 +50,8 @@ func (s *stepWithSpinner) Start() {
 }
 
 func (s *stepWithSpinner) Status(msg string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
 	s.msg = fmt.Sprintf(" %s: %s", s.msg, msg)
 }
 
@@ -55,50 +72,81 @@ func (s *stepWithSpinner) Failuref(format string, args ...interface{}) {
 }
 
 func (s *stepWithSpinner) Stopf(success bool, format string, args ...interface{}) {
+	s.mu.Lock()
 	s.msg = fmt.Sprintf(format, args...)
+	s.mu.Unlock()
 	s.Stop(success)
 }
 
 func (s *stepWithSpinner) Stop(success bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.stopped {
+		return
+	}
+	s.stopped = true
+
 	var gliph string
 	if success {
 		gliph = color.GreenString(successGlyph)
 	} else {
 		gliph = color.RedString(failureGlyph)
 	}
-	s.spinner.FinalMSG = fmt.Sprintf("%s%s\n", gliph, s.msg)
+	line := fmt.Sprintf("%s%s%s\n", s.indent, gliph, s.msg)
+	if !s.spinner.Active() {
+		fmt.Fprint(s.spinnerOut, line)
+		return
+	}
+	s.spinner.FinalMSG = line
 	s.spinner.Stop()
 }
 
 func (s *stepWithSpinner) LogInfo(msg string) {
-	s.logTo(os.Stdout, infoGlyph+msg)
+	s.mu.Lock()
+	// the info of a running sub-step is shown next to its spinner, so it disappears with the next info or when the sub-step stops
+	transient := s.indent != "" && !s.stopped && !s.parent && s.spinner.Active()
+	if transient {
+		s.spinner.Lock()
+		s.spinner.Suffix = fmt.Sprintf(" %s (%s)", s.msg, msg)
+		s.spinner.Unlock()
+	}
+	s.mu.Unlock()
+	if !transient {
+		s.logTo(s.out, s.indent+infoGlyph+msg)
+	}
 }
 
 func (s *stepWithSpinner) LogInfof(format string, args ...interface{}) {
-	s.logTof(os.Stdout, infoGlyph+format, args...)
+	s.LogInfo(fmt.Sprintf(format, args...))
 }
 
 func (s *stepWithSpinner) LogError(msg string) {
-	s.logTof(os.Stderr, color.YellowString(warningGlyph)+msg)
+	s.logTo(stderr, s.indent+color.YellowString(warningGlyph)+msg)
 }
 
 func (s *stepWithSpinner) LogErrorf(format string, args ...interface{}) {
-	s.logTof(os.Stderr, color.YellowString(warningGlyph)+format, args...)
+	s.logTof(stderr, s.indent+color.YellowString(warningGlyph)+format, args...)
 }
 
-func (s *stepWithSpinner) logTof(to io.Writer, format string, args ...interface{}) {
-	isActive := s.spinner.Active()
-	s.spinner.Stop()
-	fmt.Fprintf(to, format+"\n", args...)
-	if isActive {
-		s.spinner.Start()
+func (s *stepWithSpinner) SubStep(msg string) Step {
+	s.mu.Lock()
+	if !s.parent && !s.stopped {
+		s.parent = true
+		s.spinner.FinalMSG = fmt.Sprintf("%s%s%s\n", s.indent, infoGlyph, s.msg)
+		s.spinner.Stop()
 	}
+	s.mu.Unlock()
+	return newIndentedStepWithSpinner(msg, s.indent+subStepIndent, s.out, s.spinnerOut)
+}
+
+func (s *stepWithSpinner) logTof(to io.Writer, format string, args ...interface{}) {
+	s.logTo(to, fmt.Sprintf(format, args...))
 }
 
-func (s *stepWithSpinner) logTo(to io.Writer, format string) {
+func (s *stepWithSpinner) logTo(to io.Writer, msg string) {
 	isActive := s.spinner.Active()
 	s.spinner.Stop()
-	fmt.Fprint(to, format+"\n")
+	fmt.Fprint(to, msg+"\n")
 	if isActive {
 		s.spinner.Start()
 	}
@@ -108,7 +156,7 @@ func (s *stepWithSpinner) Prompt(msg string) (string, error) {
 	reader := bufio.NewReader(os.Stdin)
 	isActive := s.spinner.Active()
 	s.spinner.Stop()
-	fmt.Printf("%s%s", questionGlyph, msg)
+	fmt.Fprintf(s.out, "%s%s%s", s.indent, questionGlyph, msg)
 	answer, err := reader.ReadString('\n')
 	if isActive {
 		s.spinner.Start()
@@ -119,7 +167,7 @@ func (s *stepWithSpinner) Prompt(msg string) (string, error) {
 func (s *stepWithSpinner) PromptYesNo(msg string) bool {
 	isActive := s.spinner.Active()
 	s.spinner.Stop()
-	fmt.Printf("%s%s", questionGlyph, msg)
+	fmt.Fprintf(s.out, "%s%s%s", s.indent, questionGlyph, msg)
 	answer := root.PromptUser()
 	if isActive {
 		s.spinner.Start()
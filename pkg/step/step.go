@@ -10,10 +10,14 @@ type Step interface {
 	Failuref(format string, args ...interface{})
 	Stop(success bool)
 	Stopf(success bool, format string, args ...interface{})
+	// LogInfo shows an info line. Running sub-steps with a spinner show it as a transient detail next to the spinner,
+	// which is replaced by the next info and removed when the sub-step stops. Outputs which cannot be updated, like CI logs, keep all lines.
 	LogInfo(msg string)
 	LogInfof(format string, args ...interface{})
 	LogError(msg string)
 	LogErrorf(format string, args ...interface{})
+	// SubStep creates a step rendered indented under this step, to show the progress of the parts of a long-running step.
+	SubStep(msg string) Step
 	Prompt(msg string) (string, error)
 	PromptYesNo(msg string) bool
 }
@@ -0,0 +1,40 @@
+package step
+
+import (
+	"io"
+	"os"
+	"sync"
+
+	"github.com/fatih/color"
+)
+
+// subStepIndent is the indentation of sub-steps relative to their parent step
+const subStepIndent = "  "
+
+// outputLock serializes the output of all steps, so that the lines of steps updated concurrently are not interleaved
+var outputLock sync.Mutex
+
+// lockedWriter writes to its target while holding the output lock.
+// The target is resolved at every write, as the standard outputs can be replaced at runtime.
+type lockedWriter func() io.Writer
+
+func (w lockedWriter) Write(p []byte) (int, error) {
+	outputLock.Lock()
+	defer outputLock.Unlock()
+	return w().Write(p)
+}
+
+var (
+	stdout        io.Writer = lockedWriter(func() io.Writer { return os.Stdout })
+	stderr        io.Writer = lockedWriter(func() io.Writer { return os.Stderr })
+	spinnerOutput io.Writer = lockedWriter(func() io.Writer { return color.Output })
+)
+
+// outputs returns the writers of the regular output and of the spinners of the steps, out replaces both if it is set
+func outputs(out io.Writer) (io.Writer, io.Writer) {
+	if out == nil {
+		return stdout, spinnerOutput
+	}
+	w := lockedWriter(func() io.Writer { return out })
+	return w, w
+}